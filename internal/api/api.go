@@ -11,6 +11,10 @@ import (
 	campaignemailsHandler "base-server/internal/campaignemails/handler"
 	campaignHandler "base-server/internal/campaign/handler"
 	emailblastsHandler "base-server/internal/emailblasts/handler"
+	emailsequencesHandler "base-server/internal/emailsequences/handler"
+	audiencesHandler "base-server/internal/audiences/handler"
+	emailtrackingHandler "base-server/internal/emailtracking/handler"
+	eventlogHandler "base-server/internal/eventlog/handler"
 	zapierHandler "base-server/internal/integrations/zapier"
 	billingHandler "base-server/internal/money/billing/handler"
 	referralHandler "base-server/internal/referral/handler"
@@ -41,10 +45,14 @@ type API struct {
 	apikeysHandler       *apikeysHandler.Handler
 	segmentsHandler      segmentsHandler.Handler
 	emailblastsHandler   emailblastsHandler.Handler
+	emailSequencesHandler emailsequencesHandler.Handler
+	audiencesHandler      audiencesHandler.Handler
+	eventlogHandler       eventlogHandler.Handler
+	emailTrackingHandler  emailtrackingHandler.Handler
 }
 
 func New(router *gin.RouterGroup, authHandler authHandler.Handler, campaignHandler campaignHandler.Handler,
-	waitlistHandler waitlistHandler.Handler, analyticsHandler analyticsHandler.Handler, referralHandler referralHandler.Handler, rewardHandler rewardHandler.Handler, campaignEmailTemplateHandler campaignemailsHandler.Handler, blastEmailTemplateHandler blastemailsHandler.Handler, handler billingHandler.Handler, aiHandler aiHandler.Handler, voicecallHandler voiceCallHandler.Handler, webhookHandler *webhookHandler.Handler, zapierHandler *zapierHandler.Handler, apikeysHandler *apikeysHandler.Handler, segmentsHandler segmentsHandler.Handler, emailblastsHandler emailblastsHandler.Handler) API {
+	waitlistHandler waitlistHandler.Handler, analyticsHandler analyticsHandler.Handler, referralHandler referralHandler.Handler, rewardHandler rewardHandler.Handler, campaignEmailTemplateHandler campaignemailsHandler.Handler, blastEmailTemplateHandler blastemailsHandler.Handler, handler billingHandler.Handler, aiHandler aiHandler.Handler, voicecallHandler voiceCallHandler.Handler, webhookHandler *webhookHandler.Handler, zapierHandler *zapierHandler.Handler, apikeysHandler *apikeysHandler.Handler, segmentsHandler segmentsHandler.Handler, emailblastsHandler emailblastsHandler.Handler, emailSequencesHandler emailsequencesHandler.Handler, audiencesHandler audiencesHandler.Handler, eventlogHandler eventlogHandler.Handler, emailTrackingHandler emailtrackingHandler.Handler) API {
 	return API{
 		router:                       router,
 		authHandler:                  authHandler,
@@ -63,6 +71,10 @@ func New(router *gin.RouterGroup, authHandler authHandler.Handler, campaignHandl
 		apikeysHandler:               apikeysHandler,
 		segmentsHandler:              segmentsHandler,
 		emailblastsHandler:           emailblastsHandler,
+		emailSequencesHandler:        emailSequencesHandler,
+		audiencesHandler:             audiencesHandler,
+		eventlogHandler:              eventlogHandler,
+		emailTrackingHandler:         emailTrackingHandler,
 	}
 }
 
@@ -91,11 +103,18 @@ func (a *API) RegisterRoutes() {
 		{
 			webhookGroup.POST("", a.webhookHandler.HandleCreateWebhook)
 			webhookGroup.GET("", a.webhookHandler.HandleListWebhooks)
+			webhookGroup.GET("/event-types", a.webhookHandler.HandleListEventTypes)
 			webhookGroup.GET("/:webhook_id", a.webhookHandler.HandleGetWebhook)
 			webhookGroup.PUT("/:webhook_id", a.webhookHandler.HandleUpdateWebhook)
 			webhookGroup.DELETE("/:webhook_id", a.webhookHandler.HandleDeleteWebhook)
 			webhookGroup.GET("/:webhook_id/deliveries", a.webhookHandler.HandleListWebhookDeliveries)
+			webhookGroup.GET("/:webhook_id/stats", a.webhookHandler.HandleGetWebhookStats)
+			webhookGroup.POST("/:webhook_id/deliveries/retry", a.webhookHandler.HandleRetryWebhookDeliveries)
+			webhookGroup.GET("/:webhook_id/deliveries/:delivery_id", a.webhookHandler.HandleGetWebhookDelivery)
+			webhookGroup.POST("/:webhook_id/deliveries/:delivery_id/redeliver", a.webhookHandler.HandleRedeliverWebhookDelivery)
 			webhookGroup.POST("/:webhook_id/test", a.webhookHandler.HandleTestWebhook)
+			webhookGroup.POST("/:webhook_id/enable", a.webhookHandler.HandleEnableWebhook)
+			webhookGroup.POST("/:webhook_id/rotate-secret", a.webhookHandler.HandleRotateWebhookSecret)
 		}
 
 		// API Keys management routes
@@ -178,6 +197,7 @@ func (a *API) RegisterRoutes() {
 			{
 				emailTemplatesGroup.POST("", a.campaignEmailTemplateHandler.HandleCreateCampaignEmailTemplate)
 				emailTemplatesGroup.GET("", a.campaignEmailTemplateHandler.HandleListCampaignEmailTemplates)
+				emailTemplatesGroup.GET("/variant-analytics", a.campaignEmailTemplateHandler.HandleGetTemplateVariantAnalytics)
 				emailTemplatesGroup.GET("/:template_id", a.campaignEmailTemplateHandler.HandleGetCampaignEmailTemplate)
 				emailTemplatesGroup.PUT("/:template_id", a.campaignEmailTemplateHandler.HandleUpdateCampaignEmailTemplate)
 				emailTemplatesGroup.DELETE("/:template_id", a.campaignEmailTemplateHandler.HandleDeleteCampaignEmailTemplate)
@@ -213,6 +233,27 @@ func (a *API) RegisterRoutes() {
 				segmentsGroup.PUT("/:segment_id", a.segmentsHandler.HandleUpdateSegment)
 				segmentsGroup.DELETE("/:segment_id", a.segmentsHandler.HandleDeleteSegment)
 				segmentsGroup.POST("/:segment_id/refresh", a.segmentsHandler.HandleRefreshSegmentCount)
+				segmentsGroup.POST("/:segment_id/snapshot", a.segmentsHandler.HandleSnapshotSegment)
+				segmentsGroup.GET("/:segment_id/members", a.segmentsHandler.HandleListSegmentMembers)
+				segmentsGroup.POST("/:segment_id/members", a.segmentsHandler.HandleAddSegmentMembers)
+				segmentsGroup.POST("/:segment_id/members/remove", a.segmentsHandler.HandleRemoveSegmentMembers)
+				segmentsGroup.POST("/:segment_id/members/import", a.segmentsHandler.HandleImportSegmentMembers)
+				segmentsGroup.GET("/:segment_id/history", a.segmentsHandler.HandleGetSegmentMembershipHistory)
+				segmentsGroup.GET("/:segment_id/growth", a.segmentsHandler.HandleGetSegmentGrowth)
+				segmentsGroup.POST("/:segment_id/exports", a.segmentsHandler.HandleCreateSegmentExport)
+				segmentsGroup.GET("/:segment_id/exports/:export_id", a.segmentsHandler.HandleGetSegmentExport)
+				segmentsGroup.GET("/:segment_id/exports/:export_id/download", a.segmentsHandler.HandleDownloadSegmentExport)
+			}
+
+			// Email sequence routes
+			sequencesGroup := campaignsGroup.Group("/:campaign_id/email-sequences")
+			{
+				sequencesGroup.POST("", a.emailSequencesHandler.HandleCreateEmailSequence)
+				sequencesGroup.GET("", a.emailSequencesHandler.HandleListEmailSequences)
+				sequencesGroup.GET("/:sequence_id", a.emailSequencesHandler.HandleGetEmailSequence)
+				sequencesGroup.PUT("/:sequence_id", a.emailSequencesHandler.HandleUpdateEmailSequence)
+				sequencesGroup.DELETE("/:sequence_id", a.emailSequencesHandler.HandleDeleteEmailSequence)
+				sequencesGroup.GET("/:sequence_id/analytics", a.emailSequencesHandler.HandleGetEmailSequenceAnalytics)
 			}
 		}
 
@@ -232,6 +273,25 @@ func (a *API) RegisterRoutes() {
 			blastsGroup.GET("/:blast_id/analytics", a.emailblastsHandler.HandleGetBlastAnalytics)
 			blastsGroup.GET("/:blast_id/recipients", a.emailblastsHandler.HandleListBlastRecipients)
 		}
+
+		// Audiences routes (account-scoped, combine segments across campaigns)
+		audiencesGroup := v1Group.Group("/audiences")
+		{
+			audiencesGroup.POST("", a.audiencesHandler.HandleCreateAudience)
+			audiencesGroup.GET("", a.audiencesHandler.HandleListAudiences)
+			audiencesGroup.GET("/:audience_id", a.audiencesHandler.HandleGetAudience)
+			audiencesGroup.PUT("/:audience_id", a.audiencesHandler.HandleUpdateAudience)
+			audiencesGroup.DELETE("/:audience_id", a.audiencesHandler.HandleDeleteAudience)
+			audiencesGroup.GET("/:audience_id/preview", a.audiencesHandler.HandlePreviewAudience)
+		}
+
+		// Event log routes (account-scoped, list and replay recorded events)
+		eventsGroup := v1Group.Group("/events")
+		{
+			eventsGroup.GET("", a.eventlogHandler.HandleListEvents)
+			eventsGroup.GET("/:event_id", a.eventlogHandler.HandleGetEvent)
+			eventsGroup.POST("/replay", a.eventlogHandler.HandleReplayEvents)
+		}
 	}
 
 	// Public waitlist endpoints (no authentication required)
@@ -244,6 +304,7 @@ func (a *API) RegisterRoutes() {
 
 	apiGroup.GET("billing/plans", a.billingHandler.ListPrices)
 	apiGroup.POST("billing/webhook", a.billingHandler.HandleWebhook)
+	apiGroup.POST("email/webhook", a.emailTrackingHandler.HandleWebhook)
 	apiGroup.POST("phone/answer", a.voicecallHandler.HandleAnswerPhone)
 	apiGroup.GET("audio/transcribe", a.voicecallHandler.HandleVoice)               // WebSocket requires GET
 	apiGroup.POST("phone/answer-agent", a.voicecallHandler.HandleAnswerVoiceAgent) // TwiML for voice agent
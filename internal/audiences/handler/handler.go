@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"base-server/internal/apierrors"
+	"base-server/internal/audiences/processor"
+	"base-server/internal/observability"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	processor processor.AudienceProcessor
+	logger    *observability.Logger
+}
+
+func New(processor processor.AudienceProcessor, logger *observability.Logger) Handler {
+	return Handler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, processor.ErrAudienceNotFound):
+		apierrors.NotFound(c, "Audience not found")
+	case errors.Is(err, processor.ErrSegmentNotFound):
+		apierrors.NotFound(c, "Segment not found")
+	case errors.Is(err, processor.ErrUnauthorized):
+		apierrors.Forbidden(c, "FORBIDDEN", "You do not have access to this audience")
+	case errors.Is(err, processor.ErrInvalidAudience):
+		apierrors.BadRequest(c, "INVALID_AUDIENCE", err.Error())
+	default:
+		apierrors.InternalError(c, err)
+	}
+}
+
+// CreateAudienceRequest represents the HTTP request for creating an audience
+type CreateAudienceRequest struct {
+	Name                    string      `json:"name" binding:"required,max=255"`
+	Description             *string     `json:"description,omitempty"`
+	CombineMode             string      `json:"combine_mode,omitempty" binding:"omitempty,oneof=union intersect"`
+	SegmentIDs              []uuid.UUID `json:"segment_ids" binding:"required,min=1,max=50"`
+	ExcludeSegmentIDs       []uuid.UUID `json:"exclude_segment_ids,omitempty" binding:"omitempty,max=50"`
+	SuppressBounced         *bool       `json:"suppress_bounced,omitempty"`
+	RequireMarketingConsent bool        `json:"require_marketing_consent,omitempty"`
+}
+
+// HandleCreateAudience handles POST /api/v1/audiences
+func (h *Handler) HandleCreateAudience(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	var req CreateAudienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	audience, err := h.processor.CreateAudience(ctx, accountID, processor.CreateAudienceRequest{
+		Name:                    req.Name,
+		Description:             req.Description,
+		CombineMode:             req.CombineMode,
+		SegmentIDs:              req.SegmentIDs,
+		ExcludeSegmentIDs:       req.ExcludeSegmentIDs,
+		SuppressBounced:         req.SuppressBounced,
+		RequireMarketingConsent: req.RequireMarketingConsent,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, audience)
+}
+
+// HandleListAudiences handles GET /api/v1/audiences
+func (h *Handler) HandleListAudiences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	audiences, err := h.processor.ListAudiences(ctx, accountID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audiences": audiences,
+		"total":     len(audiences),
+	})
+}
+
+// HandleGetAudience handles GET /api/v1/audiences/:audience_id
+func (h *Handler) HandleGetAudience(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get audience ID from path
+	audienceID, err := uuid.Parse(c.Param("audience_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse audience ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid audience id"})
+		return
+	}
+
+	audience, err := h.processor.GetAudience(ctx, accountID, audienceID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audience)
+}
+
+// UpdateAudienceRequest represents the HTTP request for updating an audience
+type UpdateAudienceRequest struct {
+	Name                    *string     `json:"name,omitempty" binding:"omitempty,max=255"`
+	Description             *string     `json:"description,omitempty"`
+	CombineMode             *string     `json:"combine_mode,omitempty" binding:"omitempty,oneof=union intersect"`
+	SegmentIDs              []uuid.UUID `json:"segment_ids,omitempty" binding:"omitempty,min=1,max=50"`
+	ExcludeSegmentIDs       []uuid.UUID `json:"exclude_segment_ids,omitempty" binding:"omitempty,max=50"`
+	SuppressBounced         *bool       `json:"suppress_bounced,omitempty"`
+	RequireMarketingConsent *bool       `json:"require_marketing_consent,omitempty"`
+}
+
+// HandleUpdateAudience handles PUT /api/v1/audiences/:audience_id
+func (h *Handler) HandleUpdateAudience(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get audience ID from path
+	audienceID, err := uuid.Parse(c.Param("audience_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse audience ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid audience id"})
+		return
+	}
+
+	var req UpdateAudienceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	audience, err := h.processor.UpdateAudience(ctx, accountID, audienceID, processor.UpdateAudienceRequest{
+		Name:                    req.Name,
+		Description:             req.Description,
+		CombineMode:             req.CombineMode,
+		SegmentIDs:              req.SegmentIDs,
+		ExcludeSegmentIDs:       req.ExcludeSegmentIDs,
+		SuppressBounced:         req.SuppressBounced,
+		RequireMarketingConsent: req.RequireMarketingConsent,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, audience)
+}
+
+// HandleDeleteAudience handles DELETE /api/v1/audiences/:audience_id
+func (h *Handler) HandleDeleteAudience(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get audience ID from path
+	audienceID, err := uuid.Parse(c.Param("audience_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse audience ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid audience id"})
+		return
+	}
+
+	if err := h.processor.DeleteAudience(ctx, accountID, audienceID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandlePreviewAudience handles GET /api/v1/audiences/:audience_id/preview
+func (h *Handler) HandlePreviewAudience(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get audience ID from path
+	audienceID, err := uuid.Parse(c.Param("audience_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse audience ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid audience id"})
+		return
+	}
+
+	preview, err := h.processor.PreviewAudience(ctx, accountID, audienceID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
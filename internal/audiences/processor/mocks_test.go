@@ -0,0 +1,162 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: processor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=processor.go -destination=mocks_test.go -package=processor
+//
+
+// Package processor is a generated GoMock package.
+package processor
+
+import (
+	store "base-server/internal/store"
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAudienceStore is a mock of AudienceStore interface.
+type MockAudienceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockAudienceStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockAudienceStoreMockRecorder is the mock recorder for MockAudienceStore.
+type MockAudienceStoreMockRecorder struct {
+	mock *MockAudienceStore
+}
+
+// NewMockAudienceStore creates a new mock instance.
+func NewMockAudienceStore(ctrl *gomock.Controller) *MockAudienceStore {
+	mock := &MockAudienceStore{ctrl: ctrl}
+	mock.recorder = &MockAudienceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAudienceStore) EXPECT() *MockAudienceStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateAudience mocks base method.
+func (m *MockAudienceStore) CreateAudience(ctx context.Context, params store.CreateAudienceParams) (store.Audience, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAudience", ctx, params)
+	ret0, _ := ret[0].(store.Audience)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAudience indicates an expected call of CreateAudience.
+func (mr *MockAudienceStoreMockRecorder) CreateAudience(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAudience", reflect.TypeOf((*MockAudienceStore)(nil).CreateAudience), ctx, params)
+}
+
+// DeleteAudience mocks base method.
+func (m *MockAudienceStore) DeleteAudience(ctx context.Context, audienceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAudience", ctx, audienceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAudience indicates an expected call of DeleteAudience.
+func (mr *MockAudienceStoreMockRecorder) DeleteAudience(ctx, audienceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAudience", reflect.TypeOf((*MockAudienceStore)(nil).DeleteAudience), ctx, audienceID)
+}
+
+// GetAudienceByID mocks base method.
+func (m *MockAudienceStore) GetAudienceByID(ctx context.Context, audienceID uuid.UUID) (store.Audience, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudienceByID", ctx, audienceID)
+	ret0, _ := ret[0].(store.Audience)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudienceByID indicates an expected call of GetAudienceByID.
+func (mr *MockAudienceStoreMockRecorder) GetAudienceByID(ctx, audienceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudienceByID", reflect.TypeOf((*MockAudienceStore)(nil).GetAudienceByID), ctx, audienceID)
+}
+
+// GetAudiencesByAccount mocks base method.
+func (m *MockAudienceStore) GetAudiencesByAccount(ctx context.Context, accountID uuid.UUID) ([]store.Audience, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudiencesByAccount", ctx, accountID)
+	ret0, _ := ret[0].([]store.Audience)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudiencesByAccount indicates an expected call of GetAudiencesByAccount.
+func (mr *MockAudienceStoreMockRecorder) GetAudiencesByAccount(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudiencesByAccount", reflect.TypeOf((*MockAudienceStore)(nil).GetAudiencesByAccount), ctx, accountID)
+}
+
+// GetCampaignByID mocks base method.
+func (m *MockAudienceStore) GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignByID", ctx, campaignID)
+	ret0, _ := ret[0].(store.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignByID indicates an expected call of GetCampaignByID.
+func (mr *MockAudienceStoreMockRecorder) GetCampaignByID(ctx, campaignID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignByID", reflect.TypeOf((*MockAudienceStore)(nil).GetCampaignByID), ctx, campaignID)
+}
+
+// GetSegmentByID mocks base method.
+func (m *MockAudienceStore) GetSegmentByID(ctx context.Context, segmentID uuid.UUID) (store.Segment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentByID", ctx, segmentID)
+	ret0, _ := ret[0].(store.Segment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentByID indicates an expected call of GetSegmentByID.
+func (mr *MockAudienceStoreMockRecorder) GetSegmentByID(ctx, segmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentByID", reflect.TypeOf((*MockAudienceStore)(nil).GetSegmentByID), ctx, segmentID)
+}
+
+// ResolveAudience mocks base method.
+func (m *MockAudienceStore) ResolveAudience(ctx context.Context, audience store.Audience) (store.AudienceResolution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveAudience", ctx, audience)
+	ret0, _ := ret[0].(store.AudienceResolution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveAudience indicates an expected call of ResolveAudience.
+func (mr *MockAudienceStoreMockRecorder) ResolveAudience(ctx, audience any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAudience", reflect.TypeOf((*MockAudienceStore)(nil).ResolveAudience), ctx, audience)
+}
+
+// UpdateAudience mocks base method.
+func (m *MockAudienceStore) UpdateAudience(ctx context.Context, audienceID uuid.UUID, params store.UpdateAudienceParams) (store.Audience, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAudience", ctx, audienceID, params)
+	ret0, _ := ret[0].(store.Audience)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAudience indicates an expected call of UpdateAudience.
+func (mr *MockAudienceStoreMockRecorder) UpdateAudience(ctx, audienceID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAudience", reflect.TypeOf((*MockAudienceStore)(nil).UpdateAudience), ctx, audienceID, params)
+}
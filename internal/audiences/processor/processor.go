@@ -0,0 +1,318 @@
+package processor
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=processor.go -destination=mocks_test.go -package=processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// AudienceStore defines the database operations required by AudienceProcessor
+type AudienceStore interface {
+	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
+	GetSegmentByID(ctx context.Context, segmentID uuid.UUID) (store.Segment, error)
+	CreateAudience(ctx context.Context, params store.CreateAudienceParams) (store.Audience, error)
+	GetAudienceByID(ctx context.Context, audienceID uuid.UUID) (store.Audience, error)
+	GetAudiencesByAccount(ctx context.Context, accountID uuid.UUID) ([]store.Audience, error)
+	UpdateAudience(ctx context.Context, audienceID uuid.UUID, params store.UpdateAudienceParams) (store.Audience, error)
+	DeleteAudience(ctx context.Context, audienceID uuid.UUID) error
+	ResolveAudience(ctx context.Context, audience store.Audience) (store.AudienceResolution, error)
+}
+
+var (
+	ErrAudienceNotFound = errors.New("audience not found")
+	ErrSegmentNotFound  = errors.New("segment not found")
+	ErrUnauthorized     = errors.New("unauthorized access to audience")
+	ErrInvalidAudience  = errors.New("invalid audience")
+)
+
+type AudienceProcessor struct {
+	store  AudienceStore
+	logger *observability.Logger
+}
+
+func New(store AudienceStore, logger *observability.Logger) AudienceProcessor {
+	return AudienceProcessor{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// CreateAudienceRequest represents a request to create an audience
+type CreateAudienceRequest struct {
+	Name                    string
+	Description             *string
+	CombineMode             string
+	SegmentIDs              []uuid.UUID
+	ExcludeSegmentIDs       []uuid.UUID
+	SuppressBounced         *bool
+	RequireMarketingConsent bool
+}
+
+// CreateAudience creates a new account-level audience from segments of the account's campaigns
+func (p *AudienceProcessor) CreateAudience(ctx context.Context, accountID uuid.UUID, req CreateAudienceRequest) (store.Audience, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+	)
+
+	if req.CombineMode == "" {
+		req.CombineMode = string(store.AudienceCombineModeUnion)
+	}
+
+	segmentIDs, excludeSegmentIDs, err := p.validateSegments(ctx, accountID, req.CombineMode, req.SegmentIDs, req.ExcludeSegmentIDs)
+	if err != nil {
+		return store.Audience{}, err
+	}
+
+	// Bounced emails are suppressed unless explicitly disabled
+	suppressBounced := true
+	if req.SuppressBounced != nil {
+		suppressBounced = *req.SuppressBounced
+	}
+
+	audience, err := p.store.CreateAudience(ctx, store.CreateAudienceParams{
+		AccountID:               accountID,
+		Name:                    req.Name,
+		Description:             req.Description,
+		CombineMode:             req.CombineMode,
+		SegmentIDs:              segmentIDs,
+		ExcludeSegmentIDs:       excludeSegmentIDs,
+		SuppressBounced:         suppressBounced,
+		RequireMarketingConsent: req.RequireMarketingConsent,
+	})
+	if err != nil {
+		p.logger.Error(ctx, "failed to create audience", err)
+		return store.Audience{}, err
+	}
+
+	p.logger.Info(ctx, "audience created successfully")
+	return audience, nil
+}
+
+// GetAudience retrieves an audience by ID
+func (p *AudienceProcessor) GetAudience(ctx context.Context, accountID, audienceID uuid.UUID) (store.Audience, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "audience_id", Value: audienceID.String()},
+	)
+
+	audience, err := p.store.GetAudienceByID(ctx, audienceID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.Audience{}, ErrAudienceNotFound
+		}
+		p.logger.Error(ctx, "failed to get audience", err)
+		return store.Audience{}, err
+	}
+
+	if audience.AccountID != accountID {
+		return store.Audience{}, ErrUnauthorized
+	}
+
+	return audience, nil
+}
+
+// ListAudiences retrieves all audiences of an account
+func (p *AudienceProcessor) ListAudiences(ctx context.Context, accountID uuid.UUID) ([]store.Audience, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+	)
+
+	audiences, err := p.store.GetAudiencesByAccount(ctx, accountID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to list audiences", err)
+		return nil, err
+	}
+
+	// Ensure audiences is never null
+	if audiences == nil {
+		audiences = []store.Audience{}
+	}
+
+	return audiences, nil
+}
+
+// UpdateAudienceRequest represents a request to update an audience
+type UpdateAudienceRequest struct {
+	Name                    *string
+	Description             *string
+	CombineMode             *string
+	SegmentIDs              []uuid.UUID
+	ExcludeSegmentIDs       []uuid.UUID
+	SuppressBounced         *bool
+	RequireMarketingConsent *bool
+}
+
+// UpdateAudience updates an audience
+func (p *AudienceProcessor) UpdateAudience(ctx context.Context, accountID, audienceID uuid.UUID, req UpdateAudienceRequest) (store.Audience, error) {
+	existing, err := p.GetAudience(ctx, accountID, audienceID)
+	if err != nil {
+		return store.Audience{}, err
+	}
+
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "audience_id", Value: audienceID.String()},
+	)
+
+	// Validate the audience as it will be after the update
+	combineMode := existing.CombineMode
+	if req.CombineMode != nil {
+		combineMode = *req.CombineMode
+	}
+	segmentIDs := []uuid.UUID(existing.SegmentIDs)
+	if req.SegmentIDs != nil {
+		segmentIDs = req.SegmentIDs
+	}
+	excludeSegmentIDs := []uuid.UUID(existing.ExcludeSegmentIDs)
+	if req.ExcludeSegmentIDs != nil {
+		excludeSegmentIDs = req.ExcludeSegmentIDs
+	}
+
+	segmentIDs, excludeSegmentIDs, err = p.validateSegments(ctx, accountID, combineMode, segmentIDs, excludeSegmentIDs)
+	if err != nil {
+		return store.Audience{}, err
+	}
+
+	params := store.UpdateAudienceParams{
+		Name:                    req.Name,
+		Description:             req.Description,
+		CombineMode:             req.CombineMode,
+		SuppressBounced:         req.SuppressBounced,
+		RequireMarketingConsent: req.RequireMarketingConsent,
+	}
+	if req.SegmentIDs != nil {
+		params.SegmentIDs = segmentIDs
+	}
+	if req.ExcludeSegmentIDs != nil {
+		params.ExcludeSegmentIDs = excludeSegmentIDs
+	}
+
+	audience, err := p.store.UpdateAudience(ctx, audienceID, params)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.Audience{}, ErrAudienceNotFound
+		}
+		p.logger.Error(ctx, "failed to update audience", err)
+		return store.Audience{}, err
+	}
+
+	p.logger.Info(ctx, "audience updated successfully")
+	return audience, nil
+}
+
+// DeleteAudience soft deletes an audience
+func (p *AudienceProcessor) DeleteAudience(ctx context.Context, accountID, audienceID uuid.UUID) error {
+	if _, err := p.GetAudience(ctx, accountID, audienceID); err != nil {
+		return err
+	}
+
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "audience_id", Value: audienceID.String()},
+	)
+
+	if err := p.store.DeleteAudience(ctx, audienceID); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrAudienceNotFound
+		}
+		p.logger.Error(ctx, "failed to delete audience", err)
+		return err
+	}
+
+	p.logger.Info(ctx, "audience deleted successfully")
+	return nil
+}
+
+// PreviewAudience resolves an audience into its current recipients and reports how many users
+// were combined, deduplicated and suppressed
+func (p *AudienceProcessor) PreviewAudience(ctx context.Context, accountID, audienceID uuid.UUID) (store.AudienceResolution, error) {
+	audience, err := p.GetAudience(ctx, accountID, audienceID)
+	if err != nil {
+		return store.AudienceResolution{}, err
+	}
+
+	resolution, err := p.store.ResolveAudience(ctx, audience)
+	if err != nil {
+		p.logger.Error(ctx, "failed to resolve audience", err)
+		return store.AudienceResolution{}, err
+	}
+
+	return resolution, nil
+}
+
+// validateSegments checks the combine mode and that every included and excluded segment belongs to
+// a campaign of the account. Returns the segment IDs with duplicates removed.
+func (p *AudienceProcessor) validateSegments(ctx context.Context, accountID uuid.UUID, combineMode string, segmentIDs, excludeSegmentIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	if combineMode != string(store.AudienceCombineModeUnion) && combineMode != string(store.AudienceCombineModeIntersect) {
+		return nil, nil, fmt.Errorf("%w: combine_mode must be union or intersect", ErrInvalidAudience)
+	}
+
+	segmentIDs = uniqueIDs(segmentIDs)
+	excludeSegmentIDs = uniqueIDs(excludeSegmentIDs)
+
+	if len(segmentIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one segment is required", ErrInvalidAudience)
+	}
+
+	included := make(map[uuid.UUID]bool, len(segmentIDs))
+	for _, id := range segmentIDs {
+		included[id] = true
+	}
+	for _, id := range excludeSegmentIDs {
+		if included[id] {
+			return nil, nil, fmt.Errorf("%w: segment %s is both included and excluded", ErrInvalidAudience, id)
+		}
+	}
+
+	// Campaign ownership is checked once per campaign
+	campaignOwned := make(map[uuid.UUID]bool)
+	for _, segmentID := range append(append([]uuid.UUID{}, segmentIDs...), excludeSegmentIDs...) {
+		segment, err := p.store.GetSegmentByID(ctx, segmentID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, nil, ErrSegmentNotFound
+			}
+			p.logger.Error(ctx, "failed to get segment", err)
+			return nil, nil, err
+		}
+
+		owned, ok := campaignOwned[segment.CampaignID]
+		if !ok {
+			campaign, err := p.store.GetCampaignByID(ctx, segment.CampaignID)
+			if err != nil {
+				if errors.Is(err, store.ErrNotFound) {
+					return nil, nil, ErrSegmentNotFound
+				}
+				p.logger.Error(ctx, "failed to get campaign", err)
+				return nil, nil, err
+			}
+			owned = campaign.AccountID == accountID
+			campaignOwned[segment.CampaignID] = owned
+		}
+
+		if !owned {
+			return nil, nil, ErrUnauthorized
+		}
+	}
+
+	return segmentIDs, excludeSegmentIDs, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
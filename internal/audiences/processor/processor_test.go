@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateAudience(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockAudienceStore(ctrl)
+	processor := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignA := uuid.New()
+	campaignB := uuid.New()
+	segmentA := uuid.New()
+	segmentB := uuid.New()
+	suppressionSegment := uuid.New()
+
+	t.Run("creates audience across campaigns of the account", func(t *testing.T) {
+		mockStore.EXPECT().GetSegmentByID(gomock.Any(), segmentA).Return(store.Segment{ID: segmentA, CampaignID: campaignA}, nil)
+		mockStore.EXPECT().GetSegmentByID(gomock.Any(), segmentB).Return(store.Segment{ID: segmentB, CampaignID: campaignB}, nil)
+		mockStore.EXPECT().GetSegmentByID(gomock.Any(), suppressionSegment).Return(store.Segment{ID: suppressionSegment, CampaignID: campaignA}, nil)
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignA).Return(store.Campaign{ID: campaignA, AccountID: accountID}, nil)
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignB).Return(store.Campaign{ID: campaignB, AccountID: accountID}, nil)
+		mockStore.EXPECT().
+			CreateAudience(gomock.Any(), store.CreateAudienceParams{
+				AccountID:         accountID,
+				Name:              "All waitlists",
+				CombineMode:       string(store.AudienceCombineModeUnion),
+				SegmentIDs:        []uuid.UUID{segmentA, segmentB},
+				ExcludeSegmentIDs: []uuid.UUID{suppressionSegment},
+				SuppressBounced:   true,
+			}).
+			Return(store.Audience{ID: uuid.New(), AccountID: accountID}, nil)
+
+		_, err := processor.CreateAudience(ctx, accountID, CreateAudienceRequest{
+			Name:              "All waitlists",
+			SegmentIDs:        []uuid.UUID{segmentA, segmentB, segmentA},
+			ExcludeSegmentIDs: []uuid.UUID{suppressionSegment},
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("returns error when a segment belongs to another account", func(t *testing.T) {
+		otherCampaign := uuid.New()
+		otherSegment := uuid.New()
+
+		mockStore.EXPECT().GetSegmentByID(gomock.Any(), otherSegment).Return(store.Segment{ID: otherSegment, CampaignID: otherCampaign}, nil)
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), otherCampaign).Return(store.Campaign{ID: otherCampaign, AccountID: uuid.New()}, nil)
+
+		_, err := processor.CreateAudience(ctx, accountID, CreateAudienceRequest{
+			Name:       "Someone else's users",
+			SegmentIDs: []uuid.UUID{otherSegment},
+		})
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+
+	t.Run("returns error when a segment is both included and excluded", func(t *testing.T) {
+		_, err := processor.CreateAudience(ctx, accountID, CreateAudienceRequest{
+			Name:              "Contradiction",
+			SegmentIDs:        []uuid.UUID{segmentA},
+			ExcludeSegmentIDs: []uuid.UUID{segmentA},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidAudience)
+	})
+
+	t.Run("returns error for unknown combine mode", func(t *testing.T) {
+		_, err := processor.CreateAudience(ctx, accountID, CreateAudienceRequest{
+			Name:        "Unknown",
+			CombineMode: "xor",
+			SegmentIDs:  []uuid.UUID{segmentA},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidAudience)
+	})
+}
+
+func TestPreviewAudience_OtherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockAudienceStore(ctrl)
+	processor := New(mockStore, observability.NewLogger())
+
+	audienceID := uuid.New()
+	mockStore.EXPECT().GetAudienceByID(gomock.Any(), audienceID).Return(store.Audience{ID: audienceID, AccountID: uuid.New()}, nil)
+
+	_, err := processor.PreviewAudience(context.Background(), uuid.New(), audienceID)
+
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
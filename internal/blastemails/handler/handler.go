@@ -6,6 +6,7 @@ import (
 
 	"base-server/internal/apierrors"
 	"base-server/internal/blastemails/processor"
+	"base-server/internal/email"
 	"base-server/internal/observability"
 
 	"github.com/gin-gonic/gin"
@@ -31,7 +32,13 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 	case errors.Is(err, processor.ErrUnauthorized):
 		apierrors.Forbidden(c, "FORBIDDEN", "You do not have access to this template")
 	case errors.Is(err, processor.ErrInvalidTemplateContent):
-		apierrors.BadRequest(c, "INVALID_INPUT", "Invalid template content")
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
+	case errors.Is(err, processor.ErrUnknownTemplateVariables):
+		apierrors.BadRequest(c, "UNKNOWN_TEMPLATE_VARIABLES", err.Error())
+	case errors.Is(err, processor.ErrInvalidHeaders), errors.Is(err, processor.ErrInvalidAttachments):
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
+	case errors.Is(err, processor.ErrInvalidBlocks):
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
 	case errors.Is(err, processor.ErrTestEmailFailed):
 		apierrors.BadRequest(c, "EMAIL_SEND_FAILED", "Failed to send test email")
 	case errors.Is(err, processor.ErrBlastEmailTemplatesNotAvailable):
@@ -43,10 +50,13 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 
 // CreateBlastEmailTemplateRequest represents the HTTP request for creating a blast email template
 type CreateBlastEmailTemplateRequest struct {
-	Name       string      `json:"name" binding:"required,max=255"`
-	Subject    string      `json:"subject" binding:"required,max=255"`
-	HTMLBody   string      `json:"html_body" binding:"required"`
-	BlocksJSON interface{} `json:"blocks_json"`
+	Name        string                    `json:"name" binding:"required,max=255"`
+	Subject     string                    `json:"subject" binding:"required,max=255"`
+	HTMLBody    string                    `json:"html_body" binding:"required_without=BlocksJSON"`
+	BlocksJSON  interface{}               `json:"blocks_json"`
+	TextBody    string                    `json:"text_body,omitempty"`
+	Headers     map[string]string         `json:"headers,omitempty" binding:"omitempty,max=10"`
+	Attachments []email.AttachmentRequest `json:"attachments,omitempty" binding:"omitempty,max=3,dive"`
 }
 
 // HandleCreateBlastEmailTemplate handles POST /api/v1/blast-email-templates
@@ -74,10 +84,13 @@ func (h *Handler) HandleCreateBlastEmailTemplate(c *gin.Context) {
 	}
 
 	processorReq := processor.CreateBlastEmailTemplateRequest{
-		Name:       req.Name,
-		Subject:    req.Subject,
-		HTMLBody:   req.HTMLBody,
-		BlocksJSON: req.BlocksJSON,
+		Name:        req.Name,
+		Subject:     req.Subject,
+		HTMLBody:    req.HTMLBody,
+		BlocksJSON:  req.BlocksJSON,
+		TextBody:    req.TextBody,
+		Headers:     req.Headers,
+		Attachments: email.ToStoreAttachments(req.Attachments),
 	}
 
 	template, err := h.processor.CreateBlastEmailTemplate(ctx, accountID, processorReq)
@@ -154,10 +167,13 @@ func (h *Handler) HandleGetBlastEmailTemplate(c *gin.Context) {
 
 // UpdateBlastEmailTemplateRequest represents the HTTP request for updating a blast email template
 type UpdateBlastEmailTemplateRequest struct {
-	Name       *string     `json:"name,omitempty" binding:"omitempty,max=255"`
-	Subject    *string     `json:"subject,omitempty" binding:"omitempty,max=255"`
-	HTMLBody   *string     `json:"html_body,omitempty"`
-	BlocksJSON interface{} `json:"blocks_json,omitempty"`
+	Name        *string                   `json:"name,omitempty" binding:"omitempty,max=255"`
+	Subject     *string                   `json:"subject,omitempty" binding:"omitempty,max=255"`
+	HTMLBody    *string                   `json:"html_body,omitempty"`
+	BlocksJSON  interface{}               `json:"blocks_json,omitempty"`
+	TextBody    *string                   `json:"text_body,omitempty"`
+	Headers     map[string]string         `json:"headers,omitempty" binding:"omitempty,max=10"`
+	Attachments []email.AttachmentRequest `json:"attachments,omitempty" binding:"omitempty,max=3,dive"`
 }
 
 // HandleUpdateBlastEmailTemplate handles PUT /api/v1/blast-email-templates/:id
@@ -194,10 +210,13 @@ func (h *Handler) HandleUpdateBlastEmailTemplate(c *gin.Context) {
 	}
 
 	processorReq := processor.UpdateBlastEmailTemplateRequest{
-		Name:       req.Name,
-		Subject:    req.Subject,
-		HTMLBody:   req.HTMLBody,
-		BlocksJSON: req.BlocksJSON,
+		Name:        req.Name,
+		Subject:     req.Subject,
+		HTMLBody:    req.HTMLBody,
+		BlocksJSON:  req.BlocksJSON,
+		TextBody:    req.TextBody,
+		Headers:     req.Headers,
+		Attachments: email.ToStoreAttachments(req.Attachments),
 	}
 
 	template, err := h.processor.UpdateBlastEmailTemplate(ctx, accountID, templateID, processorReq)
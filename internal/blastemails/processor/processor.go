@@ -3,13 +3,14 @@ package processor
 //go:generate go run go.uber.org/mock/mockgen@latest -source=processor.go -destination=mocks_test.go -package=processor
 
 import (
+	"base-server/internal/email"
+	"base-server/internal/email/blocks"
+	"base-server/internal/email/templating"
 	"base-server/internal/observability"
 	"base-server/internal/store"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"text/template"
 
 	"github.com/google/uuid"
 )
@@ -35,10 +36,14 @@ type TierChecker interface {
 }
 
 var (
-	ErrBlastEmailTemplateNotFound = errors.New("blast email template not found")
-	ErrUnauthorized               = errors.New("unauthorized access to template")
-	ErrInvalidTemplateContent     = errors.New("invalid template content")
-	ErrTestEmailFailed            = errors.New("failed to send test email")
+	ErrBlastEmailTemplateNotFound      = errors.New("blast email template not found")
+	ErrUnauthorized                    = errors.New("unauthorized access to template")
+	ErrInvalidTemplateContent          = email.ErrInvalidTemplateContent
+	ErrTestEmailFailed                 = errors.New("failed to send test email")
+	ErrInvalidBlocks                   = blocks.ErrInvalidDesign
+	ErrUnknownTemplateVariables        = email.ErrUnknownTemplateVariables
+	ErrInvalidHeaders                  = email.ErrInvalidHeaders
+	ErrInvalidAttachments              = email.ErrInvalidAttachments
 	ErrBlastEmailTemplatesNotAvailable = errors.New("blast email templates are not available in your plan")
 )
 
@@ -60,10 +65,13 @@ func New(store BlastEmailTemplateStore, emailService EmailService, tierChecker T
 
 // CreateBlastEmailTemplateRequest represents a request to create a blast email template
 type CreateBlastEmailTemplateRequest struct {
-	Name       string
-	Subject    string
-	HTMLBody   string
-	BlocksJSON interface{}
+	Name        string
+	Subject     string
+	HTMLBody    string
+	BlocksJSON  interface{}
+	TextBody    string
+	Headers     map[string]string
+	Attachments store.EmailAttachments
 }
 
 // CreateBlastEmailTemplate creates a new blast email template for an account
@@ -82,18 +90,43 @@ func (p *BlastEmailTemplateProcessor) CreateBlastEmailTemplate(ctx context.Conte
 		return store.BlastEmailTemplate{}, ErrBlastEmailTemplatesNotAvailable
 	}
 
+	// Render the HTML body from the blocks design when it isn't supplied
+	blocksJSON := convertToJSONB(req.BlocksJSON)
+	htmlBody := req.HTMLBody
+	textBody := email.OptionalString(req.TextBody)
+	if htmlBody == "" {
+		if blocksJSON == nil {
+			return store.BlastEmailTemplate{}, ErrInvalidTemplateContent
+		}
+		rendered, err := blocks.RenderJSONBWithoutWaitlistData(*blocksJSON)
+		if err != nil {
+			return store.BlastEmailTemplate{}, err
+		}
+		htmlBody = rendered.HTML
+		if textBody == nil {
+			textBody = &rendered.Text
+		}
+	}
+
 	// Validate template content (check if it's valid Go template syntax)
-	if err := validateTemplateContent(req.HTMLBody); err != nil {
+	if err := email.CheckTemplateContent(htmlBody, email.BlastTemplateVariables()); err != nil {
 		p.logger.Error(ctx, "invalid HTML template content", err)
-		return store.BlastEmailTemplate{}, ErrInvalidTemplateContent
+		return store.BlastEmailTemplate{}, err
+	}
+
+	if err := email.CheckMessageParts(textBody, req.Headers, req.Attachments, email.BlastTemplateVariables()); err != nil {
+		return store.BlastEmailTemplate{}, err
 	}
 
 	params := store.CreateBlastEmailTemplateParams{
-		AccountID:  accountID,
-		Name:       req.Name,
-		Subject:    req.Subject,
-		HTMLBody:   req.HTMLBody,
-		BlocksJSON: convertToJSONB(req.BlocksJSON),
+		AccountID:   accountID,
+		Name:        req.Name,
+		Subject:     req.Subject,
+		HTMLBody:    htmlBody,
+		BlocksJSON:  blocksJSON,
+		TextBody:    textBody,
+		Headers:     email.HeadersToJSONB(req.Headers),
+		Attachments: req.Attachments,
 	}
 
 	template, err := p.store.CreateBlastEmailTemplate(ctx, params)
@@ -150,12 +183,16 @@ func (p *BlastEmailTemplateProcessor) ListBlastEmailTemplates(ctx context.Contex
 	return templates, nil
 }
 
-// UpdateBlastEmailTemplateRequest represents a request to update a blast email template
+// UpdateBlastEmailTemplateRequest represents a request to update a blast email template.
+// Headers and Attachments are left unchanged when nil and cleared when empty.
 type UpdateBlastEmailTemplateRequest struct {
-	Name       *string
-	Subject    *string
-	HTMLBody   *string
-	BlocksJSON interface{}
+	Name        *string
+	Subject     *string
+	HTMLBody    *string
+	BlocksJSON  interface{}
+	TextBody    *string
+	Headers     map[string]string
+	Attachments store.EmailAttachments
 }
 
 // UpdateBlastEmailTemplate updates a blast email template
@@ -179,19 +216,39 @@ func (p *BlastEmailTemplateProcessor) UpdateBlastEmailTemplate(ctx context.Conte
 		return store.BlastEmailTemplate{}, ErrUnauthorized
 	}
 
+	// Re-render the HTML body when only the blocks design changed
+	blocksJSON := convertToJSONB(req.BlocksJSON)
+	if req.HTMLBody == nil && blocksJSON != nil {
+		rendered, err := blocks.RenderJSONBWithoutWaitlistData(*blocksJSON)
+		if err != nil {
+			return store.BlastEmailTemplate{}, err
+		}
+		req.HTMLBody = &rendered.HTML
+		if req.TextBody == nil {
+			req.TextBody = &rendered.Text
+		}
+	}
+
 	// Validate template content if provided
 	if req.HTMLBody != nil {
-		if err := validateTemplateContent(*req.HTMLBody); err != nil {
+		if err := email.CheckTemplateContent(*req.HTMLBody, email.BlastTemplateVariables()); err != nil {
 			p.logger.Error(ctx, "invalid HTML template content", err)
-			return store.BlastEmailTemplate{}, ErrInvalidTemplateContent
+			return store.BlastEmailTemplate{}, err
 		}
 	}
 
+	if err := email.CheckMessageParts(req.TextBody, req.Headers, req.Attachments, email.BlastTemplateVariables()); err != nil {
+		return store.BlastEmailTemplate{}, err
+	}
+
 	params := store.UpdateBlastEmailTemplateParams{
-		Name:       req.Name,
-		Subject:    req.Subject,
-		HTMLBody:   req.HTMLBody,
-		BlocksJSON: convertToJSONB(req.BlocksJSON),
+		Name:        req.Name,
+		Subject:     req.Subject,
+		HTMLBody:    req.HTMLBody,
+		BlocksJSON:  blocksJSON,
+		TextBody:    req.TextBody,
+		Headers:     email.HeadersToJSONB(req.Headers),
+		Attachments: req.Attachments,
 	}
 
 	template, err := p.store.UpdateBlastEmailTemplate(ctx, templateID, params)
@@ -296,32 +353,18 @@ func convertToJSONB(data interface{}) *store.JSONB {
 	return nil
 }
 
-func validateTemplateContent(content string) error {
-	_, err := template.New("validate").Parse(content)
-	return err
-}
-
 func renderTemplateWithData(templateContent string, data map[string]interface{}) (string, error) {
-	tmpl, err := template.New("email").Parse(templateContent)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	// Set default test data if not provided
+	// Set default test data if not provided, using the same variables as real blast sends
 	if data == nil {
 		data = map[string]interface{}{
-			"first_name":     "John",
-			"last_name":      "Doe",
-			"email":          "test@example.com",
-			"referral_link":  "https://example.com/ref/ABC123",
-			"referral_count": 5,
+			"Email": "test@example.com",
 		}
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+	html, err := templating.Render("email", templateContent, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
 	}
 
-	return buf.String(), nil
+	return html, nil
 }
@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"base-server/internal/config"
 	"base-server/internal/observability"
+	"base-server/internal/outbound"
 	"base-server/internal/store"
 	"context"
 	"fmt"
@@ -29,6 +30,14 @@ import (
 	campaignemailsProcessor "base-server/internal/campaignemails/processor"
 	emailblastsHandler "base-server/internal/emailblasts/handler"
 	emailblastsProcessor "base-server/internal/emailblasts/processor"
+	emailsequencesHandler "base-server/internal/emailsequences/handler"
+	emailsequencesProcessor "base-server/internal/emailsequences/processor"
+	audiencesHandler "base-server/internal/audiences/handler"
+	audiencesProcessor "base-server/internal/audiences/processor"
+	emailtrackingHandler "base-server/internal/emailtracking/handler"
+	emailtrackingProcessor "base-server/internal/emailtracking/processor"
+	eventlogHandler "base-server/internal/eventlog/handler"
+	eventlogProcessor "base-server/internal/eventlog/processor"
 	integrationConsumer "base-server/internal/integrations/consumer"
 	integrationService "base-server/internal/integrations/service"
 	zapierHandler "base-server/internal/integrations/zapier"
@@ -58,6 +67,9 @@ import (
 	"base-server/internal/workers"
 	blastWorker "base-server/internal/workers/blast"
 	positionWorker "base-server/internal/workers/position"
+	segmentWorker "base-server/internal/workers/segment"
+	sequenceWorker "base-server/internal/workers/sequence"
+	variantWorker "base-server/internal/workers/variant"
 )
 
 // Dependencies holds all initialized application dependencies
@@ -83,6 +95,10 @@ type Dependencies struct {
 	APIKeysHandler       *apikeysHandler.Handler
 	SegmentsHandler      segmentsHandler.Handler
 	EmailblastsHandler   emailblastsHandler.Handler
+	EmailSequencesHandler emailsequencesHandler.Handler
+	AudiencesHandler      audiencesHandler.Handler
+	EventLogHandler       eventlogHandler.Handler
+	EmailTrackingHandler  emailtrackingHandler.Handler
 
 	// Background workers
 	WebhookConsumer     workers.EventConsumer
@@ -91,8 +107,18 @@ type Dependencies struct {
 	SpamConsumer        workers.EventConsumer
 	IntegrationConsumer workers.EventConsumer
 	BlastConsumer       workers.EventConsumer
+	EventLogConsumer    workers.EventConsumer
 	WebhookWorker       *webhookWorker.WebhookWorker
+	WebhookDisableScheduler *webhookWorker.DisableScheduler
+	WebhookDeliveryQueue    *webhookService.DeliveryQueue
+	WebhookRetentionScheduler *webhookWorker.RetentionScheduler
 	BlastScheduler      *blastWorker.BlastScheduler
+	SequenceScheduler   *sequenceWorker.SequenceScheduler
+	PositionDigestScheduler *positionWorker.DigestScheduler
+	SegmentRefreshScheduler *segmentWorker.RefreshScheduler
+	SegmentSyncScheduler    *segmentWorker.SyncScheduler
+	SegmentExportWorker     *segmentWorker.ExportWorker
+	VariantPromotionScheduler *variantWorker.PromotionScheduler
 
 	// Kafka clients (for cleanup)
 	KafkaProducer *kafkaClient.Producer
@@ -225,20 +251,69 @@ func Initialize(ctx context.Context, cfg *config.Config, logger *observability.L
 	emailblastsProc := emailblastsProcessor.New(&deps.Store, tierService, eventDispatcher, logger)
 	deps.EmailblastsHandler = emailblastsHandler.New(emailblastsProc, logger)
 
+	// Initialize email sequences processor and handler
+	emailSequencesProc := emailsequencesProcessor.New(&deps.Store, logger)
+	deps.EmailSequencesHandler = emailsequencesHandler.New(emailSequencesProc, logger)
+
+	// Initialize audiences processor and handler
+	audiencesProc := audiencesProcessor.New(&deps.Store, logger)
+	deps.AudiencesHandler = audiencesHandler.New(audiencesProc, logger)
+
+	// Outbound network policy for webhook and Zapier deliveries to user-supplied URLs
+	outboundPolicy := outbound.Policy{
+		AllowedHosts: cfg.Outbound.AllowedHosts,
+		RequireHTTPS: cfg.Outbound.RequireHTTPS,
+		MaxRedirects: cfg.Outbound.MaxRedirects,
+	}
+
 	// Initialize webhook services
-	webhookSvc := webhookService.New(&deps.Store, logger)
+	webhookSvc := webhookService.New(&deps.Store, logger, webhookService.CircuitBreakerConfig{
+		FailureThreshold: cfg.Webhook.CircuitFailureThreshold,
+		ProbeInterval:    time.Duration(cfg.Webhook.CircuitProbeIntervalMinutes) * time.Minute,
+	}, outboundPolicy, webhookService.DeliveryConfig{
+		Timeout:     time.Duration(cfg.Webhook.DeliveryTimeoutSeconds) * time.Second,
+		MaxInFlight: cfg.Webhook.MaxInFlightPerEndpoint,
+		QueueSize:   cfg.Webhook.QueueSizePerEndpoint,
+	})
+	deps.WebhookDeliveryQueue = webhookSvc.DeliveryQueue()
 	webhookProc := webhookEventProcessor.New(&deps.Store, tierService, logger, webhookSvc)
 	deps.WebhookHandler = webhookHandler.New(webhookProc, logger)
 
+	// Initialize event log processor and handler
+	eventlogProc := eventlogProcessor.New(&deps.Store, logger)
+	deps.EventLogHandler = eventlogHandler.New(eventlogProc, logger)
+
+	// Initialize email tracking processor and handler for Resend open and click events
+	emailtrackingProc := emailtrackingProcessor.New(&deps.Store, cfg.Services.ResendWebhookSecret, logger)
+	deps.EmailTrackingHandler = emailtrackingHandler.New(emailtrackingProc, logger)
+
 	// Initialize webhook retry worker (runs every 30 seconds)
 	deps.WebhookWorker = webhookWorker.New(webhookSvc, logger, 30*time.Second)
 
+	// Initialize webhook auto-disable scheduler (disables endpoints failing for days, hourly)
+	webhookFailingFor := time.Duration(cfg.Webhook.AutoDisableDays) * 24 * time.Hour
+	deps.WebhookDisableScheduler = webhookWorker.NewDisableScheduler(&deps.Store, emailService, cfg.Services.WebAppURI, logger, time.Hour, webhookFailingFor)
+
+	// Initialize webhook retention scheduler (rolls up daily stats, purges and redacts delivery logs, hourly)
+	deps.WebhookRetentionScheduler = webhookWorker.NewRetentionScheduler(&deps.Store, tierService, webhookWorker.RetentionConfig{
+		DefaultRetentionDays: cfg.Webhook.LogRetentionDays,
+		RedactAfter:          time.Duration(cfg.Webhook.RedactAfterDays) * 24 * time.Hour,
+		RedactFields:         cfg.Webhook.RedactFields,
+	}, logger, time.Hour)
+
 	// Initialize webhook event processor and consumer
 	webhookEvtProcessor := webhookEventProcessor.NewWebhookEventProcessor(webhookSvc, logger)
 	webhookConsumerConfig := workers.DefaultConsumerConfig(brokerList, cfg.Kafka.ConsumerGroup, cfg.Kafka.Topic)
 	webhookConsumerConfig.NumWorkers = cfg.WorkerPool.WebhookWorkers
+	// Deliveries are recorded in the order of each account's events, which ordered webhooks rely on
+	webhookConsumerConfig.OrderByAccount = true
 	deps.WebhookConsumer = workers.NewConsumer(webhookConsumerConfig, webhookEvtProcessor, logger)
 
+	// Initialize event log recorder and consumer (appends every public event to the event log)
+	eventRecorder := eventlogProcessor.NewEventRecorder(&deps.Store, logger)
+	eventLogConsumerConfig := workers.DefaultConsumerConfig(brokerList, cfg.Kafka.ConsumerGroup+"-eventlog", cfg.Kafka.Topic)
+	deps.EventLogConsumer = workers.NewConsumer(eventLogConsumerConfig, eventRecorder, logger)
+
 	// Initialize email event processor and consumer
 	emailEvtProcessor := email.NewEmailEventProcessor(emailService, deps.Store, logger)
 	emailConsumerConfig := workers.DefaultConsumerConfig(brokerList, cfg.Kafka.ConsumerGroup+"-email", cfg.Kafka.Topic)
@@ -251,6 +326,10 @@ func Initialize(ctx context.Context, cfg *config.Config, logger *observability.L
 	positionConsumerConfig.NumWorkers = cfg.WorkerPool.PositionWorkers
 	deps.PositionConsumer = workers.NewConsumer(positionConsumerConfig, positionEvtProcessor, logger)
 
+	// Initialize position update digest scheduler (emails users who moved up, checked every minute)
+	digestInterval := time.Duration(cfg.PositionUpdate.DigestHours) * time.Hour
+	deps.PositionDigestScheduler = positionWorker.NewDigestScheduler(&deps.Store, emailService, cfg.Services.WebAppURI, logger, time.Minute, digestInterval, cfg.PositionUpdate.MinSpots)
+
 	// Initialize spam detection processor and consumer
 	spamProc := spamProcessor.New(&deps.Store, logger)
 	spamEvtProcessor := spamConsumer.NewSpamEventProcessor(spamProc, deps.Store, logger)
@@ -267,6 +346,7 @@ func Initialize(ctx context.Context, cfg *config.Config, logger *observability.L
 
 	// Initialize integration service with deliverers
 	intService := integrationService.New(&deps.Store, logger)
+	intService.Register(integrationService.NewZapierDeliverer(logger, outboundPolicy))
 
 	// Initialize integration event processor and consumer
 	integrationEvtProcessor := integrationConsumer.NewIntegrationEventProcessor(intService, &deps.Store, logger)
@@ -277,16 +357,36 @@ func Initialize(ctx context.Context, cfg *config.Config, logger *observability.L
 	logger.Info(ctx, "Integrations system initialized")
 
 	// Initialize blast event processor and consumer
-	blastEvtProcessor := blastWorker.NewBlastEventProcessor(&deps.Store, emailService, eventDispatcher, logger)
+	sendRates := blastWorker.SendRates{
+		ProviderPerSecond: cfg.SendRate.ProviderPerSecond,
+		AccountPerSecond:  cfg.SendRate.AccountPerSecond,
+	}
+	blastEvtProcessor := blastWorker.NewBlastEventProcessor(&deps.Store, emailService, eventDispatcher, sendRates, logger)
 	blastConsumerConfig := workers.DefaultConsumerConfig(brokerList, cfg.Kafka.ConsumerGroup+"-blast", cfg.Kafka.Topic)
 	blastConsumerConfig.NumWorkers = 5 // Configurable via cfg if needed
 	deps.BlastConsumer = workers.NewConsumer(blastConsumerConfig, blastEvtProcessor, logger)
 
 	// Initialize blast scheduler (checks for scheduled blasts every 30 seconds)
-	deps.BlastScheduler = blastWorker.NewBlastScheduler(&deps.Store, eventDispatcher, logger, 30*time.Second)
+	stallTimeout := time.Duration(cfg.Blast.StallTimeoutMinutes) * time.Minute
+	deps.BlastScheduler = blastWorker.NewBlastScheduler(&deps.Store, eventDispatcher, logger, 30*time.Second, stallTimeout)
 
 	logger.Info(ctx, "Email blast system initialized")
 
+	// Initialize sequence scheduler (sends due drip email steps every 30 seconds)
+	deps.SequenceScheduler = sequenceWorker.NewSequenceScheduler(&deps.Store, emailService, cfg.Services.WebAppURI, logger, 30*time.Second)
+
+	// Initialize segment count refresh scheduler (recounts stale segments every minute)
+	segmentRefreshInterval := time.Duration(cfg.Segment.RefreshIntervalMinutes) * time.Minute
+	deps.SegmentRefreshScheduler = segmentWorker.NewRefreshScheduler(&deps.Store, eventDispatcher, logger, time.Minute, segmentRefreshInterval)
+
+	// Initialize segment sync scheduler (publishes member added/removed events) and export worker
+	segmentSyncInterval := time.Duration(cfg.Segment.SyncIntervalMinutes) * time.Minute
+	deps.SegmentSyncScheduler = segmentWorker.NewSyncScheduler(&deps.Store, eventDispatcher, logger, time.Minute, segmentSyncInterval)
+	deps.SegmentExportWorker = segmentWorker.NewExportWorker(&deps.Store, logger, 10*time.Second)
+
+	// Initialize email template variant promotion scheduler (promotes A/B test winners every 15 minutes)
+	deps.VariantPromotionScheduler = variantWorker.NewPromotionScheduler(&deps.Store, logger, 15*time.Minute)
+
 	return deps, nil
 }
 
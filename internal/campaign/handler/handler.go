@@ -27,11 +27,12 @@ func New(processor processor.CampaignProcessor, logger *observability.Logger) Ha
 
 // EmailSettingsRequest represents email settings in HTTP request
 type EmailSettingsRequest struct {
-	FromName             *string `json:"from_name,omitempty"`
-	FromEmail            *string `json:"from_email,omitempty"`
-	ReplyTo              *string `json:"reply_to,omitempty"`
-	VerificationRequired bool    `json:"verification_required"`
-	SendWelcomeEmail     bool    `json:"send_welcome_email"`
+	FromName                *string `json:"from_name,omitempty"`
+	FromEmail               *string `json:"from_email,omitempty"`
+	ReplyTo                 *string `json:"reply_to,omitempty"`
+	VerificationRequired    bool    `json:"verification_required"`
+	SendWelcomeEmail        bool    `json:"send_welcome_email"`
+	VariantAutoPromoteAfter *int    `json:"variant_auto_promote_after,omitempty" binding:"omitempty,min=1"`
 }
 
 // BrandingSettingsRequest represents branding settings in HTTP request
@@ -443,11 +444,12 @@ func convertSettingsRequest(
 
 	if emailSettings != nil {
 		settings.EmailSettings = &processor.EmailSettingsParams{
-			FromName:             emailSettings.FromName,
-			FromEmail:            emailSettings.FromEmail,
-			ReplyTo:              emailSettings.ReplyTo,
-			VerificationRequired: emailSettings.VerificationRequired,
-			SendWelcomeEmail:     emailSettings.SendWelcomeEmail,
+			FromName:                emailSettings.FromName,
+			FromEmail:               emailSettings.FromEmail,
+			ReplyTo:                 emailSettings.ReplyTo,
+			VerificationRequired:    emailSettings.VerificationRequired,
+			SendWelcomeEmail:        emailSettings.SendWelcomeEmail,
+			VariantAutoPromoteAfter: emailSettings.VariantAutoPromoteAfter,
 		}
 	}
 
@@ -105,11 +105,12 @@ type CreateCampaignParams struct {
 
 // EmailSettingsParams represents email settings parameters
 type EmailSettingsParams struct {
-	FromName             *string
-	FromEmail            *string
-	ReplyTo              *string
-	VerificationRequired bool
-	SendWelcomeEmail     bool
+	FromName                *string
+	FromEmail               *string
+	ReplyTo                 *string
+	VerificationRequired    bool
+	SendWelcomeEmail        bool
+	VariantAutoPromoteAfter *int
 }
 
 // BrandingSettingsParams represents branding settings parameters
@@ -277,12 +278,13 @@ func (p *CampaignProcessor) upsertCampaignSettings(ctx context.Context, accountI
 	// Upsert email settings
 	if settings.EmailSettings != nil {
 		_, err := p.store.UpsertCampaignEmailSettings(ctx, store.CreateCampaignEmailSettingsParams{
-			CampaignID:           campaignID,
-			FromName:             settings.EmailSettings.FromName,
-			FromEmail:            settings.EmailSettings.FromEmail,
-			ReplyTo:              settings.EmailSettings.ReplyTo,
-			VerificationRequired: settings.EmailSettings.VerificationRequired,
-			SendWelcomeEmail:     settings.EmailSettings.SendWelcomeEmail,
+			CampaignID:              campaignID,
+			FromName:                settings.EmailSettings.FromName,
+			FromEmail:               settings.EmailSettings.FromEmail,
+			ReplyTo:                 settings.EmailSettings.ReplyTo,
+			VerificationRequired:    settings.EmailSettings.VerificationRequired,
+			SendWelcomeEmail:        settings.EmailSettings.SendWelcomeEmail,
+			VariantAutoPromoteAfter: settings.EmailSettings.VariantAutoPromoteAfter,
 		})
 		if err != nil {
 			return err
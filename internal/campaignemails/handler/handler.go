@@ -6,6 +6,7 @@ import (
 
 	"base-server/internal/apierrors"
 	"base-server/internal/campaignemails/processor"
+	"base-server/internal/email"
 	"base-server/internal/observability"
 
 	"github.com/gin-gonic/gin"
@@ -35,7 +36,13 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 	case errors.Is(err, processor.ErrInvalidTemplateType):
 		apierrors.BadRequest(c, "INVALID_TYPE", "Invalid template type")
 	case errors.Is(err, processor.ErrInvalidTemplateContent):
-		apierrors.BadRequest(c, "INVALID_INPUT", "Invalid template content")
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
+	case errors.Is(err, processor.ErrUnknownTemplateVariables):
+		apierrors.BadRequest(c, "UNKNOWN_TEMPLATE_VARIABLES", err.Error())
+	case errors.Is(err, processor.ErrInvalidHeaders), errors.Is(err, processor.ErrInvalidAttachments):
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
+	case errors.Is(err, processor.ErrInvalidBlocks):
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
 	case errors.Is(err, processor.ErrTestEmailFailed):
 		apierrors.BadRequest(c, "EMAIL_SEND_FAILED", "Failed to send test email")
 	case errors.Is(err, processor.ErrVisualEmailBuilderNotAvailable):
@@ -47,13 +54,18 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 
 // CreateCampaignEmailTemplateRequest represents the HTTP request for creating a campaign email template
 type CreateCampaignEmailTemplateRequest struct {
-	Name              string      `json:"name" binding:"required,max=255"`
-	Type              string      `json:"type" binding:"required,oneof=verification welcome position_update reward_earned milestone custom"`
-	Subject           string      `json:"subject" binding:"required,max=255"`
-	HTMLBody          string      `json:"html_body" binding:"required"`
-	BlocksJSON        interface{} `json:"blocks_json"`
-	Enabled           *bool       `json:"enabled"`
-	SendAutomatically *bool       `json:"send_automatically"`
+	Name              string                    `json:"name" binding:"required,max=255"`
+	Type              string                    `json:"type" binding:"required,oneof=verification welcome position_update reward_earned milestone custom"`
+	Subject           string                    `json:"subject" binding:"required,max=255"`
+	HTMLBody          string                    `json:"html_body" binding:"required_without=BlocksJSON"`
+	BlocksJSON        interface{}               `json:"blocks_json"`
+	TextBody          string                    `json:"text_body,omitempty"`
+	Headers           map[string]string         `json:"headers,omitempty" binding:"omitempty,max=10"`
+	Attachments       []email.AttachmentRequest `json:"attachments,omitempty" binding:"omitempty,max=3,dive"`
+	Enabled           *bool                     `json:"enabled"`
+	SendAutomatically *bool                     `json:"send_automatically"`
+	VariantName       *string                   `json:"variant_name,omitempty" binding:"omitempty,max=100"`
+	VariantWeight     *int                      `json:"variant_weight,omitempty" binding:"omitempty,min=0,max=100"`
 }
 
 // HandleCreateCampaignEmailTemplate handles POST /api/v1/campaigns/:campaign_id/email-templates
@@ -95,8 +107,13 @@ func (h *Handler) HandleCreateCampaignEmailTemplate(c *gin.Context) {
 		Subject:           req.Subject,
 		HTMLBody:          req.HTMLBody,
 		BlocksJSON:        req.BlocksJSON,
+		TextBody:          req.TextBody,
+		Headers:           req.Headers,
+		Attachments:       email.ToStoreAttachments(req.Attachments),
 		Enabled:           req.Enabled,
 		SendAutomatically: req.SendAutomatically,
+		VariantName:       req.VariantName,
+		VariantWeight:     req.VariantWeight,
 	}
 
 	template, err := h.processor.CreateCampaignEmailTemplate(ctx, accountID, campaignID, processorReq)
@@ -224,12 +241,17 @@ func (h *Handler) HandleGetCampaignEmailTemplate(c *gin.Context) {
 
 // UpdateCampaignEmailTemplateRequest represents the HTTP request for updating a campaign email template
 type UpdateCampaignEmailTemplateRequest struct {
-	Name              *string     `json:"name,omitempty" binding:"omitempty,max=255"`
-	Subject           *string     `json:"subject,omitempty" binding:"omitempty,max=255"`
-	HTMLBody          *string     `json:"html_body,omitempty"`
-	BlocksJSON        interface{} `json:"blocks_json,omitempty"`
-	Enabled           *bool       `json:"enabled,omitempty"`
-	SendAutomatically *bool       `json:"send_automatically,omitempty"`
+	Name              *string                   `json:"name,omitempty" binding:"omitempty,max=255"`
+	Subject           *string                   `json:"subject,omitempty" binding:"omitempty,max=255"`
+	HTMLBody          *string                   `json:"html_body,omitempty"`
+	BlocksJSON        interface{}               `json:"blocks_json,omitempty"`
+	TextBody          *string                   `json:"text_body,omitempty"`
+	Headers           map[string]string         `json:"headers,omitempty" binding:"omitempty,max=10"`
+	Attachments       []email.AttachmentRequest `json:"attachments,omitempty" binding:"omitempty,max=3,dive"`
+	Enabled           *bool                     `json:"enabled,omitempty"`
+	SendAutomatically *bool                     `json:"send_automatically,omitempty"`
+	VariantName       *string                   `json:"variant_name,omitempty" binding:"omitempty,max=100"`
+	VariantWeight     *int                      `json:"variant_weight,omitempty" binding:"omitempty,min=0,max=100"`
 }
 
 // HandleUpdateCampaignEmailTemplate handles PUT /api/v1/campaigns/:campaign_id/email-templates/:template_id
@@ -279,8 +301,13 @@ func (h *Handler) HandleUpdateCampaignEmailTemplate(c *gin.Context) {
 		Subject:           req.Subject,
 		HTMLBody:          req.HTMLBody,
 		BlocksJSON:        req.BlocksJSON,
+		TextBody:          req.TextBody,
+		Headers:           req.Headers,
+		Attachments:       email.ToStoreAttachments(req.Attachments),
 		Enabled:           req.Enabled,
 		SendAutomatically: req.SendAutomatically,
+		VariantName:       req.VariantName,
+		VariantWeight:     req.VariantWeight,
 	}
 
 	template, err := h.processor.UpdateCampaignEmailTemplate(ctx, accountID, campaignID, templateID, processorReq)
@@ -401,3 +428,45 @@ func (h *Handler) HandleSendTestEmail(c *gin.Context) {
 		"sent_at": ctx.Value("timestamp"),
 	})
 }
+
+// HandleGetTemplateVariantAnalytics handles GET /api/v1/campaigns/:campaign_id/email-templates/variant-analytics
+func (h *Handler) HandleGetTemplateVariantAnalytics(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	templateType := c.Query("type")
+	if templateType == "" {
+		apierrors.BadRequest(c, "INVALID_INPUT", "type query parameter is required")
+		return
+	}
+
+	analytics, err := h.processor.GetTemplateVariantAnalytics(ctx, accountID, campaignID, templateType)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
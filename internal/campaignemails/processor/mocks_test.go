@@ -101,6 +101,21 @@ func (mr *MockCampaignEmailTemplateStoreMockRecorder) GetCampaignEmailTemplateBy
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignEmailTemplateByID", reflect.TypeOf((*MockCampaignEmailTemplateStore)(nil).GetCampaignEmailTemplateByID), ctx, templateID)
 }
 
+// GetCampaignEmailTemplateVariantStats mocks base method.
+func (m *MockCampaignEmailTemplateStore) GetCampaignEmailTemplateVariantStats(ctx context.Context, campaignID uuid.UUID, templateType string) ([]store.CampaignEmailTemplateVariantStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignEmailTemplateVariantStats", ctx, campaignID, templateType)
+	ret0, _ := ret[0].([]store.CampaignEmailTemplateVariantStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignEmailTemplateVariantStats indicates an expected call of GetCampaignEmailTemplateVariantStats.
+func (mr *MockCampaignEmailTemplateStoreMockRecorder) GetCampaignEmailTemplateVariantStats(ctx, campaignID, templateType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignEmailTemplateVariantStats", reflect.TypeOf((*MockCampaignEmailTemplateStore)(nil).GetCampaignEmailTemplateVariantStats), ctx, campaignID, templateType)
+}
+
 // GetCampaignEmailTemplatesByAccount mocks base method.
 func (m *MockCampaignEmailTemplateStore) GetCampaignEmailTemplatesByAccount(ctx context.Context, accountID uuid.UUID) ([]store.CampaignEmailTemplate, error) {
 	m.ctrl.T.Helper()
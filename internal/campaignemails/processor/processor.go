@@ -3,14 +3,15 @@ package processor
 //go:generate go run go.uber.org/mock/mockgen@latest -source=processor.go -destination=mocks_test.go -package=processor
 
 import (
+	"base-server/internal/email"
+	"base-server/internal/email/blocks"
+	"base-server/internal/email/templating"
 	"base-server/internal/observability"
 	"base-server/internal/store"
 	"base-server/internal/tiers"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"text/template"
 
 	"github.com/google/uuid"
 )
@@ -24,6 +25,7 @@ type CampaignEmailTemplateStore interface {
 	GetCampaignEmailTemplatesByAccount(ctx context.Context, accountID uuid.UUID) ([]store.CampaignEmailTemplate, error)
 	UpdateCampaignEmailTemplate(ctx context.Context, templateID uuid.UUID, params store.UpdateCampaignEmailTemplateParams) (store.CampaignEmailTemplate, error)
 	DeleteCampaignEmailTemplate(ctx context.Context, templateID uuid.UUID) error
+	GetCampaignEmailTemplateVariantStats(ctx context.Context, campaignID uuid.UUID, templateType string) ([]store.CampaignEmailTemplateVariantStats, error)
 }
 
 // EmailService defines the email operations required by CampaignEmailTemplateProcessor
@@ -32,13 +34,17 @@ type EmailService interface {
 }
 
 var (
-	ErrCampaignEmailTemplateNotFound   = errors.New("campaign email template not found")
-	ErrCampaignNotFound                = errors.New("campaign not found")
-	ErrUnauthorized                    = errors.New("unauthorized access to template")
-	ErrInvalidTemplateType             = errors.New("invalid template type")
-	ErrInvalidTemplateContent          = errors.New("invalid template content")
-	ErrTestEmailFailed                 = errors.New("failed to send test email")
-	ErrVisualEmailBuilderNotAvailable  = errors.New("visual email builder is not available in your plan")
+	ErrCampaignEmailTemplateNotFound  = errors.New("campaign email template not found")
+	ErrCampaignNotFound               = errors.New("campaign not found")
+	ErrUnauthorized                   = errors.New("unauthorized access to template")
+	ErrInvalidTemplateType            = errors.New("invalid template type")
+	ErrInvalidTemplateContent         = email.ErrInvalidTemplateContent
+	ErrTestEmailFailed                = errors.New("failed to send test email")
+	ErrVisualEmailBuilderNotAvailable = errors.New("visual email builder is not available in your plan")
+	ErrInvalidBlocks                  = blocks.ErrInvalidDesign
+	ErrUnknownTemplateVariables       = email.ErrUnknownTemplateVariables
+	ErrInvalidHeaders                 = email.ErrInvalidHeaders
+	ErrInvalidAttachments             = email.ErrInvalidAttachments
 )
 
 type CampaignEmailTemplateProcessor struct {
@@ -64,8 +70,13 @@ type CreateCampaignEmailTemplateRequest struct {
 	Subject           string
 	HTMLBody          string
 	BlocksJSON        interface{}
+	TextBody          string
+	Headers           map[string]string
+	Attachments       store.EmailAttachments
 	Enabled           *bool
 	SendAutomatically *bool
+	VariantName       *string
+	VariantWeight     *int
 }
 
 // CreateCampaignEmailTemplate creates a new email template for a campaign
@@ -91,10 +102,32 @@ func (p *CampaignEmailTemplateProcessor) CreateCampaignEmailTemplate(ctx context
 		return store.CampaignEmailTemplate{}, ErrInvalidTemplateType
 	}
 
+	// Render the HTML body from the blocks design when it isn't supplied
+	blocksJSON := convertToJSONB(req.BlocksJSON)
+	htmlBody := req.HTMLBody
+	textBody := email.OptionalString(req.TextBody)
+	if htmlBody == "" {
+		if blocksJSON == nil {
+			return store.CampaignEmailTemplate{}, ErrInvalidTemplateContent
+		}
+		rendered, err := blocks.RenderJSONB(*blocksJSON)
+		if err != nil {
+			return store.CampaignEmailTemplate{}, err
+		}
+		htmlBody = rendered.HTML
+		if textBody == nil {
+			textBody = &rendered.Text
+		}
+	}
+
 	// Validate template content (check if it's valid Go template syntax)
-	if err := validateTemplateContent(req.HTMLBody); err != nil {
+	if err := email.CheckTemplateContent(htmlBody, email.TemplateVariables()); err != nil {
 		p.logger.Error(ctx, "invalid HTML template content", err)
-		return store.CampaignEmailTemplate{}, ErrInvalidTemplateContent
+		return store.CampaignEmailTemplate{}, err
+	}
+
+	if err := email.CheckMessageParts(textBody, req.Headers, req.Attachments, email.TemplateVariables()); err != nil {
+		return store.CampaignEmailTemplate{}, err
 	}
 
 	// Verify campaign exists and belongs to account
@@ -127,10 +160,15 @@ func (p *CampaignEmailTemplateProcessor) CreateCampaignEmailTemplate(ctx context
 		Name:              req.Name,
 		Type:              req.Type,
 		Subject:           req.Subject,
-		HTMLBody:          req.HTMLBody,
-		BlocksJSON:        convertToJSONB(req.BlocksJSON),
+		HTMLBody:          htmlBody,
+		BlocksJSON:        blocksJSON,
+		TextBody:          textBody,
+		Headers:           email.HeadersToJSONB(req.Headers),
+		Attachments:       req.Attachments,
 		Enabled:           enabled,
 		SendAutomatically: sendAutomatically,
+		VariantName:       req.VariantName,
+		VariantWeight:     req.VariantWeight,
 	}
 
 	emailTemplate, err := p.store.CreateCampaignEmailTemplate(ctx, params)
@@ -253,14 +291,20 @@ func (p *CampaignEmailTemplateProcessor) ListAllCampaignEmailTemplates(ctx conte
 	return templates, nil
 }
 
-// UpdateCampaignEmailTemplateRequest represents a request to update a campaign email template
+// UpdateCampaignEmailTemplateRequest represents a request to update a campaign email template.
+// Headers and Attachments are left unchanged when nil and cleared when empty.
 type UpdateCampaignEmailTemplateRequest struct {
 	Name              *string
 	Subject           *string
 	HTMLBody          *string
 	BlocksJSON        interface{}
+	TextBody          *string
+	Headers           map[string]string
+	Attachments       store.EmailAttachments
 	Enabled           *bool
 	SendAutomatically *bool
+	VariantName       *string
+	VariantWeight     *int
 }
 
 // UpdateCampaignEmailTemplate updates a campaign email template
@@ -309,21 +353,43 @@ func (p *CampaignEmailTemplateProcessor) UpdateCampaignEmailTemplate(ctx context
 		return store.CampaignEmailTemplate{}, ErrUnauthorized
 	}
 
+	// Re-render the HTML body when only the blocks design changed
+	blocksJSON := convertToJSONB(req.BlocksJSON)
+	if req.HTMLBody == nil && blocksJSON != nil {
+		rendered, err := blocks.RenderJSONB(*blocksJSON)
+		if err != nil {
+			return store.CampaignEmailTemplate{}, err
+		}
+		req.HTMLBody = &rendered.HTML
+		if req.TextBody == nil {
+			req.TextBody = &rendered.Text
+		}
+	}
+
 	// Validate template content if provided
 	if req.HTMLBody != nil {
-		if err := validateTemplateContent(*req.HTMLBody); err != nil {
+		if err := email.CheckTemplateContent(*req.HTMLBody, email.TemplateVariables()); err != nil {
 			p.logger.Error(ctx, "invalid HTML template content", err)
-			return store.CampaignEmailTemplate{}, ErrInvalidTemplateContent
+			return store.CampaignEmailTemplate{}, err
 		}
 	}
 
+	if err := email.CheckMessageParts(req.TextBody, req.Headers, req.Attachments, email.TemplateVariables()); err != nil {
+		return store.CampaignEmailTemplate{}, err
+	}
+
 	params := store.UpdateCampaignEmailTemplateParams{
 		Name:              req.Name,
 		Subject:           req.Subject,
 		HTMLBody:          req.HTMLBody,
-		BlocksJSON:        convertToJSONB(req.BlocksJSON),
+		BlocksJSON:        blocksJSON,
+		TextBody:          req.TextBody,
+		Headers:           email.HeadersToJSONB(req.Headers),
+		Attachments:       req.Attachments,
 		Enabled:           req.Enabled,
 		SendAutomatically: req.SendAutomatically,
+		VariantName:       req.VariantName,
+		VariantWeight:     req.VariantWeight,
 	}
 
 	emailTemplate, err := p.store.UpdateCampaignEmailTemplate(ctx, templateID, params)
@@ -388,6 +454,54 @@ func (p *CampaignEmailTemplateProcessor) DeleteCampaignEmailTemplate(ctx context
 	return nil
 }
 
+// TemplateVariantAnalytics represents A/B test results for the variants of one template type
+type TemplateVariantAnalytics struct {
+	TemplateType string                `json:"template_type"`
+	Metric       string                `json:"metric"`
+	Variants     []email.VariantResult `json:"variants"`
+}
+
+// GetTemplateVariantAnalytics returns send, open, click and verify rates for each variant of a template type,
+// along with the significance of each variant's primary metric compared to the control
+func (p *CampaignEmailTemplateProcessor) GetTemplateVariantAnalytics(ctx context.Context, accountID, campaignID uuid.UUID, templateType string) (TemplateVariantAnalytics, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+		observability.Field{Key: "template_type", Value: templateType},
+	)
+
+	if !isValidTemplateType(templateType) {
+		return TemplateVariantAnalytics{}, ErrInvalidTemplateType
+	}
+
+	// Verify campaign exists and belongs to account
+	campaign, err := p.store.GetCampaignByID(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return TemplateVariantAnalytics{}, ErrCampaignNotFound
+		}
+		p.logger.Error(ctx, "failed to get campaign", err)
+		return TemplateVariantAnalytics{}, err
+	}
+
+	if campaign.AccountID != accountID {
+		return TemplateVariantAnalytics{}, ErrUnauthorized
+	}
+
+	stats, err := p.store.GetCampaignEmailTemplateVariantStats(ctx, campaignID, templateType)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get template variant stats", err)
+		return TemplateVariantAnalytics{}, err
+	}
+
+	metric := email.PrimaryVariantMetric(templateType)
+	return TemplateVariantAnalytics{
+		TemplateType: templateType,
+		Metric:       metric,
+		Variants:     email.EvaluateVariants(stats, metric),
+	}, nil
+}
+
 // SendTestEmailRequest represents a request to send a test email
 type SendTestEmailRequest struct {
 	RecipientEmail string
@@ -457,33 +571,26 @@ func isValidTemplateType(templateType string) bool {
 }
 
 func validateTemplateContent(content string) error {
-	_, err := template.New("validate").Parse(content)
-	return err
+	return templating.Validate(content)
 }
 
 func renderTemplateWithData(templateContent string, data map[string]interface{}) (string, error) {
-	tmpl, err := template.New("email").Parse(templateContent)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	// Set default test data if not provided
+	// Set default test data if not provided, using the same variable names as real sends
 	if data == nil {
 		data = map[string]interface{}{
-			"first_name":     "John",
-			"last_name":      "Doe",
-			"email":          "test@example.com",
-			"position":       1,
-			"referral_link":  "https://example.com/ref/ABC123",
-			"referral_count": 5,
-			"campaign_name":  "Test Campaign",
+			"FirstName":     "John",
+			"Email":         "test@example.com",
+			"Position":      1,
+			"ReferralLink":  "https://example.com/ref/ABC123",
+			"ReferralCount": 5,
+			"CampaignName":  "Test Campaign",
 		}
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+	html, err := templating.Render("email", templateContent, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
 	}
 
-	return buf.String(), nil
+	return html, nil
 }
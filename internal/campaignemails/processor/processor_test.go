@@ -6,6 +6,7 @@ import (
 	"base-server/internal/tiers"
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -140,6 +141,129 @@ func TestCreateCampaignEmailTemplate(t *testing.T) {
 		}
 	})
 
+	t.Run("returns error for unknown template variables", func(t *testing.T) {
+		req := CreateCampaignEmailTemplateRequest{
+			Name:     "Unknown Variables",
+			Type:     "welcome",
+			Subject:  "Welcome",
+			HTMLBody: "<h1>Hi {{.FirstName}}</h1><p>{{.Nickname}}</p>",
+		}
+
+		_, err := processor.CreateCampaignEmailTemplate(ctx, accountID, campaignID, req)
+
+		if !errors.Is(err, ErrUnknownTemplateVariables) {
+			t.Errorf("expected ErrUnknownTemplateVariables, got %v", err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "Nickname") {
+			t.Errorf("expected error to list Nickname, got %v", err)
+		}
+	})
+
+	t.Run("invalid template content error includes the line number", func(t *testing.T) {
+		req := CreateCampaignEmailTemplateRequest{
+			Name:     "Invalid HTML",
+			Type:     "welcome",
+			Subject:  "Welcome",
+			HTMLBody: "<h1>Welcome</h1>\n<p>{{.FirstName</p>",
+		}
+
+		_, err := processor.CreateCampaignEmailTemplate(ctx, accountID, campaignID, req)
+
+		if !errors.Is(err, ErrInvalidTemplateContent) {
+			t.Errorf("expected ErrInvalidTemplateContent, got %v", err)
+		}
+		if err != nil && !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected error to include line 2, got %v", err)
+		}
+	})
+
+	t.Run("renders HTML body from blocks when html_body is omitted", func(t *testing.T) {
+		campaign := store.Campaign{
+			ID:        campaignID,
+			AccountID: accountID,
+			Name:      "Test Campaign",
+			Status:    "active",
+		}
+
+		mockStore.EXPECT().
+			GetCampaignByID(gomock.Any(), campaignID).
+			Return(campaign, nil)
+
+		mockStore.EXPECT().
+			CreateCampaignEmailTemplate(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params store.CreateCampaignEmailTemplateParams) (store.CampaignEmailTemplate, error) {
+				if !strings.Contains(params.HTMLBody, "Hi {{.FirstName}}") {
+					t.Errorf("expected rendered heading in HTML body, got %q", params.HTMLBody)
+				}
+				if !strings.Contains(params.HTMLBody, `href="{{.ReferralLink}}"`) {
+					t.Errorf("expected referral link in HTML body, got %q", params.HTMLBody)
+				}
+				if params.BlocksJSON == nil {
+					t.Error("expected blocks_json to be stored")
+				}
+				if params.TextBody == nil || !strings.Contains(*params.TextBody, "{{.ReferralLink}}") {
+					t.Errorf("expected text body rendered from blocks, got %v", params.TextBody)
+				}
+				return store.CampaignEmailTemplate{ID: templateID, HTMLBody: params.HTMLBody}, nil
+			})
+
+		req := CreateCampaignEmailTemplateRequest{
+			Name:    "Welcome Email",
+			Type:    "welcome",
+			Subject: "Welcome!",
+			BlocksJSON: map[string]interface{}{
+				"blocks": []interface{}{
+					map[string]interface{}{"type": "heading", "text": "Hi {{.FirstName}}"},
+					map[string]interface{}{"type": "referral_link"},
+				},
+			},
+		}
+
+		result, err := processor.CreateCampaignEmailTemplate(ctx, accountID, campaignID, req)
+
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if result.ID != templateID {
+			t.Errorf("expected template ID %v, got %v", templateID, result.ID)
+		}
+	})
+
+	t.Run("returns error for invalid blocks", func(t *testing.T) {
+		req := CreateCampaignEmailTemplateRequest{
+			Name:    "Invalid Blocks",
+			Type:    "welcome",
+			Subject: "Welcome",
+			BlocksJSON: map[string]interface{}{
+				"blocks": []interface{}{
+					map[string]interface{}{"type": "carousel"},
+				},
+			},
+		}
+
+		_, err := processor.CreateCampaignEmailTemplate(ctx, accountID, campaignID, req)
+
+		if !errors.Is(err, ErrInvalidBlocks) {
+			t.Errorf("expected ErrInvalidBlocks, got %v", err)
+		}
+	})
+
+	t.Run("returns error for reserved headers", func(t *testing.T) {
+		req := CreateCampaignEmailTemplateRequest{
+			Name:     "Reserved Header",
+			Type:     "welcome",
+			Subject:  "Welcome",
+			HTMLBody: "<h1>Welcome</h1>",
+			Headers:  map[string]string{"Reply-To": "someone@example.com"},
+		}
+
+		_, err := processor.CreateCampaignEmailTemplate(ctx, accountID, campaignID, req)
+
+		if !errors.Is(err, ErrInvalidHeaders) {
+			t.Errorf("expected ErrInvalidHeaders, got %v", err)
+		}
+	})
+
 	t.Run("returns error when campaign not found", func(t *testing.T) {
 		mockStore.EXPECT().
 			GetCampaignByID(gomock.Any(), campaignID).
@@ -422,3 +546,104 @@ func TestValidateTemplateContent(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderTemplateWithData(t *testing.T) {
+	t.Run("escapes test data", func(t *testing.T) {
+		html, err := renderTemplateWithData("<p>Hi {{.FirstName}}</p>", map[string]interface{}{
+			"FirstName": "<b>Ada</b>",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if html != "<p>Hi &lt;b&gt;Ada&lt;/b&gt;</p>" {
+			t.Errorf("expected escaped first name, got %q", html)
+		}
+	})
+
+	t.Run("uses default test data", func(t *testing.T) {
+		html, err := renderTemplateWithData("{{.FirstName}} is {{ordinal .Position}}", nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if html != "John is 1st" {
+			t.Errorf("expected default test data, got %q", html)
+		}
+	})
+}
+
+func TestGetTemplateVariantAnalytics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockCampaignEmailTemplateStore(ctrl)
+	mockEmailService := NewMockEmailService(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, mockEmailService, createTestTierService(), logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+
+	t.Run("returns rates and marks the leading variant", func(t *testing.T) {
+		campaign := store.Campaign{
+			ID:        campaignID,
+			AccountID: accountID,
+			Name:      "Test Campaign",
+			Status:    "active",
+		}
+
+		stats := []store.CampaignEmailTemplateVariantStats{
+			{TemplateID: uuid.New(), TemplateName: "Control", Enabled: true, Sent: 1000, Opened: 400, Clicked: 50, Verified: 300},
+			{TemplateID: uuid.New(), TemplateName: "Challenger", Enabled: true, Sent: 1000, Opened: 450, Clicked: 60, Verified: 400},
+		}
+
+		mockStore.EXPECT().
+			GetCampaignByID(gomock.Any(), campaignID).
+			Return(campaign, nil)
+
+		mockStore.EXPECT().
+			GetCampaignEmailTemplateVariantStats(gomock.Any(), campaignID, "verification").
+			Return(stats, nil)
+
+		result, err := processor.GetTemplateVariantAnalytics(ctx, accountID, campaignID, "verification")
+
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Metric != "verify_rate" {
+			t.Errorf("expected verify_rate metric, got %s", result.Metric)
+		}
+		if len(result.Variants) != 2 {
+			t.Fatalf("expected 2 variants, got %d", len(result.Variants))
+		}
+		if !result.Variants[0].IsControl {
+			t.Error("expected first variant to be the control")
+		}
+		if result.Variants[1].VerifyRate != 40 {
+			t.Errorf("expected verify rate 40, got %f", result.Variants[1].VerifyRate)
+		}
+		if !result.Variants[1].IsLeader || !result.Variants[1].Significant {
+			t.Error("expected challenger to be the significant leader")
+		}
+	})
+
+	t.Run("returns error for invalid template type", func(t *testing.T) {
+		_, err := processor.GetTemplateVariantAnalytics(ctx, accountID, campaignID, "invalid")
+
+		if !errors.Is(err, ErrInvalidTemplateType) {
+			t.Errorf("expected ErrInvalidTemplateType, got %v", err)
+		}
+	})
+
+	t.Run("returns unauthorized for campaign owned by another account", func(t *testing.T) {
+		mockStore.EXPECT().
+			GetCampaignByID(gomock.Any(), campaignID).
+			Return(store.Campaign{ID: campaignID, AccountID: uuid.New()}, nil)
+
+		_, err := processor.GetTemplateVariantAnalytics(ctx, accountID, campaignID, "welcome")
+
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Errorf("expected ErrUnauthorized, got %v", err)
+		}
+	})
+}
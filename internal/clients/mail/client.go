@@ -4,41 +4,106 @@ import (
 	"base-server/internal/observability"
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/resendlabs/resend-go"
 )
 
 type ResendClient struct {
-	client *resend.Client
-	logger *observability.Logger
+	client     *resend.Client
+	apiKey     string
+	httpClient *http.Client
+	logger     *observability.Logger
+}
+
+// Message is an outgoing email. When Text is empty a plain-text part is generated from HTML,
+// so every email is sent as multipart/alternative.
+type Message struct {
+	From        string
+	To          string
+	Subject     string
+	HTML        string
+	Text        string
+	ReplyTo     string
+	Headers     map[string]string
+	Attachments []Attachment
+
+	// IdempotencyKey makes the provider accept the message only once, so a send retried after
+	// a crash or timeout is not delivered twice
+	IdempotencyKey string
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Filename string
+	Content  []byte
 }
 
 func NewResendClient(apiKey string, logger *observability.Logger) (*ResendClient, error) {
-	client := resend.NewClient(apiKey)
+	// 429 responses are returned as *RateLimitError so senders can back off
+	httpClient := &http.Client{Transport: &rateLimitTransport{base: http.DefaultTransport}}
+	apiKey = strings.Trim(strings.TrimSpace(apiKey), "'")
+	client := resend.NewCustomClient(httpClient, apiKey)
 	if client == nil {
 		return nil, fmt.Errorf("failed to create Resend client")
 	}
 
 	return &ResendClient{
-		client: client,
-		logger: logger,
+		client:     client,
+		apiKey:     apiKey,
+		httpClient: httpClient,
+		logger:     logger,
 	}, nil
 }
 
 func (c *ResendClient) SendEmail(ctx context.Context, from, to, subject, htmlContent string) (string, error) {
+	return c.Send(ctx, Message{
+		From:    from,
+		To:      to,
+		Subject: subject,
+		HTML:    htmlContent,
+	})
+}
+
+// Send sends a message with its text part, headers and attachments, returning the provider message ID
+func (c *ResendClient) Send(ctx context.Context, msg Message) (string, error) {
 	ctx = observability.WithFields(ctx,
-		observability.Field{Key: "email_to", Value: to},
-		observability.Field{Key: "email_subject", Value: subject},
+		observability.Field{Key: "email_to", Value: msg.To},
+		observability.Field{Key: "email_subject", Value: msg.Subject},
 	)
 
+	text := msg.Text
+	if text == "" {
+		text = PlainText(msg.HTML)
+	}
+
 	params := &resend.SendEmailRequest{
-		From:    from,
-		To:      []string{to},
-		Subject: subject,
-		Html:    htmlContent,
+		From:    msg.From,
+		To:      []string{msg.To},
+		Subject: msg.Subject,
+		Html:    msg.HTML,
+		Text:    text,
+		ReplyTo: msg.ReplyTo,
+		Headers: msg.Headers,
+	}
+
+	for _, a := range msg.Attachments {
+		params.Attachments = append(params.Attachments, resend.Attachment{
+			Filename: a.Filename,
+			Content:  string(a.Content),
+		})
+	}
+
+	client := c.client
+	if msg.IdempotencyKey != "" {
+		// The Resend client has no per-request headers, so the key is added by a transport for this send
+		client = resend.NewCustomClient(&http.Client{
+			Transport: &idempotencyTransport{base: c.httpClient.Transport, key: msg.IdempotencyKey},
+		}, c.apiKey)
 	}
 
-	res, err := c.client.Emails.Send(params)
+	res, err := client.Emails.Send(params)
 	if err != nil {
 		c.logger.Error(ctx, "failed to send email", err)
 		return "", fmt.Errorf("failed to send email: %w", err)
@@ -47,3 +112,15 @@ func (c *ResendClient) SendEmail(ctx context.Context, from, to, subject, htmlCon
 	c.logger.Info(ctx, "email sent successfully")
 	return res.Id, nil
 }
+
+// idempotencyTransport sets the Idempotency-Key header on every request
+type idempotencyTransport struct {
+	base http.RoundTripper
+	key  string
+}
+
+func (t *idempotencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Idempotency-Key", t.key)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,36 @@
+package mail
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIdempotencyTransport(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get("Idempotency-Key")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &idempotencyTransport{base: http.DefaultTransport, key: "blast-1-2"}}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if received != "blast-1-2" {
+		t.Errorf("expected Idempotency-Key blast-1-2, got %q", received)
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		t.Error("expected the original request to be left unchanged")
+	}
+}
@@ -0,0 +1,140 @@
+package mail
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var (
+	spaceRun     = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLineRun = regexp.MustCompile(`\n{3,}`)
+)
+
+// blockElements start and end on their own line
+var blockElements = map[string]bool{
+	"address": true, "article": true, "blockquote": true, "div": true, "footer": true,
+	"header": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ol": true, "p": true, "section": true, "table": true, "tr": true, "ul": true,
+}
+
+// skippedElements have no readable text
+var skippedElements = map[string]bool{
+	"head": true, "script": true, "style": true, "title": true,
+}
+
+// openLink is an <a> element whose text is still being written
+type openLink struct {
+	href  string
+	start int
+}
+
+// PlainText converts an HTML email body into a readable plain-text alternative.
+// Links are written as "text (url)" and images as their alt text.
+func PlainText(htmlContent string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(htmlContent))
+
+	skipDepth := 0
+	var links []openLink
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		tok := z.Token()
+		name := tok.Data
+
+		switch tt {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			if skippedElements[name] && tt == html.StartTagToken {
+				skipDepth++
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+
+			switch {
+			case blockElements[name]:
+				b.WriteString("\n\n")
+			case name == "br":
+				b.WriteString("\n")
+			case name == "li":
+				b.WriteString("\n- ")
+			case name == "hr":
+				b.WriteString("\n\n----------\n\n")
+			case name == "td" || name == "th":
+				b.WriteString("\n")
+			case name == "img":
+				if alt := attr(tok, "alt"); alt != "" {
+					b.WriteString(" [" + alt + "] ")
+				}
+			case name == "a" && tt == html.StartTagToken:
+				links = append(links, openLink{href: attr(tok, "href"), start: b.Len()})
+			}
+
+		case html.EndTagToken:
+			if skippedElements[name] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				continue
+			}
+
+			switch {
+			case blockElements[name]:
+				b.WriteString("\n\n")
+			case name == "a" && len(links) > 0:
+				link := links[len(links)-1]
+				links = links[:len(links)-1]
+				text := strings.TrimSpace(spaceRun.ReplaceAllString(b.String()[link.start:], " "))
+				if linkable(link.href) && link.href != text && "mailto:"+text != link.href {
+					b.WriteString(" (" + link.href + ")")
+				}
+			}
+
+		case html.TextToken:
+			if skipDepth > 0 {
+				continue
+			}
+			b.WriteString(spaceRun.ReplaceAllString(tok.Data, " "))
+		}
+	}
+
+	return tidy(b.String())
+}
+
+// tidy trims every line and collapses runs of blank lines
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(spaceRun.ReplaceAllString(line, " "))
+	}
+
+	out := blankLineRun.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(out)
+}
+
+func attr(tok html.Token, key string) string {
+	for _, a := range tok.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// linkable reports whether an href is worth printing after the link text
+func linkable(href string) bool {
+	lower := strings.ToLower(href)
+	return strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "mailto:")
+}
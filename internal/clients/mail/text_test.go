@@ -0,0 +1,56 @@
+package mail
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name     string
+		html     string
+		expected string
+	}{
+		{
+			name:     "paragraphs and line breaks",
+			html:     "<p>Hello   Ada,</p><p>You're in.<br>See you soon.</p>",
+			expected: "Hello Ada,\n\nYou're in.\nSee you soon.",
+		},
+		{
+			name:     "links include their url",
+			html:     `<p>Share <a href="https://example.com/ref/ABC">your link</a></p>`,
+			expected: "Share your link (https://example.com/ref/ABC)",
+		},
+		{
+			name:     "bare links are not repeated",
+			html:     `<a href="https://example.com">https://example.com</a> <a href="mailto:hi@example.com">hi@example.com</a>`,
+			expected: "https://example.com hi@example.com",
+		},
+		{
+			name:     "head, style and script are dropped",
+			html:     "<html><head><title>Welcome</title><style>p{color:red}</style></head><body><script>x()</script><h1>Welcome</h1></body></html>",
+			expected: "Welcome",
+		},
+		{
+			name:     "lists, rules and images",
+			html:     `<ul><li>One</li><li>Two</li></ul><hr><img src="x.png" alt="Logo">`,
+			expected: "- One\n- Two\n\n----------\n\n[Logo]",
+		},
+		{
+			name:     "layout tables put cells on their own lines",
+			html:     "<table><tr><td>Position</td><td>#4</td></tr></table>",
+			expected: "Position\n#4",
+		},
+		{
+			name:     "entities are decoded",
+			html:     "<p>Tom &amp; Jerry &lt;3</p>",
+			expected: "Tom & Jerry <3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PlainText(tt.html)
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
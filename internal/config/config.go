@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -13,12 +14,18 @@ var ErrEmptyEnvironmentVariable = errors.New("empty environment variable")
 
 // Config holds all application configuration
 type Config struct {
-	Database   DatabaseConfig
-	Auth       AuthConfig
-	Services   ServicesConfig
-	Kafka      KafkaConfig
-	WorkerPool WorkerPoolConfig
-	Server     ServerConfig
+	Database       DatabaseConfig
+	Auth           AuthConfig
+	Services       ServicesConfig
+	Kafka          KafkaConfig
+	WorkerPool     WorkerPoolConfig
+	Server         ServerConfig
+	SendRate       SendRateConfig
+	Blast          BlastConfig
+	PositionUpdate PositionUpdateConfig
+	Segment        SegmentConfig
+	Webhook        WebhookConfig
+	Outbound       OutboundConfig
 }
 
 // DatabaseConfig holds database connection settings
@@ -42,6 +49,7 @@ type ServicesConfig struct {
 	StripeSecretKey     string
 	StripeWebhookSecret string
 	ResendAPIKey        string
+	ResendWebhookSecret string // Signing secret of the Resend webhook reporting opens, clicks and bounces (optional)
 	DefaultEmailSender  string
 	GoogleAIAPIKey      string
 	OpenAIAPIKey        string
@@ -65,6 +73,51 @@ type WorkerPoolConfig struct {
 	IntegrationWorkers int // Number of workers for integration event processing (Zapier, Slack, etc.)
 }
 
+// SendRateConfig holds the email send rates shared by all blast workers
+type SendRateConfig struct {
+	ProviderPerSecond int // Emails per second across all accounts, matching the provider's rate limit
+	AccountPerSecond  int // Emails per second for one account across its concurrent blasts
+}
+
+// BlastConfig holds email blast recovery settings
+type BlastConfig struct {
+	StallTimeoutMinutes int // Minutes without progress before a processing or sending blast is marked failed
+}
+
+// PositionUpdateConfig holds position improvement notification settings
+type PositionUpdateConfig struct {
+	DigestHours int // Minimum hours between two position update emails to the same user
+	MinSpots    int // Minimum number of spots a user must move up before they are notified
+}
+
+// SegmentConfig holds scheduled segment count settings
+type SegmentConfig struct {
+	RefreshIntervalMinutes int // Minutes between two scheduled recounts of the same segment
+	SyncIntervalMinutes    int // Minutes between two membership syncs of a segment with sync enabled
+}
+
+// WebhookConfig holds webhook endpoint health settings
+type WebhookConfig struct {
+	CircuitFailureThreshold     int // Consecutive failed attempts before an endpoint's circuit opens
+	CircuitProbeIntervalMinutes int // Minutes between two probe attempts while an endpoint's circuit is open
+	AutoDisableDays             int // Days of uninterrupted failure before an endpoint is disabled
+	DeliveryTimeoutSeconds      int // Seconds before a delivery request times out
+	MaxInFlightPerEndpoint      int // Deliveries sent to one endpoint at the same time
+	QueueSizePerEndpoint        int // Deliveries waiting for one endpoint before new ones are left to the retry worker
+
+	// Delivery log retention
+	LogRetentionDays int      // Days delivery logs are kept for tiers without a retention limit
+	RedactAfterDays  int      // Days before the redacted fields are removed from delivery payloads, 0 to never redact
+	RedactFields     []string // Dot-separated payload paths redacted, e.g. user.email
+}
+
+// OutboundConfig holds the network policy for requests to user-supplied URLs (webhooks, Zapier)
+type OutboundConfig struct {
+	AllowedHosts []string // Host names, IPs and CIDR ranges reachable even though they are not public
+	RequireHTTPS bool     // Reject plain HTTP URLs, on by default in production
+	MaxRedirects int      // Redirects followed per request
+}
+
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port int
@@ -122,6 +175,7 @@ func Load() (*Config, error) {
 	if cfg.Services.ResendAPIKey, err = requireEnv("RESEND_API_KEY"); err != nil {
 		return nil, err
 	}
+	cfg.Services.ResendWebhookSecret = getEnvWithDefault("RESEND_WEBHOOK_SECRET", "")
 	if cfg.Services.DefaultEmailSender, err = requireEnv("DEFAULT_EMAIL_SENDER_ADDRESS"); err != nil {
 		return nil, err
 	}
@@ -177,6 +231,115 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse INTEGRATION_WORKERS: %w", err)
 	}
 
+	// Send rate configuration
+	providerSendRate := getEnvWithDefault("EMAIL_PROVIDER_SEND_RATE", "10")
+	cfg.SendRate.ProviderPerSecond, err = strconv.Atoi(providerSendRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EMAIL_PROVIDER_SEND_RATE: %w", err)
+	}
+
+	accountSendRate := getEnvWithDefault("EMAIL_ACCOUNT_SEND_RATE", "5")
+	cfg.SendRate.AccountPerSecond, err = strconv.Atoi(accountSendRate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EMAIL_ACCOUNT_SEND_RATE: %w", err)
+	}
+
+	blastStallTimeout := getEnvWithDefault("BLAST_STALL_TIMEOUT_MINUTES", "30")
+	cfg.Blast.StallTimeoutMinutes, err = strconv.Atoi(blastStallTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BLAST_STALL_TIMEOUT_MINUTES: %w", err)
+	}
+
+	positionUpdateDigestHours := getEnvWithDefault("POSITION_UPDATE_DIGEST_HOURS", "24")
+	cfg.PositionUpdate.DigestHours, err = strconv.Atoi(positionUpdateDigestHours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse POSITION_UPDATE_DIGEST_HOURS: %w", err)
+	}
+
+	positionUpdateMinSpots := getEnvWithDefault("POSITION_UPDATE_MIN_SPOTS", "5")
+	cfg.PositionUpdate.MinSpots, err = strconv.Atoi(positionUpdateMinSpots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse POSITION_UPDATE_MIN_SPOTS: %w", err)
+	}
+
+	segmentRefreshInterval := getEnvWithDefault("SEGMENT_REFRESH_INTERVAL_MINUTES", "60")
+	cfg.Segment.RefreshIntervalMinutes, err = strconv.Atoi(segmentRefreshInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SEGMENT_REFRESH_INTERVAL_MINUTES: %w", err)
+	}
+
+	segmentSyncInterval := getEnvWithDefault("SEGMENT_SYNC_INTERVAL_MINUTES", "5")
+	cfg.Segment.SyncIntervalMinutes, err = strconv.Atoi(segmentSyncInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SEGMENT_SYNC_INTERVAL_MINUTES: %w", err)
+	}
+
+	webhookCircuitFailureThreshold := getEnvWithDefault("WEBHOOK_CIRCUIT_FAILURE_THRESHOLD", "10")
+	cfg.Webhook.CircuitFailureThreshold, err = strconv.Atoi(webhookCircuitFailureThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_CIRCUIT_FAILURE_THRESHOLD: %w", err)
+	}
+
+	webhookCircuitProbeInterval := getEnvWithDefault("WEBHOOK_CIRCUIT_PROBE_INTERVAL_MINUTES", "5")
+	cfg.Webhook.CircuitProbeIntervalMinutes, err = strconv.Atoi(webhookCircuitProbeInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_CIRCUIT_PROBE_INTERVAL_MINUTES: %w", err)
+	}
+
+	webhookAutoDisableDays := getEnvWithDefault("WEBHOOK_AUTO_DISABLE_DAYS", "3")
+	cfg.Webhook.AutoDisableDays, err = strconv.Atoi(webhookAutoDisableDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_AUTO_DISABLE_DAYS: %w", err)
+	}
+
+	webhookDeliveryTimeout := getEnvWithDefault("WEBHOOK_DELIVERY_TIMEOUT_SECONDS", "10")
+	cfg.Webhook.DeliveryTimeoutSeconds, err = strconv.Atoi(webhookDeliveryTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_DELIVERY_TIMEOUT_SECONDS: %w", err)
+	}
+
+	webhookMaxInFlight := getEnvWithDefault("WEBHOOK_MAX_IN_FLIGHT_PER_ENDPOINT", "4")
+	cfg.Webhook.MaxInFlightPerEndpoint, err = strconv.Atoi(webhookMaxInFlight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_MAX_IN_FLIGHT_PER_ENDPOINT: %w", err)
+	}
+
+	webhookQueueSize := getEnvWithDefault("WEBHOOK_QUEUE_SIZE_PER_ENDPOINT", "100")
+	cfg.Webhook.QueueSizePerEndpoint, err = strconv.Atoi(webhookQueueSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_QUEUE_SIZE_PER_ENDPOINT: %w", err)
+	}
+
+	webhookLogRetentionDays := getEnvWithDefault("WEBHOOK_LOG_RETENTION_DAYS", "30")
+	cfg.Webhook.LogRetentionDays, err = strconv.Atoi(webhookLogRetentionDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_LOG_RETENTION_DAYS: %w", err)
+	}
+
+	webhookRedactAfterDays := getEnvWithDefault("WEBHOOK_REDACT_AFTER_DAYS", "7")
+	cfg.Webhook.RedactAfterDays, err = strconv.Atoi(webhookRedactAfterDays)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse WEBHOOK_REDACT_AFTER_DAYS: %w", err)
+	}
+
+	cfg.Webhook.RedactFields = splitList(getEnvWithDefault("WEBHOOK_REDACT_FIELDS",
+		"user.email,user.first_name,user.last_name,user.verification_token,email.to"))
+
+	// Outbound network policy
+	cfg.Outbound.AllowedHosts = splitList(getEnvWithDefault("OUTBOUND_ALLOWED_HOSTS", ""))
+
+	outboundRequireHTTPS := getEnvWithDefault("OUTBOUND_REQUIRE_HTTPS", strconv.FormatBool(goEnv == "production"))
+	cfg.Outbound.RequireHTTPS, err = strconv.ParseBool(outboundRequireHTTPS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OUTBOUND_REQUIRE_HTTPS: %w", err)
+	}
+
+	outboundMaxRedirects := getEnvWithDefault("OUTBOUND_MAX_REDIRECTS", "3")
+	cfg.Outbound.MaxRedirects, err = strconv.Atoi(outboundMaxRedirects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OUTBOUND_MAX_REDIRECTS: %w", err)
+	}
+
 	// Server configuration
 	serverPort, err := requireEnv("SERVER_PORT")
 	if err != nil {
@@ -213,3 +376,14 @@ func getEnvWithDefault(key, defaultValue string) string {
 	}
 	return value
 }
+
+// splitList splits a comma-separated value into its non-empty, trimmed items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
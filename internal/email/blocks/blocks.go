@@ -0,0 +1,261 @@
+// Package blocks renders the visual email builder's block schema (blocks_json) into
+// email-safe HTML with inlined styles and a plain-text alternative.
+//
+// The rendered output is itself a Go template: referral-link and position-badge blocks
+// emit {{.ReferralLink}} and {{.Position}}, and text may contain template variables
+// such as {{.FirstName}}, which are filled in when the email is sent.
+package blocks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Block types supported by the renderer
+const (
+	TypeHeading       = "heading"
+	TypeText          = "text"
+	TypeButton        = "button"
+	TypeImage         = "image"
+	TypeDivider       = "divider"
+	TypeColumns       = "columns"
+	TypeReferralLink  = "referral_link"
+	TypePositionBadge = "position_badge"
+)
+
+// maxColumns is the maximum number of columns in a columns block
+const maxColumns = 4
+
+var (
+	ErrNoBlocks         = errors.New("email design has no blocks")
+	ErrUnknownBlockType = errors.New("unknown block type")
+	ErrInvalidBlock     = errors.New("invalid block")
+	// ErrInvalidDesign wraps every problem RenderJSONB finds with a saved design
+	ErrInvalidDesign = errors.New("invalid email blocks")
+)
+
+var colorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Document is the top-level blocks_json structure
+type Document struct {
+	Settings Settings `json:"settings"`
+	Blocks   []Block  `json:"blocks"`
+}
+
+// Settings holds document-wide styling
+type Settings struct {
+	BackgroundColor string `json:"background_color,omitempty"`
+	ContentColor    string `json:"content_color,omitempty"`
+	TextColor       string `json:"text_color,omitempty"`
+	LinkColor       string `json:"link_color,omitempty"`
+	FontFamily      string `json:"font_family,omitempty"`
+}
+
+// Block is a single element of an email design. Which fields apply depends on Type.
+type Block struct {
+	Type string `json:"type"`
+
+	// heading, text, button, referral_link, position_badge
+	Text string `json:"text,omitempty"`
+	// heading level 1-3
+	Level int `json:"level,omitempty"`
+	// left, center or right
+	Align string `json:"align,omitempty"`
+	Color string `json:"color,omitempty"`
+
+	// button and image link target
+	URL             string `json:"url,omitempty"`
+	BackgroundColor string `json:"background_color,omitempty"`
+
+	// image
+	Src   string `json:"src,omitempty"`
+	Alt   string `json:"alt,omitempty"`
+	Width int    `json:"width,omitempty"`
+
+	// referral_link and position_badge label shown before the value
+	Label string `json:"label,omitempty"`
+
+	// columns
+	Columns []Column `json:"columns,omitempty"`
+}
+
+// Column is one column of a columns block
+type Column struct {
+	Blocks []Block `json:"blocks"`
+}
+
+// Rendered is the output of rendering a document
+type Rendered struct {
+	HTML string
+	Text string
+}
+
+// Parse decodes blocks_json as stored in the database into a Document
+func Parse(data map[string]interface{}) (Document, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Document{}, fmt.Errorf("failed to marshal blocks: %w", err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return Document{}, fmt.Errorf("%w: %v", ErrInvalidBlock, err)
+	}
+
+	return doc, nil
+}
+
+// RenderMap parses and renders blocks_json in one step
+func RenderMap(data map[string]interface{}) (Rendered, error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return Rendered{}, err
+	}
+	return Render(doc)
+}
+
+// RenderJSONB renders a template's blocks_json design to HTML and plain-text template bodies
+func RenderJSONB(data map[string]interface{}) (Rendered, error) {
+	return renderJSONB(data, true)
+}
+
+// RenderJSONBWithoutWaitlistData is RenderJSONB for emails sent without the recipient's waitlist
+// data, such as blasts. Referral link and position badge blocks are rejected.
+func RenderJSONBWithoutWaitlistData(data map[string]interface{}) (Rendered, error) {
+	return renderJSONB(data, false)
+}
+
+func renderJSONB(data map[string]interface{}, allowWaitlistData bool) (Rendered, error) {
+	doc, err := Parse(data)
+	if err != nil {
+		return Rendered{}, fmt.Errorf("%w: %w", ErrInvalidDesign, err)
+	}
+	if !allowWaitlistData && doc.UsesWaitlistData() {
+		return Rendered{}, fmt.Errorf("%w: referral link and position badge blocks need waitlist data", ErrInvalidDesign)
+	}
+
+	rendered, err := Render(doc)
+	if err != nil {
+		return Rendered{}, fmt.Errorf("%w: %w", ErrInvalidDesign, err)
+	}
+	return rendered, nil
+}
+
+// UsesWaitlistData reports whether the document has referral link or position badge blocks,
+// which show the recipient's waitlist data and render empty in emails sent without it
+func (d Document) UsesWaitlistData() bool {
+	return usesWaitlistData(d.Blocks)
+}
+
+func usesWaitlistData(blocks []Block) bool {
+	for _, b := range blocks {
+		switch normalizeType(b.Type) {
+		case TypeReferralLink, TypePositionBadge:
+			return true
+		}
+		for _, col := range b.Columns {
+			if usesWaitlistData(col.Blocks) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Validate checks every block of the document, returning the first problem found
+func (d Document) Validate() error {
+	if len(d.Blocks) == 0 {
+		return ErrNoBlocks
+	}
+	return validateBlocks(d.Blocks, "blocks", true)
+}
+
+func validateBlocks(blocks []Block, path string, allowColumns bool) error {
+	for i, b := range blocks {
+		blockPath := fmt.Sprintf("%s[%d]", path, i)
+		if err := validateBlock(b, blockPath, allowColumns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateBlock(b Block, path string, allowColumns bool) error {
+	switch normalizeType(b.Type) {
+	case TypeHeading:
+		if b.Text == "" {
+			return fmt.Errorf("%w: %s heading needs text", ErrInvalidBlock, path)
+		}
+		if b.Level < 0 || b.Level > 3 {
+			return fmt.Errorf("%w: %s heading level must be 1-3", ErrInvalidBlock, path)
+		}
+	case TypeText:
+		if b.Text == "" {
+			return fmt.Errorf("%w: %s text block needs text", ErrInvalidBlock, path)
+		}
+	case TypeButton:
+		if b.Text == "" || b.URL == "" {
+			return fmt.Errorf("%w: %s button needs text and url", ErrInvalidBlock, path)
+		}
+		if !isSafeURL(b.URL) {
+			return fmt.Errorf("%w: %s button url must be http, https or mailto", ErrInvalidBlock, path)
+		}
+	case TypeImage:
+		if b.Src == "" || !isSafeURL(b.Src) {
+			return fmt.Errorf("%w: %s image needs an http or https src", ErrInvalidBlock, path)
+		}
+		if b.URL != "" && !isSafeURL(b.URL) {
+			return fmt.Errorf("%w: %s image url must be http, https or mailto", ErrInvalidBlock, path)
+		}
+	case TypeDivider, TypeReferralLink, TypePositionBadge:
+	case TypeColumns:
+		if !allowColumns {
+			return fmt.Errorf("%w: %s columns cannot be nested", ErrInvalidBlock, path)
+		}
+		if len(b.Columns) == 0 || len(b.Columns) > maxColumns {
+			return fmt.Errorf("%w: %s columns block needs 1-%d columns", ErrInvalidBlock, path, maxColumns)
+		}
+		for i, col := range b.Columns {
+			if err := validateBlocks(col.Blocks, fmt.Sprintf("%s.columns[%d].blocks", path, i), false); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s has type %q", ErrUnknownBlockType, path, b.Type)
+	}
+	return nil
+}
+
+// normalizeType accepts both referral-link and referral_link style type names
+func normalizeType(t string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(t)), "-", "_")
+}
+
+// isSafeURL allows http(s) and mailto links as well as template expressions like {{.ReferralLink}}
+func isSafeURL(u string) bool {
+	lower := strings.ToLower(strings.TrimSpace(u))
+	return strings.HasPrefix(lower, "https://") ||
+		strings.HasPrefix(lower, "http://") ||
+		strings.HasPrefix(lower, "mailto:") ||
+		strings.HasPrefix(lower, "{{")
+}
+
+// colorOr returns c if it is a valid hex color, otherwise the fallback
+func colorOr(c, fallback string) string {
+	if colorPattern.MatchString(c) {
+		return c
+	}
+	return fallback
+}
+
+// alignOr returns a valid alignment, otherwise the fallback
+func alignOr(a, fallback string) string {
+	switch a {
+	case "left", "center", "right":
+		return a
+	}
+	return fallback
+}
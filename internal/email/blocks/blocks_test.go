@@ -0,0 +1,142 @@
+package blocks
+
+import (
+	"base-server/internal/email/templating"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRenderGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatalf("failed to list testdata: %v", err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("no golden inputs found")
+	}
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".json")
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatalf("failed to read input: %v", err)
+			}
+
+			var data map[string]interface{}
+			if err := json.Unmarshal(raw, &data); err != nil {
+				t.Fatalf("failed to decode input: %v", err)
+			}
+
+			rendered, err := RenderMap(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// The rendered design is saved as a template body, so it must pass template validation
+			if err := templating.Validate(rendered.HTML); err != nil {
+				t.Errorf("rendered HTML is not a valid template: %v", err)
+			}
+
+			compareGolden(t, filepath.Join("testdata", name+".golden.html"), rendered.HTML)
+			compareGolden(t, filepath.Join("testdata", name+".golden.txt"), rendered.Text)
+		})
+	}
+}
+
+func compareGolden(t *testing.T, path, got string) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s (run with -update to regenerate)\ngot:\n%s", path, got)
+	}
+}
+
+func TestRenderValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     Document
+		wantErr error
+	}{
+		{"no blocks", Document{}, ErrNoBlocks},
+		{"unknown type", Document{Blocks: []Block{{Type: "video"}}}, ErrUnknownBlockType},
+		{"heading without text", Document{Blocks: []Block{{Type: TypeHeading}}}, ErrInvalidBlock},
+		{"heading level out of range", Document{Blocks: []Block{{Type: TypeHeading, Text: "Hi", Level: 4}}}, ErrInvalidBlock},
+		{"button without url", Document{Blocks: []Block{{Type: TypeButton, Text: "Go"}}}, ErrInvalidBlock},
+		{"javascript button url", Document{Blocks: []Block{{Type: TypeButton, Text: "Go", URL: "javascript:alert(1)"}}}, ErrInvalidBlock},
+		{"image without src", Document{Blocks: []Block{{Type: TypeImage}}}, ErrInvalidBlock},
+		{"empty columns", Document{Blocks: []Block{{Type: TypeColumns}}}, ErrInvalidBlock},
+		{"nested columns", Document{Blocks: []Block{{Type: TypeColumns, Columns: []Column{{Blocks: []Block{{Type: TypeColumns, Columns: []Column{{}}}}}}}}}, ErrInvalidBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Render(tt.doc)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEscapeTextKeepsTemplateActions(t *testing.T) {
+	got := escapeText(`Hi {{.FirstName}} <b>&</b> {{if eq .Position 1}}"first"{{end}}`)
+	want := `Hi {{.FirstName}} &lt;b&gt;&amp;&lt;/b&gt; {{if eq .Position 1}}&#34;first&#34;{{end}}`
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestUsesWaitlistData(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  Document
+		want bool
+	}{
+		{"text only", Document{Blocks: []Block{{Type: TypeHeading, Text: "Hi"}, {Type: TypeText, Text: "News"}}}, false},
+		{"referral link", Document{Blocks: []Block{{Type: TypeReferralLink}}}, true},
+		{"position badge in column", Document{Blocks: []Block{{Type: TypeColumns, Columns: []Column{{Blocks: []Block{{Type: TypePositionBadge}}}}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.doc.UsesWaitlistData(); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRenderJSONB(t *testing.T) {
+	referral := map[string]interface{}{
+		"blocks": []interface{}{map[string]interface{}{"type": TypeReferralLink}},
+	}
+
+	if _, err := RenderJSONB(referral); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := RenderJSONBWithoutWaitlistData(referral); !errors.Is(err, ErrInvalidDesign) {
+		t.Errorf("expected ErrInvalidDesign for waitlist blocks, got %v", err)
+	}
+	if _, err := RenderJSONB(map[string]interface{}{}); !errors.Is(err, ErrInvalidDesign) || !errors.Is(err, ErrNoBlocks) {
+		t.Errorf("expected ErrInvalidDesign wrapping ErrNoBlocks, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package blocks
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Default styles used when the document doesn't set its own
+const (
+	defaultBackgroundColor = "#f4f4f5"
+	defaultContentColor    = "#ffffff"
+	defaultTextColor       = "#18181b"
+	defaultLinkColor       = "#2563eb"
+	defaultButtonTextColor = "#ffffff"
+	defaultDividerColor    = "#e4e4e7"
+	defaultFontFamily      = "Helvetica, Arial, sans-serif"
+
+	contentWidth = 600
+)
+
+var (
+	fontFamilyPattern = regexp.MustCompile(`^[A-Za-z0-9 ,\-]+$`)
+	templateAction    = regexp.MustCompile(`{{.*?}}`)
+)
+
+var headingSizes = map[int]int{1: 28, 2: 22, 3: 18}
+
+// Render validates a document and renders it to HTML and plain text
+func Render(doc Document) (Rendered, error) {
+	if err := doc.Validate(); err != nil {
+		return Rendered{}, err
+	}
+
+	r := renderer{settings: resolveSettings(doc.Settings)}
+
+	return Rendered{
+		HTML: r.html(doc.Blocks),
+		Text: r.text(doc.Blocks),
+	}, nil
+}
+
+type renderer struct {
+	settings Settings
+}
+
+func resolveSettings(s Settings) Settings {
+	font := defaultFontFamily
+	if s.FontFamily != "" && fontFamilyPattern.MatchString(s.FontFamily) {
+		font = s.FontFamily
+	}
+
+	return Settings{
+		BackgroundColor: colorOr(s.BackgroundColor, defaultBackgroundColor),
+		ContentColor:    colorOr(s.ContentColor, defaultContentColor),
+		TextColor:       colorOr(s.TextColor, defaultTextColor),
+		LinkColor:       colorOr(s.LinkColor, defaultLinkColor),
+		FontFamily:      font,
+	}
+}
+
+// html renders the full email document using nested tables so it displays consistently across clients
+func (r renderer) html(blocks []Block) string {
+	var b strings.Builder
+
+	b.WriteString("<!DOCTYPE html>\n")
+	b.WriteString("<html>\n<head>\n")
+	b.WriteString(`<meta charset="utf-8">` + "\n")
+	b.WriteString(`<meta name="viewport" content="width=device-width, initial-scale=1.0">` + "\n")
+	b.WriteString("</head>\n")
+	fmt.Fprintf(&b, `<body style="margin:0;padding:0;background-color:%s;">`+"\n", r.settings.BackgroundColor)
+	fmt.Fprintf(&b, `<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0" style="background-color:%s;">`+"\n", r.settings.BackgroundColor)
+	b.WriteString("<tr>\n")
+	b.WriteString(`<td align="center" style="padding:24px 12px;">` + "\n")
+	fmt.Fprintf(&b, `<table role="presentation" width="%d" cellpadding="0" cellspacing="0" border="0" style="width:100%%;max-width:%dpx;background-color:%s;">`+"\n", contentWidth, contentWidth, r.settings.ContentColor)
+
+	for _, block := range blocks {
+		b.WriteString("<tr>\n")
+		b.WriteString(`<td style="padding:12px 24px;">`)
+		b.WriteString(r.blockHTML(block))
+		b.WriteString("</td>\n")
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("</table>\n")
+	b.WriteString("</td>\n")
+	b.WriteString("</tr>\n")
+	b.WriteString("</table>\n")
+	b.WriteString("</body>\n</html>\n")
+
+	return b.String()
+}
+
+func (r renderer) fontStyle() string {
+	return "font-family:" + r.settings.FontFamily + ";"
+}
+
+func (r renderer) blockHTML(block Block) string {
+	switch normalizeType(block.Type) {
+	case TypeHeading:
+		level := block.Level
+		if level == 0 {
+			level = 1
+		}
+		return fmt.Sprintf(`<h%d style="margin:0;%sfont-size:%dpx;line-height:1.3;font-weight:bold;color:%s;text-align:%s;">%s</h%d>`,
+			level, r.fontStyle(), headingSizes[level], colorOr(block.Color, r.settings.TextColor), alignOr(block.Align, "left"), escapeText(block.Text), level)
+
+	case TypeText:
+		text := strings.ReplaceAll(escapeText(block.Text), "\n", "<br>")
+		return fmt.Sprintf(`<p style="margin:0;%sfont-size:16px;line-height:1.5;color:%s;text-align:%s;">%s</p>`,
+			r.fontStyle(), colorOr(block.Color, r.settings.TextColor), alignOr(block.Align, "left"), text)
+
+	case TypeButton:
+		background := colorOr(block.BackgroundColor, r.settings.LinkColor)
+		return fmt.Sprintf(`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"><tr><td align="%s">`+
+			`<table role="presentation" cellpadding="0" cellspacing="0" border="0"><tr><td style="border-radius:6px;background-color:%s;">`+
+			`<a href="%s" target="_blank" style="display:inline-block;padding:12px 24px;%sfont-size:16px;font-weight:bold;color:%s;text-decoration:none;border-radius:6px;">%s</a>`+
+			`</td></tr></table></td></tr></table>`,
+			alignOr(block.Align, "center"), background, escapeText(block.URL), r.fontStyle(), colorOr(block.Color, defaultButtonTextColor), escapeText(block.Text))
+
+	case TypeImage:
+		width := block.Width
+		if width <= 0 || width > contentWidth {
+			width = contentWidth - 48
+		}
+		img := fmt.Sprintf(`<img src="%s" alt="%s" width="%d" style="display:block;width:100%%;max-width:%dpx;height:auto;border:0;outline:none;text-decoration:none;">`,
+			escapeText(block.Src), escapeText(block.Alt), width, width)
+		if block.URL != "" {
+			img = fmt.Sprintf(`<a href="%s" target="_blank">%s</a>`, escapeText(block.URL), img)
+		}
+		return fmt.Sprintf(`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"><tr><td align="%s">%s</td></tr></table>`,
+			alignOr(block.Align, "center"), img)
+
+	case TypeDivider:
+		return fmt.Sprintf(`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"><tr><td style="border-top:1px solid %s;font-size:0;line-height:0;">&nbsp;</td></tr></table>`,
+			colorOr(block.Color, defaultDividerColor))
+
+	case TypeColumns:
+		return r.columnsHTML(block)
+
+	case TypeReferralLink:
+		label := block.Label
+		if label == "" {
+			label = "Share your referral link"
+		}
+		return fmt.Sprintf(`<p style="margin:0;%sfont-size:16px;line-height:1.5;color:%s;text-align:%s;">%s<br><a href="{{.ReferralLink}}" target="_blank" style="color:%s;word-break:break-all;">{{.ReferralLink}}</a></p>`,
+			r.fontStyle(), colorOr(block.Color, r.settings.TextColor), alignOr(block.Align, "center"), escapeText(label), r.settings.LinkColor)
+
+	case TypePositionBadge:
+		label := block.Label
+		if label == "" {
+			label = "Your position"
+		}
+		return fmt.Sprintf(`<table role="presentation" width="100%%" cellpadding="0" cellspacing="0" border="0"><tr><td align="%s">`+
+			`<table role="presentation" cellpadding="0" cellspacing="0" border="0"><tr><td style="padding:12px 20px;border-radius:999px;background-color:%s;%sfont-size:16px;font-weight:bold;color:%s;text-align:center;">`+
+			`%s: #{{.Position}}</td></tr></table></td></tr></table>`,
+			alignOr(block.Align, "center"), colorOr(block.BackgroundColor, r.settings.LinkColor), r.fontStyle(), colorOr(block.Color, defaultButtonTextColor), escapeText(label))
+	}
+
+	return ""
+}
+
+func (r renderer) columnsHTML(block Block) string {
+	var b strings.Builder
+	width := 100 / len(block.Columns)
+
+	b.WriteString(`<table role="presentation" width="100%" cellpadding="0" cellspacing="0" border="0"><tr>`)
+	for _, col := range block.Columns {
+		fmt.Fprintf(&b, `<td width="%d%%" valign="top" style="padding:0 6px;">`, width)
+		for i, child := range col.Blocks {
+			if i > 0 {
+				b.WriteString(`<div style="height:12px;line-height:12px;font-size:0;">&nbsp;</div>`)
+			}
+			b.WriteString(r.blockHTML(child))
+		}
+		b.WriteString(`</td>`)
+	}
+	b.WriteString(`</tr></table>`)
+
+	return b.String()
+}
+
+// text renders the plain-text alternative
+func (r renderer) text(blocks []Block) string {
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		if t := r.blockText(block); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+func (r renderer) blockText(block Block) string {
+	switch normalizeType(block.Type) {
+	case TypeHeading:
+		return mapLiterals(block.Text, strings.ToUpper)
+	case TypeText:
+		return block.Text
+	case TypeButton:
+		return fmt.Sprintf("%s: %s", block.Text, block.URL)
+	case TypeImage:
+		if block.Alt == "" {
+			return ""
+		}
+		if block.URL != "" {
+			return fmt.Sprintf("[%s] %s", block.Alt, block.URL)
+		}
+		return fmt.Sprintf("[%s]", block.Alt)
+	case TypeDivider:
+		return "----------"
+	case TypeColumns:
+		var parts []string
+		for _, col := range block.Columns {
+			for _, child := range col.Blocks {
+				if t := r.blockText(child); t != "" {
+					parts = append(parts, t)
+				}
+			}
+		}
+		return strings.Join(parts, "\n\n")
+	case TypeReferralLink:
+		label := block.Label
+		if label == "" {
+			label = "Share your referral link"
+		}
+		return label + ": {{.ReferralLink}}"
+	case TypePositionBadge:
+		label := block.Label
+		if label == "" {
+			label = "Your position"
+		}
+		return label + ": #{{.Position}}"
+	}
+	return ""
+}
+
+// escapeText HTML-escapes text and attribute values while leaving template actions like {{.FirstName}} intact
+func escapeText(s string) string {
+	return mapLiterals(s, html.EscapeString)
+}
+
+// mapLiterals applies fn to the parts of s outside template actions
+func mapLiterals(s string, fn func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range templateAction.FindAllStringIndex(s, -1) {
+		b.WriteString(fn(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(fn(s[last:]))
+	return b.String()
+}
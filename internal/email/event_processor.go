@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
+	"time"
 
 	"base-server/internal/observability"
 	"base-server/internal/store"
@@ -53,6 +55,9 @@ func (p *EmailEventProcessor) Process(ctx context.Context, event workers.EventMe
 	// Route to appropriate handler based on event type
 	switch event.Type {
 	case "user.created":
+		if err := p.enrollInSequences(ctx, event); err != nil {
+			return err
+		}
 		return p.handleUserCreated(ctx, event)
 	case "user.verified":
 		return p.handleUserVerified(ctx, event)
@@ -63,6 +68,46 @@ func (p *EmailEventProcessor) Process(ctx context.Context, event workers.EventMe
 	}
 }
 
+// enrollInSequences enrolls a new waitlist signup in the campaign's active drip email sequences.
+// Enrollment is idempotent, so redelivered events don't restart a sequence.
+func (p *EmailEventProcessor) enrollInSequences(ctx context.Context, event workers.EventMessage) error {
+	var eventData struct {
+		CampaignID string                 `json:"campaign_id"`
+		User       map[string]interface{} `json:"user"`
+	}
+
+	dataBytes, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event data: %w", err)
+	}
+
+	if err := json.Unmarshal(dataBytes, &eventData); err != nil {
+		return fmt.Errorf("failed to unmarshal event data: %w", err)
+	}
+
+	campaignID, err := uuid.Parse(eventData.CampaignID)
+	if err != nil {
+		return fmt.Errorf("invalid campaign_id: %w", err)
+	}
+
+	userID := parseUserID(eventData.User)
+	if userID == nil {
+		p.logger.Info(ctx, "No user id in event data, skipping email sequence enrollment")
+		return nil
+	}
+
+	enrolled, err := p.store.EnrollUserInEmailSequences(ctx, campaignID, *userID)
+	if err != nil {
+		return fmt.Errorf("failed to enroll user in email sequences: %w", err)
+	}
+
+	if enrolled > 0 {
+		p.logger.Info(ctx, fmt.Sprintf("Enrolled user in %d email sequences", enrolled))
+	}
+
+	return nil
+}
+
 // handleUserCreated sends verification or welcome email for new waitlist signups.
 // If verification is enabled: sends verification email
 // If verification is disabled but send_welcome_email is enabled: sends welcome email immediately
@@ -114,6 +159,8 @@ func (p *EmailEventProcessor) handleUserCreated(ctx context.Context, event worke
 		return fmt.Errorf("missing or invalid email in event data")
 	}
 
+	userID := parseUserID(eventData.User)
+
 	// Extract optional fields
 	firstName := ""
 	if fn, ok := eventData.User["first_name"]; ok && fn != nil {
@@ -130,6 +177,8 @@ func (p *EmailEventProcessor) handleUserCreated(ctx context.Context, event worke
 		referralLink = rl
 	}
 
+	signedUpAt := userSignedUpAt(eventData.User)
+
 	referralCount := 0
 	if rc, ok := eventData.User["referral_count"].(float64); ok {
 		referralCount = int(rc)
@@ -157,7 +206,7 @@ func (p *EmailEventProcessor) handleUserCreated(ctx context.Context, event worke
 			email, campaignName))
 
 		// Try to use custom template from database
-		err = p.sendEmailWithCustomTemplate(ctx, campaignID, "verification", email, TemplateData{
+		err = p.sendEmailWithCustomTemplate(ctx, campaign, userID, "verification", email, TemplateData{
 			FirstName:        firstName,
 			Email:            email,
 			CampaignName:     campaignName,
@@ -165,6 +214,7 @@ func (p *EmailEventProcessor) handleUserCreated(ctx context.Context, event worke
 			ReferralLink:     referralLink,
 			Position:         position,
 			ReferralCount:    referralCount,
+			SignedUpAt:       signedUpAt,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to send verification email: %w", err)
@@ -177,13 +227,14 @@ func (p *EmailEventProcessor) handleUserCreated(ctx context.Context, event worke
 			email, campaignName))
 
 		// Try to use custom template from database
-		err = p.sendEmailWithCustomTemplate(ctx, campaignID, "welcome", email, TemplateData{
+		err = p.sendEmailWithCustomTemplate(ctx, campaign, userID, "welcome", email, TemplateData{
 			FirstName:     firstName,
 			Email:         email,
 			CampaignName:  campaignName,
 			ReferralLink:  referralLink,
 			Position:      position,
 			ReferralCount: referralCount,
+			SignedUpAt:    signedUpAt,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to send welcome email: %w", err)
@@ -230,6 +281,8 @@ func (p *EmailEventProcessor) handleUserVerified(ctx context.Context, event work
 		return fmt.Errorf("missing or invalid email in event data")
 	}
 
+	userID := parseUserID(eventData.User)
+
 	firstName := ""
 	if fn, ok := eventData.User["first_name"]; ok && fn != nil {
 		firstName = fn.(string)
@@ -250,6 +303,8 @@ func (p *EmailEventProcessor) handleUserVerified(ctx context.Context, event work
 		referralLink = rl
 	}
 
+	signedUpAt := userSignedUpAt(eventData.User)
+
 	campaignName := campaign.Name
 
 	// Send welcome email
@@ -257,13 +312,14 @@ func (p *EmailEventProcessor) handleUserVerified(ctx context.Context, event work
 		email, campaignName))
 
 	// Try to use custom template from database
-	err = p.sendEmailWithCustomTemplate(ctx, campaignID, "welcome", email, TemplateData{
+	err = p.sendEmailWithCustomTemplate(ctx, campaign, userID, "welcome", email, TemplateData{
 		FirstName:     firstName,
 		Email:         email,
 		CampaignName:  campaignName,
 		ReferralLink:  referralLink,
 		Position:      position,
 		ReferralCount: referralCount,
+		SignedUpAt:    signedUpAt,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to send welcome email: %w", err)
@@ -274,14 +330,39 @@ func (p *EmailEventProcessor) handleUserVerified(ctx context.Context, event work
 }
 
 // sendEmailWithCustomTemplate tries to send an email using a custom template from the database.
-// If no custom template exists or it's disabled, it falls back to the default hardcoded template.
-func (p *EmailEventProcessor) sendEmailWithCustomTemplate(ctx context.Context, campaignID uuid.UUID, templateType, recipientEmail string, data TemplateData) error {
-	// Try to get custom template from database
-	template, err := p.store.GetCampaignEmailTemplateByType(ctx, campaignID, templateType)
-	if err == nil && template.Enabled {
+// When several enabled templates of the same type exist they are treated as A/B variants and one is
+// picked by weight. If no custom template exists or it's disabled, it falls back to the default hardcoded template.
+func (p *EmailEventProcessor) sendEmailWithCustomTemplate(ctx context.Context, campaign store.Campaign, userID *uuid.UUID, templateType, recipientEmail string, data TemplateData) error {
+	// Try to get custom templates (variants) from database
+	templates, err := p.store.GetEnabledCampaignEmailTemplatesByType(ctx, campaign.ID, templateType)
+	if err == nil && len(templates) > 0 {
+		template := SelectTemplateVariant(templates, rand.Float64())
+		if template.VariantName != nil {
+			ctx = observability.WithFields(ctx, observability.Field{Key: "variant_name", Value: *template.VariantName})
+		}
+
 		// Custom template found and enabled - use it
 		p.logger.Info(ctx, fmt.Sprintf("Using custom %s template for campaign", templateType))
-		return p.emailService.SendCustomTemplateEmail(ctx, recipientEmail, template.Subject, template.HTMLBody, data)
+		body, err := TemplateBody(template.HTMLBody, template.BlocksJSON)
+		if err != nil {
+			return err
+		}
+
+		opts, err := TemplateSendOptions(template.TextBody, template.Headers, template.Attachments)
+		if err != nil {
+			return err
+		}
+		if campaign.EmailSettings != nil && campaign.EmailSettings.ReplyTo != nil {
+			opts.ReplyTo = *campaign.EmailSettings.ReplyTo
+		}
+
+		messageID, err := p.emailService.SendCustomTemplateEmail(ctx, recipientEmail, template.Subject, body, data, opts)
+		if err != nil {
+			return err
+		}
+
+		p.recordTemplateSend(ctx, campaign.ID, userID, template, recipientEmail, messageID)
+		return nil
 	}
 
 	// Fall back to default templates
@@ -298,3 +379,59 @@ func (p *EmailEventProcessor) sendEmailWithCustomTemplate(ctx context.Context, c
 		return fmt.Errorf("unknown template type: %s", templateType)
 	}
 }
+
+// recordTemplateSend stores an email log for a custom template send, including the chosen variant.
+// Failures are logged but do not fail the send since the email has already gone out.
+func (p *EmailEventProcessor) recordTemplateSend(ctx context.Context, campaignID uuid.UUID, userID *uuid.UUID, template store.CampaignEmailTemplate, recipientEmail, messageID string) {
+	var providerMessageID *string
+	if messageID != "" {
+		providerMessageID = &messageID
+	}
+
+	emailLog, err := p.store.CreateEmailLog(ctx, store.CreateEmailLogParams{
+		CampaignID:         campaignID,
+		UserID:             userID,
+		CampaignTemplateID: &template.ID,
+		RecipientEmail:     recipientEmail,
+		Subject:            template.Subject,
+		Type:               template.Type,
+		ProviderMessageID:  providerMessageID,
+		VariantName:        template.VariantName,
+	})
+	if err != nil {
+		p.logger.Error(ctx, "failed to create email log", err)
+		return
+	}
+
+	if err := p.store.UpdateEmailLogStatus(ctx, emailLog.ID, store.EmailLogStatusSent); err != nil {
+		p.logger.Error(ctx, "failed to mark email log as sent", err)
+	}
+}
+
+// parseUserID extracts the waitlist user ID from event user data, if present
+func parseUserID(user map[string]interface{}) *uuid.UUID {
+	idStr, ok := user["id"].(string)
+	if !ok {
+		return nil
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil
+	}
+
+	return &id
+}
+
+// userSignedUpAt returns the signup time of an event's user, or the zero time when it is missing
+func userSignedUpAt(user map[string]interface{}) time.Time {
+	createdAt, ok := user["created_at"].(string)
+	if !ok {
+		return time.Time{}
+	}
+	signedUpAt, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return time.Time{}
+	}
+	return signedUpAt
+}
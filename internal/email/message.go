@@ -0,0 +1,184 @@
+package email
+
+import (
+	"base-server/internal/clients/mail"
+	"base-server/internal/store"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxHeaders is the maximum number of custom headers on a template
+	maxHeaders = 10
+	// maxAttachments is the maximum number of attachments on a template
+	maxAttachments = 3
+	// maxAttachmentBytes is the maximum decoded size of all attachments on a template combined
+	maxAttachmentBytes = 256 * 1024
+)
+
+var (
+	ErrInvalidHeaders     = errors.New("invalid email headers")
+	ErrInvalidAttachments = errors.New("invalid email attachments")
+)
+
+var headerNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9\-]*$`)
+
+// reservedHeaders are set by the mail path and cannot be overridden by a template
+var reservedHeaders = map[string]bool{
+	"bcc": true, "cc": true, "content-transfer-encoding": true, "content-type": true,
+	"date": true, "dkim-signature": true, "from": true, "message-id": true, "mime-version": true,
+	"reply-to": true, "return-path": true, "sender": true, "subject": true, "to": true,
+}
+
+// allowedAttachmentExtensions are the file types templates can attach, e.g. a calendar invite for launch day
+var allowedAttachmentExtensions = map[string]bool{
+	".csv": true, ".gif": true, ".ics": true, ".jpeg": true, ".jpg": true,
+	".pdf": true, ".png": true, ".txt": true, ".vcf": true,
+}
+
+// SendOptions are the optional parts of a custom template email
+type SendOptions struct {
+	// TextBody is a template for the plain-text part; it is generated from the HTML when empty
+	TextBody    string
+	ReplyTo     string
+	Headers     map[string]string
+	Attachments []mail.Attachment
+	// IdempotencyKey identifies the send so the provider delivers it only once when it is retried
+	IdempotencyKey string
+}
+
+// TemplateSendOptions builds send options from a template's stored text body, headers and attachments
+func TemplateSendOptions(textBody *string, headers *store.JSONB, attachments store.EmailAttachments) (SendOptions, error) {
+	var opts SendOptions
+
+	if textBody != nil {
+		opts.TextBody = *textBody
+	}
+
+	if headers != nil && len(*headers) > 0 {
+		opts.Headers = make(map[string]string, len(*headers))
+		for name, value := range *headers {
+			opts.Headers[name] = fmt.Sprint(value)
+		}
+	}
+
+	for _, a := range attachments {
+		content, err := base64.StdEncoding.DecodeString(a.Content)
+		if err != nil {
+			return SendOptions{}, fmt.Errorf("%w: %s is not valid base64", ErrInvalidAttachments, a.Filename)
+		}
+		opts.Attachments = append(opts.Attachments, mail.Attachment{Filename: a.Filename, Content: content})
+	}
+
+	return opts, nil
+}
+
+// AttachmentRequest is a file attached to a template in an API request, with base64 encoded content
+type AttachmentRequest struct {
+	Filename string `json:"filename" binding:"required,max=255"`
+	Content  string `json:"content" binding:"required"`
+}
+
+// ToStoreAttachments converts request attachments, keeping nil (unchanged) distinct from empty (cleared)
+func ToStoreAttachments(attachments []AttachmentRequest) store.EmailAttachments {
+	if attachments == nil {
+		return nil
+	}
+	result := make(store.EmailAttachments, 0, len(attachments))
+	for _, a := range attachments {
+		result = append(result, store.EmailAttachment{Filename: a.Filename, Content: a.Content})
+	}
+	return result
+}
+
+// CheckMessageParts validates the plain-text body, headers and attachments of a template before it
+// is saved. variables are the variables the text body can reference.
+func CheckMessageParts(textBody *string, headers map[string]string, attachments store.EmailAttachments, variables []string) error {
+	if textBody != nil && *textBody != "" {
+		if err := CheckTextContent(*textBody, variables); err != nil {
+			return err
+		}
+	}
+	if err := ValidateHeaders(headers); err != nil {
+		return err
+	}
+	return ValidateAttachments(attachments)
+}
+
+// HeadersToJSONB converts request headers for storage. nil stays nil so updates leave headers unchanged.
+func HeadersToJSONB(headers map[string]string) *store.JSONB {
+	if headers == nil {
+		return nil
+	}
+	result := make(store.JSONB, len(headers))
+	for name, value := range headers {
+		result[name] = value
+	}
+	return &result
+}
+
+// OptionalString returns nil for an empty request field, e.g. a text body left out
+func OptionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// ValidateHeaders checks custom template headers before they are saved
+func ValidateHeaders(headers map[string]string) error {
+	if len(headers) > maxHeaders {
+		return fmt.Errorf("%w: at most %d headers are allowed", ErrInvalidHeaders, maxHeaders)
+	}
+
+	for name, value := range headers {
+		if !headerNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: %q is not a valid header name", ErrInvalidHeaders, name)
+		}
+		if reservedHeaders[strings.ToLower(name)] {
+			return fmt.Errorf("%w: %s cannot be set on a template", ErrInvalidHeaders, name)
+		}
+		if strings.ContainsAny(value, "\r\n") || len(value) > 998 {
+			return fmt.Errorf("%w: value of %s must be a single line under 998 characters", ErrInvalidHeaders, name)
+		}
+	}
+
+	return nil
+}
+
+// ValidateAttachments checks template attachments before they are saved
+func ValidateAttachments(attachments store.EmailAttachments) error {
+	if len(attachments) > maxAttachments {
+		return fmt.Errorf("%w: at most %d attachments are allowed", ErrInvalidAttachments, maxAttachments)
+	}
+
+	total := 0
+	for _, a := range attachments {
+		if a.Filename == "" || len(a.Filename) > 255 || strings.ContainsAny(a.Filename, `/\`) {
+			return fmt.Errorf("%w: %q is not a valid filename", ErrInvalidAttachments, a.Filename)
+		}
+		if !allowedAttachmentExtensions[strings.ToLower(filepath.Ext(a.Filename))] {
+			return fmt.Errorf("%w: %s has an unsupported file type", ErrInvalidAttachments, a.Filename)
+		}
+
+		content, err := base64.StdEncoding.DecodeString(a.Content)
+		if err != nil {
+			return fmt.Errorf("%w: %s is not valid base64", ErrInvalidAttachments, a.Filename)
+		}
+		if len(content) == 0 {
+			return fmt.Errorf("%w: %s is empty", ErrInvalidAttachments, a.Filename)
+		}
+
+		total += len(content)
+	}
+
+	if total > maxAttachmentBytes {
+		return fmt.Errorf("%w: attachments must be %d KB or less in total", ErrInvalidAttachments, maxAttachmentBytes/1024)
+	}
+
+	return nil
+}
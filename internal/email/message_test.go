@@ -0,0 +1,118 @@
+package email
+
+import (
+	"base-server/internal/store"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		valid   bool
+	}{
+		{"no headers", nil, true},
+		{"custom headers", map[string]string{"List-Unsubscribe": "<mailto:unsub@example.com>", "X-Campaign": "launch"}, true},
+		{"reserved header", map[string]string{"From": "someone@example.com"}, false},
+		{"reserved header any case", map[string]string{"reply-to": "someone@example.com"}, false},
+		{"invalid name", map[string]string{"X Campaign": "launch"}, false},
+		{"header injection", map[string]string{"X-Campaign": "launch\r\nBcc: victim@example.com"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHeaders(tt.headers)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidHeaders) {
+				t.Errorf("expected ErrInvalidHeaders, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateAttachments(t *testing.T) {
+	invite := base64.StdEncoding.EncodeToString([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+	tooLarge := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", maxAttachmentBytes+1)))
+
+	tests := []struct {
+		name        string
+		attachments store.EmailAttachments
+		valid       bool
+	}{
+		{"no attachments", nil, true},
+		{"calendar invite", store.EmailAttachments{{Filename: "launch.ics", Content: invite}}, true},
+		{"too many", store.EmailAttachments{
+			{Filename: "a.ics", Content: invite},
+			{Filename: "b.ics", Content: invite},
+			{Filename: "c.ics", Content: invite},
+			{Filename: "d.ics", Content: invite},
+		}, false},
+		{"too large", store.EmailAttachments{{Filename: "big.txt", Content: tooLarge}}, false},
+		{"unsupported type", store.EmailAttachments{{Filename: "run.exe", Content: invite}}, false},
+		{"path in filename", store.EmailAttachments{{Filename: "../launch.ics", Content: invite}}, false},
+		{"invalid base64", store.EmailAttachments{{Filename: "launch.ics", Content: "not base64!"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAttachments(tt.attachments)
+			if tt.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidAttachments) {
+				t.Errorf("expected ErrInvalidAttachments, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTemplateSendOptions(t *testing.T) {
+	textBody := "Hi {{.FirstName}}"
+	headers := store.JSONB{"X-Campaign": "launch"}
+	attachments := store.EmailAttachments{
+		{Filename: "launch.ics", Content: base64.StdEncoding.EncodeToString([]byte("BEGIN:VCALENDAR"))},
+	}
+
+	opts, err := TemplateSendOptions(&textBody, &headers, attachments)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if opts.TextBody != textBody {
+		t.Errorf("expected text body %q, got %q", textBody, opts.TextBody)
+	}
+	if opts.Headers["X-Campaign"] != "launch" {
+		t.Errorf("expected X-Campaign header, got %v", opts.Headers)
+	}
+	if len(opts.Attachments) != 1 || string(opts.Attachments[0].Content) != "BEGIN:VCALENDAR" {
+		t.Errorf("expected decoded attachment, got %+v", opts.Attachments)
+	}
+}
+
+func TestCheckMessageParts(t *testing.T) {
+	text := "Hi {{.FirstName}}, you are #{{.Position}}"
+
+	if err := CheckMessageParts(&text, nil, nil, TemplateVariables()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := CheckMessageParts(&text, nil, nil, BlastTemplateVariables()); !errors.Is(err, ErrUnknownTemplateVariables) {
+		t.Errorf("expected ErrUnknownTemplateVariables, got %v", err)
+	}
+	if err := CheckMessageParts(nil, map[string]string{"From": "someone@example.com"}, nil, TemplateVariables()); !errors.Is(err, ErrInvalidHeaders) {
+		t.Errorf("expected ErrInvalidHeaders, got %v", err)
+	}
+}
+
+func TestToStoreAttachments(t *testing.T) {
+	if got := ToStoreAttachments(nil); got != nil {
+		t.Errorf("expected nil attachments to stay nil, got %v", got)
+	}
+	if got := ToStoreAttachments([]AttachmentRequest{}); got == nil || len(got) != 0 {
+		t.Errorf("expected empty attachments to stay empty, got %v", got)
+	}
+}
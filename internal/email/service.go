@@ -2,12 +2,13 @@ package email
 
 import (
 	"base-server/internal/clients/mail"
+	"base-server/internal/email/templating"
 	"base-server/internal/observability"
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
-	"text/template"
+	"reflect"
+	"time"
 )
 
 var (
@@ -36,9 +37,27 @@ type TemplateData struct {
 	ReferralLink     string
 	ReferralCount    int
 	CampaignName     string
+	SpotsMoved       int
+	SignedUpAt       time.Time // Zero when unknown, rendered empty by the date helper
 	// Add more fields as needed
 }
 
+// TemplateVariables returns the names of the variables custom templates can reference, e.g. FirstName for {{.FirstName}}
+func TemplateVariables() []string {
+	t := reflect.TypeOf(TemplateData{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, t.Field(i).Name)
+	}
+	return names
+}
+
+// BlastTemplateVariables returns the names of the variables blast templates can reference. Blasts
+// are sent without the recipient's waitlist data, so only their email address is filled in.
+func BlastTemplateVariables() []string {
+	return []string{"Email"}
+}
+
 // New creates a new EmailService
 func New(mailClient *mail.ResendClient, defaultSender string, logger *observability.Logger) *EmailService {
 	return &EmailService{
@@ -131,17 +150,12 @@ func (s *EmailService) renderTemplate(templateName string, data TemplateData) (s
 		return "", fmt.Errorf("template %s not found", templateName)
 	}
 
-	tmpl, err := template.New(templateName).Parse(tmplStr)
+	html, err := templating.Render(templateName, tmplStr, data)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
-	}
-
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+		return "", fmt.Errorf("failed to render template: %w", err)
 	}
 
-	return buf.String(), nil
+	return html, nil
 }
 
 // SendWelcomeEmail sends a welcome email to a new user
@@ -256,7 +270,7 @@ func (s *EmailService) SendEmail(ctx context.Context, to, subject, htmlContent s
 // RegisterTemplate adds a new template to the email service
 func (s *EmailService) RegisterTemplate(name, templateContent string) error {
 	// Validate the template by attempting to parse it
-	_, err := template.New(name).Parse(templateContent)
+	err := templating.Validate(templateContent)
 	if err != nil {
 		return fmt.Errorf("invalid template: %w", err)
 	}
@@ -390,29 +404,24 @@ func (s *EmailService) SendWaitlistPositionUpdateEmail(ctx context.Context, to,
 	return nil
 }
 
-// RenderCustomTemplate renders a custom template string with the provided data
+// RenderCustomTemplate renders a custom template string with the provided data, escaping values for HTML
 func (s *EmailService) RenderCustomTemplate(ctx context.Context, templateContent string, data TemplateData) (string, error) {
 	if templateContent == "" {
 		return "", ErrEmptyTemplate
 	}
 
-	tmpl, err := template.New("custom").Parse(templateContent)
+	html, err := templating.Render("custom", templateContent, data)
 	if err != nil {
-		s.logger.Error(ctx, "failed to parse custom template", err)
-		return "", fmt.Errorf("failed to parse template: %w", err)
+		s.logger.Error(ctx, "failed to render custom template", err)
+		return "", fmt.Errorf("failed to render template: %w", err)
 	}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		s.logger.Error(ctx, "failed to execute custom template", err)
-		return "", fmt.Errorf("failed to execute template: %w", err)
-	}
-
-	return buf.String(), nil
+	return html, nil
 }
 
-// SendCustomTemplateEmail renders a custom template and sends it
-func (s *EmailService) SendCustomTemplateEmail(ctx context.Context, to, subject, templateContent string, data TemplateData) error {
+// SendCustomTemplateEmail renders a custom template and sends it with the given text part, reply-to,
+// headers and attachments, returning the provider message ID
+func (s *EmailService) SendCustomTemplateEmail(ctx context.Context, to, subject, templateContent string, data TemplateData, opts SendOptions) (string, error) {
 	ctx = observability.WithFields(ctx,
 		observability.Field{Key: "email_type", Value: "custom_template"},
 		observability.Field{Key: "recipient", Value: to},
@@ -420,14 +429,34 @@ func (s *EmailService) SendCustomTemplateEmail(ctx context.Context, to, subject,
 
 	htmlContent, err := s.RenderCustomTemplate(ctx, templateContent, data)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrEmptyTemplate, err.Error())
-	}
-
-	_, err = s.mailClient.SendEmail(ctx, s.defaultSender, to, subject, htmlContent)
+		return "", fmt.Errorf("%w: %s", ErrEmptyTemplate, err.Error())
+	}
+
+	var textContent string
+	if opts.TextBody != "" {
+		textContent, err = templating.RenderText("custom_text", opts.TextBody, data)
+		if err != nil {
+			s.logger.Error(ctx, "failed to render custom text template", err)
+			return "", fmt.Errorf("%w: %s", ErrEmptyTemplate, err.Error())
+		}
+	}
+
+	messageID, err := s.mailClient.Send(ctx, mail.Message{
+		From:        s.defaultSender,
+		To:          to,
+		Subject:     subject,
+		HTML:        htmlContent,
+		Text:        textContent,
+		ReplyTo:     opts.ReplyTo,
+		Headers:     opts.Headers,
+		Attachments: opts.Attachments,
+
+		IdempotencyKey: opts.IdempotencyKey,
+	})
 	if err != nil {
 		s.logger.Error(ctx, "failed to send custom template email", err)
-		return fmt.Errorf("%w: %s", ErrSendingEmail, err.Error())
+		return "", fmt.Errorf("%w: %w", ErrSendingEmail, err)
 	}
 
-	return nil
+	return messageID, nil
 }
@@ -0,0 +1,23 @@
+package email
+
+import (
+	"fmt"
+
+	"base-server/internal/email/blocks"
+	"base-server/internal/store"
+)
+
+// TemplateBody returns the HTML body to send for a stored template. Templates saved
+// without an HTML body are rendered from their blocks_json design at send time.
+func TemplateBody(htmlBody string, blocksJSON *store.JSONB) (string, error) {
+	if htmlBody != "" || blocksJSON == nil {
+		return htmlBody, nil
+	}
+
+	rendered, err := blocks.RenderMap(*blocksJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to render email blocks: %w", err)
+	}
+
+	return rendered.HTML, nil
+}
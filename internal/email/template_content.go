@@ -0,0 +1,46 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"base-server/internal/email/templating"
+)
+
+var (
+	ErrInvalidTemplateContent   = errors.New("invalid template content")
+	ErrUnknownTemplateVariables = errors.New("template references unknown variables")
+)
+
+// CheckTemplateContent validates a template body before it is saved. Syntax errors include the
+// line number and variables not in variables are listed by name.
+func CheckTemplateContent(content string, variables []string) error {
+	if err := templating.Validate(content); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplateContent, err)
+	}
+	return CheckTemplateVariables(content, variables)
+}
+
+// CheckTextContent validates a plain-text body template before it is saved
+func CheckTextContent(content string, variables []string) error {
+	if err := templating.ValidateText(content); err != nil {
+		return fmt.Errorf("%w: text body %v", ErrInvalidTemplateContent, err)
+	}
+	return CheckTemplateVariables(content, variables)
+}
+
+// CheckTemplateVariables lists any variables the template uses that aren't in variables, the
+// variables available when it is sent
+func CheckTemplateVariables(content string, variables []string) error {
+	err := templating.CheckVariables(content, variables)
+	var unknownErr *templating.UnknownVariablesError
+	if errors.As(err, &unknownErr) {
+		return fmt.Errorf("%w: %s", ErrUnknownTemplateVariables, strings.Join(unknownErr.Variables, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTemplateContent, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package email
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckTemplateContent(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		variables []string
+		wantErr   error
+	}{
+		{"known variables", "<p>Hi {{.FirstName}}, you are #{{.Position}}</p>", TemplateVariables(), nil},
+		{"syntax error", "<p>Hi {{.FirstName</p>", TemplateVariables(), ErrInvalidTemplateContent},
+		{"unknown variable", "<p>Hi {{.Nickname}}</p>", TemplateVariables(), ErrUnknownTemplateVariables},
+		{"waitlist variable in blast", "<p>You are #{{.Position}}</p>", BlastTemplateVariables(), ErrUnknownTemplateVariables},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTemplateContent(tt.content, tt.variables)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
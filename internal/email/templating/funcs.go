@@ -0,0 +1,148 @@
+package templating
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Funcs returns the helper functions available to every email template
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"default":   defaultValue,
+		"upper":     upper,
+		"date":      formatDate,
+		"pluralize": pluralize,
+		"ordinal":   ordinal,
+		"urlsafe":   urlSafe,
+	}
+}
+
+// defaultValue returns value, or fallback when value is empty
+func defaultValue(fallback, value interface{}) interface{} {
+	if isEmpty(value) {
+		return fallback
+	}
+	return value
+}
+
+func upper(value interface{}) string {
+	return strings.ToUpper(toString(value))
+}
+
+// formatDate formats a time.Time, *time.Time or RFC 3339 string with a Go layout.
+// Values that are not times are returned unchanged.
+func formatDate(layout string, value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(layout)
+	case *time.Time:
+		if v == nil || v.IsZero() {
+			return ""
+		}
+		return v.Format(layout)
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return v
+		}
+		return t.Format(layout)
+	}
+	return toString(value)
+}
+
+// pluralize returns singular when count is 1, otherwise plural (singular + "s" when omitted)
+func pluralize(count interface{}, singular string, plural ...string) string {
+	n, _ := toInt(count)
+	if n == 1 || n == -1 {
+		return singular
+	}
+	if len(plural) > 0 {
+		return plural[0]
+	}
+	return singular + "s"
+}
+
+// ordinal formats a number as 1st, 2nd, 3rd, 4th, ... 11th, 12th, 13th, ... 21st
+func ordinal(value interface{}) string {
+	n, ok := toInt(value)
+	if !ok {
+		return toString(value)
+	}
+
+	suffix := "th"
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	if abs%100 < 11 || abs%100 > 13 {
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+
+	return strconv.Itoa(n) + suffix
+}
+
+// urlSafe query-escapes a value. The result is marked as URL content so it isn't escaped a second
+// time when used in an href, e.g. https://twitter.com/intent/tweet?url={{urlsafe .ReferralLink}}.
+func urlSafe(value interface{}) template.URL {
+	return template.URL(url.QueryEscape(toString(value)))
+}
+
+func toString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// toInt accepts any integer or float kind; JSON test data decodes numbers as float64
+func toInt(value interface{}) (int, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return int(math.Round(v.Float())), true
+	case reflect.String:
+		n, err := strconv.Atoi(strings.TrimSpace(v.String()))
+		return n, err == nil
+	case reflect.Ptr:
+		if v.IsNil() {
+			return 0, false
+		}
+		return toInt(v.Elem().Interface())
+	}
+	return 0, false
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,225 @@
+// Package templating parses and renders user-authored email templates.
+//
+// Templates use html/template, so values such as a subscriber's first name are escaped for the
+// context they appear in. A small set of helper functions is available to every template:
+//
+//	{{default "friend" .FirstName}}          fallback for empty values
+//	{{upper .CampaignName}}                  upper-case a value
+//	{{date "Jan 2, 2006" .SignedUpAt}}       format a time with a Go layout
+//	{{pluralize .ReferralCount "friend"}}    "friend" or "friends" depending on the count
+//	{{ordinal .Position}}                    1st, 2nd, 3rd, 11th, ...
+//	{{urlsafe .ReferralLink}}                query-escape a value for use inside a URL
+package templating
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+var ErrUnknownVariables = errors.New("template references unknown variables")
+
+// Error describes a template problem and the line it occurred on (0 when unknown)
+type Error struct {
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// UnknownVariablesError lists variables a template uses that are not available when it is sent
+type UnknownVariablesError struct {
+	Variables []string
+}
+
+func (e *UnknownVariablesError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownVariables, strings.Join(e.Variables, ", "))
+}
+
+func (e *UnknownVariablesError) Unwrap() error {
+	return ErrUnknownVariables
+}
+
+// parseErrorPattern matches the "template: name:line:" prefix of text/template errors
+var parseErrorPattern = regexp.MustCompile(`^template: [^:]*:(\d+):(?:\d+:)?\s*(.*)$`)
+
+// Parse parses content with the helper functions available
+func Parse(name, content string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(Funcs()).Parse(content)
+	if err != nil {
+		return nil, newError(err)
+	}
+	return tmpl, nil
+}
+
+// Render parses content and executes it with data
+func Render(name, content string, data interface{}) (string, error) {
+	tmpl, err := Parse(name, content)
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", newError(err)
+	}
+
+	return buf.String(), nil
+}
+
+// RenderText renders a plain-text template with data. Values are not HTML escaped.
+func RenderText(name, content string, data interface{}) (string, error) {
+	tmpl, err := texttemplate.New(name).Funcs(texttemplate.FuncMap(Funcs())).Parse(content)
+	if err != nil {
+		return "", newError(err)
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", newError(err)
+	}
+
+	return buf.String(), nil
+}
+
+// ValidateText checks that a plain-text template parses
+func ValidateText(content string) error {
+	_, err := texttemplate.New("validate").Funcs(texttemplate.FuncMap(Funcs())).Parse(content)
+	if err != nil {
+		return newError(err)
+	}
+	return nil
+}
+
+// Validate checks that content parses and can be escaped safely, e.g. that no action is left
+// inside an unterminated attribute or script. It does not check which variables are used.
+func Validate(content string) error {
+	tmpl, err := Parse("validate", content)
+	if err != nil {
+		return err
+	}
+
+	// Escaping happens on first execution. Executing without data surfaces escaping problems
+	// while field lookups on the missing data are not treated as errors.
+	err = tmpl.Execute(io.Discard, nil)
+	var escapeErr *template.Error
+	if errors.As(err, &escapeErr) {
+		return &Error{Line: escapeErr.Line, Message: escapeErr.Description}
+	}
+
+	return nil
+}
+
+// CheckVariables parses content and returns an *UnknownVariablesError if it references top-level
+// variables, like {{.Nickname}}, that are not in known
+func CheckVariables(content string, known []string) error {
+	tmpl, err := Parse("validate", content)
+	if err != nil {
+		return err
+	}
+
+	allowed := make(map[string]bool, len(known))
+	for _, k := range known {
+		allowed[k] = true
+	}
+
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, name := range referencedVariables(tmpl) {
+		if !allowed[name] && !seen[name] {
+			seen[name] = true
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return &UnknownVariablesError{Variables: unknown}
+	}
+	return nil
+}
+
+// newError converts a text/template parse or exec error into an *Error with its line number
+func newError(err error) error {
+	var escapeErr *template.Error
+	if errors.As(err, &escapeErr) {
+		return &Error{Line: escapeErr.Line, Message: escapeErr.Description}
+	}
+
+	msg := err.Error()
+	if m := parseErrorPattern.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &Error{Line: line, Message: m[2]}
+	}
+	return &Error{Message: msg}
+}
+
+// referencedVariables returns the top-level fields used by the main template, in order of appearance.
+// Fields inside range and with blocks are relative to a different dot and are skipped, except
+// when referenced through $.
+func referencedVariables(tmpl *template.Template) []string {
+	if tmpl.Tree == nil || tmpl.Tree.Root == nil {
+		return nil
+	}
+
+	var names []string
+	walkNode(tmpl.Tree.Root, true, &names)
+	return names
+}
+
+func walkNode(node parse.Node, rootDot bool, names *[]string) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkNode(child, rootDot, names)
+		}
+	case *parse.ActionNode:
+		walkNode(n.Pipe, rootDot, names)
+	case *parse.IfNode:
+		walkNode(n.Pipe, rootDot, names)
+		walkNode(n.List, rootDot, names)
+		walkNode(n.ElseList, rootDot, names)
+	case *parse.RangeNode:
+		walkNode(n.Pipe, rootDot, names)
+		walkNode(n.List, false, names)
+		walkNode(n.ElseList, rootDot, names)
+	case *parse.WithNode:
+		walkNode(n.Pipe, rootDot, names)
+		walkNode(n.List, false, names)
+		walkNode(n.ElseList, rootDot, names)
+	case *parse.TemplateNode:
+		walkNode(n.Pipe, rootDot, names)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				walkNode(arg, rootDot, names)
+			}
+		}
+	case *parse.FieldNode:
+		if rootDot && len(n.Ident) > 0 {
+			*names = append(*names, n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			*names = append(*names, n.Ident[1])
+		}
+	case *parse.ChainNode:
+		walkNode(n.Node, rootDot, names)
+	}
+}
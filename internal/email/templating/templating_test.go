@@ -0,0 +1,132 @@
+package templating
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderEscapesValues(t *testing.T) {
+	data := map[string]interface{}{
+		"FirstName":    `<script>alert("hi")</script>`,
+		"ReferralLink": "javascript:alert(1)",
+	}
+
+	html, err := Render("email", `<p>Hi {{.FirstName}}</p><a href="{{.ReferralLink}}">share</a>`, data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(html, "<script>") {
+		t.Errorf("expected first name to be escaped, got %q", html)
+	}
+	if strings.Contains(html, "javascript:") {
+		t.Errorf("expected unsafe URL to be filtered, got %q", html)
+	}
+}
+
+func TestFuncs(t *testing.T) {
+	signedUp := time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		template string
+		data     map[string]interface{}
+		expected string
+	}{
+		{"default with empty value", `{{default "friend" .FirstName}}`, map[string]interface{}{"FirstName": ""}, "friend"},
+		{"default with missing value", `{{default "friend" .FirstName}}`, map[string]interface{}{}, "friend"},
+		{"default with value", `{{default "friend" .FirstName}}`, map[string]interface{}{"FirstName": "Ada"}, "Ada"},
+		{"upper", `{{upper .CampaignName}}`, map[string]interface{}{"CampaignName": "launch"}, "LAUNCH"},
+		{"date from time", `{{date "Jan 2, 2006" .SignedUpAt}}`, map[string]interface{}{"SignedUpAt": signedUp}, "Mar 5, 2024"},
+		{"date from string", `{{date "2006-01-02" .SignedUpAt}}`, map[string]interface{}{"SignedUpAt": "2024-03-05T10:00:00Z"}, "2024-03-05"},
+		{"date from zero time", `{{date "Jan 2, 2006" .SignedUpAt}}`, map[string]interface{}{"SignedUpAt": time.Time{}}, ""},
+		{"pluralize one", `{{pluralize .ReferralCount "friend"}}`, map[string]interface{}{"ReferralCount": 1}, "friend"},
+		{"pluralize many", `{{pluralize .ReferralCount "friend"}}`, map[string]interface{}{"ReferralCount": 3}, "friends"},
+		{"pluralize irregular", `{{pluralize .ReferralCount "person" "people"}}`, map[string]interface{}{"ReferralCount": 0}, "people"},
+		{"pluralize float count", `{{pluralize .ReferralCount "friend"}}`, map[string]interface{}{"ReferralCount": float64(1)}, "friend"},
+		{"ordinal 1", `{{ordinal .Position}}`, map[string]interface{}{"Position": 1}, "1st"},
+		{"ordinal 2", `{{ordinal .Position}}`, map[string]interface{}{"Position": 2}, "2nd"},
+		{"ordinal 3", `{{ordinal .Position}}`, map[string]interface{}{"Position": 3}, "3rd"},
+		{"ordinal 11", `{{ordinal .Position}}`, map[string]interface{}{"Position": 11}, "11th"},
+		{"ordinal 112", `{{ordinal .Position}}`, map[string]interface{}{"Position": 112}, "112th"},
+		{"ordinal 121", `{{ordinal .Position}}`, map[string]interface{}{"Position": 121}, "121st"},
+		{
+			"urlsafe in href",
+			`<a href="https://twitter.com/intent/tweet?url={{urlsafe .ReferralLink}}">`,
+			map[string]interface{}{"ReferralLink": "https://example.com/ref?code=A B"},
+			`<a href="https://twitter.com/intent/tweet?url=https%3A%2F%2Fexample.com%2Fref%3Fcode%3DA&#43;B">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Render("test", tt.template, tt.data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestValidateReportsLineNumbers(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		expectedLine int
+	}{
+		{"unclosed action", "<h1>Hi</h1>\n<p>\n{{.FirstName</p>", 3},
+		{"missing end", "<p>\n{{if .FirstName}}hi</p>", 2},
+		{"unknown function", "<p>hi</p>\n\n{{shout .FirstName}}", 3},
+		// html/template can't attribute a template that ends inside an attribute to a line
+		{"unterminated attribute", "<p>hi</p>\n<a href=\"{{.ReferralLink}}>link</a>", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.content)
+
+			var tmplErr *Error
+			if !errors.As(err, &tmplErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if tmplErr.Line != tt.expectedLine {
+				t.Errorf("expected line %d, got %d (%v)", tt.expectedLine, tmplErr.Line, err)
+			}
+		})
+	}
+}
+
+func TestCheckVariables(t *testing.T) {
+	known := []string{"FirstName", "Position", "ReferralLink"}
+
+	t.Run("known variables pass", func(t *testing.T) {
+		content := `{{default "friend" .FirstName}} is {{ordinal .Position}}{{with .ReferralLink}} {{.}}{{end}}`
+		if err := CheckVariables(content, known); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("lists unknown variables once in order", func(t *testing.T) {
+		content := `{{.Nickname}} {{if .Points}}{{.Nickname}}{{end}} {{range .Rewards}}{{.Name}}{{end}} {{with .FirstName}}{{$.Tier}}{{end}}`
+
+		err := CheckVariables(content, known)
+
+		var unknownErr *UnknownVariablesError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("expected *UnknownVariablesError, got %v", err)
+		}
+		if !errors.Is(err, ErrUnknownVariables) {
+			t.Errorf("expected ErrUnknownVariables, got %v", err)
+		}
+
+		expected := []string{"Nickname", "Points", "Rewards", "Tier"}
+		if strings.Join(unknownErr.Variables, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected %v, got %v", expected, unknownErr.Variables)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package email
+
+import (
+	"math"
+
+	"base-server/internal/store"
+)
+
+// defaultVariantWeight is used for templates that have no explicit variant weight
+const defaultVariantWeight = 100
+
+// significanceConfidence is the confidence level (percent) at which a variant difference is reported as significant
+const significanceConfidence = 95.0
+
+// Metrics used to rank campaign email template variants
+const (
+	VariantMetricOpenRate   = "open_rate"
+	VariantMetricClickRate  = "click_rate"
+	VariantMetricVerifyRate = "verify_rate"
+)
+
+// PrimaryVariantMetric returns the metric used to judge variants of a template type.
+// Verification emails are judged on how many recipients verify, everything else on clicks.
+func PrimaryVariantMetric(templateType string) string {
+	if templateType == store.EmailTemplateTypeVerification {
+		return VariantMetricVerifyRate
+	}
+	return VariantMetricClickRate
+}
+
+// variantWeight returns the effective weight of a template, treating missing weights as the default
+func variantWeight(template store.CampaignEmailTemplate) int {
+	if template.VariantWeight == nil {
+		return defaultVariantWeight
+	}
+	if *template.VariantWeight < 0 {
+		return 0
+	}
+	return *template.VariantWeight
+}
+
+// SelectTemplateVariant picks a template using weighted random selection.
+// roll must be in [0, 1); it is passed in so callers control the randomness source.
+// If every weight is zero the first template is returned.
+func SelectTemplateVariant(templates []store.CampaignEmailTemplate, roll float64) store.CampaignEmailTemplate {
+	if len(templates) == 1 {
+		return templates[0]
+	}
+
+	totalWeight := 0
+	for _, t := range templates {
+		totalWeight += variantWeight(t)
+	}
+	if totalWeight == 0 {
+		return templates[0]
+	}
+
+	target := int(roll * float64(totalWeight))
+	for _, t := range templates {
+		target -= variantWeight(t)
+		if target < 0 {
+			return t
+		}
+	}
+
+	return templates[len(templates)-1]
+}
+
+// VariantResult represents the computed performance of one template variant
+type VariantResult struct {
+	store.CampaignEmailTemplateVariantStats
+
+	OpenRate   float64 `json:"open_rate"`
+	ClickRate  float64 `json:"click_rate"`
+	VerifyRate float64 `json:"verify_rate"`
+
+	// Significance of the primary metric compared to the control variant
+	IsControl   bool    `json:"is_control"`
+	ZScore      float64 `json:"z_score"`
+	Confidence  float64 `json:"confidence"`
+	Significant bool    `json:"significant"`
+
+	IsLeader bool `json:"is_leader"`
+}
+
+// metricCount returns the number of successes for a metric
+func metricCount(stats store.CampaignEmailTemplateVariantStats, metric string) int {
+	switch metric {
+	case VariantMetricOpenRate:
+		return stats.Opened
+	case VariantMetricVerifyRate:
+		return stats.Verified
+	default:
+		return stats.Clicked
+	}
+}
+
+// rate returns count/total as a percentage
+func rate(count, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total) * 100
+}
+
+// EvaluateVariants computes rates for each variant and compares every variant against the
+// control (the oldest variant) on the given metric using a two-proportion z-test.
+func EvaluateVariants(stats []store.CampaignEmailTemplateVariantStats, metric string) []VariantResult {
+	results := make([]VariantResult, len(stats))
+	if len(stats) == 0 {
+		return results
+	}
+
+	for i, s := range stats {
+		results[i] = VariantResult{
+			CampaignEmailTemplateVariantStats: s,
+			OpenRate:                          rate(s.Opened, s.Sent),
+			ClickRate:                         rate(s.Clicked, s.Sent),
+			VerifyRate:                        rate(s.Verified, s.Sent),
+		}
+	}
+
+	control := stats[0]
+	results[0].IsControl = true
+
+	leader := -1
+	leaderRate := -1.0
+	for i, s := range stats {
+		if i > 0 {
+			z := twoProportionZScore(metricCount(control, metric), control.Sent, metricCount(s, metric), s.Sent)
+			results[i].ZScore = z
+			results[i].Confidence = (2*normalCDF(math.Abs(z)) - 1) * 100
+			results[i].Significant = results[i].Confidence >= significanceConfidence
+		}
+
+		if s.Sent == 0 {
+			continue
+		}
+		r := rate(metricCount(s, metric), s.Sent)
+		if r > leaderRate {
+			leader = i
+			leaderRate = r
+		}
+	}
+
+	if leader >= 0 {
+		results[leader].IsLeader = true
+	}
+
+	return results
+}
+
+// PickVariantWinner returns the best enabled variant once every enabled variant has at least minSends sends.
+// It returns false while the test is still running, when there is nothing to choose between, or when no
+// variant has any opens, clicks or verifications for the metric yet.
+func PickVariantWinner(results []VariantResult, metric string, minSends int) (VariantResult, bool) {
+	var enabled []VariantResult
+	for _, r := range results {
+		if r.Enabled {
+			enabled = append(enabled, r)
+		}
+	}
+
+	if len(enabled) < 2 || minSends <= 0 {
+		return VariantResult{}, false
+	}
+
+	best := enabled[0]
+	for _, r := range enabled {
+		if r.Sent < minSends {
+			return VariantResult{}, false
+		}
+		if rate(metricCount(r.CampaignEmailTemplateVariantStats, metric), r.Sent) > rate(metricCount(best.CampaignEmailTemplateVariantStats, metric), best.Sent) {
+			best = r
+		}
+	}
+
+	if metricCount(best.CampaignEmailTemplateVariantStats, metric) == 0 {
+		return VariantResult{}, false
+	}
+	return best, true
+}
+
+// twoProportionZScore returns the z statistic for the difference between two proportions
+func twoProportionZScore(successA, totalA, successB, totalB int) float64 {
+	if totalA == 0 || totalB == 0 {
+		return 0
+	}
+
+	pA := float64(successA) / float64(totalA)
+	pB := float64(successB) / float64(totalB)
+	pooled := float64(successA+successB) / float64(totalA+totalB)
+
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(totalA) + 1/float64(totalB)))
+	if se == 0 {
+		return 0
+	}
+
+	return (pB - pA) / se
+}
+
+// normalCDF returns the standard normal cumulative distribution function at x
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
@@ -0,0 +1,81 @@
+package email
+
+import (
+	"testing"
+
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestSelectTemplateVariant(t *testing.T) {
+	a := store.CampaignEmailTemplate{ID: uuid.New(), VariantWeight: intPtr(75)}
+	b := store.CampaignEmailTemplate{ID: uuid.New(), VariantWeight: intPtr(25)}
+
+	tests := []struct {
+		name      string
+		templates []store.CampaignEmailTemplate
+		roll      float64
+		want      uuid.UUID
+	}{
+		{"low roll picks first", []store.CampaignEmailTemplate{a, b}, 0.1, a.ID},
+		{"roll at boundary picks second", []store.CampaignEmailTemplate{a, b}, 0.75, b.ID},
+		{"high roll picks second", []store.CampaignEmailTemplate{a, b}, 0.99, b.ID},
+		{"zero weight is never picked", []store.CampaignEmailTemplate{{ID: a.ID, VariantWeight: intPtr(0)}, b}, 0.0, b.ID},
+		{"all zero weights fall back to first", []store.CampaignEmailTemplate{{ID: a.ID, VariantWeight: intPtr(0)}, {ID: b.ID, VariantWeight: intPtr(0)}}, 0.5, a.ID},
+		{"missing weights are equal", []store.CampaignEmailTemplate{{ID: a.ID}, {ID: b.ID}}, 0.6, b.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SelectTemplateVariant(tt.templates, tt.roll)
+			if got.ID != tt.want {
+				t.Errorf("expected template %s, got %s", tt.want, got.ID)
+			}
+		})
+	}
+}
+
+func TestPickVariantWinner(t *testing.T) {
+	control := store.CampaignEmailTemplateVariantStats{TemplateID: uuid.New(), Enabled: true, Sent: 200, Clicked: 10}
+	challenger := store.CampaignEmailTemplateVariantStats{TemplateID: uuid.New(), Enabled: true, Sent: 150, Clicked: 30}
+
+	t.Run("waits until every variant reaches the threshold", func(t *testing.T) {
+		results := EvaluateVariants([]store.CampaignEmailTemplateVariantStats{control, challenger}, VariantMetricClickRate)
+		if _, ok := PickVariantWinner(results, VariantMetricClickRate, 175); ok {
+			t.Error("expected no winner before threshold is reached")
+		}
+	})
+
+	t.Run("picks the best rate once threshold is reached", func(t *testing.T) {
+		results := EvaluateVariants([]store.CampaignEmailTemplateVariantStats{control, challenger}, VariantMetricClickRate)
+		winner, ok := PickVariantWinner(results, VariantMetricClickRate, 100)
+		if !ok {
+			t.Fatal("expected a winner")
+		}
+		if winner.TemplateID != challenger.TemplateID {
+			t.Errorf("expected challenger to win, got %s", winner.TemplateID)
+		}
+	})
+
+	t.Run("waits while no variant has engagement", func(t *testing.T) {
+		unopened := []store.CampaignEmailTemplateVariantStats{control, challenger}
+		unopened[0].Clicked = 0
+		unopened[1].Clicked = 0
+		results := EvaluateVariants(unopened, VariantMetricClickRate)
+		if _, ok := PickVariantWinner(results, VariantMetricClickRate, 100); ok {
+			t.Error("expected no winner without any clicks")
+		}
+	})
+
+	t.Run("ignores disabled variants", func(t *testing.T) {
+		disabled := challenger
+		disabled.Enabled = false
+		results := EvaluateVariants([]store.CampaignEmailTemplateVariantStats{control, disabled}, VariantMetricClickRate)
+		if _, ok := PickVariantWinner(results, VariantMetricClickRate, 100); ok {
+			t.Error("expected no winner with a single enabled variant")
+		}
+	})
+}
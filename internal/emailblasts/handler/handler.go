@@ -33,6 +33,10 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 		apierrors.NotFound(c, "Email blast not found")
 	case errors.Is(err, processor.ErrSegmentNotFound):
 		apierrors.NotFound(c, "Segment not found")
+	case errors.Is(err, processor.ErrAudienceNotFound):
+		apierrors.NotFound(c, "Audience not found")
+	case errors.Is(err, processor.ErrInvalidRecipients):
+		apierrors.BadRequest(c, "INVALID_INPUT", "Provide either segment_ids or audience_id, not both")
 	case errors.Is(err, processor.ErrTemplateNotFound):
 		apierrors.NotFound(c, "Email template not found")
 	case errors.Is(err, processor.ErrCampaignNotFound):
@@ -55,6 +59,10 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 		apierrors.BadRequest(c, "INVALID_INPUT", "Scheduled time must be in the future")
 	case errors.Is(err, processor.ErrNoRecipients):
 		apierrors.BadRequest(c, "INVALID_INPUT", "Segment has no matching users to send to")
+	case errors.Is(err, processor.ErrInvalidABTest):
+		apierrors.BadRequest(c, "INVALID_INPUT", "A/B tests need 2 to 4 uniquely named variants, a test percentage between 1 and 50 and a metric of open_rate or click_rate")
+	case errors.Is(err, processor.ErrInvalidLocalSendTime):
+		apierrors.BadRequest(c, "INVALID_INPUT", "send_at_local_time must be HH:MM, fallback_timezone must be an IANA timezone and local time delivery cannot be combined with an A/B test")
 	case errors.Is(err, processor.ErrEmailBlastsNotAvailable):
 		apierrors.Forbidden(c, "FEATURE_NOT_AVAILABLE", "Email blasts are not available in your plan. Please upgrade to Team plan.")
 	default:
@@ -65,12 +73,30 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 // CreateEmailBlastRequest represents the HTTP request for creating an email blast
 type CreateEmailBlastRequest struct {
 	Name                  string     `json:"name" binding:"required,max=255"`
-	SegmentIDs            []string   `json:"segment_ids" binding:"required,min=1,dive,uuid"`
+	SegmentIDs            []string   `json:"segment_ids" binding:"required_without=AudienceID,omitempty,min=1,dive,uuid"`
+	AudienceID            *string    `json:"audience_id,omitempty" binding:"omitempty,uuid"`
 	BlastTemplateID       string     `json:"blast_template_id" binding:"required,uuid"`
 	Subject               string     `json:"subject" binding:"required,max=255"`
 	ScheduledAt           *time.Time `json:"scheduled_at,omitempty"`
 	BatchSize             *int       `json:"batch_size,omitempty"`
 	SendThrottlePerSecond *int       `json:"send_throttle_per_second,omitempty"`
+
+	// A/B test settings
+	Variants          []BlastVariantRequest `json:"variants,omitempty" binding:"omitempty,min=2,max=4,dive"`
+	ABTestPercentage  *int                  `json:"ab_test_percentage,omitempty" binding:"omitempty,min=1,max=50"`
+	ABTestWaitMinutes *int                  `json:"ab_test_wait_minutes,omitempty" binding:"omitempty,min=1"`
+	ABTestMetric      *string               `json:"ab_test_metric,omitempty" binding:"omitempty,oneof=open_rate click_rate"`
+
+	// Local time delivery settings
+	SendAtLocalTime  *string `json:"send_at_local_time,omitempty"`
+	FallbackTimezone *string `json:"fallback_timezone,omitempty" binding:"omitempty,max=64"`
+}
+
+// BlastVariantRequest represents a subject/template variant in an A/B tested blast
+type BlastVariantRequest struct {
+	Name            string  `json:"name" binding:"required,max=100"`
+	Subject         string  `json:"subject" binding:"required,max=255"`
+	BlastTemplateID *string `json:"blast_template_id,omitempty" binding:"omitempty,uuid"`
 }
 
 // HandleCreateEmailBlast handles POST /api/v1/blasts
@@ -111,8 +137,26 @@ func (h *Handler) HandleCreateEmailBlast(c *gin.Context) {
 		segmentIDs[i], _ = uuid.Parse(s)
 	}
 
+	var audienceID *uuid.UUID
+	if req.AudienceID != nil {
+		id, _ := uuid.Parse(*req.AudienceID)
+		audienceID = &id
+	}
+
 	blastTemplateID, _ := uuid.Parse(req.BlastTemplateID)
 
+	variants := make([]processor.BlastVariantRequest, len(req.Variants))
+	for i, v := range req.Variants {
+		variants[i] = processor.BlastVariantRequest{
+			Name:    v.Name,
+			Subject: v.Subject,
+		}
+		if v.BlastTemplateID != nil {
+			templateID, _ := uuid.Parse(*v.BlastTemplateID)
+			variants[i].BlastTemplateID = &templateID
+		}
+	}
+
 	batchSize := 100
 	if req.BatchSize != nil {
 		batchSize = *req.BatchSize
@@ -121,11 +165,18 @@ func (h *Handler) HandleCreateEmailBlast(c *gin.Context) {
 	processorReq := processor.CreateEmailBlastRequest{
 		Name:                  req.Name,
 		SegmentIDs:            segmentIDs,
+		AudienceID:            audienceID,
 		BlastTemplateID:       blastTemplateID,
 		Subject:               req.Subject,
 		ScheduledAt:           req.ScheduledAt,
 		BatchSize:             batchSize,
 		SendThrottlePerSecond: req.SendThrottlePerSecond,
+		Variants:              variants,
+		ABTestPercentage:      req.ABTestPercentage,
+		ABTestWaitMinutes:     req.ABTestWaitMinutes,
+		ABTestMetric:          req.ABTestMetric,
+		SendAtLocalTime:       req.SendAtLocalTime,
+		FallbackTimezone:      req.FallbackTimezone,
 	}
 
 	blast, err := h.processor.CreateEmailBlast(ctx, accountID, userID, processorReq)
@@ -103,6 +103,20 @@ func (mr *MockEmailBlastStoreMockRecorder) CreateEmailBlast(ctx, params any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailBlast", reflect.TypeOf((*MockEmailBlastStore)(nil).CreateEmailBlast), ctx, params)
 }
 
+// DeleteBlastSendRateBucket mocks base method.
+func (m *MockEmailBlastStore) DeleteBlastSendRateBucket(ctx context.Context, blastID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBlastSendRateBucket", ctx, blastID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBlastSendRateBucket indicates an expected call of DeleteBlastSendRateBucket.
+func (mr *MockEmailBlastStoreMockRecorder) DeleteBlastSendRateBucket(ctx, blastID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBlastSendRateBucket", reflect.TypeOf((*MockEmailBlastStore)(nil).DeleteBlastSendRateBucket), ctx, blastID)
+}
+
 // DeleteEmailBlast mocks base method.
 func (m *MockEmailBlastStore) DeleteEmailBlast(ctx context.Context, blastID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -117,6 +131,36 @@ func (mr *MockEmailBlastStoreMockRecorder) DeleteEmailBlast(ctx, blastID any) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmailBlast", reflect.TypeOf((*MockEmailBlastStore)(nil).DeleteEmailBlast), ctx, blastID)
 }
 
+// GetAudienceByID mocks base method.
+func (m *MockEmailBlastStore) GetAudienceByID(ctx context.Context, audienceID uuid.UUID) (store.Audience, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAudienceByID", ctx, audienceID)
+	ret0, _ := ret[0].(store.Audience)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAudienceByID indicates an expected call of GetAudienceByID.
+func (mr *MockEmailBlastStoreMockRecorder) GetAudienceByID(ctx, audienceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAudienceByID", reflect.TypeOf((*MockEmailBlastStore)(nil).GetAudienceByID), ctx, audienceID)
+}
+
+// GetBlastCohortStats mocks base method.
+func (m *MockEmailBlastStore) GetBlastCohortStats(ctx context.Context, blastID uuid.UUID) ([]store.BlastCohortStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlastCohortStats", ctx, blastID)
+	ret0, _ := ret[0].([]store.BlastCohortStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlastCohortStats indicates an expected call of GetBlastCohortStats.
+func (mr *MockEmailBlastStoreMockRecorder) GetBlastCohortStats(ctx, blastID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlastCohortStats", reflect.TypeOf((*MockEmailBlastStore)(nil).GetBlastCohortStats), ctx, blastID)
+}
+
 // GetBlastEmailTemplateByID mocks base method.
 func (m *MockEmailBlastStore) GetBlastEmailTemplateByID(ctx context.Context, templateID uuid.UUID) (store.BlastEmailTemplate, error) {
 	m.ctrl.T.Helper()
@@ -162,6 +206,21 @@ func (mr *MockEmailBlastStoreMockRecorder) GetBlastRecipientsByBlast(ctx, blastI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlastRecipientsByBlast", reflect.TypeOf((*MockEmailBlastStore)(nil).GetBlastRecipientsByBlast), ctx, blastID, limit, offset)
 }
 
+// GetBlastVariantStats mocks base method.
+func (m *MockEmailBlastStore) GetBlastVariantStats(ctx context.Context, blastID uuid.UUID) ([]store.BlastVariantStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlastVariantStats", ctx, blastID)
+	ret0, _ := ret[0].([]store.BlastVariantStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlastVariantStats indicates an expected call of GetBlastVariantStats.
+func (mr *MockEmailBlastStoreMockRecorder) GetBlastVariantStats(ctx, blastID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlastVariantStats", reflect.TypeOf((*MockEmailBlastStore)(nil).GetBlastVariantStats), ctx, blastID)
+}
+
 // GetCampaignByID mocks base method.
 func (m *MockEmailBlastStore) GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error) {
 	m.ctrl.T.Helper()
@@ -192,6 +251,21 @@ func (mr *MockEmailBlastStoreMockRecorder) GetEmailBlastByID(ctx, blastID any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailBlastByID", reflect.TypeOf((*MockEmailBlastStore)(nil).GetEmailBlastByID), ctx, blastID)
 }
 
+// GetEmailBlastVariantsByBlast mocks base method.
+func (m *MockEmailBlastStore) GetEmailBlastVariantsByBlast(ctx context.Context, blastID uuid.UUID) ([]store.EmailBlastVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailBlastVariantsByBlast", ctx, blastID)
+	ret0, _ := ret[0].([]store.EmailBlastVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailBlastVariantsByBlast indicates an expected call of GetEmailBlastVariantsByBlast.
+func (mr *MockEmailBlastStoreMockRecorder) GetEmailBlastVariantsByBlast(ctx, blastID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailBlastVariantsByBlast", reflect.TypeOf((*MockEmailBlastStore)(nil).GetEmailBlastVariantsByBlast), ctx, blastID)
+}
+
 // GetEmailBlastsByAccount mocks base method.
 func (m *MockEmailBlastStore) GetEmailBlastsByAccount(ctx context.Context, accountID uuid.UUID, limit, offset int) ([]store.EmailBlast, error) {
 	m.ctrl.T.Helper()
@@ -16,6 +16,7 @@ import (
 type EmailBlastStore interface {
 	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
 	GetSegmentByID(ctx context.Context, segmentID uuid.UUID) (store.Segment, error)
+	GetAudienceByID(ctx context.Context, audienceID uuid.UUID) (store.Audience, error)
 	GetBlastEmailTemplateByID(ctx context.Context, templateID uuid.UUID) (store.BlastEmailTemplate, error)
 	CreateEmailBlast(ctx context.Context, params store.CreateEmailBlastParams) (store.EmailBlast, error)
 	GetEmailBlastByID(ctx context.Context, blastID uuid.UUID) (store.EmailBlast, error)
@@ -24,6 +25,7 @@ type EmailBlastStore interface {
 	UpdateEmailBlast(ctx context.Context, blastID uuid.UUID, params store.UpdateEmailBlastParams) (store.EmailBlast, error)
 	DeleteEmailBlast(ctx context.Context, blastID uuid.UUID) error
 	UpdateEmailBlastStatus(ctx context.Context, blastID uuid.UUID, status string, errorMessage *string) (store.EmailBlast, error)
+	DeleteBlastSendRateBucket(ctx context.Context, blastID uuid.UUID) error
 	UpdateEmailBlastTotalRecipients(ctx context.Context, blastID uuid.UUID, totalRecipients int) error
 	ScheduleBlast(ctx context.Context, blastID uuid.UUID, scheduledAt time.Time) (store.EmailBlast, error)
 	GetBlastRecipientsByBlast(ctx context.Context, blastID uuid.UUID, limit, offset int) ([]store.BlastRecipient, error)
@@ -31,6 +33,9 @@ type EmailBlastStore interface {
 	GetBlastRecipientStats(ctx context.Context, blastID uuid.UUID) (store.BlastRecipientStats, error)
 	PreviewBlastRecipients(ctx context.Context, segmentIDs []uuid.UUID) (store.BlastRecipientsPreview, error)
 	CreateBlastRecipientsFromMultipleSegments(ctx context.Context, blastID uuid.UUID, segmentIDs []uuid.UUID, batchSize int) (int, error)
+	GetEmailBlastVariantsByBlast(ctx context.Context, blastID uuid.UUID) ([]store.EmailBlastVariant, error)
+	GetBlastVariantStats(ctx context.Context, blastID uuid.UUID) ([]store.BlastVariantStats, error)
+	GetBlastCohortStats(ctx context.Context, blastID uuid.UUID) ([]store.BlastCohortStats, error)
 }
 
 // TierChecker defines the tier checking operations required by EmailBlastProcessor
@@ -47,6 +52,8 @@ type EventDispatcher interface {
 var (
 	ErrBlastNotFound         = errors.New("email blast not found")
 	ErrSegmentNotFound       = errors.New("segment not found")
+	ErrAudienceNotFound      = errors.New("audience not found")
+	ErrInvalidRecipients     = errors.New("blast must target either segments or an audience")
 	ErrTemplateNotFound      = errors.New("email template not found")
 	ErrCampaignNotFound      = errors.New("campaign not found")
 	ErrUnauthorized          = errors.New("unauthorized access to email blast")
@@ -59,6 +66,19 @@ var (
 	ErrInvalidScheduleTime   = errors.New("scheduled time must be in the future")
 	ErrNoRecipients          = errors.New("segment has no matching users")
 	ErrEmailBlastsNotAvailable = errors.New("email blasts are not available in your plan")
+	ErrInvalidABTest         = errors.New("invalid A/B test configuration")
+	ErrInvalidLocalSendTime  = errors.New("invalid local send time")
+)
+
+const (
+	// maxBlastVariants is the maximum number of A/B test variants on a blast
+	maxBlastVariants = 4
+	// defaultABTestPercentage is the share of recipients in the A/B test slice
+	defaultABTestPercentage = 10
+	// defaultABTestWaitMinutes is how long to wait after the test slice before sending the winner
+	defaultABTestWaitMinutes = 240
+	// defaultFallbackTimezone is the timezone of recipients without one in local time blasts
+	defaultFallbackTimezone = "UTC"
 )
 
 type EmailBlastProcessor struct {
@@ -81,11 +101,31 @@ func New(store EmailBlastStore, tierChecker TierChecker, eventDispatcher EventDi
 type CreateEmailBlastRequest struct {
 	Name                  string
 	SegmentIDs            []uuid.UUID
+	AudienceID            *uuid.UUID // sends the blast to an account-level audience instead of SegmentIDs
 	BlastTemplateID       uuid.UUID
 	Subject               string
 	ScheduledAt           *time.Time
 	BatchSize             int
 	SendThrottlePerSecond *int
+
+	// A/B test settings. A test runs when two or more variants are given.
+	Variants          []BlastVariantRequest
+	ABTestPercentage  *int
+	ABTestWaitMinutes *int
+	ABTestMetric      *string
+
+	// Local time delivery settings. When SendAtLocalTime ("HH:MM") is set each recipient gets the
+	// blast at that time in their own timezone, or in FallbackTimezone when theirs is unknown.
+	SendAtLocalTime  *string
+	FallbackTimezone *string
+}
+
+// BlastVariantRequest represents a subject/template variant of an A/B tested blast.
+// BlastTemplateID defaults to the blast's template when nil.
+type BlastVariantRequest struct {
+	Name            string
+	Subject         string
+	BlastTemplateID *uuid.UUID
 }
 
 // CreateEmailBlast creates a new email blast for an account
@@ -104,8 +144,12 @@ func (p *EmailBlastProcessor) CreateEmailBlast(ctx context.Context, accountID uu
 		return store.EmailBlast{}, ErrEmailBlastsNotAvailable
 	}
 
-	// Validate at least one segment is provided
-	if len(req.SegmentIDs) == 0 {
+	if req.AudienceID != nil {
+		if err := p.verifyAudience(ctx, accountID, req); err != nil {
+			return store.EmailBlast{}, err
+		}
+	} else if len(req.SegmentIDs) == 0 {
+		// Validate at least one segment is provided
 		return store.EmailBlast{}, ErrSegmentNotFound
 	}
 
@@ -149,6 +193,16 @@ func (p *EmailBlastProcessor) CreateEmailBlast(ctx context.Context, accountID uu
 		return store.EmailBlast{}, ErrUnauthorized
 	}
 
+	variants, err := p.buildBlastVariants(ctx, accountID, req)
+	if err != nil {
+		return store.EmailBlast{}, err
+	}
+
+	sendAtLocalTime, fallbackTimezone, err := validateLocalSendTime(req)
+	if err != nil {
+		return store.EmailBlast{}, err
+	}
+
 	// Validate scheduled time if provided
 	if req.ScheduledAt != nil && req.ScheduledAt.Before(time.Now()) {
 		return store.EmailBlast{}, ErrInvalidScheduleTime
@@ -164,12 +218,35 @@ func (p *EmailBlastProcessor) CreateEmailBlast(ctx context.Context, accountID uu
 		AccountID:             accountID,
 		BlastTemplateID:       req.BlastTemplateID,
 		SegmentIDs:            req.SegmentIDs,
+		AudienceID:            req.AudienceID,
 		Name:                  req.Name,
 		Subject:               req.Subject,
 		ScheduledAt:           req.ScheduledAt,
 		BatchSize:             batchSize,
 		SendThrottlePerSecond: req.SendThrottlePerSecond,
 		CreatedBy:             userID,
+		Variants:              variants,
+		SendAtLocalTime:       sendAtLocalTime,
+		FallbackTimezone:      fallbackTimezone,
+	}
+
+	if len(variants) > 0 {
+		percentage := defaultABTestPercentage
+		if req.ABTestPercentage != nil {
+			percentage = *req.ABTestPercentage
+		}
+		waitMinutes := defaultABTestWaitMinutes
+		if req.ABTestWaitMinutes != nil {
+			waitMinutes = *req.ABTestWaitMinutes
+		}
+		metric := string(store.BlastABTestMetricOpenRate)
+		if req.ABTestMetric != nil {
+			metric = *req.ABTestMetric
+		}
+
+		params.ABTestPercentage = &percentage
+		params.ABTestWaitMinutes = &waitMinutes
+		params.ABTestMetric = &metric
 	}
 
 	blast, err := p.store.CreateEmailBlast(ctx, params)
@@ -182,6 +259,127 @@ func (p *EmailBlastProcessor) CreateEmailBlast(ctx context.Context, accountID uu
 	return blast, nil
 }
 
+// verifyAudience checks that a blast targeting an audience has no segments and that the audience
+// belongs to the account
+func (p *EmailBlastProcessor) verifyAudience(ctx context.Context, accountID uuid.UUID, req CreateEmailBlastRequest) error {
+	if len(req.SegmentIDs) > 0 {
+		return ErrInvalidRecipients
+	}
+
+	audience, err := p.store.GetAudienceByID(ctx, *req.AudienceID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrAudienceNotFound
+		}
+		p.logger.Error(ctx, "failed to get audience", err)
+		return err
+	}
+
+	if audience.AccountID != accountID {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// buildBlastVariants validates the A/B test settings of a create request and resolves variant templates
+func (p *EmailBlastProcessor) buildBlastVariants(ctx context.Context, accountID uuid.UUID, req CreateEmailBlastRequest) ([]store.CreateEmailBlastVariantParams, error) {
+	if len(req.Variants) == 0 {
+		if req.ABTestPercentage != nil || req.ABTestWaitMinutes != nil || req.ABTestMetric != nil {
+			return nil, ErrInvalidABTest
+		}
+		return nil, nil
+	}
+
+	if len(req.Variants) < 2 || len(req.Variants) > maxBlastVariants {
+		return nil, ErrInvalidABTest
+	}
+
+	if req.ABTestPercentage != nil && (*req.ABTestPercentage < 1 || *req.ABTestPercentage > 50) {
+		return nil, ErrInvalidABTest
+	}
+
+	if req.ABTestWaitMinutes != nil && *req.ABTestWaitMinutes <= 0 {
+		return nil, ErrInvalidABTest
+	}
+
+	if req.ABTestMetric != nil &&
+		*req.ABTestMetric != string(store.BlastABTestMetricOpenRate) &&
+		*req.ABTestMetric != string(store.BlastABTestMetricClickRate) {
+		return nil, ErrInvalidABTest
+	}
+
+	seenNames := make(map[string]bool)
+	variants := make([]store.CreateEmailBlastVariantParams, 0, len(req.Variants))
+	for _, v := range req.Variants {
+		if v.Name == "" || v.Subject == "" || seenNames[v.Name] {
+			return nil, ErrInvalidABTest
+		}
+		seenNames[v.Name] = true
+
+		templateID := req.BlastTemplateID
+		if v.BlastTemplateID != nil && *v.BlastTemplateID != req.BlastTemplateID {
+			template, err := p.store.GetBlastEmailTemplateByID(ctx, *v.BlastTemplateID)
+			if err != nil {
+				if errors.Is(err, store.ErrNotFound) {
+					return nil, ErrTemplateNotFound
+				}
+				p.logger.Error(ctx, "failed to get variant template", err)
+				return nil, err
+			}
+
+			if template.AccountID != accountID {
+				return nil, ErrUnauthorized
+			}
+			templateID = template.ID
+		}
+
+		variants = append(variants, store.CreateEmailBlastVariantParams{
+			BlastTemplateID: templateID,
+			Name:            v.Name,
+			Subject:         v.Subject,
+		})
+	}
+
+	return variants, nil
+}
+
+// validateLocalSendTime validates the local time delivery settings of a create request and
+// returns the send time and fallback timezone to store
+func validateLocalSendTime(req CreateEmailBlastRequest) (*string, *string, error) {
+	if req.SendAtLocalTime == nil {
+		if req.FallbackTimezone != nil {
+			return nil, nil, ErrInvalidLocalSendTime
+		}
+		return nil, nil, nil
+	}
+
+	// Send times are stored as HH:MM, so accepted forms such as 9:00 are zero-padded
+	sendAt, err := time.Parse("15:04", *req.SendAtLocalTime)
+	if err != nil {
+		return nil, nil, ErrInvalidLocalSendTime
+	}
+	sendAtLocalTime := sendAt.Format("15:04")
+
+	// Local time delivery releases recipients by timezone, which would split the A/B test slice
+	if len(req.Variants) > 0 {
+		return nil, nil, ErrInvalidLocalSendTime
+	}
+
+	fallbackTimezone := defaultFallbackTimezone
+	if req.FallbackTimezone != nil {
+		if *req.FallbackTimezone == "" || *req.FallbackTimezone == "Local" {
+			return nil, nil, ErrInvalidLocalSendTime
+		}
+		loc, err := time.LoadLocation(*req.FallbackTimezone)
+		if err != nil {
+			return nil, nil, ErrInvalidLocalSendTime
+		}
+		fallbackTimezone = loc.String()
+	}
+
+	return &sendAtLocalTime, &fallbackTimezone, nil
+}
+
 // GetEmailBlast retrieves an email blast by ID
 func (p *EmailBlastProcessor) GetEmailBlast(ctx context.Context, accountID, blastID uuid.UUID) (store.EmailBlast, error) {
 	ctx = observability.WithFields(ctx,
@@ -203,6 +401,26 @@ func (p *EmailBlastProcessor) GetEmailBlast(ctx context.Context, accountID, blas
 		return store.EmailBlast{}, ErrUnauthorized
 	}
 
+	// Include variants for A/B tested blasts
+	if blast.ABTestPercentage != nil {
+		variants, err := p.store.GetEmailBlastVariantsByBlast(ctx, blastID)
+		if err != nil {
+			p.logger.Error(ctx, "failed to get email blast variants", err)
+			return store.EmailBlast{}, err
+		}
+		blast.Variants = variants
+	}
+
+	// Include timezone cohort progress for local time blasts
+	if blast.SendAtLocalTime != nil {
+		cohorts, err := p.store.GetBlastCohortStats(ctx, blastID)
+		if err != nil {
+			p.logger.Error(ctx, "failed to get blast cohort stats", err)
+			return store.EmailBlast{}, err
+		}
+		blast.Cohorts = cohorts
+	}
+
 	return blast, nil
 }
 
@@ -535,6 +753,11 @@ func (p *EmailBlastProcessor) CancelBlast(ctx context.Context, accountID, blastI
 		return store.EmailBlast{}, err
 	}
 
+	// The blast's own send rate bucket is no longer needed; the cancel succeeds even if it is left behind
+	if err := p.store.DeleteBlastSendRateBucket(ctx, blastID); err != nil {
+		p.logger.Error(ctx, "failed to delete blast send rate bucket", err)
+	}
+
 	p.logger.Info(ctx, "email blast cancelled successfully")
 	return blast, nil
 }
@@ -557,6 +780,32 @@ type BlastAnalytics struct {
 	StartedAt       *time.Time `json:"started_at,omitempty"`
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
 	DurationSeconds *int       `json:"duration_seconds,omitempty"`
+
+	// EffectiveSendRate is the achieved rate in emails per second between the first and last send
+	EffectiveSendRate     *float64 `json:"effective_send_rate,omitempty"`
+	SendThrottlePerSecond *int     `json:"send_throttle_per_second,omitempty"`
+
+	ABTest *BlastABTestAnalytics `json:"ab_test,omitempty"`
+
+	// Cohorts shows per-timezone progress of blasts sent at each recipient's local time
+	Cohorts []store.BlastCohortStats `json:"cohorts,omitempty"`
+}
+
+// BlastABTestAnalytics represents the results of a blast's A/B test slice
+type BlastABTestAnalytics struct {
+	Metric          string                  `json:"metric"`
+	Percentage      int                     `json:"percentage"`
+	EndsAt          *time.Time              `json:"ends_at,omitempty"`
+	WinnerVariantID *uuid.UUID              `json:"winner_variant_id,omitempty"`
+	Variants        []BlastVariantAnalytics `json:"variants"`
+}
+
+// BlastVariantAnalytics represents test slice performance for one blast variant
+type BlastVariantAnalytics struct {
+	store.BlastVariantStats
+	OpenRate  float64 `json:"open_rate"`
+	ClickRate float64 `json:"click_rate"`
+	IsWinner  bool    `json:"is_winner"`
 }
 
 // GetBlastAnalytics retrieves analytics for an email blast
@@ -590,6 +839,9 @@ func (p *EmailBlastProcessor) GetBlastAnalytics(ctx context.Context, accountID,
 		Failed:          stats.Failed,
 		StartedAt:       blast.StartedAt,
 		CompletedAt:     blast.CompletedAt,
+		Cohorts:         blast.Cohorts,
+
+		SendThrottlePerSecond: blast.SendThrottlePerSecond,
 	}
 
 	// Calculate rates
@@ -605,9 +857,69 @@ func (p *EmailBlastProcessor) GetBlastAnalytics(ctx context.Context, accountID,
 		analytics.DurationSeconds = &duration
 	}
 
+	analytics.EffectiveSendRate = effectiveSendRate(analytics.Sent, stats.FirstSentAt, stats.LastSentAt)
+
+	if blast.ABTestPercentage != nil {
+		abTest, err := p.getBlastABTestAnalytics(ctx, blast)
+		if err != nil {
+			return BlastAnalytics{}, err
+		}
+		analytics.ABTest = &abTest
+	}
+
 	return analytics, nil
 }
 
+// effectiveSendRate returns the emails sent per second between the first and last send,
+// or nil until there are at least two sends to measure
+func effectiveSendRate(sent int, firstSentAt, lastSentAt *time.Time) *float64 {
+	if sent < 2 || firstSentAt == nil || lastSentAt == nil {
+		return nil
+	}
+
+	window := lastSentAt.Sub(*firstSentAt).Seconds()
+	if window <= 0 {
+		return nil
+	}
+
+	rate := float64(sent-1) / window
+	return &rate
+}
+
+// getBlastABTestAnalytics computes per-variant results of a blast's A/B test slice
+func (p *EmailBlastProcessor) getBlastABTestAnalytics(ctx context.Context, blast store.EmailBlast) (BlastABTestAnalytics, error) {
+	stats, err := p.store.GetBlastVariantStats(ctx, blast.ID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get blast variant stats", err)
+		return BlastABTestAnalytics{}, err
+	}
+
+	abTest := BlastABTestAnalytics{
+		Metric:          string(store.BlastABTestMetricOpenRate),
+		Percentage:      *blast.ABTestPercentage,
+		EndsAt:          blast.ABTestEndsAt,
+		WinnerVariantID: blast.ABTestWinnerVariantID,
+		Variants:        make([]BlastVariantAnalytics, 0, len(stats)),
+	}
+	if blast.ABTestMetric != nil {
+		abTest.Metric = *blast.ABTestMetric
+	}
+
+	for _, s := range stats {
+		variant := BlastVariantAnalytics{
+			BlastVariantStats: s,
+			IsWinner:          blast.ABTestWinnerVariantID != nil && *blast.ABTestWinnerVariantID == s.VariantID,
+		}
+		if s.Sent > 0 {
+			variant.OpenRate = float64(s.Opened) / float64(s.Sent) * 100
+			variant.ClickRate = float64(s.Clicked) / float64(s.Sent) * 100
+		}
+		abTest.Variants = append(abTest.Variants, variant)
+	}
+
+	return abTest, nil
+}
+
 // ListBlastRecipientsRequest represents a request to list blast recipients
 type ListBlastRecipientsRequest struct {
 	Page  int
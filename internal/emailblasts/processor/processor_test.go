@@ -278,6 +278,85 @@ func TestCreateEmailBlast(t *testing.T) {
 
 		assert.ErrorIs(t, err, ErrInvalidScheduleTime)
 	})
+
+	t.Run("successfully creates email blast for an audience", func(t *testing.T) {
+		audienceID := uuid.New()
+
+		mockTierChecker.EXPECT().
+			HasFeatureByAccountID(gomock.Any(), accountID, "email_blasts").
+			Return(true, nil)
+
+		mockStore.EXPECT().
+			GetAudienceByID(gomock.Any(), audienceID).
+			Return(store.Audience{ID: audienceID, AccountID: accountID}, nil)
+
+		mockStore.EXPECT().
+			GetBlastEmailTemplateByID(gomock.Any(), templateID).
+			Return(store.BlastEmailTemplate{ID: templateID, AccountID: accountID}, nil)
+
+		mockStore.EXPECT().
+			CreateEmailBlast(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params store.CreateEmailBlastParams) (store.EmailBlast, error) {
+				assert.Equal(t, &audienceID, params.AudienceID)
+				assert.Empty(t, params.SegmentIDs)
+				return store.EmailBlast{ID: blastID, AccountID: accountID, AudienceID: params.AudienceID}, nil
+			})
+
+		req := CreateEmailBlastRequest{
+			Name:            "Everyone",
+			AudienceID:      &audienceID,
+			BlastTemplateID: templateID,
+			Subject:         "Test Subject",
+		}
+
+		result, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		require.NoError(t, err)
+		assert.Equal(t, &audienceID, result.AudienceID)
+	})
+
+	t.Run("returns error when audience belongs to another account", func(t *testing.T) {
+		audienceID := uuid.New()
+
+		mockTierChecker.EXPECT().
+			HasFeatureByAccountID(gomock.Any(), accountID, "email_blasts").
+			Return(true, nil)
+
+		mockStore.EXPECT().
+			GetAudienceByID(gomock.Any(), audienceID).
+			Return(store.Audience{ID: audienceID, AccountID: uuid.New()}, nil)
+
+		req := CreateEmailBlastRequest{
+			Name:            "Everyone",
+			AudienceID:      &audienceID,
+			BlastTemplateID: templateID,
+			Subject:         "Test Subject",
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+
+	t.Run("returns error when both segments and audience are provided", func(t *testing.T) {
+		audienceID := uuid.New()
+
+		mockTierChecker.EXPECT().
+			HasFeatureByAccountID(gomock.Any(), accountID, "email_blasts").
+			Return(true, nil)
+
+		req := CreateEmailBlastRequest{
+			Name:            "Everyone",
+			SegmentIDs:      []uuid.UUID{segmentID},
+			AudienceID:      &audienceID,
+			BlastTemplateID: templateID,
+			Subject:         "Test Subject",
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		assert.ErrorIs(t, err, ErrInvalidRecipients)
+	})
 }
 
 func TestGetEmailBlast(t *testing.T) {
@@ -904,6 +983,10 @@ func TestCancelBlast(t *testing.T) {
 				UpdateEmailBlastStatus(gomock.Any(), blastID, string(store.EmailBlastStatusCancelled), nil).
 				Return(cancelledBlast, nil)
 
+			mockStore.EXPECT().
+				DeleteBlastSendRateBucket(gomock.Any(), blastID).
+				Return(nil)
+
 			result, err := processor.CancelBlast(ctx, accountID, blastID)
 
 			require.NoError(t, err)
@@ -911,6 +994,39 @@ func TestCancelBlast(t *testing.T) {
 		})
 	}
 
+	t.Run("cancels blast when its send rate bucket cannot be deleted", func(t *testing.T) {
+		existingBlast := store.EmailBlast{
+			ID:        blastID,
+			AccountID: accountID,
+			Name:      "Test Blast",
+			Status:    string(store.EmailBlastStatusSending),
+		}
+
+		cancelledBlast := store.EmailBlast{
+			ID:        blastID,
+			AccountID: accountID,
+			Name:      "Test Blast",
+			Status:    string(store.EmailBlastStatusCancelled),
+		}
+
+		mockStore.EXPECT().
+			GetEmailBlastByID(gomock.Any(), blastID).
+			Return(existingBlast, nil)
+
+		mockStore.EXPECT().
+			UpdateEmailBlastStatus(gomock.Any(), blastID, string(store.EmailBlastStatusCancelled), nil).
+			Return(cancelledBlast, nil)
+
+		mockStore.EXPECT().
+			DeleteBlastSendRateBucket(gomock.Any(), blastID).
+			Return(errors.New("database error"))
+
+		result, err := processor.CancelBlast(ctx, accountID, blastID)
+
+		require.NoError(t, err)
+		assert.Equal(t, string(store.EmailBlastStatusCancelled), result.Status)
+	})
+
 	t.Run("returns error when blast is already completed", func(t *testing.T) {
 		existingBlast := store.EmailBlast{
 			ID:        blastID,
@@ -980,6 +1096,34 @@ func TestGetBlastAnalytics(t *testing.T) {
 		assert.Equal(t, 5, result.Failed)
 	})
 
+	t.Run("reports effective send rate", func(t *testing.T) {
+		throttle := 10
+		firstSentAt := time.Now().Add(-time.Minute)
+		lastSentAt := firstSentAt.Add(99 * time.Second)
+		blast := store.EmailBlast{
+			ID:                    blastID,
+			AccountID:             accountID,
+			Status:                string(store.EmailBlastStatusCompleted),
+			TotalRecipients:       100,
+			SendThrottlePerSecond: &throttle,
+		}
+
+		mockStore.EXPECT().
+			GetEmailBlastByID(gomock.Any(), blastID).
+			Return(blast, nil)
+
+		mockStore.EXPECT().
+			GetBlastRecipientStats(gomock.Any(), blastID).
+			Return(store.BlastRecipientStats{Sent: 100, FirstSentAt: &firstSentAt, LastSentAt: &lastSentAt}, nil)
+
+		result, err := processor.GetBlastAnalytics(ctx, accountID, blastID)
+
+		require.NoError(t, err)
+		require.NotNil(t, result.EffectiveSendRate)
+		assert.InDelta(t, 1.0, *result.EffectiveSendRate, 0.001)
+		assert.Equal(t, &throttle, result.SendThrottlePerSecond)
+	})
+
 	t.Run("returns error when blast not found", func(t *testing.T) {
 		mockStore.EXPECT().
 			GetEmailBlastByID(gomock.Any(), blastID).
@@ -1048,3 +1192,381 @@ func TestListBlastRecipients(t *testing.T) {
 		assert.ErrorIs(t, err, ErrBlastNotFound)
 	})
 }
+
+func TestCreateEmailBlastABTest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailBlastStore(ctrl)
+	mockTierChecker := NewMockTierChecker(ctrl)
+	mockEventDispatcher := NewMockEventDispatcher(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, mockTierChecker, mockEventDispatcher, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+	templateID := uuid.New()
+	variantTemplateID := uuid.New()
+
+	expectValidBlast := func() {
+		mockTierChecker.EXPECT().
+			HasFeatureByAccountID(gomock.Any(), accountID, "email_blasts").
+			Return(true, nil)
+
+		mockStore.EXPECT().
+			GetSegmentByID(gomock.Any(), segmentID).
+			Return(store.Segment{ID: segmentID, CampaignID: campaignID}, nil)
+
+		mockStore.EXPECT().
+			GetCampaignByID(gomock.Any(), campaignID).
+			Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+
+		mockStore.EXPECT().
+			GetBlastEmailTemplateByID(gomock.Any(), templateID).
+			Return(store.BlastEmailTemplate{ID: templateID, AccountID: accountID}, nil)
+	}
+
+	t.Run("creates variants with default test settings", func(t *testing.T) {
+		expectValidBlast()
+
+		mockStore.EXPECT().
+			GetBlastEmailTemplateByID(gomock.Any(), variantTemplateID).
+			Return(store.BlastEmailTemplate{ID: variantTemplateID, AccountID: accountID}, nil)
+
+		mockStore.EXPECT().
+			CreateEmailBlast(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params store.CreateEmailBlastParams) (store.EmailBlast, error) {
+				require.Len(t, params.Variants, 2)
+				assert.Equal(t, templateID, params.Variants[0].BlastTemplateID)
+				assert.Equal(t, variantTemplateID, params.Variants[1].BlastTemplateID)
+				assert.Equal(t, 10, *params.ABTestPercentage)
+				assert.Equal(t, 240, *params.ABTestWaitMinutes)
+				assert.Equal(t, "open_rate", *params.ABTestMetric)
+				return store.EmailBlast{ID: uuid.New(), AccountID: accountID}, nil
+			})
+
+		req := CreateEmailBlastRequest{
+			Name:            "Test Blast",
+			SegmentIDs:      []uuid.UUID{segmentID},
+			BlastTemplateID: templateID,
+			Subject:         "Test Subject",
+			BatchSize:       100,
+			Variants: []BlastVariantRequest{
+				{Name: "A", Subject: "Subject A"},
+				{Name: "B", Subject: "Subject B", BlastTemplateID: &variantTemplateID},
+			},
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("returns error for a single variant", func(t *testing.T) {
+		expectValidBlast()
+
+		req := CreateEmailBlastRequest{
+			Name:            "Test Blast",
+			SegmentIDs:      []uuid.UUID{segmentID},
+			BlastTemplateID: templateID,
+			Subject:         "Test Subject",
+			Variants:        []BlastVariantRequest{{Name: "A", Subject: "Subject A"}},
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		assert.ErrorIs(t, err, ErrInvalidABTest)
+	})
+
+	t.Run("returns error for duplicate variant names", func(t *testing.T) {
+		expectValidBlast()
+
+		req := CreateEmailBlastRequest{
+			Name:            "Test Blast",
+			SegmentIDs:      []uuid.UUID{segmentID},
+			BlastTemplateID: templateID,
+			Subject:         "Test Subject",
+			Variants: []BlastVariantRequest{
+				{Name: "A", Subject: "Subject A"},
+				{Name: "A", Subject: "Subject B"},
+			},
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		assert.ErrorIs(t, err, ErrInvalidABTest)
+	})
+
+	t.Run("returns error for test settings without variants", func(t *testing.T) {
+		expectValidBlast()
+
+		percentage := 20
+		req := CreateEmailBlastRequest{
+			Name:             "Test Blast",
+			SegmentIDs:       []uuid.UUID{segmentID},
+			BlastTemplateID:  templateID,
+			Subject:          "Test Subject",
+			ABTestPercentage: &percentage,
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		assert.ErrorIs(t, err, ErrInvalidABTest)
+	})
+}
+
+func TestGetBlastAnalyticsABTest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailBlastStore(ctrl)
+	mockTierChecker := NewMockTierChecker(ctrl)
+	mockEventDispatcher := NewMockEventDispatcher(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, mockTierChecker, mockEventDispatcher, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	blastID := uuid.New()
+	variantA := uuid.New()
+	variantB := uuid.New()
+
+	t.Run("includes variant results and winner", func(t *testing.T) {
+		percentage := 10
+		metric := "click_rate"
+		blast := store.EmailBlast{
+			ID:                    blastID,
+			AccountID:             accountID,
+			Status:                string(store.EmailBlastStatusSending),
+			ABTestPercentage:      &percentage,
+			ABTestMetric:          &metric,
+			ABTestWinnerVariantID: &variantB,
+		}
+
+		mockStore.EXPECT().
+			GetEmailBlastByID(gomock.Any(), blastID).
+			Return(blast, nil)
+
+		mockStore.EXPECT().
+			GetEmailBlastVariantsByBlast(gomock.Any(), blastID).
+			Return([]store.EmailBlastVariant{{ID: variantA}, {ID: variantB}}, nil)
+
+		mockStore.EXPECT().
+			GetBlastRecipientStats(gomock.Any(), blastID).
+			Return(store.BlastRecipientStats{}, nil)
+
+		mockStore.EXPECT().
+			GetBlastVariantStats(gomock.Any(), blastID).
+			Return([]store.BlastVariantStats{
+				{VariantID: variantA, Name: "A", Sent: 50, Opened: 20, Clicked: 5},
+				{VariantID: variantB, Name: "B", Sent: 50, Opened: 15, Clicked: 10},
+			}, nil)
+
+		result, err := processor.GetBlastAnalytics(ctx, accountID, blastID)
+
+		require.NoError(t, err)
+		require.NotNil(t, result.ABTest)
+		assert.Equal(t, "click_rate", result.ABTest.Metric)
+		require.Len(t, result.ABTest.Variants, 2)
+		assert.Equal(t, 40.0, result.ABTest.Variants[0].OpenRate)
+		assert.Equal(t, 20.0, result.ABTest.Variants[1].ClickRate)
+		assert.False(t, result.ABTest.Variants[0].IsWinner)
+		assert.True(t, result.ABTest.Variants[1].IsWinner)
+	})
+}
+
+func TestCreateEmailBlastLocalTime(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailBlastStore(ctrl)
+	mockTierChecker := NewMockTierChecker(ctrl)
+	mockEventDispatcher := NewMockEventDispatcher(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, mockTierChecker, mockEventDispatcher, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+	templateID := uuid.New()
+
+	expectValidBlast := func() {
+		mockTierChecker.EXPECT().
+			HasFeatureByAccountID(gomock.Any(), accountID, "email_blasts").
+			Return(true, nil)
+
+		mockStore.EXPECT().
+			GetSegmentByID(gomock.Any(), segmentID).
+			Return(store.Segment{ID: segmentID, CampaignID: campaignID}, nil)
+
+		mockStore.EXPECT().
+			GetCampaignByID(gomock.Any(), campaignID).
+			Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+
+		mockStore.EXPECT().
+			GetBlastEmailTemplateByID(gomock.Any(), templateID).
+			Return(store.BlastEmailTemplate{ID: templateID, AccountID: accountID}, nil)
+	}
+
+	newRequest := func(sendAt, fallback *string) CreateEmailBlastRequest {
+		return CreateEmailBlastRequest{
+			Name:             "Test Blast",
+			SegmentIDs:       []uuid.UUID{segmentID},
+			BlastTemplateID:  templateID,
+			Subject:          "Test Subject",
+			BatchSize:        100,
+			SendAtLocalTime:  sendAt,
+			FallbackTimezone: fallback,
+		}
+	}
+
+	t.Run("defaults the fallback timezone to UTC", func(t *testing.T) {
+		expectValidBlast()
+
+		sendAt := "09:00"
+		mockStore.EXPECT().
+			CreateEmailBlast(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params store.CreateEmailBlastParams) (store.EmailBlast, error) {
+				require.NotNil(t, params.SendAtLocalTime)
+				require.NotNil(t, params.FallbackTimezone)
+				assert.Equal(t, "09:00", *params.SendAtLocalTime)
+				assert.Equal(t, "UTC", *params.FallbackTimezone)
+				return store.EmailBlast{ID: uuid.New(), AccountID: accountID}, nil
+			})
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, newRequest(&sendAt, nil))
+
+		require.NoError(t, err)
+	})
+
+	t.Run("stores the fallback timezone", func(t *testing.T) {
+		expectValidBlast()
+
+		sendAt := "18:30"
+		fallback := "Europe/Berlin"
+		mockStore.EXPECT().
+			CreateEmailBlast(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params store.CreateEmailBlastParams) (store.EmailBlast, error) {
+				assert.Equal(t, "Europe/Berlin", *params.FallbackTimezone)
+				return store.EmailBlast{ID: uuid.New(), AccountID: accountID}, nil
+			})
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, newRequest(&sendAt, &fallback))
+
+		require.NoError(t, err)
+	})
+
+	t.Run("zero-pads the send time", func(t *testing.T) {
+		expectValidBlast()
+
+		sendAt := "9:05"
+		mockStore.EXPECT().
+			CreateEmailBlast(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, params store.CreateEmailBlastParams) (store.EmailBlast, error) {
+				require.NotNil(t, params.SendAtLocalTime)
+				assert.Equal(t, "09:05", *params.SendAtLocalTime)
+				return store.EmailBlast{ID: uuid.New(), AccountID: accountID}, nil
+			})
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, newRequest(&sendAt, nil))
+
+		require.NoError(t, err)
+	})
+
+	t.Run("returns error for an invalid send time", func(t *testing.T) {
+		expectValidBlast()
+
+		sendAt := "9am"
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, newRequest(&sendAt, nil))
+
+		assert.ErrorIs(t, err, ErrInvalidLocalSendTime)
+	})
+
+	t.Run("returns error for an unknown fallback timezone", func(t *testing.T) {
+		expectValidBlast()
+
+		sendAt := "09:00"
+		fallback := "Mars/Olympus_Mons"
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, newRequest(&sendAt, &fallback))
+
+		assert.ErrorIs(t, err, ErrInvalidLocalSendTime)
+	})
+
+	t.Run("returns error for a fallback timezone without a send time", func(t *testing.T) {
+		expectValidBlast()
+
+		fallback := "UTC"
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, newRequest(nil, &fallback))
+
+		assert.ErrorIs(t, err, ErrInvalidLocalSendTime)
+	})
+
+	t.Run("returns error when combined with an A/B test", func(t *testing.T) {
+		expectValidBlast()
+
+		sendAt := "09:00"
+		req := newRequest(&sendAt, nil)
+		req.Variants = []BlastVariantRequest{
+			{Name: "A", Subject: "Subject A"},
+			{Name: "B", Subject: "Subject B"},
+		}
+
+		_, err := processor.CreateEmailBlast(ctx, accountID, nil, req)
+
+		assert.ErrorIs(t, err, ErrInvalidLocalSendTime)
+	})
+}
+
+func TestGetBlastAnalyticsCohorts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailBlastStore(ctrl)
+	mockTierChecker := NewMockTierChecker(ctrl)
+	mockEventDispatcher := NewMockEventDispatcher(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, mockTierChecker, mockEventDispatcher, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	blastID := uuid.New()
+
+	t.Run("includes timezone cohort progress", func(t *testing.T) {
+		sendAt := "09:00"
+		releasedAt := time.Now().Add(-time.Hour)
+		blast := store.EmailBlast{
+			ID:              blastID,
+			AccountID:       accountID,
+			Status:          string(store.EmailBlastStatusSending),
+			SendAtLocalTime: &sendAt,
+		}
+
+		mockStore.EXPECT().
+			GetEmailBlastByID(gomock.Any(), blastID).
+			Return(blast, nil)
+
+		mockStore.EXPECT().
+			GetBlastCohortStats(gomock.Any(), blastID).
+			Return([]store.BlastCohortStats{
+				{Timezone: "Europe/Berlin", ReleasedAt: &releasedAt, Recipients: 40, Sent: 38, Failed: 2},
+				{Timezone: "America/New_York", Recipients: 60, Pending: 60},
+			}, nil)
+
+		mockStore.EXPECT().
+			GetBlastRecipientStats(gomock.Any(), blastID).
+			Return(store.BlastRecipientStats{Sent: 38, Failed: 2, Pending: 60}, nil)
+
+		result, err := processor.GetBlastAnalytics(ctx, accountID, blastID)
+
+		require.NoError(t, err)
+		require.Len(t, result.Cohorts, 2)
+		assert.Equal(t, "Europe/Berlin", result.Cohorts[0].Timezone)
+		assert.NotNil(t, result.Cohorts[0].ReleasedAt)
+		assert.Nil(t, result.Cohorts[1].ReleasedAt)
+		assert.Equal(t, 60, result.Cohorts[1].Pending)
+		assert.Nil(t, result.ABTest)
+	})
+}
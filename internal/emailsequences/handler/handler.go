@@ -0,0 +1,357 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"base-server/internal/apierrors"
+	"base-server/internal/emailsequences/processor"
+	"base-server/internal/observability"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	processor processor.EmailSequenceProcessor
+	logger    *observability.Logger
+}
+
+func New(processor processor.EmailSequenceProcessor, logger *observability.Logger) Handler {
+	return Handler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, processor.ErrEmailSequenceNotFound):
+		apierrors.NotFound(c, "Email sequence not found")
+	case errors.Is(err, processor.ErrCampaignNotFound):
+		apierrors.NotFound(c, "Campaign not found")
+	case errors.Is(err, processor.ErrTemplateNotFound):
+		apierrors.NotFound(c, "Email template not found")
+	case errors.Is(err, processor.ErrUnauthorized):
+		apierrors.Forbidden(c, "FORBIDDEN", "You do not have access to this email sequence")
+	case errors.Is(err, processor.ErrInvalidSequence):
+		apierrors.BadRequest(c, "INVALID_INPUT", err.Error())
+	default:
+		apierrors.InternalError(c, err)
+	}
+}
+
+// EmailSequenceStepRequest represents one step of a sequence in HTTP requests.
+// Condition uses the same format as segment filter criteria.
+type EmailSequenceStepRequest struct {
+	TemplateID   string                 `json:"template_id" binding:"required,uuid"`
+	DelayMinutes int                    `json:"delay_minutes" binding:"min=0"`
+	Condition    map[string]interface{} `json:"condition,omitempty"`
+}
+
+// CreateEmailSequenceRequest represents the HTTP request for creating an email sequence
+type CreateEmailSequenceRequest struct {
+	Name   string                     `json:"name" binding:"required,max=255"`
+	Status *string                    `json:"status,omitempty" binding:"omitempty,oneof=draft active paused"`
+	Steps  []EmailSequenceStepRequest `json:"steps" binding:"omitempty,dive"`
+}
+
+// HandleCreateEmailSequence handles POST /api/v1/campaigns/:campaign_id/email-sequences
+func (h *Handler) HandleCreateEmailSequence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	var req CreateEmailSequenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	processorReq := processor.CreateEmailSequenceRequest{
+		Name:   req.Name,
+		Status: req.Status,
+		Steps:  toProcessorSteps(req.Steps),
+	}
+
+	sequence, err := h.processor.CreateEmailSequence(ctx, accountID, campaignID, processorReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sequence)
+}
+
+// HandleListEmailSequences handles GET /api/v1/campaigns/:campaign_id/email-sequences
+func (h *Handler) HandleListEmailSequences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	sequences, err := h.processor.ListEmailSequences(ctx, accountID, campaignID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sequences)
+}
+
+// HandleGetEmailSequence handles GET /api/v1/campaigns/:campaign_id/email-sequences/:sequence_id
+func (h *Handler) HandleGetEmailSequence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get sequence ID from path
+	sequenceIDStr := c.Param("sequence_id")
+	sequenceID, err := uuid.Parse(sequenceIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse sequence ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sequence id"})
+		return
+	}
+
+	sequence, err := h.processor.GetEmailSequence(ctx, accountID, campaignID, sequenceID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sequence)
+}
+
+// UpdateEmailSequenceRequest represents the HTTP request for updating an email sequence.
+// Steps replace all existing steps when present.
+type UpdateEmailSequenceRequest struct {
+	Name   *string                     `json:"name,omitempty" binding:"omitempty,max=255"`
+	Status *string                     `json:"status,omitempty" binding:"omitempty,oneof=draft active paused"`
+	Steps  *[]EmailSequenceStepRequest `json:"steps,omitempty" binding:"omitempty,dive"`
+}
+
+// HandleUpdateEmailSequence handles PUT /api/v1/campaigns/:campaign_id/email-sequences/:sequence_id
+func (h *Handler) HandleUpdateEmailSequence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get sequence ID from path
+	sequenceIDStr := c.Param("sequence_id")
+	sequenceID, err := uuid.Parse(sequenceIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse sequence ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sequence id"})
+		return
+	}
+
+	var req UpdateEmailSequenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	processorReq := processor.UpdateEmailSequenceRequest{
+		Name:   req.Name,
+		Status: req.Status,
+	}
+	if req.Steps != nil {
+		processorReq.Steps = toProcessorSteps(*req.Steps)
+	}
+
+	sequence, err := h.processor.UpdateEmailSequence(ctx, accountID, campaignID, sequenceID, processorReq)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sequence)
+}
+
+// HandleDeleteEmailSequence handles DELETE /api/v1/campaigns/:campaign_id/email-sequences/:sequence_id
+func (h *Handler) HandleDeleteEmailSequence(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get sequence ID from path
+	sequenceIDStr := c.Param("sequence_id")
+	sequenceID, err := uuid.Parse(sequenceIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse sequence ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sequence id"})
+		return
+	}
+
+	err = h.processor.DeleteEmailSequence(ctx, accountID, campaignID, sequenceID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// HandleGetEmailSequenceAnalytics handles GET /api/v1/campaigns/:campaign_id/email-sequences/:sequence_id/analytics
+func (h *Handler) HandleGetEmailSequenceAnalytics(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get sequence ID from path
+	sequenceIDStr := c.Param("sequence_id")
+	sequenceID, err := uuid.Parse(sequenceIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse sequence ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid sequence id"})
+		return
+	}
+
+	analytics, err := h.processor.GetEmailSequenceAnalytics(ctx, accountID, campaignID, sequenceID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// toProcessorSteps converts HTTP steps to processor steps. Template IDs are validated by binding.
+func toProcessorSteps(steps []EmailSequenceStepRequest) []processor.EmailSequenceStepRequest {
+	result := make([]processor.EmailSequenceStepRequest, 0, len(steps))
+	for _, step := range steps {
+		templateID, _ := uuid.Parse(step.TemplateID)
+		result = append(result, processor.EmailSequenceStepRequest{
+			TemplateID:   templateID,
+			DelayMinutes: step.DelayMinutes,
+			Condition:    step.Condition,
+		})
+	}
+	return result
+}
@@ -0,0 +1,177 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: processor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=processor.go -destination=mocks_test.go -package=processor
+//
+
+// Package processor is a generated GoMock package.
+package processor
+
+import (
+	store "base-server/internal/store"
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEmailSequenceStore is a mock of EmailSequenceStore interface.
+type MockEmailSequenceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailSequenceStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailSequenceStoreMockRecorder is the mock recorder for MockEmailSequenceStore.
+type MockEmailSequenceStoreMockRecorder struct {
+	mock *MockEmailSequenceStore
+}
+
+// NewMockEmailSequenceStore creates a new mock instance.
+func NewMockEmailSequenceStore(ctrl *gomock.Controller) *MockEmailSequenceStore {
+	mock := &MockEmailSequenceStore{ctrl: ctrl}
+	mock.recorder = &MockEmailSequenceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailSequenceStore) EXPECT() *MockEmailSequenceStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateEmailSequence mocks base method.
+func (m *MockEmailSequenceStore) CreateEmailSequence(ctx context.Context, params store.CreateEmailSequenceParams) (store.EmailSequence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailSequence", ctx, params)
+	ret0, _ := ret[0].(store.EmailSequence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmailSequence indicates an expected call of CreateEmailSequence.
+func (mr *MockEmailSequenceStoreMockRecorder) CreateEmailSequence(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailSequence", reflect.TypeOf((*MockEmailSequenceStore)(nil).CreateEmailSequence), ctx, params)
+}
+
+// DeleteEmailSequence mocks base method.
+func (m *MockEmailSequenceStore) DeleteEmailSequence(ctx context.Context, sequenceID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEmailSequence", ctx, sequenceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEmailSequence indicates an expected call of DeleteEmailSequence.
+func (mr *MockEmailSequenceStoreMockRecorder) DeleteEmailSequence(ctx, sequenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEmailSequence", reflect.TypeOf((*MockEmailSequenceStore)(nil).DeleteEmailSequence), ctx, sequenceID)
+}
+
+// GetCampaignByID mocks base method.
+func (m *MockEmailSequenceStore) GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignByID", ctx, campaignID)
+	ret0, _ := ret[0].(store.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignByID indicates an expected call of GetCampaignByID.
+func (mr *MockEmailSequenceStoreMockRecorder) GetCampaignByID(ctx, campaignID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignByID", reflect.TypeOf((*MockEmailSequenceStore)(nil).GetCampaignByID), ctx, campaignID)
+}
+
+// GetCampaignEmailTemplateByID mocks base method.
+func (m *MockEmailSequenceStore) GetCampaignEmailTemplateByID(ctx context.Context, templateID uuid.UUID) (store.CampaignEmailTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignEmailTemplateByID", ctx, templateID)
+	ret0, _ := ret[0].(store.CampaignEmailTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignEmailTemplateByID indicates an expected call of GetCampaignEmailTemplateByID.
+func (mr *MockEmailSequenceStoreMockRecorder) GetCampaignEmailTemplateByID(ctx, templateID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignEmailTemplateByID", reflect.TypeOf((*MockEmailSequenceStore)(nil).GetCampaignEmailTemplateByID), ctx, templateID)
+}
+
+// GetEmailSequenceByID mocks base method.
+func (m *MockEmailSequenceStore) GetEmailSequenceByID(ctx context.Context, sequenceID uuid.UUID) (store.EmailSequence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailSequenceByID", ctx, sequenceID)
+	ret0, _ := ret[0].(store.EmailSequence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailSequenceByID indicates an expected call of GetEmailSequenceByID.
+func (mr *MockEmailSequenceStoreMockRecorder) GetEmailSequenceByID(ctx, sequenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailSequenceByID", reflect.TypeOf((*MockEmailSequenceStore)(nil).GetEmailSequenceByID), ctx, sequenceID)
+}
+
+// GetEmailSequenceEnrollmentStats mocks base method.
+func (m *MockEmailSequenceStore) GetEmailSequenceEnrollmentStats(ctx context.Context, sequenceID uuid.UUID) (store.EmailSequenceEnrollmentStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailSequenceEnrollmentStats", ctx, sequenceID)
+	ret0, _ := ret[0].(store.EmailSequenceEnrollmentStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailSequenceEnrollmentStats indicates an expected call of GetEmailSequenceEnrollmentStats.
+func (mr *MockEmailSequenceStoreMockRecorder) GetEmailSequenceEnrollmentStats(ctx, sequenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailSequenceEnrollmentStats", reflect.TypeOf((*MockEmailSequenceStore)(nil).GetEmailSequenceEnrollmentStats), ctx, sequenceID)
+}
+
+// GetEmailSequenceStepStats mocks base method.
+func (m *MockEmailSequenceStore) GetEmailSequenceStepStats(ctx context.Context, sequenceID uuid.UUID) ([]store.EmailSequenceStepStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailSequenceStepStats", ctx, sequenceID)
+	ret0, _ := ret[0].([]store.EmailSequenceStepStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailSequenceStepStats indicates an expected call of GetEmailSequenceStepStats.
+func (mr *MockEmailSequenceStoreMockRecorder) GetEmailSequenceStepStats(ctx, sequenceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailSequenceStepStats", reflect.TypeOf((*MockEmailSequenceStore)(nil).GetEmailSequenceStepStats), ctx, sequenceID)
+}
+
+// GetEmailSequencesByCampaign mocks base method.
+func (m *MockEmailSequenceStore) GetEmailSequencesByCampaign(ctx context.Context, campaignID uuid.UUID) ([]store.EmailSequence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailSequencesByCampaign", ctx, campaignID)
+	ret0, _ := ret[0].([]store.EmailSequence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailSequencesByCampaign indicates an expected call of GetEmailSequencesByCampaign.
+func (mr *MockEmailSequenceStoreMockRecorder) GetEmailSequencesByCampaign(ctx, campaignID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailSequencesByCampaign", reflect.TypeOf((*MockEmailSequenceStore)(nil).GetEmailSequencesByCampaign), ctx, campaignID)
+}
+
+// UpdateEmailSequence mocks base method.
+func (m *MockEmailSequenceStore) UpdateEmailSequence(ctx context.Context, sequenceID uuid.UUID, params store.UpdateEmailSequenceParams) (store.EmailSequence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmailSequence", ctx, sequenceID, params)
+	ret0, _ := ret[0].(store.EmailSequence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEmailSequence indicates an expected call of UpdateEmailSequence.
+func (mr *MockEmailSequenceStoreMockRecorder) UpdateEmailSequence(ctx, sequenceID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmailSequence", reflect.TypeOf((*MockEmailSequenceStore)(nil).UpdateEmailSequence), ctx, sequenceID, params)
+}
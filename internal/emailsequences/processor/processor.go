@@ -0,0 +1,398 @@
+package processor
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=processor.go -destination=mocks_test.go -package=processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// EmailSequenceStore defines the database operations required by EmailSequenceProcessor
+type EmailSequenceStore interface {
+	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
+	GetCampaignEmailTemplateByID(ctx context.Context, templateID uuid.UUID) (store.CampaignEmailTemplate, error)
+	CreateEmailSequence(ctx context.Context, params store.CreateEmailSequenceParams) (store.EmailSequence, error)
+	GetEmailSequenceByID(ctx context.Context, sequenceID uuid.UUID) (store.EmailSequence, error)
+	GetEmailSequencesByCampaign(ctx context.Context, campaignID uuid.UUID) ([]store.EmailSequence, error)
+	UpdateEmailSequence(ctx context.Context, sequenceID uuid.UUID, params store.UpdateEmailSequenceParams) (store.EmailSequence, error)
+	DeleteEmailSequence(ctx context.Context, sequenceID uuid.UUID) error
+	GetEmailSequenceStepStats(ctx context.Context, sequenceID uuid.UUID) ([]store.EmailSequenceStepStats, error)
+	GetEmailSequenceEnrollmentStats(ctx context.Context, sequenceID uuid.UUID) (store.EmailSequenceEnrollmentStats, error)
+}
+
+var (
+	ErrEmailSequenceNotFound = errors.New("email sequence not found")
+	ErrCampaignNotFound      = errors.New("campaign not found")
+	ErrTemplateNotFound      = errors.New("email template not found")
+	ErrUnauthorized          = errors.New("unauthorized access to email sequence")
+	ErrInvalidSequence       = errors.New("invalid email sequence")
+)
+
+const (
+	// maxSequenceSteps is the maximum number of emails in a sequence
+	maxSequenceSteps = 20
+	// maxStepDelayMinutes is the longest a step can wait after the previous one (one year)
+	maxStepDelayMinutes = 365 * 24 * 60
+)
+
+type EmailSequenceProcessor struct {
+	store  EmailSequenceStore
+	logger *observability.Logger
+}
+
+func New(store EmailSequenceStore, logger *observability.Logger) EmailSequenceProcessor {
+	return EmailSequenceProcessor{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// EmailSequenceStepRequest represents one step of a sequence.
+// Condition holds segment filter criteria the user must match for the step to be sent.
+type EmailSequenceStepRequest struct {
+	TemplateID   uuid.UUID
+	DelayMinutes int
+	Condition    map[string]interface{}
+}
+
+// CreateEmailSequenceRequest represents a request to create an email sequence
+type CreateEmailSequenceRequest struct {
+	Name   string
+	Status *string
+	Steps  []EmailSequenceStepRequest
+}
+
+// CreateEmailSequence creates a drip sequence for a campaign
+func (p *EmailSequenceProcessor) CreateEmailSequence(ctx context.Context, accountID, campaignID uuid.UUID, req CreateEmailSequenceRequest) (store.EmailSequence, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+	)
+
+	if err := p.verifyCampaign(ctx, accountID, campaignID); err != nil {
+		return store.EmailSequence{}, err
+	}
+
+	status := string(store.EmailSequenceStatusDraft)
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	steps, err := p.validateSteps(ctx, campaignID, status, req.Steps)
+	if err != nil {
+		return store.EmailSequence{}, err
+	}
+
+	sequence, err := p.store.CreateEmailSequence(ctx, store.CreateEmailSequenceParams{
+		CampaignID: campaignID,
+		Name:       req.Name,
+		Status:     status,
+		Steps:      steps,
+	})
+	if err != nil {
+		p.logger.Error(ctx, "failed to create email sequence", err)
+		return store.EmailSequence{}, err
+	}
+
+	p.logger.Info(ctx, "email sequence created successfully")
+	return sequence, nil
+}
+
+// GetEmailSequence retrieves an email sequence with its steps
+func (p *EmailSequenceProcessor) GetEmailSequence(ctx context.Context, accountID, campaignID, sequenceID uuid.UUID) (store.EmailSequence, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+		observability.Field{Key: "sequence_id", Value: sequenceID.String()},
+	)
+
+	if err := p.verifyCampaign(ctx, accountID, campaignID); err != nil {
+		return store.EmailSequence{}, err
+	}
+
+	return p.getCampaignSequence(ctx, campaignID, sequenceID)
+}
+
+// ListEmailSequences retrieves all email sequences of a campaign
+func (p *EmailSequenceProcessor) ListEmailSequences(ctx context.Context, accountID, campaignID uuid.UUID) ([]store.EmailSequence, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+	)
+
+	if err := p.verifyCampaign(ctx, accountID, campaignID); err != nil {
+		return nil, err
+	}
+
+	sequences, err := p.store.GetEmailSequencesByCampaign(ctx, campaignID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to list email sequences", err)
+		return nil, err
+	}
+
+	if sequences == nil {
+		sequences = []store.EmailSequence{}
+	}
+
+	return sequences, nil
+}
+
+// UpdateEmailSequenceRequest represents a request to update an email sequence.
+// Steps replace the existing steps when not nil.
+type UpdateEmailSequenceRequest struct {
+	Name   *string
+	Status *string
+	Steps  []EmailSequenceStepRequest
+}
+
+// UpdateEmailSequence updates an email sequence. Users already enrolled continue from the step
+// at their current position, so replacing steps applies to their remaining emails.
+func (p *EmailSequenceProcessor) UpdateEmailSequence(ctx context.Context, accountID, campaignID, sequenceID uuid.UUID, req UpdateEmailSequenceRequest) (store.EmailSequence, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+		observability.Field{Key: "sequence_id", Value: sequenceID.String()},
+	)
+
+	if err := p.verifyCampaign(ctx, accountID, campaignID); err != nil {
+		return store.EmailSequence{}, err
+	}
+
+	existing, err := p.getCampaignSequence(ctx, campaignID, sequenceID)
+	if err != nil {
+		return store.EmailSequence{}, err
+	}
+
+	status := existing.Status
+	if req.Status != nil {
+		status = *req.Status
+	}
+
+	params := store.UpdateEmailSequenceParams{
+		Name:   req.Name,
+		Status: req.Status,
+	}
+
+	if req.Steps != nil {
+		params.Steps, err = p.validateSteps(ctx, campaignID, status, req.Steps)
+		if err != nil {
+			return store.EmailSequence{}, err
+		}
+	} else if status == string(store.EmailSequenceStatusActive) && len(existing.Steps) == 0 {
+		return store.EmailSequence{}, fmt.Errorf("%w: an active sequence needs at least one step", ErrInvalidSequence)
+	}
+
+	sequence, err := p.store.UpdateEmailSequence(ctx, sequenceID, params)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.EmailSequence{}, ErrEmailSequenceNotFound
+		}
+		p.logger.Error(ctx, "failed to update email sequence", err)
+		return store.EmailSequence{}, err
+	}
+
+	p.logger.Info(ctx, "email sequence updated successfully")
+	return sequence, nil
+}
+
+// DeleteEmailSequence deletes an email sequence. Enrolled users receive no further emails from it.
+func (p *EmailSequenceProcessor) DeleteEmailSequence(ctx context.Context, accountID, campaignID, sequenceID uuid.UUID) error {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+		observability.Field{Key: "sequence_id", Value: sequenceID.String()},
+	)
+
+	if err := p.verifyCampaign(ctx, accountID, campaignID); err != nil {
+		return err
+	}
+
+	if _, err := p.getCampaignSequence(ctx, campaignID, sequenceID); err != nil {
+		return err
+	}
+
+	err := p.store.DeleteEmailSequence(ctx, sequenceID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrEmailSequenceNotFound
+		}
+		p.logger.Error(ctx, "failed to delete email sequence", err)
+		return err
+	}
+
+	p.logger.Info(ctx, "email sequence deleted successfully")
+	return nil
+}
+
+// EmailSequenceAnalytics represents enrollment counts and per-step results of a sequence
+type EmailSequenceAnalytics struct {
+	SequenceID  uuid.UUID                          `json:"sequence_id"`
+	Enrollments store.EmailSequenceEnrollmentStats `json:"enrollments"`
+	Steps       []EmailSequenceStepAnalytics       `json:"steps"`
+}
+
+// EmailSequenceStepAnalytics represents the results of one step of a sequence
+type EmailSequenceStepAnalytics struct {
+	store.EmailSequenceStepStats
+	OpenRate  float64 `json:"open_rate"`
+	ClickRate float64 `json:"click_rate"`
+}
+
+// GetEmailSequenceAnalytics retrieves enrollment counts and per-step results of a sequence
+func (p *EmailSequenceProcessor) GetEmailSequenceAnalytics(ctx context.Context, accountID, campaignID, sequenceID uuid.UUID) (EmailSequenceAnalytics, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "campaign_id", Value: campaignID.String()},
+		observability.Field{Key: "sequence_id", Value: sequenceID.String()},
+	)
+
+	if err := p.verifyCampaign(ctx, accountID, campaignID); err != nil {
+		return EmailSequenceAnalytics{}, err
+	}
+
+	if _, err := p.getCampaignSequence(ctx, campaignID, sequenceID); err != nil {
+		return EmailSequenceAnalytics{}, err
+	}
+
+	enrollments, err := p.store.GetEmailSequenceEnrollmentStats(ctx, sequenceID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get email sequence enrollment stats", err)
+		return EmailSequenceAnalytics{}, err
+	}
+
+	stepStats, err := p.store.GetEmailSequenceStepStats(ctx, sequenceID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get email sequence step stats", err)
+		return EmailSequenceAnalytics{}, err
+	}
+
+	steps := make([]EmailSequenceStepAnalytics, 0, len(stepStats))
+	for _, s := range stepStats {
+		step := EmailSequenceStepAnalytics{EmailSequenceStepStats: s}
+		if s.Sent > 0 {
+			step.OpenRate = float64(s.Opened) / float64(s.Sent) * 100
+			step.ClickRate = float64(s.Clicked) / float64(s.Sent) * 100
+		}
+		steps = append(steps, step)
+	}
+
+	return EmailSequenceAnalytics{
+		SequenceID:  sequenceID,
+		Enrollments: enrollments,
+		Steps:       steps,
+	}, nil
+}
+
+// verifyCampaign checks that the campaign exists and belongs to the account
+func (p *EmailSequenceProcessor) verifyCampaign(ctx context.Context, accountID, campaignID uuid.UUID) error {
+	campaign, err := p.store.GetCampaignByID(ctx, campaignID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ErrCampaignNotFound
+		}
+		p.logger.Error(ctx, "failed to get campaign", err)
+		return err
+	}
+
+	if campaign.AccountID != accountID {
+		return ErrUnauthorized
+	}
+
+	return nil
+}
+
+// getCampaignSequence retrieves a sequence and checks that it belongs to the campaign
+func (p *EmailSequenceProcessor) getCampaignSequence(ctx context.Context, campaignID, sequenceID uuid.UUID) (store.EmailSequence, error) {
+	sequence, err := p.store.GetEmailSequenceByID(ctx, sequenceID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.EmailSequence{}, ErrEmailSequenceNotFound
+		}
+		p.logger.Error(ctx, "failed to get email sequence", err)
+		return store.EmailSequence{}, err
+	}
+
+	if sequence.CampaignID != campaignID {
+		return store.EmailSequence{}, ErrUnauthorized
+	}
+
+	return sequence, nil
+}
+
+// validateSteps checks the steps of a sequence and converts them to store parameters.
+// Every step must use a template of the campaign.
+func (p *EmailSequenceProcessor) validateSteps(ctx context.Context, campaignID uuid.UUID, status string, steps []EmailSequenceStepRequest) ([]store.EmailSequenceStepParams, error) {
+	if !isValidSequenceStatus(status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidSequence, status)
+	}
+
+	if len(steps) > maxSequenceSteps {
+		return nil, fmt.Errorf("%w: at most %d steps are allowed", ErrInvalidSequence, maxSequenceSteps)
+	}
+
+	if status == string(store.EmailSequenceStatusActive) && len(steps) == 0 {
+		return nil, fmt.Errorf("%w: an active sequence needs at least one step", ErrInvalidSequence)
+	}
+
+	params := make([]store.EmailSequenceStepParams, 0, len(steps))
+	for i, step := range steps {
+		if step.DelayMinutes < 0 || step.DelayMinutes > maxStepDelayMinutes {
+			return nil, fmt.Errorf("%w: step %d delay must be between 0 and %d minutes", ErrInvalidSequence, i+1, maxStepDelayMinutes)
+		}
+
+		template, err := p.store.GetCampaignEmailTemplateByID(ctx, step.TemplateID)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil, ErrTemplateNotFound
+			}
+			p.logger.Error(ctx, "failed to get campaign email template", err)
+			return nil, err
+		}
+
+		if template.CampaignID != campaignID {
+			return nil, ErrTemplateNotFound
+		}
+
+		var condition *store.JSONB
+		if len(step.Condition) > 0 {
+			jsonb := store.JSONB(step.Condition)
+			if err := validateCondition(jsonb); err != nil {
+				return nil, fmt.Errorf("%w: step %d condition: %v", ErrInvalidSequence, i+1, err)
+			}
+			condition = &jsonb
+		}
+
+		params = append(params, store.EmailSequenceStepParams{
+			TemplateID:   step.TemplateID,
+			DelayMinutes: step.DelayMinutes,
+			Condition:    condition,
+		})
+	}
+
+	return params, nil
+}
+
+// validateCondition checks that a step condition is valid segment filter criteria
+func validateCondition(condition store.JSONB) error {
+	criteria, err := store.ParseFilterCriteria(condition)
+	if err != nil {
+		return err
+	}
+	if tree := criteria.Tree(); tree != nil {
+		return tree.Validate()
+	}
+	return nil
+}
+
+func isValidSequenceStatus(status string) bool {
+	switch store.EmailSequenceStatus(status) {
+	case store.EmailSequenceStatusDraft, store.EmailSequenceStatusActive, store.EmailSequenceStatusPaused:
+		return true
+	}
+	return false
+}
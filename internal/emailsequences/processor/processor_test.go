@@ -0,0 +1,183 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateEmailSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailSequenceStore(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	templateID := uuid.New()
+
+	campaign := store.Campaign{ID: campaignID, AccountID: accountID}
+	template := store.CampaignEmailTemplate{ID: templateID, CampaignID: campaignID}
+	active := string(store.EmailSequenceStatusActive)
+
+	t.Run("successfully creates sequence with conditional step", func(t *testing.T) {
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(campaign, nil)
+		mockStore.EXPECT().GetCampaignEmailTemplateByID(gomock.Any(), templateID).Return(template, nil).Times(2)
+		mockStore.EXPECT().
+			CreateEmailSequence(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params store.CreateEmailSequenceParams) (store.EmailSequence, error) {
+				require.Len(t, params.Steps, 2)
+				assert.Equal(t, active, params.Status)
+				assert.Equal(t, 3*24*60, params.Steps[0].DelayMinutes)
+				assert.Nil(t, params.Steps[0].Condition)
+				require.NotNil(t, params.Steps[1].Condition)
+				assert.Equal(t, false, (*params.Steps[1].Condition)["has_referrals"])
+				return store.EmailSequence{ID: uuid.New(), CampaignID: campaignID, Status: params.Status}, nil
+			})
+
+		sequence, err := processor.CreateEmailSequence(ctx, accountID, campaignID, CreateEmailSequenceRequest{
+			Name:   "Onboarding",
+			Status: &active,
+			Steps: []EmailSequenceStepRequest{
+				{TemplateID: templateID, DelayMinutes: 3 * 24 * 60},
+				{TemplateID: templateID, DelayMinutes: 11 * 24 * 60, Condition: map[string]interface{}{"has_referrals": false}},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, active, sequence.Status)
+	})
+
+	t.Run("rejects active sequence without steps", func(t *testing.T) {
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(campaign, nil)
+
+		_, err := processor.CreateEmailSequence(ctx, accountID, campaignID, CreateEmailSequenceRequest{
+			Name:   "Empty",
+			Status: &active,
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidSequence)
+	})
+
+	t.Run("rejects negative delay", func(t *testing.T) {
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(campaign, nil)
+
+		_, err := processor.CreateEmailSequence(ctx, accountID, campaignID, CreateEmailSequenceRequest{
+			Name:  "Negative",
+			Steps: []EmailSequenceStepRequest{{TemplateID: templateID, DelayMinutes: -1}},
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidSequence)
+	})
+
+	t.Run("rejects template from another campaign", func(t *testing.T) {
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(campaign, nil)
+		mockStore.EXPECT().
+			GetCampaignEmailTemplateByID(gomock.Any(), templateID).
+			Return(store.CampaignEmailTemplate{ID: templateID, CampaignID: uuid.New()}, nil)
+
+		_, err := processor.CreateEmailSequence(ctx, accountID, campaignID, CreateEmailSequenceRequest{
+			Name:  "Foreign template",
+			Steps: []EmailSequenceStepRequest{{TemplateID: templateID}},
+		})
+
+		assert.ErrorIs(t, err, ErrTemplateNotFound)
+	})
+
+	t.Run("rejects campaign of another account", func(t *testing.T) {
+		mockStore.EXPECT().
+			GetCampaignByID(gomock.Any(), campaignID).
+			Return(store.Campaign{ID: campaignID, AccountID: uuid.New()}, nil)
+
+		_, err := processor.CreateEmailSequence(ctx, accountID, campaignID, CreateEmailSequenceRequest{Name: "Other"})
+
+		assert.ErrorIs(t, err, ErrUnauthorized)
+	})
+}
+
+func TestUpdateEmailSequence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailSequenceStore(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	sequenceID := uuid.New()
+
+	campaign := store.Campaign{ID: campaignID, AccountID: accountID}
+	active := string(store.EmailSequenceStatusActive)
+
+	t.Run("rejects activating a sequence without steps", func(t *testing.T) {
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(campaign, nil)
+		mockStore.EXPECT().
+			GetEmailSequenceByID(gomock.Any(), sequenceID).
+			Return(store.EmailSequence{ID: sequenceID, CampaignID: campaignID, Status: string(store.EmailSequenceStatusDraft)}, nil)
+
+		_, err := processor.UpdateEmailSequence(ctx, accountID, campaignID, sequenceID, UpdateEmailSequenceRequest{Status: &active})
+
+		assert.ErrorIs(t, err, ErrInvalidSequence)
+	})
+
+	t.Run("returns not found for sequence of another campaign", func(t *testing.T) {
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(campaign, nil)
+		mockStore.EXPECT().
+			GetEmailSequenceByID(gomock.Any(), sequenceID).
+			Return(store.EmailSequence{}, store.ErrNotFound)
+
+		_, err := processor.UpdateEmailSequence(ctx, accountID, campaignID, sequenceID, UpdateEmailSequenceRequest{Status: &active})
+
+		assert.ErrorIs(t, err, ErrEmailSequenceNotFound)
+	})
+}
+
+func TestGetEmailSequenceAnalytics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailSequenceStore(ctrl)
+	logger := observability.NewLogger()
+	processor := New(mockStore, logger)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	sequenceID := uuid.New()
+
+	mockStore.EXPECT().
+		GetCampaignByID(gomock.Any(), campaignID).
+		Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+	mockStore.EXPECT().
+		GetEmailSequenceByID(gomock.Any(), sequenceID).
+		Return(store.EmailSequence{ID: sequenceID, CampaignID: campaignID}, nil)
+	mockStore.EXPECT().
+		GetEmailSequenceEnrollmentStats(gomock.Any(), sequenceID).
+		Return(store.EmailSequenceEnrollmentStats{Enrolled: 10, Active: 6, Exited: 4, Converted: 3}, nil)
+	mockStore.EXPECT().
+		GetEmailSequenceStepStats(gomock.Any(), sequenceID).
+		Return([]store.EmailSequenceStepStats{
+			{Position: 1, Sent: 10, Opened: 5, Clicked: 2},
+			{Position: 2, Skipped: 4},
+		}, nil)
+
+	analytics, err := processor.GetEmailSequenceAnalytics(ctx, accountID, campaignID, sequenceID)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, analytics.Enrollments.Converted)
+	require.Len(t, analytics.Steps, 2)
+	assert.Equal(t, 50.0, analytics.Steps[0].OpenRate)
+	assert.Equal(t, 20.0, analytics.Steps[0].ClickRate)
+	assert.Equal(t, 0.0, analytics.Steps[1].OpenRate)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"base-server/internal/apierrors"
+	"base-server/internal/emailtracking/processor"
+	"base-server/internal/observability"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Svix headers Resend signs its webhooks with
+const (
+	headerSvixID        = "svix-id"
+	headerSvixTimestamp = "svix-timestamp"
+	headerSvixSignature = "svix-signature"
+)
+
+type Handler struct {
+	processor processor.EmailTrackingProcessor
+	logger    *observability.Logger
+}
+
+func New(processor processor.EmailTrackingProcessor, logger *observability.Logger) Handler {
+	return Handler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, processor.ErrWebhookNotConfigured):
+		apierrors.ServiceUnavailable(c, "WEBHOOK_NOT_CONFIGURED", "Email webhooks are not configured", err)
+	case errors.Is(err, processor.ErrInvalidSignature):
+		apierrors.BadRequest(c, "INVALID_SIGNATURE", "invalid webhook signature")
+	case errors.Is(err, processor.ErrInvalidPayload):
+		apierrors.BadRequest(c, "INVALID_INPUT", "invalid webhook payload")
+	default:
+		apierrors.InternalError(c, err)
+	}
+}
+
+// HandleWebhook handles POST /api/email/webhook
+//
+// Receives Resend email events and records opens, clicks and bounces of sent emails
+func (h *Handler) HandleWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		apierrors.BadRequest(c, "INVALID_INPUT", "failed to read request body")
+		return
+	}
+
+	headers := processor.WebhookHeaders{
+		ID:        c.GetHeader(headerSvixID),
+		Timestamp: c.GetHeader(headerSvixTimestamp),
+		Signature: c.GetHeader(headerSvixSignature),
+	}
+
+	if err := h.processor.HandleWebhook(ctx, headers, payload); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "success"})
+}
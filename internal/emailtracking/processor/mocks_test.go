@@ -0,0 +1,142 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: processor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=processor.go -destination=mocks_test.go -package=processor
+//
+
+// Package processor is a generated GoMock package.
+package processor
+
+import (
+	store "base-server/internal/store"
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEmailTrackingStore is a mock of EmailTrackingStore interface.
+type MockEmailTrackingStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailTrackingStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailTrackingStoreMockRecorder is the mock recorder for MockEmailTrackingStore.
+type MockEmailTrackingStoreMockRecorder struct {
+	mock *MockEmailTrackingStore
+}
+
+// NewMockEmailTrackingStore creates a new mock instance.
+func NewMockEmailTrackingStore(ctrl *gomock.Controller) *MockEmailTrackingStore {
+	mock := &MockEmailTrackingStore{ctrl: ctrl}
+	mock.recorder = &MockEmailTrackingStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailTrackingStore) EXPECT() *MockEmailTrackingStoreMockRecorder {
+	return m.recorder
+}
+
+// GetEmailLogByProviderMessageID mocks base method.
+func (m *MockEmailTrackingStore) GetEmailLogByProviderMessageID(ctx context.Context, providerMessageID string) (store.EmailLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailLogByProviderMessageID", ctx, providerMessageID)
+	ret0, _ := ret[0].(store.EmailLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailLogByProviderMessageID indicates an expected call of GetEmailLogByProviderMessageID.
+func (mr *MockEmailTrackingStoreMockRecorder) GetEmailLogByProviderMessageID(ctx, providerMessageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailLogByProviderMessageID", reflect.TypeOf((*MockEmailTrackingStore)(nil).GetEmailLogByProviderMessageID), ctx, providerMessageID)
+}
+
+// IncrementEmailClickCount mocks base method.
+func (m *MockEmailTrackingStore) IncrementEmailClickCount(ctx context.Context, logID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementEmailClickCount", ctx, logID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementEmailClickCount indicates an expected call of IncrementEmailClickCount.
+func (mr *MockEmailTrackingStoreMockRecorder) IncrementEmailClickCount(ctx, logID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementEmailClickCount", reflect.TypeOf((*MockEmailTrackingStore)(nil).IncrementEmailClickCount), ctx, logID)
+}
+
+// IncrementEmailOpenCount mocks base method.
+func (m *MockEmailTrackingStore) IncrementEmailOpenCount(ctx context.Context, logID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementEmailOpenCount", ctx, logID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementEmailOpenCount indicates an expected call of IncrementEmailOpenCount.
+func (mr *MockEmailTrackingStoreMockRecorder) IncrementEmailOpenCount(ctx, logID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementEmailOpenCount", reflect.TypeOf((*MockEmailTrackingStore)(nil).IncrementEmailOpenCount), ctx, logID)
+}
+
+// RecordBlastRecipientBounced mocks base method.
+func (m *MockEmailTrackingStore) RecordBlastRecipientBounced(ctx context.Context, providerMessageID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBlastRecipientBounced", ctx, providerMessageID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBlastRecipientBounced indicates an expected call of RecordBlastRecipientBounced.
+func (mr *MockEmailTrackingStoreMockRecorder) RecordBlastRecipientBounced(ctx, providerMessageID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBlastRecipientBounced", reflect.TypeOf((*MockEmailTrackingStore)(nil).RecordBlastRecipientBounced), ctx, providerMessageID, reason)
+}
+
+// RecordBlastRecipientClicked mocks base method.
+func (m *MockEmailTrackingStore) RecordBlastRecipientClicked(ctx context.Context, providerMessageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBlastRecipientClicked", ctx, providerMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBlastRecipientClicked indicates an expected call of RecordBlastRecipientClicked.
+func (mr *MockEmailTrackingStoreMockRecorder) RecordBlastRecipientClicked(ctx, providerMessageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBlastRecipientClicked", reflect.TypeOf((*MockEmailTrackingStore)(nil).RecordBlastRecipientClicked), ctx, providerMessageID)
+}
+
+// RecordBlastRecipientOpened mocks base method.
+func (m *MockEmailTrackingStore) RecordBlastRecipientOpened(ctx context.Context, providerMessageID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBlastRecipientOpened", ctx, providerMessageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBlastRecipientOpened indicates an expected call of RecordBlastRecipientOpened.
+func (mr *MockEmailTrackingStoreMockRecorder) RecordBlastRecipientOpened(ctx, providerMessageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBlastRecipientOpened", reflect.TypeOf((*MockEmailTrackingStore)(nil).RecordBlastRecipientOpened), ctx, providerMessageID)
+}
+
+// RecordEmailLogBounced mocks base method.
+func (m *MockEmailTrackingStore) RecordEmailLogBounced(ctx context.Context, logID uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEmailLogBounced", ctx, logID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEmailLogBounced indicates an expected call of RecordEmailLogBounced.
+func (mr *MockEmailTrackingStoreMockRecorder) RecordEmailLogBounced(ctx, logID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEmailLogBounced", reflect.TypeOf((*MockEmailTrackingStore)(nil).RecordEmailLogBounced), ctx, logID, reason)
+}
@@ -0,0 +1,185 @@
+package processor
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=processor.go -destination=mocks_test.go -package=processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/DMSAVentures/base-server/pkg/webhookverify"
+	"github.com/google/uuid"
+)
+
+// Resend webhook event types that are recorded
+const (
+	EventEmailOpened  = "email.opened"
+	EventEmailClicked = "email.clicked"
+	EventEmailBounced = "email.bounced"
+)
+
+// EmailTrackingStore defines the database operations required by EmailTrackingProcessor
+type EmailTrackingStore interface {
+	GetEmailLogByProviderMessageID(ctx context.Context, providerMessageID string) (store.EmailLog, error)
+	IncrementEmailOpenCount(ctx context.Context, logID uuid.UUID) error
+	IncrementEmailClickCount(ctx context.Context, logID uuid.UUID) error
+	RecordBlastRecipientOpened(ctx context.Context, providerMessageID string) error
+	RecordBlastRecipientClicked(ctx context.Context, providerMessageID string) error
+	RecordEmailLogBounced(ctx context.Context, logID uuid.UUID, reason string) error
+	RecordBlastRecipientBounced(ctx context.Context, providerMessageID, reason string) error
+}
+
+var (
+	ErrWebhookNotConfigured = errors.New("email webhook secret is not configured")
+	ErrInvalidSignature     = errors.New("invalid webhook signature")
+	ErrInvalidPayload       = errors.New("invalid webhook payload")
+)
+
+type EmailTrackingProcessor struct {
+	store    EmailTrackingStore
+	verifier *webhookverify.Verifier
+	logger   *observability.Logger
+}
+
+// New creates an EmailTrackingProcessor verifying webhooks with the Resend signing secret.
+// Without a valid secret every webhook is rejected.
+func New(store EmailTrackingStore, webhookSecret string, logger *observability.Logger) EmailTrackingProcessor {
+	var verifier *webhookverify.Verifier
+	if webhookSecret != "" {
+		v, err := webhookverify.New(webhookSecret)
+		if err != nil {
+			logger.Error(context.Background(), "invalid Resend webhook secret, email webhooks will be rejected", err)
+		} else {
+			verifier = v
+		}
+	}
+
+	return EmailTrackingProcessor{
+		store:    store,
+		verifier: verifier,
+		logger:   logger,
+	}
+}
+
+// WebhookHeaders holds the Svix signature headers Resend signs its webhooks with
+type WebhookHeaders struct {
+	ID        string
+	Timestamp string
+	Signature string
+}
+
+// resendEvent is the part of a Resend webhook event the processor reads
+type resendEvent struct {
+	Type string `json:"type"`
+	Data struct {
+		EmailID string `json:"email_id"`
+		Bounce  struct {
+			Message string `json:"message"`
+		} `json:"bounce"`
+	} `json:"data"`
+}
+
+// HandleWebhook verifies a Resend webhook and records the open, click or bounce it reports on the
+// email log or blast recipient the message was sent as. Other event types are ignored.
+func (p *EmailTrackingProcessor) HandleWebhook(ctx context.Context, headers WebhookHeaders, body []byte) error {
+	if p.verifier == nil {
+		return ErrWebhookNotConfigured
+	}
+	if err := p.verifier.VerifyStandard(headers.ID, headers.Timestamp, headers.Signature, body); err != nil {
+		p.logger.Warn(ctx, fmt.Sprintf("rejected email webhook: %v", err))
+		return ErrInvalidSignature
+	}
+
+	var event resendEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return ErrInvalidPayload
+	}
+	if event.Data.EmailID == "" {
+		return ErrInvalidPayload
+	}
+
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "email_event_type", Value: event.Type},
+		observability.Field{Key: "provider_message_id", Value: event.Data.EmailID},
+	)
+
+	switch event.Type {
+	case EventEmailOpened:
+		return p.recordOpen(ctx, event.Data.EmailID)
+	case EventEmailClicked:
+		return p.recordClick(ctx, event.Data.EmailID)
+	case EventEmailBounced:
+		return p.recordBounce(ctx, event.Data.EmailID, event.Data.Bounce.Message)
+	default:
+		return nil
+	}
+}
+
+// recordOpen counts an open on the email log of the message, or on its blast recipient
+func (p *EmailTrackingProcessor) recordOpen(ctx context.Context, providerMessageID string) error {
+	emailLog, err := p.store.GetEmailLogByProviderMessageID(ctx, providerMessageID)
+	if err == nil {
+		if err := p.store.IncrementEmailOpenCount(ctx, emailLog.ID); err != nil {
+			p.logger.Error(ctx, "failed to record email open", err)
+			return err
+		}
+		return nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		p.logger.Error(ctx, "failed to get email log", err)
+		return err
+	}
+
+	if err := p.store.RecordBlastRecipientOpened(ctx, providerMessageID); err != nil {
+		p.logger.Error(ctx, "failed to record blast email open", err)
+		return err
+	}
+	return nil
+}
+
+// recordClick counts a click on the email log of the message, or on its blast recipient
+func (p *EmailTrackingProcessor) recordClick(ctx context.Context, providerMessageID string) error {
+	emailLog, err := p.store.GetEmailLogByProviderMessageID(ctx, providerMessageID)
+	if err == nil {
+		if err := p.store.IncrementEmailClickCount(ctx, emailLog.ID); err != nil {
+			p.logger.Error(ctx, "failed to record email click", err)
+			return err
+		}
+		return nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		p.logger.Error(ctx, "failed to get email log", err)
+		return err
+	}
+
+	if err := p.store.RecordBlastRecipientClicked(ctx, providerMessageID); err != nil {
+		p.logger.Error(ctx, "failed to record blast email click", err)
+		return err
+	}
+	return nil
+}
+
+// recordBounce marks the email log of the message, or its blast recipient, as bounced
+func (p *EmailTrackingProcessor) recordBounce(ctx context.Context, providerMessageID, reason string) error {
+	emailLog, err := p.store.GetEmailLogByProviderMessageID(ctx, providerMessageID)
+	if err == nil {
+		if err := p.store.RecordEmailLogBounced(ctx, emailLog.ID, reason); err != nil {
+			p.logger.Error(ctx, "failed to record email bounce", err)
+			return err
+		}
+		return nil
+	}
+	if !errors.Is(err, store.ErrNotFound) {
+		p.logger.Error(ctx, "failed to get email log", err)
+		return err
+	}
+
+	if err := p.store.RecordBlastRecipientBounced(ctx, providerMessageID, reason); err != nil {
+		p.logger.Error(ctx, "failed to record blast email bounce", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+var testSecretKey = []byte("resend-test-secret")
+
+// signedHeaders signs a body the way Resend does with the test secret
+func signedHeaders(body []byte) WebhookHeaders {
+	id := "msg_" + uuid.NewString()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, testSecretKey)
+	mac.Write([]byte(id + "." + timestamp + "." + string(body)))
+	return WebhookHeaders{
+		ID:        id,
+		Timestamp: timestamp,
+		Signature: "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	}
+}
+
+func TestHandleWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEmailTrackingStore(ctrl)
+	secret := "whsec_" + base64.StdEncoding.EncodeToString(testSecretKey)
+	processor := New(mockStore, secret, observability.NewLogger())
+
+	ctx := context.Background()
+
+	t.Run("records open on the email log of the message", func(t *testing.T) {
+		body := []byte(`{"type":"email.opened","data":{"email_id":"re_log"}}`)
+		logID := uuid.New()
+
+		mockStore.EXPECT().GetEmailLogByProviderMessageID(gomock.Any(), "re_log").Return(store.EmailLog{ID: logID}, nil)
+		mockStore.EXPECT().IncrementEmailOpenCount(gomock.Any(), logID).Return(nil)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("records click on the blast recipient when there is no email log", func(t *testing.T) {
+		body := []byte(`{"type":"email.clicked","data":{"email_id":"re_blast"}}`)
+
+		mockStore.EXPECT().GetEmailLogByProviderMessageID(gomock.Any(), "re_blast").Return(store.EmailLog{}, store.ErrNotFound)
+		mockStore.EXPECT().RecordBlastRecipientClicked(gomock.Any(), "re_blast").Return(nil)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("records open on the blast recipient when there is no email log", func(t *testing.T) {
+		body := []byte(`{"type":"email.opened","data":{"email_id":"re_blast"}}`)
+
+		mockStore.EXPECT().GetEmailLogByProviderMessageID(gomock.Any(), "re_blast").Return(store.EmailLog{}, store.ErrNotFound)
+		mockStore.EXPECT().RecordBlastRecipientOpened(gomock.Any(), "re_blast").Return(nil)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("records bounce on the email log of the message", func(t *testing.T) {
+		body := []byte(`{"type":"email.bounced","data":{"email_id":"re_log","bounce":{"message":"mailbox does not exist"}}}`)
+		logID := uuid.New()
+
+		mockStore.EXPECT().GetEmailLogByProviderMessageID(gomock.Any(), "re_log").Return(store.EmailLog{ID: logID}, nil)
+		mockStore.EXPECT().RecordEmailLogBounced(gomock.Any(), logID, "mailbox does not exist").Return(nil)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("records bounce on the blast recipient when there is no email log", func(t *testing.T) {
+		body := []byte(`{"type":"email.bounced","data":{"email_id":"re_blast","bounce":{"message":"mailbox full"}}}`)
+
+		mockStore.EXPECT().GetEmailLogByProviderMessageID(gomock.Any(), "re_blast").Return(store.EmailLog{}, store.ErrNotFound)
+		mockStore.EXPECT().RecordBlastRecipientBounced(gomock.Any(), "re_blast", "mailbox full").Return(nil)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("ignores other event types", func(t *testing.T) {
+		body := []byte(`{"type":"email.delivered","data":{"email_id":"re_log"}}`)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("rejects invalid signature", func(t *testing.T) {
+		body := []byte(`{"type":"email.opened","data":{"email_id":"re_log"}}`)
+		headers := signedHeaders([]byte(`{"type":"email.clicked","data":{"email_id":"re_log"}}`))
+
+		err := processor.HandleWebhook(ctx, headers, body)
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("rejects payload without email id", func(t *testing.T) {
+		body := []byte(`{"type":"email.opened","data":{}}`)
+
+		err := processor.HandleWebhook(ctx, signedHeaders(body), body)
+
+		assert.ErrorIs(t, err, ErrInvalidPayload)
+	})
+}
+
+func TestHandleWebhook_NotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	processor := New(NewMockEmailTrackingStore(ctrl), "", observability.NewLogger())
+	body := []byte(`{"type":"email.opened","data":{"email_id":"re_log"}}`)
+
+	err := processor.HandleWebhook(context.Background(), signedHeaders(body), body)
+
+	assert.ErrorIs(t, err, ErrWebhookNotConfigured)
+}
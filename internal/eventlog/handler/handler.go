@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"base-server/internal/apierrors"
+	"base-server/internal/eventlog/processor"
+	"base-server/internal/observability"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	processor processor.EventLogProcessor
+	logger    *observability.Logger
+}
+
+func New(processor processor.EventLogProcessor, logger *observability.Logger) Handler {
+	return Handler{
+		processor: processor,
+		logger:    logger,
+	}
+}
+
+func (h *Handler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, processor.ErrEventNotFound):
+		apierrors.NotFound(c, "Event not found")
+	case errors.Is(err, processor.ErrWebhookNotFound):
+		apierrors.NotFound(c, "Webhook not found")
+	case errors.Is(err, processor.ErrWebhookInactive):
+		apierrors.Conflict(c, "WEBHOOK_INACTIVE", "Webhook must be active to replay events")
+	case errors.Is(err, processor.ErrInvalidEventType):
+		apierrors.BadRequest(c, "INVALID_EVENT_TYPE", err.Error())
+	case errors.Is(err, processor.ErrInvalidTimeRange):
+		apierrors.BadRequest(c, "INVALID_TIME_RANGE", err.Error())
+	case errors.Is(err, processor.ErrReplayTooLarge):
+		apierrors.BadRequest(c, "REPLAY_TOO_LARGE", err.Error())
+	default:
+		apierrors.InternalError(c, err)
+	}
+}
+
+// HandleListEvents handles GET /api/v1/events
+//
+// Query parameters: type (repeatable or comma-separated), campaign_id, from and to (RFC 3339),
+// after (cursor from a previous page) and limit.
+func (h *Handler) HandleListEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	req := processor.ListEventsRequest{
+		Types: parseListQuery(c, "type"),
+	}
+
+	if campaignIDStr := c.Query("campaign_id"); campaignIDStr != "" {
+		campaignID, err := uuid.Parse(campaignIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+			return
+		}
+		req.CampaignID = &campaignID
+	}
+
+	if req.From, err = parseTimeQuery(c, "from"); err != nil {
+		apierrors.BadRequest(c, "INVALID_TIME_RANGE", "from must be an RFC 3339 timestamp")
+		return
+	}
+	if req.To, err = parseTimeQuery(c, "to"); err != nil {
+		apierrors.BadRequest(c, "INVALID_TIME_RANGE", "to must be an RFC 3339 timestamp")
+		return
+	}
+
+	if afterStr := c.Query("after"); afterStr != "" {
+		req.After, err = strconv.ParseInt(afterStr, 10, 64)
+		if err != nil || req.After < 0 {
+			apierrors.BadRequest(c, "INVALID_CURSOR", "after must be a cursor returned by a previous page")
+			return
+		}
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		req.Limit, _ = strconv.Atoi(limitStr)
+	}
+
+	page, err := h.processor.ListEvents(ctx, accountID, req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// HandleGetEvent handles GET /api/v1/events/:event_id
+func (h *Handler) HandleGetEvent(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get event ID from path
+	eventIDStr := c.Param("event_id")
+	eventID, err := uuid.Parse(eventIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse event ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event id"})
+		return
+	}
+
+	event, err := h.processor.GetEvent(ctx, accountID, eventID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// ReplayEventsRequest represents the HTTP request for replaying events to a webhook
+type ReplayEventsRequest struct {
+	WebhookID  uuid.UUID `json:"webhook_id" binding:"required"`
+	From       time.Time `json:"from" binding:"required"`
+	To         time.Time `json:"to" binding:"required"`
+	EventTypes []string  `json:"event_types,omitempty" binding:"omitempty,max=50"`
+}
+
+// HandleReplayEvents handles POST /api/v1/events/replay
+func (h *Handler) HandleReplayEvents(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	var req ReplayEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	result, err := h.processor.ReplayEvents(ctx, accountID, processor.ReplayRequest{
+		WebhookID:  req.WebhookID,
+		From:       req.From,
+		To:         req.To,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
+
+// parseListQuery reads a query parameter given repeatedly or as a comma-separated list
+func parseListQuery(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// parseTimeQuery reads an optional RFC 3339 timestamp query parameter
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, error) {
+	valStr := c.Query(key)
+	if valStr == "" {
+		return nil, nil
+	}
+	val, err := time.Parse(time.RFC3339, valStr)
+	if err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
@@ -0,0 +1,92 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
+	"base-server/internal/workers"
+
+	"github.com/google/uuid"
+)
+
+// EventRecorder implements the EventProcessor interface for the event log.
+// It appends every public event to the events table.
+type EventRecorder struct {
+	store  EventLogStore
+	logger *observability.Logger
+}
+
+// NewEventRecorder creates a new event log recorder.
+func NewEventRecorder(store EventLogStore, logger *observability.Logger) workers.EventProcessor {
+	return &EventRecorder{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Name returns the processor name for logging and metrics.
+func (r *EventRecorder) Name() string {
+	return "eventlog"
+}
+
+// Process records a single event from Kafka.
+// Internal events are skipped. Recording is idempotent on the event ID, so redelivered events are
+// recorded once. Returns an error if recording fails, which prevents offset commit and enables replay.
+func (r *EventRecorder) Process(ctx context.Context, event workers.EventMessage) error {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "event_id", Value: event.ID},
+		observability.Field{Key: "event_type", Value: event.Type},
+		observability.Field{Key: "account_id", Value: event.AccountID},
+	)
+
+	if !events.IsPublicEvent(event.Type) {
+		return nil
+	}
+
+	eventID, err := uuid.Parse(event.ID)
+	if err != nil {
+		r.logger.Error(ctx, "Invalid event id", err)
+		return fmt.Errorf("invalid event id: %w", err)
+	}
+
+	accountID, err := uuid.Parse(event.AccountID)
+	if err != nil {
+		r.logger.Error(ctx, "Invalid account_id in event", err)
+		return fmt.Errorf("invalid account_id: %w", err)
+	}
+
+	var campaignID *uuid.UUID
+	if event.CampaignID != nil {
+		parsed, err := uuid.Parse(*event.CampaignID)
+		if err != nil {
+			r.logger.Error(ctx, "Invalid campaign_id in event", err)
+			return fmt.Errorf("invalid campaign_id: %w", err)
+		}
+		campaignID = &parsed
+	}
+
+	occurredAt, err := time.Parse(time.RFC3339, event.Timestamp)
+	if err != nil {
+		r.logger.Warn(ctx, "Invalid event timestamp, recording with the current time")
+		occurredAt = time.Now().UTC()
+	}
+
+	err = r.store.RecordEvent(ctx, store.RecordEventParams{
+		ID:         eventID,
+		AccountID:  accountID,
+		CampaignID: campaignID,
+		Type:       event.Type,
+		Data:       store.JSONB(event.Data),
+		OccurredAt: occurredAt,
+	})
+	if err != nil {
+		r.logger.Error(ctx, "Failed to record event", err)
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	return nil
+}
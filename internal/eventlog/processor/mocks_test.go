@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: processor.go
+//
+// Generated by this command:
+//
+//	mockgen -source=processor.go -destination=mocks_test.go -package=processor
+//
+
+// Package processor is a generated GoMock package.
+package processor
+
+import (
+	store "base-server/internal/store"
+	context "context"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockEventLogStore is a mock of EventLogStore interface.
+type MockEventLogStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventLogStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockEventLogStoreMockRecorder is the mock recorder for MockEventLogStore.
+type MockEventLogStoreMockRecorder struct {
+	mock *MockEventLogStore
+}
+
+// NewMockEventLogStore creates a new mock instance.
+func NewMockEventLogStore(ctrl *gomock.Controller) *MockEventLogStore {
+	mock := &MockEventLogStore{ctrl: ctrl}
+	mock.recorder = &MockEventLogStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventLogStore) EXPECT() *MockEventLogStoreMockRecorder {
+	return m.recorder
+}
+
+// CreateWebhookDelivery mocks base method.
+func (m *MockEventLogStore) CreateWebhookDelivery(ctx context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, params)
+	ret0, _ := ret[0].(store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockEventLogStoreMockRecorder) CreateWebhookDelivery(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockEventLogStore)(nil).CreateWebhookDelivery), ctx, params)
+}
+
+// GetEventByID mocks base method.
+func (m *MockEventLogStore) GetEventByID(ctx context.Context, eventID uuid.UUID) (store.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, eventID)
+	ret0, _ := ret[0].(store.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventByID indicates an expected call of GetEventByID.
+func (mr *MockEventLogStoreMockRecorder) GetEventByID(ctx, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockEventLogStore)(nil).GetEventByID), ctx, eventID)
+}
+
+// GetWebhookByID mocks base method.
+func (m *MockEventLogStore) GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookByID", ctx, webhookID)
+	ret0, _ := ret[0].(store.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookByID indicates an expected call of GetWebhookByID.
+func (mr *MockEventLogStoreMockRecorder) GetWebhookByID(ctx, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockEventLogStore)(nil).GetWebhookByID), ctx, webhookID)
+}
+
+// ListEvents mocks base method.
+func (m *MockEventLogStore) ListEvents(ctx context.Context, params store.ListEventsParams) ([]store.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEvents", ctx, params)
+	ret0, _ := ret[0].([]store.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEvents indicates an expected call of ListEvents.
+func (mr *MockEventLogStoreMockRecorder) ListEvents(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEvents", reflect.TypeOf((*MockEventLogStore)(nil).ListEvents), ctx, params)
+}
+
+// RecordEvent mocks base method.
+func (m *MockEventLogStore) RecordEvent(ctx context.Context, params store.RecordEventParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEvent", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordEvent indicates an expected call of RecordEvent.
+func (mr *MockEventLogStoreMockRecorder) RecordEvent(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEvent", reflect.TypeOf((*MockEventLogStore)(nil).RecordEvent), ctx, params)
+}
@@ -0,0 +1,290 @@
+package processor
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=processor.go -destination=mocks_test.go -package=processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
+	"base-server/internal/webhooks/subscription"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+
+	// MaxReplayEvents is the largest number of events a single replay may deliver
+	MaxReplayEvents = 10000
+
+	// replayPageSize is the number of events read from the log per query during a replay
+	replayPageSize = 500
+)
+
+// EventLogStore defines the database operations required by EventLogProcessor
+type EventLogStore interface {
+	RecordEvent(ctx context.Context, params store.RecordEventParams) error
+	GetEventByID(ctx context.Context, eventID uuid.UUID) (store.Event, error)
+	ListEvents(ctx context.Context, params store.ListEventsParams) ([]store.Event, error)
+	GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error)
+	CreateWebhookDelivery(ctx context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error)
+}
+
+var (
+	ErrEventNotFound    = errors.New("event not found")
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrWebhookInactive  = errors.New("webhook is not active")
+	ErrInvalidEventType = errors.New("invalid event type")
+	ErrInvalidTimeRange = errors.New("invalid time range")
+	ErrReplayTooLarge   = errors.New("replay matches too many events")
+)
+
+type EventLogProcessor struct {
+	store  EventLogStore
+	logger *observability.Logger
+}
+
+func New(store EventLogStore, logger *observability.Logger) EventLogProcessor {
+	return EventLogProcessor{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// ListEventsRequest represents filters for listing an account's events
+type ListEventsRequest struct {
+	Types      []string
+	CampaignID *uuid.UUID
+	From       *time.Time
+	To         *time.Time
+	After      int64
+	Limit      int
+}
+
+// EventPage is a page of the event log in sequence order
+type EventPage struct {
+	Events     []store.Event `json:"events"`
+	NextCursor *int64        `json:"next_cursor,omitempty"`
+	HasMore    bool          `json:"has_more"`
+}
+
+// ListEvents lists an account's events in the order they were recorded.
+// The sequence number of the last event of a page is the cursor for the next page.
+func (p *EventLogProcessor) ListEvents(ctx context.Context, accountID uuid.UUID, req ListEventsRequest) (EventPage, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+	)
+
+	if err := validateFilters(req.Types, req.From, req.To); err != nil {
+		return EventPage{}, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+
+	// Fetch one extra event to know whether there is another page
+	evts, err := p.store.ListEvents(ctx, store.ListEventsParams{
+		AccountID:     accountID,
+		Types:         req.Types,
+		CampaignID:    req.CampaignID,
+		From:          req.From,
+		To:            req.To,
+		AfterSequence: req.After,
+		Limit:         limit + 1,
+	})
+	if err != nil {
+		p.logger.Error(ctx, "failed to list events", err)
+		return EventPage{}, err
+	}
+
+	page := EventPage{Events: evts}
+	if len(evts) > limit {
+		page.Events = evts[:limit]
+		page.HasMore = true
+		cursor := page.Events[limit-1].Sequence
+		page.NextCursor = &cursor
+	}
+	if page.Events == nil {
+		page.Events = []store.Event{}
+	}
+
+	return page, nil
+}
+
+// GetEvent retrieves one of an account's events
+func (p *EventLogProcessor) GetEvent(ctx context.Context, accountID, eventID uuid.UUID) (store.Event, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "event_id", Value: eventID.String()},
+	)
+
+	event, err := p.store.GetEventByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.Event{}, ErrEventNotFound
+		}
+		p.logger.Error(ctx, "failed to get event", err)
+		return store.Event{}, err
+	}
+
+	// Events of other accounts are reported as missing
+	if event.AccountID != accountID {
+		return store.Event{}, ErrEventNotFound
+	}
+
+	return event, nil
+}
+
+// ReplayRequest represents a request to re-deliver a time range of events to a webhook
+type ReplayRequest struct {
+	WebhookID  uuid.UUID
+	From       time.Time
+	To         time.Time
+	EventTypes []string
+}
+
+// ReplayResult summarizes a replay
+type ReplayResult struct {
+	WebhookID     uuid.UUID `json:"webhook_id"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	EventsQueued  int       `json:"events_queued"`
+	EventsSkipped int       `json:"events_skipped"`
+}
+
+// ReplayEvents re-delivers the account's events that occurred in [From, To) to a webhook.
+// Only events the webhook subscribes to and that meet its filters, and for campaign webhooks only
+// that campaign's events, are replayed. Each event is queued as a pending delivery with its original event ID, and is
+// sent by the webhook retry worker with the usual retries.
+func (p *EventLogProcessor) ReplayEvents(ctx context.Context, accountID uuid.UUID, req ReplayRequest) (ReplayResult, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "account_id", Value: accountID.String()},
+		observability.Field{Key: "webhook_id", Value: req.WebhookID.String()},
+	)
+
+	if err := validateFilters(req.EventTypes, &req.From, &req.To); err != nil {
+		return ReplayResult{}, err
+	}
+
+	webhook, err := p.store.GetWebhookByID(ctx, req.WebhookID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return ReplayResult{}, ErrWebhookNotFound
+		}
+		p.logger.Error(ctx, "failed to get webhook", err)
+		return ReplayResult{}, err
+	}
+	if webhook.AccountID != accountID || webhook.DeletedAt != nil {
+		return ReplayResult{}, ErrWebhookNotFound
+	}
+	if webhook.Status != "active" {
+		return ReplayResult{}, ErrWebhookInactive
+	}
+
+	types := replayEventTypes(webhook.Events, req.EventTypes)
+	result := ReplayResult{
+		WebhookID: webhook.ID,
+		From:      req.From,
+		To:        req.To,
+	}
+	if len(types) == 0 {
+		return result, nil
+	}
+
+	// Read the whole range before queueing anything so an oversized replay queues nothing
+	var replay []store.Event
+	var after int64
+	for {
+		page, err := p.store.ListEvents(ctx, store.ListEventsParams{
+			AccountID:     accountID,
+			Types:         types,
+			From:          &req.From,
+			To:            &req.To,
+			AfterSequence: after,
+			Limit:         replayPageSize,
+		})
+		if err != nil {
+			p.logger.Error(ctx, "failed to list events for replay", err)
+			return ReplayResult{}, err
+		}
+
+		for _, event := range page {
+			// Campaign scope and filters apply as in dispatch
+			if !subscription.Matches(webhook, event.Type, event.CampaignID, event.Data) {
+				continue
+			}
+			replay = append(replay, event)
+		}
+		if len(replay) > MaxReplayEvents {
+			return ReplayResult{}, fmt.Errorf("%w: narrow the time range to at most %d events", ErrReplayTooLarge, MaxReplayEvents)
+		}
+		if len(page) < replayPageSize {
+			break
+		}
+		after = page[len(page)-1].Sequence
+	}
+
+	now := time.Now()
+	for _, event := range replay {
+		eventID := event.ID
+		_, err := p.store.CreateWebhookDelivery(ctx, store.CreateWebhookDeliveryParams{
+			WebhookID:   webhook.ID,
+			EventID:     &eventID,
+			TriggeredBy: store.WebhookDeliveryTriggerReplay,
+			EventType:   event.Type,
+			Payload:     event.Data,
+			NextRetryAt: &now,
+		})
+		if err != nil {
+			p.logger.Error(ctx, fmt.Sprintf("failed to queue replay of event %s", event.ID), err)
+			result.EventsSkipped++
+			continue
+		}
+		result.EventsQueued++
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("queued replay of %d events (%d skipped)", result.EventsQueued, result.EventsSkipped))
+
+	return result, nil
+}
+
+// replayEventTypes returns the event types a webhook subscribes to, exactly or by pattern,
+// narrowed to the requested types when any are given
+func replayEventTypes(subscribed, requested []string) []string {
+	if len(requested) == 0 {
+		return subscription.ExpandPatterns(subscribed)
+	}
+
+	var types []string
+	for _, eventType := range requested {
+		if subscription.SubscribesTo(subscribed, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	return types
+}
+
+// validateFilters checks event type and time range filters
+func validateFilters(types []string, from, to *time.Time) error {
+	for _, eventType := range types {
+		if !events.IsPublicEvent(eventType) {
+			return fmt.Errorf("%w: %s", ErrInvalidEventType, eventType)
+		}
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidTimeRange)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestListEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEventLogStore(ctrl)
+	processor := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+
+	t.Run("returns cursor when there is another page", func(t *testing.T) {
+		mockStore.EXPECT().
+			ListEvents(gomock.Any(), store.ListEventsParams{
+				AccountID:     accountID,
+				Types:         []string{"user.created"},
+				AfterSequence: 10,
+				Limit:         3,
+			}).
+			Return([]store.Event{{Sequence: 11}, {Sequence: 12}, {Sequence: 14}}, nil)
+
+		page, err := processor.ListEvents(ctx, accountID, ListEventsRequest{
+			Types: []string{"user.created"},
+			After: 10,
+			Limit: 2,
+		})
+
+		require.NoError(t, err)
+		assert.Len(t, page.Events, 2)
+		assert.True(t, page.HasMore)
+		require.NotNil(t, page.NextCursor)
+		assert.Equal(t, int64(12), *page.NextCursor)
+	})
+
+	t.Run("returns last page without cursor", func(t *testing.T) {
+		mockStore.EXPECT().ListEvents(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		page, err := processor.ListEvents(ctx, accountID, ListEventsRequest{})
+
+		require.NoError(t, err)
+		assert.NotNil(t, page.Events)
+		assert.False(t, page.HasMore)
+		assert.Nil(t, page.NextCursor)
+	})
+
+	t.Run("rejects unknown event types", func(t *testing.T) {
+		_, err := processor.ListEvents(ctx, accountID, ListEventsRequest{Types: []string{"blast.started"}})
+
+		assert.ErrorIs(t, err, ErrInvalidEventType)
+	})
+
+	t.Run("rejects inverted time range", func(t *testing.T) {
+		from := time.Now()
+		to := from.Add(-time.Hour)
+
+		_, err := processor.ListEvents(ctx, accountID, ListEventsRequest{From: &from, To: &to})
+
+		assert.ErrorIs(t, err, ErrInvalidTimeRange)
+	})
+}
+
+func TestGetEvent_OtherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEventLogStore(ctrl)
+	processor := New(mockStore, observability.NewLogger())
+
+	eventID := uuid.New()
+	mockStore.EXPECT().GetEventByID(gomock.Any(), eventID).Return(store.Event{ID: eventID, AccountID: uuid.New()}, nil)
+
+	_, err := processor.GetEvent(context.Background(), uuid.New(), eventID)
+
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestReplayEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockEventLogStore(ctrl)
+	processor := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	otherCampaignID := uuid.New()
+	webhookID := uuid.New()
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+
+	webhook := store.Webhook{
+		ID:         webhookID,
+		AccountID:  accountID,
+		CampaignID: &campaignID,
+		Events:     []string{"user.created", "user.verified"},
+		Status:     "active",
+	}
+
+	t.Run("queues subscribed events of the webhook's campaign with their event IDs", func(t *testing.T) {
+		campaignEvent := store.Event{ID: uuid.New(), Sequence: 1, CampaignID: &campaignID, Type: "user.created"}
+		accountEvent := store.Event{ID: uuid.New(), Sequence: 2, Type: "user.created"}
+		otherEvent := store.Event{ID: uuid.New(), Sequence: 3, CampaignID: &otherCampaignID, Type: "user.created"}
+
+		mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(webhook, nil)
+		mockStore.EXPECT().
+			ListEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params store.ListEventsParams) ([]store.Event, error) {
+				// Requested types are narrowed to the webhook's subscriptions
+				assert.Equal(t, []string{"user.created"}, params.Types)
+				assert.Equal(t, from, *params.From)
+				assert.Equal(t, to, *params.To)
+				return []store.Event{campaignEvent, accountEvent, otherEvent}, nil
+			})
+
+		var queued []uuid.UUID
+		mockStore.EXPECT().
+			CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+				assert.Equal(t, webhookID, params.WebhookID)
+				assert.NotNil(t, params.NextRetryAt)
+				assert.Equal(t, store.WebhookDeliveryTriggerReplay, params.TriggeredBy)
+				queued = append(queued, *params.EventID)
+				return store.WebhookDelivery{ID: uuid.New()}, nil
+			}).
+			Times(2)
+
+		result, err := processor.ReplayEvents(ctx, accountID, ReplayRequest{
+			WebhookID:  webhookID,
+			From:       from,
+			To:         to,
+			EventTypes: []string{"user.created", "user.deleted"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.EventsQueued)
+		assert.Equal(t, []uuid.UUID{campaignEvent.ID, accountEvent.ID}, queued)
+	})
+
+	t.Run("returns not found for another account's webhook", func(t *testing.T) {
+		mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(store.Webhook{ID: webhookID, AccountID: uuid.New(), Status: "active"}, nil)
+
+		_, err := processor.ReplayEvents(ctx, accountID, ReplayRequest{WebhookID: webhookID, From: from, To: to})
+
+		assert.ErrorIs(t, err, ErrWebhookNotFound)
+	})
+
+	t.Run("rejects paused webhooks", func(t *testing.T) {
+		paused := webhook
+		paused.Status = "paused"
+		mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(paused, nil)
+
+		_, err := processor.ReplayEvents(ctx, accountID, ReplayRequest{WebhookID: webhookID, From: from, To: to})
+
+		assert.ErrorIs(t, err, ErrWebhookInactive)
+	})
+
+	t.Run("queues nothing when the range exceeds the replay limit", func(t *testing.T) {
+		mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(webhook, nil)
+
+		var sequence int64
+		mockStore.EXPECT().
+			ListEvents(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, params store.ListEventsParams) ([]store.Event, error) {
+				page := make([]store.Event, params.Limit)
+				for i := range page {
+					sequence++
+					page[i] = store.Event{ID: uuid.New(), Sequence: sequence, CampaignID: &campaignID, Type: "user.created"}
+				}
+				return page, nil
+			}).
+			AnyTimes()
+
+		_, err := processor.ReplayEvents(ctx, accountID, ReplayRequest{WebhookID: webhookID, From: from, To: to})
+
+		assert.ErrorIs(t, err, ErrReplayTooLarge)
+	})
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"base-server/internal/integrations"
 	"base-server/internal/observability"
+	"base-server/internal/outbound"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -18,12 +19,11 @@ type ZapierDeliverer struct {
 	logger *observability.Logger
 }
 
-// NewZapierDeliverer creates a new ZapierDeliverer
-func NewZapierDeliverer(logger *observability.Logger) *ZapierDeliverer {
+// NewZapierDeliverer creates a new ZapierDeliverer. Events are sent with a client enforcing the
+// outbound network policy.
+func NewZapierDeliverer(logger *observability.Logger, policy outbound.Policy) *ZapierDeliverer {
 	return &ZapierDeliverer{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: outbound.NewClient(policy, 30*time.Second),
 		logger: logger,
 	}
 }
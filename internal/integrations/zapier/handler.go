@@ -350,35 +350,7 @@ func (h *Handler) HandleListCampaigns(c *gin.Context) {
 
 // isValidEventType checks if an event type is valid for subscription
 func isValidEventType(eventType string) bool {
-	validEvents := []string{
-		webhookEvents.EventUserCreated,
-		webhookEvents.EventUserUpdated,
-		webhookEvents.EventUserVerified,
-		webhookEvents.EventUserDeleted,
-		webhookEvents.EventUserPositionChanged,
-		webhookEvents.EventUserConverted,
-		webhookEvents.EventReferralCreated,
-		webhookEvents.EventReferralVerified,
-		webhookEvents.EventReferralConverted,
-		webhookEvents.EventRewardEarned,
-		webhookEvents.EventRewardDelivered,
-		webhookEvents.EventRewardRedeemed,
-		webhookEvents.EventCampaignMilestone,
-		webhookEvents.EventCampaignLaunched,
-		webhookEvents.EventCampaignCompleted,
-		webhookEvents.EventEmailSent,
-		webhookEvents.EventEmailDelivered,
-		webhookEvents.EventEmailOpened,
-		webhookEvents.EventEmailClicked,
-		webhookEvents.EventEmailBounced,
-	}
-
-	for _, valid := range validEvents {
-		if eventType == valid {
-			return true
-		}
-	}
-	return false
+	return webhookEvents.IsPublicEvent(eventType)
 }
 
 // ===== Management Endpoints (JWT authenticated, for the UI) =====
@@ -524,6 +496,10 @@ func (h *Handler) HandleDisconnect(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// zapierPayloadVersion is the event payload version of Zapier deliveries, which carry the event
+// data as published
+const zapierPayloadVersion = 1
+
 // getSampleDataForEvent returns sample data for a given event type
 // Returns nil for unknown event types
 func getSampleDataForEvent(eventType string) map[string]interface{} {
@@ -532,89 +508,17 @@ func getSampleDataForEvent(eventType string) map[string]interface{} {
 		return nil
 	}
 
-	basePayload := map[string]interface{}{
-		"id":          "evt_" + uuid.New().String()[:8],
-		"event":       eventType,
-		"occurred_at": time.Now().UTC().Format(time.RFC3339),
-		"account_id":  "acc_" + uuid.New().String()[:8],
-		"campaign_id": "cmp_" + uuid.New().String()[:8],
+	data := webhookEvents.SampleData(eventType, zapierPayloadVersion)
+	if data == nil {
+		return nil
 	}
 
-	switch eventType {
-	case webhookEvents.EventUserCreated, webhookEvents.EventUserVerified, webhookEvents.EventUserUpdated:
-		basePayload["data"] = map[string]interface{}{
-			"user": map[string]interface{}{
-				"id":             "usr_" + uuid.New().String()[:8],
-				"email":          "john.doe@example.com",
-				"first_name":     "John",
-				"last_name":      "Doe",
-				"position":       42,
-				"referral_code":  "JOHN123",
-				"referral_count": 5,
-				"points":         150,
-				"status":         "verified",
-				"created_at":     time.Now().Add(-24 * time.Hour).UTC().Format(time.RFC3339),
-			},
-		}
-
-	case webhookEvents.EventUserPositionChanged:
-		basePayload["data"] = map[string]interface{}{
-			"user": map[string]interface{}{
-				"id":       "usr_" + uuid.New().String()[:8],
-				"email":    "john.doe@example.com",
-				"position": 35,
-			},
-			"old_position": 42,
-			"new_position": 35,
-		}
-
-	case webhookEvents.EventReferralCreated, webhookEvents.EventReferralVerified:
-		basePayload["data"] = map[string]interface{}{
-			"referral": map[string]interface{}{
-				"id":           "ref_" + uuid.New().String()[:8],
-				"referrer_id":  "usr_" + uuid.New().String()[:8],
-				"referred_id":  "usr_" + uuid.New().String()[:8],
-				"status":       "verified",
-				"points_award": 25,
-				"created_at":   time.Now().Add(-1 * time.Hour).UTC().Format(time.RFC3339),
-			},
-		}
-
-	case webhookEvents.EventRewardEarned, webhookEvents.EventRewardDelivered:
-		basePayload["data"] = map[string]interface{}{
-			"reward": map[string]interface{}{
-				"id":          "rwd_" + uuid.New().String()[:8],
-				"name":        "Early Access",
-				"description": "Get early access to the product",
-				"type":        "access",
-			},
-			"user": map[string]interface{}{
-				"id":    "usr_" + uuid.New().String()[:8],
-				"email": "john.doe@example.com",
-			},
-		}
-
-	case webhookEvents.EventCampaignMilestone:
-		basePayload["data"] = map[string]interface{}{
-			"milestone":     1000,
-			"total_signups": 1000,
-		}
-
-	case webhookEvents.EventEmailSent, webhookEvents.EventEmailOpened, webhookEvents.EventEmailClicked:
-		basePayload["data"] = map[string]interface{}{
-			"email": map[string]interface{}{
-				"id":        "eml_" + uuid.New().String()[:8],
-				"to":        "john.doe@example.com",
-				"subject":   "Welcome to the waitlist!",
-				"template":  "welcome",
-				"opened_at": time.Now().UTC().Format(time.RFC3339),
-			},
-		}
-
-	default:
-		// For valid events without specific sample data, return generic data
-		basePayload["data"] = map[string]interface{}{}
+	return map[string]interface{}{
+		"id":          uuid.New().String(),
+		"event":       eventType,
+		"occurred_at": time.Now().UTC().Format(time.RFC3339),
+		"account_id":  uuid.New().String(),
+		"campaign_id": data["campaign_id"],
+		"data":        data,
 	}
-
-	return basePayload
 }
@@ -0,0 +1,65 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewClient creates an HTTP client that enforces the policy. Hosts are resolved once per
+// connection and the connection is made to the checked addresses, so a DNS answer cannot change
+// between the check and the dial. Proxies from the environment are ignored, and every redirect is
+// checked like the original URL.
+func NewClient(policy Policy, timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = policy.dialContext(&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	})
+
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     transport,
+		CheckRedirect: policy.checkRedirect,
+	}
+}
+
+// dialContext resolves the host being dialed, checks its addresses and connects to the first
+// address that accepts the connection
+func (p Policy) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		ips, err := p.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialErr error
+		for _, ip := range ips {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = errors.Join(dialErr, err)
+		}
+
+		return nil, dialErr
+	}
+}
+
+// checkRedirect limits the number of redirects and applies the scheme policy to their targets.
+// Their addresses are checked when they are dialed.
+func (p Policy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > p.MaxRedirects {
+		return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, p.MaxRedirects)
+	}
+	return p.checkURL(req.URL)
+}
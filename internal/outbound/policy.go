@@ -0,0 +1,165 @@
+// Package outbound provides the HTTP client used to call user-supplied URLs, such as webhook and
+// Zapier endpoints. Every destination is resolved and checked against the outbound network policy
+// before it is dialed, so tenants cannot reach internal services through the platform.
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrInvalidURL       = errors.New("invalid url")
+	ErrInsecureURL      = errors.New("url must use https")
+	ErrBlockedAddress   = errors.New("url resolves to a blocked address")
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// blockedRanges are the non-public ranges not covered by the net.IP classification methods
+var blockedRanges = mustParseCIDRs(
+	"0.0.0.0/8",       // "this" network
+	"100.64.0.0/10",   // carrier-grade NAT
+	"192.0.0.0/24",    // IETF protocol assignments
+	"192.0.2.0/24",    // documentation
+	"198.18.0.0/15",   // benchmarking
+	"198.51.100.0/24", // documentation
+	"203.0.113.0/24",  // documentation
+	"240.0.0.0/4",     // reserved
+	"64:ff9b::/96",    // NAT64, embeds IPv4 addresses
+	"2001:db8::/32",   // documentation
+)
+
+// Policy restricts the destinations of outbound requests
+type Policy struct {
+	// AllowedHosts lists host names, IPs and CIDR ranges that may be reached even though they are
+	// not public, for local development
+	AllowedHosts []string
+	// RequireHTTPS rejects plain HTTP URLs and redirects
+	RequireHTTPS bool
+	// MaxRedirects is the number of redirects followed, none when 0
+	MaxRedirects int
+}
+
+// ValidateURL checks that a URL may be called under the policy: it must be an absolute HTTP(S)
+// URL whose host resolves to allowed addresses only.
+func (p Policy) ValidateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	if err := p.checkURL(u); err != nil {
+		return err
+	}
+
+	_, err = p.resolve(ctx, u.Hostname())
+	return err
+}
+
+// checkURL checks the scheme and host of a URL without resolving it
+func (p Policy) checkURL(u *url.URL) error {
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if p.RequireHTTPS {
+			return ErrInsecureURL
+		}
+	default:
+		return fmt.Errorf("%w: scheme must be http or https", ErrInvalidURL)
+	}
+
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+
+	return nil
+}
+
+// resolve looks up the addresses of a host and returns them if all of them are allowed. Checking
+// every address keeps a host from mixing public and internal records.
+func (p Policy) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		if !p.isAllowedIP(ip) {
+			return nil, fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+		}
+		return []net.IP{ip}, nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to resolve %s: %v", ErrInvalidURL, host, err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("%w: %s has no addresses", ErrInvalidURL, host)
+	}
+
+	hostAllowed := p.isAllowedHost(host)
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		if !hostAllowed && !p.isAllowedIP(addr.IP) {
+			return nil, fmt.Errorf("%w: %s resolves to %s", ErrBlockedAddress, host, addr.IP)
+		}
+		ips = append(ips, addr.IP)
+	}
+
+	return ips, nil
+}
+
+// isAllowedHost reports whether a host name is explicitly allowed
+func (p Policy) isAllowedHost(host string) bool {
+	for _, allowed := range p.AllowedHosts {
+		if strings.EqualFold(allowed, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAllowedIP reports whether an address is public or explicitly allowed
+func (p Policy) isAllowedIP(ip net.IP) bool {
+	for _, allowed := range p.AllowedHosts {
+		if _, network, err := net.ParseCIDR(allowed); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+		} else if allowedIP := net.ParseIP(allowed); allowedIP != nil && allowedIP.Equal(ip) {
+			return true
+		}
+	}
+
+	return IsPublicIP(ip)
+}
+
+// IsPublicIP reports whether an address is publicly routable, meaning it is not loopback, private,
+// link-local, multicast, unspecified or otherwise reserved
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range blockedRanges {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,97 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		url     string
+		wantErr error
+	}{
+		{name: "public https", url: "https://93.184.216.34/hook"},
+		{name: "public http", url: "http://93.184.216.34/hook"},
+		{name: "http when https required", policy: Policy{RequireHTTPS: true}, url: "http://93.184.216.34/hook", wantErr: ErrInsecureURL},
+		{name: "unsupported scheme", url: "ftp://93.184.216.34/hook", wantErr: ErrInvalidURL},
+		{name: "missing host", url: "https:///hook", wantErr: ErrInvalidURL},
+		{name: "loopback", url: "http://127.0.0.1:8080/hook", wantErr: ErrBlockedAddress},
+		{name: "ipv6 loopback", url: "http://[::1]/hook", wantErr: ErrBlockedAddress},
+		{name: "cloud metadata", url: "http://169.254.169.254/latest/meta-data", wantErr: ErrBlockedAddress},
+		{name: "rfc1918", url: "https://10.1.2.3/hook", wantErr: ErrBlockedAddress},
+		{name: "rfc1918 172.16/12", url: "https://172.20.0.1/hook", wantErr: ErrBlockedAddress},
+		{name: "carrier-grade nat", url: "https://100.64.0.1/hook", wantErr: ErrBlockedAddress},
+		{name: "unspecified", url: "http://0.0.0.0/hook", wantErr: ErrBlockedAddress},
+		{name: "ipv4-mapped loopback", url: "http://[::ffff:127.0.0.1]/hook", wantErr: ErrBlockedAddress},
+		{name: "unique local ipv6", url: "https://[fd00::1]/hook", wantErr: ErrBlockedAddress},
+		{name: "allowed ip", policy: Policy{AllowedHosts: []string{"127.0.0.1"}}, url: "http://127.0.0.1:8080/hook"},
+		{name: "allowed cidr", policy: Policy{AllowedHosts: []string{"10.0.0.0/8"}}, url: "https://10.1.2.3/hook"},
+		{name: "outside allowed cidr", policy: Policy{AllowedHosts: []string{"10.0.0.0/8"}}, url: "https://192.168.1.1/hook", wantErr: ErrBlockedAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.ValidateURL(context.Background(), tt.url)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestClient_BlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(Policy{}, 5*time.Second)
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Fatalf("expected ErrBlockedAddress, got %v", err)
+	}
+}
+
+func TestClient_AllowlistedAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(Policy{AllowedHosts: []string{"127.0.0.1"}}, 5*time.Second)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestClient_LimitsRedirects(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, server.URL+"/next", http.StatusFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(Policy{AllowedHosts: []string{"127.0.0.1"}, MaxRedirects: 2}, 5*time.Second)
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrTooManyRedirects) {
+		t.Fatalf("expected ErrTooManyRedirects, got %v", err)
+	}
+}
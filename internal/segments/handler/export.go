@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"base-server/internal/apierrors"
+	"base-server/internal/store"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CreateSegmentExportRequest represents the HTTP request for exporting a segment
+type CreateSegmentExportRequest struct {
+	Format string `json:"format,omitempty" binding:"omitempty,oneof=csv json"`
+}
+
+// SegmentExportResponse is a segment export with the link to download its file once completed
+type SegmentExportResponse struct {
+	store.SegmentExport
+	DownloadURL *string `json:"download_url,omitempty"`
+}
+
+func newSegmentExportResponse(export store.SegmentExport) SegmentExportResponse {
+	response := SegmentExportResponse{SegmentExport: export}
+	if export.Status == string(store.SegmentExportStatusCompleted) {
+		url := fmt.Sprintf("/api/v1/campaigns/%s/segments/%s/exports/%s/download", export.CampaignID, export.SegmentID, export.ID)
+		response.DownloadURL = &url
+	}
+	return response
+}
+
+// HandleCreateSegmentExport handles POST /api/v1/campaigns/:campaign_id/segments/:segment_id/exports
+func (h *Handler) HandleCreateSegmentExport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+
+	var req CreateSegmentExportRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierrors.ValidationError(c, err)
+			return
+		}
+	}
+
+	export, err := h.processor.CreateSegmentExport(ctx, accountID, campaignID, segmentID, store.SegmentExportFormat(req.Format))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newSegmentExportResponse(export))
+}
+
+// HandleGetSegmentExport handles GET /api/v1/campaigns/:campaign_id/segments/:segment_id/exports/:export_id
+func (h *Handler) HandleGetSegmentExport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+
+	// Get export ID from path
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse export ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export id"})
+		return
+	}
+
+	export, err := h.processor.GetSegmentExport(ctx, accountID, campaignID, segmentID, exportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSegmentExportResponse(export))
+}
+
+// HandleDownloadSegmentExport handles GET /api/v1/campaigns/:campaign_id/segments/:segment_id/exports/:export_id/download
+func (h *Handler) HandleDownloadSegmentExport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+
+	// Get export ID from path
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse export ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid export id"})
+		return
+	}
+
+	export, content, err := h.processor.GetSegmentExportFile(ctx, accountID, campaignID, segmentID, exportID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	contentType := "text/csv"
+	if export.Format == string(store.SegmentExportFormatJSON) {
+		contentType = "application/json"
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="segment-%s-%s.%s"`, segmentID, export.CreatedAt.UTC().Format("20060102"), export.Format))
+	c.Data(http.StatusOK, contentType, content)
+}
@@ -36,9 +36,23 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 	case errors.Is(err, processor.ErrUnauthorized):
 		apierrors.Forbidden(c, "FORBIDDEN", "You do not have access to this segment")
 	case errors.Is(err, processor.ErrInvalidCriteria):
-		apierrors.BadRequest(c, "INVALID_FILTER_CRITERIA", "Invalid filter criteria")
+		apierrors.BadRequest(c, "INVALID_FILTER_CRITERIA", err.Error())
+	case errors.Is(err, processor.ErrSegmentNotStatic):
+		apierrors.BadRequest(c, "SEGMENT_NOT_STATIC", "Members can only be managed on static segments")
+	case errors.Is(err, processor.ErrNoMembers):
+		apierrors.BadRequest(c, "NO_MEMBERS", "No members provided")
+	case errors.Is(err, processor.ErrInvalidThreshold):
+		apierrors.BadRequest(c, "INVALID_SIZE_THRESHOLD", err.Error())
+	case errors.Is(err, processor.ErrExportNotFound):
+		apierrors.NotFound(c, "Segment export not found")
+	case errors.Is(err, processor.ErrExportNotReady):
+		apierrors.Conflict(c, "EXPORT_NOT_READY", "Segment export has not completed")
+	case errors.Is(err, processor.ErrInvalidFormat):
+		apierrors.BadRequest(c, "INVALID_FORMAT", "Export format must be csv or json")
 	case errors.Is(err, processor.ErrSegmentInUse):
 		apierrors.Conflict(c, "SEGMENT_IN_USE", "Segment is in use by an email blast and cannot be deleted")
+	case errors.Is(err, processor.ErrSegmentInAudience):
+		apierrors.Conflict(c, "SEGMENT_IN_AUDIENCE", "Segment is used by an audience and cannot be deleted; remove it from the audience first")
 	default:
 		apierrors.InternalError(c, err)
 	}
@@ -56,13 +70,18 @@ type FilterCriteriaRequest struct {
 	DateFrom      *string           `json:"date_from,omitempty"`
 	DateTo        *string           `json:"date_to,omitempty"`
 	CustomFields  map[string]string `json:"custom_fields,omitempty"`
+	// Rules is a nested AND/OR/NOT filter tree combined with the flat criteria using AND
+	Rules *store.SegmentFilterNode `json:"rules,omitempty"`
 }
 
 // CreateSegmentRequest represents the HTTP request for creating a segment
 type CreateSegmentRequest struct {
 	Name           string                `json:"name" binding:"required,max=255"`
 	Description    *string               `json:"description,omitempty"`
-	FilterCriteria FilterCriteriaRequest `json:"filter_criteria" binding:"required"`
+	Type           string                `json:"type,omitempty" binding:"omitempty,oneof=dynamic static"`
+	FilterCriteria FilterCriteriaRequest `json:"filter_criteria"`
+	SizeThreshold  *int                  `json:"size_threshold,omitempty" binding:"omitempty,min=1"`
+	SyncEnabled    bool                  `json:"sync_enabled,omitempty"`
 }
 
 // HandleCreateSegment handles POST /api/v1/campaigns/:campaign_id/segments
@@ -108,7 +127,10 @@ func (h *Handler) HandleCreateSegment(c *gin.Context) {
 	processorReq := processor.CreateSegmentRequest{
 		Name:           req.Name,
 		Description:    req.Description,
+		Type:           store.SegmentType(req.Type),
 		FilterCriteria: filterCriteria,
+		SizeThreshold:  req.SizeThreshold,
+		SyncEnabled:    req.SyncEnabled,
 	}
 
 	segment, err := h.processor.CreateSegment(ctx, accountID, campaignID, processorReq)
@@ -213,6 +235,8 @@ type UpdateSegmentRequest struct {
 	Description    *string                `json:"description,omitempty"`
 	FilterCriteria *FilterCriteriaRequest `json:"filter_criteria,omitempty"`
 	Status         *string                `json:"status,omitempty" binding:"omitempty,oneof=active archived"`
+	SizeThreshold  *int                   `json:"size_threshold,omitempty" binding:"omitempty,min=0"` // 0 removes the threshold
+	SyncEnabled    *bool                  `json:"sync_enabled,omitempty"`
 }
 
 // HandleUpdateSegment handles PUT /api/v1/campaigns/:campaign_id/segments/:segment_id
@@ -258,9 +282,11 @@ func (h *Handler) HandleUpdateSegment(c *gin.Context) {
 	}
 
 	processorReq := processor.UpdateSegmentRequest{
-		Name:        req.Name,
-		Description: req.Description,
-		Status:      req.Status,
+		Name:          req.Name,
+		Description:   req.Description,
+		Status:        req.Status,
+		SizeThreshold: req.SizeThreshold,
+		SyncEnabled:   req.SyncEnabled,
 	}
 
 	// Convert filter criteria if provided
@@ -441,6 +467,52 @@ func (h *Handler) HandleRefreshSegmentCount(c *gin.Context) {
 	})
 }
 
+// HandleGetSegmentGrowth handles GET /api/v1/campaigns/:campaign_id/segments/:segment_id/growth
+func (h *Handler) HandleGetSegmentGrowth(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	days := ParseIntQuery(c, "days", 30)
+
+	growth, err := h.processor.GetSegmentGrowth(ctx, accountID, campaignID, segmentID, days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, growth)
+}
+
 // Helper function to convert HTTP request filter criteria to store model
 func convertFilterCriteria(req FilterCriteriaRequest) (store.SegmentFilterCriteria, error) {
 	criteria := store.SegmentFilterCriteria{
@@ -452,6 +524,7 @@ func convertFilterCriteria(req FilterCriteriaRequest) (store.SegmentFilterCriter
 		MinPosition:   req.MinPosition,
 		MaxPosition:   req.MaxPosition,
 		CustomFields:  req.CustomFields,
+		Rules:         req.Rules,
 	}
 
 	// Parse date strings
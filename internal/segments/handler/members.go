@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"base-server/internal/apierrors"
+	"base-server/internal/segments/processor"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportEmails is the maximum number of emails accepted in one static segment CSV upload
+const maxImportEmails = 50000
+
+// SegmentMembersRequest represents the HTTP request for adding or removing static segment members
+type SegmentMembersRequest struct {
+	UserIDs []uuid.UUID `json:"user_ids" binding:"required,min=1,max=10000"`
+}
+
+// HandleListSegmentMembers handles GET /api/v1/campaigns/:campaign_id/segments/:segment_id/members
+func (h *Handler) HandleListSegmentMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	limit := ParseIntQuery(c, "limit", 50)
+	offset := ParseIntQuery(c, "offset", 0)
+
+	page, err := h.processor.ListSegmentMembers(ctx, accountID, campaignID, segmentID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// HandleAddSegmentMembers handles POST /api/v1/campaigns/:campaign_id/segments/:segment_id/members
+func (h *Handler) HandleAddSegmentMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	var req SegmentMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	change, err := h.processor.AddSegmentMembers(ctx, accountID, campaignID, segmentID, req.UserIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"added":      change.Changed,
+		"user_count": change.UserCount,
+	})
+}
+
+// HandleRemoveSegmentMembers handles POST /api/v1/campaigns/:campaign_id/segments/:segment_id/members/remove
+func (h *Handler) HandleRemoveSegmentMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	var req SegmentMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	change, err := h.processor.RemoveSegmentMembers(ctx, accountID, campaignID, segmentID, req.UserIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"removed":    change.Changed,
+		"user_count": change.UserCount,
+	})
+}
+
+// HandleImportSegmentMembers handles POST /api/v1/campaigns/:campaign_id/segments/:segment_id/members/import
+// The request is a multipart form with a CSV "file" that has an email column.
+func (h *Handler) HandleImportSegmentMembers(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		h.logger.Error(ctx, "failed to get file from request", err)
+		apierrors.BadRequest(c, "FILE_REQUIRED", "File is required")
+		return
+	}
+	defer file.Close()
+
+	emails, err := readEmailsFromCSV(file)
+	if err != nil {
+		apierrors.BadRequest(c, "INVALID_CSV", err.Error())
+		return
+	}
+
+	result, err := h.processor.ImportSegmentMembers(ctx, accountID, campaignID, segmentID, emails)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SnapshotSegmentRequest represents the HTTP request for snapshotting a segment
+type SnapshotSegmentRequest struct {
+	Name        *string `json:"name,omitempty" binding:"omitempty,max=255"`
+	Description *string `json:"description,omitempty"`
+}
+
+// HandleSnapshotSegment handles POST /api/v1/campaigns/:campaign_id/segments/:segment_id/snapshot
+func (h *Handler) HandleSnapshotSegment(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	var req SnapshotSegmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierrors.ValidationError(c, err)
+			return
+		}
+	}
+
+	snapshot, err := h.processor.SnapshotSegment(ctx, accountID, campaignID, segmentID, processor.SnapshotSegmentRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// HandleGetSegmentMembershipHistory handles GET /api/v1/campaigns/:campaign_id/segments/:segment_id/history
+func (h *Handler) HandleGetSegmentMembershipHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context
+	accountIDStr, exists := c.Get("Account-ID")
+	if !exists {
+		apierrors.Unauthorized(c, "account ID not found in context")
+		return
+	}
+
+	accountID, err := uuid.Parse(accountIDStr.(string))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse account ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account id"})
+		return
+	}
+
+	// Get campaign ID from path
+	campaignIDStr := c.Param("campaign_id")
+	campaignID, err := uuid.Parse(campaignIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse campaign ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign id"})
+		return
+	}
+
+	// Get segment ID from path
+	segmentIDStr := c.Param("segment_id")
+	segmentID, err := uuid.Parse(segmentIDStr)
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse segment ID", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid segment id"})
+		return
+	}
+	limit := ParseIntQuery(c, "limit", 50)
+	offset := ParseIntQuery(c, "offset", 0)
+
+	events, err := h.processor.GetSegmentMembershipHistory(ctx, accountID, campaignID, segmentID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// readEmailsFromCSV reads the email column of an uploaded CSV file
+func readEmailsFromCSV(file io.Reader) ([]string, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("failed to read CSV headers")
+	}
+
+	emailIdx := -1
+	for i, header := range headers {
+		if strings.ToLower(strings.TrimSpace(header)) == "email" {
+			emailIdx = i
+			break
+		}
+	}
+	if emailIdx == -1 {
+		return nil, errors.New("email column is required")
+	}
+
+	var emails []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("failed to read CSV row")
+		}
+
+		if len(record) <= emailIdx || strings.TrimSpace(record[emailIdx]) == "" {
+			continue
+		}
+
+		if len(emails) >= maxImportEmails {
+			return nil, errors.New("CSV has too many rows")
+		}
+		emails = append(emails, record[emailIdx])
+	}
+
+	return emails, nil
+}
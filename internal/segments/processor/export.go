@@ -0,0 +1,81 @@
+package processor
+
+import (
+	"base-server/internal/store"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// CreateSegmentExport requests an export of a segment's current users as a CSV or JSON file.
+// The file is rendered in the background; poll the export until it is completed.
+func (p *SegmentProcessor) CreateSegmentExport(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, format store.SegmentExportFormat) (store.SegmentExport, error) {
+	segment, err := p.GetSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return store.SegmentExport{}, err
+	}
+
+	if format == "" {
+		format = store.SegmentExportFormatCSV
+	}
+	if format != store.SegmentExportFormatCSV && format != store.SegmentExportFormatJSON {
+		return store.SegmentExport{}, ErrInvalidFormat
+	}
+
+	export, err := p.store.CreateSegmentExport(ctx, store.CreateSegmentExportParams{
+		AccountID:  accountID,
+		CampaignID: campaignID,
+		SegmentID:  segment.ID,
+		Format:     string(format),
+	})
+	if err != nil {
+		p.logger.Error(ctx, "failed to create segment export", err)
+		return store.SegmentExport{}, err
+	}
+
+	p.logger.Info(ctx, "segment export requested")
+	return export, nil
+}
+
+// GetSegmentExport retrieves a segment export
+func (p *SegmentProcessor) GetSegmentExport(ctx context.Context, accountID, campaignID, segmentID, exportID uuid.UUID) (store.SegmentExport, error) {
+	export, err := p.store.GetSegmentExportByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.SegmentExport{}, ErrExportNotFound
+		}
+		p.logger.Error(ctx, "failed to get segment export", err)
+		return store.SegmentExport{}, err
+	}
+
+	if export.AccountID != accountID || export.CampaignID != campaignID || export.SegmentID != segmentID {
+		return store.SegmentExport{}, ErrExportNotFound
+	}
+
+	return export, nil
+}
+
+// GetSegmentExportFile retrieves the file of a completed segment export
+func (p *SegmentProcessor) GetSegmentExportFile(ctx context.Context, accountID, campaignID, segmentID, exportID uuid.UUID) (store.SegmentExport, []byte, error) {
+	export, err := p.GetSegmentExport(ctx, accountID, campaignID, segmentID, exportID)
+	if err != nil {
+		return store.SegmentExport{}, nil, err
+	}
+
+	if export.Status != string(store.SegmentExportStatusCompleted) {
+		return store.SegmentExport{}, nil, ErrExportNotReady
+	}
+
+	content, err := p.store.GetSegmentExportContent(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			// Expired exports are kept until the export worker deletes them
+			return store.SegmentExport{}, nil, ErrExportNotFound
+		}
+		p.logger.Error(ctx, "failed to get segment export content", err)
+		return store.SegmentExport{}, nil, err
+	}
+
+	return export, content, nil
+}
@@ -0,0 +1,97 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSegmentExportFile(t *testing.T) {
+	t.Parallel()
+
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+	exportID := uuid.New()
+
+	tests := []struct {
+		name    string
+		export  store.SegmentExport
+		setup   func(*MockSegmentStore)
+		wantErr error
+	}{
+		{
+			name: "completed export",
+			export: store.SegmentExport{
+				ID: exportID, AccountID: accountID, CampaignID: campaignID, SegmentID: segmentID,
+				Format: "csv", Status: string(store.SegmentExportStatusCompleted),
+			},
+			setup: func(m *MockSegmentStore) {
+				m.EXPECT().GetSegmentExportContent(gomock.Any(), exportID).Return([]byte("id,email\n"), nil)
+			},
+		},
+		{
+			name: "pending export",
+			export: store.SegmentExport{
+				ID: exportID, AccountID: accountID, CampaignID: campaignID, SegmentID: segmentID,
+				Format: "csv", Status: string(store.SegmentExportStatusPending),
+			},
+			wantErr: ErrExportNotReady,
+		},
+		{
+			name: "export of another account",
+			export: store.SegmentExport{
+				ID: exportID, AccountID: uuid.New(), CampaignID: campaignID, SegmentID: segmentID,
+				Format: "csv", Status: string(store.SegmentExportStatusCompleted),
+			},
+			wantErr: ErrExportNotFound,
+		},
+		{
+			name: "expired export",
+			export: store.SegmentExport{
+				ID: exportID, AccountID: accountID, CampaignID: campaignID, SegmentID: segmentID,
+				Format: "json", Status: string(store.SegmentExportStatusCompleted),
+			},
+			setup: func(m *MockSegmentStore) {
+				m.EXPECT().GetSegmentExportContent(gomock.Any(), exportID).Return(nil, store.ErrNotFound)
+			},
+			wantErr: ErrExportNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockStore := NewMockSegmentStore(ctrl)
+			p := New(mockStore, observability.NewLogger())
+
+			mockStore.EXPECT().GetSegmentExportByID(gomock.Any(), exportID).Return(tt.export, nil)
+			if tt.setup != nil {
+				tt.setup(mockStore)
+			}
+
+			_, content, err := p.GetSegmentExportFile(context.Background(), accountID, campaignID, segmentID, exportID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(content) != "id,email\n" {
+				t.Errorf("unexpected content %q", content)
+			}
+		})
+	}
+}
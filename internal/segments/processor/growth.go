@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultGrowthDays = 30
+	maxGrowthDays     = 365
+)
+
+// SegmentGrowthPoint is a segment's user count on a day
+type SegmentGrowthPoint struct {
+	Date      string `json:"date"`
+	UserCount int    `json:"user_count"`
+}
+
+// SegmentGrowth is a segment's daily size over a period
+type SegmentGrowth struct {
+	SegmentID     uuid.UUID            `json:"segment_id"`
+	Days          int                  `json:"days"`
+	CurrentCount  int                  `json:"current_count"`
+	StartCount    int                  `json:"start_count"`
+	Change        int                  `json:"change"`
+	ChangePercent *float64             `json:"change_percent,omitempty"`
+	SizeThreshold *int                 `json:"size_threshold,omitempty"`
+	Points        []SegmentGrowthPoint `json:"points"`
+}
+
+// GetSegmentGrowth retrieves a segment's daily size over the last days days.
+// Days without a recorded count carry the previous day's count; the series starts at the first
+// recorded day within the period.
+func (p *SegmentProcessor) GetSegmentGrowth(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, days int) (SegmentGrowth, error) {
+	segment, err := p.GetSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return SegmentGrowth{}, err
+	}
+
+	if days <= 0 {
+		days = defaultGrowthDays
+	}
+	if days > maxGrowthDays {
+		days = maxGrowthDays
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	sizes, err := p.store.GetSegmentSizeHistory(ctx, segment.ID, since)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get segment size history", err)
+		return SegmentGrowth{}, err
+	}
+
+	growth := SegmentGrowth{
+		SegmentID:     segment.ID,
+		Days:          days,
+		CurrentCount:  segment.CachedUserCount,
+		SizeThreshold: segment.SizeThreshold,
+		Points:        []SegmentGrowthPoint{},
+	}
+
+	if len(sizes) == 0 {
+		growth.StartCount = segment.CachedUserCount
+		return growth, nil
+	}
+
+	counts := make(map[string]int, len(sizes))
+	for _, size := range sizes {
+		counts[size.Date.UTC().Format("2006-01-02")] = size.UserCount
+	}
+
+	first := sizes[0].Date.UTC().Truncate(24 * time.Hour)
+	last := sizes[0].UserCount
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if count, ok := counts[date]; ok {
+			last = count
+		}
+		growth.Points = append(growth.Points, SegmentGrowthPoint{Date: date, UserCount: last})
+	}
+
+	growth.StartCount = sizes[0].UserCount
+	growth.Change = growth.CurrentCount - growth.StartCount
+	if growth.StartCount > 0 {
+		percent := float64(growth.Change) / float64(growth.StartCount) * 100
+		growth.ChangePercent = &percent
+	}
+
+	return growth, nil
+}
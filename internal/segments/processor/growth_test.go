@@ -0,0 +1,107 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetSegmentGrowth(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockSegmentStore(ctrl)
+	p := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+	mockStore.EXPECT().GetSegmentByID(gomock.Any(), segmentID).
+		Return(store.Segment{ID: segmentID, CampaignID: campaignID, CachedUserCount: 150}, nil)
+	mockStore.EXPECT().GetSegmentSizeHistory(gomock.Any(), segmentID, today.AddDate(0, 0, -6)).
+		Return([]store.SegmentSize{
+			{SegmentID: segmentID, Date: today.AddDate(0, 0, -3), UserCount: 100},
+			{SegmentID: segmentID, Date: today.AddDate(0, 0, -1), UserCount: 140},
+			{SegmentID: segmentID, Date: today, UserCount: 150},
+		}, nil)
+
+	growth, err := p.GetSegmentGrowth(ctx, accountID, campaignID, segmentID, 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The series starts at the first recorded day and carries counts over missing days
+	expected := []int{100, 100, 140, 150}
+	if len(growth.Points) != len(expected) {
+		t.Fatalf("expected %d points, got %+v", len(expected), growth.Points)
+	}
+	for i, count := range expected {
+		if growth.Points[i].UserCount != count {
+			t.Errorf("point %d: expected %d users, got %d", i, count, growth.Points[i].UserCount)
+		}
+	}
+	if growth.Points[0].Date != today.AddDate(0, 0, -3).Format("2006-01-02") {
+		t.Errorf("unexpected first date %s", growth.Points[0].Date)
+	}
+
+	if growth.StartCount != 100 || growth.Change != 50 {
+		t.Errorf("unexpected growth: %+v", growth)
+	}
+	if growth.ChangePercent == nil || *growth.ChangePercent != 50 {
+		t.Errorf("expected 50%% change, got %v", growth.ChangePercent)
+	}
+}
+
+func TestCreateSegment_AboveSizeThreshold(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockSegmentStore(ctrl)
+	p := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+	threshold := 10
+	minReferrals := 5
+
+	mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+	mockStore.EXPECT().CreateSegment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params store.CreateSegmentParams) (store.Segment, error) {
+			if params.SizeThreshold == nil || *params.SizeThreshold != threshold {
+				t.Errorf("expected size threshold %d, got %v", threshold, params.SizeThreshold)
+			}
+			return store.Segment{ID: segmentID, CampaignID: campaignID, SizeThreshold: params.SizeThreshold}, nil
+		})
+	mockStore.EXPECT().CountUsersMatchingCriteria(gomock.Any(), campaignID, gomock.Any()).Return(25, nil)
+	mockStore.EXPECT().UpdateSegmentCachedCount(gomock.Any(), segmentID, 25).Return(nil)
+	// Already above the threshold at creation, so the scheduled refresh must not report a crossing
+	mockStore.EXPECT().UpdateSegmentThresholdReached(gomock.Any(), segmentID, true).Return(nil)
+
+	segment, err := p.CreateSegment(ctx, accountID, campaignID, CreateSegmentRequest{
+		Name:           "Power referrers",
+		FilterCriteria: store.SegmentFilterCriteria{MinReferrals: &minReferrals},
+		SizeThreshold:  &threshold,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !segment.SizeThresholdReached || segment.CachedUserCount != 25 {
+		t.Errorf("unexpected segment: %+v", segment)
+	}
+}
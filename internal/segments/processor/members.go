@@ -0,0 +1,293 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// membershipBatchSize is the number of users added or removed per query
+const membershipBatchSize = 1000
+
+// MembershipChange is the result of adding or removing static segment members
+type MembershipChange struct {
+	Changed   int `json:"changed"`
+	UserCount int `json:"user_count"`
+}
+
+// AddSegmentMembers adds waitlist users to a static segment by user ID
+func (p *SegmentProcessor) AddSegmentMembers(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, userIDs []uuid.UUID) (MembershipChange, error) {
+	segment, err := p.getStaticSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return MembershipChange{}, err
+	}
+	if len(userIDs) == 0 {
+		return MembershipChange{}, ErrNoMembers
+	}
+
+	added, err := p.addMembers(ctx, segment.ID, userIDs, store.SegmentMembershipSourceManual)
+	if err != nil {
+		return MembershipChange{}, err
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("added %d members to static segment", added))
+	return MembershipChange{Changed: added, UserCount: p.refreshMemberCount(ctx, segment)}, nil
+}
+
+// RemoveSegmentMembers removes waitlist users from a static segment by user ID
+func (p *SegmentProcessor) RemoveSegmentMembers(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, userIDs []uuid.UUID) (MembershipChange, error) {
+	segment, err := p.getStaticSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return MembershipChange{}, err
+	}
+	if len(userIDs) == 0 {
+		return MembershipChange{}, ErrNoMembers
+	}
+
+	removed := 0
+	for start := 0; start < len(userIDs); start += membershipBatchSize {
+		end := min(start+membershipBatchSize, len(userIDs))
+		n, err := p.store.RemoveSegmentMembers(ctx, segment.ID, userIDs[start:end], store.SegmentMembershipSourceManual)
+		if err != nil {
+			p.logger.Error(ctx, "failed to remove segment members", err)
+			return MembershipChange{}, err
+		}
+		removed += n
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("removed %d members from static segment", removed))
+	return MembershipChange{Changed: removed, UserCount: p.refreshMemberCount(ctx, segment)}, nil
+}
+
+// ImportSegmentMembersResult is the result of importing static segment members by email
+type ImportSegmentMembersResult struct {
+	Added           int      `json:"added"`
+	Matched         int      `json:"matched"`
+	UnmatchedEmails []string `json:"unmatched_emails"`
+	UserCount       int      `json:"user_count"`
+}
+
+// ImportSegmentMembers adds the campaign's waitlist users with the given emails to a static segment.
+// Emails that do not belong to a waitlist user of the campaign are returned as unmatched.
+func (p *SegmentProcessor) ImportSegmentMembers(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, emails []string) (ImportSegmentMembersResult, error) {
+	segment, err := p.getStaticSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return ImportSegmentMembersResult{}, err
+	}
+
+	// Normalize and deduplicate emails, keeping their upload order
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, email := range emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if email == "" || seen[email] {
+			continue
+		}
+		seen[email] = true
+		normalized = append(normalized, email)
+	}
+	if len(normalized) == 0 {
+		return ImportSegmentMembersResult{}, ErrNoMembers
+	}
+
+	result := ImportSegmentMembersResult{UnmatchedEmails: []string{}}
+	var userIDs []uuid.UUID
+	for start := 0; start < len(normalized); start += membershipBatchSize {
+		batch := normalized[start:min(start+membershipBatchSize, len(normalized))]
+
+		matches, err := p.store.GetWaitlistUserIDsByEmails(ctx, campaignID, batch)
+		if err != nil {
+			p.logger.Error(ctx, "failed to look up waitlist users by email", err)
+			return ImportSegmentMembersResult{}, err
+		}
+
+		for _, email := range batch {
+			userID, ok := matches[email]
+			if !ok {
+				result.UnmatchedEmails = append(result.UnmatchedEmails, email)
+				continue
+			}
+			userIDs = append(userIDs, userID)
+		}
+	}
+	result.Matched = len(userIDs)
+
+	result.Added, err = p.addMembers(ctx, segment.ID, userIDs, store.SegmentMembershipSourceCSV)
+	if err != nil {
+		return ImportSegmentMembersResult{}, err
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("imported %d members into static segment, %d emails unmatched", result.Added, len(result.UnmatchedEmails)))
+	result.UserCount = p.refreshMemberCount(ctx, segment)
+	return result, nil
+}
+
+// SnapshotSegmentRequest represents a request to snapshot a segment into a new static segment
+type SnapshotSegmentRequest struct {
+	Name        *string
+	Description *string
+}
+
+// SnapshotSegment creates a static segment containing the users that match a segment right now.
+// The snapshot keeps those users as members even as they stop matching the source segment.
+func (p *SegmentProcessor) SnapshotSegment(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, req SnapshotSegmentRequest) (store.Segment, error) {
+	source, err := p.GetSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return store.Segment{}, err
+	}
+
+	users, err := p.store.GetSegmentUsers(ctx, source)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get users for segment snapshot", err)
+		return store.Segment{}, err
+	}
+
+	name := fmt.Sprintf("%s (snapshot %s)", source.Name, time.Now().UTC().Format("2006-01-02"))
+	if req.Name != nil && *req.Name != "" {
+		name = *req.Name
+	}
+
+	snapshot, err := p.store.CreateSegment(ctx, store.CreateSegmentParams{
+		CampaignID:     campaignID,
+		Name:           name,
+		Description:    req.Description,
+		Type:           string(store.SegmentTypeStatic),
+		FilterCriteria: store.JSONB{},
+	})
+	if err != nil {
+		p.logger.Error(ctx, "failed to create snapshot segment", err)
+		return store.Segment{}, err
+	}
+
+	userIDs := make([]uuid.UUID, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+
+	if _, err := p.addMembers(ctx, snapshot.ID, userIDs, store.SegmentMembershipSourceSnapshot); err != nil {
+		return store.Segment{}, err
+	}
+
+	snapshot.CachedUserCount = p.refreshMemberCount(ctx, snapshot)
+
+	p.logger.Info(ctx, fmt.Sprintf("created snapshot of segment with %d members", snapshot.CachedUserCount))
+	return snapshot, nil
+}
+
+// SegmentMembersPage is a page of static segment members
+type SegmentMembersPage struct {
+	Members []store.WaitlistUser `json:"members"`
+	Total   int                  `json:"total"`
+	Limit   int                  `json:"limit"`
+	Offset  int                  `json:"offset"`
+}
+
+// ListSegmentMembers retrieves a page of a static segment's members
+func (p *SegmentProcessor) ListSegmentMembers(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, limit, offset int) (SegmentMembersPage, error) {
+	segment, err := p.getStaticSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return SegmentMembersPage{}, err
+	}
+
+	limit, offset = normalizePage(limit, offset)
+
+	members, err := p.store.GetSegmentMembers(ctx, segment.ID, limit, offset)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get segment members", err)
+		return SegmentMembersPage{}, err
+	}
+	if members == nil {
+		members = []store.WaitlistUser{}
+	}
+
+	total, err := p.store.CountSegmentMembers(ctx, segment.ID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to count segment members", err)
+		return SegmentMembersPage{}, err
+	}
+
+	return SegmentMembersPage{Members: members, Total: total, Limit: limit, Offset: offset}, nil
+}
+
+// GetSegmentMembershipHistory retrieves the membership changes of a static segment, newest first
+func (p *SegmentProcessor) GetSegmentMembershipHistory(ctx context.Context, accountID, campaignID, segmentID uuid.UUID, limit, offset int) ([]store.SegmentMembershipEvent, error) {
+	segment, err := p.getStaticSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, offset = normalizePage(limit, offset)
+
+	events, err := p.store.GetSegmentMembershipHistory(ctx, segment.ID, limit, offset)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get segment membership history", err)
+		return nil, err
+	}
+	if events == nil {
+		events = []store.SegmentMembershipEvent{}
+	}
+
+	return events, nil
+}
+
+// getStaticSegment retrieves a segment owned by the account and verifies it is static
+func (p *SegmentProcessor) getStaticSegment(ctx context.Context, accountID, campaignID, segmentID uuid.UUID) (store.Segment, error) {
+	segment, err := p.GetSegment(ctx, accountID, campaignID, segmentID)
+	if err != nil {
+		return store.Segment{}, err
+	}
+	if segment.Type != string(store.SegmentTypeStatic) {
+		return store.Segment{}, ErrSegmentNotStatic
+	}
+	return segment, nil
+}
+
+// addMembers adds users to a static segment in batches and returns the number added
+func (p *SegmentProcessor) addMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source store.SegmentMembershipSource) (int, error) {
+	added := 0
+	for start := 0; start < len(userIDs); start += membershipBatchSize {
+		end := min(start+membershipBatchSize, len(userIDs))
+		n, err := p.store.AddSegmentMembers(ctx, segmentID, userIDs[start:end], source)
+		if err != nil {
+			p.logger.Error(ctx, "failed to add segment members", err)
+			return 0, err
+		}
+		added += n
+	}
+	return added, nil
+}
+
+// refreshMemberCount recounts a static segment's members and caches the count.
+// Failures are logged and the previously cached count is returned.
+func (p *SegmentProcessor) refreshMemberCount(ctx context.Context, segment store.Segment) int {
+	ctx = observability.WithFields(ctx, observability.Field{Key: "segment_id", Value: segment.ID.String()})
+
+	count, err := p.store.CountSegmentMembers(ctx, segment.ID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to count segment members", err)
+		return segment.CachedUserCount
+	}
+
+	if err := p.store.UpdateSegmentCachedCount(ctx, segment.ID, count); err != nil {
+		p.logger.Error(ctx, "failed to update segment cached count", err)
+	}
+	return count
+}
+
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
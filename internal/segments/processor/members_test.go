@@ -0,0 +1,131 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestImportSegmentMembers(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockSegmentStore(ctrl)
+	p := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+	aliceID := uuid.New()
+
+	mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+	mockStore.EXPECT().GetSegmentByID(gomock.Any(), segmentID).
+		Return(store.Segment{ID: segmentID, CampaignID: campaignID, Type: string(store.SegmentTypeStatic)}, nil)
+	mockStore.EXPECT().
+		GetWaitlistUserIDsByEmails(gomock.Any(), campaignID, []string{"alice@example.com", "bob@example.com"}).
+		Return(map[string]uuid.UUID{"alice@example.com": aliceID}, nil)
+	mockStore.EXPECT().
+		AddSegmentMembers(gomock.Any(), segmentID, []uuid.UUID{aliceID}, store.SegmentMembershipSourceCSV).
+		Return(1, nil)
+	mockStore.EXPECT().CountSegmentMembers(gomock.Any(), segmentID).Return(1, nil)
+	mockStore.EXPECT().UpdateSegmentCachedCount(gomock.Any(), segmentID, 1).Return(nil)
+
+	result, err := p.ImportSegmentMembers(ctx, accountID, campaignID, segmentID,
+		[]string{" Alice@Example.com", "bob@example.com", "", "alice@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Added != 1 || result.Matched != 1 || result.UserCount != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.UnmatchedEmails) != 1 || result.UnmatchedEmails[0] != "bob@example.com" {
+		t.Errorf("expected bob@example.com to be unmatched, got %v", result.UnmatchedEmails)
+	}
+}
+
+func TestAddSegmentMembers_DynamicSegment(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockSegmentStore(ctrl)
+	p := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+
+	mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+	mockStore.EXPECT().GetSegmentByID(gomock.Any(), segmentID).
+		Return(store.Segment{ID: segmentID, CampaignID: campaignID, Type: string(store.SegmentTypeDynamic)}, nil)
+
+	_, err := p.AddSegmentMembers(ctx, accountID, campaignID, segmentID, []uuid.UUID{uuid.New()})
+	if !errors.Is(err, ErrSegmentNotStatic) {
+		t.Errorf("expected ErrSegmentNotStatic, got %v", err)
+	}
+}
+
+func TestSnapshotSegment(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockSegmentStore(ctrl)
+	p := New(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	sourceID := uuid.New()
+	snapshotID := uuid.New()
+	source := store.Segment{
+		ID:             sourceID,
+		CampaignID:     campaignID,
+		Name:           "Power referrers",
+		Type:           string(store.SegmentTypeDynamic),
+		FilterCriteria: store.JSONB{"min_referrals": float64(5)},
+	}
+	users := []store.WaitlistUser{{ID: uuid.New()}, {ID: uuid.New()}}
+
+	mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+	mockStore.EXPECT().GetSegmentByID(gomock.Any(), sourceID).Return(source, nil)
+	mockStore.EXPECT().GetSegmentUsers(gomock.Any(), source).Return(users, nil)
+	mockStore.EXPECT().
+		CreateSegment(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params store.CreateSegmentParams) (store.Segment, error) {
+			if params.Type != string(store.SegmentTypeStatic) {
+				t.Errorf("expected a static segment, got %q", params.Type)
+			}
+			if params.Name != "Beta cohort 1" {
+				t.Errorf("expected requested name, got %q", params.Name)
+			}
+			return store.Segment{ID: snapshotID, CampaignID: campaignID, Name: params.Name, Type: params.Type}, nil
+		})
+	mockStore.EXPECT().
+		AddSegmentMembers(gomock.Any(), snapshotID, []uuid.UUID{users[0].ID, users[1].ID}, store.SegmentMembershipSourceSnapshot).
+		Return(2, nil)
+	mockStore.EXPECT().CountSegmentMembers(gomock.Any(), snapshotID).Return(2, nil)
+	mockStore.EXPECT().UpdateSegmentCachedCount(gomock.Any(), snapshotID, 2).Return(nil)
+
+	name := "Beta cohort 1"
+	snapshot, err := p.SnapshotSegment(ctx, accountID, campaignID, sourceID, SnapshotSegmentRequest{Name: &name})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if snapshot.ID != snapshotID || snapshot.CachedUserCount != 2 {
+		t.Errorf("unexpected snapshot: %+v", snapshot)
+	}
+}
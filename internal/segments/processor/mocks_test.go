@@ -13,6 +13,7 @@ import (
 	store "base-server/internal/store"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,50 @@ func (m *MockSegmentStore) EXPECT() *MockSegmentStoreMockRecorder {
 	return m.recorder
 }
 
+// AddSegmentMembers mocks base method.
+func (m *MockSegmentStore) AddSegmentMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source store.SegmentMembershipSource) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSegmentMembers", ctx, segmentID, userIDs, source)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSegmentMembers indicates an expected call of AddSegmentMembers.
+func (mr *MockSegmentStoreMockRecorder) AddSegmentMembers(ctx, segmentID, userIDs, source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSegmentMembers", reflect.TypeOf((*MockSegmentStore)(nil).AddSegmentMembers), ctx, segmentID, userIDs, source)
+}
+
+// ClearSegmentSync mocks base method.
+func (m *MockSegmentStore) ClearSegmentSync(ctx context.Context, segmentID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearSegmentSync", ctx, segmentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearSegmentSync indicates an expected call of ClearSegmentSync.
+func (mr *MockSegmentStoreMockRecorder) ClearSegmentSync(ctx, segmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearSegmentSync", reflect.TypeOf((*MockSegmentStore)(nil).ClearSegmentSync), ctx, segmentID)
+}
+
+// CountSegmentMembers mocks base method.
+func (m *MockSegmentStore) CountSegmentMembers(ctx context.Context, segmentID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSegmentMembers", ctx, segmentID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSegmentMembers indicates an expected call of CountSegmentMembers.
+func (mr *MockSegmentStoreMockRecorder) CountSegmentMembers(ctx, segmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSegmentMembers", reflect.TypeOf((*MockSegmentStore)(nil).CountSegmentMembers), ctx, segmentID)
+}
+
 // CountUsersMatchingCriteria mocks base method.
 func (m *MockSegmentStore) CountUsersMatchingCriteria(ctx context.Context, campaignID uuid.UUID, criteria store.SegmentFilterCriteria) (int, error) {
 	m.ctrl.T.Helper()
@@ -72,6 +117,21 @@ func (mr *MockSegmentStoreMockRecorder) CreateSegment(ctx, params any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSegment", reflect.TypeOf((*MockSegmentStore)(nil).CreateSegment), ctx, params)
 }
 
+// CreateSegmentExport mocks base method.
+func (m *MockSegmentStore) CreateSegmentExport(ctx context.Context, params store.CreateSegmentExportParams) (store.SegmentExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSegmentExport", ctx, params)
+	ret0, _ := ret[0].(store.SegmentExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSegmentExport indicates an expected call of CreateSegmentExport.
+func (mr *MockSegmentStoreMockRecorder) CreateSegmentExport(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSegmentExport", reflect.TypeOf((*MockSegmentStore)(nil).CreateSegmentExport), ctx, params)
+}
+
 // DeleteSegment mocks base method.
 func (m *MockSegmentStore) DeleteSegment(ctx context.Context, segmentID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -131,6 +191,96 @@ func (mr *MockSegmentStoreMockRecorder) GetSegmentByID(ctx, segmentID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentByID", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentByID), ctx, segmentID)
 }
 
+// GetSegmentExportByID mocks base method.
+func (m *MockSegmentStore) GetSegmentExportByID(ctx context.Context, exportID uuid.UUID) (store.SegmentExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentExportByID", ctx, exportID)
+	ret0, _ := ret[0].(store.SegmentExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentExportByID indicates an expected call of GetSegmentExportByID.
+func (mr *MockSegmentStoreMockRecorder) GetSegmentExportByID(ctx, exportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentExportByID", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentExportByID), ctx, exportID)
+}
+
+// GetSegmentExportContent mocks base method.
+func (m *MockSegmentStore) GetSegmentExportContent(ctx context.Context, exportID uuid.UUID) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentExportContent", ctx, exportID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentExportContent indicates an expected call of GetSegmentExportContent.
+func (mr *MockSegmentStoreMockRecorder) GetSegmentExportContent(ctx, exportID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentExportContent", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentExportContent), ctx, exportID)
+}
+
+// GetSegmentMembers mocks base method.
+func (m *MockSegmentStore) GetSegmentMembers(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]store.WaitlistUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentMembers", ctx, segmentID, limit, offset)
+	ret0, _ := ret[0].([]store.WaitlistUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentMembers indicates an expected call of GetSegmentMembers.
+func (mr *MockSegmentStoreMockRecorder) GetSegmentMembers(ctx, segmentID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentMembers", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentMembers), ctx, segmentID, limit, offset)
+}
+
+// GetSegmentMembershipHistory mocks base method.
+func (m *MockSegmentStore) GetSegmentMembershipHistory(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]store.SegmentMembershipEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentMembershipHistory", ctx, segmentID, limit, offset)
+	ret0, _ := ret[0].([]store.SegmentMembershipEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentMembershipHistory indicates an expected call of GetSegmentMembershipHistory.
+func (mr *MockSegmentStoreMockRecorder) GetSegmentMembershipHistory(ctx, segmentID, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentMembershipHistory", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentMembershipHistory), ctx, segmentID, limit, offset)
+}
+
+// GetSegmentSizeHistory mocks base method.
+func (m *MockSegmentStore) GetSegmentSizeHistory(ctx context.Context, segmentID uuid.UUID, since time.Time) ([]store.SegmentSize, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentSizeHistory", ctx, segmentID, since)
+	ret0, _ := ret[0].([]store.SegmentSize)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentSizeHistory indicates an expected call of GetSegmentSizeHistory.
+func (mr *MockSegmentStoreMockRecorder) GetSegmentSizeHistory(ctx, segmentID, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentSizeHistory", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentSizeHistory), ctx, segmentID, since)
+}
+
+// GetSegmentUsers mocks base method.
+func (m *MockSegmentStore) GetSegmentUsers(ctx context.Context, segment store.Segment) ([]store.WaitlistUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSegmentUsers", ctx, segment)
+	ret0, _ := ret[0].([]store.WaitlistUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSegmentUsers indicates an expected call of GetSegmentUsers.
+func (mr *MockSegmentStoreMockRecorder) GetSegmentUsers(ctx, segment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSegmentUsers", reflect.TypeOf((*MockSegmentStore)(nil).GetSegmentUsers), ctx, segment)
+}
+
 // GetSegmentsByCampaign mocks base method.
 func (m *MockSegmentStore) GetSegmentsByCampaign(ctx context.Context, campaignID uuid.UUID) ([]store.Segment, error) {
 	m.ctrl.T.Helper()
@@ -161,6 +311,51 @@ func (mr *MockSegmentStoreMockRecorder) GetUsersMatchingCriteria(ctx, campaignID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersMatchingCriteria", reflect.TypeOf((*MockSegmentStore)(nil).GetUsersMatchingCriteria), ctx, campaignID, criteria, limit, offset)
 }
 
+// GetWaitlistUserIDsByEmails mocks base method.
+func (m *MockSegmentStore) GetWaitlistUserIDsByEmails(ctx context.Context, campaignID uuid.UUID, emails []string) (map[string]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlistUserIDsByEmails", ctx, campaignID, emails)
+	ret0, _ := ret[0].(map[string]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlistUserIDsByEmails indicates an expected call of GetWaitlistUserIDsByEmails.
+func (mr *MockSegmentStoreMockRecorder) GetWaitlistUserIDsByEmails(ctx, campaignID, emails any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlistUserIDsByEmails", reflect.TypeOf((*MockSegmentStore)(nil).GetWaitlistUserIDsByEmails), ctx, campaignID, emails)
+}
+
+// IsSegmentUsedByAudience mocks base method.
+func (m *MockSegmentStore) IsSegmentUsedByAudience(ctx context.Context, segmentID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSegmentUsedByAudience", ctx, segmentID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSegmentUsedByAudience indicates an expected call of IsSegmentUsedByAudience.
+func (mr *MockSegmentStoreMockRecorder) IsSegmentUsedByAudience(ctx, segmentID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSegmentUsedByAudience", reflect.TypeOf((*MockSegmentStore)(nil).IsSegmentUsedByAudience), ctx, segmentID)
+}
+
+// RemoveSegmentMembers mocks base method.
+func (m *MockSegmentStore) RemoveSegmentMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source store.SegmentMembershipSource) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSegmentMembers", ctx, segmentID, userIDs, source)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveSegmentMembers indicates an expected call of RemoveSegmentMembers.
+func (mr *MockSegmentStoreMockRecorder) RemoveSegmentMembers(ctx, segmentID, userIDs, source any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSegmentMembers", reflect.TypeOf((*MockSegmentStore)(nil).RemoveSegmentMembers), ctx, segmentID, userIDs, source)
+}
+
 // UpdateSegment mocks base method.
 func (m *MockSegmentStore) UpdateSegment(ctx context.Context, segmentID uuid.UUID, params store.UpdateSegmentParams) (store.Segment, error) {
 	m.ctrl.T.Helper()
@@ -189,3 +384,17 @@ func (mr *MockSegmentStoreMockRecorder) UpdateSegmentCachedCount(ctx, segmentID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSegmentCachedCount", reflect.TypeOf((*MockSegmentStore)(nil).UpdateSegmentCachedCount), ctx, segmentID, count)
 }
+
+// UpdateSegmentThresholdReached mocks base method.
+func (m *MockSegmentStore) UpdateSegmentThresholdReached(ctx context.Context, segmentID uuid.UUID, reached bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSegmentThresholdReached", ctx, segmentID, reached)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSegmentThresholdReached indicates an expected call of UpdateSegmentThresholdReached.
+func (mr *MockSegmentStoreMockRecorder) UpdateSegmentThresholdReached(ctx, segmentID, reached any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSegmentThresholdReached", reflect.TypeOf((*MockSegmentStore)(nil).UpdateSegmentThresholdReached), ctx, segmentID, reached)
+}
@@ -7,6 +7,8 @@ import (
 	"base-server/internal/store"
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,17 +22,38 @@ type SegmentStore interface {
 	GetActiveSegmentsByCampaign(ctx context.Context, campaignID uuid.UUID) ([]store.Segment, error)
 	UpdateSegment(ctx context.Context, segmentID uuid.UUID, params store.UpdateSegmentParams) (store.Segment, error)
 	DeleteSegment(ctx context.Context, segmentID uuid.UUID) error
+	IsSegmentUsedByAudience(ctx context.Context, segmentID uuid.UUID) (bool, error)
 	UpdateSegmentCachedCount(ctx context.Context, segmentID uuid.UUID, count int) error
 	CountUsersMatchingCriteria(ctx context.Context, campaignID uuid.UUID, criteria store.SegmentFilterCriteria) (int, error)
 	GetUsersMatchingCriteria(ctx context.Context, campaignID uuid.UUID, criteria store.SegmentFilterCriteria, limit, offset int) ([]store.WaitlistUser, error)
+	GetSegmentUsers(ctx context.Context, segment store.Segment) ([]store.WaitlistUser, error)
+	CountSegmentMembers(ctx context.Context, segmentID uuid.UUID) (int, error)
+	GetSegmentMembers(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]store.WaitlistUser, error)
+	AddSegmentMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source store.SegmentMembershipSource) (int, error)
+	RemoveSegmentMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source store.SegmentMembershipSource) (int, error)
+	GetWaitlistUserIDsByEmails(ctx context.Context, campaignID uuid.UUID, emails []string) (map[string]uuid.UUID, error)
+	GetSegmentMembershipHistory(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]store.SegmentMembershipEvent, error)
+	UpdateSegmentThresholdReached(ctx context.Context, segmentID uuid.UUID, reached bool) error
+	GetSegmentSizeHistory(ctx context.Context, segmentID uuid.UUID, since time.Time) ([]store.SegmentSize, error)
+	ClearSegmentSync(ctx context.Context, segmentID uuid.UUID) error
+	CreateSegmentExport(ctx context.Context, params store.CreateSegmentExportParams) (store.SegmentExport, error)
+	GetSegmentExportByID(ctx context.Context, exportID uuid.UUID) (store.SegmentExport, error)
+	GetSegmentExportContent(ctx context.Context, exportID uuid.UUID) ([]byte, error)
 }
 
 var (
-	ErrSegmentNotFound  = errors.New("segment not found")
-	ErrCampaignNotFound = errors.New("campaign not found")
-	ErrUnauthorized     = errors.New("unauthorized access to segment")
-	ErrInvalidCriteria  = errors.New("invalid filter criteria")
-	ErrSegmentInUse     = errors.New("segment is in use by an email blast")
+	ErrSegmentNotFound   = errors.New("segment not found")
+	ErrCampaignNotFound  = errors.New("campaign not found")
+	ErrUnauthorized      = errors.New("unauthorized access to segment")
+	ErrInvalidCriteria   = errors.New("invalid filter criteria")
+	ErrSegmentInUse      = errors.New("segment is in use by an email blast")
+	ErrSegmentInAudience = errors.New("segment is used by an audience")
+	ErrSegmentNotStatic  = errors.New("segment is not a static segment")
+	ErrNoMembers         = errors.New("no members provided")
+	ErrInvalidThreshold  = errors.New("invalid size threshold")
+	ErrExportNotFound    = errors.New("segment export not found")
+	ErrExportNotReady    = errors.New("segment export is not ready")
+	ErrInvalidFormat     = errors.New("invalid export format")
 )
 
 type SegmentProcessor struct {
@@ -49,7 +72,10 @@ func New(store SegmentStore, logger *observability.Logger) SegmentProcessor {
 type CreateSegmentRequest struct {
 	Name           string
 	Description    *string
+	Type           store.SegmentType
 	FilterCriteria store.SegmentFilterCriteria
+	SizeThreshold  *int
+	SyncEnabled    bool
 }
 
 // CreateSegment creates a new segment for a campaign
@@ -73,6 +99,23 @@ func (p *SegmentProcessor) CreateSegment(ctx context.Context, accountID, campaig
 		return store.Segment{}, ErrUnauthorized
 	}
 
+	if req.Type == "" {
+		req.Type = store.SegmentTypeDynamic
+	}
+
+	if req.SizeThreshold != nil && *req.SizeThreshold < 1 {
+		return store.Segment{}, fmt.Errorf("%w: size_threshold must be at least 1", ErrInvalidThreshold)
+	}
+
+	// Static segments list their members instead of matching filter criteria
+	if req.Type == store.SegmentTypeStatic {
+		if req.FilterCriteria.Tree() != nil {
+			return store.Segment{}, fmt.Errorf("%w: static segments do not have filter criteria", ErrInvalidCriteria)
+		}
+	} else if err := validateCriteria(req.FilterCriteria); err != nil {
+		return store.Segment{}, err
+	}
+
 	// Convert filter criteria to JSONB
 	filterCriteriaJSON := filterCriteriaToJSONB(req.FilterCriteria)
 
@@ -80,7 +123,10 @@ func (p *SegmentProcessor) CreateSegment(ctx context.Context, accountID, campaig
 		CampaignID:     campaignID,
 		Name:           req.Name,
 		Description:    req.Description,
+		Type:           string(req.Type),
 		FilterCriteria: filterCriteriaJSON,
+		SizeThreshold:  req.SizeThreshold,
+		SyncEnabled:    req.SyncEnabled,
 	}
 
 	segment, err := p.store.CreateSegment(ctx, params)
@@ -89,6 +135,11 @@ func (p *SegmentProcessor) CreateSegment(ctx context.Context, accountID, campaig
 		return store.Segment{}, err
 	}
 
+	if req.Type == store.SegmentTypeStatic {
+		p.logger.Info(ctx, "segment created successfully")
+		return segment, nil
+	}
+
 	// Calculate and cache user count
 	count, err := p.store.CountUsersMatchingCriteria(ctx, campaignID, req.FilterCriteria)
 	if err != nil {
@@ -98,6 +149,15 @@ func (p *SegmentProcessor) CreateSegment(ctx context.Context, accountID, campaig
 			p.logger.Error(ctx, "failed to update segment cached count", updateErr)
 		}
 		segment.CachedUserCount = count
+
+		// A segment created above its threshold has not crossed it
+		if req.SizeThreshold != nil && count >= *req.SizeThreshold {
+			if updateErr := p.store.UpdateSegmentThresholdReached(ctx, segment.ID, true); updateErr != nil {
+				p.logger.Error(ctx, "failed to update segment threshold state", updateErr)
+			} else {
+				segment.SizeThresholdReached = true
+			}
+		}
 	}
 
 	p.logger.Info(ctx, "segment created successfully")
@@ -184,6 +244,9 @@ type UpdateSegmentRequest struct {
 	Description    *string
 	FilterCriteria *store.SegmentFilterCriteria
 	Status         *string
+	// SizeThreshold sets the size threshold; 0 removes it
+	SizeThreshold *int
+	SyncEnabled   *bool
 }
 
 // UpdateSegment updates a segment
@@ -227,8 +290,19 @@ func (p *SegmentProcessor) UpdateSegment(ctx context.Context, accountID, campaig
 		return store.Segment{}, ErrInvalidCriteria
 	}
 
+	if req.SizeThreshold != nil && *req.SizeThreshold < 0 {
+		return store.Segment{}, fmt.Errorf("%w: size_threshold must not be negative", ErrInvalidThreshold)
+	}
+
+	if req.FilterCriteria != nil && existingSegment.Type == string(store.SegmentTypeStatic) {
+		return store.Segment{}, fmt.Errorf("%w: static segments do not have filter criteria", ErrInvalidCriteria)
+	}
+
 	var filterCriteriaJSON *store.JSONB
 	if req.FilterCriteria != nil {
+		if err := validateCriteria(*req.FilterCriteria); err != nil {
+			return store.Segment{}, err
+		}
 		jsonb := filterCriteriaToJSONB(*req.FilterCriteria)
 		filterCriteriaJSON = &jsonb
 	}
@@ -238,6 +312,8 @@ func (p *SegmentProcessor) UpdateSegment(ctx context.Context, accountID, campaig
 		Description:    req.Description,
 		FilterCriteria: filterCriteriaJSON,
 		Status:         req.Status,
+		SizeThreshold:  req.SizeThreshold,
+		SyncEnabled:    req.SyncEnabled,
 	}
 
 	segment, err := p.store.UpdateSegment(ctx, segmentID, params)
@@ -249,6 +325,15 @@ func (p *SegmentProcessor) UpdateSegment(ctx context.Context, accountID, campaig
 		return store.Segment{}, err
 	}
 
+	// Disabling sync forgets what was pushed downstream so re-enabling starts from a full sync
+	if req.SyncEnabled != nil && !*req.SyncEnabled && existingSegment.SyncEnabled {
+		if err := p.store.ClearSegmentSync(ctx, segmentID); err != nil {
+			p.logger.Error(ctx, "failed to clear segment sync", err)
+			return store.Segment{}, err
+		}
+		segment.SyncedAt = nil
+	}
+
 	// Recalculate cached count if filter criteria changed
 	if req.FilterCriteria != nil {
 		count, countErr := p.store.CountUsersMatchingCriteria(ctx, campaignID, *req.FilterCriteria)
@@ -302,6 +387,16 @@ func (p *SegmentProcessor) DeleteSegment(ctx context.Context, accountID, campaig
 		return ErrUnauthorized
 	}
 
+	// Audiences resolve their segments on every send, so a referenced segment cannot be deleted
+	inAudience, err := p.store.IsSegmentUsedByAudience(ctx, segmentID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to check audiences using segment", err)
+		return err
+	}
+	if inAudience {
+		return ErrSegmentInAudience
+	}
+
 	err = p.store.DeleteSegment(ctx, segmentID)
 	if err != nil {
 		if errors.Is(err, store.ErrNotFound) {
@@ -348,6 +443,10 @@ func (p *SegmentProcessor) PreviewSegment(ctx context.Context, accountID, campai
 		return SegmentPreview{}, ErrUnauthorized
 	}
 
+	if err := validateCriteria(req.FilterCriteria); err != nil {
+		return SegmentPreview{}, err
+	}
+
 	// Default sample limit
 	if req.SampleLimit <= 0 {
 		req.SampleLimit = 10
@@ -394,17 +493,8 @@ func (p *SegmentProcessor) RefreshSegmentCount(ctx context.Context, accountID, c
 		return 0, err
 	}
 
-	// Parse filter criteria
-	criteria, err := store.ParseFilterCriteria(segment.FilterCriteria)
+	count, err := p.countSegmentUsers(ctx, segment)
 	if err != nil {
-		p.logger.Error(ctx, "failed to parse filter criteria", err)
-		return 0, ErrInvalidCriteria
-	}
-
-	// Count matching users
-	count, err := p.store.CountUsersMatchingCriteria(ctx, campaignID, criteria)
-	if err != nil {
-		p.logger.Error(ctx, "failed to count users", err)
 		return 0, err
 	}
 
@@ -418,6 +508,31 @@ func (p *SegmentProcessor) RefreshSegmentCount(ctx context.Context, accountID, c
 	return count, nil
 }
 
+// countSegmentUsers counts the members of a static segment or the users matching a dynamic segment
+func (p *SegmentProcessor) countSegmentUsers(ctx context.Context, segment store.Segment) (int, error) {
+	if segment.Type == string(store.SegmentTypeStatic) {
+		count, err := p.store.CountSegmentMembers(ctx, segment.ID)
+		if err != nil {
+			p.logger.Error(ctx, "failed to count segment members", err)
+			return 0, err
+		}
+		return count, nil
+	}
+
+	criteria, err := store.ParseFilterCriteria(segment.FilterCriteria)
+	if err != nil {
+		p.logger.Error(ctx, "failed to parse filter criteria", err)
+		return 0, ErrInvalidCriteria
+	}
+
+	count, err := p.store.CountUsersMatchingCriteria(ctx, segment.CampaignID, criteria)
+	if err != nil {
+		p.logger.Error(ctx, "failed to count users", err)
+		return 0, err
+	}
+	return count, nil
+}
+
 // Helper functions
 
 func filterCriteriaToJSONB(criteria store.SegmentFilterCriteria) store.JSONB {
@@ -453,10 +568,25 @@ func filterCriteriaToJSONB(criteria store.SegmentFilterCriteria) store.JSONB {
 	if len(criteria.CustomFields) > 0 {
 		jsonb["custom_fields"] = criteria.CustomFields
 	}
+	if criteria.Rules != nil {
+		jsonb["rules"] = criteria.Rules
+	}
 
 	return jsonb
 }
 
+// validateCriteria checks the filter tree built from the flat criteria and rules
+func validateCriteria(criteria store.SegmentFilterCriteria) error {
+	tree := criteria.Tree()
+	if tree == nil {
+		return nil
+	}
+	if err := tree.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCriteria, err)
+	}
+	return nil
+}
+
 func isValidSegmentStatus(status string) bool {
 	validStatuses := map[string]bool{
 		"active":   true,
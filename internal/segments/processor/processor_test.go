@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeleteSegment(t *testing.T) {
+	t.Parallel()
+
+	accountID := uuid.New()
+	campaignID := uuid.New()
+	segmentID := uuid.New()
+
+	setup := func(t *testing.T) (*MockSegmentStore, SegmentProcessor) {
+		ctrl := gomock.NewController(t)
+		mockStore := NewMockSegmentStore(ctrl)
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, AccountID: accountID}, nil)
+		mockStore.EXPECT().GetSegmentByID(gomock.Any(), segmentID).Return(store.Segment{ID: segmentID, CampaignID: campaignID}, nil)
+		return mockStore, New(mockStore, observability.NewLogger())
+	}
+
+	t.Run("deletes unreferenced segment", func(t *testing.T) {
+		mockStore, p := setup(t)
+		mockStore.EXPECT().IsSegmentUsedByAudience(gomock.Any(), segmentID).Return(false, nil)
+		mockStore.EXPECT().DeleteSegment(gomock.Any(), segmentID).Return(nil)
+
+		if err := p.DeleteSegment(context.Background(), accountID, campaignID, segmentID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects segment used by an audience", func(t *testing.T) {
+		mockStore, p := setup(t)
+		mockStore.EXPECT().IsSegmentUsedByAudience(gomock.Any(), segmentID).Return(true, nil)
+
+		err := p.DeleteSegment(context.Background(), accountID, campaignID, segmentID)
+		if !errors.Is(err, ErrSegmentInAudience) {
+			t.Errorf("expected ErrSegmentInAudience, got %v", err)
+		}
+	})
+}
@@ -78,6 +78,10 @@ func (s *Server) Setup() {
 		s.deps.APIKeysHandler,
 		s.deps.SegmentsHandler,
 		s.deps.EmailblastsHandler,
+		s.deps.EmailSequencesHandler,
+		s.deps.AudiencesHandler,
+		s.deps.EventLogHandler,
+		s.deps.EmailTrackingHandler,
 	)
 	api.RegisterRoutes()
 
@@ -136,9 +140,41 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
+	// Start event log consumer (records events for the events API and replays)
+	go func() {
+		if err := s.deps.EventLogConsumer.Start(ctx); err != nil {
+			s.logger.Error(ctx, "event log consumer stopped with error", err)
+		}
+	}()
+
 	// Start blast scheduler (checks for scheduled blasts)
 	go s.deps.BlastScheduler.Start(ctx)
 
+	// Start sequence scheduler (sends drip email sequence steps)
+	go s.deps.SequenceScheduler.Start(ctx)
+
+	// Start position update digest scheduler (emails users who moved up)
+	go s.deps.PositionDigestScheduler.Start(ctx)
+
+	// Start segment count refresh scheduler (records segment sizes, fires threshold events)
+	go s.deps.SegmentRefreshScheduler.Start(ctx)
+
+	// Start webhook auto-disable scheduler (disables endpoints that keep failing)
+	go s.deps.WebhookDisableScheduler.Start(ctx)
+
+	// Start webhook retention scheduler (daily delivery stats, log purge and payload redaction)
+	go s.deps.WebhookRetentionScheduler.Start(ctx)
+
+	// Start webhook delivery queue metrics (queue depth, in-flight and spilled deliveries)
+	go s.deps.WebhookDeliveryQueue.Start(ctx)
+
+	// Start segment sync scheduler and export worker
+	go s.deps.SegmentSyncScheduler.Start(ctx)
+	go s.deps.SegmentExportWorker.Start(ctx)
+
+	// Start email template variant promotion scheduler
+	go s.deps.VariantPromotionScheduler.Start(ctx)
+
 	// Create HTTP server
 	s.httpServer = &http.Server{
 		Addr:    fmt.Sprintf(":%d", s.config.Server.Port),
@@ -174,13 +210,23 @@ func (s *Server) WaitForShutdown(ctx context.Context) error {
 	var wg sync.WaitGroup
 	stopFuncs := []func(){
 		s.deps.WebhookWorker.Stop,
+		s.deps.WebhookDisableScheduler.Stop,
+		s.deps.WebhookRetentionScheduler.Stop,
+		s.deps.WebhookDeliveryQueue.Stop,
 		s.deps.WebhookConsumer.Stop,
 		s.deps.EmailConsumer.Stop,
 		s.deps.PositionConsumer.Stop,
 		s.deps.SpamConsumer.Stop,
 		s.deps.IntegrationConsumer.Stop,
 		s.deps.BlastConsumer.Stop,
+		s.deps.EventLogConsumer.Stop,
 		s.deps.BlastScheduler.Stop,
+		s.deps.SequenceScheduler.Stop,
+		s.deps.PositionDigestScheduler.Stop,
+		s.deps.SegmentRefreshScheduler.Stop,
+		s.deps.SegmentSyncScheduler.Stop,
+		s.deps.SegmentExportWorker.Stop,
+		s.deps.VariantPromotionScheduler.Stop,
 	}
 
 	for _, stopFn := range stopFuncs {
@@ -301,3 +301,26 @@ func (s *Store) DeleteTeamMember(ctx context.Context, accountID, userID uuid.UUI
 
 	return nil
 }
+
+const sqlGetAccountOwnerEmail = `
+SELECT COALESCE(ea.email, oa.email)
+FROM accounts a
+JOIN user_auth ua ON ua.user_id = a.owner_user_id
+LEFT JOIN email_auth ea ON ea.auth_id = ua.id
+LEFT JOIN oauth_auth oa ON oa.auth_id = ua.id
+WHERE a.id = $1 AND a.deleted_at IS NULL AND COALESCE(ea.email, oa.email) IS NOT NULL
+LIMIT 1
+`
+
+// GetAccountOwnerEmail retrieves the email address of an account's owner
+func (s *Store) GetAccountOwnerEmail(ctx context.Context, accountID uuid.UUID) (string, error) {
+	var email string
+	err := s.db.GetContext(ctx, &email, sqlGetAccountOwnerEmail, accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("failed to get account owner email: %w", err)
+	}
+	return email, nil
+}
@@ -0,0 +1,386 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CreateAudienceParams represents parameters for creating an audience
+type CreateAudienceParams struct {
+	AccountID               uuid.UUID
+	Name                    string
+	Description             *string
+	CombineMode             string
+	SegmentIDs              []uuid.UUID
+	ExcludeSegmentIDs       []uuid.UUID
+	SuppressBounced         bool
+	RequireMarketingConsent bool
+}
+
+const sqlCreateAudience = `
+INSERT INTO audiences (account_id, name, description, combine_mode, segment_ids, exclude_segment_ids, suppress_bounced, require_marketing_consent)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, account_id, name, description, combine_mode, segment_ids, exclude_segment_ids, suppress_bounced, require_marketing_consent, created_at, updated_at, deleted_at
+`
+
+// CreateAudience creates a new account-level audience
+func (s *Store) CreateAudience(ctx context.Context, params CreateAudienceParams) (Audience, error) {
+	excludeSegmentIDs := params.ExcludeSegmentIDs
+	if excludeSegmentIDs == nil {
+		excludeSegmentIDs = []uuid.UUID{}
+	}
+
+	var audience Audience
+	err := s.db.GetContext(ctx, &audience, sqlCreateAudience,
+		params.AccountID,
+		params.Name,
+		params.Description,
+		params.CombineMode,
+		pq.Array(params.SegmentIDs),
+		pq.Array(excludeSegmentIDs),
+		params.SuppressBounced,
+		params.RequireMarketingConsent)
+	if err != nil {
+		return Audience{}, fmt.Errorf("failed to create audience: %w", err)
+	}
+	return audience, nil
+}
+
+const sqlGetAudienceByID = `
+SELECT id, account_id, name, description, combine_mode, segment_ids, exclude_segment_ids, suppress_bounced, require_marketing_consent, created_at, updated_at, deleted_at
+FROM audiences
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// GetAudienceByID retrieves an audience by ID
+func (s *Store) GetAudienceByID(ctx context.Context, audienceID uuid.UUID) (Audience, error) {
+	var audience Audience
+	err := s.db.GetContext(ctx, &audience, sqlGetAudienceByID, audienceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Audience{}, ErrNotFound
+		}
+		return Audience{}, fmt.Errorf("failed to get audience: %w", err)
+	}
+	return audience, nil
+}
+
+const sqlGetAudiencesByAccount = `
+SELECT id, account_id, name, description, combine_mode, segment_ids, exclude_segment_ids, suppress_bounced, require_marketing_consent, created_at, updated_at, deleted_at
+FROM audiences
+WHERE account_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC
+`
+
+// GetAudiencesByAccount retrieves all audiences of an account
+func (s *Store) GetAudiencesByAccount(ctx context.Context, accountID uuid.UUID) ([]Audience, error) {
+	var audiences []Audience
+	err := s.db.SelectContext(ctx, &audiences, sqlGetAudiencesByAccount, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audiences: %w", err)
+	}
+	return audiences, nil
+}
+
+// UpdateAudienceParams represents parameters for updating an audience
+type UpdateAudienceParams struct {
+	Name                    *string
+	Description             *string
+	CombineMode             *string
+	SegmentIDs              []uuid.UUID
+	ExcludeSegmentIDs       []uuid.UUID
+	SuppressBounced         *bool
+	RequireMarketingConsent *bool
+}
+
+const sqlUpdateAudience = `
+UPDATE audiences
+SET name = COALESCE($2, name),
+    description = COALESCE($3, description),
+    combine_mode = COALESCE($4, combine_mode),
+    segment_ids = COALESCE($5, segment_ids),
+    exclude_segment_ids = COALESCE($6, exclude_segment_ids),
+    suppress_bounced = COALESCE($7, suppress_bounced),
+    require_marketing_consent = COALESCE($8, require_marketing_consent),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, account_id, name, description, combine_mode, segment_ids, exclude_segment_ids, suppress_bounced, require_marketing_consent, created_at, updated_at, deleted_at
+`
+
+// UpdateAudience updates an audience. Nil segment ID slices leave the stored segments unchanged.
+func (s *Store) UpdateAudience(ctx context.Context, audienceID uuid.UUID, params UpdateAudienceParams) (Audience, error) {
+	var audience Audience
+	err := s.db.GetContext(ctx, &audience, sqlUpdateAudience,
+		audienceID,
+		params.Name,
+		params.Description,
+		params.CombineMode,
+		UUIDArray(params.SegmentIDs),
+		UUIDArray(params.ExcludeSegmentIDs),
+		params.SuppressBounced,
+		params.RequireMarketingConsent)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Audience{}, ErrNotFound
+		}
+		return Audience{}, fmt.Errorf("failed to update audience: %w", err)
+	}
+	return audience, nil
+}
+
+const sqlDeleteAudience = `
+UPDATE audiences
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// DeleteAudience soft deletes an audience
+func (s *Store) DeleteAudience(ctx context.Context, audienceID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, sqlDeleteAudience, audienceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete audience: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+const sqlIsSegmentUsedByAudience = `
+SELECT EXISTS (
+    SELECT 1 FROM audiences
+    WHERE deleted_at IS NULL AND ($1 = ANY(segment_ids) OR $1 = ANY(exclude_segment_ids))
+)
+`
+
+// IsSegmentUsedByAudience reports whether an audience includes or excludes the segment
+func (s *Store) IsSegmentUsedByAudience(ctx context.Context, segmentID uuid.UUID) (bool, error) {
+	var used bool
+	err := s.db.GetContext(ctx, &used, sqlIsSegmentUsedByAudience, segmentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check audiences using segment: %w", err)
+	}
+	return used, nil
+}
+
+const sqlGetBouncedEmails = `
+SELECT LOWER(br.email) AS email
+FROM blast_recipients br
+JOIN email_blasts b ON b.id = br.blast_id
+WHERE b.account_id = $1 AND br.status = 'bounced' AND LOWER(br.email) = ANY($2)
+UNION
+SELECT LOWER(el.recipient_email) AS email
+FROM email_logs el
+JOIN campaigns c ON c.id = el.campaign_id
+WHERE c.account_id = $1 AND el.status = 'bounced' AND LOWER(el.recipient_email) = ANY($2)
+`
+
+// GetBouncedEmails returns which of the given emails bounced in a previous blast or email of the account.
+// Emails are matched case-insensitively and returned lowercased.
+func (s *Store) GetBouncedEmails(ctx context.Context, accountID uuid.UUID, emails []string) (map[string]bool, error) {
+	bounced := make(map[string]bool)
+	if len(emails) == 0 {
+		return bounced, nil
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	var rows []string
+	err := s.db.SelectContext(ctx, &rows, sqlGetBouncedEmails, accountID, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bounced emails: %w", err)
+	}
+
+	for _, email := range rows {
+		bounced[email] = true
+	}
+	return bounced, nil
+}
+
+// AudienceResolution is the recipient list of an audience along with how it was built
+type AudienceResolution struct {
+	Users []WaitlistUser `json:"-"`
+
+	BySegment []BlastRecipientsSegmentInfo `json:"by_segment"`
+	// TotalBeforeDedup is the sum of the included segments' user counts
+	TotalBeforeDedup int `json:"total_before_dedup"`
+	// Combined is the number of unique emails left after union or intersect
+	Combined int `json:"combined"`
+	// Suppressed is the number of combined users removed by suppression rules
+	Suppressed int `json:"suppressed"`
+	// TotalCount is the number of recipients
+	TotalCount int `json:"total_count"`
+}
+
+// ResolveAudience builds the recipient list of an audience. The users of its segments are combined
+// by email using the audience's combine mode, then suppression rules are applied.
+func (s *Store) ResolveAudience(ctx context.Context, audience Audience) (AudienceResolution, error) {
+	resolution := AudienceResolution{
+		BySegment: make([]BlastRecipientsSegmentInfo, 0, len(audience.SegmentIDs)),
+	}
+
+	segmentUsers := make([][]WaitlistUser, 0, len(audience.SegmentIDs))
+	for _, segmentID := range audience.SegmentIDs {
+		segment, err := s.GetSegmentByID(ctx, segmentID)
+		if err != nil {
+			return AudienceResolution{}, fmt.Errorf("failed to get segment %s: %w", segmentID, err)
+		}
+
+		users, err := s.GetSegmentUsers(ctx, segment)
+		if err != nil {
+			return AudienceResolution{}, fmt.Errorf("failed to get users for segment %s: %w", segmentID, err)
+		}
+
+		segmentUsers = append(segmentUsers, users)
+		resolution.BySegment = append(resolution.BySegment, BlastRecipientsSegmentInfo{
+			SegmentID:   segmentID,
+			SegmentName: segment.Name,
+			UserCount:   len(users),
+		})
+		resolution.TotalBeforeDedup += len(users)
+	}
+
+	combined := combineAudienceUsers(AudienceCombineMode(audience.CombineMode), segmentUsers)
+	resolution.Combined = len(combined)
+
+	excluded := make(map[string]bool)
+	for _, segmentID := range audience.ExcludeSegmentIDs {
+		segment, err := s.GetSegmentByID(ctx, segmentID)
+		if err != nil {
+			return AudienceResolution{}, fmt.Errorf("failed to get excluded segment %s: %w", segmentID, err)
+		}
+
+		users, err := s.GetSegmentUsers(ctx, segment)
+		if err != nil {
+			return AudienceResolution{}, fmt.Errorf("failed to get users for excluded segment %s: %w", segmentID, err)
+		}
+
+		for _, user := range users {
+			excluded[strings.ToLower(user.Email)] = true
+		}
+	}
+
+	bounced := make(map[string]bool)
+	if audience.SuppressBounced && len(combined) > 0 {
+		emails := make([]string, len(combined))
+		for i, user := range combined {
+			emails[i] = user.Email
+		}
+
+		var err error
+		bounced, err = s.GetBouncedEmails(ctx, audience.AccountID, emails)
+		if err != nil {
+			return AudienceResolution{}, err
+		}
+	}
+
+	resolution.Users = suppressAudienceUsers(audience, combined, excluded, bounced)
+	resolution.TotalCount = len(resolution.Users)
+	resolution.Suppressed = resolution.Combined - resolution.TotalCount
+
+	return resolution, nil
+}
+
+// combineAudienceUsers combines the users of each segment by lowercased email. Union keeps every
+// email found in any segment, intersect only emails found in every segment. When an email appears
+// more than once the first user found, in segment order, is kept.
+func combineAudienceUsers(mode AudienceCombineMode, segmentUsers [][]WaitlistUser) []WaitlistUser {
+	// segmentsByEmail counts the number of segments each email appears in
+	segmentsByEmail := make(map[string]int)
+	firstUser := make(map[string]WaitlistUser)
+	var order []string
+
+	for _, users := range segmentUsers {
+		seenInSegment := make(map[string]bool)
+		for _, user := range users {
+			email := strings.ToLower(user.Email)
+			if seenInSegment[email] {
+				continue
+			}
+			seenInSegment[email] = true
+
+			if _, ok := firstUser[email]; !ok {
+				firstUser[email] = user
+				order = append(order, email)
+			}
+			segmentsByEmail[email]++
+		}
+	}
+
+	combined := make([]WaitlistUser, 0, len(order))
+	for _, email := range order {
+		if mode == AudienceCombineModeIntersect && segmentsByEmail[email] != len(segmentUsers) {
+			continue
+		}
+		combined = append(combined, firstUser[email])
+	}
+	return combined
+}
+
+// suppressAudienceUsers removes users matched by the audience's suppression rules. Blocked and
+// removed users are always suppressed; excluded and bounced are keyed by lowercased email.
+func suppressAudienceUsers(audience Audience, users []WaitlistUser, excluded, bounced map[string]bool) []WaitlistUser {
+	kept := make([]WaitlistUser, 0, len(users))
+	for _, user := range users {
+		email := strings.ToLower(user.Email)
+		switch {
+		case user.Status == WaitlistUserStatusBlocked, user.Status == WaitlistUserStatusRemoved:
+			continue
+		case excluded[email], bounced[email]:
+			continue
+		case audience.RequireMarketingConsent && !user.MarketingConsent:
+			continue
+		}
+		kept = append(kept, user)
+	}
+	return kept
+}
+
+// getBlastUsersFromAudience returns the recipients of an audience for a blast
+func (s *Store) getBlastUsersFromAudience(ctx context.Context, audienceID uuid.UUID) ([]WaitlistUser, error) {
+	audience, err := s.GetAudienceByID(ctx, audienceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audience %s: %w", audienceID, err)
+	}
+
+	resolution, err := s.ResolveAudience(ctx, audience)
+	if err != nil {
+		return nil, err
+	}
+	return resolution.Users, nil
+}
+
+// CreateBlastRecipientsFromAudience creates recipients from an audience, deduplicated by email
+// and with its suppression rules applied
+func (s *Store) CreateBlastRecipientsFromAudience(ctx context.Context, blastID, audienceID uuid.UUID, batchSize int) (int, error) {
+	users, err := s.getBlastUsersFromAudience(ctx, audienceID)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(users) == 0 {
+		return 0, nil
+	}
+
+	if err := s.CreateBlastRecipientsBulk(ctx, blastID, users, batchSize); err != nil {
+		return 0, err
+	}
+
+	return len(users), nil
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCombineAudienceUsers(t *testing.T) {
+	t.Parallel()
+
+	alice := WaitlistUser{ID: uuid.New(), Email: "alice@example.com"}
+	aliceOtherCampaign := WaitlistUser{ID: uuid.New(), Email: "Alice@Example.com"}
+	bob := WaitlistUser{ID: uuid.New(), Email: "bob@example.com"}
+	carol := WaitlistUser{ID: uuid.New(), Email: "carol@example.com"}
+
+	segments := [][]WaitlistUser{
+		{alice, bob},
+		{aliceOtherCampaign, carol},
+	}
+
+	union := combineAudienceUsers(AudienceCombineModeUnion, segments)
+	if len(union) != 3 {
+		t.Fatalf("expected 3 unique users in union, got %d", len(union))
+	}
+	if union[0].ID != alice.ID {
+		t.Errorf("expected the first user found for a duplicate email to be kept")
+	}
+
+	intersect := combineAudienceUsers(AudienceCombineModeIntersect, segments)
+	if len(intersect) != 1 || intersect[0].ID != alice.ID {
+		t.Errorf("expected only alice in intersection, got %+v", intersect)
+	}
+}
+
+func TestSuppressAudienceUsers(t *testing.T) {
+	t.Parallel()
+
+	users := []WaitlistUser{
+		{Email: "kept@example.com", Status: WaitlistUserStatusVerified, MarketingConsent: true},
+		{Email: "blocked@example.com", Status: WaitlistUserStatusBlocked, MarketingConsent: true},
+		{Email: "excluded@example.com", Status: WaitlistUserStatusPending, MarketingConsent: true},
+		{Email: "Bounced@example.com", Status: WaitlistUserStatusPending, MarketingConsent: true},
+		{Email: "no-consent@example.com", Status: WaitlistUserStatusPending},
+	}
+	excluded := map[string]bool{"excluded@example.com": true}
+	bounced := map[string]bool{"bounced@example.com": true}
+
+	kept := suppressAudienceUsers(Audience{RequireMarketingConsent: true}, users, excluded, bounced)
+	if len(kept) != 1 || kept[0].Email != "kept@example.com" {
+		t.Errorf("expected only kept@example.com, got %+v", kept)
+	}
+
+	kept = suppressAudienceUsers(Audience{}, users, excluded, bounced)
+	if len(kept) != 2 {
+		t.Errorf("expected users without consent to be kept when consent is not required, got %d users", len(kept))
+	}
+}
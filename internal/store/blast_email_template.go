@@ -11,17 +11,20 @@ import (
 
 // CreateBlastEmailTemplateParams represents parameters for creating a blast email template
 type CreateBlastEmailTemplateParams struct {
-	AccountID  uuid.UUID
-	Name       string
-	Subject    string
-	HTMLBody   string
-	BlocksJSON *JSONB
+	AccountID   uuid.UUID
+	Name        string
+	Subject     string
+	HTMLBody    string
+	BlocksJSON  *JSONB
+	TextBody    *string
+	Headers     *JSONB
+	Attachments EmailAttachments
 }
 
 const sqlCreateBlastEmailTemplate = `
-INSERT INTO blast_email_templates (account_id, name, subject, html_body, blocks_json)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, account_id, name, subject, html_body, blocks_json, created_at, updated_at, deleted_at
+INSERT INTO blast_email_templates (account_id, name, subject, html_body, blocks_json, text_body, headers, attachments)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, account_id, name, subject, html_body, text_body, blocks_json, headers, attachments, created_at, updated_at, deleted_at
 `
 
 // CreateBlastEmailTemplate creates a new blast email template
@@ -32,7 +35,10 @@ func (s *Store) CreateBlastEmailTemplate(ctx context.Context, params CreateBlast
 		params.Name,
 		params.Subject,
 		params.HTMLBody,
-		params.BlocksJSON)
+		params.BlocksJSON,
+		params.TextBody,
+		params.Headers,
+		params.Attachments)
 	if err != nil {
 		return BlastEmailTemplate{}, fmt.Errorf("failed to create blast email template: %w", err)
 	}
@@ -40,7 +46,7 @@ func (s *Store) CreateBlastEmailTemplate(ctx context.Context, params CreateBlast
 }
 
 const sqlGetBlastEmailTemplateByID = `
-SELECT id, account_id, name, subject, html_body, blocks_json, created_at, updated_at, deleted_at
+SELECT id, account_id, name, subject, html_body, text_body, blocks_json, headers, attachments, created_at, updated_at, deleted_at
 FROM blast_email_templates
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -59,7 +65,7 @@ func (s *Store) GetBlastEmailTemplateByID(ctx context.Context, templateID uuid.U
 }
 
 const sqlGetBlastEmailTemplatesByAccount = `
-SELECT id, account_id, name, subject, html_body, blocks_json, created_at, updated_at, deleted_at
+SELECT id, account_id, name, subject, html_body, text_body, blocks_json, headers, attachments, created_at, updated_at, deleted_at
 FROM blast_email_templates
 WHERE account_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -81,17 +87,23 @@ SET name = COALESCE($2, name),
     subject = COALESCE($3, subject),
     html_body = COALESCE($4, html_body),
     blocks_json = COALESCE($5, blocks_json),
+    text_body = COALESCE($6, text_body),
+    headers = COALESCE($7, headers),
+    attachments = COALESCE($8, attachments),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, account_id, name, subject, html_body, blocks_json, created_at, updated_at, deleted_at
+RETURNING id, account_id, name, subject, html_body, text_body, blocks_json, headers, attachments, created_at, updated_at, deleted_at
 `
 
 // UpdateBlastEmailTemplateParams represents parameters for updating a blast email template
 type UpdateBlastEmailTemplateParams struct {
-	Name       *string
-	Subject    *string
-	HTMLBody   *string
-	BlocksJSON *JSONB
+	Name        *string
+	Subject     *string
+	HTMLBody    *string
+	BlocksJSON  *JSONB
+	TextBody    *string
+	Headers     *JSONB
+	Attachments EmailAttachments
 }
 
 // UpdateBlastEmailTemplate updates a blast email template
@@ -102,7 +114,10 @@ func (s *Store) UpdateBlastEmailTemplate(ctx context.Context, templateID uuid.UU
 		params.Name,
 		params.Subject,
 		params.HTMLBody,
-		params.BlocksJSON)
+		params.BlocksJSON,
+		params.TextBody,
+		params.Headers,
+		params.Attachments)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return BlastEmailTemplate{}, ErrNotFound
@@ -153,7 +168,7 @@ func (s *Store) CountBlastEmailTemplatesByAccount(ctx context.Context, accountID
 }
 
 const sqlGetBlastEmailTemplateByName = `
-SELECT id, account_id, name, subject, html_body, blocks_json, created_at, updated_at, deleted_at
+SELECT id, account_id, name, subject, html_body, text_body, blocks_json, headers, attachments, created_at, updated_at, deleted_at
 FROM blast_email_templates
 WHERE account_id = $1 AND name = $2 AND deleted_at IS NULL
 `
@@ -0,0 +1,268 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const sqlClaimBlastRecipients = `
+UPDATE blast_recipients
+SET status = 'sending',
+    lease_owner = $3,
+    lease_expires_at = CURRENT_TIMESTAMP + $4 * INTERVAL '1 second',
+    attempts = attempts + 1,
+    queued_at = COALESCE(queued_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT id
+    FROM blast_recipients
+    WHERE blast_id = $1 AND batch_number = $2
+      AND (status = 'pending' OR (status IN ('queued', 'sending') AND lease_expires_at < CURRENT_TIMESTAMP))
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, variant_id, timezone, lease_owner, lease_expires_at, attempts, created_at, updated_at
+`
+
+// ClaimBlastRecipients leases the unsent recipients of a batch to owner for the lease duration.
+// Recipients that were already sent, or are leased by another worker, are not returned.
+func (s *Store) ClaimBlastRecipients(ctx context.Context, blastID uuid.UUID, batchNumber int, owner string, lease time.Duration) ([]BlastRecipient, error) {
+	var recipients []BlastRecipient
+	err := s.db.SelectContext(ctx, &recipients, sqlClaimBlastRecipients, blastID, batchNumber, owner, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim blast recipients: %w", err)
+	}
+	return recipients, nil
+}
+
+const sqlExtendBlastRecipientLeases = `
+UPDATE blast_recipients
+SET lease_expires_at = CURRENT_TIMESTAMP + $3 * INTERVAL '1 second'
+WHERE blast_id = $1 AND lease_owner = $2 AND status = 'sending'
+`
+
+// ExtendBlastRecipientLeases renews the leases owner holds on the recipients of a blast
+func (s *Store) ExtendBlastRecipientLeases(ctx context.Context, blastID uuid.UUID, owner string, lease time.Duration) error {
+	_, err := s.db.ExecContext(ctx, sqlExtendBlastRecipientLeases, blastID, owner, lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to extend blast recipient leases: %w", err)
+	}
+	return nil
+}
+
+const sqlReleaseBlastRecipientLeases = `
+UPDATE blast_recipients
+SET status = 'pending',
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE blast_id = $1 AND lease_owner = $2 AND status = 'sending'
+`
+
+// ReleaseBlastRecipientLeases returns the recipients owner has claimed but not sent to pending
+func (s *Store) ReleaseBlastRecipientLeases(ctx context.Context, blastID uuid.UUID, owner string) error {
+	_, err := s.db.ExecContext(ctx, sqlReleaseBlastRecipientLeases, blastID, owner)
+	if err != nil {
+		return fmt.Errorf("failed to release blast recipient leases: %w", err)
+	}
+	return nil
+}
+
+// ExpiredBlastBatch is a batch of a sending blast that had recipients with expired leases
+type ExpiredBlastBatch struct {
+	BlastID     uuid.UUID `db:"blast_id"`
+	AccountID   uuid.UUID `db:"account_id"`
+	BatchNumber int       `db:"batch_number"`
+}
+
+const sqlFailExhaustedBlastRecipients = `
+UPDATE blast_recipients
+SET status = 'failed',
+    failed_at = CURRENT_TIMESTAMP,
+    error_message = 'lease expired after ' || attempts || ' send attempts',
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('queued', 'sending') AND lease_expires_at < CURRENT_TIMESTAMP AND attempts >= $1
+RETURNING blast_id, batch_number
+`
+
+const sqlRequeueExpiredBlastRecipients = `
+UPDATE blast_recipients
+SET status = 'pending',
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status IN ('queued', 'sending') AND lease_expires_at < CURRENT_TIMESTAMP
+RETURNING blast_id, batch_number
+`
+
+const sqlGetSendingBlastAccounts = `
+SELECT id, account_id
+FROM email_blasts
+WHERE id = ANY($1) AND status = 'sending' AND deleted_at IS NULL
+`
+
+// RequeueExpiredBlastRecipients returns recipients whose lease expired to pending so their batch can
+// be sent again. Recipients that were claimed maxAttempts times are marked failed instead, so a
+// recipient that keeps crashing workers cannot stall its blast. Returns the affected batches of
+// blasts that are still sending.
+func (s *Store) RequeueExpiredBlastRecipients(ctx context.Context, maxAttempts int) ([]ExpiredBlastBatch, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	type expiredRecipient struct {
+		BlastID     uuid.UUID `db:"blast_id"`
+		BatchNumber *int      `db:"batch_number"`
+	}
+
+	var failed, requeued []expiredRecipient
+	if err := tx.SelectContext(ctx, &failed, sqlFailExhaustedBlastRecipients, maxAttempts); err != nil {
+		return nil, fmt.Errorf("failed to fail exhausted blast recipients: %w", err)
+	}
+	if err := tx.SelectContext(ctx, &requeued, sqlRequeueExpiredBlastRecipients); err != nil {
+		return nil, fmt.Errorf("failed to requeue expired blast recipients: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	type batchKey struct {
+		blastID     uuid.UUID
+		batchNumber int
+	}
+	seen := make(map[batchKey]bool)
+	var keys []batchKey
+	var blastIDs []uuid.UUID
+	for _, r := range append(failed, requeued...) {
+		if r.BatchNumber == nil {
+			continue
+		}
+		key := batchKey{r.BlastID, *r.BatchNumber}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+		blastIDs = append(blastIDs, r.BlastID)
+	}
+
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	var blasts []struct {
+		ID        uuid.UUID `db:"id"`
+		AccountID uuid.UUID `db:"account_id"`
+	}
+	if err := s.db.SelectContext(ctx, &blasts, sqlGetSendingBlastAccounts, pq.Array(blastIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get sending blasts: %w", err)
+	}
+
+	accounts := make(map[uuid.UUID]uuid.UUID, len(blasts))
+	for _, b := range blasts {
+		accounts[b.ID] = b.AccountID
+	}
+
+	var batches []ExpiredBlastBatch
+	for _, key := range keys {
+		accountID, ok := accounts[key.blastID]
+		if !ok {
+			continue
+		}
+		batches = append(batches, ExpiredBlastBatch{BlastID: key.blastID, AccountID: accountID, BatchNumber: key.batchNumber})
+	}
+
+	return batches, nil
+}
+
+// StalledEmailBlast is a processing or sending blast that has made no progress recently
+type StalledEmailBlast struct {
+	ID             uuid.UUID `db:"id"`
+	AccountID      uuid.UUID `db:"account_id"`
+	Status         string    `db:"status"`
+	LastProgressAt time.Time `db:"last_progress_at"`
+	Pending        int       `db:"pending"`
+	InFlight       int       `db:"in_flight"`
+	LastError      *string   `db:"last_error"`
+	CurrentBatch   int       `db:"current_batch"`
+}
+
+// Outstanding reports whether the blast still has recipients to send to
+func (b StalledEmailBlast) Outstanding() bool {
+	return b.Pending > 0 || b.InFlight > 0
+}
+
+// A blast counts as progressing while it sends to recipients, releases cohorts or waits for an
+// A/B test. Sending blasts whose only remaining recipients wait for an unreleased cohort or an A/B
+// test winner are not stalled.
+const sqlGetStalledEmailBlasts = `
+WITH progress AS (
+    SELECT
+        b.id,
+        b.account_id,
+        b.status,
+        b.current_batch,
+        COALESCE(GREATEST(
+            b.started_at,
+            b.last_batch_at,
+            b.ab_test_ends_at,
+            (SELECT MAX(GREATEST(br.sent_at, br.failed_at)) FROM blast_recipients br WHERE br.blast_id = b.id),
+            (SELECT MAX(c.released_at) FROM email_blast_cohorts c WHERE c.blast_id = b.id)
+        ), b.updated_at) AS last_progress_at
+    FROM email_blasts b
+    WHERE b.status IN ('processing', 'sending') AND b.deleted_at IS NULL
+)
+SELECT
+    p.id,
+    p.account_id,
+    p.status,
+    p.current_batch,
+    p.last_progress_at,
+    (SELECT COUNT(*) FROM blast_recipients br WHERE br.blast_id = p.id AND br.status = 'pending') AS pending,
+    (SELECT COUNT(*) FROM blast_recipients br WHERE br.blast_id = p.id AND br.status IN ('queued', 'sending')) AS in_flight,
+    (SELECT br.error_message FROM blast_recipients br
+     WHERE br.blast_id = p.id AND br.status = 'failed' AND br.error_message IS NOT NULL
+     ORDER BY br.failed_at DESC LIMIT 1) AS last_error
+FROM progress p
+WHERE p.last_progress_at < $1
+  AND (
+    p.status = 'processing'
+    OR EXISTS (
+        SELECT 1
+        FROM blast_recipients br
+        JOIN email_blasts b ON b.id = br.blast_id
+        WHERE br.blast_id = p.id
+          AND br.status IN ('pending', 'queued', 'sending')
+          AND NOT (b.ab_test_last_batch IS NOT NULL AND br.batch_number > b.ab_test_last_batch
+                   AND b.ab_test_winner_variant_id IS NULL
+                   AND (b.ab_test_ends_at IS NULL OR b.ab_test_ends_at > CURRENT_TIMESTAMP))
+          AND NOT EXISTS (
+              SELECT 1 FROM email_blast_cohorts c
+              WHERE c.blast_id = br.blast_id AND c.released_at IS NULL
+                AND br.batch_number BETWEEN c.first_batch AND c.last_batch)
+    )
+    OR (
+        NOT EXISTS (SELECT 1 FROM blast_recipients br WHERE br.blast_id = p.id AND br.status IN ('pending', 'queued', 'sending'))
+        AND NOT EXISTS (SELECT 1 FROM email_blast_cohorts c WHERE c.blast_id = p.id AND c.released_at IS NULL)
+    )
+  )
+ORDER BY p.last_progress_at ASC
+`
+
+// GetStalledEmailBlasts retrieves processing and sending blasts that have made no progress since progressBefore
+func (s *Store) GetStalledEmailBlasts(ctx context.Context, progressBefore time.Time) ([]StalledEmailBlast, error) {
+	var blasts []StalledEmailBlast
+	err := s.db.SelectContext(ctx, &blasts, sqlGetStalledEmailBlasts, progressBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stalled email blasts: %w", err)
+	}
+	return blasts, nil
+}
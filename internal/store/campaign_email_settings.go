@@ -11,27 +11,29 @@ import (
 
 // CreateCampaignEmailSettingsParams represents parameters for creating email settings
 type CreateCampaignEmailSettingsParams struct {
-	CampaignID           uuid.UUID
-	FromName             *string
-	FromEmail            *string
-	ReplyTo              *string
-	VerificationRequired bool
-	SendWelcomeEmail     bool
+	CampaignID              uuid.UUID
+	FromName                *string
+	FromEmail               *string
+	ReplyTo                 *string
+	VerificationRequired    bool
+	SendWelcomeEmail        bool
+	VariantAutoPromoteAfter *int
 }
 
 // UpdateCampaignEmailSettingsParams represents parameters for updating email settings
 type UpdateCampaignEmailSettingsParams struct {
-	FromName             *string
-	FromEmail            *string
-	ReplyTo              *string
-	VerificationRequired *bool
-	SendWelcomeEmail     *bool
+	FromName                *string
+	FromEmail               *string
+	ReplyTo                 *string
+	VerificationRequired    *bool
+	SendWelcomeEmail        *bool
+	VariantAutoPromoteAfter *int
 }
 
 const sqlCreateCampaignEmailSettings = `
-INSERT INTO campaign_email_settings (campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email)
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, created_at, updated_at
+INSERT INTO campaign_email_settings (campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, variant_auto_promote_after)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, variant_auto_promote_after, created_at, updated_at
 `
 
 // CreateCampaignEmailSettings creates email settings for a campaign
@@ -43,7 +45,8 @@ func (s *Store) CreateCampaignEmailSettings(ctx context.Context, params CreateCa
 		params.FromEmail,
 		params.ReplyTo,
 		params.VerificationRequired,
-		params.SendWelcomeEmail)
+		params.SendWelcomeEmail,
+		params.VariantAutoPromoteAfter)
 	if err != nil {
 		return CampaignEmailSettings{}, fmt.Errorf("failed to create campaign email settings: %w", err)
 	}
@@ -51,7 +54,7 @@ func (s *Store) CreateCampaignEmailSettings(ctx context.Context, params CreateCa
 }
 
 const sqlGetCampaignEmailSettings = `
-SELECT id, campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, created_at, updated_at
+SELECT id, campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, variant_auto_promote_after, created_at, updated_at
 FROM campaign_email_settings
 WHERE campaign_id = $1
 `
@@ -76,9 +79,10 @@ SET from_name = COALESCE($2, from_name),
     reply_to = COALESCE($4, reply_to),
     verification_required = COALESCE($5, verification_required),
     send_welcome_email = COALESCE($6, send_welcome_email),
+    variant_auto_promote_after = COALESCE($7, variant_auto_promote_after),
     updated_at = CURRENT_TIMESTAMP
 WHERE campaign_id = $1
-RETURNING id, campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, created_at, updated_at
+RETURNING id, campaign_id, from_name, from_email, reply_to, verification_required, send_welcome_email, variant_auto_promote_after, created_at, updated_at
 `
 
 // UpdateCampaignEmailSettings updates email settings for a campaign
@@ -90,7 +94,8 @@ func (s *Store) UpdateCampaignEmailSettings(ctx context.Context, campaignID uuid
 		params.FromEmail,
 		params.ReplyTo,
 		params.VerificationRequired,
-		params.SendWelcomeEmail)
+		params.SendWelcomeEmail,
+		params.VariantAutoPromoteAfter)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return CampaignEmailSettings{}, ErrNotFound
@@ -137,17 +142,18 @@ func (s *Store) UpsertCampaignEmailSettings(ctx context.Context, params CreateCa
 
 	// Update existing settings
 	updateParams := UpdateCampaignEmailSettingsParams{
-		FromName:             params.FromName,
-		FromEmail:            params.FromEmail,
-		ReplyTo:              params.ReplyTo,
-		VerificationRequired: &params.VerificationRequired,
-		SendWelcomeEmail:     &params.SendWelcomeEmail,
+		FromName:                params.FromName,
+		FromEmail:               params.FromEmail,
+		ReplyTo:                 params.ReplyTo,
+		VerificationRequired:    &params.VerificationRequired,
+		SendWelcomeEmail:        &params.SendWelcomeEmail,
+		VariantAutoPromoteAfter: params.VariantAutoPromoteAfter,
 	}
 
 	// Only update if different from existing
 	if existing.FromName != params.FromName || existing.FromEmail != params.FromEmail ||
 		existing.ReplyTo != params.ReplyTo || existing.VerificationRequired != params.VerificationRequired ||
-		existing.SendWelcomeEmail != params.SendWelcomeEmail {
+		existing.SendWelcomeEmail != params.SendWelcomeEmail || params.VariantAutoPromoteAfter != nil {
 		return s.UpdateCampaignEmailSettings(ctx, params.CampaignID, updateParams)
 	}
 
@@ -21,12 +21,15 @@ type CreateCampaignEmailTemplateParams struct {
 	SendAutomatically bool
 	VariantName       *string
 	VariantWeight     *int
+	TextBody          *string
+	Headers           *JSONB
+	Attachments       EmailAttachments
 }
 
 const sqlCreateCampaignEmailTemplate = `
-INSERT INTO campaign_email_templates (campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-RETURNING id, campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
+INSERT INTO campaign_email_templates (campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight, text_body, headers, attachments)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+RETURNING id, campaign_id, name, type, subject, html_body, text_body, blocks_json, headers, attachments, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
 `
 
 // CreateCampaignEmailTemplate creates a new campaign email template
@@ -42,7 +45,10 @@ func (s *Store) CreateCampaignEmailTemplate(ctx context.Context, params CreateCa
 		params.Enabled,
 		params.SendAutomatically,
 		params.VariantName,
-		params.VariantWeight)
+		params.VariantWeight,
+		params.TextBody,
+		params.Headers,
+		params.Attachments)
 	if err != nil {
 		return CampaignEmailTemplate{}, fmt.Errorf("failed to create campaign email template: %w", err)
 	}
@@ -50,7 +56,7 @@ func (s *Store) CreateCampaignEmailTemplate(ctx context.Context, params CreateCa
 }
 
 const sqlGetCampaignEmailTemplateByID = `
-SELECT id, campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
+SELECT id, campaign_id, name, type, subject, html_body, text_body, blocks_json, headers, attachments, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
 FROM campaign_email_templates
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -69,7 +75,7 @@ func (s *Store) GetCampaignEmailTemplateByID(ctx context.Context, templateID uui
 }
 
 const sqlGetCampaignEmailTemplatesByCampaign = `
-SELECT id, campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
+SELECT id, campaign_id, name, type, subject, html_body, text_body, blocks_json, headers, attachments, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
 FROM campaign_email_templates
 WHERE campaign_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -86,7 +92,7 @@ func (s *Store) GetCampaignEmailTemplatesByCampaign(ctx context.Context, campaig
 }
 
 const sqlGetCampaignEmailTemplatesByAccount = `
-SELECT et.id, et.campaign_id, et.name, et.type, et.subject, et.html_body, et.blocks_json, et.enabled, et.send_automatically, et.variant_name, et.variant_weight, et.created_at, et.updated_at, et.deleted_at
+SELECT et.id, et.campaign_id, et.name, et.type, et.subject, et.html_body, et.text_body, et.blocks_json, et.headers, et.attachments, et.enabled, et.send_automatically, et.variant_name, et.variant_weight, et.created_at, et.updated_at, et.deleted_at
 FROM campaign_email_templates et
 JOIN campaigns c ON et.campaign_id = c.id
 WHERE c.account_id = $1 AND et.deleted_at IS NULL AND c.deleted_at IS NULL
@@ -104,7 +110,7 @@ func (s *Store) GetCampaignEmailTemplatesByAccount(ctx context.Context, accountI
 }
 
 const sqlGetCampaignEmailTemplateByType = `
-SELECT id, campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
+SELECT id, campaign_id, name, type, subject, html_body, text_body, blocks_json, headers, attachments, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
 FROM campaign_email_templates
 WHERE campaign_id = $1 AND type = $2 AND enabled = TRUE AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -124,6 +130,24 @@ func (s *Store) GetCampaignEmailTemplateByType(ctx context.Context, campaignID u
 	return template, nil
 }
 
+const sqlGetEnabledCampaignEmailTemplatesByType = `
+SELECT id, campaign_id, name, type, subject, html_body, text_body, blocks_json, headers, attachments, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
+FROM campaign_email_templates
+WHERE campaign_id = $1 AND type = $2 AND enabled = TRUE AND deleted_at IS NULL
+ORDER BY created_at ASC
+`
+
+// GetEnabledCampaignEmailTemplatesByType retrieves every enabled template of a type for a campaign.
+// Multiple results are A/B variants competing for the same automatic email.
+func (s *Store) GetEnabledCampaignEmailTemplatesByType(ctx context.Context, campaignID uuid.UUID, templateType string) ([]CampaignEmailTemplate, error) {
+	var templates []CampaignEmailTemplate
+	err := s.db.SelectContext(ctx, &templates, sqlGetEnabledCampaignEmailTemplatesByType, campaignID, templateType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get enabled campaign email templates by type: %w", err)
+	}
+	return templates, nil
+}
+
 const sqlUpdateCampaignEmailTemplate = `
 UPDATE campaign_email_templates
 SET name = COALESCE($2, name),
@@ -132,9 +156,14 @@ SET name = COALESCE($2, name),
     blocks_json = COALESCE($5, blocks_json),
     enabled = COALESCE($6, enabled),
     send_automatically = COALESCE($7, send_automatically),
+    variant_name = COALESCE($8, variant_name),
+    variant_weight = COALESCE($9, variant_weight),
+    text_body = COALESCE($10, text_body),
+    headers = COALESCE($11, headers),
+    attachments = COALESCE($12, attachments),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, campaign_id, name, type, subject, html_body, blocks_json, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
+RETURNING id, campaign_id, name, type, subject, html_body, text_body, blocks_json, headers, attachments, enabled, send_automatically, variant_name, variant_weight, created_at, updated_at, deleted_at
 `
 
 // UpdateCampaignEmailTemplateParams represents parameters for updating a campaign email template
@@ -145,6 +174,11 @@ type UpdateCampaignEmailTemplateParams struct {
 	BlocksJSON        *JSONB
 	Enabled           *bool
 	SendAutomatically *bool
+	VariantName       *string
+	VariantWeight     *int
+	TextBody          *string
+	Headers           *JSONB
+	Attachments       EmailAttachments
 }
 
 // UpdateCampaignEmailTemplate updates a campaign email template
@@ -157,7 +191,12 @@ func (s *Store) UpdateCampaignEmailTemplate(ctx context.Context, templateID uuid
 		params.HTMLBody,
 		params.BlocksJSON,
 		params.Enabled,
-		params.SendAutomatically)
+		params.SendAutomatically,
+		params.VariantName,
+		params.VariantWeight,
+		params.TextBody,
+		params.Headers,
+		params.Attachments)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return CampaignEmailTemplate{}, ErrNotFound
@@ -205,12 +244,13 @@ type CreateEmailLogParams struct {
 	Subject            string
 	Type               string
 	ProviderMessageID  *string
+	VariantName        *string
 }
 
 const sqlCreateEmailLog = `
-INSERT INTO email_logs (campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, provider_message_id)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-RETURNING id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, created_at, updated_at
+INSERT INTO email_logs (campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, provider_message_id, variant_name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, variant_name, created_at, updated_at
 `
 
 // CreateEmailLog creates a new email log entry
@@ -225,7 +265,8 @@ func (s *Store) CreateEmailLog(ctx context.Context, params CreateEmailLogParams)
 		params.RecipientEmail,
 		params.Subject,
 		params.Type,
-		params.ProviderMessageID)
+		params.ProviderMessageID,
+		params.VariantName)
 	if err != nil {
 		return EmailLog{}, fmt.Errorf("failed to create email log: %w", err)
 	}
@@ -233,7 +274,7 @@ func (s *Store) CreateEmailLog(ctx context.Context, params CreateEmailLogParams)
 }
 
 const sqlGetEmailLogByID = `
-SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, created_at, updated_at
+SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, variant_name, created_at, updated_at
 FROM email_logs
 WHERE id = $1
 `
@@ -252,7 +293,7 @@ func (s *Store) GetEmailLogByID(ctx context.Context, logID uuid.UUID) (EmailLog,
 }
 
 const sqlGetEmailLogsByUser = `
-SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, created_at, updated_at
+SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, variant_name, created_at, updated_at
 FROM email_logs
 WHERE user_id = $1
 ORDER BY created_at DESC
@@ -270,7 +311,7 @@ func (s *Store) GetEmailLogsByUser(ctx context.Context, userID uuid.UUID, limit,
 }
 
 const sqlGetEmailLogsByCampaign = `
-SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, created_at, updated_at
+SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, variant_name, created_at, updated_at
 FROM email_logs
 WHERE campaign_id = $1
 ORDER BY created_at DESC
@@ -356,7 +397,7 @@ func (s *Store) IncrementEmailClickCount(ctx context.Context, logID uuid.UUID) e
 }
 
 const sqlGetEmailLogByProviderMessageID = `
-SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, created_at, updated_at
+SELECT id, campaign_id, user_id, campaign_template_id, blast_template_id, blast_id, recipient_email, subject, type, status, provider_message_id, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, bounce_reason, open_count, click_count, variant_name, created_at, updated_at
 FROM email_logs
 WHERE provider_message_id = $1
 `
@@ -373,3 +414,126 @@ func (s *Store) GetEmailLogByProviderMessageID(ctx context.Context, providerMess
 	}
 	return log, nil
 }
+
+// CampaignEmailTemplateVariantStats represents aggregate send and engagement counts for one template variant
+type CampaignEmailTemplateVariantStats struct {
+	TemplateID    uuid.UUID `db:"template_id" json:"template_id"`
+	TemplateName  string    `db:"template_name" json:"template_name"`
+	VariantName   *string   `db:"variant_name" json:"variant_name,omitempty"`
+	VariantWeight *int      `db:"variant_weight" json:"variant_weight,omitempty"`
+	Enabled       bool      `db:"enabled" json:"enabled"`
+	Sent          int       `db:"sent" json:"sent"`
+	Opened        int       `db:"opened" json:"opened"`
+	Clicked       int       `db:"clicked" json:"clicked"`
+	Verified      int       `db:"verified" json:"verified"`
+}
+
+const sqlGetCampaignEmailTemplateVariantStats = `
+SELECT
+    t.id AS template_id,
+    t.name AS template_name,
+    t.variant_name,
+    t.variant_weight,
+    t.enabled,
+    COUNT(l.id) FILTER (WHERE l.status <> 'failed') AS sent,
+    COUNT(l.id) FILTER (WHERE l.opened_at IS NOT NULL) AS opened,
+    COUNT(l.id) FILTER (WHERE l.clicked_at IS NOT NULL) AS clicked,
+    COUNT(l.id) FILTER (WHERE wu.email_verified = TRUE) AS verified
+FROM campaign_email_templates t
+LEFT JOIN email_logs l ON l.campaign_template_id = t.id
+LEFT JOIN waitlist_users wu ON wu.id = l.user_id
+WHERE t.campaign_id = $1 AND t.type = $2 AND t.deleted_at IS NULL
+GROUP BY t.id, t.name, t.variant_name, t.variant_weight, t.enabled, t.created_at
+ORDER BY t.created_at ASC
+`
+
+// GetCampaignEmailTemplateVariantStats retrieves per-variant send, open, click and verify counts
+// for all templates of a type in a campaign
+func (s *Store) GetCampaignEmailTemplateVariantStats(ctx context.Context, campaignID uuid.UUID, templateType string) ([]CampaignEmailTemplateVariantStats, error) {
+	var stats []CampaignEmailTemplateVariantStats
+	err := s.db.SelectContext(ctx, &stats, sqlGetCampaignEmailTemplateVariantStats, campaignID, templateType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign email template variant stats: %w", err)
+	}
+	return stats, nil
+}
+
+// CampaignEmailVariantTest identifies a template type of a campaign with several enabled variants
+// and automatic promotion of the winner
+type CampaignEmailVariantTest struct {
+	CampaignID       uuid.UUID `db:"campaign_id"`
+	TemplateType     string    `db:"template_type"`
+	AutoPromoteAfter int       `db:"variant_auto_promote_after"`
+}
+
+const sqlGetCampaignEmailVariantTests = `
+SELECT t.campaign_id, t.type AS template_type, s.variant_auto_promote_after
+FROM campaign_email_templates t
+JOIN campaign_email_settings s ON s.campaign_id = t.campaign_id
+JOIN campaigns c ON c.id = t.campaign_id
+WHERE s.variant_auto_promote_after IS NOT NULL
+  AND t.enabled = TRUE
+  AND t.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+GROUP BY t.campaign_id, t.type, s.variant_auto_promote_after
+HAVING COUNT(*) > 1
+`
+
+// GetCampaignEmailVariantTests retrieves the running template variant tests whose winner is promoted
+// automatically
+func (s *Store) GetCampaignEmailVariantTests(ctx context.Context) ([]CampaignEmailVariantTest, error) {
+	var tests []CampaignEmailVariantTest
+	err := s.db.SelectContext(ctx, &tests, sqlGetCampaignEmailVariantTests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign email variant tests: %w", err)
+	}
+	return tests, nil
+}
+
+const sqlDisableLosingCampaignEmailTemplateVariants = `
+UPDATE campaign_email_templates
+SET enabled = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE campaign_id = $1 AND type = $2 AND id <> $3 AND enabled = TRUE AND deleted_at IS NULL
+`
+
+const sqlPromoteCampaignEmailTemplateVariant = `
+UPDATE campaign_email_templates
+SET variant_weight = 100,
+    enabled = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND campaign_id = $2 AND type = $3 AND deleted_at IS NULL
+`
+
+// PromoteCampaignEmailTemplateVariant makes the winning variant the only enabled template of its type
+func (s *Store) PromoteCampaignEmailTemplateVariant(ctx context.Context, campaignID uuid.UUID, templateType string, winnerID uuid.UUID) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, sqlPromoteCampaignEmailTemplateVariant, winnerID, campaignID, templateType)
+	if err != nil {
+		return fmt.Errorf("failed to promote campaign email template variant: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, sqlDisableLosingCampaignEmailTemplateVariants, campaignID, templateType, winnerID); err != nil {
+		return fmt.Errorf("failed to disable losing campaign email template variants: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
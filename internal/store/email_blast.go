@@ -16,24 +16,50 @@ type CreateEmailBlastParams struct {
 	AccountID             uuid.UUID
 	BlastTemplateID       uuid.UUID
 	SegmentIDs            []uuid.UUID
+	AudienceID            *uuid.UUID
 	Name                  string
 	Subject               string
 	ScheduledAt           *time.Time
 	BatchSize             int
 	SendThrottlePerSecond *int
 	CreatedBy             *uuid.UUID
+	ABTestPercentage      *int
+	ABTestWaitMinutes     *int
+	ABTestMetric          *string
+	SendAtLocalTime       *string
+	FallbackTimezone      *string
+	Variants              []CreateEmailBlastVariantParams
+}
+
+// CreateEmailBlastVariantParams represents parameters for creating an A/B test variant of an email blast
+type CreateEmailBlastVariantParams struct {
+	BlastTemplateID uuid.UUID
+	Name            string
+	Subject         string
 }
 
 const sqlCreateEmailBlast = `
-INSERT INTO email_blasts (account_id, blast_template_id, segment_ids, name, subject, scheduled_at, batch_size, send_throttle_per_second, created_by, status)
-VALUES ($1, $2, $3, $4, $5, $6::timestamptz, $7, $8, $9, CASE WHEN $6::timestamptz IS NOT NULL THEN 'scheduled'::email_blast_status ELSE 'draft'::email_blast_status END)
-RETURNING id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+INSERT INTO email_blasts (account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, batch_size, send_throttle_per_second, created_by, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, send_at_local_time, fallback_timezone, status)
+VALUES ($1, $2, $3, $15, $4, $5, $6::timestamptz, $7, $8, $9, $10, $11, $12, $13, $14, CASE WHEN $6::timestamptz IS NOT NULL THEN 'scheduled'::email_blast_status ELSE 'draft'::email_blast_status END)
+RETURNING id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
+`
+
+const sqlCreateEmailBlastVariant = `
+INSERT INTO email_blast_variants (blast_id, blast_template_id, name, subject)
+VALUES ($1, $2, $3, $4)
+RETURNING id, blast_id, blast_template_id, name, subject, created_at
 `
 
-// CreateEmailBlast creates a new email blast
+// CreateEmailBlast creates a new email blast along with its A/B test variants
 func (s *Store) CreateEmailBlast(ctx context.Context, params CreateEmailBlastParams) (EmailBlast, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return EmailBlast{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	var blast EmailBlast
-	err := s.db.GetContext(ctx, &blast, sqlCreateEmailBlast,
+	err = tx.GetContext(ctx, &blast, sqlCreateEmailBlast,
 		params.AccountID,
 		params.BlastTemplateID,
 		pq.Array(params.SegmentIDs),
@@ -42,15 +68,35 @@ func (s *Store) CreateEmailBlast(ctx context.Context, params CreateEmailBlastPar
 		params.ScheduledAt,
 		params.BatchSize,
 		params.SendThrottlePerSecond,
-		params.CreatedBy)
+		params.CreatedBy,
+		params.ABTestPercentage,
+		params.ABTestWaitMinutes,
+		params.ABTestMetric,
+		params.SendAtLocalTime,
+		params.FallbackTimezone,
+		params.AudienceID)
 	if err != nil {
 		return EmailBlast{}, fmt.Errorf("failed to create email blast: %w", err)
 	}
+
+	for _, v := range params.Variants {
+		var variant EmailBlastVariant
+		err = tx.GetContext(ctx, &variant, sqlCreateEmailBlastVariant, blast.ID, v.BlastTemplateID, v.Name, v.Subject)
+		if err != nil {
+			return EmailBlast{}, fmt.Errorf("failed to create email blast variant: %w", err)
+		}
+		blast.Variants = append(blast.Variants, variant)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EmailBlast{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return blast, nil
 }
 
 const sqlGetEmailBlastByID = `
-SELECT id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+SELECT id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
 FROM email_blasts
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -69,7 +115,7 @@ func (s *Store) GetEmailBlastByID(ctx context.Context, blastID uuid.UUID) (Email
 }
 
 const sqlGetEmailBlastsByAccount = `
-SELECT id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+SELECT id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
 FROM email_blasts
 WHERE account_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -118,7 +164,7 @@ SET name = COALESCE($2, name),
     batch_size = COALESCE($5, batch_size),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL AND status = 'draft'
-RETURNING id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+RETURNING id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
 `
 
 // UpdateEmailBlast updates an email blast (only if in draft status)
@@ -172,7 +218,7 @@ SET status = $2::email_blast_status,
     completed_at = CASE WHEN $2 IN ('completed', 'cancelled', 'failed') THEN CURRENT_TIMESTAMP ELSE completed_at END,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+RETURNING id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
 `
 
 // UpdateEmailBlastStatus updates the status of an email blast
@@ -188,6 +234,29 @@ func (s *Store) UpdateEmailBlastStatus(ctx context.Context, blastID uuid.UUID, s
 	return blast, nil
 }
 
+const sqlCompleteEmailBlast = `
+UPDATE email_blasts
+SET status = 'completed',
+    completed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'sending' AND deleted_at IS NULL
+`
+
+// CompleteEmailBlast marks a sending blast as completed. It returns false when the blast was not
+// sending, e.g. because another worker completed it first, so only one caller announces completion.
+func (s *Store) CompleteEmailBlast(ctx context.Context, blastID uuid.UUID) (bool, error) {
+	res, err := s.db.ExecContext(ctx, sqlCompleteEmailBlast, blastID)
+	if err != nil {
+		return false, fmt.Errorf("failed to complete email blast: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
 const sqlUpdateEmailBlastTotalRecipients = `
 UPDATE email_blasts
 SET total_recipients = $2,
@@ -274,7 +343,7 @@ func (s *Store) UpdateEmailBlastProgress(ctx context.Context, blastID uuid.UUID,
 }
 
 const sqlGetScheduledBlasts = `
-SELECT id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+SELECT id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
 FROM email_blasts
 WHERE status = 'scheduled' AND scheduled_at <= $1 AND deleted_at IS NULL
 ORDER BY scheduled_at ASC
@@ -305,7 +374,7 @@ type CreateBlastRecipientParams struct {
 const sqlCreateBlastRecipient = `
 INSERT INTO blast_recipients (blast_id, user_id, email, batch_number)
 VALUES ($1, $2, $3, $4)
-RETURNING id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, created_at, updated_at
+RETURNING id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, variant_id, timezone, lease_owner, lease_expires_at, attempts, created_at, updated_at
 `
 
 // CreateBlastRecipient creates a new blast recipient
@@ -343,7 +412,8 @@ func (s *Store) CreateBlastRecipientsBulk(ctx context.Context, blastID uuid.UUID
 	defer stmt.Close()
 
 	for i, user := range users {
-		batchNumber := i / batchSize
+		// Batches are 1-based to match the batch numbers dispatched by the blast worker
+		batchNumber := i/batchSize + 1
 		_, err := stmt.ExecContext(ctx, blastID, user.ID, user.Email, batchNumber)
 		if err != nil {
 			return fmt.Errorf("failed to insert blast recipient: %w", err)
@@ -359,30 +429,41 @@ func (s *Store) CreateBlastRecipientsBulk(ctx context.Context, blastID uuid.UUID
 
 // CreateBlastRecipientsFromMultipleSegments creates recipients from multiple segments with deduplication
 func (s *Store) CreateBlastRecipientsFromMultipleSegments(ctx context.Context, blastID uuid.UUID, segmentIDs []uuid.UUID, batchSize int) (int, error) {
-	if len(segmentIDs) == 0 {
+	allUsers, err := s.getBlastUsersFromSegments(ctx, segmentIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(allUsers) == 0 {
 		return 0, nil
 	}
 
+	// Create recipients in bulk
+	err = s.CreateBlastRecipientsBulk(ctx, blastID, allUsers, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(allUsers), nil
+}
+
+// getBlastUsersFromSegments returns the users matching any of the segments, deduplicated by email
+func (s *Store) getBlastUsersFromSegments(ctx context.Context, segmentIDs []uuid.UUID) ([]WaitlistUser, error) {
 	// Use a map to deduplicate users by email across segments
 	seenEmails := make(map[string]bool)
 	var allUsers []WaitlistUser
 
 	for _, segmentID := range segmentIDs {
-		// Get the segment to get its campaign and filter criteria
+		// Get the segment to get its campaign and type
 		segment, err := s.GetSegmentByID(ctx, segmentID)
 		if err != nil {
-			return 0, fmt.Errorf("failed to get segment %s: %w", segmentID, err)
-		}
-
-		criteria, err := ParseFilterCriteria(segment.FilterCriteria)
-		if err != nil {
-			return 0, fmt.Errorf("failed to parse filter criteria for segment %s: %w", segmentID, err)
+			return nil, fmt.Errorf("failed to get segment %s: %w", segmentID, err)
 		}
 
 		// Get users for this segment
-		users, err := s.GetUsersForBlast(ctx, segment.CampaignID, criteria)
+		users, err := s.GetSegmentUsers(ctx, segment)
 		if err != nil {
-			return 0, fmt.Errorf("failed to get users for segment %s: %w", segmentID, err)
+			return nil, fmt.Errorf("failed to get users for segment %s: %w", segmentID, err)
 		}
 
 		// Deduplicate by email
@@ -394,21 +475,11 @@ func (s *Store) CreateBlastRecipientsFromMultipleSegments(ctx context.Context, b
 		}
 	}
 
-	if len(allUsers) == 0 {
-		return 0, nil
-	}
-
-	// Create recipients in bulk
-	err := s.CreateBlastRecipientsBulk(ctx, blastID, allUsers, batchSize)
-	if err != nil {
-		return 0, err
-	}
-
-	return len(allUsers), nil
+	return allUsers, nil
 }
 
 const sqlGetBlastRecipientByID = `
-SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, created_at, updated_at
+SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, variant_id, timezone, lease_owner, lease_expires_at, attempts, created_at, updated_at
 FROM blast_recipients
 WHERE id = $1
 `
@@ -427,7 +498,7 @@ func (s *Store) GetBlastRecipientByID(ctx context.Context, recipientID uuid.UUID
 }
 
 const sqlGetPendingBlastRecipients = `
-SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, created_at, updated_at
+SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, variant_id, timezone, lease_owner, lease_expires_at, attempts, created_at, updated_at
 FROM blast_recipients
 WHERE blast_id = $1 AND batch_number = $2 AND status = 'pending'
 ORDER BY created_at ASC
@@ -445,7 +516,7 @@ func (s *Store) GetPendingBlastRecipients(ctx context.Context, blastID uuid.UUID
 }
 
 const sqlGetBlastRecipientsByBlast = `
-SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, created_at, updated_at
+SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, variant_id, timezone, lease_owner, lease_expires_at, attempts, created_at, updated_at
 FROM blast_recipients
 WHERE blast_id = $1
 ORDER BY created_at ASC
@@ -490,6 +561,8 @@ SET status = $2,
     bounced_at = CASE WHEN $2 = 'bounced' THEN CURRENT_TIMESTAMP ELSE bounced_at END,
     failed_at = CASE WHEN $2 = 'failed' THEN CURRENT_TIMESTAMP ELSE failed_at END,
     error_message = COALESCE($4, error_message),
+    lease_owner = CASE WHEN $2 = 'sending' THEN lease_owner ELSE NULL END,
+    lease_expires_at = CASE WHEN $2 = 'sending' THEN lease_expires_at ELSE NULL END,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 `
@@ -513,6 +586,37 @@ func (s *Store) UpdateBlastRecipientStatus(ctx context.Context, recipientID uuid
 	return nil
 }
 
+const sqlMarkBlastRecipientSent = `
+UPDATE blast_recipients
+SET status = 'sent',
+    sent_at = CURRENT_TIMESTAMP,
+    provider_message_id = $2,
+    lease_owner = NULL,
+    lease_expires_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+// MarkBlastRecipientSent marks a blast recipient as sent with the provider message ID of its email,
+// which engagement events are matched by
+func (s *Store) MarkBlastRecipientSent(ctx context.Context, recipientID uuid.UUID, providerMessageID string) error {
+	res, err := s.db.ExecContext(ctx, sqlMarkBlastRecipientSent, recipientID, providerMessageID)
+	if err != nil {
+		return fmt.Errorf("failed to mark blast recipient sent: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
 // BlastRecipientStats represents aggregate stats for blast recipients
 type BlastRecipientStats struct {
 	Total     int `db:"total"`
@@ -525,6 +629,10 @@ type BlastRecipientStats struct {
 	Clicked   int `db:"clicked"`
 	Bounced   int `db:"bounced"`
 	Failed    int `db:"failed"`
+
+	// First and last send times, used to compute the effective send rate
+	FirstSentAt *time.Time `db:"first_sent_at"`
+	LastSentAt  *time.Time `db:"last_sent_at"`
 }
 
 const sqlGetBlastRecipientStats = `
@@ -538,7 +646,9 @@ SELECT
     COUNT(*) FILTER (WHERE status = 'opened') as opened,
     COUNT(*) FILTER (WHERE status = 'clicked') as clicked,
     COUNT(*) FILTER (WHERE status = 'bounced') as bounced,
-    COUNT(*) FILTER (WHERE status = 'failed') as failed
+    COUNT(*) FILTER (WHERE status = 'failed') as failed,
+    MIN(sent_at) as first_sent_at,
+    MAX(sent_at) as last_sent_at
 FROM blast_recipients
 WHERE blast_id = $1
 `
@@ -570,7 +680,7 @@ func (s *Store) GetMaxBatchNumber(ctx context.Context, blastID uuid.UUID) (int,
 }
 
 const sqlGetBlastRecipientsByBatch = `
-SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, created_at, updated_at
+SELECT id, blast_id, user_id, email, status, email_log_id, queued_at, sent_at, delivered_at, opened_at, clicked_at, bounced_at, failed_at, error_message, batch_number, variant_id, timezone, lease_owner, lease_expires_at, attempts, created_at, updated_at
 FROM blast_recipients
 WHERE blast_id = $1 AND batch_number = $2
 ORDER BY created_at ASC
@@ -638,7 +748,7 @@ func (s *Store) ScheduleBlast(ctx context.Context, blastID uuid.UUID, scheduledA
 	    scheduled_at = $2,
 	    updated_at = CURRENT_TIMESTAMP
 	WHERE id = $1 AND deleted_at IS NULL AND status = 'draft'
-	RETURNING id, account_id, blast_template_id, segment_ids, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, created_by, created_at, updated_at, deleted_at
+	RETURNING id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
 	`
 
 	var blast EmailBlast
@@ -684,13 +794,8 @@ func (s *Store) PreviewBlastRecipients(ctx context.Context, segmentIDs []uuid.UU
 			return BlastRecipientsPreview{}, fmt.Errorf("failed to get segment %s: %w", segmentID, err)
 		}
 
-		criteria, err := ParseFilterCriteria(segment.FilterCriteria)
-		if err != nil {
-			return BlastRecipientsPreview{}, fmt.Errorf("failed to parse filter criteria for segment %s: %w", segmentID, err)
-		}
-
 		// Count users for this segment
-		count, err := s.CountUsersMatchingCriteria(ctx, segment.CampaignID, criteria)
+		count, err := s.CountSegmentUsers(ctx, segment)
 		if err != nil {
 			return BlastRecipientsPreview{}, fmt.Errorf("failed to count users for segment %s: %w", segmentID, err)
 		}
@@ -704,7 +809,7 @@ func (s *Store) PreviewBlastRecipients(ctx context.Context, segmentIDs []uuid.UU
 		totalBeforeDedup += count
 
 		// Get users to count unique emails (for dedup calculation)
-		users, err := s.GetUsersForBlast(ctx, segment.CampaignID, criteria)
+		users, err := s.GetSegmentUsers(ctx, segment)
 		if err != nil {
 			return BlastRecipientsPreview{}, fmt.Errorf("failed to get users for segment %s: %w", segmentID, err)
 		}
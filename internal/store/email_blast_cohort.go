@@ -0,0 +1,304 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	// Embed the timezone database so recipient timezones resolve on hosts without zoneinfo
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// blastCohortPlan is the timezone cohort a group of recipients is sent in
+type blastCohortPlan struct {
+	Timezone   string
+	ReleaseAt  time.Time
+	FirstBatch int
+	LastBatch  int
+	Users      []WaitlistUser
+}
+
+// nextLocalSendTime returns the first time at or after after when the clock reads sendAt ("HH:MM") in loc
+func nextLocalSendTime(after time.Time, sendAt string, loc *time.Location) (time.Time, error) {
+	clock, err := time.Parse("15:04", sendAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid local send time %q: %w", sendAt, err)
+	}
+
+	local := after.In(loc)
+	releaseAt := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if releaseAt.Before(after) {
+		releaseAt = time.Date(local.Year(), local.Month(), local.Day()+1, clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+
+	return releaseAt, nil
+}
+
+// planBlastCohorts groups users by timezone and numbers their batches so cohorts are sent in
+// order of their release time. Users without a valid timezone are sent in the fallback timezone.
+func planBlastCohorts(users []WaitlistUser, sendAt string, fallback *time.Location, after time.Time, batchSize int) ([]blastCohortPlan, error) {
+	locations := make(map[string]*time.Location)
+	byTimezone := make(map[string]*blastCohortPlan)
+
+	for _, user := range users {
+		loc := fallback
+		if user.UserTimezone != nil && *user.UserTimezone != "" && *user.UserTimezone != "Local" {
+			cached, ok := locations[*user.UserTimezone]
+			if !ok {
+				cached, _ = time.LoadLocation(*user.UserTimezone)
+				locations[*user.UserTimezone] = cached
+			}
+			if cached != nil {
+				loc = cached
+			}
+		}
+
+		cohort, ok := byTimezone[loc.String()]
+		if !ok {
+			releaseAt, err := nextLocalSendTime(after, sendAt, loc)
+			if err != nil {
+				return nil, err
+			}
+			cohort = &blastCohortPlan{Timezone: loc.String(), ReleaseAt: releaseAt}
+			byTimezone[loc.String()] = cohort
+		}
+		cohort.Users = append(cohort.Users, user)
+	}
+
+	cohorts := make([]blastCohortPlan, 0, len(byTimezone))
+	for _, cohort := range byTimezone {
+		cohorts = append(cohorts, *cohort)
+	}
+
+	sort.Slice(cohorts, func(i, j int) bool {
+		if !cohorts[i].ReleaseAt.Equal(cohorts[j].ReleaseAt) {
+			return cohorts[i].ReleaseAt.Before(cohorts[j].ReleaseAt)
+		}
+		return cohorts[i].Timezone < cohorts[j].Timezone
+	})
+
+	// Batches are 1-based and never span two cohorts
+	nextBatch := 1
+	for i := range cohorts {
+		cohorts[i].FirstBatch = nextBatch
+		cohorts[i].LastBatch = nextBatch + (len(cohorts[i].Users)+batchSize-1)/batchSize - 1
+		nextBatch = cohorts[i].LastBatch + 1
+	}
+
+	return cohorts, nil
+}
+
+const sqlCreateEmailBlastCohort = `
+INSERT INTO email_blast_cohorts (blast_id, timezone, release_at, first_batch, last_batch, recipient_count)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+const sqlCreateBlastCohortRecipients = `
+INSERT INTO blast_recipients (blast_id, user_id, email, batch_number, timezone)
+SELECT $1, d.user_id, d.email, d.batch_number, $2
+FROM unnest($3::uuid[], $4::text[], $5::int[]) AS d(user_id, email, batch_number)
+ON CONFLICT (blast_id, user_id) DO NOTHING
+`
+
+// CreateBlastRecipientCohortsFromMultipleSegments creates recipients from multiple segments with
+// deduplication and splits them into timezone cohorts released at sendAt ("HH:MM") local time.
+// Each cohort is released at the first occurrence of its local send time at or after after.
+func (s *Store) CreateBlastRecipientCohortsFromMultipleSegments(ctx context.Context, blastID uuid.UUID, segmentIDs []uuid.UUID, batchSize int, sendAt, fallbackTimezone string, after time.Time) (int, error) {
+	users, err := s.getBlastUsersFromSegments(ctx, segmentIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.createBlastRecipientCohorts(ctx, blastID, users, batchSize, sendAt, fallbackTimezone, after)
+}
+
+// CreateBlastRecipientCohortsFromAudience creates recipients from an audience and splits them into
+// timezone cohorts like CreateBlastRecipientCohortsFromMultipleSegments
+func (s *Store) CreateBlastRecipientCohortsFromAudience(ctx context.Context, blastID, audienceID uuid.UUID, batchSize int, sendAt, fallbackTimezone string, after time.Time) (int, error) {
+	users, err := s.getBlastUsersFromAudience(ctx, audienceID)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.createBlastRecipientCohorts(ctx, blastID, users, batchSize, sendAt, fallbackTimezone, after)
+}
+
+// createBlastRecipientCohorts stores users as blast recipients grouped into timezone cohorts, with
+// one insert per cohort. It returns the number of recipients created.
+func (s *Store) createBlastRecipientCohorts(ctx context.Context, blastID uuid.UUID, users []WaitlistUser, batchSize int, sendAt, fallbackTimezone string, after time.Time) (int, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+
+	fallback, err := time.LoadLocation(fallbackTimezone)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fallback timezone %q: %w", fallbackTimezone, err)
+	}
+
+	if len(users) == 0 {
+		return 0, nil
+	}
+
+	cohorts, err := planBlastCohorts(users, sendAt, fallback, after, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	total := 0
+	for _, cohort := range cohorts {
+		ids := make([]string, len(cohort.Users))
+		emails := make([]string, len(cohort.Users))
+		batchNumbers := make([]int, len(cohort.Users))
+		for i, user := range cohort.Users {
+			ids[i] = user.ID.String()
+			emails[i] = user.Email
+			batchNumbers[i] = cohort.FirstBatch + i/batchSize
+		}
+
+		res, err := tx.ExecContext(ctx, sqlCreateBlastCohortRecipients,
+			blastID, cohort.Timezone, pq.Array(ids), pq.Array(emails), pq.Array(batchNumbers))
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert blast recipients: %w", err)
+		}
+
+		// Recipients already in the blast are skipped, so the cohort counts the rows inserted
+		inserted, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if inserted == 0 {
+			continue
+		}
+
+		_, err = tx.ExecContext(ctx, sqlCreateEmailBlastCohort,
+			blastID,
+			cohort.Timezone,
+			cohort.ReleaseAt,
+			cohort.FirstBatch,
+			cohort.LastBatch,
+			inserted)
+		if err != nil {
+			return 0, fmt.Errorf("failed to create email blast cohort: %w", err)
+		}
+		total += int(inserted)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return total, nil
+}
+
+const sqlGetEmailBlastCohorts = `
+SELECT id, blast_id, timezone, release_at, first_batch, last_batch, recipient_count, released_at, created_at
+FROM email_blast_cohorts
+WHERE blast_id = $1
+ORDER BY first_batch ASC
+`
+
+// GetEmailBlastCohorts retrieves the timezone cohorts of a blast in send order
+func (s *Store) GetEmailBlastCohorts(ctx context.Context, blastID uuid.UUID) ([]EmailBlastCohort, error) {
+	var cohorts []EmailBlastCohort
+	err := s.db.SelectContext(ctx, &cohorts, sqlGetEmailBlastCohorts, blastID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email blast cohorts: %w", err)
+	}
+	return cohorts, nil
+}
+
+// DueEmailBlastCohort is a cohort whose local send time has arrived, with the account of its blast
+type DueEmailBlastCohort struct {
+	EmailBlastCohort
+	AccountID uuid.UUID `db:"account_id"`
+}
+
+const sqlGetDueEmailBlastCohorts = `
+SELECT c.id, c.blast_id, c.timezone, c.release_at, c.first_batch, c.last_batch, c.recipient_count, c.released_at, c.created_at, b.account_id
+FROM email_blast_cohorts c
+JOIN email_blasts b ON b.id = c.blast_id
+WHERE c.released_at IS NULL AND c.release_at <= $1 AND b.status = 'sending' AND b.deleted_at IS NULL
+ORDER BY c.release_at ASC
+`
+
+// GetDueEmailBlastCohorts retrieves unreleased cohorts of sending blasts whose local send time has arrived
+func (s *Store) GetDueEmailBlastCohorts(ctx context.Context, beforeTime time.Time) ([]DueEmailBlastCohort, error) {
+	var cohorts []DueEmailBlastCohort
+	err := s.db.SelectContext(ctx, &cohorts, sqlGetDueEmailBlastCohorts, beforeTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due email blast cohorts: %w", err)
+	}
+	return cohorts, nil
+}
+
+const sqlReleaseEmailBlastCohort = `
+UPDATE email_blast_cohorts
+SET released_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND released_at IS NULL
+`
+
+// ReleaseEmailBlastCohort marks a cohort as released.
+// Returns ErrNotFound if the cohort does not exist or was already released.
+func (s *Store) ReleaseEmailBlastCohort(ctx context.Context, cohortID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, sqlReleaseEmailBlastCohort, cohortID)
+	if err != nil {
+		return fmt.Errorf("failed to release email blast cohort: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// BlastCohortStats represents the sending progress of one timezone cohort of a blast
+type BlastCohortStats struct {
+	Timezone   string     `db:"timezone" json:"timezone"`
+	ReleaseAt  time.Time  `db:"release_at" json:"release_at"`
+	ReleasedAt *time.Time `db:"released_at" json:"released_at,omitempty"`
+	Recipients int        `db:"recipients" json:"recipients"`
+	Pending    int        `db:"pending" json:"pending"`
+	Sent       int        `db:"sent" json:"sent"`
+	Failed     int        `db:"failed" json:"failed"`
+}
+
+const sqlGetBlastCohortStats = `
+SELECT
+    c.timezone,
+    c.release_at,
+    c.released_at,
+    COUNT(br.id) AS recipients,
+    COUNT(br.id) FILTER (WHERE br.status = 'pending') AS pending,
+    COUNT(br.id) FILTER (WHERE br.status IN ('sent', 'delivered', 'opened', 'clicked')) AS sent,
+    COUNT(br.id) FILTER (WHERE br.status IN ('failed', 'bounced')) AS failed
+FROM email_blast_cohorts c
+LEFT JOIN blast_recipients br ON br.blast_id = c.blast_id AND br.timezone = c.timezone
+WHERE c.blast_id = $1
+GROUP BY c.id, c.timezone, c.release_at, c.released_at, c.first_batch
+ORDER BY c.first_batch ASC
+`
+
+// GetBlastCohortStats retrieves sending progress for each timezone cohort of a blast
+func (s *Store) GetBlastCohortStats(ctx context.Context, blastID uuid.UUID) ([]BlastCohortStats, error) {
+	var stats []BlastCohortStats
+	err := s.db.SelectContext(ctx, &stats, sqlGetBlastCohortStats, blastID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blast cohort stats: %w", err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNextLocalSendTime(t *testing.T) {
+	t.Parallel()
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		after    time.Time
+		sendAt   string
+		loc      *time.Location
+		expected time.Time
+	}{
+		{
+			name:     "later today",
+			after:    time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC), // 07:00 in New York
+			sendAt:   "09:00",
+			loc:      newYork,
+			expected: time.Date(2026, 3, 2, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "already passed today",
+			after:    time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC), // 10:00 in New York
+			sendAt:   "09:00",
+			loc:      newYork,
+			expected: time.Date(2026, 3, 3, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "exactly now",
+			after:    time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+			sendAt:   "09:00",
+			loc:      time.UTC,
+			expected: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "across daylight saving change",
+			after:    time.Date(2026, 3, 7, 15, 0, 0, 0, time.UTC), // Saturday 10:00 EST
+			sendAt:   "09:00",
+			loc:      newYork,
+			expected: time.Date(2026, 3, 8, 13, 0, 0, 0, time.UTC), // Sunday 09:00 EDT
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := nextLocalSendTime(tt.after, tt.sendAt, tt.loc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+
+	if _, err := nextLocalSendTime(time.Now(), "9am", time.UTC); err == nil {
+		t.Error("expected error for invalid send time")
+	}
+}
+
+func TestPlanBlastCohorts(t *testing.T) {
+	t.Parallel()
+
+	timezone := func(tz string) *string { return &tz }
+	user := func(tz *string) WaitlistUser {
+		return WaitlistUser{ID: uuid.New(), Email: uuid.NewString() + "@example.com", UserTimezone: tz}
+	}
+
+	users := []WaitlistUser{
+		user(timezone("America/Los_Angeles")),
+		user(timezone("Asia/Tokyo")),
+		user(timezone("America/Los_Angeles")),
+		user(nil),
+		user(timezone("Not/A_Zone")),
+		user(timezone("America/Los_Angeles")),
+	}
+
+	after := time.Date(2026, 6, 1, 1, 0, 0, 0, time.UTC)
+	cohorts, err := planBlastCohorts(users, "09:00", time.UTC, after, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Users without a valid timezone fall back to UTC. Tokyo is already past 09:00 on
+	// June 1st, so its cohort goes out the next morning, after Los Angeles.
+	expected := []struct {
+		timezone   string
+		releaseAt  time.Time
+		users      int
+		firstBatch int
+		lastBatch  int
+	}{
+		{"UTC", time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC), 2, 1, 1},
+		{"America/Los_Angeles", time.Date(2026, 6, 1, 16, 0, 0, 0, time.UTC), 3, 2, 3},
+		{"Asia/Tokyo", time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), 1, 4, 4},
+	}
+
+	if len(cohorts) != len(expected) {
+		t.Fatalf("expected %d cohorts, got %d", len(expected), len(cohorts))
+	}
+
+	for i, e := range expected {
+		c := cohorts[i]
+		if c.Timezone != e.timezone {
+			t.Errorf("cohort %d: expected timezone %s, got %s", i, e.timezone, c.Timezone)
+		}
+		if !c.ReleaseAt.Equal(e.releaseAt) {
+			t.Errorf("cohort %d: expected release at %v, got %v", i, e.releaseAt, c.ReleaseAt)
+		}
+		if len(c.Users) != e.users {
+			t.Errorf("cohort %d: expected %d users, got %d", i, e.users, len(c.Users))
+		}
+		if c.FirstBatch != e.firstBatch || c.LastBatch != e.lastBatch {
+			t.Errorf("cohort %d: expected batches %d-%d, got %d-%d", i, e.firstBatch, e.lastBatch, c.FirstBatch, c.LastBatch)
+		}
+	}
+}
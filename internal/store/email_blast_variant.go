@@ -0,0 +1,202 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const sqlGetEmailBlastVariantsByBlast = `
+SELECT id, blast_id, blast_template_id, name, subject, created_at
+FROM email_blast_variants
+WHERE blast_id = $1
+ORDER BY created_at ASC, name ASC
+`
+
+// GetEmailBlastVariantsByBlast retrieves the A/B test variants of a blast
+func (s *Store) GetEmailBlastVariantsByBlast(ctx context.Context, blastID uuid.UUID) ([]EmailBlastVariant, error) {
+	var variants []EmailBlastVariant
+	err := s.db.SelectContext(ctx, &variants, sqlGetEmailBlastVariantsByBlast, blastID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email blast variants: %w", err)
+	}
+	return variants, nil
+}
+
+// sqlAssignBlastTestVariants shuffles the recipients of a blast, spreads the first $3 of them
+// across the variants round-robin and renumbers batches so the test slice is sent first
+const sqlAssignBlastTestVariants = `
+WITH ranked AS (
+    SELECT id, (ROW_NUMBER() OVER (ORDER BY random()) - 1)::int AS rn
+    FROM blast_recipients
+    WHERE blast_id = $1
+)
+UPDATE blast_recipients br
+SET variant_id = CASE WHEN r.rn < $3 THEN ($2::uuid[])[(r.rn % array_length($2::uuid[], 1)) + 1] ELSE NULL END,
+    batch_number = CASE
+        WHEN r.rn < $3 THEN r.rn / $4 + 1
+        ELSE ($3 + $4 - 1) / $4 + (r.rn - $3) / $4 + 1
+    END,
+    updated_at = CURRENT_TIMESTAMP
+FROM ranked r
+WHERE br.id = r.id
+`
+
+const sqlSetEmailBlastABTestLastBatch = `
+UPDATE email_blasts
+SET ab_test_last_batch = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// AssignBlastTestVariants randomly selects testSize recipients for the A/B test slice, assigns them
+// evenly to the given variants and renumbers batches so test batches come first.
+// It returns the number of the last test batch, which is also stored on the blast.
+func (s *Store) AssignBlastTestVariants(ctx context.Context, blastID uuid.UUID, variantIDs []uuid.UUID, testSize, batchSize int) (int, error) {
+	if len(variantIDs) == 0 {
+		return 0, fmt.Errorf("no variants to assign")
+	}
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, sqlAssignBlastTestVariants, blastID, pq.Array(variantIDs), testSize, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign blast test variants: %w", err)
+	}
+
+	lastTestBatch := (testSize + batchSize - 1) / batchSize
+
+	res, err := tx.ExecContext(ctx, sqlSetEmailBlastABTestLastBatch, blastID, lastTestBatch)
+	if err != nil {
+		return 0, fmt.Errorf("failed to set A/B test last batch: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return 0, ErrNotFound
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return lastTestBatch, nil
+}
+
+// BlastVariantStats represents aggregate recipient stats for one variant of a blast
+type BlastVariantStats struct {
+	VariantID  uuid.UUID `db:"variant_id" json:"variant_id"`
+	Name       string    `db:"name" json:"name"`
+	Subject    string    `db:"subject" json:"subject"`
+	Recipients int       `db:"recipients" json:"recipients"`
+	Sent       int       `db:"sent" json:"sent"`
+	Opened     int       `db:"opened" json:"opened"`
+	Clicked    int       `db:"clicked" json:"clicked"`
+}
+
+const sqlGetBlastVariantStats = `
+SELECT
+    v.id AS variant_id,
+    v.name,
+    v.subject,
+    COUNT(br.id) AS recipients,
+    COUNT(br.id) FILTER (WHERE br.status IN ('sent', 'delivered', 'opened', 'clicked')) AS sent,
+    COUNT(br.id) FILTER (WHERE br.opened_at IS NOT NULL OR br.status IN ('opened', 'clicked')) AS opened,
+    COUNT(br.id) FILTER (WHERE br.clicked_at IS NOT NULL OR br.status = 'clicked') AS clicked
+FROM email_blast_variants v
+LEFT JOIN blast_recipients br ON br.variant_id = v.id
+WHERE v.blast_id = $1
+GROUP BY v.id, v.name, v.subject, v.created_at
+ORDER BY v.created_at ASC, v.name ASC
+`
+
+// GetBlastVariantStats retrieves test slice stats for each variant of a blast
+func (s *Store) GetBlastVariantStats(ctx context.Context, blastID uuid.UUID) ([]BlastVariantStats, error) {
+	var stats []BlastVariantStats
+	err := s.db.SelectContext(ctx, &stats, sqlGetBlastVariantStats, blastID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blast variant stats: %w", err)
+	}
+	return stats, nil
+}
+
+const sqlStartEmailBlastABTestWait = `
+UPDATE email_blasts
+SET ab_test_ends_at = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL AND ab_test_winner_variant_id IS NULL
+`
+
+// StartEmailBlastABTestWait records when the A/B test waiting window of a blast ends
+func (s *Store) StartEmailBlastABTestWait(ctx context.Context, blastID uuid.UUID, endsAt time.Time) error {
+	res, err := s.db.ExecContext(ctx, sqlStartEmailBlastABTestWait, blastID, endsAt)
+	if err != nil {
+		return fmt.Errorf("failed to start email blast A/B test wait: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+const sqlGetBlastsAwaitingABTestWinner = `
+SELECT id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
+FROM email_blasts
+WHERE status = 'sending' AND ab_test_ends_at <= $1 AND ab_test_winner_variant_id IS NULL AND deleted_at IS NULL
+ORDER BY ab_test_ends_at ASC
+`
+
+// GetBlastsAwaitingABTestWinner retrieves sending blasts whose A/B test waiting window has ended
+func (s *Store) GetBlastsAwaitingABTestWinner(ctx context.Context, beforeTime time.Time) ([]EmailBlast, error) {
+	var blasts []EmailBlast
+	err := s.db.SelectContext(ctx, &blasts, sqlGetBlastsAwaitingABTestWinner, beforeTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blasts awaiting A/B test winner: %w", err)
+	}
+	return blasts, nil
+}
+
+const sqlSetEmailBlastABTestWinner = `
+UPDATE email_blasts
+SET ab_test_winner_variant_id = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL AND ab_test_winner_variant_id IS NULL
+RETURNING id, account_id, blast_template_id, segment_ids, audience_id, name, subject, scheduled_at, started_at, completed_at, status, total_recipients, sent_count, delivered_count, opened_count, clicked_count, bounced_count, failed_count, batch_size, current_batch, last_batch_at, error_message, send_throttle_per_second, ab_test_percentage, ab_test_wait_minutes, ab_test_metric, ab_test_last_batch, ab_test_ends_at, ab_test_winner_variant_id, send_at_local_time, fallback_timezone, created_by, created_at, updated_at, deleted_at
+`
+
+// SetEmailBlastABTestWinner records the winning variant of a blast.
+// Returns ErrNotFound if the blast does not exist or a winner was already picked.
+func (s *Store) SetEmailBlastABTestWinner(ctx context.Context, blastID, variantID uuid.UUID) (EmailBlast, error) {
+	var blast EmailBlast
+	err := s.db.GetContext(ctx, &blast, sqlSetEmailBlastABTestWinner, blastID, variantID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EmailBlast{}, ErrNotFound
+		}
+		return EmailBlast{}, fmt.Errorf("failed to set email blast A/B test winner: %w", err)
+	}
+	return blast, nil
+}
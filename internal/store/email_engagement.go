@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+const sqlRecordBlastRecipientOpened = `
+WITH opened AS (
+    UPDATE blast_recipients
+    SET opened_at = CURRENT_TIMESTAMP,
+        status = CASE WHEN status IN ('sent', 'delivered') THEN 'opened'::blast_recipient_status ELSE status END,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE provider_message_id = $1 AND opened_at IS NULL
+    RETURNING blast_id
+)
+UPDATE email_blasts
+SET opened_count = opened_count + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (SELECT blast_id FROM opened)
+`
+
+// RecordBlastRecipientOpened records the first open of the blast email with the given provider
+// message ID and counts it on the blast. Later opens, and unknown message IDs, are ignored.
+func (s *Store) RecordBlastRecipientOpened(ctx context.Context, providerMessageID string) error {
+	_, err := s.db.ExecContext(ctx, sqlRecordBlastRecipientOpened, providerMessageID)
+	if err != nil {
+		return fmt.Errorf("failed to record blast recipient open: %w", err)
+	}
+	return nil
+}
+
+const sqlRecordBlastRecipientClicked = `
+WITH clicked AS (
+    UPDATE blast_recipients
+    SET clicked_at = CURRENT_TIMESTAMP,
+        status = CASE WHEN status IN ('sent', 'delivered', 'opened') THEN 'clicked'::blast_recipient_status ELSE status END,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE provider_message_id = $1 AND clicked_at IS NULL
+    RETURNING blast_id
+)
+UPDATE email_blasts
+SET clicked_count = clicked_count + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (SELECT blast_id FROM clicked)
+`
+
+// RecordBlastRecipientClicked records the first click in the blast email with the given provider
+// message ID and counts it on the blast. Later clicks, and unknown message IDs, are ignored.
+func (s *Store) RecordBlastRecipientClicked(ctx context.Context, providerMessageID string) error {
+	_, err := s.db.ExecContext(ctx, sqlRecordBlastRecipientClicked, providerMessageID)
+	if err != nil {
+		return fmt.Errorf("failed to record blast recipient click: %w", err)
+	}
+	return nil
+}
+
+const sqlRecordBlastRecipientBounced = `
+WITH bounced AS (
+    UPDATE blast_recipients
+    SET bounced_at = CURRENT_TIMESTAMP,
+        status = 'bounced',
+        error_message = $2,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE provider_message_id = $1 AND bounced_at IS NULL
+    RETURNING blast_id
+)
+UPDATE email_blasts
+SET bounced_count = bounced_count + 1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (SELECT blast_id FROM bounced)
+`
+
+// RecordBlastRecipientBounced marks the blast email with the given provider message ID as bounced
+// and counts it on the blast, so later audiences can suppress the address. Unknown message IDs are ignored.
+func (s *Store) RecordBlastRecipientBounced(ctx context.Context, providerMessageID, reason string) error {
+	_, err := s.db.ExecContext(ctx, sqlRecordBlastRecipientBounced, providerMessageID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record blast recipient bounce: %w", err)
+	}
+	return nil
+}
+
+const sqlRecordEmailLogBounced = `
+UPDATE email_logs
+SET status = 'bounced',
+    bounced_at = COALESCE(bounced_at, CURRENT_TIMESTAMP),
+    bounce_reason = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+// RecordEmailLogBounced marks an email as bounced with the reason reported by the provider
+func (s *Store) RecordEmailLogBounced(ctx context.Context, logID uuid.UUID, reason string) error {
+	_, err := s.db.ExecContext(ctx, sqlRecordEmailLogBounced, logID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record email bounce: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,506 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// EmailSequenceStepParams represents one step of a sequence being created or updated
+type EmailSequenceStepParams struct {
+	TemplateID   uuid.UUID
+	DelayMinutes int
+	Condition    *JSONB
+}
+
+// CreateEmailSequenceParams represents parameters for creating an email sequence
+type CreateEmailSequenceParams struct {
+	CampaignID uuid.UUID
+	Name       string
+	Status     string
+	Steps      []EmailSequenceStepParams
+}
+
+const sqlCreateEmailSequence = `
+INSERT INTO email_sequences (campaign_id, name, status)
+VALUES ($1, $2, $3)
+RETURNING id, campaign_id, name, status, created_at, updated_at, deleted_at
+`
+
+// CreateEmailSequence creates an email sequence with its steps, numbered in the given order
+func (s *Store) CreateEmailSequence(ctx context.Context, params CreateEmailSequenceParams) (EmailSequence, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return EmailSequence{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var sequence EmailSequence
+	err = tx.GetContext(ctx, &sequence, sqlCreateEmailSequence, params.CampaignID, params.Name, params.Status)
+	if err != nil {
+		return EmailSequence{}, fmt.Errorf("failed to create email sequence: %w", err)
+	}
+
+	sequence.Steps, err = replaceEmailSequenceSteps(ctx, tx, sequence.ID, params.Steps)
+	if err != nil {
+		return EmailSequence{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EmailSequence{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return sequence, nil
+}
+
+const sqlUpsertEmailSequenceStep = `
+INSERT INTO email_sequence_steps (sequence_id, template_id, position, delay_minutes, condition)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (sequence_id, position) DO UPDATE
+SET template_id = EXCLUDED.template_id,
+    delay_minutes = EXCLUDED.delay_minutes,
+    condition = EXCLUDED.condition,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id, sequence_id, template_id, position, delay_minutes, condition, created_at, updated_at
+`
+
+const sqlDeleteEmailSequenceStepsAfter = `
+DELETE FROM email_sequence_steps
+WHERE sequence_id = $1 AND position > $2
+`
+
+// replaceEmailSequenceSteps makes steps the steps of a sequence. Steps keep their ID when their
+// position is unchanged, so the analytics of a step survive edits to its template or delay.
+func replaceEmailSequenceSteps(ctx context.Context, tx *sqlx.Tx, sequenceID uuid.UUID, steps []EmailSequenceStepParams) ([]EmailSequenceStep, error) {
+	result := make([]EmailSequenceStep, 0, len(steps))
+	for i, step := range steps {
+		var created EmailSequenceStep
+		err := tx.GetContext(ctx, &created, sqlUpsertEmailSequenceStep, sequenceID, step.TemplateID, i+1, step.DelayMinutes, step.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save email sequence step: %w", err)
+		}
+		result = append(result, created)
+	}
+
+	if _, err := tx.ExecContext(ctx, sqlDeleteEmailSequenceStepsAfter, sequenceID, len(steps)); err != nil {
+		return nil, fmt.Errorf("failed to delete email sequence steps: %w", err)
+	}
+
+	return result, nil
+}
+
+const sqlGetEmailSequenceByID = `
+SELECT id, campaign_id, name, status, created_at, updated_at, deleted_at
+FROM email_sequences
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+const sqlGetEmailSequenceSteps = `
+SELECT id, sequence_id, template_id, position, delay_minutes, condition, created_at, updated_at
+FROM email_sequence_steps
+WHERE sequence_id = $1
+ORDER BY position ASC
+`
+
+// GetEmailSequenceByID retrieves an email sequence with its steps
+func (s *Store) GetEmailSequenceByID(ctx context.Context, sequenceID uuid.UUID) (EmailSequence, error) {
+	var sequence EmailSequence
+	err := s.db.GetContext(ctx, &sequence, sqlGetEmailSequenceByID, sequenceID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EmailSequence{}, ErrNotFound
+		}
+		return EmailSequence{}, fmt.Errorf("failed to get email sequence: %w", err)
+	}
+
+	sequence.Steps = []EmailSequenceStep{}
+	err = s.db.SelectContext(ctx, &sequence.Steps, sqlGetEmailSequenceSteps, sequenceID)
+	if err != nil {
+		return EmailSequence{}, fmt.Errorf("failed to get email sequence steps: %w", err)
+	}
+
+	return sequence, nil
+}
+
+const sqlGetEmailSequencesByCampaign = `
+SELECT id, campaign_id, name, status, created_at, updated_at, deleted_at
+FROM email_sequences
+WHERE campaign_id = $1 AND deleted_at IS NULL
+ORDER BY created_at DESC
+`
+
+const sqlGetEmailSequenceStepsByCampaign = `
+SELECT st.id, st.sequence_id, st.template_id, st.position, st.delay_minutes, st.condition, st.created_at, st.updated_at
+FROM email_sequence_steps st
+JOIN email_sequences s ON s.id = st.sequence_id
+WHERE s.campaign_id = $1 AND s.deleted_at IS NULL
+ORDER BY st.position ASC
+`
+
+// GetEmailSequencesByCampaign retrieves all email sequences of a campaign with their steps
+func (s *Store) GetEmailSequencesByCampaign(ctx context.Context, campaignID uuid.UUID) ([]EmailSequence, error) {
+	var sequences []EmailSequence
+	err := s.db.SelectContext(ctx, &sequences, sqlGetEmailSequencesByCampaign, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email sequences: %w", err)
+	}
+
+	var steps []EmailSequenceStep
+	err = s.db.SelectContext(ctx, &steps, sqlGetEmailSequenceStepsByCampaign, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email sequence steps: %w", err)
+	}
+
+	bySequence := make(map[uuid.UUID][]EmailSequenceStep)
+	for _, step := range steps {
+		bySequence[step.SequenceID] = append(bySequence[step.SequenceID], step)
+	}
+	for i := range sequences {
+		sequences[i].Steps = bySequence[sequences[i].ID]
+		if sequences[i].Steps == nil {
+			sequences[i].Steps = []EmailSequenceStep{}
+		}
+	}
+
+	return sequences, nil
+}
+
+// UpdateEmailSequenceParams represents parameters for updating an email sequence.
+// Steps replace the existing steps when not nil.
+type UpdateEmailSequenceParams struct {
+	Name   *string
+	Status *string
+	Steps  []EmailSequenceStepParams
+}
+
+const sqlUpdateEmailSequence = `
+UPDATE email_sequences
+SET name = COALESCE($2, name),
+    status = COALESCE($3, status),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, campaign_id, name, status, created_at, updated_at, deleted_at
+`
+
+// UpdateEmailSequence updates an email sequence and, when given, replaces its steps
+func (s *Store) UpdateEmailSequence(ctx context.Context, sequenceID uuid.UUID, params UpdateEmailSequenceParams) (EmailSequence, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return EmailSequence{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var sequence EmailSequence
+	err = tx.GetContext(ctx, &sequence, sqlUpdateEmailSequence, sequenceID, params.Name, params.Status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EmailSequence{}, ErrNotFound
+		}
+		return EmailSequence{}, fmt.Errorf("failed to update email sequence: %w", err)
+	}
+
+	if params.Steps != nil {
+		sequence.Steps, err = replaceEmailSequenceSteps(ctx, tx, sequenceID, params.Steps)
+		if err != nil {
+			return EmailSequence{}, err
+		}
+	} else {
+		sequence.Steps = []EmailSequenceStep{}
+		err = tx.SelectContext(ctx, &sequence.Steps, sqlGetEmailSequenceSteps, sequenceID)
+		if err != nil {
+			return EmailSequence{}, fmt.Errorf("failed to get email sequence steps: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EmailSequence{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return sequence, nil
+}
+
+const sqlDeleteEmailSequence = `
+UPDATE email_sequences
+SET deleted_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// DeleteEmailSequence soft deletes an email sequence. Enrolled users receive no further steps.
+func (s *Store) DeleteEmailSequence(ctx context.Context, sequenceID uuid.UUID) error {
+	res, err := s.db.ExecContext(ctx, sqlDeleteEmailSequence, sequenceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete email sequence: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rows == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+const sqlEnrollUserInEmailSequences = `
+INSERT INTO email_sequence_enrollments (sequence_id, user_id, next_send_at)
+SELECT s.id, $2, CURRENT_TIMESTAMP + st.delay_minutes * INTERVAL '1 minute'
+FROM email_sequences s
+JOIN email_sequence_steps st ON st.sequence_id = s.id AND st.position = 1
+WHERE s.campaign_id = $1 AND s.status = 'active' AND s.deleted_at IS NULL
+ON CONFLICT (sequence_id, user_id) DO NOTHING
+`
+
+// EnrollUserInEmailSequences enrolls a waitlist user in every active sequence of the campaign.
+// Users already enrolled in a sequence are left as they are. Returns the number of new enrollments.
+func (s *Store) EnrollUserInEmailSequences(ctx context.Context, campaignID, userID uuid.UUID) (int, error) {
+	res, err := s.db.ExecContext(ctx, sqlEnrollUserInEmailSequences, campaignID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enroll user in email sequences: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+// DueEmailSequenceEnrollment is an enrollment whose next step is due, with the campaign of its sequence
+type DueEmailSequenceEnrollment struct {
+	EmailSequenceEnrollment
+	CampaignID uuid.UUID `db:"campaign_id"`
+}
+
+const sqlClaimDueEmailSequenceEnrollments = `
+UPDATE email_sequence_enrollments e
+SET next_send_at = CURRENT_TIMESTAMP + $2 * INTERVAL '1 second',
+    updated_at = CURRENT_TIMESTAMP
+FROM email_sequences s
+WHERE s.id = e.sequence_id
+  AND e.id IN (
+    SELECT e2.id
+    FROM email_sequence_enrollments e2
+    JOIN email_sequences s2 ON s2.id = e2.sequence_id
+    WHERE e2.status = 'active' AND e2.next_send_at <= CURRENT_TIMESTAMP
+      AND s2.status = 'active' AND s2.deleted_at IS NULL
+    ORDER BY e2.next_send_at ASC
+    LIMIT $1
+    FOR UPDATE OF e2 SKIP LOCKED
+  )
+RETURNING e.id, e.sequence_id, e.user_id, e.status, e.current_step, e.next_send_at, e.exit_reason, e.enrolled_at, e.completed_at, e.exited_at, e.updated_at, s.campaign_id
+`
+
+// ClaimDueEmailSequenceEnrollments claims up to limit enrollments of active sequences whose next step
+// is due. Claimed enrollments are not due again for the lease duration, so concurrent schedulers
+// don't send the same step, and a step left unrecorded by a crash is retried once the lease ends.
+func (s *Store) ClaimDueEmailSequenceEnrollments(ctx context.Context, limit int, lease time.Duration) ([]DueEmailSequenceEnrollment, error) {
+	var enrollments []DueEmailSequenceEnrollment
+	err := s.db.SelectContext(ctx, &enrollments, sqlClaimDueEmailSequenceEnrollments, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due email sequence enrollments: %w", err)
+	}
+	return enrollments, nil
+}
+
+// RecordEmailSequenceStepParams represents the outcome of a sequence step for an enrollment.
+// The enrollment moves to the step at NextStep at NextSendAt, or completes when NextSendAt is nil.
+type RecordEmailSequenceStepParams struct {
+	EnrollmentID uuid.UUID
+	StepID       uuid.UUID
+	Status       string
+	EmailLogID   *uuid.UUID
+	ErrorMessage *string
+	NextStep     int
+	NextSendAt   *time.Time
+}
+
+const sqlCreateEmailSequenceSend = `
+INSERT INTO email_sequence_sends (enrollment_id, step_id, status, email_log_id, error_message)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (enrollment_id, step_id) DO NOTHING
+`
+
+const sqlAdvanceEmailSequenceEnrollment = `
+UPDATE email_sequence_enrollments
+SET current_step = $2,
+    next_send_at = $3,
+    status = CASE WHEN $3::timestamptz IS NULL THEN 'completed' ELSE status END,
+    completed_at = CASE WHEN $3::timestamptz IS NULL THEN CURRENT_TIMESTAMP ELSE completed_at END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'active'
+`
+
+// RecordEmailSequenceStep records the outcome of a step and moves the enrollment on to its next step
+func (s *Store) RecordEmailSequenceStep(ctx context.Context, params RecordEmailSequenceStepParams) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, sqlCreateEmailSequenceSend,
+		params.EnrollmentID,
+		params.StepID,
+		params.Status,
+		params.EmailLogID,
+		params.ErrorMessage)
+	if err != nil {
+		return fmt.Errorf("failed to record email sequence send: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, sqlAdvanceEmailSequenceEnrollment, params.EnrollmentID, params.NextStep, params.NextSendAt)
+	if err != nil {
+		return fmt.Errorf("failed to advance email sequence enrollment: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+const sqlCompleteEmailSequenceEnrollment = `
+UPDATE email_sequence_enrollments
+SET status = 'completed',
+    completed_at = CURRENT_TIMESTAMP,
+    next_send_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'active'
+`
+
+// CompleteEmailSequenceEnrollment marks an enrollment completed, e.g. when the steps it had left were removed
+func (s *Store) CompleteEmailSequenceEnrollment(ctx context.Context, enrollmentID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, sqlCompleteEmailSequenceEnrollment, enrollmentID)
+	if err != nil {
+		return fmt.Errorf("failed to complete email sequence enrollment: %w", err)
+	}
+	return nil
+}
+
+const sqlExitEmailSequenceEnrollment = `
+UPDATE email_sequence_enrollments
+SET status = 'exited',
+    exit_reason = $2,
+    exited_at = CURRENT_TIMESTAMP,
+    next_send_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'active'
+`
+
+// ExitEmailSequenceEnrollment removes a user from a sequence before its last step
+func (s *Store) ExitEmailSequenceEnrollment(ctx context.Context, enrollmentID uuid.UUID, reason string) error {
+	_, err := s.db.ExecContext(ctx, sqlExitEmailSequenceEnrollment, enrollmentID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to exit email sequence enrollment: %w", err)
+	}
+	return nil
+}
+
+const sqlExitInactiveUserEmailSequenceEnrollments = `
+UPDATE email_sequence_enrollments e
+SET status = 'exited',
+    exit_reason = CASE WHEN u.status = 'converted' THEN 'converted' ELSE 'user_removed' END,
+    exited_at = CURRENT_TIMESTAMP,
+    next_send_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+FROM waitlist_users u
+WHERE u.id = e.user_id
+  AND e.status = 'active'
+  AND (u.status IN ('converted', 'removed', 'blocked') OR u.deleted_at IS NOT NULL)
+`
+
+// ExitInactiveUserEmailSequenceEnrollments ends the active enrollments of users who converted,
+// or were removed, blocked or deleted. Returns the number of enrollments ended.
+func (s *Store) ExitInactiveUserEmailSequenceEnrollments(ctx context.Context) (int, error) {
+	res, err := s.db.ExecContext(ctx, sqlExitInactiveUserEmailSequenceEnrollments)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exit email sequence enrollments: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+// EmailSequenceStepStats represents the outcome of one step of a sequence across its enrollments
+type EmailSequenceStepStats struct {
+	StepID     uuid.UUID `db:"step_id" json:"step_id"`
+	Position   int       `db:"position" json:"position"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+	Sent       int       `db:"sent" json:"sent"`
+	Skipped    int       `db:"skipped" json:"skipped"`
+	Failed     int       `db:"failed" json:"failed"`
+	Opened     int       `db:"opened" json:"opened"`
+	Clicked    int       `db:"clicked" json:"clicked"`
+}
+
+const sqlGetEmailSequenceStepStats = `
+SELECT
+    st.id AS step_id,
+    st.position,
+    st.template_id,
+    COUNT(ss.id) FILTER (WHERE ss.status = 'sent') AS sent,
+    COUNT(ss.id) FILTER (WHERE ss.status = 'skipped') AS skipped,
+    COUNT(ss.id) FILTER (WHERE ss.status = 'failed') AS failed,
+    COUNT(ss.id) FILTER (WHERE el.opened_at IS NOT NULL) AS opened,
+    COUNT(ss.id) FILTER (WHERE el.clicked_at IS NOT NULL) AS clicked
+FROM email_sequence_steps st
+LEFT JOIN email_sequence_sends ss ON ss.step_id = st.id
+LEFT JOIN email_logs el ON el.id = ss.email_log_id
+WHERE st.sequence_id = $1
+GROUP BY st.id, st.position, st.template_id
+ORDER BY st.position ASC
+`
+
+// GetEmailSequenceStepStats retrieves send, skip, failure, open and click counts for each step of a sequence
+func (s *Store) GetEmailSequenceStepStats(ctx context.Context, sequenceID uuid.UUID) ([]EmailSequenceStepStats, error) {
+	var stats []EmailSequenceStepStats
+	err := s.db.SelectContext(ctx, &stats, sqlGetEmailSequenceStepStats, sequenceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email sequence step stats: %w", err)
+	}
+	return stats, nil
+}
+
+// EmailSequenceEnrollmentStats represents how many users are in each state of a sequence
+type EmailSequenceEnrollmentStats struct {
+	Enrolled  int `db:"enrolled" json:"enrolled"`
+	Active    int `db:"active" json:"active"`
+	Completed int `db:"completed" json:"completed"`
+	Exited    int `db:"exited" json:"exited"`
+	Converted int `db:"converted" json:"converted"`
+}
+
+const sqlGetEmailSequenceEnrollmentStats = `
+SELECT
+    COUNT(*) AS enrolled,
+    COUNT(*) FILTER (WHERE status = 'active') AS active,
+    COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+    COUNT(*) FILTER (WHERE status = 'exited') AS exited,
+    COUNT(*) FILTER (WHERE exit_reason = 'converted') AS converted
+FROM email_sequence_enrollments
+WHERE sequence_id = $1
+`
+
+// GetEmailSequenceEnrollmentStats retrieves enrollment counts of a sequence
+func (s *Store) GetEmailSequenceEnrollmentStats(ctx context.Context, sequenceID uuid.UUID) (EmailSequenceEnrollmentStats, error) {
+	var stats EmailSequenceEnrollmentStats
+	err := s.db.GetContext(ctx, &stats, sqlGetEmailSequenceEnrollmentStats, sequenceID)
+	if err != nil {
+		return EmailSequenceEnrollmentStats{}, fmt.Errorf("failed to get email sequence enrollment stats: %w", err)
+	}
+	return stats, nil
+}
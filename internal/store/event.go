@@ -0,0 +1,140 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const eventColumns = `id, sequence, account_id, campaign_id, type, data, occurred_at, recorded_at, redacted_at`
+
+// RecordEventParams represents parameters for recording an event
+type RecordEventParams struct {
+	ID         uuid.UUID
+	AccountID  uuid.UUID
+	CampaignID *uuid.UUID
+	Type       string
+	Data       JSONB
+	OccurredAt time.Time
+}
+
+// sqlLockAccountEvents serializes recording an account's events until the transaction ends.
+// Sequence values are allocated at insert rather than at commit, so without it an event could
+// commit after a later one and be skipped by a client paging past the later sequence.
+const sqlLockAccountEvents = `
+SELECT pg_advisory_xact_lock(hashtext('events:' || $1::text))
+`
+
+const sqlRecordEvent = `
+INSERT INTO events (id, account_id, campaign_id, type, data, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (id) DO NOTHING
+`
+
+// RecordEvent appends an event to the event log. Recording an event ID that is already in the
+// log is a no-op, so events redelivered by Kafka are recorded once. An account's events are
+// recorded one at a time, so they become visible in sequence order and the sequence cursor of
+// ListEvents never skips an event committed late.
+func (s *Store) RecordEvent(ctx context.Context, params RecordEventParams) error {
+	data := params.Data
+	if data == nil {
+		data = JSONB{}
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, sqlLockAccountEvents, params.AccountID); err != nil {
+		return fmt.Errorf("failed to lock account events: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, sqlRecordEvent,
+		params.ID,
+		params.AccountID,
+		params.CampaignID,
+		params.Type,
+		data,
+		params.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+const sqlGetEventByID = `
+SELECT ` + eventColumns + `
+FROM events
+WHERE id = $1
+`
+
+// GetEventByID retrieves an event by ID
+func (s *Store) GetEventByID(ctx context.Context, eventID uuid.UUID) (Event, error) {
+	var event Event
+	err := s.db.GetContext(ctx, &event, sqlGetEventByID, eventID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Event{}, ErrNotFound
+		}
+		return Event{}, fmt.Errorf("failed to get event: %w", err)
+	}
+	return event, nil
+}
+
+// ListEventsParams represents filters for listing an account's events.
+// Empty filters match every event.
+type ListEventsParams struct {
+	AccountID     uuid.UUID
+	Types         []string
+	CampaignID    *uuid.UUID
+	From          *time.Time
+	To            *time.Time
+	AfterSequence int64
+	Limit         int
+}
+
+const sqlListEvents = `
+SELECT ` + eventColumns + `
+FROM events
+WHERE account_id = $1
+  AND sequence > $2
+  AND (cardinality($3::text[]) = 0 OR type = ANY($3::text[]))
+  AND ($4::uuid IS NULL OR campaign_id = $4)
+  AND ($5::timestamptz IS NULL OR occurred_at >= $5)
+  AND ($6::timestamptz IS NULL OR occurred_at < $6)
+ORDER BY sequence ASC
+LIMIT $7
+`
+
+// ListEvents retrieves an account's events in log order, starting after a sequence number
+func (s *Store) ListEvents(ctx context.Context, params ListEventsParams) ([]Event, error) {
+	types := params.Types
+	if types == nil {
+		types = []string{}
+	}
+
+	var events []Event
+	err := s.db.SelectContext(ctx, &events, sqlListEvents,
+		params.AccountID,
+		params.AfterSequence,
+		pq.Array(types),
+		params.CampaignID,
+		params.From,
+		params.To,
+		params.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
@@ -54,6 +54,104 @@ func (j *JSONB) Scan(value interface{}) error {
 	return nil
 }
 
+// EmailAttachment is a file attached to an email template. Content is base64 encoded.
+type EmailAttachment struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
+}
+
+// EmailAttachments is a custom type for JSONB arrays of email attachments
+type EmailAttachments []EmailAttachment
+
+// Value implements the driver.Valuer interface for EmailAttachments
+func (a EmailAttachments) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements the sql.Scanner interface for EmailAttachments
+func (a *EmailAttachments) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("incompatible type for EmailAttachments")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*a = nil
+		return nil
+	}
+
+	var result EmailAttachments
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+	*a = result
+	return nil
+}
+
+// WebhookFilter is a condition on an event's data that a webhook's deliveries must meet
+type WebhookFilter struct {
+	// Field is a dot-separated path in the event data, e.g. "user.utm_source"
+	Field string `json:"field"`
+	// Operator is one of eq, neq, in, not_in and exists
+	Operator string `json:"operator"`
+	// Value is compared with the field, a list for in and not_in, unused for exists
+	Value interface{} `json:"value,omitempty"`
+}
+
+// WebhookFilters is a custom type for JSONB arrays of webhook filters
+type WebhookFilters []WebhookFilter
+
+// Value implements the driver.Valuer interface for WebhookFilters
+func (f WebhookFilters) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface for WebhookFilters
+func (f *WebhookFilters) Scan(value interface{}) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("incompatible type for WebhookFilters")
+	}
+
+	if len(bytes) == 0 || string(bytes) == "null" {
+		*f = nil
+		return nil
+	}
+
+	var result WebhookFilters
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return err
+	}
+	*f = result
+	return nil
+}
+
 // StringArray is a custom type for PostgreSQL text[] arrays
 type StringArray []string
 
@@ -352,8 +450,10 @@ type CampaignEmailSettings struct {
 	ReplyTo              *string   `db:"reply_to" json:"reply_to,omitempty"`
 	VerificationRequired bool      `db:"verification_required" json:"verification_required"`
 	SendWelcomeEmail     bool      `db:"send_welcome_email" json:"send_welcome_email"`
-	CreatedAt            time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+	// VariantAutoPromoteAfter is the number of sends each A/B variant needs before the winner is promoted
+	VariantAutoPromoteAfter *int      `db:"variant_auto_promote_after" json:"variant_auto_promote_after,omitempty"`
+	CreatedAt               time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt               time.Time `db:"updated_at" json:"updated_at"`
 }
 
 // CampaignBrandingSettings represents branding configuration for a campaign
@@ -585,8 +685,12 @@ type CampaignEmailTemplate struct {
 	Type       string    `db:"type" json:"type"`
 	Subject    string    `db:"subject" json:"subject"`
 
-	HTMLBody   string `db:"html_body" json:"html_body"`
-	BlocksJSON *JSONB `db:"blocks_json" json:"blocks_json,omitempty"`
+	HTMLBody   string  `db:"html_body" json:"html_body"`
+	TextBody   *string `db:"text_body" json:"text_body,omitempty"`
+	BlocksJSON *JSONB  `db:"blocks_json" json:"blocks_json,omitempty"`
+
+	Headers     *JSONB           `db:"headers" json:"headers,omitempty"`
+	Attachments EmailAttachments `db:"attachments" json:"attachments,omitempty"`
 
 	Enabled           bool `db:"enabled" json:"enabled"`
 	SendAutomatically bool `db:"send_automatically" json:"send_automatically"`
@@ -606,8 +710,12 @@ type BlastEmailTemplate struct {
 	Name      string    `db:"name" json:"name"`
 	Subject   string    `db:"subject" json:"subject"`
 
-	HTMLBody   string `db:"html_body" json:"html_body"`
-	BlocksJSON *JSONB `db:"blocks_json" json:"blocks_json,omitempty"`
+	HTMLBody   string  `db:"html_body" json:"html_body"`
+	TextBody   *string `db:"text_body" json:"text_body,omitempty"`
+	BlocksJSON *JSONB  `db:"blocks_json" json:"blocks_json,omitempty"`
+
+	Headers     *JSONB           `db:"headers" json:"headers,omitempty"`
+	Attachments EmailAttachments `db:"attachments" json:"attachments,omitempty"`
 
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
@@ -646,6 +754,8 @@ type EmailLog struct {
 	OpenCount  int `db:"open_count" json:"open_count"`
 	ClickCount int `db:"click_count" json:"click_count"`
 
+	VariantName *string `db:"variant_name" json:"variant_name,omitempty"`
+
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
@@ -696,7 +806,8 @@ type Webhook struct {
 	URL    string `db:"url" json:"url"`
 	Secret string `db:"secret" json:"-"`
 
-	Events StringArray `db:"events" json:"events"`
+	Events  StringArray    `db:"events" json:"events"`
+	Filters WebhookFilters `db:"filters" json:"filters,omitempty"`
 
 	Status string `db:"status" json:"status"`
 
@@ -708,15 +819,55 @@ type Webhook struct {
 	LastSuccessAt *time.Time `db:"last_success_at" json:"last_success_at,omitempty"`
 	LastFailureAt *time.Time `db:"last_failure_at" json:"last_failure_at,omitempty"`
 
+	ConsecutiveFailures int        `db:"consecutive_failures" json:"consecutive_failures"`
+	FailingSince        *time.Time `db:"failing_since" json:"failing_since,omitempty"`
+	CircuitOpenedAt     *time.Time `db:"circuit_opened_at" json:"circuit_opened_at,omitempty"`
+	CircuitNextProbeAt  *time.Time `db:"circuit_next_probe_at" json:"circuit_next_probe_at,omitempty"`
+	DisabledAt          *time.Time `db:"disabled_at" json:"disabled_at,omitempty"`
+	DisabledReason      *string    `db:"disabled_reason" json:"disabled_reason,omitempty"`
+
+	PreviousSecret          *string    `db:"previous_secret" json:"-"`
+	PreviousSecretExpiresAt *time.Time `db:"previous_secret_expires_at" json:"previous_secret_expires_at,omitempty"`
+	SecretRotatedAt         *time.Time `db:"secret_rotated_at" json:"secret_rotated_at,omitempty"`
+
+	SignatureFormat WebhookSignatureFormat `db:"signature_format" json:"signature_format"`
+	OrderedDelivery bool                   `db:"ordered_delivery" json:"ordered_delivery"`
+	PayloadVersion  int                    `db:"payload_version" json:"payload_version"`
+
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
 	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
+// WebhookSignatureFormat represents how a webhook's deliveries are signed
+type WebhookSignatureFormat string
+
+const (
+	// WebhookSignatureFormatV1 signs with X-Webhook-Signature: t=<timestamp>,v1=<hex HMAC-SHA256>
+	WebhookSignatureFormatV1 WebhookSignatureFormat = "v1"
+	// WebhookSignatureFormatStandard signs with the Standard Webhooks headers
+	WebhookSignatureFormatStandard WebhookSignatureFormat = "standard_webhooks"
+)
+
+// WebhookDeliveryTrigger represents what created a webhook delivery
+type WebhookDeliveryTrigger string
+
+const (
+	WebhookDeliveryTriggerEvent      WebhookDeliveryTrigger = "event"
+	WebhookDeliveryTriggerTest       WebhookDeliveryTrigger = "test"
+	WebhookDeliveryTriggerReplay     WebhookDeliveryTrigger = "replay"
+	WebhookDeliveryTriggerRedelivery WebhookDeliveryTrigger = "redelivery"
+	WebhookDeliveryTriggerBulkRetry  WebhookDeliveryTrigger = "bulk_retry"
+)
+
 // WebhookDelivery represents a webhook delivery attempt
 type WebhookDelivery struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	WebhookID uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	ID        uuid.UUID  `db:"id" json:"id"`
+	WebhookID uuid.UUID  `db:"webhook_id" json:"webhook_id"`
+	EventID   *uuid.UUID `db:"event_id" json:"event_id,omitempty"`
+
+	OriginalDeliveryID *uuid.UUID `db:"original_delivery_id" json:"original_delivery_id,omitempty"`
+	TriggeredBy        string     `db:"triggered_by" json:"triggered_by"`
 
 	EventType string `db:"event_type" json:"event_type"`
 	Payload   JSONB  `db:"payload" json:"payload"`
@@ -737,6 +888,21 @@ type WebhookDelivery struct {
 
 	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
 	DeliveredAt *time.Time `db:"delivered_at" json:"delivered_at,omitempty"`
+	// RedactedAt is set once the configured payload fields have been redacted
+	RedactedAt *time.Time `db:"redacted_at" json:"redacted_at,omitempty"`
+}
+
+// WebhookDailyStats holds a webhook's deliveries of one UTC day, by status at aggregation time
+type WebhookDailyStats struct {
+	WebhookID     uuid.UUID `db:"webhook_id" json:"webhook_id"`
+	Day           time.Time `db:"day" json:"day"`
+	Deliveries    int       `db:"deliveries" json:"deliveries"`
+	Succeeded     int       `db:"succeeded" json:"succeeded"`
+	Failed        int       `db:"failed" json:"failed"`
+	Pending       int       `db:"pending" json:"pending"`
+	AvgDurationMs *int      `db:"avg_duration_ms" json:"avg_duration_ms,omitempty"`
+	MaxDurationMs *int      `db:"max_duration_ms" json:"max_duration_ms,omitempty"`
+	UpdatedAt     time.Time `db:"updated_at" json:"updated_at"`
 }
 
 // APIKey represents an API key
@@ -827,6 +993,31 @@ const (
 	SegmentStatusArchived SegmentStatus = "archived"
 )
 
+// SegmentType represents how a segment selects its users
+type SegmentType string
+
+const (
+	SegmentTypeDynamic SegmentType = "dynamic"
+	SegmentTypeStatic  SegmentType = "static"
+)
+
+// SegmentMembershipAction represents a change to a static segment's members
+type SegmentMembershipAction string
+
+const (
+	SegmentMembershipActionAdded   SegmentMembershipAction = "added"
+	SegmentMembershipActionRemoved SegmentMembershipAction = "removed"
+)
+
+// SegmentMembershipSource represents how members were added to or removed from a static segment
+type SegmentMembershipSource string
+
+const (
+	SegmentMembershipSourceManual   SegmentMembershipSource = "manual"
+	SegmentMembershipSourceCSV      SegmentMembershipSource = "csv"
+	SegmentMembershipSourceSnapshot SegmentMembershipSource = "snapshot"
+)
+
 // EmailBlastStatus represents the status of an email blast
 type EmailBlastStatus string
 
@@ -856,6 +1047,41 @@ const (
 	BlastRecipientStatusFailed    BlastRecipientStatus = "failed"
 )
 
+// BlastABTestMetric represents the metric used to pick the winning variant of an A/B tested blast
+type BlastABTestMetric string
+
+const (
+	BlastABTestMetricOpenRate  BlastABTestMetric = "open_rate"
+	BlastABTestMetricClickRate BlastABTestMetric = "click_rate"
+)
+
+// EmailSequenceStatus represents the status of a drip email sequence
+type EmailSequenceStatus string
+
+const (
+	EmailSequenceStatusDraft  EmailSequenceStatus = "draft"
+	EmailSequenceStatusActive EmailSequenceStatus = "active"
+	EmailSequenceStatusPaused EmailSequenceStatus = "paused"
+)
+
+// EmailSequenceEnrollmentStatus represents the progress of a user through a sequence
+type EmailSequenceEnrollmentStatus string
+
+const (
+	EmailSequenceEnrollmentStatusActive    EmailSequenceEnrollmentStatus = "active"
+	EmailSequenceEnrollmentStatusCompleted EmailSequenceEnrollmentStatus = "completed"
+	EmailSequenceEnrollmentStatusExited    EmailSequenceEnrollmentStatus = "exited"
+)
+
+// EmailSequenceSendStatus represents the outcome of one sequence step for a user
+type EmailSequenceSendStatus string
+
+const (
+	EmailSequenceSendStatusSent    EmailSequenceSendStatus = "sent"
+	EmailSequenceSendStatusSkipped EmailSequenceSendStatus = "skipped"
+	EmailSequenceSendStatusFailed  EmailSequenceSendStatus = "failed"
+)
+
 // SegmentFilterCriteria represents the filter criteria for a segment
 type SegmentFilterCriteria struct {
 	Statuses      []string          `json:"statuses,omitempty"`
@@ -868,6 +1094,8 @@ type SegmentFilterCriteria struct {
 	DateFrom      *time.Time        `json:"date_from,omitempty"`
 	DateTo        *time.Time        `json:"date_to,omitempty"`
 	CustomFields  map[string]string `json:"custom_fields,omitempty"`
+	// Rules is a nested AND/OR/NOT filter tree, combined with the flat criteria above using AND
+	Rules *SegmentFilterNode `json:"rules,omitempty"`
 }
 
 // Segment represents a reusable segment definition for targeting waitlist users
@@ -877,12 +1105,19 @@ type Segment struct {
 
 	Name        string  `db:"name" json:"name"`
 	Description *string `db:"description" json:"description,omitempty"`
+	Type        string  `db:"type" json:"type"`
 
 	FilterCriteria JSONB `db:"filter_criteria" json:"filter_criteria"`
 
 	CachedUserCount int        `db:"cached_user_count" json:"cached_user_count"`
 	CachedAt        *time.Time `db:"cached_at" json:"cached_at,omitempty"`
 
+	SizeThreshold        *int `db:"size_threshold" json:"size_threshold,omitempty"`
+	SizeThresholdReached bool `db:"size_threshold_reached" json:"size_threshold_reached"`
+
+	SyncEnabled bool       `db:"sync_enabled" json:"sync_enabled"`
+	SyncedAt    *time.Time `db:"synced_at" json:"synced_at,omitempty"`
+
 	Status string `db:"status" json:"status"`
 
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
@@ -890,12 +1125,104 @@ type Segment struct {
 	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
 }
 
+// SegmentMembershipEvent records a user being added to or removed from a static segment
+type SegmentMembershipEvent struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	SegmentID uuid.UUID `db:"segment_id" json:"segment_id"`
+	UserID    uuid.UUID `db:"user_id" json:"user_id"`
+	Email     string    `db:"email" json:"email"`
+	Action    string    `db:"action" json:"action"`
+	Source    string    `db:"source" json:"source"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// SegmentSize is a segment's user count recorded on a day
+type SegmentSize struct {
+	SegmentID uuid.UUID `db:"segment_id" json:"segment_id"`
+	Date      time.Time `db:"date" json:"date"`
+	UserCount int       `db:"user_count" json:"user_count"`
+}
+
+// SegmentExportFormat represents the file format of a segment export
+type SegmentExportFormat string
+
+const (
+	SegmentExportFormatCSV  SegmentExportFormat = "csv"
+	SegmentExportFormatJSON SegmentExportFormat = "json"
+)
+
+// SegmentExportStatus represents the status of a segment export
+type SegmentExportStatus string
+
+const (
+	SegmentExportStatusPending    SegmentExportStatus = "pending"
+	SegmentExportStatusProcessing SegmentExportStatus = "processing"
+	SegmentExportStatusCompleted  SegmentExportStatus = "completed"
+	SegmentExportStatusFailed     SegmentExportStatus = "failed"
+)
+
+// SegmentExport represents an asynchronous export of a segment's members
+type SegmentExport struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	AccountID  uuid.UUID `db:"account_id" json:"account_id"`
+	CampaignID uuid.UUID `db:"campaign_id" json:"campaign_id"`
+	SegmentID  uuid.UUID `db:"segment_id" json:"segment_id"`
+
+	Format string `db:"format" json:"format"`
+	Status string `db:"status" json:"status"`
+
+	RowCount     *int    `db:"row_count" json:"row_count,omitempty"`
+	ErrorMessage *string `db:"error_message" json:"error_message,omitempty"`
+
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	StartedAt   *time.Time `db:"started_at" json:"started_at,omitempty"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+}
+
+// SegmentSyncMember is a segment member as last pushed to downstream destinations
+type SegmentSyncMember struct {
+	UserID uuid.UUID `db:"user_id" json:"user_id"`
+	Email  string    `db:"email" json:"email"`
+}
+
+// AudienceCombineMode represents how an audience combines its segments
+type AudienceCombineMode string
+
+const (
+	AudienceCombineModeUnion     AudienceCombineMode = "union"
+	AudienceCombineModeIntersect AudienceCombineMode = "intersect"
+)
+
+// Audience represents an account-level recipient list built from segments of any of the account's
+// campaigns, deduplicated by email, with suppression rules applied
+type Audience struct {
+	ID          uuid.UUID `db:"id" json:"id"`
+	AccountID   uuid.UUID `db:"account_id" json:"account_id"`
+	Name        string    `db:"name" json:"name"`
+	Description *string   `db:"description" json:"description,omitempty"`
+
+	CombineMode string    `db:"combine_mode" json:"combine_mode"`
+	SegmentIDs  UUIDArray `db:"segment_ids" json:"segment_ids"`
+
+	// Suppression rules
+	ExcludeSegmentIDs       UUIDArray `db:"exclude_segment_ids" json:"exclude_segment_ids"`
+	SuppressBounced         bool      `db:"suppress_bounced" json:"suppress_bounced"`
+	RequireMarketingConsent bool      `db:"require_marketing_consent" json:"require_marketing_consent"`
+
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
 // EmailBlast represents an account-scoped email blast sent to multiple segments
 type EmailBlast struct {
 	ID              uuid.UUID `db:"id" json:"id"`
 	AccountID       uuid.UUID `db:"account_id" json:"account_id"`
 	BlastTemplateID uuid.UUID `db:"blast_template_id" json:"blast_template_id"`
 	SegmentIDs      UUIDArray `db:"segment_ids" json:"segment_ids"`
+	// AudienceID is set when the blast is sent to an account-level audience instead of SegmentIDs
+	AudienceID *uuid.UUID `db:"audience_id" json:"audience_id,omitempty"`
 
 	Name    string `db:"name" json:"name"`
 	Subject string `db:"subject" json:"subject"`
@@ -921,6 +1248,24 @@ type EmailBlast struct {
 
 	SendThrottlePerSecond *int `db:"send_throttle_per_second" json:"send_throttle_per_second,omitempty"`
 
+	// A/B test settings (only set when the blast has variants)
+	ABTestPercentage      *int       `db:"ab_test_percentage" json:"ab_test_percentage,omitempty"`
+	ABTestWaitMinutes     *int       `db:"ab_test_wait_minutes" json:"ab_test_wait_minutes,omitempty"`
+	ABTestMetric          *string    `db:"ab_test_metric" json:"ab_test_metric,omitempty"`
+	ABTestLastBatch       *int       `db:"ab_test_last_batch" json:"ab_test_last_batch,omitempty"`
+	ABTestEndsAt          *time.Time `db:"ab_test_ends_at" json:"ab_test_ends_at,omitempty"`
+	ABTestWinnerVariantID *uuid.UUID `db:"ab_test_winner_variant_id" json:"ab_test_winner_variant_id,omitempty"`
+
+	// Local time delivery settings (only set when the blast is sent at each recipient's local time)
+	SendAtLocalTime  *string `db:"send_at_local_time" json:"send_at_local_time,omitempty"`
+	FallbackTimezone *string `db:"fallback_timezone" json:"fallback_timezone,omitempty"`
+
+	// Variants is populated separately from email_blast_variants
+	Variants []EmailBlastVariant `db:"-" json:"variants,omitempty"`
+
+	// Cohorts is populated separately from email_blast_cohorts
+	Cohorts []BlastCohortStats `db:"-" json:"cohorts,omitempty"`
+
 	CreatedBy *uuid.UUID `db:"created_by" json:"created_by,omitempty"`
 
 	CreatedAt time.Time  `db:"created_at" json:"created_at"`
@@ -952,6 +1297,106 @@ type BlastRecipient struct {
 
 	BatchNumber *int `db:"batch_number" json:"batch_number,omitempty"`
 
+	VariantID *uuid.UUID `db:"variant_id" json:"variant_id,omitempty"`
+
+	Timezone *string `db:"timezone" json:"timezone,omitempty"`
+
+	LeaseOwner     *string    `db:"lease_owner" json:"-"`
+	LeaseExpiresAt *time.Time `db:"lease_expires_at" json:"-"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+
 	CreatedAt time.Time `db:"created_at" json:"created_at"`
 	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
 }
+
+// EmailBlastVariant represents one subject/template variant of an A/B tested email blast
+type EmailBlastVariant struct {
+	ID              uuid.UUID `db:"id" json:"id"`
+	BlastID         uuid.UUID `db:"blast_id" json:"blast_id"`
+	BlastTemplateID uuid.UUID `db:"blast_template_id" json:"blast_template_id"`
+
+	Name    string `db:"name" json:"name"`
+	Subject string `db:"subject" json:"subject"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// EmailBlastCohort represents the recipients of a local time blast in one timezone.
+// A cohort's batches are contiguous and are sent once its local send time arrives.
+type EmailBlastCohort struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	BlastID uuid.UUID `db:"blast_id" json:"blast_id"`
+
+	Timezone  string    `db:"timezone" json:"timezone"`
+	ReleaseAt time.Time `db:"release_at" json:"release_at"`
+
+	FirstBatch     int `db:"first_batch" json:"first_batch"`
+	LastBatch      int `db:"last_batch" json:"last_batch"`
+	RecipientCount int `db:"recipient_count" json:"recipient_count"`
+
+	ReleasedAt *time.Time `db:"released_at" json:"released_at,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+}
+
+// EmailSequence represents a campaign-scoped drip sequence of automated emails
+type EmailSequence struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	CampaignID uuid.UUID `db:"campaign_id" json:"campaign_id"`
+
+	Name   string `db:"name" json:"name"`
+	Status string `db:"status" json:"status"`
+
+	Steps []EmailSequenceStep `db:"-" json:"steps"`
+
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time  `db:"updated_at" json:"updated_at"`
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+}
+
+// EmailSequenceStep represents one email of a sequence, sent DelayMinutes after the previous step.
+// Condition holds segment filter criteria the user must match for the step to be sent.
+type EmailSequenceStep struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	SequenceID uuid.UUID `db:"sequence_id" json:"sequence_id"`
+	TemplateID uuid.UUID `db:"template_id" json:"template_id"`
+
+	Position     int    `db:"position" json:"position"`
+	DelayMinutes int    `db:"delay_minutes" json:"delay_minutes"`
+	Condition    *JSONB `db:"condition" json:"condition,omitempty"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// EmailSequenceEnrollment tracks a waitlist user's progress through a sequence
+type EmailSequenceEnrollment struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	SequenceID uuid.UUID `db:"sequence_id" json:"sequence_id"`
+	UserID     uuid.UUID `db:"user_id" json:"user_id"`
+
+	Status      string     `db:"status" json:"status"`
+	CurrentStep int        `db:"current_step" json:"current_step"`
+	NextSendAt  *time.Time `db:"next_send_at" json:"next_send_at,omitempty"`
+	ExitReason  *string    `db:"exit_reason" json:"exit_reason,omitempty"`
+
+	EnrolledAt  time.Time  `db:"enrolled_at" json:"enrolled_at"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+	ExitedAt    *time.Time `db:"exited_at" json:"exited_at,omitempty"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// Event is a domain event recorded in the append-only event log
+type Event struct {
+	ID         uuid.UUID  `db:"id" json:"id"`
+	Sequence   int64      `db:"sequence" json:"sequence"`
+	AccountID  uuid.UUID  `db:"account_id" json:"account_id"`
+	CampaignID *uuid.UUID `db:"campaign_id" json:"campaign_id,omitempty"`
+
+	Type string `db:"type" json:"type"`
+	Data JSONB  `db:"data" json:"data"`
+
+	OccurredAt time.Time `db:"occurred_at" json:"occurred_at"`
+	RecordedAt time.Time `db:"recorded_at" json:"recorded_at"`
+	// RedactedAt is set once the configured data fields have been redacted
+	RedactedAt *time.Time `db:"redacted_at" json:"redacted_at,omitempty"`
+}
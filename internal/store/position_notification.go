@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const sqlRecordPositionImprovements = `
+INSERT INTO waitlist_position_notifications (user_id, campaign_id, baseline_position, pending_since)
+SELECT d.user_id, $1, d.previous_position, CURRENT_TIMESTAMP
+FROM unnest($2::uuid[], $3::int[]) AS d(user_id, previous_position)
+ON CONFLICT (user_id) DO UPDATE
+SET pending_since = COALESCE(waitlist_position_notifications.pending_since, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+`
+
+// RecordPositionImprovements marks users who moved up as having a pending position update.
+// previousPositions holds each user's position before the recalculation; it becomes the baseline
+// of users who were never notified, while notified users keep the position of their last email.
+func (s *Store) RecordPositionImprovements(ctx context.Context, campaignID uuid.UUID, userIDs []uuid.UUID, previousPositions []int) error {
+	if len(userIDs) != len(previousPositions) {
+		return fmt.Errorf("userIDs and previousPositions must have same length")
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = id.String()
+	}
+
+	_, err := s.db.ExecContext(ctx, sqlRecordPositionImprovements, campaignID, pq.Array(ids), pq.Array(previousPositions))
+	if err != nil {
+		return fmt.Errorf("failed to record position improvements: %w", err)
+	}
+	return nil
+}
+
+// PositionUpdateDigest is a pending position update that is ready to be emailed
+type PositionUpdateDigest struct {
+	UserID           uuid.UUID `db:"user_id"`
+	CampaignID       uuid.UUID `db:"campaign_id"`
+	PreviousPosition int       `db:"previous_position"`
+	Position         int       `db:"position"`
+}
+
+// SpotsMoved returns how many spots the user moved up since their last position update
+func (d PositionUpdateDigest) SpotsMoved() int {
+	return d.PreviousPosition - d.Position
+}
+
+const sqlClaimDuePositionUpdateDigests = `
+UPDATE waitlist_position_notifications n
+SET claimed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+FROM waitlist_users u
+WHERE u.id = n.user_id
+  AND n.user_id IN (
+    SELECT n2.user_id
+    FROM waitlist_position_notifications n2
+    JOIN waitlist_users u2 ON u2.id = n2.user_id
+    WHERE n2.pending_since IS NOT NULL
+      AND (n2.last_sent_at IS NULL OR n2.last_sent_at <= $1)
+      AND (n2.claimed_at IS NULL OR n2.claimed_at <= $2)
+      AND u2.deleted_at IS NULL
+      AND u2.status NOT IN ('converted', 'removed', 'blocked')
+      AND n2.baseline_position - u2.position >= $3
+    ORDER BY n2.pending_since ASC
+    LIMIT $4
+    FOR UPDATE OF n2 SKIP LOCKED
+  )
+RETURNING n.user_id, n.campaign_id, n.baseline_position AS previous_position, u.position
+`
+
+// ClaimDuePositionUpdateDigests claims up to limit users who moved up at least minSpots since their
+// last position update and were not emailed after lastSentBefore. Users claimed after claimedBefore
+// are skipped, so a user is held by one sender at a time; a claim whose send failed or never
+// finished is picked up again once it is older than claimedBefore.
+func (s *Store) ClaimDuePositionUpdateDigests(ctx context.Context, lastSentBefore, claimedBefore time.Time, minSpots, limit int) ([]PositionUpdateDigest, error) {
+	var digests []PositionUpdateDigest
+	err := s.db.SelectContext(ctx, &digests, sqlClaimDuePositionUpdateDigests, lastSentBefore, claimedBefore, minSpots, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due position update digests: %w", err)
+	}
+	return digests, nil
+}
+
+const sqlMarkPositionUpdateDigestSent = `
+UPDATE waitlist_position_notifications
+SET baseline_position = $2,
+    pending_since = NULL,
+    last_sent_at = CURRENT_TIMESTAMP,
+    claimed_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = $1
+`
+
+// MarkPositionUpdateDigestSent clears a user's pending position update and claim once it has been
+// emailed, making the reported position the baseline for the next one and starting a new digest
+// window
+func (s *Store) MarkPositionUpdateDigestSent(ctx context.Context, userID uuid.UUID, position int) error {
+	_, err := s.db.ExecContext(ctx, sqlMarkPositionUpdateDigestSent, userID, position)
+	if err != nil {
+		return fmt.Errorf("failed to mark position update digest sent: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -16,13 +17,16 @@ type CreateSegmentParams struct {
 	CampaignID     uuid.UUID
 	Name           string
 	Description    *string
+	Type           string
 	FilterCriteria JSONB
+	SizeThreshold  *int
+	SyncEnabled    bool
 }
 
 const sqlCreateSegment = `
-INSERT INTO segments (campaign_id, name, description, filter_criteria)
-VALUES ($1, $2, $3, $4)
-RETURNING id, campaign_id, name, description, filter_criteria, cached_user_count, cached_at, status, created_at, updated_at, deleted_at
+INSERT INTO segments (campaign_id, name, description, type, filter_criteria, size_threshold, sync_enabled)
+VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), 'dynamic'), $5, $6, $7)
+RETURNING id, campaign_id, name, description, type, filter_criteria, cached_user_count, cached_at, size_threshold, size_threshold_reached, sync_enabled, synced_at, status, created_at, updated_at, deleted_at
 `
 
 // CreateSegment creates a new segment
@@ -32,7 +36,10 @@ func (s *Store) CreateSegment(ctx context.Context, params CreateSegmentParams) (
 		params.CampaignID,
 		params.Name,
 		params.Description,
-		params.FilterCriteria)
+		params.Type,
+		params.FilterCriteria,
+		params.SizeThreshold,
+		params.SyncEnabled)
 	if err != nil {
 		return Segment{}, fmt.Errorf("failed to create segment: %w", err)
 	}
@@ -40,7 +47,7 @@ func (s *Store) CreateSegment(ctx context.Context, params CreateSegmentParams) (
 }
 
 const sqlGetSegmentByID = `
-SELECT id, campaign_id, name, description, filter_criteria, cached_user_count, cached_at, status, created_at, updated_at, deleted_at
+SELECT id, campaign_id, name, description, type, filter_criteria, cached_user_count, cached_at, size_threshold, size_threshold_reached, sync_enabled, synced_at, status, created_at, updated_at, deleted_at
 FROM segments
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -59,7 +66,7 @@ func (s *Store) GetSegmentByID(ctx context.Context, segmentID uuid.UUID) (Segmen
 }
 
 const sqlGetSegmentsByCampaign = `
-SELECT id, campaign_id, name, description, filter_criteria, cached_user_count, cached_at, status, created_at, updated_at, deleted_at
+SELECT id, campaign_id, name, description, type, filter_criteria, cached_user_count, cached_at, size_threshold, size_threshold_reached, sync_enabled, synced_at, status, created_at, updated_at, deleted_at
 FROM segments
 WHERE campaign_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -76,7 +83,7 @@ func (s *Store) GetSegmentsByCampaign(ctx context.Context, campaignID uuid.UUID)
 }
 
 const sqlGetActiveSegmentsByCampaign = `
-SELECT id, campaign_id, name, description, filter_criteria, cached_user_count, cached_at, status, created_at, updated_at, deleted_at
+SELECT id, campaign_id, name, description, type, filter_criteria, cached_user_count, cached_at, size_threshold, size_threshold_reached, sync_enabled, synced_at, status, created_at, updated_at, deleted_at
 FROM segments
 WHERE campaign_id = $1 AND status = 'active' AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -98,6 +105,10 @@ type UpdateSegmentParams struct {
 	Description    *string
 	FilterCriteria *JSONB
 	Status         *string
+	// SizeThreshold sets the size threshold; 0 removes it. The reached state is reset from the
+	// cached count so that changing the threshold does not itself fire a crossing.
+	SizeThreshold *int
+	SyncEnabled   *bool
 }
 
 const sqlUpdateSegment = `
@@ -106,9 +117,12 @@ SET name = COALESCE($2, name),
     description = COALESCE($3, description),
     filter_criteria = COALESCE($4, filter_criteria),
     status = COALESCE($5, status),
+    size_threshold = CASE WHEN $6::int IS NULL THEN size_threshold ELSE NULLIF($6::int, 0) END,
+    size_threshold_reached = CASE WHEN $6::int IS NULL THEN size_threshold_reached ELSE $6::int > 0 AND cached_user_count >= $6::int END,
+    sync_enabled = COALESCE($7, sync_enabled),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, campaign_id, name, description, filter_criteria, cached_user_count, cached_at, status, created_at, updated_at, deleted_at
+RETURNING id, campaign_id, name, description, type, filter_criteria, cached_user_count, cached_at, size_threshold, size_threshold_reached, sync_enabled, synced_at, status, created_at, updated_at, deleted_at
 `
 
 // UpdateSegment updates a segment
@@ -119,7 +133,9 @@ func (s *Store) UpdateSegment(ctx context.Context, segmentID uuid.UUID, params U
 		params.Name,
 		params.Description,
 		params.FilterCriteria,
-		params.Status)
+		params.Status,
+		params.SizeThreshold,
+		params.SyncEnabled)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Segment{}, ErrNotFound
@@ -183,10 +199,13 @@ func (s *Store) UpdateSegmentCachedCount(ctx context.Context, segmentID uuid.UUI
 
 // CountUsersMatchingCriteria counts users matching the given filter criteria
 func (s *Store) CountUsersMatchingCriteria(ctx context.Context, campaignID uuid.UUID, criteria SegmentFilterCriteria) (int, error) {
-	query, args := buildSegmentFilterQuery(campaignID, criteria, true, 0, 0)
+	query, args, err := buildSegmentFilterQuery(campaignID, criteria, true, 0, 0)
+	if err != nil {
+		return 0, err
+	}
 
 	var count int
-	err := s.db.GetContext(ctx, &count, query, args...)
+	err = s.db.GetContext(ctx, &count, query, args...)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count users matching criteria: %w", err)
 	}
@@ -194,12 +213,33 @@ func (s *Store) CountUsersMatchingCriteria(ctx context.Context, campaignID uuid.
 	return count, nil
 }
 
+// UserMatchesCriteria reports whether a waitlist user of the campaign matches the given filter criteria
+func (s *Store) UserMatchesCriteria(ctx context.Context, campaignID, userID uuid.UUID, criteria SegmentFilterCriteria) (bool, error) {
+	query, args, err := buildSegmentFilterQuery(campaignID, criteria, true, 0, 0)
+	if err != nil {
+		return false, err
+	}
+	query += fmt.Sprintf(" AND id = $%d", len(args)+1)
+	args = append(args, userID)
+
+	var count int
+	err = s.db.GetContext(ctx, &count, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to match user against criteria: %w", err)
+	}
+
+	return count > 0, nil
+}
+
 // GetUsersMatchingCriteria retrieves users matching the given filter criteria
 func (s *Store) GetUsersMatchingCriteria(ctx context.Context, campaignID uuid.UUID, criteria SegmentFilterCriteria, limit, offset int) ([]WaitlistUser, error) {
-	query, args := buildSegmentFilterQuery(campaignID, criteria, false, limit, offset)
+	query, args, err := buildSegmentFilterQuery(campaignID, criteria, false, limit, offset)
+	if err != nil {
+		return nil, err
+	}
 
 	var users []WaitlistUser
-	err := s.db.SelectContext(ctx, &users, query, args...)
+	err = s.db.SelectContext(ctx, &users, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users matching criteria: %w", err)
 	}
@@ -207,11 +247,12 @@ func (s *Store) GetUsersMatchingCriteria(ctx context.Context, campaignID uuid.UU
 	return users, nil
 }
 
-// buildSegmentFilterQuery builds the SQL query for filtering users based on segment criteria
-func buildSegmentFilterQuery(campaignID uuid.UUID, criteria SegmentFilterCriteria, countOnly bool, limit, offset int) (string, []interface{}) {
+// buildSegmentFilterQuery builds the SQL query for filtering users based on segment criteria.
+// The flat criteria and rules are compiled as one filter tree; see SegmentFilterCriteria.Tree.
+func buildSegmentFilterQuery(campaignID uuid.UUID, criteria SegmentFilterCriteria, countOnly bool, limit, offset int) (string, []interface{}, error) {
 	var query strings.Builder
-	args := []interface{}{campaignID}
-	argCount := 1
+	b := &filterBuilder{}
+	b.arg(campaignID)
 
 	if countOnly {
 		query.WriteString("SELECT COUNT(*) FROM waitlist_users WHERE campaign_id = $1 AND deleted_at IS NULL")
@@ -219,115 +260,36 @@ func buildSegmentFilterQuery(campaignID uuid.UUID, criteria SegmentFilterCriteri
 		query.WriteString("SELECT id, campaign_id, email, first_name, last_name, status, position, original_position, referral_code, referred_by_id, referral_count, verified_referral_count, points, email_verified, verification_token, verification_sent_at, verified_at, source, utm_source, utm_medium, utm_campaign, utm_term, utm_content, ip_address, user_agent, country_code, city, device_fingerprint, metadata, marketing_consent, marketing_consent_at, terms_accepted, terms_accepted_at, last_activity_at, share_count, created_at, updated_at, deleted_at FROM waitlist_users WHERE campaign_id = $1 AND deleted_at IS NULL")
 	}
 
-	// Status filter
-	if len(criteria.Statuses) > 0 {
-		argCount++
-		placeholders := make([]string, len(criteria.Statuses))
-		for i := range criteria.Statuses {
-			placeholders[i] = fmt.Sprintf("$%d", argCount+i)
-		}
-		query.WriteString(fmt.Sprintf(" AND status IN (%s)", strings.Join(placeholders, ", ")))
-		for _, status := range criteria.Statuses {
-			args = append(args, status)
-		}
-		argCount += len(criteria.Statuses) - 1
-	}
-
-	// Source filter
-	if len(criteria.Sources) > 0 {
-		argCount++
-		placeholders := make([]string, len(criteria.Sources))
-		for i := range criteria.Sources {
-			placeholders[i] = fmt.Sprintf("$%d", argCount+i)
-		}
-		query.WriteString(fmt.Sprintf(" AND source IN (%s)", strings.Join(placeholders, ", ")))
-		for _, source := range criteria.Sources {
-			args = append(args, source)
-		}
-		argCount += len(criteria.Sources) - 1
-	}
-
-	// Email verified filter
-	if criteria.EmailVerified != nil {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND email_verified = $%d", argCount))
-		args = append(args, *criteria.EmailVerified)
-	}
-
-	// Has referrals filter
-	if criteria.HasReferrals != nil {
-		if *criteria.HasReferrals {
-			query.WriteString(" AND referral_count > 0")
-		} else {
-			query.WriteString(" AND referral_count = 0")
+	if tree := criteria.Tree(); tree != nil {
+		query.WriteString(" AND ")
+		if err := tree.compile(b, 0, &query); err != nil {
+			return "", nil, err
 		}
 	}
 
-	// Min referrals filter
-	if criteria.MinReferrals != nil {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND referral_count >= $%d", argCount))
-		args = append(args, *criteria.MinReferrals)
-	}
-
-	// Position range filters
-	if criteria.MinPosition != nil {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND position >= $%d", argCount))
-		args = append(args, *criteria.MinPosition)
-	}
-
-	if criteria.MaxPosition != nil {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND position <= $%d", argCount))
-		args = append(args, *criteria.MaxPosition)
-	}
-
-	// Date range filters
-	if criteria.DateFrom != nil {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND created_at >= $%d", argCount))
-		args = append(args, *criteria.DateFrom)
-	}
-
-	if criteria.DateTo != nil {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND created_at <= $%d", argCount))
-		args = append(args, *criteria.DateTo)
-	}
-
-	// Custom fields filter (JSONB metadata)
-	for key, value := range criteria.CustomFields {
-		argCount++
-		query.WriteString(fmt.Sprintf(" AND metadata ->> $%d ILIKE $%d", argCount, argCount+1))
-		args = append(args, key, "%"+value+"%")
-		argCount++
-	}
-
 	// Add ordering and pagination for non-count queries
 	if !countOnly {
 		query.WriteString(" ORDER BY position ASC")
 		if limit > 0 {
-			argCount++
-			query.WriteString(fmt.Sprintf(" LIMIT $%d", argCount))
-			args = append(args, limit)
+			query.WriteString(" LIMIT " + b.arg(limit))
 		}
 		if offset > 0 {
-			argCount++
-			query.WriteString(fmt.Sprintf(" OFFSET $%d", argCount))
-			args = append(args, offset)
+			query.WriteString(" OFFSET " + b.arg(offset))
 		}
 	}
 
-	return query.String(), args
+	return query.String(), b.args, nil
 }
 
 // GetUsersForBlast retrieves all users matching segment criteria for a blast (no pagination)
 func (s *Store) GetUsersForBlast(ctx context.Context, campaignID uuid.UUID, criteria SegmentFilterCriteria) ([]WaitlistUser, error) {
-	query, args := buildSegmentFilterQuery(campaignID, criteria, false, 0, 0)
+	query, args, err := buildSegmentFilterQuery(campaignID, criteria, false, 0, 0)
+	if err != nil {
+		return nil, err
+	}
 
 	var users []WaitlistUser
-	err := s.db.SelectContext(ctx, &users, query, args...)
+	err = s.db.SelectContext(ctx, &users, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users for blast: %w", err)
 	}
@@ -399,5 +361,17 @@ func ParseFilterCriteria(jsonb JSONB) (SegmentFilterCriteria, error) {
 		}
 	}
 
+	if rules, ok := jsonb["rules"]; ok && rules != nil {
+		data, err := json.Marshal(rules)
+		if err != nil {
+			return SegmentFilterCriteria{}, fmt.Errorf("failed to marshal filter rules: %w", err)
+		}
+		var tree SegmentFilterNode
+		if err := json.Unmarshal(data, &tree); err != nil {
+			return SegmentFilterCriteria{}, fmt.Errorf("%w: %v", ErrInvalidFilter, err)
+		}
+		criteria.Rules = &tree
+	}
+
 	return criteria, nil
 }
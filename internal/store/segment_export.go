@@ -0,0 +1,165 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const segmentExportColumns = `id, account_id, campaign_id, segment_id, format, status, row_count, error_message, created_at, started_at, completed_at, expires_at`
+
+// CreateSegmentExportParams represents parameters for creating a segment export
+type CreateSegmentExportParams struct {
+	AccountID  uuid.UUID
+	CampaignID uuid.UUID
+	SegmentID  uuid.UUID
+	Format     string
+}
+
+const sqlCreateSegmentExport = `
+INSERT INTO segment_exports (account_id, campaign_id, segment_id, format)
+VALUES ($1, $2, $3, $4)
+RETURNING ` + segmentExportColumns
+
+// CreateSegmentExport creates a pending segment export
+func (s *Store) CreateSegmentExport(ctx context.Context, params CreateSegmentExportParams) (SegmentExport, error) {
+	var export SegmentExport
+	err := s.db.GetContext(ctx, &export, sqlCreateSegmentExport,
+		params.AccountID,
+		params.CampaignID,
+		params.SegmentID,
+		params.Format)
+	if err != nil {
+		return SegmentExport{}, fmt.Errorf("failed to create segment export: %w", err)
+	}
+	return export, nil
+}
+
+const sqlGetSegmentExportByID = `
+SELECT ` + segmentExportColumns + `
+FROM segment_exports
+WHERE id = $1
+`
+
+// GetSegmentExportByID retrieves a segment export by ID, without its file content
+func (s *Store) GetSegmentExportByID(ctx context.Context, exportID uuid.UUID) (SegmentExport, error) {
+	var export SegmentExport
+	err := s.db.GetContext(ctx, &export, sqlGetSegmentExportByID, exportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SegmentExport{}, ErrNotFound
+		}
+		return SegmentExport{}, fmt.Errorf("failed to get segment export: %w", err)
+	}
+	return export, nil
+}
+
+const sqlGetSegmentExportContent = `
+SELECT content
+FROM segment_exports
+WHERE id = $1 AND status = 'completed' AND expires_at > CURRENT_TIMESTAMP
+`
+
+// GetSegmentExportContent retrieves the file of a completed, unexpired segment export
+func (s *Store) GetSegmentExportContent(ctx context.Context, exportID uuid.UUID) ([]byte, error) {
+	var content []byte
+	err := s.db.GetContext(ctx, &content, sqlGetSegmentExportContent, exportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get segment export content: %w", err)
+	}
+	return content, nil
+}
+
+const sqlClaimSegmentExport = `
+UPDATE segment_exports
+SET status = 'processing',
+    started_at = CURRENT_TIMESTAMP
+WHERE id = (
+    SELECT id
+    FROM segment_exports
+    WHERE status = 'pending'
+       OR (status = 'processing' AND started_at < $1)
+    ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING ` + segmentExportColumns
+
+// ClaimSegmentExport marks the oldest pending segment export as processing and returns it.
+// Exports left processing since before staleBefore are claimed again. Returns ErrNotFound when
+// no export is waiting.
+func (s *Store) ClaimSegmentExport(ctx context.Context, staleBefore time.Time) (SegmentExport, error) {
+	var export SegmentExport
+	err := s.db.GetContext(ctx, &export, sqlClaimSegmentExport, staleBefore)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SegmentExport{}, ErrNotFound
+		}
+		return SegmentExport{}, fmt.Errorf("failed to claim segment export: %w", err)
+	}
+	return export, nil
+}
+
+const sqlCompleteSegmentExport = `
+UPDATE segment_exports
+SET status = 'completed',
+    content = $2,
+    row_count = $3,
+    completed_at = CURRENT_TIMESTAMP,
+    expires_at = $4
+WHERE id = $1
+`
+
+// CompleteSegmentExport stores the file of a segment export and marks it completed
+func (s *Store) CompleteSegmentExport(ctx context.Context, exportID uuid.UUID, content []byte, rowCount int, expiresAt time.Time) error {
+	_, err := s.db.ExecContext(ctx, sqlCompleteSegmentExport, exportID, content, rowCount, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete segment export: %w", err)
+	}
+	return nil
+}
+
+const sqlFailSegmentExport = `
+UPDATE segment_exports
+SET status = 'failed',
+    error_message = $2,
+    completed_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+// FailSegmentExport marks a segment export failed
+func (s *Store) FailSegmentExport(ctx context.Context, exportID uuid.UUID, errorMessage string) error {
+	_, err := s.db.ExecContext(ctx, sqlFailSegmentExport, exportID, errorMessage)
+	if err != nil {
+		return fmt.Errorf("failed to fail segment export: %w", err)
+	}
+	return nil
+}
+
+const sqlDeleteExpiredSegmentExports = `
+DELETE FROM segment_exports
+WHERE expires_at < $1
+   OR (status = 'failed' AND completed_at < $1)
+`
+
+// DeleteExpiredSegmentExports deletes exports whose file expired before the given time, and
+// failed exports that ended before it
+func (s *Store) DeleteExpiredSegmentExports(ctx context.Context, before time.Time) (int, error) {
+	res, err := s.db.ExecContext(ctx, sqlDeleteExpiredSegmentExports, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired segment exports: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(rows), nil
+}
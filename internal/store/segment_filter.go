@@ -0,0 +1,510 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ErrInvalidFilter is returned when a segment filter tree references an unknown field or operator,
+// or a value of the wrong type
+var ErrInvalidFilter = errors.New("invalid segment filter")
+
+// maxFilterDepth bounds how deeply segment filter groups can be nested
+const maxFilterDepth = 8
+
+// customFieldPrefix selects a key of the user's metadata, e.g. custom.company
+const customFieldPrefix = "custom."
+
+// Segment filter operators
+const (
+	FilterOpEq            = "eq"
+	FilterOpNeq           = "neq"
+	FilterOpIn            = "in"
+	FilterOpNotIn         = "not_in"
+	FilterOpContains      = "contains"
+	FilterOpExists        = "exists"
+	FilterOpGt            = "gt"
+	FilterOpGte           = "gte"
+	FilterOpLt            = "lt"
+	FilterOpLte           = "lte"
+	FilterOpWithinDays    = "within_days"
+	FilterOpOlderThanDays = "older_than_days"
+)
+
+// SegmentFilterNode is a node of a segment filter tree. A node is either a group combining
+// child nodes with And, Or or Not, or a condition comparing Field to Value with Operator.
+//
+//	{"and": [
+//	  {"field": "utm_source", "operator": "in", "value": ["twitter", "x"]},
+//	  {"not": {"field": "blast_opened", "operator": "eq", "value": true}},
+//	  {"or": [
+//	    {"field": "custom.plan", "operator": "exists"},
+//	    {"field": "points", "operator": "gte", "value": 100}
+//	  ]}
+//	]}
+type SegmentFilterNode struct {
+	And []SegmentFilterNode `json:"and,omitempty"`
+	Or  []SegmentFilterNode `json:"or,omitempty"`
+	Not *SegmentFilterNode  `json:"not,omitempty"`
+
+	Field    string      `json:"field,omitempty"`
+	Operator string      `json:"operator,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+type filterFieldKind int
+
+const (
+	filterFieldString filterFieldKind = iota
+	filterFieldNumber
+	filterFieldBool
+	filterFieldTime
+	filterFieldEngagement
+)
+
+// filterField describes a field segment filters can reference and the SQL expression it reads
+type filterField struct {
+	kind filterFieldKind
+	expr string
+}
+
+var segmentFilterFields = map[string]filterField{
+	"status":                  {filterFieldString, "status::text"},
+	"source":                  {filterFieldString, "source::text"},
+	"email":                   {filterFieldString, "email"},
+	"utm_source":              {filterFieldString, "utm_source"},
+	"utm_medium":              {filterFieldString, "utm_medium"},
+	"utm_campaign":            {filterFieldString, "utm_campaign"},
+	"utm_term":                {filterFieldString, "utm_term"},
+	"utm_content":             {filterFieldString, "utm_content"},
+	"country":                 {filterFieldString, "country"},
+	"country_code":            {filterFieldString, "country_code"},
+	"region":                  {filterFieldString, "region"},
+	"region_code":             {filterFieldString, "region_code"},
+	"city":                    {filterFieldString, "city"},
+	"device_type":             {filterFieldString, "device_type::text"},
+	"device_os":               {filterFieldString, "device_os::text"},
+	"position":                {filterFieldNumber, "position"},
+	"referral_count":          {filterFieldNumber, "referral_count"},
+	"verified_referral_count": {filterFieldNumber, "verified_referral_count"},
+	"points":                  {filterFieldNumber, "points"},
+	"share_count":             {filterFieldNumber, "share_count"},
+	"rewards_earned": {filterFieldNumber, `(SELECT COUNT(*) FROM user_rewards ur
+		WHERE ur.user_id = waitlist_users.id AND ur.status IN ('earned', 'delivered', 'redeemed'))`},
+	"email_verified":    {filterFieldBool, "email_verified"},
+	"marketing_consent": {filterFieldBool, "marketing_consent"},
+	"created_at":        {filterFieldTime, "created_at"},
+	"verified_at":       {filterFieldTime, "verified_at"},
+	"last_activity_at":  {filterFieldTime, "last_activity_at"},
+	"blast_opened":      {filterFieldEngagement, "opened_at"},
+	"blast_clicked":     {filterFieldEngagement, "clicked_at"},
+}
+
+var filterOperatorsByKind = map[filterFieldKind][]string{
+	filterFieldString:     {FilterOpEq, FilterOpNeq, FilterOpIn, FilterOpNotIn, FilterOpContains, FilterOpExists},
+	filterFieldNumber:     {FilterOpEq, FilterOpNeq, FilterOpIn, FilterOpNotIn, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte},
+	filterFieldBool:       {FilterOpEq},
+	filterFieldTime:       {FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte, FilterOpExists, FilterOpWithinDays, FilterOpOlderThanDays},
+	filterFieldEngagement: {FilterOpEq, FilterOpIn},
+}
+
+// customFieldOperators are the operators allowed on custom.<key> metadata fields
+var customFieldOperators = []string{
+	FilterOpEq, FilterOpNeq, FilterOpIn, FilterOpNotIn, FilterOpContains, FilterOpExists,
+	FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte,
+}
+
+var comparisonSQL = map[string]string{
+	FilterOpEq:  "=",
+	FilterOpNeq: "<>",
+	FilterOpGt:  ">",
+	FilterOpGte: ">=",
+	FilterOpLt:  "<",
+	FilterOpLte: "<=",
+}
+
+// Validate checks that every node of the tree is well formed
+func (n SegmentFilterNode) Validate() error {
+	return n.compile(&filterBuilder{}, 0, new(strings.Builder))
+}
+
+// Tree returns the criteria as a single filter tree: the flat criteria become conditions
+// combined with AND, together with Rules when present. Returns nil when nothing is filtered.
+func (c SegmentFilterCriteria) Tree() *SegmentFilterNode {
+	var conditions []SegmentFilterNode
+	add := func(field, operator string, value interface{}) {
+		conditions = append(conditions, SegmentFilterNode{Field: field, Operator: operator, Value: value})
+	}
+
+	if len(c.Statuses) > 0 {
+		add("status", FilterOpIn, c.Statuses)
+	}
+	if len(c.Sources) > 0 {
+		add("source", FilterOpIn, c.Sources)
+	}
+	if c.EmailVerified != nil {
+		add("email_verified", FilterOpEq, *c.EmailVerified)
+	}
+	if c.HasReferrals != nil {
+		if *c.HasReferrals {
+			add("referral_count", FilterOpGt, 0)
+		} else {
+			add("referral_count", FilterOpEq, 0)
+		}
+	}
+	if c.MinReferrals != nil {
+		add("referral_count", FilterOpGte, *c.MinReferrals)
+	}
+	if c.MinPosition != nil {
+		add("position", FilterOpGte, *c.MinPosition)
+	}
+	if c.MaxPosition != nil {
+		add("position", FilterOpLte, *c.MaxPosition)
+	}
+	if c.DateFrom != nil {
+		add("created_at", FilterOpGte, *c.DateFrom)
+	}
+	if c.DateTo != nil {
+		add("created_at", FilterOpLte, *c.DateTo)
+	}
+	for _, key := range slices.Sorted(maps.Keys(c.CustomFields)) {
+		add(customFieldPrefix+key, FilterOpContains, c.CustomFields[key])
+	}
+	if c.Rules != nil {
+		conditions = append(conditions, *c.Rules)
+	}
+
+	switch len(conditions) {
+	case 0:
+		return nil
+	case 1:
+		return &conditions[0]
+	default:
+		return &SegmentFilterNode{And: conditions}
+	}
+}
+
+// filterBuilder collects the positional arguments of a compiled filter
+type filterBuilder struct {
+	args []interface{}
+}
+
+// arg adds a query argument and returns its placeholder
+func (b *filterBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// compile writes the SQL condition for the node to sql
+func (n SegmentFilterNode) compile(b *filterBuilder, depth int, sql *strings.Builder) error {
+	if depth > maxFilterDepth {
+		return fmt.Errorf("%w: groups nested deeper than %d levels", ErrInvalidFilter, maxFilterDepth)
+	}
+
+	kinds := 0
+	for _, set := range []bool{n.And != nil, n.Or != nil, n.Not != nil, n.Field != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("%w: each node needs exactly one of and, or, not or field", ErrInvalidFilter)
+	}
+
+	switch {
+	case n.And != nil:
+		return compileGroup(b, depth, sql, n.And, " AND ")
+	case n.Or != nil:
+		return compileGroup(b, depth, sql, n.Or, " OR ")
+	case n.Not != nil:
+		sql.WriteString("NOT (")
+		if err := n.Not.compile(b, depth+1, sql); err != nil {
+			return err
+		}
+		sql.WriteString(")")
+		return nil
+	default:
+		return n.compileCondition(b, sql)
+	}
+}
+
+func compileGroup(b *filterBuilder, depth int, sql *strings.Builder, children []SegmentFilterNode, separator string) error {
+	if len(children) == 0 {
+		return fmt.Errorf("%w: groups need at least one condition", ErrInvalidFilter)
+	}
+
+	sql.WriteString("(")
+	for i, child := range children {
+		if i > 0 {
+			sql.WriteString(separator)
+		}
+		if err := child.compile(b, depth+1, sql); err != nil {
+			return err
+		}
+	}
+	sql.WriteString(")")
+	return nil
+}
+
+// compileCondition writes the SQL for a single field comparison
+func (n SegmentFilterNode) compileCondition(b *filterBuilder, sql *strings.Builder) error {
+	if key, ok := strings.CutPrefix(n.Field, customFieldPrefix); ok {
+		if key == "" {
+			return fmt.Errorf("%w: custom field needs a key", ErrInvalidFilter)
+		}
+		if !slices.Contains(customFieldOperators, n.Operator) {
+			return fmt.Errorf("%w: operator %q is not supported on custom fields", ErrInvalidFilter, n.Operator)
+		}
+		return n.compileCustomField(b, sql, key)
+	}
+
+	field, ok := segmentFilterFields[n.Field]
+	if !ok {
+		return fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, n.Field)
+	}
+	if !slices.Contains(filterOperatorsByKind[field.kind], n.Operator) {
+		return fmt.Errorf("%w: operator %q is not supported on %s", ErrInvalidFilter, n.Operator, n.Field)
+	}
+
+	switch field.kind {
+	case filterFieldString:
+		return compileStringCondition(b, sql, field.expr, n.Operator, n.Value)
+	case filterFieldNumber:
+		return compileNumberCondition(b, sql, field.expr, n.Operator, n.Value)
+	case filterFieldBool:
+		value, ok := n.Value.(bool)
+		if !ok {
+			return fmt.Errorf("%w: %s needs a boolean value", ErrInvalidFilter, n.Field)
+		}
+		sql.WriteString(fmt.Sprintf("COALESCE(%s, FALSE) = %s", field.expr, b.arg(value)))
+		return nil
+	case filterFieldTime:
+		return compileTimeCondition(b, sql, field.expr, n.Operator, n.Value)
+	default:
+		return compileEngagementCondition(b, sql, field.expr, n.Operator, n.Value)
+	}
+}
+
+// compileCustomField compares a key of the user's metadata. Numeric comparisons only match
+// values that are numbers.
+func (n SegmentFilterNode) compileCustomField(b *filterBuilder, sql *strings.Builder, key string) error {
+	expr := fmt.Sprintf("(metadata ->> %s)", b.arg(key))
+
+	switch n.Operator {
+	case FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		value, err := filterNumber(n.Value)
+		if err != nil {
+			return err
+		}
+		sql.WriteString(fmt.Sprintf("(CASE WHEN %s ~ '^-?[0-9]+(\\.[0-9]+)?$' THEN %s::numeric END) %s %s::numeric",
+			expr, expr, comparisonSQL[n.Operator], b.arg(value)))
+		return nil
+	default:
+		return compileStringCondition(b, sql, expr, n.Operator, n.Value)
+	}
+}
+
+func compileStringCondition(b *filterBuilder, sql *strings.Builder, expr, operator string, raw interface{}) error {
+	switch operator {
+	case FilterOpExists:
+		exists, ok := raw.(bool)
+		if raw == nil {
+			exists, ok = true, true
+		}
+		if !ok {
+			return fmt.Errorf("%w: exists takes an optional boolean value", ErrInvalidFilter)
+		}
+		if exists {
+			sql.WriteString(fmt.Sprintf("(%s IS NOT NULL AND %s <> '')", expr, expr))
+		} else {
+			sql.WriteString(fmt.Sprintf("(%s IS NULL OR %s = '')", expr, expr))
+		}
+		return nil
+	case FilterOpIn, FilterOpNotIn:
+		values, err := filterStrings(raw)
+		if err != nil {
+			return err
+		}
+		if operator == FilterOpIn {
+			sql.WriteString(fmt.Sprintf("%s = ANY(%s)", expr, b.arg(pq.Array(values))))
+		} else {
+			sql.WriteString(fmt.Sprintf("(%s IS NULL OR NOT (%s = ANY(%s)))", expr, expr, b.arg(pq.Array(values))))
+		}
+		return nil
+	}
+
+	value, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("%w: %s needs a string value", ErrInvalidFilter, operator)
+	}
+
+	switch operator {
+	case FilterOpContains:
+		sql.WriteString(fmt.Sprintf("%s ILIKE %s", expr, b.arg("%"+value+"%")))
+	case FilterOpNeq:
+		sql.WriteString(fmt.Sprintf("%s IS DISTINCT FROM %s", expr, b.arg(value)))
+	default:
+		sql.WriteString(fmt.Sprintf("%s = %s", expr, b.arg(value)))
+	}
+	return nil
+}
+
+func compileNumberCondition(b *filterBuilder, sql *strings.Builder, expr, operator string, raw interface{}) error {
+	if operator == FilterOpIn || operator == FilterOpNotIn {
+		values, err := filterNumbers(raw)
+		if err != nil {
+			return err
+		}
+		negate := ""
+		if operator == FilterOpNotIn {
+			negate = "NOT "
+		}
+		sql.WriteString(fmt.Sprintf("%s(%s = ANY(%s::numeric[]))", negate, expr, b.arg(pq.Array(values))))
+		return nil
+	}
+
+	value, err := filterNumber(raw)
+	if err != nil {
+		return err
+	}
+	sql.WriteString(fmt.Sprintf("%s %s %s::numeric", expr, comparisonSQL[operator], b.arg(value)))
+	return nil
+}
+
+func compileTimeCondition(b *filterBuilder, sql *strings.Builder, expr, operator string, raw interface{}) error {
+	switch operator {
+	case FilterOpExists:
+		exists, ok := raw.(bool)
+		if raw == nil {
+			exists, ok = true, true
+		}
+		if !ok {
+			return fmt.Errorf("%w: exists takes an optional boolean value", ErrInvalidFilter)
+		}
+		if exists {
+			sql.WriteString(expr + " IS NOT NULL")
+		} else {
+			sql.WriteString(expr + " IS NULL")
+		}
+		return nil
+	case FilterOpWithinDays, FilterOpOlderThanDays:
+		days, err := filterNumber(raw)
+		if err != nil {
+			return err
+		}
+		comparison := ">="
+		if operator == FilterOpOlderThanDays {
+			comparison = "<"
+		}
+		sql.WriteString(fmt.Sprintf("%s %s CURRENT_TIMESTAMP - %s::float8 * INTERVAL '1 day'", expr, comparison, b.arg(days)))
+		return nil
+	}
+
+	value, err := filterTime(raw)
+	if err != nil {
+		return err
+	}
+	sql.WriteString(fmt.Sprintf("%s %s %s", expr, comparisonSQL[operator], b.arg(value)))
+	return nil
+}
+
+// compileEngagementCondition matches users who opened or clicked an email blast.
+// eq true or false checks any blast; in checks the listed blasts. Opens and clicks are recorded
+// from the Resend webhook by provider message ID, so blasts sent without RESEND_WEBHOOK_SECRET
+// configured never count as opened or clicked.
+func compileEngagementCondition(b *filterBuilder, sql *strings.Builder, column, operator string, raw interface{}) error {
+	query := fmt.Sprintf(`EXISTS (SELECT 1 FROM blast_recipients br
+		WHERE br.user_id = waitlist_users.id AND br.%s IS NOT NULL`, column)
+
+	if operator == FilterOpIn {
+		ids, err := filterStrings(raw)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if _, err := uuid.Parse(id); err != nil {
+				return fmt.Errorf("%w: %q is not a blast id", ErrInvalidFilter, id)
+			}
+		}
+		sql.WriteString(fmt.Sprintf("%s AND br.blast_id::text = ANY(%s))", query, b.arg(pq.Array(ids))))
+		return nil
+	}
+
+	engaged, ok := raw.(bool)
+	if !ok {
+		return fmt.Errorf("%w: eq needs a boolean value", ErrInvalidFilter)
+	}
+	if !engaged {
+		sql.WriteString("NOT ")
+	}
+	sql.WriteString(query + ")")
+	return nil
+}
+
+func filterNumber(raw interface{}) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case int:
+		return float64(v), nil
+	default:
+		return 0, fmt.Errorf("%w: expected a number, got %v", ErrInvalidFilter, raw)
+	}
+}
+
+func filterNumbers(raw interface{}) ([]float64, error) {
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected a list of numbers", ErrInvalidFilter)
+	}
+	values := make([]float64, 0, len(items))
+	for _, item := range items {
+		value, err := filterNumber(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func filterStrings(raw interface{}) ([]string, error) {
+	if values, ok := raw.([]string); ok {
+		return values, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%w: expected a non-empty list of strings", ErrInvalidFilter)
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a list of strings", ErrInvalidFilter)
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func filterTime(raw interface{}) (time.Time, error) {
+	switch v := raw.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, nil
+		}
+		if t, err := time.Parse("2006-01-02", v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: expected an RFC 3339 timestamp or date, got %v", ErrInvalidFilter, raw)
+}
@@ -0,0 +1,178 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestSegmentFilterCriteria_Tree(t *testing.T) {
+	t.Parallel()
+
+	minReferrals := 2
+	hasReferrals := false
+	campaignID := uuid.New()
+
+	tests := []struct {
+		name     string
+		criteria SegmentFilterCriteria
+		wantSQL  string
+		wantArgs int
+	}{
+		{
+			name:     "no criteria",
+			criteria: SegmentFilterCriteria{},
+			wantSQL:  "deleted_at IS NULL",
+			wantArgs: 1,
+		},
+		{
+			name:     "single flat criterion",
+			criteria: SegmentFilterCriteria{Statuses: []string{"pending", "verified"}},
+			wantSQL:  "AND status::text = ANY($2)",
+			wantArgs: 2,
+		},
+		{
+			name: "flat criteria are combined with AND",
+			criteria: SegmentFilterCriteria{
+				HasReferrals: &hasReferrals,
+				MinReferrals: &minReferrals,
+				CustomFields: map[string]string{"company": "acme"},
+			},
+			wantSQL:  "AND (referral_count = $2::numeric AND referral_count >= $3::numeric AND (metadata ->> $4) ILIKE $5)",
+			wantArgs: 5,
+		},
+		{
+			name: "rules are combined with flat criteria",
+			criteria: SegmentFilterCriteria{
+				Sources: []string{"referral"},
+				Rules: &SegmentFilterNode{Or: []SegmentFilterNode{
+					{Field: "utm_source", Operator: FilterOpEq, Value: "twitter"},
+					{Not: &SegmentFilterNode{Field: "blast_opened", Operator: FilterOpEq, Value: true}},
+				}},
+			},
+			wantSQL:  "AND (source::text = ANY($2) AND (utm_source = $3 OR NOT (EXISTS (SELECT 1 FROM blast_recipients br",
+			wantArgs: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			query, args, err := buildSegmentFilterQuery(campaignID, tt.criteria, true, 0, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(query, tt.wantSQL) {
+				t.Errorf("query %q does not contain %q", query, tt.wantSQL)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("got %d args, want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestSegmentFilterNode_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+	}{
+		{
+			name: "nested groups over supported fields",
+			json: `{"and": [
+				{"field": "country_code", "operator": "in", "value": ["US", "CA"]},
+				{"field": "last_activity_at", "operator": "within_days", "value": 7},
+				{"or": [
+					{"field": "custom.plan", "operator": "exists"},
+					{"field": "custom.seats", "operator": "gte", "value": 10},
+					{"field": "rewards_earned", "operator": "gt", "value": 0}
+				]},
+				{"not": {"field": "marketing_consent", "operator": "eq", "value": false}}
+			]}`,
+		},
+		{
+			name: "clicked specific blasts",
+			json: `{"field": "blast_clicked", "operator": "in", "value": ["` + uuid.NewString() + `"]}`,
+		},
+		{
+			name:    "unknown field",
+			json:    `{"field": "password", "operator": "eq", "value": "x"}`,
+			wantErr: true,
+		},
+		{
+			name:    "operator not supported on field",
+			json:    `{"field": "email_verified", "operator": "contains", "value": "x"}`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong value type",
+			json:    `{"field": "points", "operator": "gt", "value": "many"}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty group",
+			json:    `{"or": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "node with both a group and a field",
+			json:    `{"and": [{"field": "points", "operator": "gt", "value": 1}], "field": "points"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid blast id",
+			json:    `{"field": "blast_opened", "operator": "in", "value": ["not-a-uuid"]}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var node SegmentFilterNode
+			if err := json.Unmarshal([]byte(tt.json), &node); err != nil {
+				t.Fatalf("failed to unmarshal node: %v", err)
+			}
+
+			err := node.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidFilter) {
+					t.Errorf("expected ErrInvalidFilter, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseFilterCriteria_Rules(t *testing.T) {
+	t.Parallel()
+
+	criteria, err := ParseFilterCriteria(JSONB{
+		"statuses": []interface{}{"verified"},
+		"rules": map[string]interface{}{
+			"not": map[string]interface{}{"field": "device_type", "operator": "eq", "value": "mobile"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(criteria.Statuses) != 1 {
+		t.Errorf("expected flat statuses to be kept, got %v", criteria.Statuses)
+	}
+	if criteria.Rules == nil || criteria.Rules.Not == nil || criteria.Rules.Not.Field != "device_type" {
+		t.Fatalf("expected rules to be parsed, got %+v", criteria.Rules)
+	}
+}
@@ -0,0 +1,222 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// segmentMemberUserColumns is the waitlist user column list selected through segment_members
+const segmentMemberUserColumns = `wu.id, wu.campaign_id, wu.email, wu.first_name, wu.last_name, wu.status, wu.position, wu.original_position, wu.referral_code, wu.referred_by_id, wu.referral_count, wu.verified_referral_count, wu.points, wu.email_verified, wu.verification_token, wu.verification_sent_at, wu.verified_at, wu.source, wu.utm_source, wu.utm_medium, wu.utm_campaign, wu.utm_term, wu.utm_content, wu.ip_address, wu.user_agent, wu.country_code, wu.city, wu.device_fingerprint, wu.metadata, wu.marketing_consent, wu.marketing_consent_at, wu.terms_accepted, wu.terms_accepted_at, wu.last_activity_at, wu.share_count, wu.created_at, wu.updated_at, wu.deleted_at`
+
+const sqlAddSegmentMembers = `
+WITH eligible AS (
+    SELECT wu.id
+    FROM waitlist_users wu
+    JOIN segments s ON s.campaign_id = wu.campaign_id
+    WHERE s.id = $1 AND wu.id = ANY($2::uuid[]) AND wu.deleted_at IS NULL
+),
+added AS (
+    INSERT INTO segment_members (segment_id, user_id)
+    SELECT $1, id FROM eligible
+    ON CONFLICT (segment_id, user_id) DO NOTHING
+    RETURNING user_id
+),
+logged AS (
+    INSERT INTO segment_membership_events (segment_id, user_id, action, source)
+    SELECT $1, user_id, 'added', $3 FROM added
+    RETURNING id
+)
+SELECT COUNT(*) FROM logged
+`
+
+// AddSegmentMembers adds users of the segment's campaign to a static segment and records the change
+// in the membership history. Users that are already members or belong to another campaign are skipped.
+// Returns the number of users added.
+func (s *Store) AddSegmentMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source SegmentMembershipSource) (int, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	var added int
+	err := s.db.GetContext(ctx, &added, sqlAddSegmentMembers, segmentID, pq.Array(uuidStrings(userIDs)), string(source))
+	if err != nil {
+		return 0, fmt.Errorf("failed to add segment members: %w", err)
+	}
+	return added, nil
+}
+
+const sqlRemoveSegmentMembers = `
+WITH removed AS (
+    DELETE FROM segment_members
+    WHERE segment_id = $1 AND user_id = ANY($2::uuid[])
+    RETURNING user_id
+),
+logged AS (
+    INSERT INTO segment_membership_events (segment_id, user_id, action, source)
+    SELECT $1, user_id, 'removed', $3 FROM removed
+    RETURNING id
+)
+SELECT COUNT(*) FROM logged
+`
+
+// RemoveSegmentMembers removes users from a static segment and records the change in the membership history.
+// Returns the number of users removed.
+func (s *Store) RemoveSegmentMembers(ctx context.Context, segmentID uuid.UUID, userIDs []uuid.UUID, source SegmentMembershipSource) (int, error) {
+	if len(userIDs) == 0 {
+		return 0, nil
+	}
+
+	var removed int
+	err := s.db.GetContext(ctx, &removed, sqlRemoveSegmentMembers, segmentID, pq.Array(uuidStrings(userIDs)), string(source))
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove segment members: %w", err)
+	}
+	return removed, nil
+}
+
+const sqlGetWaitlistUserIDsByEmails = `
+SELECT id, email
+FROM waitlist_users
+WHERE campaign_id = $1 AND LOWER(email) = ANY($2) AND deleted_at IS NULL
+`
+
+// GetWaitlistUserIDsByEmails looks up the campaign's waitlist users by email.
+// Emails are matched case-insensitively and the returned map is keyed by the lowercased email.
+func (s *Store) GetWaitlistUserIDsByEmails(ctx context.Context, campaignID uuid.UUID, emails []string) (map[string]uuid.UUID, error) {
+	result := make(map[string]uuid.UUID)
+	if len(emails) == 0 {
+		return result, nil
+	}
+
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+
+	var rows []struct {
+		ID    uuid.UUID `db:"id"`
+		Email string    `db:"email"`
+	}
+	err := s.db.SelectContext(ctx, &rows, sqlGetWaitlistUserIDsByEmails, campaignID, pq.Array(lowered))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist users by emails: %w", err)
+	}
+
+	for _, row := range rows {
+		result[strings.ToLower(row.Email)] = row.ID
+	}
+	return result, nil
+}
+
+const sqlGetSegmentMembers = `
+SELECT ` + segmentMemberUserColumns + `
+FROM segment_members sm
+JOIN waitlist_users wu ON wu.id = sm.user_id
+WHERE sm.segment_id = $1 AND wu.deleted_at IS NULL
+ORDER BY wu.position ASC
+LIMIT $2 OFFSET $3
+`
+
+// GetSegmentMembers retrieves a page of a static segment's members ordered by position
+func (s *Store) GetSegmentMembers(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]WaitlistUser, error) {
+	var users []WaitlistUser
+	err := s.db.SelectContext(ctx, &users, sqlGetSegmentMembers, segmentID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segment members: %w", err)
+	}
+	return users, nil
+}
+
+const sqlGetAllSegmentMembers = `
+SELECT ` + segmentMemberUserColumns + `
+FROM segment_members sm
+JOIN waitlist_users wu ON wu.id = sm.user_id
+WHERE sm.segment_id = $1 AND wu.deleted_at IS NULL
+ORDER BY wu.position ASC
+`
+
+// GetAllSegmentMembers retrieves every member of a static segment (no pagination)
+func (s *Store) GetAllSegmentMembers(ctx context.Context, segmentID uuid.UUID) ([]WaitlistUser, error) {
+	var users []WaitlistUser
+	err := s.db.SelectContext(ctx, &users, sqlGetAllSegmentMembers, segmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get all segment members: %w", err)
+	}
+	return users, nil
+}
+
+const sqlCountSegmentMembers = `
+SELECT COUNT(*)
+FROM segment_members sm
+JOIN waitlist_users wu ON wu.id = sm.user_id
+WHERE sm.segment_id = $1 AND wu.deleted_at IS NULL
+`
+
+// CountSegmentMembers counts the members of a static segment
+func (s *Store) CountSegmentMembers(ctx context.Context, segmentID uuid.UUID) (int, error) {
+	var count int
+	err := s.db.GetContext(ctx, &count, sqlCountSegmentMembers, segmentID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count segment members: %w", err)
+	}
+	return count, nil
+}
+
+const sqlGetSegmentMembershipHistory = `
+SELECT e.id, e.segment_id, e.user_id, wu.email, e.action, e.source, e.created_at
+FROM segment_membership_events e
+JOIN waitlist_users wu ON wu.id = e.user_id
+WHERE e.segment_id = $1
+ORDER BY e.created_at DESC
+LIMIT $2 OFFSET $3
+`
+
+// GetSegmentMembershipHistory retrieves the membership changes of a static segment, newest first
+func (s *Store) GetSegmentMembershipHistory(ctx context.Context, segmentID uuid.UUID, limit, offset int) ([]SegmentMembershipEvent, error) {
+	var events []SegmentMembershipEvent
+	err := s.db.SelectContext(ctx, &events, sqlGetSegmentMembershipHistory, segmentID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segment membership history: %w", err)
+	}
+	return events, nil
+}
+
+// GetSegmentUsers retrieves all users of a segment: the members of a static segment,
+// or the users matching a dynamic segment's filter criteria
+func (s *Store) GetSegmentUsers(ctx context.Context, segment Segment) ([]WaitlistUser, error) {
+	if segment.Type == string(SegmentTypeStatic) {
+		return s.GetAllSegmentMembers(ctx, segment.ID)
+	}
+
+	criteria, err := ParseFilterCriteria(segment.FilterCriteria)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse filter criteria for segment %s: %w", segment.ID, err)
+	}
+	return s.GetUsersForBlast(ctx, segment.CampaignID, criteria)
+}
+
+// CountSegmentUsers counts the users of a segment: the members of a static segment,
+// or the users matching a dynamic segment's filter criteria
+func (s *Store) CountSegmentUsers(ctx context.Context, segment Segment) (int, error) {
+	if segment.Type == string(SegmentTypeStatic) {
+		return s.CountSegmentMembers(ctx, segment.ID)
+	}
+
+	criteria, err := ParseFilterCriteria(segment.FilterCriteria)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse filter criteria for segment %s: %w", segment.ID, err)
+	}
+	return s.CountUsersMatchingCriteria(ctx, segment.CampaignID, criteria)
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = id.String()
+	}
+	return strs
+}
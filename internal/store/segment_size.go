@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const sqlGetSegmentsDueForCountRefresh = `
+SELECT s.id, s.campaign_id, s.name, s.description, s.type, s.filter_criteria, s.cached_user_count, s.cached_at, s.size_threshold, s.size_threshold_reached, s.sync_enabled, s.synced_at, s.status, s.created_at, s.updated_at, s.deleted_at
+FROM segments s
+JOIN campaigns c ON c.id = s.campaign_id AND c.deleted_at IS NULL
+WHERE s.status = 'active'
+  AND s.deleted_at IS NULL
+  AND (s.cached_at IS NULL OR s.cached_at < $1)
+ORDER BY s.cached_at NULLS FIRST
+LIMIT $2
+`
+
+// GetSegmentsDueForCountRefresh retrieves active segments whose cached count is older than
+// cachedBefore, least recently counted first
+func (s *Store) GetSegmentsDueForCountRefresh(ctx context.Context, cachedBefore time.Time, limit int) ([]Segment, error) {
+	var segments []Segment
+	err := s.db.SelectContext(ctx, &segments, sqlGetSegmentsDueForCountRefresh, cachedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segments due for count refresh: %w", err)
+	}
+	return segments, nil
+}
+
+const sqlRecordSegmentSize = `
+INSERT INTO segment_size_history (segment_id, date, user_count)
+VALUES ($1, (CURRENT_TIMESTAMP AT TIME ZONE 'UTC')::date, $2)
+ON CONFLICT (segment_id, date)
+DO UPDATE SET user_count = EXCLUDED.user_count, recorded_at = CURRENT_TIMESTAMP
+`
+
+// RecordSegmentSize records a segment's count for the current UTC day, replacing any count
+// recorded earlier that day
+func (s *Store) RecordSegmentSize(ctx context.Context, segmentID uuid.UUID, count int) error {
+	_, err := s.db.ExecContext(ctx, sqlRecordSegmentSize, segmentID, count)
+	if err != nil {
+		return fmt.Errorf("failed to record segment size: %w", err)
+	}
+	return nil
+}
+
+const sqlGetSegmentSizeHistory = `
+SELECT segment_id, date, user_count
+FROM segment_size_history
+WHERE segment_id = $1 AND date >= $2
+ORDER BY date ASC
+`
+
+// GetSegmentSizeHistory retrieves a segment's daily counts from the given day on, oldest first
+func (s *Store) GetSegmentSizeHistory(ctx context.Context, segmentID uuid.UUID, since time.Time) ([]SegmentSize, error) {
+	var sizes []SegmentSize
+	err := s.db.SelectContext(ctx, &sizes, sqlGetSegmentSizeHistory, segmentID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segment size history: %w", err)
+	}
+	return sizes, nil
+}
+
+const sqlUpdateSegmentThresholdReached = `
+UPDATE segments
+SET size_threshold_reached = $2
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// UpdateSegmentThresholdReached records whether a segment is at or above its size threshold
+func (s *Store) UpdateSegmentThresholdReached(ctx context.Context, segmentID uuid.UUID, reached bool) error {
+	_, err := s.db.ExecContext(ctx, sqlUpdateSegmentThresholdReached, segmentID, reached)
+	if err != nil {
+		return fmt.Errorf("failed to update segment threshold state: %w", err)
+	}
+	return nil
+}
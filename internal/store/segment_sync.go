@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const sqlGetSegmentsDueForSync = `
+SELECT s.id, s.campaign_id, s.name, s.description, s.type, s.filter_criteria, s.cached_user_count, s.cached_at, s.size_threshold, s.size_threshold_reached, s.sync_enabled, s.synced_at, s.status, s.created_at, s.updated_at, s.deleted_at
+FROM segments s
+JOIN campaigns c ON c.id = s.campaign_id AND c.deleted_at IS NULL
+WHERE s.sync_enabled
+  AND s.status = 'active'
+  AND s.deleted_at IS NULL
+  AND (s.synced_at IS NULL OR s.synced_at < $1)
+ORDER BY s.synced_at NULLS FIRST
+LIMIT $2
+`
+
+// GetSegmentsDueForSync retrieves active segments with sync enabled that were last synced
+// before syncedBefore, least recently synced first
+func (s *Store) GetSegmentsDueForSync(ctx context.Context, syncedBefore time.Time, limit int) ([]Segment, error) {
+	var segments []Segment
+	err := s.db.SelectContext(ctx, &segments, sqlGetSegmentsDueForSync, syncedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get segments due for sync: %w", err)
+	}
+	return segments, nil
+}
+
+const sqlLockSegmentForSync = `
+SELECT id
+FROM segments
+WHERE id = $1
+FOR UPDATE
+`
+
+const sqlGetSegmentSyncMembers = `
+SELECT user_id, email
+FROM segment_sync_members
+WHERE segment_id = $1
+`
+
+const sqlInsertSegmentSyncMembers = `
+INSERT INTO segment_sync_members (segment_id, user_id, email)
+SELECT $1, d.user_id, d.email
+FROM unnest($2::uuid[], $3::text[]) AS d(user_id, email)
+ON CONFLICT (segment_id, user_id) DO NOTHING
+`
+
+const sqlDeleteSegmentSyncMembers = `
+DELETE FROM segment_sync_members
+WHERE segment_id = $1 AND user_id = ANY($2::uuid[])
+`
+
+const sqlMarkSegmentSynced = `
+UPDATE segments
+SET synced_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+// SegmentSyncPublisher publishes the members added to and removed from a segment since its
+// previous sync
+type SegmentSyncPublisher func(added, removed []SegmentSyncMember) error
+
+// SyncSegmentMembers replaces the membership last pushed downstream for a segment with its
+// current users and returns the members that were added and removed since the previous sync.
+// The changes are published before the new membership is committed, and nothing is committed
+// when publishing fails, so the next sync publishes them again instead of losing them.
+func (s *Store) SyncSegmentMembers(ctx context.Context, segmentID uuid.UUID, users []WaitlistUser, publish SegmentSyncPublisher) ([]SegmentSyncMember, []SegmentSyncMember, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the segment so concurrent syncs of it do not publish the same changes twice
+	var lockedID uuid.UUID
+	if err := tx.GetContext(ctx, &lockedID, sqlLockSegmentForSync, segmentID); err != nil {
+		return nil, nil, fmt.Errorf("failed to lock segment for sync: %w", err)
+	}
+
+	var previous []SegmentSyncMember
+	if err := tx.SelectContext(ctx, &previous, sqlGetSegmentSyncMembers, segmentID); err != nil {
+		return nil, nil, fmt.Errorf("failed to get segment sync members: %w", err)
+	}
+
+	added, removed := diffSegmentSyncMembers(previous, users)
+
+	if len(added) > 0 {
+		ids := make([]string, len(added))
+		emails := make([]string, len(added))
+		for i, member := range added {
+			ids[i] = member.UserID.String()
+			emails[i] = member.Email
+		}
+		if _, err := tx.ExecContext(ctx, sqlInsertSegmentSyncMembers, segmentID, pq.Array(ids), pq.Array(emails)); err != nil {
+			return nil, nil, fmt.Errorf("failed to insert segment sync members: %w", err)
+		}
+	}
+
+	if len(removed) > 0 {
+		ids := make([]string, len(removed))
+		for i, member := range removed {
+			ids[i] = member.UserID.String()
+		}
+		if _, err := tx.ExecContext(ctx, sqlDeleteSegmentSyncMembers, segmentID, pq.Array(ids)); err != nil {
+			return nil, nil, fmt.Errorf("failed to delete segment sync members: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, sqlMarkSegmentSynced, segmentID); err != nil {
+		return nil, nil, fmt.Errorf("failed to mark segment synced: %w", err)
+	}
+
+	if len(added) > 0 || len(removed) > 0 {
+		if err := publish(added, removed); err != nil {
+			return nil, nil, fmt.Errorf("failed to publish segment sync: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return added, removed, nil
+}
+
+// diffSegmentSyncMembers returns the users that are not in the previously synced membership
+// and the previously synced members that are no longer users of the segment
+func diffSegmentSyncMembers(previous []SegmentSyncMember, users []WaitlistUser) ([]SegmentSyncMember, []SegmentSyncMember) {
+	previousIDs := make(map[uuid.UUID]bool, len(previous))
+	for _, member := range previous {
+		previousIDs[member.UserID] = true
+	}
+
+	current := make(map[uuid.UUID]bool, len(users))
+	var added []SegmentSyncMember
+	for _, user := range users {
+		if current[user.ID] {
+			continue
+		}
+		current[user.ID] = true
+		if !previousIDs[user.ID] {
+			added = append(added, SegmentSyncMember{UserID: user.ID, Email: strings.ToLower(user.Email)})
+		}
+	}
+
+	var removed []SegmentSyncMember
+	for _, member := range previous {
+		if !current[member.UserID] {
+			removed = append(removed, member)
+		}
+	}
+
+	return added, removed
+}
+
+const sqlClearSegmentSync = `
+WITH cleared AS (
+    DELETE FROM segment_sync_members
+    WHERE segment_id = $1
+)
+UPDATE segments
+SET synced_at = NULL
+WHERE id = $1
+`
+
+// ClearSegmentSync forgets the membership last pushed downstream for a segment, so the next
+// sync after re-enabling publishes every member as added
+func (s *Store) ClearSegmentSync(ctx context.Context, segmentID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, sqlClearSegmentSync, segmentID)
+	if err != nil {
+		return fmt.Errorf("failed to clear segment sync: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffSegmentSyncMembers(t *testing.T) {
+	t.Parallel()
+
+	stays := uuid.New()
+	leaves := uuid.New()
+	joins := uuid.New()
+
+	previous := []SegmentSyncMember{
+		{UserID: stays, Email: "stays@example.com"},
+		{UserID: leaves, Email: "leaves@example.com"},
+	}
+	users := []WaitlistUser{
+		{ID: stays, Email: "stays@example.com"},
+		{ID: joins, Email: "Joins@Example.com"},
+		{ID: joins, Email: "Joins@Example.com"},
+	}
+
+	added, removed := diffSegmentSyncMembers(previous, users)
+
+	if len(added) != 1 || added[0].UserID != joins || added[0].Email != "joins@example.com" {
+		t.Errorf("expected only the joining user to be added with a lowercased email, got %+v", added)
+	}
+	if len(removed) != 1 || removed[0].UserID != leaves || removed[0].Email != "leaves@example.com" {
+		t.Errorf("expected only the leaving user to be removed, got %+v", removed)
+	}
+
+	added, removed = diffSegmentSyncMembers(nil, users)
+	if len(added) != 2 || len(removed) != 0 {
+		t.Errorf("expected the first sync to add every user, got %d added and %d removed", len(added), len(removed))
+	}
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// sendRateRecoveryPerSecond is how much of the configured rate a throttled bucket recovers per second
+	sendRateRecoveryPerSecond = 0.02
+	// minSendRateFactor is the lowest share of the configured rate a throttled bucket slows down to
+	minSendRateFactor = 0.1
+	// sendRateBaseBackoff is the pause after the first 429; it doubles with every consecutive 429
+	sendRateBaseBackoff = time.Second
+	// sendRateMaxBackoff caps the pause after consecutive 429s
+	sendRateMaxBackoff = time.Minute
+)
+
+// sendRateBucket is the state of a shared token bucket
+type sendRateBucket struct {
+	Tokens               float64    `db:"tokens"`
+	RateFactor           float64    `db:"rate_factor"`
+	ConsecutiveThrottles int        `db:"consecutive_throttles"`
+	BackoffUntil         *time.Time `db:"backoff_until"`
+	UpdatedAt            time.Time  `db:"updated_at"`
+	Now                  time.Time  `db:"now"`
+}
+
+// refill adds the tokens earned since the bucket was last updated and recovers its rate
+func (b *sendRateBucket) refill(ratePerSecond float64, burst int, now time.Time) {
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	b.RateFactor = math.Min(1, b.RateFactor+elapsed*sendRateRecoveryPerSecond)
+	if b.RateFactor >= 1 {
+		b.ConsecutiveThrottles = 0
+	}
+
+	b.Tokens = math.Min(float64(burst), b.Tokens+elapsed*ratePerSecond*b.RateFactor)
+	b.UpdatedAt = now
+}
+
+// take removes a token from the bucket. It returns how long to wait before a token is
+// available, or 0 if one was taken.
+func (b *sendRateBucket) take(ratePerSecond float64, burst int, now time.Time) time.Duration {
+	b.refill(ratePerSecond, burst, now)
+
+	if b.BackoffUntil != nil && now.Before(*b.BackoffUntil) {
+		return b.BackoffUntil.Sub(now)
+	}
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return 0
+	}
+
+	return time.Duration((1 - b.Tokens) / (ratePerSecond * b.RateFactor) * float64(time.Second))
+}
+
+// throttle empties the bucket, halves its rate and pauses it for retryAfter or an exponential
+// backoff, whichever is longer
+func (b *sendRateBucket) throttle(ratePerSecond float64, burst int, retryAfter time.Duration, now time.Time) {
+	b.refill(ratePerSecond, burst, now)
+
+	b.ConsecutiveThrottles++
+	b.RateFactor = math.Max(minSendRateFactor, b.RateFactor/2)
+	b.Tokens = 0
+
+	backoff := sendRateMaxBackoff
+	if b.ConsecutiveThrottles <= 6 {
+		backoff = min(sendRateMaxBackoff, sendRateBaseBackoff<<(b.ConsecutiveThrottles-1))
+	}
+	if retryAfter > backoff {
+		backoff = retryAfter
+	}
+
+	until := now.Add(backoff)
+	if b.BackoffUntil == nil || until.After(*b.BackoffUntil) {
+		b.BackoffUntil = &until
+	}
+}
+
+const sqlEnsureSendRateBucket = `
+INSERT INTO send_rate_buckets (bucket_key, tokens)
+VALUES ($1, $2)
+ON CONFLICT (bucket_key) DO NOTHING
+`
+
+const sqlLockSendRateBucket = `
+SELECT tokens, rate_factor, consecutive_throttles, backoff_until, updated_at, CURRENT_TIMESTAMP AS now
+FROM send_rate_buckets
+WHERE bucket_key = $1
+FOR UPDATE
+`
+
+const sqlUpdateSendRateBucket = `
+UPDATE send_rate_buckets
+SET tokens = $2,
+    rate_factor = $3,
+    consecutive_throttles = $4,
+    backoff_until = $5,
+    updated_at = $6
+WHERE bucket_key = $1
+`
+
+// updateSendRateBucket locks a bucket, creating it full if it doesn't exist, applies fn and saves the result
+func (s *Store) updateSendRateBucket(ctx context.Context, bucketKey string, burst int, fn func(b *sendRateBucket)) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, sqlEnsureSendRateBucket, bucketKey, burst)
+	if err != nil {
+		return fmt.Errorf("failed to create send rate bucket: %w", err)
+	}
+
+	var bucket sendRateBucket
+	err = tx.GetContext(ctx, &bucket, sqlLockSendRateBucket, bucketKey)
+	if err != nil {
+		return fmt.Errorf("failed to lock send rate bucket: %w", err)
+	}
+
+	fn(&bucket)
+
+	_, err = tx.ExecContext(ctx, sqlUpdateSendRateBucket,
+		bucketKey,
+		bucket.Tokens,
+		bucket.RateFactor,
+		bucket.ConsecutiveThrottles,
+		bucket.BackoffUntil,
+		bucket.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update send rate bucket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// TakeSendRateToken takes a token from a shared send rate bucket that refills at ratePerSecond up
+// to burst tokens. It returns 0 if a token was taken, or how long to wait before trying again.
+func (s *Store) TakeSendRateToken(ctx context.Context, bucketKey string, ratePerSecond float64, burst int) (time.Duration, error) {
+	if ratePerSecond <= 0 || burst < 1 {
+		return 0, fmt.Errorf("invalid send rate %v with burst %d", ratePerSecond, burst)
+	}
+
+	var wait time.Duration
+	err := s.updateSendRateBucket(ctx, bucketKey, burst, func(b *sendRateBucket) {
+		wait = b.take(ratePerSecond, burst, b.Now)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return wait, nil
+}
+
+// ThrottleSendRateBucket backs off a shared send rate bucket after the provider rate limited a send.
+// retryAfter is the provider's Retry-After, or 0 when it didn't send one.
+func (s *Store) ThrottleSendRateBucket(ctx context.Context, bucketKey string, ratePerSecond float64, burst int, retryAfter time.Duration) error {
+	if ratePerSecond <= 0 || burst < 1 {
+		return fmt.Errorf("invalid send rate %v with burst %d", ratePerSecond, burst)
+	}
+
+	return s.updateSendRateBucket(ctx, bucketKey, burst, func(b *sendRateBucket) {
+		b.throttle(ratePerSecond, burst, retryAfter, b.Now)
+	})
+}
+
+// BlastSendRateBucketKey returns the key of the bucket that throttles a blast to its own send rate
+func BlastSendRateBucketKey(blastID uuid.UUID) string {
+	return "blast:" + blastID.String()
+}
+
+const sqlDeleteSendRateBucket = `
+DELETE FROM send_rate_buckets
+WHERE bucket_key = $1
+`
+
+// DeleteBlastSendRateBucket removes a blast's own send rate bucket once the blast stopped sending.
+// Shared account and provider buckets are kept.
+func (s *Store) DeleteBlastSendRateBucket(ctx context.Context, blastID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, sqlDeleteSendRateBucket, BlastSendRateBucketKey(blastID))
+	if err != nil {
+		return fmt.Errorf("failed to delete blast send rate bucket: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSendRateBucket_Take(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	bucket := sendRateBucket{Tokens: 2, RateFactor: 1, UpdatedAt: now}
+
+	// The burst is available immediately
+	for i := 0; i < 2; i++ {
+		if wait := bucket.take(10, 2, now); wait != 0 {
+			t.Fatalf("take %d: expected a token, got wait %v", i, wait)
+		}
+	}
+
+	// At 10 per second the next token arrives 100ms later
+	if wait := bucket.take(10, 2, now); wait != 100*time.Millisecond {
+		t.Errorf("expected wait of 100ms, got %v", wait)
+	}
+	if wait := bucket.take(10, 2, now.Add(100*time.Millisecond)); wait != 0 {
+		t.Errorf("expected a token after 100ms, got wait %v", wait)
+	}
+
+	// Tokens never exceed the burst
+	bucket.take(10, 2, now.Add(time.Hour))
+	if bucket.Tokens != 1 {
+		t.Errorf("expected 1 token left after refilling to the burst, got %v", bucket.Tokens)
+	}
+}
+
+func TestSendRateBucket_Throttle(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	bucket := sendRateBucket{Tokens: 5, RateFactor: 1, UpdatedAt: now}
+
+	bucket.throttle(10, 5, 0, now)
+	if bucket.RateFactor != 0.5 {
+		t.Errorf("expected rate factor 0.5, got %v", bucket.RateFactor)
+	}
+	if wait := bucket.take(10, 5, now); wait != time.Second {
+		t.Errorf("expected 1s backoff after the first 429, got %v", wait)
+	}
+
+	// Consecutive 429s double the backoff unless Retry-After asks for longer
+	bucket.throttle(10, 5, 0, now)
+	if wait := bucket.take(10, 5, now); wait != 2*time.Second {
+		t.Errorf("expected 2s backoff after the second 429, got %v", wait)
+	}
+	bucket.throttle(10, 5, 30*time.Second, now)
+	if wait := bucket.take(10, 5, now); wait != 30*time.Second {
+		t.Errorf("expected Retry-After of 30s to be honored, got %v", wait)
+	}
+
+	// After the backoff tokens refill at the reduced rate
+	after := now.Add(30 * time.Second)
+	bucket.take(10, 5, after)
+	if bucket.RateFactor >= 1 {
+		t.Errorf("expected rate to still be recovering, got factor %v", bucket.RateFactor)
+	}
+
+	// The rate fully recovers over time and the backoff resets
+	bucket.take(10, 5, after.Add(time.Minute))
+	if bucket.RateFactor != 1 || bucket.ConsecutiveThrottles != 0 {
+		t.Errorf("expected rate to recover, got factor %v with %d throttles", bucket.RateFactor, bucket.ConsecutiveThrottles)
+	}
+}
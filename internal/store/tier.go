@@ -127,6 +127,8 @@ func (s *Store) GetTierInfoByPriceID(ctx context.Context, priceID uuid.UUID) (Ti
 		"campaigns":    true,
 		"leads":        true,
 		"team_members": true,
+
+		"webhook_log_retention_days": true,
 	}
 
 	for _, f := range featuresWithLimits {
@@ -12,19 +12,23 @@ import (
 
 // CreateWebhookParams represents parameters for creating a webhook
 type CreateWebhookParams struct {
-	AccountID    uuid.UUID
-	CampaignID   *uuid.UUID
-	URL          string
-	Secret       string
-	Events       []string
-	RetryEnabled bool
-	MaxRetries   int
+	AccountID       uuid.UUID
+	CampaignID      *uuid.UUID
+	URL             string
+	Secret          string
+	Events          []string
+	Filters         WebhookFilters
+	RetryEnabled    bool
+	MaxRetries      int
+	SignatureFormat WebhookSignatureFormat
+	OrderedDelivery bool
+	PayloadVersion  int
 }
 
 const sqlCreateWebhook = `
-INSERT INTO webhooks (account_id, campaign_id, url, secret, events, retry_enabled, max_retries)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
-RETURNING id, account_id, campaign_id, url, secret, events, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, created_at, updated_at, deleted_at
+INSERT INTO webhooks (account_id, campaign_id, url, secret, events, retry_enabled, max_retries, signature_format, filters, ordered_delivery, payload_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, account_id, campaign_id, url, secret, events, filters, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, consecutive_failures, failing_since, circuit_opened_at, circuit_next_probe_at, disabled_at, disabled_reason, previous_secret, previous_secret_expires_at, secret_rotated_at, signature_format, ordered_delivery, payload_version, created_at, updated_at, deleted_at
 `
 
 // CreateWebhook creates a new webhook
@@ -37,7 +41,11 @@ func (s *Store) CreateWebhook(ctx context.Context, params CreateWebhookParams) (
 		params.Secret,
 		StringArray(params.Events),
 		params.RetryEnabled,
-		params.MaxRetries)
+		params.MaxRetries,
+		params.SignatureFormat,
+		params.Filters,
+		params.OrderedDelivery,
+		params.PayloadVersion)
 	if err != nil {
 		return Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
 	}
@@ -45,7 +53,7 @@ func (s *Store) CreateWebhook(ctx context.Context, params CreateWebhookParams) (
 }
 
 const sqlGetWebhookByID = `
-SELECT id, account_id, campaign_id, url, secret, events, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, created_at, updated_at, deleted_at
+SELECT id, account_id, campaign_id, url, secret, events, filters, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, consecutive_failures, failing_since, circuit_opened_at, circuit_next_probe_at, disabled_at, disabled_reason, previous_secret, previous_secret_expires_at, secret_rotated_at, signature_format, ordered_delivery, payload_version, created_at, updated_at, deleted_at
 FROM webhooks
 WHERE id = $1 AND deleted_at IS NULL
 `
@@ -64,7 +72,7 @@ func (s *Store) GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (Webhoo
 }
 
 const sqlGetWebhooksByAccount = `
-SELECT id, account_id, campaign_id, url, secret, events, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, created_at, updated_at, deleted_at
+SELECT id, account_id, campaign_id, url, secret, events, filters, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, consecutive_failures, failing_since, circuit_opened_at, circuit_next_probe_at, disabled_at, disabled_reason, previous_secret, previous_secret_expires_at, secret_rotated_at, signature_format, ordered_delivery, payload_version, created_at, updated_at, deleted_at
 FROM webhooks
 WHERE account_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -81,7 +89,7 @@ func (s *Store) GetWebhooksByAccount(ctx context.Context, accountID uuid.UUID) (
 }
 
 const sqlGetWebhooksByCampaign = `
-SELECT id, account_id, campaign_id, url, secret, events, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, created_at, updated_at, deleted_at
+SELECT id, account_id, campaign_id, url, secret, events, filters, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, consecutive_failures, failing_since, circuit_opened_at, circuit_next_probe_at, disabled_at, disabled_reason, previous_secret, previous_secret_expires_at, secret_rotated_at, signature_format, ordered_delivery, payload_version, created_at, updated_at, deleted_at
 FROM webhooks
 WHERE campaign_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
@@ -101,21 +109,38 @@ const sqlUpdateWebhook = `
 UPDATE webhooks
 SET url = COALESCE($2, url),
     events = COALESCE($3, events),
-    status = COALESCE($4, status),
+    -- Failed webhooks are re-enabled by EnableWebhook after a test ping, never by an update
+    status = CASE WHEN $4 = 'active' AND status = 'failed' THEN status ELSE COALESCE($4, status) END,
     retry_enabled = COALESCE($5, retry_enabled),
     max_retries = COALESCE($6, max_retries),
+    signature_format = COALESCE($7, signature_format),
+    filters = COALESCE($8, filters),
+    ordered_delivery = COALESCE($9, ordered_delivery),
+    payload_version = COALESCE($10, payload_version),
+    -- Resuming a paused webhook starts its health tracking over
+    consecutive_failures = CASE WHEN $4 = 'active' AND status = 'paused' THEN 0 ELSE consecutive_failures END,
+    failing_since = CASE WHEN $4 = 'active' AND status = 'paused' THEN NULL ELSE failing_since END,
+    circuit_opened_at = CASE WHEN $4 = 'active' AND status = 'paused' THEN NULL ELSE circuit_opened_at END,
+    circuit_next_probe_at = CASE WHEN $4 = 'active' AND status = 'paused' THEN NULL ELSE circuit_next_probe_at END,
+    disabled_at = CASE WHEN $4 = 'active' AND status = 'paused' THEN NULL ELSE disabled_at END,
+    disabled_reason = CASE WHEN $4 = 'active' AND status = 'paused' THEN NULL ELSE disabled_reason END,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1 AND deleted_at IS NULL
-RETURNING id, account_id, campaign_id, url, secret, events, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, created_at, updated_at, deleted_at
+RETURNING id, account_id, campaign_id, url, secret, events, filters, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, consecutive_failures, failing_since, circuit_opened_at, circuit_next_probe_at, disabled_at, disabled_reason, previous_secret, previous_secret_expires_at, secret_rotated_at, signature_format, ordered_delivery, payload_version, created_at, updated_at, deleted_at
 `
 
 // UpdateWebhookParams represents parameters for updating a webhook
 type UpdateWebhookParams struct {
-	URL          *string
-	Events       []string
-	Status       *string
-	RetryEnabled *bool
-	MaxRetries   *int
+	URL             *string
+	Events          []string
+	Status          *string
+	RetryEnabled    *bool
+	MaxRetries      *int
+	SignatureFormat *WebhookSignatureFormat
+	// Filters replaces the webhook's filters when not nil, an empty list removes them
+	Filters         WebhookFilters
+	OrderedDelivery *bool
+	PayloadVersion  *int
 }
 
 // UpdateWebhook updates a webhook
@@ -132,7 +157,11 @@ func (s *Store) UpdateWebhook(ctx context.Context, webhookID uuid.UUID, params U
 		eventsArray,
 		params.Status,
 		params.RetryEnabled,
-		params.MaxRetries)
+		params.MaxRetries,
+		params.SignatureFormat,
+		params.Filters,
+		params.OrderedDelivery,
+		params.PayloadVersion)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return Webhook{}, ErrNotFound
@@ -171,11 +200,15 @@ const sqlIncrementWebhookSent = `
 UPDATE webhooks
 SET total_sent = total_sent + 1,
     last_success_at = CURRENT_TIMESTAMP,
+    consecutive_failures = 0,
+    failing_since = NULL,
+    circuit_opened_at = NULL,
+    circuit_next_probe_at = NULL,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1
 `
 
-// IncrementWebhookSent increments the total sent counter
+// IncrementWebhookSent increments the total sent counter and closes the webhook's circuit
 func (s *Store) IncrementWebhookSent(ctx context.Context, webhookID uuid.UUID) error {
 	_, err := s.db.ExecContext(ctx, sqlIncrementWebhookSent, webhookID)
 	if err != nil {
@@ -205,26 +238,37 @@ func (s *Store) IncrementWebhookFailed(ctx context.Context, webhookID uuid.UUID)
 
 // CreateWebhookDeliveryParams represents parameters for creating a webhook delivery
 type CreateWebhookDeliveryParams struct {
-	WebhookID   uuid.UUID
-	EventType   string
-	Payload     JSONB
-	NextRetryAt *time.Time
+	WebhookID          uuid.UUID
+	EventID            *uuid.UUID
+	OriginalDeliveryID *uuid.UUID
+	TriggeredBy        WebhookDeliveryTrigger
+	EventType          string
+	Payload            JSONB
+	NextRetryAt        *time.Time
 }
 
 const sqlCreateWebhookDelivery = `
-INSERT INTO webhook_deliveries (webhook_id, event_type, payload, next_retry_at)
-VALUES ($1, $2, $3, $4)
-RETURNING id, webhook_id, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at
+INSERT INTO webhook_deliveries (webhook_id, event_type, payload, next_retry_at, event_id, original_delivery_id, triggered_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, webhook_id, event_id, original_delivery_id, triggered_by, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at, redacted_at
 `
 
 // CreateWebhookDelivery creates a new webhook delivery record
 func (s *Store) CreateWebhookDelivery(ctx context.Context, params CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	triggeredBy := params.TriggeredBy
+	if triggeredBy == "" {
+		triggeredBy = WebhookDeliveryTriggerEvent
+	}
+
 	var delivery WebhookDelivery
 	err := s.db.GetContext(ctx, &delivery, sqlCreateWebhookDelivery,
 		params.WebhookID,
 		params.EventType,
 		params.Payload,
-		params.NextRetryAt)
+		params.NextRetryAt,
+		params.EventID,
+		params.OriginalDeliveryID,
+		string(triggeredBy))
 	if err != nil {
 		return WebhookDelivery{}, fmt.Errorf("failed to create webhook delivery: %w", err)
 	}
@@ -232,7 +276,7 @@ func (s *Store) CreateWebhookDelivery(ctx context.Context, params CreateWebhookD
 }
 
 const sqlGetWebhookDeliveryByID = `
-SELECT id, webhook_id, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at
+SELECT id, webhook_id, event_id, original_delivery_id, triggered_by, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at, redacted_at
 FROM webhook_deliveries
 WHERE id = $1
 `
@@ -251,7 +295,7 @@ func (s *Store) GetWebhookDeliveryByID(ctx context.Context, deliveryID uuid.UUID
 }
 
 const sqlGetWebhookDeliveriesByWebhook = `
-SELECT id, webhook_id, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at
+SELECT id, webhook_id, event_id, original_delivery_id, triggered_by, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at, redacted_at
 FROM webhook_deliveries
 WHERE webhook_id = $1
 ORDER BY created_at DESC
@@ -314,14 +358,20 @@ func (s *Store) UpdateWebhookDeliveryStatus(ctx context.Context, deliveryID uuid
 }
 
 const sqlGetPendingWebhookDeliveries = `
-SELECT id, webhook_id, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at
+SELECT id, webhook_id, event_id, original_delivery_id, triggered_by, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at, redacted_at
 FROM webhook_deliveries
-WHERE status = 'failed' AND next_retry_at IS NOT NULL AND next_retry_at <= CURRENT_TIMESTAMP AND attempt_number < $2
-ORDER BY next_retry_at ASC
+WHERE attempt_number < $2
+  AND (
+    (status IN ('pending', 'failed') AND next_retry_at IS NOT NULL AND next_retry_at <= CURRENT_TIMESTAMP)
+    -- Queued deliveries never attempted, e.g. because the server restarted
+    OR (status = 'pending' AND next_retry_at IS NULL AND created_at <= CURRENT_TIMESTAMP - INTERVAL '15 minutes')
+  )
+ORDER BY COALESCE(next_retry_at, created_at) ASC
 LIMIT $1
 `
 
-// GetPendingWebhookDeliveries retrieves webhook deliveries ready for retry
+// GetPendingWebhookDeliveries retrieves webhook deliveries ready for retry, including queued
+// deliveries left unattempted for 15 minutes
 func (s *Store) GetPendingWebhookDeliveries(ctx context.Context, limit int, maxAttempt int) ([]WebhookDelivery,
 	error) {
 	var deliveries []WebhookDelivery
@@ -332,6 +382,31 @@ func (s *Store) GetPendingWebhookDeliveries(ctx context.Context, limit int, maxA
 	return deliveries, nil
 }
 
+const sqlGetOldestUnfinishedWebhookDelivery = `
+SELECT id, webhook_id, event_id, original_delivery_id, triggered_by, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at, redacted_at
+FROM webhook_deliveries
+WHERE webhook_id = $1
+  AND triggered_by = 'event'
+  AND attempt_number < $2
+  AND (status = 'pending' OR (status = 'failed' AND next_retry_at IS NOT NULL))
+ORDER BY created_at ASC, id ASC
+LIMIT 1
+`
+
+// GetOldestUnfinishedWebhookDelivery retrieves the oldest event delivery of a webhook that is
+// still queued or will be retried. Returns ErrNotFound if every event delivery succeeded or was dropped.
+func (s *Store) GetOldestUnfinishedWebhookDelivery(ctx context.Context, webhookID uuid.UUID, maxAttempt int) (WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := s.db.GetContext(ctx, &delivery, sqlGetOldestUnfinishedWebhookDelivery, webhookID, maxAttempt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WebhookDelivery{}, ErrNotFound
+		}
+		return WebhookDelivery{}, fmt.Errorf("failed to get oldest unfinished webhook delivery: %w", err)
+	}
+	return delivery, nil
+}
+
 const sqlIncrementDeliveryAttempt = `
 UPDATE webhook_deliveries
 SET attempt_number = attempt_number + 1,
@@ -347,3 +422,50 @@ func (s *Store) IncrementDeliveryAttempt(ctx context.Context, deliveryID uuid.UU
 	}
 	return nil
 }
+
+const sqlGetWebhookDeliveryChain = `
+SELECT id, webhook_id, event_id, original_delivery_id, triggered_by, event_type, payload, status, request_headers, response_status, response_body, response_headers, duration_ms, attempt_number, next_retry_at, error_message, created_at, delivered_at, redacted_at
+FROM webhook_deliveries
+WHERE id = $1 OR original_delivery_id = $1
+ORDER BY created_at ASC
+`
+
+// GetWebhookDeliveryChain retrieves a delivery and all of its redeliveries, oldest first.
+// rootDeliveryID must be the first delivery of the chain.
+func (s *Store) GetWebhookDeliveryChain(ctx context.Context, rootDeliveryID uuid.UUID) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := s.db.SelectContext(ctx, &deliveries, sqlGetWebhookDeliveryChain, rootDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery chain: %w", err)
+	}
+	return deliveries, nil
+}
+
+const sqlGetRetryableWebhookDeliveries = `
+SELECT d.id, d.webhook_id, d.event_id, d.original_delivery_id, d.triggered_by, d.event_type, d.payload, d.status, d.request_headers, d.response_status, d.response_body, d.response_headers, d.duration_ms, d.attempt_number, d.next_retry_at, d.error_message, d.created_at, d.delivered_at, d.redacted_at
+FROM webhook_deliveries d
+WHERE d.webhook_id = $1
+  AND d.status = 'failed'
+  AND (d.next_retry_at IS NULL OR d.attempt_number >= $4)
+  AND d.created_at >= $2
+  AND d.created_at < $3
+  AND d.redacted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM webhook_deliveries r
+      WHERE r.original_delivery_id = COALESCE(d.original_delivery_id, d.id)
+        AND r.created_at > d.created_at
+  )
+ORDER BY d.created_at ASC
+LIMIT $5
+`
+
+// GetRetryableWebhookDeliveries retrieves a webhook's deliveries created in [from, to) that failed
+// with no automatic retries left and are the latest attempt of their chain
+func (s *Store) GetRetryableWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, from, to time.Time, maxAttempt, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := s.db.SelectContext(ctx, &deliveries, sqlGetRetryableWebhookDeliveries, webhookID, from, to, maxAttempt, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retryable webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
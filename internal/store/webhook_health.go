@@ -0,0 +1,212 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const webhookColumns = `id, account_id, campaign_id, url, secret, events, filters, status, retry_enabled, max_retries, total_sent, total_failed, last_success_at, last_failure_at, consecutive_failures, failing_since, circuit_opened_at, circuit_next_probe_at, disabled_at, disabled_reason, previous_secret, previous_secret_expires_at, secret_rotated_at, signature_format, ordered_delivery, payload_version, created_at, updated_at, deleted_at`
+
+const sqlRecordWebhookAttemptFailure = `
+UPDATE webhooks
+SET consecutive_failures = consecutive_failures + 1,
+    failing_since = COALESCE(failing_since, CURRENT_TIMESTAMP),
+    circuit_opened_at = CASE WHEN consecutive_failures + 1 >= $2 THEN COALESCE(circuit_opened_at, CURRENT_TIMESTAMP) ELSE circuit_opened_at END,
+    circuit_next_probe_at = CASE WHEN consecutive_failures + 1 >= $2 THEN $3 ELSE circuit_next_probe_at END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING ` + webhookColumns
+
+// RecordWebhookAttemptFailure counts a failed delivery attempt against a webhook. Once the webhook
+// has failed threshold consecutive attempts its circuit is open and the next probe is allowed at
+// nextProbeAt.
+func (s *Store) RecordWebhookAttemptFailure(ctx context.Context, webhookID uuid.UUID, threshold int, nextProbeAt time.Time) (Webhook, error) {
+	var webhook Webhook
+	err := s.db.GetContext(ctx, &webhook, sqlRecordWebhookAttemptFailure, webhookID, threshold, nextProbeAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrNotFound
+		}
+		return Webhook{}, fmt.Errorf("failed to record webhook attempt failure: %w", err)
+	}
+	return webhook, nil
+}
+
+const sqlClaimWebhookProbe = `
+UPDATE webhooks
+SET circuit_next_probe_at = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND circuit_opened_at IS NOT NULL
+  AND circuit_next_probe_at <= CURRENT_TIMESTAMP
+`
+
+// ClaimWebhookProbe claims the due probe attempt of a webhook with an open circuit and moves the
+// next probe to nextProbeAt. It returns false when the probe is not due or was claimed by another
+// delivery.
+func (s *Store) ClaimWebhookProbe(ctx context.Context, webhookID uuid.UUID, nextProbeAt time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx, sqlClaimWebhookProbe, webhookID, nextProbeAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim webhook probe: %w", err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rows == 1, nil
+}
+
+const sqlDeferWebhookDelivery = `
+UPDATE webhook_deliveries
+SET status = 'failed',
+    error_message = $3,
+    next_retry_at = $2
+WHERE id = $1
+`
+
+// DeferWebhookDelivery marks a delivery that was not attempted as failed and schedules it for
+// nextRetryAt without counting an attempt. A nil nextRetryAt drops the delivery.
+func (s *Store) DeferWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, nextRetryAt *time.Time, reason string) error {
+	_, err := s.db.ExecContext(ctx, sqlDeferWebhookDelivery, deliveryID, nextRetryAt, reason)
+	if err != nil {
+		return fmt.Errorf("failed to defer webhook delivery: %w", err)
+	}
+	return nil
+}
+
+const sqlGetWebhooksFailingSince = `
+SELECT ` + webhookColumns + `
+FROM webhooks
+WHERE status = 'active'
+  AND failing_since IS NOT NULL
+  AND failing_since <= $1
+  AND deleted_at IS NULL
+ORDER BY failing_since ASC
+LIMIT $2
+`
+
+// GetWebhooksFailingSince retrieves active webhooks that have failed every attempt since before
+// failingBefore
+func (s *Store) GetWebhooksFailingSince(ctx context.Context, failingBefore time.Time, limit int) ([]Webhook, error) {
+	var webhooks []Webhook
+	err := s.db.SelectContext(ctx, &webhooks, sqlGetWebhooksFailingSince, failingBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get failing webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+const sqlDisableWebhook = `
+UPDATE webhooks
+SET status = 'failed',
+    disabled_at = CURRENT_TIMESTAMP,
+    disabled_reason = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'active' AND deleted_at IS NULL
+RETURNING ` + webhookColumns
+
+// DisableWebhook moves an active webhook to the failed status.
+// It returns ErrNotFound when the webhook is not active.
+func (s *Store) DisableWebhook(ctx context.Context, webhookID uuid.UUID, reason string) (Webhook, error) {
+	var webhook Webhook
+	err := s.db.GetContext(ctx, &webhook, sqlDisableWebhook, webhookID, reason)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrNotFound
+		}
+		return Webhook{}, fmt.Errorf("failed to disable webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+const sqlEnableWebhook = `
+UPDATE webhooks
+SET status = 'active',
+    consecutive_failures = 0,
+    failing_since = NULL,
+    circuit_opened_at = NULL,
+    circuit_next_probe_at = NULL,
+    disabled_at = NULL,
+    disabled_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING ` + webhookColumns
+
+// EnableWebhook moves a webhook back to the active status with a closed circuit
+func (s *Store) EnableWebhook(ctx context.Context, webhookID uuid.UUID) (Webhook, error) {
+	var webhook Webhook
+	err := s.db.GetContext(ctx, &webhook, sqlEnableWebhook, webhookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrNotFound
+		}
+		return Webhook{}, fmt.Errorf("failed to enable webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+const sqlRotateWebhookSecret = `
+UPDATE webhooks
+SET previous_secret = secret,
+    previous_secret_expires_at = $3,
+    secret = $2,
+    secret_rotated_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING ` + webhookColumns
+
+// RotateWebhookSecretParams represents parameters for rotating a webhook's signing secret
+type RotateWebhookSecretParams struct {
+	WebhookID               uuid.UUID
+	Secret                  string
+	PreviousSecretExpiresAt time.Time
+	AuditLog                CreateAuditLogParams
+}
+
+// RotateWebhookSecret replaces a webhook's signing secret, keeping the replaced secret valid until
+// PreviousSecretExpiresAt, and records the rotation in the audit log in the same transaction
+func (s *Store) RotateWebhookSecret(ctx context.Context, params RotateWebhookSecretParams) (Webhook, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var webhook Webhook
+	err = tx.GetContext(ctx, &webhook, sqlRotateWebhookSecret, params.WebhookID, params.Secret, params.PreviousSecretExpiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Webhook{}, ErrNotFound
+		}
+		return Webhook{}, fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	audit := params.AuditLog
+	_, err = tx.ExecContext(ctx, sqlCreateAuditLog,
+		audit.AccountID,
+		audit.ActorUserID,
+		audit.ActorType,
+		audit.ActorIdentifier,
+		audit.Action,
+		audit.ResourceType,
+		audit.ResourceID,
+		audit.Changes,
+		audit.IPAddress,
+		audit.UserAgent)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to create audit log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Webhook{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return webhook, nil
+}
@@ -0,0 +1,241 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const sqlGetRetentionAccountIDs = `
+SELECT account_id FROM webhooks
+UNION
+SELECT DISTINCT account_id FROM events
+`
+
+// GetRetentionAccountIDs retrieves the accounts with webhooks, including deleted webhooks whose
+// deliveries are still stored, or with recorded events
+func (s *Store) GetRetentionAccountIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var accountIDs []uuid.UUID
+	err := s.db.SelectContext(ctx, &accountIDs, sqlGetRetentionAccountIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook account ids: %w", err)
+	}
+	return accountIDs, nil
+}
+
+// sqlSelectWebhookDailyStats groups deliveries by webhook and UTC day; callers add the WHERE clause
+const sqlSelectWebhookDailyStats = `
+SELECT webhook_id,
+       (created_at AT TIME ZONE 'UTC')::date AS day,
+       COUNT(*) AS deliveries,
+       COUNT(*) FILTER (WHERE status = 'success') AS succeeded,
+       COUNT(*) FILTER (WHERE status = 'failed') AS failed,
+       COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+       AVG(duration_ms)::INTEGER AS avg_duration_ms,
+       MAX(duration_ms) AS max_duration_ms,
+       CURRENT_TIMESTAMP AS updated_at
+FROM webhook_deliveries
+`
+
+const sqlAggregateWebhookDeliveryStats = `
+INSERT INTO webhook_delivery_daily_stats (webhook_id, day, deliveries, succeeded, failed, pending, avg_duration_ms, max_duration_ms, updated_at)
+` + sqlSelectWebhookDailyStats + `
+WHERE created_at >= $1
+GROUP BY webhook_id, day
+ON CONFLICT (webhook_id, day) DO UPDATE
+SET deliveries = EXCLUDED.deliveries,
+    succeeded = EXCLUDED.succeeded,
+    failed = EXCLUDED.failed,
+    pending = EXCLUDED.pending,
+    avg_duration_ms = EXCLUDED.avg_duration_ms,
+    max_duration_ms = EXCLUDED.max_duration_ms,
+    updated_at = EXCLUDED.updated_at
+`
+
+// AggregateWebhookDeliveryStats recomputes the daily stats of every webhook from the deliveries
+// created since the given time, which must be the start of a UTC day. The days recomputed must
+// still have all of their deliveries.
+func (s *Store) AggregateWebhookDeliveryStats(ctx context.Context, since time.Time) error {
+	_, err := s.db.ExecContext(ctx, sqlAggregateWebhookDeliveryStats, since)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate webhook delivery stats: %w", err)
+	}
+	return nil
+}
+
+const sqlAggregatePurgedWebhookDeliveryStats = `
+INSERT INTO webhook_delivery_daily_stats (webhook_id, day, deliveries, succeeded, failed, pending, avg_duration_ms, max_duration_ms, updated_at)
+` + sqlSelectWebhookDailyStats + `
+WHERE webhook_id IN (SELECT id FROM webhooks WHERE account_id = $1)
+  AND created_at < $2
+GROUP BY webhook_id, day
+ON CONFLICT (webhook_id, day) DO NOTHING
+`
+
+const sqlPurgeWebhookDeliveries = `
+DELETE FROM webhook_deliveries
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries d
+    WHERE d.webhook_id IN (SELECT id FROM webhooks WHERE account_id = $1)
+      AND d.created_at < $2
+      AND NOT EXISTS (
+          SELECT 1 FROM webhook_deliveries r
+          WHERE r.original_delivery_id = d.id
+            AND r.created_at >= $2
+      )
+    LIMIT $3
+)
+`
+
+// AggregatePurgedWebhookDeliveryStats rolls an account's deliveries created before the given time,
+// which must be the start of a UTC day, up into the daily stats ahead of purging them. Days already
+// aggregated keep their stats, so a purge interrupted halfway does not undercount.
+func (s *Store) AggregatePurgedWebhookDeliveryStats(ctx context.Context, accountID uuid.UUID, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, sqlAggregatePurgedWebhookDeliveryStats, accountID, before)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate purged webhook delivery stats: %w", err)
+	}
+	return nil
+}
+
+// PurgeWebhookDeliveries deletes up to limit of an account's deliveries created before the given
+// time and returns the number deleted. Their days must have been aggregated with
+// AggregatePurgedWebhookDeliveryStats first. Deliveries redelivered after the given time are kept
+// with their attempt chain.
+func (s *Store) PurgeWebhookDeliveries(ctx context.Context, accountID uuid.UUID, before time.Time, limit int) (int64, error) {
+	result, err := s.db.ExecContext(ctx, sqlPurgeWebhookDeliveries, accountID, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge webhook deliveries: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get purged webhook deliveries: %w", err)
+	}
+
+	return purged, nil
+}
+
+const sqlGetWebhookDeliveriesToRedact = `
+SELECT id, webhook_id, event_type, payload, status, created_at
+FROM webhook_deliveries
+WHERE redacted_at IS NULL
+  AND created_at < $1
+  AND (status = 'success' OR (status = 'failed' AND next_retry_at IS NULL))
+ORDER BY created_at ASC
+LIMIT $2
+`
+
+// GetWebhookDeliveriesToRedact retrieves up to limit deliveries created before the given time whose
+// payload has not been redacted and that will not be retried automatically, oldest first. Only the
+// identifying columns and the payload are loaded.
+func (s *Store) GetWebhookDeliveriesToRedact(ctx context.Context, before time.Time, limit int) ([]WebhookDelivery, error) {
+	var deliveries []WebhookDelivery
+	err := s.db.SelectContext(ctx, &deliveries, sqlGetWebhookDeliveriesToRedact, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries to redact: %w", err)
+	}
+	return deliveries, nil
+}
+
+const sqlRedactWebhookDelivery = `
+UPDATE webhook_deliveries
+SET payload = $2,
+    redacted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+// RedactWebhookDelivery replaces a delivery's payload with its redacted payload
+func (s *Store) RedactWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, payload JSONB) error {
+	_, err := s.db.ExecContext(ctx, sqlRedactWebhookDelivery, deliveryID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to redact webhook delivery: %w", err)
+	}
+	return nil
+}
+
+const sqlGetWebhookDailyStats = `
+SELECT webhook_id, day, deliveries, succeeded, failed, pending, avg_duration_ms, max_duration_ms, updated_at
+FROM webhook_delivery_daily_stats
+WHERE webhook_id = $1
+  AND day >= $2
+  AND day <= $3
+ORDER BY day ASC
+`
+
+// GetWebhookDailyStats retrieves a webhook's daily delivery stats for the UTC days from and to,
+// inclusive, oldest first
+func (s *Store) GetWebhookDailyStats(ctx context.Context, webhookID uuid.UUID, from, to time.Time) ([]WebhookDailyStats, error) {
+	var stats []WebhookDailyStats
+	err := s.db.SelectContext(ctx, &stats, sqlGetWebhookDailyStats, webhookID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook daily stats: %w", err)
+	}
+	return stats, nil
+}
+
+const sqlPurgeEvents = `
+DELETE FROM events
+WHERE id IN (
+    SELECT id
+    FROM events
+    WHERE account_id = $1
+      AND occurred_at < $2
+    LIMIT $3
+)
+`
+
+// PurgeEvents deletes up to limit of an account's events that occurred before the given time and
+// returns the number deleted
+func (s *Store) PurgeEvents(ctx context.Context, accountID uuid.UUID, before time.Time, limit int) (int64, error) {
+	result, err := s.db.ExecContext(ctx, sqlPurgeEvents, accountID, before, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge events: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get purged events: %w", err)
+	}
+
+	return purged, nil
+}
+
+const sqlGetEventsToRedact = `
+SELECT ` + eventColumns + `
+FROM events
+WHERE redacted_at IS NULL
+  AND occurred_at < $1
+ORDER BY occurred_at ASC
+LIMIT $2
+`
+
+// GetEventsToRedact retrieves up to limit events that occurred before the given time whose data has
+// not been redacted, oldest first
+func (s *Store) GetEventsToRedact(ctx context.Context, before time.Time, limit int) ([]Event, error) {
+	var events []Event
+	err := s.db.SelectContext(ctx, &events, sqlGetEventsToRedact, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events to redact: %w", err)
+	}
+	return events, nil
+}
+
+const sqlRedactEvent = `
+UPDATE events
+SET data = $2,
+    redacted_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+// RedactEvent replaces an event's data with its redacted data. Events are otherwise append-only.
+func (s *Store) RedactEvent(ctx context.Context, eventID uuid.UUID, data JSONB) error {
+	_, err := s.db.ExecContext(ctx, sqlRedactEvent, eventID, data)
+	if err != nil {
+		return fmt.Errorf("failed to redact event: %w", err)
+	}
+	return nil
+}
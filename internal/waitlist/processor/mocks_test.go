@@ -11,6 +11,7 @@ package processor
 
 import (
 	store "base-server/internal/store"
+	events "base-server/internal/webhooks/events"
 	context "context"
 	reflect "reflect"
 
@@ -339,6 +340,20 @@ func (mr *MockWaitlistStoreMockRecorder) ListWaitlistUsersWithExtendedFilters(ct
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWaitlistUsersWithExtendedFilters", reflect.TypeOf((*MockWaitlistStore)(nil).ListWaitlistUsersWithExtendedFilters), ctx, params)
 }
 
+// RecordPositionImprovements mocks base method.
+func (m *MockWaitlistStore) RecordPositionImprovements(ctx context.Context, campaignID uuid.UUID, userIDs []uuid.UUID, previousPositions []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPositionImprovements", ctx, campaignID, userIDs, previousPositions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPositionImprovements indicates an expected call of RecordPositionImprovements.
+func (mr *MockWaitlistStoreMockRecorder) RecordPositionImprovements(ctx, campaignID, userIDs, previousPositions any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPositionImprovements", reflect.TypeOf((*MockWaitlistStore)(nil).RecordPositionImprovements), ctx, campaignID, userIDs, previousPositions)
+}
+
 // SearchWaitlistUsers mocks base method.
 func (m *MockWaitlistStore) SearchWaitlistUsers(ctx context.Context, params store.SearchWaitlistUsersParams) ([]store.WaitlistUser, error) {
 	m.ctrl.T.Helper()
@@ -422,27 +437,27 @@ func (m *MockEventDispatcher) EXPECT() *MockEventDispatcherMockRecorder {
 }
 
 // DispatchUserCreated mocks base method.
-func (m *MockEventDispatcher) DispatchUserCreated(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]any) {
+func (m *MockEventDispatcher) DispatchUserCreated(ctx context.Context, accountID, campaignID uuid.UUID, user events.UserPayload) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "DispatchUserCreated", ctx, accountID, campaignID, userData)
+	m.ctrl.Call(m, "DispatchUserCreated", ctx, accountID, campaignID, user)
 }
 
 // DispatchUserCreated indicates an expected call of DispatchUserCreated.
-func (mr *MockEventDispatcherMockRecorder) DispatchUserCreated(ctx, accountID, campaignID, userData any) *gomock.Call {
+func (mr *MockEventDispatcherMockRecorder) DispatchUserCreated(ctx, accountID, campaignID, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchUserCreated", reflect.TypeOf((*MockEventDispatcher)(nil).DispatchUserCreated), ctx, accountID, campaignID, userData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchUserCreated", reflect.TypeOf((*MockEventDispatcher)(nil).DispatchUserCreated), ctx, accountID, campaignID, user)
 }
 
 // DispatchUserVerified mocks base method.
-func (m *MockEventDispatcher) DispatchUserVerified(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]any) {
+func (m *MockEventDispatcher) DispatchUserVerified(ctx context.Context, accountID, campaignID uuid.UUID, user events.UserPayload) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "DispatchUserVerified", ctx, accountID, campaignID, userData)
+	m.ctrl.Call(m, "DispatchUserVerified", ctx, accountID, campaignID, user)
 }
 
 // DispatchUserVerified indicates an expected call of DispatchUserVerified.
-func (mr *MockEventDispatcherMockRecorder) DispatchUserVerified(ctx, accountID, campaignID, userData any) *gomock.Call {
+func (mr *MockEventDispatcherMockRecorder) DispatchUserVerified(ctx, accountID, campaignID, user any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchUserVerified", reflect.TypeOf((*MockEventDispatcher)(nil).DispatchUserVerified), ctx, accountID, campaignID, userData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchUserVerified", reflect.TypeOf((*MockEventDispatcher)(nil).DispatchUserVerified), ctx, accountID, campaignID, user)
 }
 
 // MockCaptchaVerifier is a mock of CaptchaVerifier interface.
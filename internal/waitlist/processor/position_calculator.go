@@ -113,10 +113,35 @@ func (pc *PositionCalculator) CalculatePositionsForCampaign(ctx context.Context,
 		return fmt.Errorf("failed to update positions: %w", err)
 	}
 
+	// 5. Record users who moved up so they can be sent a position update digest.
+	// Positions are already saved, so a failure here only costs notifications.
+	improvedIDs, previousPositions := positionImprovements(users, userPositions)
+	if err := pc.store.RecordPositionImprovements(ctx, campaignID, improvedIDs, previousPositions); err != nil {
+		pc.logger.Error(ctx, "failed to record position improvements", err)
+	}
+
 	pc.logger.Info(ctx, "successfully calculated and updated positions for campaign")
 	return nil
 }
 
+// positionImprovements returns the users whose new position is better than their previous one,
+// along with their previous positions. Users without a previous position are new and skipped.
+func positionImprovements(users []store.WaitlistUser, newPositions map[uuid.UUID]int) ([]uuid.UUID, []int) {
+	var userIDs []uuid.UUID
+	var previousPositions []int
+
+	for _, user := range users {
+		newPosition, ok := newPositions[user.ID]
+		if !ok || user.Position <= 0 || newPosition >= user.Position {
+			continue
+		}
+		userIDs = append(userIDs, user.ID)
+		previousPositions = append(previousPositions, user.Position)
+	}
+
+	return userIDs, previousPositions
+}
+
 // calculatePositions implements the position calculation algorithm
 // Algorithm:
 // 1. Sort users by (effective_score DESC, created_at ASC, id ASC)
@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalculatePositionsForCampaign_RecordsImprovements(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWaitlistStore(ctrl)
+	calculator := NewPositionCalculator(mockStore, observability.NewLogger())
+
+	ctx := context.Background()
+	campaignID := uuid.New()
+	now := time.Now()
+
+	// The referrer signed up last but now has two referrals, so they move from 3 to 1
+	early := store.WaitlistUser{ID: uuid.New(), Position: 1, CreatedAt: now.Add(-2 * time.Hour)}
+	middle := store.WaitlistUser{ID: uuid.New(), Position: 2, CreatedAt: now.Add(-time.Hour)}
+	referrer := store.WaitlistUser{ID: uuid.New(), Position: 3, ReferralCount: 2, CreatedAt: now}
+	newcomer := store.WaitlistUser{ID: uuid.New(), Position: 0, CreatedAt: now.Add(time.Minute)}
+
+	mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID}, nil)
+	mockStore.EXPECT().
+		GetAllWaitlistUsersForPositionCalculation(gomock.Any(), campaignID).
+		Return([]store.WaitlistUser{early, middle, referrer, newcomer}, nil)
+	mockStore.EXPECT().BulkUpdateWaitlistUserPositions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	mockStore.EXPECT().
+		RecordPositionImprovements(gomock.Any(), campaignID, []uuid.UUID{referrer.ID}, []int{3}).
+		Return(nil)
+
+	if err := calculator.CalculatePositionsForCampaign(ctx, campaignID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"base-server/internal/store"
 	"base-server/internal/tiers"
 	"base-server/internal/waitlist/utils"
+	"base-server/internal/webhooks/events"
 	"context"
 	"errors"
 	"fmt"
@@ -37,6 +38,7 @@ type WaitlistStore interface {
 	// Position calculation methods
 	GetAllWaitlistUsersForPositionCalculation(ctx context.Context, campaignID uuid.UUID) ([]store.WaitlistUser, error)
 	BulkUpdateWaitlistUserPositions(ctx context.Context, userIDs []uuid.UUID, positions []int) error
+	RecordPositionImprovements(ctx context.Context, campaignID uuid.UUID, userIDs []uuid.UUID, previousPositions []int) error
 	// Channel code methods
 	CreateUserChannelCodes(ctx context.Context, userID uuid.UUID, codes map[string]string) ([]store.UserChannelCode, error)
 	GetUserByChannelCode(ctx context.Context, code string) (*store.WaitlistUser, string, error)
@@ -50,8 +52,8 @@ type WaitlistStore interface {
 
 // EventDispatcher defines the event operations required by WaitlistProcessor
 type EventDispatcher interface {
-	DispatchUserCreated(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]interface{})
-	DispatchUserVerified(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]interface{})
+	DispatchUserCreated(ctx context.Context, accountID, campaignID uuid.UUID, user events.UserPayload)
+	DispatchUserVerified(ctx context.Context, accountID, campaignID uuid.UUID, user events.UserPayload)
 }
 
 // CaptchaVerifier defines the captcha verification operations
@@ -344,20 +346,24 @@ func (p *WaitlistProcessor) SignupUser(ctx context.Context, campaignID uuid.UUID
 
 	// Dispatch user.created event for email notifications
 	if p.eventDispatcher != nil {
-		userData := map[string]interface{}{
-			"id":                 user.ID.String(),
-			"email":              user.Email,
-			"first_name":         user.FirstName,
-			"last_name":          user.LastName,
-			"position":           user.Position,
-			"referral_code":      user.ReferralCode,
-			"referral_link":      referralLink,
-			"verification_token": user.VerificationToken,
-			"email_verified":     user.EmailVerified,
-			"campaign_name":      campaign.Name,
-			"campaign_slug":      campaign.Slug,
-		}
-		p.eventDispatcher.DispatchUserCreated(ctx, campaign.AccountID, campaign.ID, userData)
+		p.eventDispatcher.DispatchUserCreated(ctx, campaign.AccountID, campaign.ID, events.UserPayload{
+			ID:                user.ID.String(),
+			Email:             user.Email,
+			FirstName:         user.FirstName,
+			LastName:          user.LastName,
+			Position:          &user.Position,
+			ReferralCode:      user.ReferralCode,
+			ReferralLink:      referralLink,
+			VerificationToken: user.VerificationToken,
+			EmailVerified:     &user.EmailVerified,
+			CampaignName:      campaign.Name,
+			CampaignSlug:      campaign.Slug,
+			UTMSource:         user.UTMSource,
+			UTMMedium:         user.UTMMedium,
+			UTMCampaign:       user.UTMCampaign,
+			UTMTerm:           user.UTMTerm,
+			UTMContent:        user.UTMContent,
+		})
 	}
 
 	p.logger.Info(ctx, "user signed up successfully")
@@ -1150,17 +1156,21 @@ func (p *WaitlistProcessor) VerifyUserByToken(ctx context.Context, campaignID uu
 		// Don't fail the verification, just log
 	} else if p.eventDispatcher != nil {
 		// Dispatch user.verified event
-		userData := map[string]interface{}{
-			"id":            user.ID.String(),
-			"email":         user.Email,
-			"first_name":    user.FirstName,
-			"last_name":     user.LastName,
-			"position":      user.Position,
-			"referral_code": user.ReferralCode,
-			"campaign_name": campaign.Name,
-			"campaign_slug": campaign.Slug,
-		}
-		p.eventDispatcher.DispatchUserVerified(ctx, campaign.AccountID, campaign.ID, userData)
+		p.eventDispatcher.DispatchUserVerified(ctx, campaign.AccountID, campaign.ID, events.UserPayload{
+			ID:           user.ID.String(),
+			Email:        user.Email,
+			FirstName:    user.FirstName,
+			LastName:     user.LastName,
+			Position:     &user.Position,
+			ReferralCode: user.ReferralCode,
+			CampaignName: campaign.Name,
+			CampaignSlug: campaign.Slug,
+			UTMSource:    user.UTMSource,
+			UTMMedium:    user.UTMMedium,
+			UTMCampaign:  user.UTMCampaign,
+			UTMTerm:      user.UTMTerm,
+			UTMContent:   user.UTMContent,
+		})
 	}
 
 	p.logger.Info(ctx, "user email verified successfully via token")
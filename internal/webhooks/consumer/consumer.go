@@ -124,6 +124,13 @@ func (c *EventConsumer) processEvent(ctx context.Context, event kafka.EventMessa
 
 	c.logger.Info(ctx, fmt.Sprintf("Processing event %s", event.Type))
 
+	// Parse event ID
+	eventID, err := uuid.Parse(event.ID)
+	if err != nil {
+		c.logger.Error(ctx, "invalid event id", err)
+		return fmt.Errorf("invalid event id: %w", err)
+	}
+
 	// Parse account ID
 	accountID, err := uuid.Parse(event.AccountID)
 	if err != nil {
@@ -143,7 +150,7 @@ func (c *EventConsumer) processEvent(ctx context.Context, event kafka.EventMessa
 	}
 
 	// Dispatch to webhooks
-	err = c.webhookService.DispatchEvent(ctx, accountID, campaignID, event.Type, event.Data)
+	err = c.webhookService.DispatchEvent(ctx, eventID, accountID, campaignID, event.Type, event.Data)
 	if err != nil {
 		c.logger.Error(ctx, "failed to dispatch event to webhooks", err)
 		return fmt.Errorf("failed to dispatch event to webhooks: %w", err)
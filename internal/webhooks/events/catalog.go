@@ -0,0 +1,205 @@
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// PayloadVersion describes a version of the event payloads delivered to webhooks
+type PayloadVersion struct {
+	Version int    `json:"version"`
+	Changes string `json:"changes"`
+}
+
+// LatestPayloadVersion is the payload version of new webhooks. Existing webhooks keep the version
+// they are pinned to until they are updated.
+const LatestPayloadVersion = 2
+
+// PayloadVersions lists the payload versions, oldest first
+var PayloadVersions = []PayloadVersion{
+	{Version: 1, Changes: "Initial payloads."},
+	{Version: 2, Changes: "User payloads no longer include verification_token."},
+}
+
+// IsValidPayloadVersion reports whether a payload version exists
+func IsValidPayloadVersion(version int) bool {
+	return version >= 1 && version <= LatestPayloadVersion
+}
+
+// catalogEntry describes a public event type
+type catalogEntry struct {
+	description string
+	// sample is sample data of the event; its type is the event's data type
+	sample interface{}
+}
+
+// Sample identifiers, fixed so samples are stable across requests
+const (
+	sampleEventID    = "0d3c7a9e-5b1f-4c2a-9e8d-1f2a3b4c5d6e"
+	sampleAccountID  = "8a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"
+	sampleCampaignID = "3f9e8d7c-6b5a-4f3e-9d2c-1b0a9f8e7d6c"
+	sampleUserID     = "5e4d3c2b-1a0f-4e9d-8c7b-6a5f4e3d2c1b"
+	sampleCreatedAt  = "2025-01-15T10:30:00Z"
+)
+
+var (
+	sampleUser = UserPayload{
+		ID:                sampleUserID,
+		Email:             "john.doe@example.com",
+		FirstName:         ptr("John"),
+		LastName:          ptr("Doe"),
+		Status:            "verified",
+		Position:          ptr(42),
+		ReferralCode:      "JOHN123",
+		ReferralLink:      "https://example.com/launch?ref=JOHN123",
+		ReferralCount:     ptr(5),
+		EmailVerified:     ptr(true),
+		CampaignName:      "Product Launch",
+		CampaignSlug:      "product-launch",
+		VerificationToken: ptr("b6f0c1d2e3a4"),
+	}
+	sampleReferral = ReferralPayload{
+		ID:         "7c6b5a4f-3e2d-4c1b-8a9f-0e1d2c3b4a5f",
+		ReferrerID: sampleUserID,
+		ReferredID: "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e",
+		Status:     "verified",
+		CreatedAt:  sampleCreatedAt,
+	}
+	sampleReward = RewardPayload{
+		ID:          "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+		Name:        "Early Access",
+		Description: "Get early access to the product",
+		Type:        "access",
+	}
+	sampleEmail = EmailPayload{
+		ID:       "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d",
+		To:       "john.doe@example.com",
+		Subject:  "Welcome to the waitlist!",
+		Template: "welcome",
+	}
+	sampleSegment = SegmentPayload{
+		ID:   "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+		Name: "Power referrers",
+		Type: "dynamic",
+	}
+)
+
+var catalog = map[string]catalogEntry{
+	EventUserCreated:         {"A user signed up to a campaign's waitlist.", UserEventData{sampleCampaignID, sampleUser}},
+	EventUserUpdated:         {"A waitlist user's details changed.", UserEventData{sampleCampaignID, sampleUser}},
+	EventUserVerified:        {"A waitlist user verified their email address.", UserEventData{sampleCampaignID, sampleUser}},
+	EventUserDeleted:         {"A waitlist user was deleted.", UserEventData{sampleCampaignID, sampleUser}},
+	EventUserPositionChanged: {"A waitlist user's position changed.", UserPositionChangedData{sampleCampaignID, sampleUser, 42, 35}},
+	EventUserConverted:       {"A waitlist user converted to a customer.", UserEventData{sampleCampaignID, sampleUser}},
+	EventReferralCreated:     {"A waitlist user referred someone.", ReferralEventData{sampleCampaignID, sampleReferral}},
+	EventReferralVerified:    {"A referred user verified their email address.", ReferralEventData{sampleCampaignID, sampleReferral}},
+	EventReferralConverted:   {"A referred user converted to a customer.", ReferralEventData{sampleCampaignID, sampleReferral}},
+	EventRewardEarned:        {"A waitlist user earned a reward.", RewardEventData{sampleCampaignID, sampleReward, &sampleUser}},
+	EventRewardDelivered:     {"A reward was delivered to a waitlist user.", RewardEventData{sampleCampaignID, sampleReward, &sampleUser}},
+	EventRewardRedeemed:      {"A waitlist user redeemed a reward.", RewardEventData{sampleCampaignID, sampleReward, &sampleUser}},
+	EventCampaignMilestone:   {"A campaign reached a signup milestone.", CampaignMilestoneData{sampleCampaignID, 1000, 1000}},
+	EventCampaignLaunched:    {"A campaign was launched.", CampaignEventData{sampleCampaignID}},
+	EventCampaignCompleted:   {"A campaign was completed.", CampaignEventData{sampleCampaignID}},
+	EventEmailSent:           {"An email was sent to a waitlist user.", EmailEventData{sampleCampaignID, sampleEmail}},
+	EventEmailDelivered:      {"An email was delivered to a waitlist user.", EmailEventData{sampleCampaignID, sampleEmail}},
+	EventEmailOpened:         {"A waitlist user opened an email.", EmailEventData{sampleCampaignID, sampleEmail}},
+	EventEmailClicked:        {"A waitlist user clicked a link in an email.", EmailEventData{sampleCampaignID, sampleEmail}},
+	EventEmailBounced:        {"An email to a waitlist user bounced.", EmailEventData{sampleCampaignID, sampleEmail}},
+	EventSegmentThresholdCrossed: {"A segment's size crossed one of its thresholds.",
+		SegmentThresholdCrossedData{sampleCampaignID, sampleSegment, 500, 498, 503, "up"}},
+	EventSegmentMemberAdded:   {"A user started matching a segment.", SegmentMemberData{sampleCampaignID, sampleSegment, sampleUser}},
+	EventSegmentMemberRemoved: {"A user stopped matching a segment.", SegmentMemberData{sampleCampaignID, sampleSegment, sampleUser}},
+}
+
+// EventTypeInfo describes a public event type in a payload version
+type EventTypeInfo struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Schema is the JSON Schema of the delivered payload
+	Schema map[string]interface{} `json:"schema"`
+	// Sample is a sample delivered payload
+	Sample map[string]interface{} `json:"sample"`
+}
+
+// Catalog returns the public event types as delivered in a payload version
+func Catalog(version int) []EventTypeInfo {
+	infos := make([]EventTypeInfo, 0, len(PublicEvents))
+	for _, eventType := range PublicEvents {
+		entry := catalog[eventType]
+		infos = append(infos, EventTypeInfo{
+			Type:        eventType,
+			Description: entry.description,
+			Schema:      payloadSchema(eventType, version, jsonSchema(reflect.TypeOf(entry.sample), version)),
+			Sample: map[string]interface{}{
+				"id":             sampleEventID,
+				"type":           eventType,
+				"created_at":     sampleCreatedAt,
+				"data":           SampleData(eventType, version),
+				"account_id":     sampleAccountID,
+				"schema_version": version,
+			},
+		})
+	}
+	return infos
+}
+
+// payloadSchema returns the JSON Schema of a webhook payload carrying data of the given schema
+func payloadSchema(eventType string, version int, dataSchema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   eventType,
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"id":             map[string]interface{}{"type": "string", "format": "uuid"},
+			"type":           map[string]interface{}{"const": eventType},
+			"created_at":     map[string]interface{}{"type": "string", "format": "date-time"},
+			"data":           dataSchema,
+			"account_id":     map[string]interface{}{"type": "string", "format": "uuid"},
+			"schema_version": map[string]interface{}{"const": version},
+		},
+		"required": []string{"id", "type", "created_at", "data", "account_id", "schema_version"},
+	}
+}
+
+// FormatData returns event data as delivered in a payload version. Events are published with the
+// fields of every version, and each webhook receives the fields of the version it is pinned to.
+// Data of event types outside the catalog is returned as is.
+func FormatData(eventType string, version int, data map[string]interface{}) map[string]interface{} {
+	entry, ok := catalog[eventType]
+	if !ok {
+		return data
+	}
+	return formatData(reflect.TypeOf(entry.sample), version, data)
+}
+
+// SampleData returns sample data of an event type in a payload version, nil for unknown types
+func SampleData(eventType string, version int) map[string]interface{} {
+	entry, ok := catalog[eventType]
+	if !ok {
+		return nil
+	}
+
+	data, err := toData(entry.sample)
+	if err != nil {
+		return nil
+	}
+	return FormatData(eventType, version, data)
+}
+
+// toData converts a data payload to the map published with an event
+func toData(payload interface{}) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,133 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCatalog_CoversPublicEvents(t *testing.T) {
+	for _, eventType := range PublicEvents {
+		if _, ok := catalog[eventType]; !ok {
+			t.Errorf("Expected %s in the catalog", eventType)
+		}
+	}
+
+	for version := 1; version <= LatestPayloadVersion; version++ {
+		infos := Catalog(version)
+		if len(infos) != len(PublicEvents) {
+			t.Fatalf("Expected %d event types in version %d, got %d", len(PublicEvents), version, len(infos))
+		}
+		for _, info := range infos {
+			if info.Description == "" {
+				t.Errorf("Expected a description for %s", info.Type)
+			}
+			if info.Sample["schema_version"] != version {
+				t.Errorf("Expected %s sample in version %d, got %v", info.Type, version, info.Sample["schema_version"])
+			}
+			if _, err := json.Marshal(info); err != nil {
+				t.Errorf("Failed to marshal %s: %v", info.Type, err)
+			}
+		}
+	}
+}
+
+func TestPayloadVersions(t *testing.T) {
+	if len(PayloadVersions) != LatestPayloadVersion {
+		t.Fatalf("Expected %d payload versions, got %d", LatestPayloadVersion, len(PayloadVersions))
+	}
+	for i, version := range PayloadVersions {
+		if version.Version != i+1 {
+			t.Errorf("Expected payload version %d at index %d, got %d", i+1, i, version.Version)
+		}
+	}
+
+	if IsValidPayloadVersion(0) || IsValidPayloadVersion(LatestPayloadVersion+1) {
+		t.Error("Expected versions outside 1 to latest to be invalid")
+	}
+}
+
+func TestFormatData_RemovesFieldsByVersion(t *testing.T) {
+	data := map[string]interface{}{
+		"campaign_id": "campaign",
+		"user": map[string]interface{}{
+			"id":                 "user",
+			"email":              "test@example.com",
+			"verification_token": "token",
+			"custom":             "kept",
+		},
+	}
+
+	v1 := FormatData(EventUserCreated, 1, data)
+	if _, ok := v1["user"].(map[string]interface{})["verification_token"]; !ok {
+		t.Error("Expected version 1 to include verification_token")
+	}
+
+	v2 := FormatData(EventUserCreated, 2, data)
+	user := v2["user"].(map[string]interface{})
+	if _, ok := user["verification_token"]; ok {
+		t.Error("Expected version 2 not to include verification_token")
+	}
+	if user["custom"] != "kept" || v2["campaign_id"] != "campaign" {
+		t.Errorf("Expected other fields to be kept, got %v", v2)
+	}
+
+	if _, ok := data["user"].(map[string]interface{})["verification_token"]; !ok {
+		t.Error("Expected the input data not to be modified")
+	}
+}
+
+func TestFormatData_NestedPointer(t *testing.T) {
+	data := map[string]interface{}{
+		"reward": map[string]interface{}{"id": "reward"},
+		"user":   map[string]interface{}{"id": "user", "verification_token": "token"},
+	}
+
+	formatted := FormatData(EventRewardEarned, 2, data)
+	if _, ok := formatted["user"].(map[string]interface{})["verification_token"]; ok {
+		t.Error("Expected version 2 reward payloads not to include the user's verification_token")
+	}
+}
+
+func TestFormatData_UnknownEventType(t *testing.T) {
+	data := map[string]interface{}{"test": true}
+
+	if formatted := FormatData("webhook.test", 2, data); formatted["test"] != true {
+		t.Errorf("Expected data of unknown event types as is, got %v", formatted)
+	}
+}
+
+func TestJSONSchema_ByVersion(t *testing.T) {
+	userProperties := func(version int) map[string]interface{} {
+		schema := Catalog(version)[0].Schema
+		data := schema["properties"].(map[string]interface{})["data"].(map[string]interface{})
+		user := data["properties"].(map[string]interface{})["user"].(map[string]interface{})
+		return user["properties"].(map[string]interface{})
+	}
+
+	if _, ok := userProperties(1)["verification_token"]; !ok {
+		t.Error("Expected the version 1 schema to include verification_token")
+	}
+	if _, ok := userProperties(2)["verification_token"]; ok {
+		t.Error("Expected the version 2 schema not to include verification_token")
+	}
+	if userProperties(2)["email"].(map[string]interface{})["format"] != "email" {
+		t.Error("Expected the email property to have the email format")
+	}
+	// UTM parameters are documented for filters such as user.utm_source
+	for _, field := range []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content"} {
+		if _, ok := userProperties(1)[field]; !ok {
+			t.Errorf("Expected the user schema to include %s", field)
+		}
+	}
+}
+
+func TestSampleData(t *testing.T) {
+	sample := SampleData(EventUserCreated, 2)
+	if sample["campaign_id"] == nil || sample["user"] == nil {
+		t.Errorf("Expected campaign_id and user in the sample, got %v", sample)
+	}
+
+	if SampleData("unknown.event", 2) != nil {
+		t.Error("Expected no sample for unknown event types")
+	}
+}
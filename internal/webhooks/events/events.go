@@ -4,6 +4,7 @@ import (
 	"base-server/internal/observability"
 	"base-server/internal/webhooks/producer"
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 )
@@ -40,12 +41,55 @@ const (
 	EventEmailClicked   = "email.clicked"
 	EventEmailBounced   = "email.bounced"
 
+	// Segment events
+	EventSegmentThresholdCrossed = "segment.threshold_crossed"
+	EventSegmentMemberAdded      = "segment.member_added"
+	EventSegmentMemberRemoved    = "segment.member_removed"
+
 	// Email Blast events
 	EventBlastStarted   = "blast.started"   // Start processing blast (create recipients from segment)
 	EventBlastBatchSend = "blast.batch"     // Send a batch of emails
 	EventBlastCompleted = "blast.completed" // Mark blast as complete
 )
 
+// PublicEvents lists the event types delivered to webhooks, integrations and the events API.
+// Internal job events such as the blast events are not public.
+var PublicEvents = []string{
+	EventUserCreated,
+	EventUserUpdated,
+	EventUserVerified,
+	EventUserDeleted,
+	EventUserPositionChanged,
+	EventUserConverted,
+	EventReferralCreated,
+	EventReferralVerified,
+	EventReferralConverted,
+	EventRewardEarned,
+	EventRewardDelivered,
+	EventRewardRedeemed,
+	EventCampaignMilestone,
+	EventCampaignLaunched,
+	EventCampaignCompleted,
+	EventEmailSent,
+	EventEmailDelivered,
+	EventEmailOpened,
+	EventEmailClicked,
+	EventEmailBounced,
+	EventSegmentThresholdCrossed,
+	EventSegmentMemberAdded,
+	EventSegmentMemberRemoved,
+}
+
+// IsPublicEvent reports whether an event type is a public event
+func IsPublicEvent(eventType string) bool {
+	for _, event := range PublicEvents {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
 // EventDispatcher provides convenience methods for dispatching webhook events
 type EventDispatcher struct {
 	eventProducer *producer.EventProducer
@@ -60,124 +104,125 @@ func NewEventDispatcher(eventProducer *producer.EventProducer, logger *observabi
 	}
 }
 
-// DispatchUserCreated dispatches a user.created event
-func (d *EventDispatcher) DispatchUserCreated(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"user":        userData,
+// publish publishes an event with a typed data payload. Errors are logged and returned for the
+// callers that must not lose the event.
+func (d *EventDispatcher) publish(ctx context.Context, accountID, campaignID uuid.UUID, eventType string, payload interface{}) error {
+	data, err := toData(payload)
+	if err == nil {
+		err = d.eventProducer.PublishEvent(ctx, accountID, &campaignID, eventType, data)
 	}
-
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventUserCreated, data)
 	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch user.created event", err)
+		d.logger.Error(ctx, fmt.Sprintf("failed to dispatch %s event", eventType), err)
+		return fmt.Errorf("failed to dispatch %s event: %w", eventType, err)
 	}
+	return nil
 }
 
-// DispatchUserVerified dispatches a user.verified event
-func (d *EventDispatcher) DispatchUserVerified(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"user":        userData,
-	}
+// DispatchUserCreated dispatches a user.created event
+func (d *EventDispatcher) DispatchUserCreated(ctx context.Context, accountID, campaignID uuid.UUID, user UserPayload) {
+	d.publish(ctx, accountID, campaignID, EventUserCreated, UserEventData{
+		CampaignID: campaignID.String(),
+		User:       user,
+	})
+}
 
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventUserVerified, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch user.verified event", err)
-	}
+// DispatchUserVerified dispatches a user.verified event
+func (d *EventDispatcher) DispatchUserVerified(ctx context.Context, accountID, campaignID uuid.UUID, user UserPayload) {
+	d.publish(ctx, accountID, campaignID, EventUserVerified, UserEventData{
+		CampaignID: campaignID.String(),
+		User:       user,
+	})
 }
 
 // DispatchUserPositionChanged dispatches a user.position_changed event
-func (d *EventDispatcher) DispatchUserPositionChanged(ctx context.Context, accountID, campaignID uuid.UUID, userData map[string]interface{}, oldPosition, newPosition int) {
-	data := map[string]interface{}{
-		"campaign_id":  campaignID.String(),
-		"user":         userData,
-		"old_position": oldPosition,
-		"new_position": newPosition,
-	}
-
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventUserPositionChanged, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch user.position_changed event", err)
-	}
+func (d *EventDispatcher) DispatchUserPositionChanged(ctx context.Context, accountID, campaignID uuid.UUID, user UserPayload, oldPosition, newPosition int) {
+	d.publish(ctx, accountID, campaignID, EventUserPositionChanged, UserPositionChangedData{
+		CampaignID:  campaignID.String(),
+		User:        user,
+		OldPosition: oldPosition,
+		NewPosition: newPosition,
+	})
 }
 
 // DispatchReferralCreated dispatches a referral.created event
-func (d *EventDispatcher) DispatchReferralCreated(ctx context.Context, accountID, campaignID uuid.UUID, referralData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"referral":    referralData,
-	}
-
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventReferralCreated, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch referral.created event", err)
-	}
+func (d *EventDispatcher) DispatchReferralCreated(ctx context.Context, accountID, campaignID uuid.UUID, referral ReferralPayload) {
+	d.publish(ctx, accountID, campaignID, EventReferralCreated, ReferralEventData{
+		CampaignID: campaignID.String(),
+		Referral:   referral,
+	})
 }
 
 // DispatchReferralVerified dispatches a referral.verified event
-func (d *EventDispatcher) DispatchReferralVerified(ctx context.Context, accountID, campaignID uuid.UUID, referralData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"referral":    referralData,
-	}
-
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventReferralVerified, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch referral.verified event", err)
-	}
+func (d *EventDispatcher) DispatchReferralVerified(ctx context.Context, accountID, campaignID uuid.UUID, referral ReferralPayload) {
+	d.publish(ctx, accountID, campaignID, EventReferralVerified, ReferralEventData{
+		CampaignID: campaignID.String(),
+		Referral:   referral,
+	})
 }
 
 // DispatchRewardEarned dispatches a reward.earned event
-func (d *EventDispatcher) DispatchRewardEarned(ctx context.Context, accountID, campaignID uuid.UUID, rewardData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"reward":      rewardData,
-	}
-
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventRewardEarned, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch reward.earned event", err)
-	}
+func (d *EventDispatcher) DispatchRewardEarned(ctx context.Context, accountID, campaignID uuid.UUID, reward RewardPayload, user *UserPayload) {
+	d.publish(ctx, accountID, campaignID, EventRewardEarned, RewardEventData{
+		CampaignID: campaignID.String(),
+		Reward:     reward,
+		User:       user,
+	})
 }
 
 // DispatchCampaignMilestone dispatches a campaign.milestone event
 func (d *EventDispatcher) DispatchCampaignMilestone(ctx context.Context, accountID, campaignID uuid.UUID, milestone int, totalSignups int) {
-	data := map[string]interface{}{
-		"campaign_id":   campaignID.String(),
-		"milestone":     milestone,
-		"total_signups": totalSignups,
-	}
+	d.publish(ctx, accountID, campaignID, EventCampaignMilestone, CampaignMilestoneData{
+		CampaignID:   campaignID.String(),
+		Milestone:    milestone,
+		TotalSignups: totalSignups,
+	})
+}
 
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventCampaignMilestone, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch campaign.milestone event", err)
-	}
+// DispatchSegmentThresholdCrossed dispatches a segment.threshold_crossed event.
+// Direction is "up" when the segment grew to its threshold and "down" when it shrank below it.
+func (d *EventDispatcher) DispatchSegmentThresholdCrossed(ctx context.Context, accountID, campaignID uuid.UUID, segment SegmentPayload, threshold, previousCount, userCount int, direction string) {
+	d.publish(ctx, accountID, campaignID, EventSegmentThresholdCrossed, SegmentThresholdCrossedData{
+		CampaignID:    campaignID.String(),
+		Segment:       segment,
+		Threshold:     threshold,
+		PreviousCount: previousCount,
+		UserCount:     userCount,
+		Direction:     direction,
+	})
 }
 
-// DispatchEmailSent dispatches an email.sent event
-func (d *EventDispatcher) DispatchEmailSent(ctx context.Context, accountID, campaignID uuid.UUID, emailData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"email":       emailData,
-	}
+// DispatchSegmentMemberAdded dispatches a segment.member_added event
+func (d *EventDispatcher) DispatchSegmentMemberAdded(ctx context.Context, accountID, campaignID uuid.UUID, segment SegmentPayload, user UserPayload) error {
+	return d.publish(ctx, accountID, campaignID, EventSegmentMemberAdded, SegmentMemberData{
+		CampaignID: campaignID.String(),
+		Segment:    segment,
+		User:       user,
+	})
+}
 
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventEmailSent, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch email.sent event", err)
-	}
+// DispatchSegmentMemberRemoved dispatches a segment.member_removed event
+func (d *EventDispatcher) DispatchSegmentMemberRemoved(ctx context.Context, accountID, campaignID uuid.UUID, segment SegmentPayload, user UserPayload) error {
+	return d.publish(ctx, accountID, campaignID, EventSegmentMemberRemoved, SegmentMemberData{
+		CampaignID: campaignID.String(),
+		Segment:    segment,
+		User:       user,
+	})
 }
 
-// DispatchEmailDelivered dispatches an email.delivered event
-func (d *EventDispatcher) DispatchEmailDelivered(ctx context.Context, accountID, campaignID uuid.UUID, emailData map[string]interface{}) {
-	data := map[string]interface{}{
-		"campaign_id": campaignID.String(),
-		"email":       emailData,
-	}
+// DispatchEmailSent dispatches an email.sent event
+func (d *EventDispatcher) DispatchEmailSent(ctx context.Context, accountID, campaignID uuid.UUID, email EmailPayload) {
+	d.publish(ctx, accountID, campaignID, EventEmailSent, EmailEventData{
+		CampaignID: campaignID.String(),
+		Email:      email,
+	})
+}
 
-	err := d.eventProducer.PublishEvent(ctx, accountID, &campaignID, EventEmailDelivered, data)
-	if err != nil {
-		d.logger.Error(ctx, "failed to dispatch email.delivered event", err)
-	}
+// DispatchEmailDelivered dispatches an email.delivered event
+func (d *EventDispatcher) DispatchEmailDelivered(ctx context.Context, accountID, campaignID uuid.UUID, email EmailPayload) {
+	d.publish(ctx, accountID, campaignID, EventEmailDelivered, EmailEventData{
+		CampaignID: campaignID.String(),
+		Email:      email,
+	})
 }
 
 // DispatchBlastStarted dispatches a blast.started event to begin processing an email blast
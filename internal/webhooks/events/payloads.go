@@ -0,0 +1,128 @@
+package events
+
+// Event data payloads. Each public event type publishes one of these structs as its data; the
+// catalog derives the event's JSON Schema from it.
+//
+// Fields carry their payload versions in struct tags: since:"N" for fields added in version N and
+// until:"N" for fields removed in version N. Webhooks pinned to a version receive the fields of
+// that version only. format and enum tags end up in the JSON Schema.
+
+// UserPayload describes a waitlist user
+type UserPayload struct {
+	ID            string  `json:"id" format:"uuid"`
+	Email         string  `json:"email" format:"email"`
+	FirstName     *string `json:"first_name,omitempty"`
+	LastName      *string `json:"last_name,omitempty"`
+	Status        string  `json:"status,omitempty"`
+	Position      *int    `json:"position,omitempty"`
+	ReferralCode  string  `json:"referral_code,omitempty"`
+	ReferralLink  string  `json:"referral_link,omitempty" format:"uri"`
+	ReferralCount *int    `json:"referral_count,omitempty"`
+	EmailVerified *bool   `json:"email_verified,omitempty"`
+	CampaignName  string  `json:"campaign_name,omitempty"`
+	CampaignSlug  string  `json:"campaign_slug,omitempty"`
+	// UTM parameters captured at signup, usable in webhook filters such as user.utm_source
+	UTMSource   *string `json:"utm_source,omitempty"`
+	UTMMedium   *string `json:"utm_medium,omitempty"`
+	UTMCampaign *string `json:"utm_campaign,omitempty"`
+	UTMTerm     *string `json:"utm_term,omitempty"`
+	UTMContent  *string `json:"utm_content,omitempty"`
+	// VerificationToken is used by the verification email and is not sent to webhooks from version 2
+	VerificationToken *string `json:"verification_token,omitempty" until:"2"`
+}
+
+// ReferralPayload describes a referral
+type ReferralPayload struct {
+	ID         string `json:"id" format:"uuid"`
+	ReferrerID string `json:"referrer_id" format:"uuid"`
+	ReferredID string `json:"referred_id" format:"uuid"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at,omitempty" format:"date-time"`
+}
+
+// RewardPayload describes a reward
+type RewardPayload struct {
+	ID          string `json:"id" format:"uuid"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+}
+
+// EmailPayload describes an email sent to a waitlist user
+type EmailPayload struct {
+	ID       string `json:"id" format:"uuid"`
+	To       string `json:"to" format:"email"`
+	Subject  string `json:"subject"`
+	Template string `json:"template,omitempty"`
+}
+
+// SegmentPayload describes a segment
+type SegmentPayload struct {
+	ID   string `json:"id" format:"uuid"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// UserEventData is the data of the user.created, user.updated, user.verified, user.deleted and
+// user.converted events
+type UserEventData struct {
+	CampaignID string      `json:"campaign_id" format:"uuid"`
+	User       UserPayload `json:"user"`
+}
+
+// UserPositionChangedData is the data of the user.position_changed event
+type UserPositionChangedData struct {
+	CampaignID  string      `json:"campaign_id" format:"uuid"`
+	User        UserPayload `json:"user"`
+	OldPosition int         `json:"old_position"`
+	NewPosition int         `json:"new_position"`
+}
+
+// ReferralEventData is the data of the referral events
+type ReferralEventData struct {
+	CampaignID string          `json:"campaign_id" format:"uuid"`
+	Referral   ReferralPayload `json:"referral"`
+}
+
+// RewardEventData is the data of the reward events
+type RewardEventData struct {
+	CampaignID string        `json:"campaign_id" format:"uuid"`
+	Reward     RewardPayload `json:"reward"`
+	User       *UserPayload  `json:"user,omitempty"`
+}
+
+// CampaignEventData is the data of the campaign.launched and campaign.completed events
+type CampaignEventData struct {
+	CampaignID string `json:"campaign_id" format:"uuid"`
+}
+
+// CampaignMilestoneData is the data of the campaign.milestone event
+type CampaignMilestoneData struct {
+	CampaignID   string `json:"campaign_id" format:"uuid"`
+	Milestone    int    `json:"milestone"`
+	TotalSignups int    `json:"total_signups"`
+}
+
+// EmailEventData is the data of the email events
+type EmailEventData struct {
+	CampaignID string       `json:"campaign_id" format:"uuid"`
+	Email      EmailPayload `json:"email"`
+}
+
+// SegmentThresholdCrossedData is the data of the segment.threshold_crossed event. Direction is
+// "up" when the segment grew to its threshold and "down" when it shrank below it.
+type SegmentThresholdCrossedData struct {
+	CampaignID    string         `json:"campaign_id" format:"uuid"`
+	Segment       SegmentPayload `json:"segment"`
+	Threshold     int            `json:"threshold"`
+	PreviousCount int            `json:"previous_count"`
+	UserCount     int            `json:"user_count"`
+	Direction     string         `json:"direction" enum:"up,down"`
+}
+
+// SegmentMemberData is the data of the segment.member_added and segment.member_removed events
+type SegmentMemberData struct {
+	CampaignID string         `json:"campaign_id" format:"uuid"`
+	Segment    SegmentPayload `json:"segment"`
+	User       UserPayload    `json:"user"`
+}
@@ -0,0 +1,134 @@
+package events
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// payloadField is a struct field as it appears in a JSON payload
+type payloadField struct {
+	name      string
+	omitEmpty bool
+	field     reflect.StructField
+}
+
+// payloadFields returns the JSON fields of a struct type, in any payload version
+func payloadFields(t reflect.Type) []payloadField {
+	var fields []payloadField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, payloadField{
+			name:      name,
+			omitEmpty: strings.Contains(options, "omitempty"),
+			field:     field,
+		})
+	}
+	return fields
+}
+
+// inVersion reports whether a field is part of a payload version, per its since and until tags
+func (f payloadField) inVersion(version int) bool {
+	if since, err := strconv.Atoi(f.field.Tag.Get("since")); err == nil && version < since {
+		return false
+	}
+	if until, err := strconv.Atoi(f.field.Tag.Get("until")); err == nil && version >= until {
+		return false
+	}
+	return true
+}
+
+// jsonSchema returns the JSON Schema of a payload type in a payload version. Fields without
+// omitempty are required.
+func jsonSchema(t reflect.Type, version int) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return jsonSchema(t.Elem(), version)
+	case reflect.Struct:
+		properties := map[string]interface{}{}
+		required := []string{}
+		for _, f := range payloadFields(t) {
+			if !f.inVersion(version) {
+				continue
+			}
+
+			schema := jsonSchema(f.field.Type, version)
+			if format := f.field.Tag.Get("format"); format != "" {
+				schema["format"] = format
+			}
+			if enum := f.field.Tag.Get("enum"); enum != "" {
+				schema["enum"] = strings.Split(enum, ",")
+			}
+			properties[f.name] = schema
+
+			if !f.omitEmpty {
+				required = append(required, f.name)
+			}
+		}
+
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": jsonSchema(t.Elem(), version)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object"}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// formatData returns a copy of event data holding the fields of a payload type in a payload
+// version. Fields not part of the version are removed; keys unknown to the type are kept.
+func formatData(t reflect.Type, version int, data map[string]interface{}) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return data
+	}
+
+	formatted := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		formatted[key] = value
+	}
+
+	for _, f := range payloadFields(t) {
+		if !f.inVersion(version) {
+			delete(formatted, f.name)
+			continue
+		}
+		if nested, ok := formatted[f.name].(map[string]interface{}); ok {
+			formatted[f.name] = formatData(f.field.Type, version, nested)
+		}
+	}
+
+	return formatted
+}
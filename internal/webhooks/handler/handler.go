@@ -4,9 +4,12 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"base-server/internal/apierrors"
 	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
 	"base-server/internal/webhooks/processor"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +37,28 @@ func (h *Handler) handleError(c *gin.Context, err error) {
 		apierrors.Forbidden(c, "FEATURE_NOT_AVAILABLE", "Webhooks are not available in your plan. Please upgrade to Team plan.")
 	case errors.Is(err, processor.ErrWebhookNotFound):
 		apierrors.NotFound(c, "Webhook not found")
+	case errors.Is(err, processor.ErrDeliveryNotFound):
+		apierrors.NotFound(c, "Webhook delivery not found")
+	case errors.Is(err, processor.ErrWebhookInactive):
+		apierrors.Conflict(c, "WEBHOOK_INACTIVE", "Webhook must be active to redeliver")
+	case errors.Is(err, processor.ErrWebhookFailed):
+		apierrors.Conflict(c, "WEBHOOK_FAILED", "Webhook was disabled after repeated failures; re-enable it with POST /enable, which sends a test ping first")
+	case errors.Is(err, processor.ErrDeliveryRedacted):
+		apierrors.Conflict(c, "DELIVERY_REDACTED", "Webhook delivery payload has been redacted and can no longer be redelivered")
+	case errors.Is(err, processor.ErrInvalidTimeRange):
+		apierrors.BadRequest(c, "INVALID_TIME_RANGE", err.Error())
+	case errors.Is(err, processor.ErrInvalidWebhookURL):
+		apierrors.BadRequest(c, "INVALID_WEBHOOK_URL", err.Error())
+	case errors.Is(err, processor.ErrInvalidRotationOverlap):
+		apierrors.BadRequest(c, "INVALID_ROTATION_OVERLAP", err.Error())
+	case errors.Is(err, processor.ErrInvalidSignatureFormat):
+		apierrors.BadRequest(c, "INVALID_SIGNATURE_FORMAT", err.Error())
+	case errors.Is(err, processor.ErrInvalidFilters):
+		apierrors.BadRequest(c, "INVALID_FILTERS", err.Error())
+	case errors.Is(err, processor.ErrInvalidPayloadVersion):
+		apierrors.BadRequest(c, "INVALID_PAYLOAD_VERSION", err.Error())
+	case errors.Is(err, processor.ErrTestPingFailed):
+		apierrors.Conflict(c, "TEST_PING_FAILED", "The webhook endpoint did not accept the test event, so the webhook was not re-enabled")
 	default:
 		apierrors.InternalError(c, err)
 	}
@@ -46,6 +71,14 @@ type CreateWebhookRequest struct {
 	Events       []string `json:"events" binding:"required,min=1"`
 	RetryEnabled bool     `json:"retry_enabled"`
 	MaxRetries   int      `json:"max_retries"`
+	// SignatureFormat is "v1" (default) or "standard_webhooks"
+	SignatureFormat string `json:"signature_format"`
+	// Filters are conditions on the event data, e.g. {"field": "user.utm_source", "operator": "eq", "value": "producthunt"}
+	Filters store.WebhookFilters `json:"filters"`
+	// OrderedDelivery sends deliveries one at a time in event order instead of concurrently
+	OrderedDelivery bool `json:"ordered_delivery"`
+	// PayloadVersion pins the event payload version, the latest version when omitted
+	PayloadVersion int `json:"payload_version"`
 }
 
 // CreateWebhookResponse represents the response for creating a webhook
@@ -88,12 +121,16 @@ func (h *Handler) HandleCreateWebhook(c *gin.Context) {
 
 	// Create webhook
 	webhook, secret, err := h.processor.CreateWebhook(ctx, processor.CreateWebhookParams{
-		AccountID:    parsedAccountID,
-		CampaignID:   campaignID,
-		URL:          req.URL,
-		Events:       req.Events,
-		RetryEnabled: req.RetryEnabled,
-		MaxRetries:   req.MaxRetries,
+		AccountID:       parsedAccountID,
+		CampaignID:      campaignID,
+		URL:             req.URL,
+		Events:          req.Events,
+		RetryEnabled:    req.RetryEnabled,
+		MaxRetries:      req.MaxRetries,
+		SignatureFormat: req.SignatureFormat,
+		Filters:         req.Filters,
+		OrderedDelivery: req.OrderedDelivery,
+		PayloadVersion:  req.PayloadVersion,
 	})
 	if err != nil {
 		h.handleError(c, err)
@@ -145,6 +182,32 @@ func (h *Handler) HandleListWebhooks(c *gin.Context) {
 	c.JSON(http.StatusOK, webhooks)
 }
 
+// HandleListEventTypes handles GET /api/v1/webhooks/event-types
+func (h *Handler) HandleListEventTypes(c *gin.Context) {
+	version := 0
+	if versionStr := c.Query("version"); versionStr != "" {
+		parsedVersion, err := strconv.Atoi(versionStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+			return
+		}
+		version = parsedVersion
+	}
+
+	version, eventTypes, err := h.processor.GetEventTypes(version)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version":        version,
+		"latest_version": events.LatestPayloadVersion,
+		"versions":       events.PayloadVersions,
+		"event_types":    eventTypes,
+	})
+}
+
 // HandleGetWebhook handles GET /api/v1/webhooks/:webhook_id
 func (h *Handler) HandleGetWebhook(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -169,11 +232,16 @@ func (h *Handler) HandleGetWebhook(c *gin.Context) {
 
 // UpdateWebhookRequest represents a request to update a webhook
 type UpdateWebhookRequest struct {
-	URL          *string  `json:"url"`
-	Events       []string `json:"events"`
-	Status       *string  `json:"status"`
-	RetryEnabled *bool    `json:"retry_enabled"`
-	MaxRetries   *int     `json:"max_retries"`
+	URL             *string  `json:"url"`
+	Events          []string `json:"events"`
+	Status          *string  `json:"status"`
+	RetryEnabled    *bool    `json:"retry_enabled"`
+	MaxRetries      *int     `json:"max_retries"`
+	SignatureFormat *string  `json:"signature_format"`
+	// Filters replaces the webhook's filters when present, [] removes them
+	Filters         store.WebhookFilters `json:"filters"`
+	OrderedDelivery *bool                `json:"ordered_delivery"`
+	PayloadVersion  *int                 `json:"payload_version"`
 }
 
 // HandleUpdateWebhook handles PUT /api/v1/webhooks/:webhook_id
@@ -196,11 +264,15 @@ func (h *Handler) HandleUpdateWebhook(c *gin.Context) {
 	}
 
 	webhook, err := h.processor.UpdateWebhook(ctx, webhookID, processor.UpdateWebhookParams{
-		URL:          req.URL,
-		Events:       req.Events,
-		Status:       req.Status,
-		RetryEnabled: req.RetryEnabled,
-		MaxRetries:   req.MaxRetries,
+		URL:             req.URL,
+		Events:          req.Events,
+		Status:          req.Status,
+		RetryEnabled:    req.RetryEnabled,
+		MaxRetries:      req.MaxRetries,
+		SignatureFormat: req.SignatureFormat,
+		Filters:         req.Filters,
+		OrderedDelivery: req.OrderedDelivery,
+		PayloadVersion:  req.PayloadVersion,
 	})
 	if err != nil {
 		h.handleError(c, err)
@@ -304,3 +376,245 @@ func (h *Handler) HandleTestWebhook(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Test webhook sent successfully"})
 }
+
+// RotateWebhookSecretRequest represents a request to rotate a webhook's signing secret
+type RotateWebhookSecretRequest struct {
+	// OverlapHours is how long the replaced secret stays valid, 24 hours when omitted
+	OverlapHours *int `json:"overlap_hours"`
+}
+
+// RotateWebhookSecretResponse represents the response for rotating a webhook's signing secret
+type RotateWebhookSecretResponse struct {
+	Webhook interface{} `json:"webhook"`
+	Secret  string      `json:"secret"`
+}
+
+// HandleRotateWebhookSecret handles POST /api/v1/webhooks/:webhook_id/rotate-secret
+func (h *Handler) HandleRotateWebhookSecret(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account and user ID from context (set by auth middleware)
+	accountID := c.MustGet("Account-ID")
+	parsedAccountID := uuid.MustParse(accountID.(string))
+	userID := c.MustGet("User-ID")
+	parsedUserID := uuid.MustParse(userID.(string))
+
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse webhook_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id"})
+		return
+	}
+
+	var req RotateWebhookSecretRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			apierrors.ValidationError(c, err)
+			return
+		}
+	}
+
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	var overlap *time.Duration
+	if req.OverlapHours != nil {
+		hours := time.Duration(*req.OverlapHours) * time.Hour
+		overlap = &hours
+	}
+
+	var ipAddress *string
+	if ip := c.ClientIP(); ip != "" {
+		ipAddress = &ip
+	}
+	userAgent := c.Request.UserAgent()
+
+	webhook, secret, err := h.processor.RotateWebhookSecret(ctx, processor.RotateSecretParams{
+		AccountID:   parsedAccountID,
+		WebhookID:   webhookID,
+		ActorUserID: parsedUserID,
+		Overlap:     overlap,
+		IPAddress:   ipAddress,
+		UserAgent:   &userAgent,
+	})
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, RotateWebhookSecretResponse{
+		Webhook: webhook,
+		Secret:  secret,
+	})
+}
+
+// HandleEnableWebhook handles POST /api/v1/webhooks/:webhook_id/enable
+func (h *Handler) HandleEnableWebhook(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context (set by auth middleware)
+	accountID := c.MustGet("Account-ID")
+	parsedAccountID := uuid.MustParse(accountID.(string))
+
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse webhook_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id"})
+		return
+	}
+
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	webhook, err := h.processor.EnableWebhook(ctx, parsedAccountID, webhookID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// HandleGetWebhookDelivery handles GET /api/v1/webhooks/:webhook_id/deliveries/:delivery_id
+func (h *Handler) HandleGetWebhookDelivery(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context (set by auth middleware)
+	accountID := c.MustGet("Account-ID")
+	parsedAccountID := uuid.MustParse(accountID.(string))
+
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse webhook_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id"})
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse delivery_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery_id"})
+		return
+	}
+
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "webhook_id", Value: webhookID},
+		observability.Field{Key: "delivery_id", Value: deliveryID},
+	)
+
+	attempts, err := h.processor.GetWebhookDelivery(ctx, parsedAccountID, webhookID, deliveryID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attempts)
+}
+
+// HandleGetWebhookStats handles GET /api/v1/webhooks/:webhook_id/stats
+func (h *Handler) HandleGetWebhookStats(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context (set by auth middleware)
+	accountID := c.MustGet("Account-ID")
+	parsedAccountID := uuid.MustParse(accountID.(string))
+
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse webhook_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id"})
+		return
+	}
+
+	days := 30
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsedDays, err := strconv.Atoi(daysStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+			return
+		}
+		days = parsedDays
+	}
+
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	stats, err := h.processor.GetWebhookDailyStats(ctx, parsedAccountID, webhookID, days)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"stats": stats})
+}
+
+// HandleRedeliverWebhookDelivery handles POST /api/v1/webhooks/:webhook_id/deliveries/:delivery_id/redeliver
+func (h *Handler) HandleRedeliverWebhookDelivery(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context (set by auth middleware)
+	accountID := c.MustGet("Account-ID")
+	parsedAccountID := uuid.MustParse(accountID.(string))
+
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse webhook_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id"})
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse delivery_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery_id"})
+		return
+	}
+
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "webhook_id", Value: webhookID},
+		observability.Field{Key: "delivery_id", Value: deliveryID},
+	)
+
+	attempts, err := h.processor.RedeliverWebhookDelivery(ctx, parsedAccountID, webhookID, deliveryID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attempts)
+}
+
+// RetryWebhookDeliveriesRequest represents a request to retry a webhook's failed deliveries
+type RetryWebhookDeliveriesRequest struct {
+	From time.Time `json:"from" binding:"required"`
+	To   time.Time `json:"to" binding:"required"`
+}
+
+// HandleRetryWebhookDeliveries handles POST /api/v1/webhooks/:webhook_id/deliveries/retry
+func (h *Handler) HandleRetryWebhookDeliveries(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	// Get account ID from context (set by auth middleware)
+	accountID := c.MustGet("Account-ID")
+	parsedAccountID := uuid.MustParse(accountID.(string))
+
+	webhookID, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		h.logger.Error(ctx, "failed to parse webhook_id", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook_id"})
+		return
+	}
+
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	var req RetryWebhookDeliveriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierrors.ValidationError(c, err)
+		return
+	}
+
+	result, err := h.processor.RetryFailedWebhookDeliveries(ctx, parsedAccountID, webhookID, req.From, req.To)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, result)
+}
@@ -46,6 +46,14 @@ func (p *WebhookEventProcessor) Process(ctx context.Context, event workers.Event
 
 	p.logger.Info(ctx, fmt.Sprintf("Processing webhook event %s", event.Type))
 
+	// Parse event ID
+	eventID, err := uuid.Parse(event.ID)
+	if err != nil {
+		p.logger.Error(ctx, "Invalid event id", err)
+		// Return error to prevent offset commit - event will be replayed
+		return fmt.Errorf("invalid event id: %w", err)
+	}
+
 	// Parse account ID
 	accountID, err := uuid.Parse(event.AccountID)
 	if err != nil {
@@ -69,9 +77,9 @@ func (p *WebhookEventProcessor) Process(ctx context.Context, event workers.Event
 	// Dispatch event to subscribed webhooks
 	// This will:
 	// 1. Find all webhooks subscribed to this event type for this account
-	// 2. Create webhook delivery attempts for each
-	// 3. Attempt immediate delivery (with retries on failure via webhook worker)
-	err = p.webhookService.DispatchEvent(ctx, accountID, campaignID, event.Type, event.Data)
+	// 2. Record a webhook delivery for each
+	// 3. Queue the deliveries on their webhooks' delivery queues (with retries on failure via webhook worker)
+	err = p.webhookService.DispatchEvent(ctx, eventID, accountID, campaignID, event.Type, event.Data)
 	if err != nil {
 		p.logger.Error(ctx, "Failed to dispatch event to webhooks", err)
 		// Return error to prevent offset commit - event will be replayed
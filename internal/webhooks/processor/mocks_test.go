@@ -13,6 +13,7 @@ import (
 	store "base-server/internal/store"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -57,6 +58,21 @@ func (mr *MockWebhookStoreMockRecorder) CreateWebhook(ctx, params any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhookStore)(nil).CreateWebhook), ctx, params)
 }
 
+// CreateWebhookDelivery mocks base method.
+func (m *MockWebhookStore) CreateWebhookDelivery(ctx context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDelivery", ctx, params)
+	ret0, _ := ret[0].(store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDelivery indicates an expected call of CreateWebhookDelivery.
+func (mr *MockWebhookStoreMockRecorder) CreateWebhookDelivery(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockWebhookStore)(nil).CreateWebhookDelivery), ctx, params)
+}
+
 // DeleteWebhook mocks base method.
 func (m *MockWebhookStore) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -71,6 +87,36 @@ func (mr *MockWebhookStoreMockRecorder) DeleteWebhook(ctx, webhookID any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookStore)(nil).DeleteWebhook), ctx, webhookID)
 }
 
+// EnableWebhook mocks base method.
+func (m *MockWebhookStore) EnableWebhook(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableWebhook", ctx, webhookID)
+	ret0, _ := ret[0].(store.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableWebhook indicates an expected call of EnableWebhook.
+func (mr *MockWebhookStoreMockRecorder) EnableWebhook(ctx, webhookID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableWebhook", reflect.TypeOf((*MockWebhookStore)(nil).EnableWebhook), ctx, webhookID)
+}
+
+// GetRetryableWebhookDeliveries mocks base method.
+func (m *MockWebhookStore) GetRetryableWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, from, to time.Time, maxAttempt, limit int) ([]store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRetryableWebhookDeliveries", ctx, webhookID, from, to, maxAttempt, limit)
+	ret0, _ := ret[0].([]store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRetryableWebhookDeliveries indicates an expected call of GetRetryableWebhookDeliveries.
+func (mr *MockWebhookStoreMockRecorder) GetRetryableWebhookDeliveries(ctx, webhookID, from, to, maxAttempt, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRetryableWebhookDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).GetRetryableWebhookDeliveries), ctx, webhookID, from, to, maxAttempt, limit)
+}
+
 // GetWebhookByID mocks base method.
 func (m *MockWebhookStore) GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +132,21 @@ func (mr *MockWebhookStoreMockRecorder) GetWebhookByID(ctx, webhookID any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookByID", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhookByID), ctx, webhookID)
 }
 
+// GetWebhookDailyStats mocks base method.
+func (m *MockWebhookStore) GetWebhookDailyStats(ctx context.Context, webhookID uuid.UUID, from, to time.Time) ([]store.WebhookDailyStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDailyStats", ctx, webhookID, from, to)
+	ret0, _ := ret[0].([]store.WebhookDailyStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDailyStats indicates an expected call of GetWebhookDailyStats.
+func (mr *MockWebhookStoreMockRecorder) GetWebhookDailyStats(ctx, webhookID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDailyStats", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhookDailyStats), ctx, webhookID, from, to)
+}
+
 // GetWebhookDeliveriesByWebhook mocks base method.
 func (m *MockWebhookStore) GetWebhookDeliveriesByWebhook(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]store.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
@@ -101,6 +162,36 @@ func (mr *MockWebhookStoreMockRecorder) GetWebhookDeliveriesByWebhook(ctx, webho
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesByWebhook", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhookDeliveriesByWebhook), ctx, webhookID, limit, offset)
 }
 
+// GetWebhookDeliveryByID mocks base method.
+func (m *MockWebhookStore) GetWebhookDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryByID", ctx, deliveryID)
+	ret0, _ := ret[0].(store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryByID indicates an expected call of GetWebhookDeliveryByID.
+func (mr *MockWebhookStoreMockRecorder) GetWebhookDeliveryByID(ctx, deliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryByID", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhookDeliveryByID), ctx, deliveryID)
+}
+
+// GetWebhookDeliveryChain mocks base method.
+func (m *MockWebhookStore) GetWebhookDeliveryChain(ctx context.Context, rootDeliveryID uuid.UUID) ([]store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveryChain", ctx, rootDeliveryID)
+	ret0, _ := ret[0].([]store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveryChain indicates an expected call of GetWebhookDeliveryChain.
+func (mr *MockWebhookStoreMockRecorder) GetWebhookDeliveryChain(ctx, rootDeliveryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveryChain", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhookDeliveryChain), ctx, rootDeliveryID)
+}
+
 // GetWebhooksByAccount mocks base method.
 func (m *MockWebhookStore) GetWebhooksByAccount(ctx context.Context, accountID uuid.UUID) ([]store.Webhook, error) {
 	m.ctrl.T.Helper()
@@ -131,6 +222,21 @@ func (mr *MockWebhookStoreMockRecorder) GetWebhooksByCampaign(ctx, campaignID an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksByCampaign", reflect.TypeOf((*MockWebhookStore)(nil).GetWebhooksByCampaign), ctx, campaignID)
 }
 
+// RotateWebhookSecret mocks base method.
+func (m *MockWebhookStore) RotateWebhookSecret(ctx context.Context, params store.RotateWebhookSecretParams) (store.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateWebhookSecret", ctx, params)
+	ret0, _ := ret[0].(store.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateWebhookSecret indicates an expected call of RotateWebhookSecret.
+func (mr *MockWebhookStoreMockRecorder) RotateWebhookSecret(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateWebhookSecret", reflect.TypeOf((*MockWebhookStore)(nil).RotateWebhookSecret), ctx, params)
+}
+
 // UpdateWebhook mocks base method.
 func (m *MockWebhookStore) UpdateWebhook(ctx context.Context, webhookID uuid.UUID, params store.UpdateWebhookParams) (store.Webhook, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +276,21 @@ func (m *MockWebhookService) EXPECT() *MockWebhookServiceMockRecorder {
 	return m.recorder
 }
 
+// RedeliverDelivery mocks base method.
+func (m *MockWebhookService) RedeliverDelivery(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, webhook, delivery)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockWebhookServiceMockRecorder) RedeliverDelivery(ctx, webhook, delivery any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockWebhookService)(nil).RedeliverDelivery), ctx, webhook, delivery)
+}
+
 // TestWebhook mocks base method.
 func (m *MockWebhookService) TestWebhook(ctx context.Context, webhookID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -183,3 +304,17 @@ func (mr *MockWebhookServiceMockRecorder) TestWebhook(ctx, webhookID any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TestWebhook", reflect.TypeOf((*MockWebhookService)(nil).TestWebhook), ctx, webhookID)
 }
+
+// ValidateURL mocks base method.
+func (m *MockWebhookService) ValidateURL(ctx context.Context, rawURL string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateURL", ctx, rawURL)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ValidateURL indicates an expected call of ValidateURL.
+func (mr *MockWebhookServiceMockRecorder) ValidateURL(ctx, rawURL any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateURL", reflect.TypeOf((*MockWebhookService)(nil).ValidateURL), ctx, rawURL)
+}
@@ -6,18 +6,32 @@ import (
 	"base-server/internal/observability"
 	"base-server/internal/store"
 	"base-server/internal/tiers"
+	"base-server/internal/webhooks/events"
+	"base-server/internal/webhooks/subscription"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 var (
-	ErrWebhooksNotAvailable = errors.New("webhooks are not available in your plan")
-	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrWebhooksNotAvailable   = errors.New("webhooks are not available in your plan")
+	ErrWebhookNotFound        = errors.New("webhook not found")
+	ErrDeliveryNotFound       = errors.New("webhook delivery not found")
+	ErrWebhookInactive        = errors.New("webhook is not active")
+	ErrWebhookFailed          = errors.New("failed webhook must be re-enabled with a test ping")
+	ErrInvalidTimeRange       = errors.New("invalid time range")
+	ErrDeliveryRedacted       = errors.New("webhook delivery payload has been redacted")
+	ErrTestPingFailed         = errors.New("test ping to webhook failed")
+	ErrInvalidWebhookURL      = errors.New("webhook url is not allowed")
+	ErrInvalidRotationOverlap = errors.New("secret rotation overlap must be between 0 and 168 hours")
+	ErrInvalidSignatureFormat = errors.New("signature format must be v1 or standard_webhooks")
+	ErrInvalidFilters         = errors.New("invalid webhook filters")
+	ErrInvalidPayloadVersion  = fmt.Errorf("payload version must be between 1 and %d", events.LatestPayloadVersion)
 )
 
 // WebhookStore defines the database operations required by WebhookProcessor
@@ -29,11 +43,20 @@ type WebhookStore interface {
 	GetWebhooksByCampaign(ctx context.Context, campaignID uuid.UUID) ([]store.Webhook, error)
 	DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error
 	GetWebhookDeliveriesByWebhook(ctx context.Context, webhookID uuid.UUID, limit, offset int) ([]store.WebhookDelivery, error)
+	GetWebhookDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (store.WebhookDelivery, error)
+	GetWebhookDeliveryChain(ctx context.Context, rootDeliveryID uuid.UUID) ([]store.WebhookDelivery, error)
+	GetRetryableWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, from, to time.Time, maxAttempt, limit int) ([]store.WebhookDelivery, error)
+	CreateWebhookDelivery(ctx context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error)
+	EnableWebhook(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error)
+	RotateWebhookSecret(ctx context.Context, params store.RotateWebhookSecretParams) (store.Webhook, error)
+	GetWebhookDailyStats(ctx context.Context, webhookID uuid.UUID, from, to time.Time) ([]store.WebhookDailyStats, error)
 }
 
 // WebhookService defines the webhook operations required by WebhookProcessor
 type WebhookService interface {
 	TestWebhook(ctx context.Context, webhookID uuid.UUID) error
+	ValidateURL(ctx context.Context, rawURL string) error
+	RedeliverDelivery(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) (uuid.UUID, error)
 }
 
 // WebhookProcessor handles webhook business logic
@@ -62,6 +85,14 @@ type CreateWebhookParams struct {
 	Events       []string
 	RetryEnabled bool
 	MaxRetries   int
+	// SignatureFormat is how deliveries are signed, v1 when empty
+	SignatureFormat string
+	// Filters are conditions on the event data every delivered event must meet
+	Filters store.WebhookFilters
+	// OrderedDelivery sends deliveries one at a time in event order
+	OrderedDelivery bool
+	// PayloadVersion pins the event payload version, the latest version when 0
+	PayloadVersion int
 }
 
 // CreateWebhook creates a new webhook
@@ -99,6 +130,29 @@ func (p *WebhookProcessor) CreateWebhook(ctx context.Context, params CreateWebho
 		}
 	}
 
+	if err := p.validateFilters(params.Filters); err != nil {
+		return store.Webhook{}, "", err
+	}
+
+	if err := p.validateURL(ctx, params.URL); err != nil {
+		return store.Webhook{}, "", err
+	}
+
+	signatureFormat := store.WebhookSignatureFormatV1
+	if params.SignatureFormat != "" {
+		signatureFormat = store.WebhookSignatureFormat(params.SignatureFormat)
+		if !p.isValidSignatureFormat(signatureFormat) {
+			return store.Webhook{}, "", ErrInvalidSignatureFormat
+		}
+	}
+
+	if params.PayloadVersion == 0 {
+		params.PayloadVersion = events.LatestPayloadVersion
+	}
+	if !events.IsValidPayloadVersion(params.PayloadVersion) {
+		return store.Webhook{}, "", ErrInvalidPayloadVersion
+	}
+
 	// Set default values
 	if params.MaxRetries == 0 {
 		params.MaxRetries = 5
@@ -106,13 +160,17 @@ func (p *WebhookProcessor) CreateWebhook(ctx context.Context, params CreateWebho
 
 	// Create webhook in database
 	webhook, err := p.store.CreateWebhook(ctx, store.CreateWebhookParams{
-		AccountID:    params.AccountID,
-		CampaignID:   params.CampaignID,
-		URL:          params.URL,
-		Secret:       secret,
-		Events:       params.Events,
-		RetryEnabled: params.RetryEnabled,
-		MaxRetries:   params.MaxRetries,
+		AccountID:       params.AccountID,
+		CampaignID:      params.CampaignID,
+		URL:             params.URL,
+		Secret:          secret,
+		Events:          params.Events,
+		RetryEnabled:    params.RetryEnabled,
+		MaxRetries:      params.MaxRetries,
+		SignatureFormat: signatureFormat,
+		Filters:         params.Filters,
+		OrderedDelivery: params.OrderedDelivery,
+		PayloadVersion:  params.PayloadVersion,
 	})
 	if err != nil {
 		p.logger.Error(ctx, "failed to create webhook", err)
@@ -122,16 +180,21 @@ func (p *WebhookProcessor) CreateWebhook(ctx context.Context, params CreateWebho
 	p.logger.Info(ctx, fmt.Sprintf("created webhook %s", webhook.ID))
 
 	// Return webhook and secret (secret is only returned once)
-	return webhook, secret, nil
+	return webhook, formatSecret(webhook.SignatureFormat, secret), nil
 }
 
 // UpdateWebhookParams represents parameters for updating a webhook
 type UpdateWebhookParams struct {
-	URL          *string
-	Events       []string
-	Status       *string
-	RetryEnabled *bool
-	MaxRetries   *int
+	URL             *string
+	Events          []string
+	Status          *string
+	RetryEnabled    *bool
+	MaxRetries      *int
+	SignatureFormat *string
+	// Filters replaces the webhook's filters when not nil, an empty list removes them
+	Filters         store.WebhookFilters
+	OrderedDelivery *bool
+	PayloadVersion  *int
 }
 
 // UpdateWebhook updates an existing webhook
@@ -152,15 +215,58 @@ func (p *WebhookProcessor) UpdateWebhook(ctx context.Context, webhookID uuid.UUI
 		if !p.isValidStatus(*params.Status) {
 			return store.Webhook{}, fmt.Errorf("invalid status: %s", *params.Status)
 		}
+
+		// Webhooks disabled after repeated failures are only re-enabled by EnableWebhook, once
+		// a test ping succeeds
+		if *params.Status == "active" {
+			current, err := p.store.GetWebhookByID(ctx, webhookID)
+			if err != nil {
+				if errors.Is(err, store.ErrNotFound) {
+					return store.Webhook{}, ErrWebhookNotFound
+				}
+				p.logger.Error(ctx, "failed to get webhook", err)
+				return store.Webhook{}, fmt.Errorf("failed to get webhook: %w", err)
+			}
+			if current.Status == "failed" {
+				return store.Webhook{}, ErrWebhookFailed
+			}
+		}
+	}
+
+	if err := p.validateFilters(params.Filters); err != nil {
+		return store.Webhook{}, err
+	}
+
+	if params.URL != nil {
+		if err := p.validateURL(ctx, *params.URL); err != nil {
+			return store.Webhook{}, err
+		}
+	}
+
+	if params.PayloadVersion != nil && !events.IsValidPayloadVersion(*params.PayloadVersion) {
+		return store.Webhook{}, ErrInvalidPayloadVersion
+	}
+
+	var signatureFormat *store.WebhookSignatureFormat
+	if params.SignatureFormat != nil {
+		format := store.WebhookSignatureFormat(*params.SignatureFormat)
+		if !p.isValidSignatureFormat(format) {
+			return store.Webhook{}, ErrInvalidSignatureFormat
+		}
+		signatureFormat = &format
 	}
 
 	// Update webhook in database
 	webhook, err := p.store.UpdateWebhook(ctx, webhookID, store.UpdateWebhookParams{
-		URL:          params.URL,
-		Events:       params.Events,
-		Status:       params.Status,
-		RetryEnabled: params.RetryEnabled,
-		MaxRetries:   params.MaxRetries,
+		URL:             params.URL,
+		Events:          params.Events,
+		Status:          params.Status,
+		RetryEnabled:    params.RetryEnabled,
+		MaxRetries:      params.MaxRetries,
+		SignatureFormat: signatureFormat,
+		Filters:         params.Filters,
+		OrderedDelivery: params.OrderedDelivery,
+		PayloadVersion:  params.PayloadVersion,
 	})
 	if err != nil {
 		if errors.Is(err, store.ErrNotFound) {
@@ -175,6 +281,18 @@ func (p *WebhookProcessor) UpdateWebhook(ctx context.Context, webhookID uuid.UUI
 	return webhook, nil
 }
 
+// GetEventTypes returns the public event types as delivered in a payload version, with their JSON
+// Schema and a sample payload. Version 0 is the latest version.
+func (p *WebhookProcessor) GetEventTypes(version int) (int, []events.EventTypeInfo, error) {
+	if version == 0 {
+		version = events.LatestPayloadVersion
+	}
+	if !events.IsValidPayloadVersion(version) {
+		return 0, nil, ErrInvalidPayloadVersion
+	}
+	return version, events.Catalog(version), nil
+}
+
 // GetWebhook retrieves a webhook by ID
 func (p *WebhookProcessor) GetWebhook(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error) {
 	webhook, err := p.store.GetWebhookByID(ctx, webhookID)
@@ -263,6 +381,35 @@ func (p *WebhookProcessor) TestWebhook(ctx context.Context, webhookID uuid.UUID)
 	return nil
 }
 
+// EnableWebhook re-enables a paused or disabled webhook. A test event is sent first, and the
+// webhook stays as it is unless the endpoint accepts it. Re-enabling closes the webhook's circuit
+// and resets its failure streak.
+func (p *WebhookProcessor) EnableWebhook(ctx context.Context, accountID, webhookID uuid.UUID) (store.Webhook, error) {
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	if _, err := p.getAccountWebhook(ctx, accountID, webhookID); err != nil {
+		return store.Webhook{}, err
+	}
+
+	if err := p.webhookService.TestWebhook(ctx, webhookID); err != nil {
+		p.logger.Warn(ctx, fmt.Sprintf("test ping failed, webhook not re-enabled: %v", err))
+		return store.Webhook{}, ErrTestPingFailed
+	}
+
+	webhook, err := p.store.EnableWebhook(ctx, webhookID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.Webhook{}, ErrWebhookNotFound
+		}
+		p.logger.Error(ctx, "failed to enable webhook", err)
+		return store.Webhook{}, fmt.Errorf("failed to enable webhook: %w", err)
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("re-enabled webhook %s", webhookID))
+
+	return webhook, nil
+}
+
 // generateSecret generates a random secret for HMAC signing
 func (p *WebhookProcessor) generateSecret() (string, error) {
 	bytes := make([]byte, 32)
@@ -273,37 +420,39 @@ func (p *WebhookProcessor) generateSecret() (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
-// isValidEvent checks if an event type is valid
+// validateURL checks that a webhook URL is allowed by the outbound network policy
+func (p *WebhookProcessor) validateURL(ctx context.Context, rawURL string) error {
+	if err := p.webhookService.ValidateURL(ctx, rawURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookURL, err)
+	}
+	return nil
+}
+
+// validateFilters checks a webhook's payload filters
+func (p *WebhookProcessor) validateFilters(filters store.WebhookFilters) error {
+	if err := subscription.ValidateFilters(filters); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFilters, err)
+	}
+	return nil
+}
+
+// isValidEvent checks if an event type or pattern ("user.*", "*") is valid
 func (p *WebhookProcessor) isValidEvent(eventType string) bool {
-	validEvents := []string{
-		"user.created",
-		"user.updated",
-		"user.verified",
-		"user.deleted",
-		"user.position_changed",
-		"user.converted",
-		"referral.created",
-		"referral.verified",
-		"referral.converted",
-		"reward.earned",
-		"reward.delivered",
-		"reward.redeemed",
-		"campaign.milestone",
-		"campaign.launched",
-		"campaign.completed",
-		"email.sent",
-		"email.delivered",
-		"email.opened",
-		"email.clicked",
-		"email.bounced",
-	}
-
-	for _, valid := range validEvents {
-		if eventType == valid {
-			return true
-		}
+	return subscription.ValidatePatterns([]string{eventType}) == nil
+}
+
+// isValidSignatureFormat checks if a signature format is valid
+func (p *WebhookProcessor) isValidSignatureFormat(format store.WebhookSignatureFormat) bool {
+	return format == store.WebhookSignatureFormatV1 || format == store.WebhookSignatureFormatStandard
+}
+
+// formatSecret returns a webhook secret the way receivers configure it for the signature format.
+// Standard Webhooks verifiers expect the HMAC key base64-encoded with a "whsec_" prefix.
+func formatSecret(format store.WebhookSignatureFormat, secret string) string {
+	if format == store.WebhookSignatureFormatStandard {
+		return "whsec_" + base64.StdEncoding.EncodeToString([]byte(secret))
 	}
-	return false
+	return secret
 }
 
 // isValidStatus checks if a status is valid
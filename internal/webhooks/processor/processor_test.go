@@ -4,7 +4,9 @@ import (
 	"base-server/internal/observability"
 	"base-server/internal/store"
 	"base-server/internal/tiers"
+	"base-server/internal/webhooks/events"
 	"context"
+	"encoding/base64"
 	"errors"
 	"testing"
 
@@ -77,6 +79,7 @@ func TestCreateWebhook_Success(t *testing.T) {
 		MaxRetries:   params.MaxRetries,
 	}
 
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
 	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, p store.CreateWebhookParams) (store.Webhook, error) {
 			if p.AccountID != accountID {
@@ -128,6 +131,7 @@ func TestCreateWebhook_DefaultMaxRetries(t *testing.T) {
 		MaxRetries:   0, // Should default to 5
 	}
 
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
 	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
 		DoAndReturn(func(ctx context.Context, p store.CreateWebhookParams) (store.Webhook, error) {
 			if p.MaxRetries != 5 {
@@ -197,6 +201,179 @@ func TestCreateWebhook_InvalidEvent(t *testing.T) {
 	}
 }
 
+func TestCreateWebhook_URLNotAllowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	params := CreateWebhookParams{
+		AccountID: uuid.New(),
+		URL:       "http://169.254.169.254/latest/meta-data",
+		Events:    []string{"user.created"},
+	}
+
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).
+		Return(errors.New("url resolves to a blocked address: 169.254.169.254"))
+	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := processor.CreateWebhook(context.Background(), params)
+
+	if !errors.Is(err, ErrInvalidWebhookURL) {
+		t.Errorf("expected ErrInvalidWebhookURL, got %v", err)
+	}
+}
+
+func TestCreateWebhook_StandardWebhooksFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	params := CreateWebhookParams{
+		AccountID:       uuid.New(),
+		URL:             "https://example.com/webhook",
+		Events:          []string{"user.created"},
+		SignatureFormat: "standard_webhooks",
+	}
+
+	var storedSecret string
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
+	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p store.CreateWebhookParams) (store.Webhook, error) {
+			if p.SignatureFormat != store.WebhookSignatureFormatStandard {
+				t.Errorf("expected signature format standard_webhooks, got %s", p.SignatureFormat)
+			}
+			storedSecret = p.Secret
+			return store.Webhook{ID: uuid.New(), Secret: p.Secret, SignatureFormat: p.SignatureFormat}, nil
+		})
+
+	_, secret, err := processor.CreateWebhook(context.Background(), params)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Standard Webhooks verifiers take the secret base64-encoded with a whsec_ prefix
+	if want := "whsec_" + base64.StdEncoding.EncodeToString([]byte(storedSecret)); secret != want {
+		t.Errorf("expected secret %s, got %s", want, secret)
+	}
+}
+
+func TestCreateWebhook_InvalidSignatureFormat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	params := CreateWebhookParams{
+		AccountID:       uuid.New(),
+		URL:             "https://example.com/webhook",
+		Events:          []string{"user.created"},
+		SignatureFormat: "v2",
+	}
+
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
+	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := processor.CreateWebhook(context.Background(), params)
+
+	if !errors.Is(err, ErrInvalidSignatureFormat) {
+		t.Errorf("expected ErrInvalidSignatureFormat, got %v", err)
+	}
+}
+
+func TestCreateWebhook_PayloadVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	tests := []struct {
+		name            string
+		payloadVersion  int
+		expectedVersion int
+	}{
+		{name: "defaults to the latest version", payloadVersion: 0, expectedVersion: events.LatestPayloadVersion},
+		{name: "pins an older version", payloadVersion: 1, expectedVersion: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := CreateWebhookParams{
+				AccountID:      uuid.New(),
+				URL:            "https://example.com/webhook",
+				Events:         []string{"user.created"},
+				PayloadVersion: tt.payloadVersion,
+			}
+
+			mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
+			mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, p store.CreateWebhookParams) (store.Webhook, error) {
+					if p.PayloadVersion != tt.expectedVersion {
+						t.Errorf("expected payload version %d, got %d", tt.expectedVersion, p.PayloadVersion)
+					}
+					return store.Webhook{ID: uuid.New()}, nil
+				})
+
+			if _, _, err := processor.CreateWebhook(context.Background(), params); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateWebhook_InvalidPayloadVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	params := CreateWebhookParams{
+		AccountID:      uuid.New(),
+		URL:            "https://example.com/webhook",
+		Events:         []string{"user.created"},
+		PayloadVersion: events.LatestPayloadVersion + 1,
+	}
+
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
+	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := processor.CreateWebhook(context.Background(), params)
+
+	if !errors.Is(err, ErrInvalidPayloadVersion) {
+		t.Errorf("expected ErrInvalidPayloadVersion, got %v", err)
+	}
+}
+
+func TestGetEventTypes(t *testing.T) {
+	processor := New(nil, createTestTierService(), observability.NewLogger(), nil)
+
+	version, eventTypes, err := processor.GetEventTypes(0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if version != events.LatestPayloadVersion {
+		t.Errorf("expected the latest version, got %d", version)
+	}
+	if len(eventTypes) != len(events.PublicEvents) {
+		t.Errorf("expected %d event types, got %d", len(events.PublicEvents), len(eventTypes))
+	}
+
+	if _, _, err := processor.GetEventTypes(events.LatestPayloadVersion + 1); !errors.Is(err, ErrInvalidPayloadVersion) {
+		t.Errorf("expected ErrInvalidPayloadVersion, got %v", err)
+	}
+}
+
 func TestCreateWebhook_StoreError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -218,6 +395,7 @@ func TestCreateWebhook_StoreError(t *testing.T) {
 	}
 
 	storeErr := errors.New("database error")
+	mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
 	mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
 		Return(store.Webhook{}, storeErr)
 
@@ -258,6 +436,7 @@ func TestUpdateWebhook_Success(t *testing.T) {
 		Events: store.StringArray{"user.created", "user.deleted"},
 	}
 
+	mockService.EXPECT().ValidateURL(gomock.Any(), *params.URL).Return(nil)
 	mockStore.EXPECT().UpdateWebhook(gomock.Any(), webhookID, gomock.Any()).
 		Return(expectedWebhook, nil)
 
@@ -323,6 +502,33 @@ func TestUpdateWebhook_InvalidStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateWebhook_ActivateFailedWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	logger := observability.NewLogger()
+
+	processor := New(mockStore, createTestTierService(), logger, mockService)
+
+	ctx := context.Background()
+	webhookID := uuid.New()
+	status := "active"
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, Status: "failed"}, nil)
+	// The webhook is not updated and no test ping is sent
+	mockStore.EXPECT().UpdateWebhook(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	mockService.EXPECT().TestWebhook(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := processor.UpdateWebhook(ctx, webhookID, UpdateWebhookParams{Status: &status})
+
+	if !errors.Is(err, ErrWebhookFailed) {
+		t.Errorf("expected ErrWebhookFailed, got %v", err)
+	}
+}
+
 func TestUpdateWebhook_StoreError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -342,6 +548,7 @@ func TestUpdateWebhook_StoreError(t *testing.T) {
 	}
 
 	storeErr := errors.New("database error")
+	mockService.EXPECT().ValidateURL(gomock.Any(), *params.URL).Return(nil)
 	mockStore.EXPECT().UpdateWebhook(gomock.Any(), webhookID, gomock.Any()).
 		Return(store.Webhook{}, storeErr)
 
@@ -370,6 +577,7 @@ func TestUpdateWebhook_NotFound(t *testing.T) {
 		URL: &newURL,
 	}
 
+	mockService.EXPECT().ValidateURL(gomock.Any(), *params.URL).Return(nil)
 	mockStore.EXPECT().UpdateWebhook(gomock.Any(), webhookID, gomock.Any()).
 		Return(store.Webhook{}, store.ErrNotFound)
 
@@ -847,6 +1055,84 @@ func TestTestWebhook_ServiceError(t *testing.T) {
 	}
 }
 
+func TestEnableWebhook_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	webhookID := uuid.New()
+
+	disabled := store.Webhook{ID: webhookID, AccountID: accountID, Status: "failed", ConsecutiveFailures: 42}
+	enabled := store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}
+
+	gomock.InOrder(
+		mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(disabled, nil),
+		mockService.EXPECT().TestWebhook(gomock.Any(), webhookID).Return(nil),
+		mockStore.EXPECT().EnableWebhook(gomock.Any(), webhookID).Return(enabled, nil),
+	)
+
+	webhook, err := processor.EnableWebhook(ctx, accountID, webhookID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if webhook.Status != "active" {
+		t.Errorf("expected status active, got %s", webhook.Status)
+	}
+}
+
+func TestEnableWebhook_TestPingFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	webhookID := uuid.New()
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: accountID, Status: "failed"}, nil)
+	mockService.EXPECT().TestWebhook(gomock.Any(), webhookID).
+		Return(errors.New("webhook delivery failed: received non-2xx status code: 503"))
+	// The webhook is left disabled
+	mockStore.EXPECT().EnableWebhook(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := processor.EnableWebhook(ctx, accountID, webhookID)
+
+	if !errors.Is(err, ErrTestPingFailed) {
+		t.Errorf("expected ErrTestPingFailed, got %v", err)
+	}
+}
+
+func TestEnableWebhook_OtherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	webhookID := uuid.New()
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: uuid.New(), Status: "failed"}, nil)
+
+	_, err := processor.EnableWebhook(ctx, uuid.New(), webhookID)
+
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
 // Test valid events
 
 func TestCreateWebhook_AllValidEvents(t *testing.T) {
@@ -892,6 +1178,7 @@ func TestCreateWebhook_AllValidEvents(t *testing.T) {
 			Events:    []string{event},
 		}
 
+		mockService.EXPECT().ValidateURL(gomock.Any(), params.URL).Return(nil)
 		mockStore.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).
 			Return(store.Webhook{ID: uuid.New()}, nil)
 
@@ -926,6 +1213,10 @@ func TestUpdateWebhook_AllValidStatuses(t *testing.T) {
 			Status: &statusCopy,
 		}
 
+		if status == "active" {
+			mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+				Return(store.Webhook{ID: webhookID, Status: "paused"}, nil)
+		}
 		mockStore.EXPECT().UpdateWebhook(gomock.Any(), webhookID, gomock.Any()).
 			Return(store.Webhook{ID: webhookID, Status: status}, nil)
 
@@ -0,0 +1,193 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/webhooks/service"
+
+	"github.com/google/uuid"
+)
+
+// maxBulkRetryDeliveries is the largest number of deliveries queued by one bulk retry
+const maxBulkRetryDeliveries = 1000
+
+// DeliveryAttempts is a webhook delivery with every attempt of its chain, oldest first
+type DeliveryAttempts struct {
+	Delivery store.WebhookDelivery   `json:"delivery"`
+	Attempts []store.WebhookDelivery `json:"attempts"`
+}
+
+// BulkRetryResult summarizes a bulk retry of failed deliveries
+type BulkRetryResult struct {
+	Queued  int  `json:"queued"`
+	Skipped int  `json:"skipped"`
+	HasMore bool `json:"has_more"`
+}
+
+// GetWebhookDelivery retrieves one of a webhook's deliveries with its attempt chain
+func (p *WebhookProcessor) GetWebhookDelivery(ctx context.Context, accountID, webhookID, deliveryID uuid.UUID) (DeliveryAttempts, error) {
+	_, delivery, err := p.getWebhookDelivery(ctx, accountID, webhookID, deliveryID)
+	if err != nil {
+		return DeliveryAttempts{}, err
+	}
+
+	return p.getDeliveryAttempts(ctx, delivery)
+}
+
+// RedeliverWebhookDelivery sends a delivery's payload to its webhook again, whatever the status of
+// the delivery and its retries. The redelivery is a new attempt linked to the original delivery.
+func (p *WebhookProcessor) RedeliverWebhookDelivery(ctx context.Context, accountID, webhookID, deliveryID uuid.UUID) (DeliveryAttempts, error) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "webhook_id", Value: webhookID},
+		observability.Field{Key: "delivery_id", Value: deliveryID},
+	)
+
+	webhook, delivery, err := p.getWebhookDelivery(ctx, accountID, webhookID, deliveryID)
+	if err != nil {
+		return DeliveryAttempts{}, err
+	}
+
+	if webhook.Status != "active" {
+		return DeliveryAttempts{}, ErrWebhookInactive
+	}
+
+	// Redacted payloads no longer hold the event as it was sent
+	if delivery.RedactedAt != nil {
+		return DeliveryAttempts{}, ErrDeliveryRedacted
+	}
+
+	redeliveryID, err := p.webhookService.RedeliverDelivery(ctx, webhook, delivery)
+	if redeliveryID == uuid.Nil {
+		p.logger.Error(ctx, "failed to redeliver webhook delivery", err)
+		return DeliveryAttempts{}, fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+	// A failed redelivery is recorded in the attempt chain like any other attempt
+
+	p.logger.Info(ctx, fmt.Sprintf("redelivered webhook delivery %s as %s", deliveryID, redeliveryID))
+
+	redelivery, err := p.store.GetWebhookDeliveryByID(ctx, redeliveryID)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get webhook redelivery", err)
+		return DeliveryAttempts{}, fmt.Errorf("failed to get webhook redelivery: %w", err)
+	}
+
+	return p.getDeliveryAttempts(ctx, redelivery)
+}
+
+// RetryFailedWebhookDeliveries queues a new attempt of each of a webhook's deliveries created in
+// [from, to) that failed with no automatic retries left. Only the latest attempt of each chain is
+// retried, deliveries with redacted payloads are left out, and the queued attempts are sent by the
+// webhook retry worker.
+func (p *WebhookProcessor) RetryFailedWebhookDeliveries(ctx context.Context, accountID, webhookID uuid.UUID, from, to time.Time) (BulkRetryResult, error) {
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	if !from.Before(to) {
+		return BulkRetryResult{}, fmt.Errorf("%w: from must be before to", ErrInvalidTimeRange)
+	}
+
+	webhook, err := p.getAccountWebhook(ctx, accountID, webhookID)
+	if err != nil {
+		return BulkRetryResult{}, err
+	}
+
+	if webhook.Status != "active" {
+		return BulkRetryResult{}, ErrWebhookInactive
+	}
+
+	// Fetch one extra delivery to know whether the window has more than one batch
+	deliveries, err := p.store.GetRetryableWebhookDeliveries(ctx, webhook.ID, from, to, service.MaxDeliveryAttempts, maxBulkRetryDeliveries+1)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get retryable webhook deliveries", err)
+		return BulkRetryResult{}, fmt.Errorf("failed to get retryable webhook deliveries: %w", err)
+	}
+
+	var result BulkRetryResult
+	if len(deliveries) > maxBulkRetryDeliveries {
+		deliveries = deliveries[:maxBulkRetryDeliveries]
+		result.HasMore = true
+	}
+
+	now := time.Now()
+	for _, delivery := range deliveries {
+		_, err := p.store.CreateWebhookDelivery(ctx, store.CreateWebhookDeliveryParams{
+			WebhookID:          webhook.ID,
+			EventID:            delivery.EventID,
+			OriginalDeliveryID: service.RootDeliveryID(delivery),
+			TriggeredBy:        store.WebhookDeliveryTriggerBulkRetry,
+			EventType:          delivery.EventType,
+			Payload:            delivery.Payload,
+			NextRetryAt:        &now,
+		})
+		if err != nil {
+			p.logger.Error(ctx, fmt.Sprintf("failed to queue retry of webhook delivery %s", delivery.ID), err)
+			result.Skipped++
+			continue
+		}
+		result.Queued++
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("queued retry of %d failed webhook deliveries (%d skipped)", result.Queued, result.Skipped))
+
+	return result, nil
+}
+
+// getAccountWebhook retrieves a webhook of an account
+func (p *WebhookProcessor) getAccountWebhook(ctx context.Context, accountID, webhookID uuid.UUID) (store.Webhook, error) {
+	webhook, err := p.GetWebhook(ctx, webhookID)
+	if err != nil {
+		return store.Webhook{}, err
+	}
+
+	// Webhooks of other accounts are reported as missing
+	if webhook.AccountID != accountID || webhook.DeletedAt != nil {
+		return store.Webhook{}, ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+// getWebhookDelivery retrieves a delivery of an account's webhook
+func (p *WebhookProcessor) getWebhookDelivery(ctx context.Context, accountID, webhookID, deliveryID uuid.UUID) (store.Webhook, store.WebhookDelivery, error) {
+	webhook, err := p.getAccountWebhook(ctx, accountID, webhookID)
+	if err != nil {
+		return store.Webhook{}, store.WebhookDelivery{}, err
+	}
+
+	delivery, err := p.store.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.Webhook{}, store.WebhookDelivery{}, ErrDeliveryNotFound
+		}
+		p.logger.Error(ctx, "failed to get webhook delivery", err)
+		return store.Webhook{}, store.WebhookDelivery{}, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	if delivery.WebhookID != webhook.ID {
+		return store.Webhook{}, store.WebhookDelivery{}, ErrDeliveryNotFound
+	}
+
+	return webhook, delivery, nil
+}
+
+// getDeliveryAttempts retrieves the attempt chain of a delivery
+func (p *WebhookProcessor) getDeliveryAttempts(ctx context.Context, delivery store.WebhookDelivery) (DeliveryAttempts, error) {
+	attempts, err := p.store.GetWebhookDeliveryChain(ctx, *service.RootDeliveryID(delivery))
+	if err != nil {
+		p.logger.Error(ctx, "failed to get webhook delivery chain", err)
+		return DeliveryAttempts{}, fmt.Errorf("failed to get webhook delivery chain: %w", err)
+	}
+
+	if attempts == nil {
+		attempts = []store.WebhookDelivery{}
+	}
+
+	return DeliveryAttempts{
+		Delivery: delivery,
+		Attempts: attempts,
+	}, nil
+}
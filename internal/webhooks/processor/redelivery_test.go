@@ -0,0 +1,218 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRedeliverWebhookDelivery_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	rootID := uuid.New()
+	retryID := uuid.New()
+	redeliveryID := uuid.New()
+
+	webhook := store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}
+	// Redelivering a redelivery links the new attempt to the chain's first delivery
+	failedRetry := store.WebhookDelivery{ID: retryID, WebhookID: webhookID, OriginalDeliveryID: &rootID, Status: "failed"}
+	redelivery := store.WebhookDelivery{ID: redeliveryID, WebhookID: webhookID, OriginalDeliveryID: &rootID, Status: "success"}
+	chain := []store.WebhookDelivery{{ID: rootID, WebhookID: webhookID}, failedRetry, redelivery}
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(webhook, nil)
+	mockStore.EXPECT().GetWebhookDeliveryByID(gomock.Any(), retryID).Return(failedRetry, nil)
+	mockService.EXPECT().RedeliverDelivery(gomock.Any(), webhook, failedRetry).Return(redeliveryID, nil)
+	mockStore.EXPECT().GetWebhookDeliveryByID(gomock.Any(), redeliveryID).Return(redelivery, nil)
+	mockStore.EXPECT().GetWebhookDeliveryChain(gomock.Any(), rootID).Return(chain, nil)
+
+	attempts, err := processor.RedeliverWebhookDelivery(ctx, accountID, webhookID, retryID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if attempts.Delivery.ID != redeliveryID {
+		t.Errorf("expected redelivery %s, got %s", redeliveryID, attempts.Delivery.ID)
+	}
+	if len(attempts.Attempts) != 3 {
+		t.Errorf("expected 3 attempts, got %d", len(attempts.Attempts))
+	}
+}
+
+func TestRedeliverWebhookDelivery_FailedAttemptIsReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	redeliveryID := uuid.New()
+
+	webhook := store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}
+	delivery := store.WebhookDelivery{ID: deliveryID, WebhookID: webhookID, Status: "failed"}
+	redelivery := store.WebhookDelivery{ID: redeliveryID, WebhookID: webhookID, OriginalDeliveryID: &deliveryID, Status: "failed"}
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(webhook, nil)
+	mockStore.EXPECT().GetWebhookDeliveryByID(gomock.Any(), deliveryID).Return(delivery, nil)
+	mockService.EXPECT().RedeliverDelivery(gomock.Any(), webhook, delivery).
+		Return(redeliveryID, errors.New("webhook delivery failed: received non-2xx status code: 500"))
+	mockStore.EXPECT().GetWebhookDeliveryByID(gomock.Any(), redeliveryID).Return(redelivery, nil)
+	mockStore.EXPECT().GetWebhookDeliveryChain(gomock.Any(), deliveryID).Return([]store.WebhookDelivery{delivery, redelivery}, nil)
+
+	attempts, err := processor.RedeliverWebhookDelivery(ctx, accountID, webhookID, deliveryID)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if attempts.Delivery.Status != "failed" || len(attempts.Attempts) != 2 {
+		t.Errorf("unexpected attempts: %+v", attempts)
+	}
+}
+
+func TestRedeliverWebhookDelivery_OtherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	webhookID := uuid.New()
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: uuid.New(), Status: "active"}, nil)
+
+	_, err := processor.RedeliverWebhookDelivery(context.Background(), uuid.New(), webhookID, uuid.New())
+
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
+
+func TestRedeliverWebhookDelivery_DeliveryOfOtherWebhook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}, nil)
+	mockStore.EXPECT().GetWebhookDeliveryByID(gomock.Any(), deliveryID).
+		Return(store.WebhookDelivery{ID: deliveryID, WebhookID: uuid.New()}, nil)
+
+	_, err := processor.RedeliverWebhookDelivery(context.Background(), accountID, webhookID, deliveryID)
+
+	if !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestRedeliverWebhookDelivery_Redacted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	redactedAt := time.Now()
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}, nil)
+	mockStore.EXPECT().GetWebhookDeliveryByID(gomock.Any(), deliveryID).
+		Return(store.WebhookDelivery{ID: deliveryID, WebhookID: webhookID, RedactedAt: &redactedAt}, nil)
+	mockService.EXPECT().RedeliverDelivery(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := processor.RedeliverWebhookDelivery(context.Background(), accountID, webhookID, deliveryID)
+
+	if !errors.Is(err, ErrDeliveryRedacted) {
+		t.Errorf("expected ErrDeliveryRedacted, got %v", err)
+	}
+}
+
+func TestRetryFailedWebhookDeliveries_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	rootID := uuid.New()
+	eventID := uuid.New()
+	to := time.Now()
+	from := to.Add(-time.Hour)
+
+	failed := []store.WebhookDelivery{
+		{ID: rootID, WebhookID: webhookID, EventID: &eventID, EventType: "user.created", Status: "failed"},
+		{ID: uuid.New(), WebhookID: webhookID, OriginalDeliveryID: &rootID, EventType: "user.verified", Status: "failed"},
+	}
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}, nil)
+	mockStore.EXPECT().GetRetryableWebhookDeliveries(gomock.Any(), webhookID, from, to, 5, maxBulkRetryDeliveries+1).
+		Return(failed, nil)
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+			if params.OriginalDeliveryID == nil || *params.OriginalDeliveryID != rootID {
+				t.Errorf("expected retry linked to %s, got %v", rootID, params.OriginalDeliveryID)
+			}
+			if params.TriggeredBy != store.WebhookDeliveryTriggerBulkRetry || params.NextRetryAt == nil {
+				t.Errorf("expected queued bulk retry, got %+v", params)
+			}
+			return store.WebhookDelivery{ID: uuid.New()}, nil
+		}).
+		Times(2)
+
+	result, err := processor.RetryFailedWebhookDeliveries(ctx, accountID, webhookID, from, to)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Queued != 2 || result.HasMore {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestRetryFailedWebhookDeliveries_InvalidTimeRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	now := time.Now()
+	_, err := processor.RetryFailedWebhookDeliveries(context.Background(), uuid.New(), uuid.New(), now, now.Add(-time.Hour))
+
+	if !errors.Is(err, ErrInvalidTimeRange) {
+		t.Errorf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultSecretRotationOverlap is how long a rotated secret stays valid when no overlap is given
+	DefaultSecretRotationOverlap = 24 * time.Hour
+	// MaxSecretRotationOverlap is the longest a rotated secret may stay valid
+	MaxSecretRotationOverlap = 7 * 24 * time.Hour
+)
+
+// RotateSecretParams represents parameters for rotating a webhook's signing secret
+type RotateSecretParams struct {
+	AccountID   uuid.UUID
+	WebhookID   uuid.UUID
+	ActorUserID uuid.UUID
+	// Overlap is how long the replaced secret stays valid, DefaultSecretRotationOverlap when nil
+	Overlap   *time.Duration
+	IPAddress *string
+	UserAgent *string
+}
+
+// RotateWebhookSecret generates a new signing secret for a webhook. Until the overlap ends,
+// deliveries are signed with both the new and the replaced secret so receivers can switch
+// without dropping deliveries. The rotation is recorded in the audit log; the new secret is
+// returned only once.
+func (p *WebhookProcessor) RotateWebhookSecret(ctx context.Context, params RotateSecretParams) (store.Webhook, string, error) {
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: params.WebhookID})
+
+	overlap := DefaultSecretRotationOverlap
+	if params.Overlap != nil {
+		overlap = *params.Overlap
+	}
+	if overlap < 0 || overlap > MaxSecretRotationOverlap {
+		return store.Webhook{}, "", ErrInvalidRotationOverlap
+	}
+
+	if _, err := p.getAccountWebhook(ctx, params.AccountID, params.WebhookID); err != nil {
+		return store.Webhook{}, "", err
+	}
+
+	secret, err := p.generateSecret()
+	if err != nil {
+		p.logger.Error(ctx, "failed to generate secret", err)
+		return store.Webhook{}, "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	previousSecretExpiresAt := time.Now().Add(overlap)
+
+	webhook, err := p.store.RotateWebhookSecret(ctx, store.RotateWebhookSecretParams{
+		WebhookID:               params.WebhookID,
+		Secret:                  secret,
+		PreviousSecretExpiresAt: previousSecretExpiresAt,
+		AuditLog: store.CreateAuditLogParams{
+			AccountID:    &params.AccountID,
+			ActorUserID:  &params.ActorUserID,
+			ActorType:    "user",
+			Action:       "webhook.secret_rotated",
+			ResourceType: "webhook",
+			ResourceID:   &params.WebhookID,
+			Changes: store.JSONB{
+				"overlap_seconds":            int(overlap.Seconds()),
+				"previous_secret_expires_at": previousSecretExpiresAt.UTC().Format(time.RFC3339),
+			},
+			IPAddress: params.IPAddress,
+			UserAgent: params.UserAgent,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return store.Webhook{}, "", ErrWebhookNotFound
+		}
+		p.logger.Error(ctx, "failed to rotate webhook secret", err)
+		return store.Webhook{}, "", fmt.Errorf("failed to rotate webhook secret: %w", err)
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("rotated secret of webhook %s, previous secret valid until %s", webhook.ID, previousSecretExpiresAt.UTC().Format(time.RFC3339)))
+
+	return webhook, formatSecret(webhook.SignatureFormat, secret), nil
+}
@@ -0,0 +1,114 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRotateWebhookSecret_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	ctx := context.Background()
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	userID := uuid.New()
+	overlap := 2 * time.Hour
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: accountID, Secret: "old-secret", Status: "active"}, nil)
+
+	var rotatedTo string
+	mockStore.EXPECT().RotateWebhookSecret(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, p store.RotateWebhookSecretParams) (store.Webhook, error) {
+			if p.WebhookID != webhookID {
+				t.Errorf("expected webhook %s, got %s", webhookID, p.WebhookID)
+			}
+			if p.Secret == "" || p.Secret == "old-secret" {
+				t.Errorf("expected a new secret, got %q", p.Secret)
+			}
+			if until := time.Until(p.PreviousSecretExpiresAt); until < overlap-time.Minute || until > overlap {
+				t.Errorf("expected previous secret to expire in %v, got %v", overlap, until)
+			}
+			if p.AuditLog.Action != "webhook.secret_rotated" || *p.AuditLog.ResourceID != webhookID || *p.AuditLog.ActorUserID != userID {
+				t.Errorf("unexpected audit log %+v", p.AuditLog)
+			}
+			for _, value := range p.AuditLog.Changes {
+				if value == p.Secret || value == "old-secret" {
+					t.Error("expected secrets to be kept out of the audit log")
+				}
+			}
+			rotatedTo = p.Secret
+			return store.Webhook{ID: webhookID, AccountID: accountID, Secret: p.Secret}, nil
+		})
+
+	_, secret, err := processor.RotateWebhookSecret(ctx, RotateSecretParams{
+		AccountID:   accountID,
+		WebhookID:   webhookID,
+		ActorUserID: userID,
+		Overlap:     &overlap,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if secret != rotatedTo {
+		t.Errorf("expected the new secret to be returned")
+	}
+}
+
+func TestRotateWebhookSecret_InvalidOverlap(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	overlap := MaxSecretRotationOverlap + time.Hour
+
+	_, _, err := processor.RotateWebhookSecret(context.Background(), RotateSecretParams{
+		AccountID: uuid.New(),
+		WebhookID: uuid.New(),
+		Overlap:   &overlap,
+	})
+
+	if !errors.Is(err, ErrInvalidRotationOverlap) {
+		t.Errorf("expected ErrInvalidRotationOverlap, got %v", err)
+	}
+}
+
+func TestRotateWebhookSecret_OtherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	mockService := NewMockWebhookService(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), mockService)
+
+	webhookID := uuid.New()
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: uuid.New()}, nil)
+	mockStore.EXPECT().RotateWebhookSecret(gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := processor.RotateWebhookSecret(context.Background(), RotateSecretParams{
+		AccountID: uuid.New(),
+		WebhookID: webhookID,
+	})
+
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+// maxStatsDays is the largest number of days of daily stats returned at once
+const maxStatsDays = 365
+
+// GetWebhookDailyStats retrieves a webhook's daily delivery stats for the last days UTC days, today
+// included, oldest first. Stats are rolled up hourly and kept after delivery logs are purged.
+func (p *WebhookProcessor) GetWebhookDailyStats(ctx context.Context, accountID, webhookID uuid.UUID, days int) ([]store.WebhookDailyStats, error) {
+	ctx = observability.WithFields(ctx, observability.Field{Key: "webhook_id", Value: webhookID})
+
+	if days < 1 || days > maxStatsDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidTimeRange, maxStatsDays)
+	}
+
+	webhook, err := p.getAccountWebhook(ctx, accountID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.AddDate(0, 0, -(days - 1))
+
+	stats, err := p.store.GetWebhookDailyStats(ctx, webhook.ID, from, to)
+	if err != nil {
+		p.logger.Error(ctx, "failed to get webhook daily stats", err)
+		return nil, fmt.Errorf("failed to get webhook daily stats: %w", err)
+	}
+
+	if stats == nil {
+		stats = []store.WebhookDailyStats{}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetWebhookDailyStats_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), NewMockWebhookService(ctrl))
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: accountID, Status: "active"}, nil)
+	mockStore.EXPECT().GetWebhookDailyStats(gomock.Any(), webhookID, today.AddDate(0, 0, -6), today).
+		Return([]store.WebhookDailyStats{{WebhookID: webhookID, Day: today, Deliveries: 3, Succeeded: 2, Failed: 1}}, nil)
+
+	stats, err := processor.GetWebhookDailyStats(context.Background(), accountID, webhookID, 7)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(stats) != 1 || stats[0].Deliveries != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestGetWebhookDailyStats_InvalidDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), NewMockWebhookService(ctrl))
+
+	for _, days := range []int{0, maxStatsDays + 1} {
+		_, err := processor.GetWebhookDailyStats(context.Background(), uuid.New(), uuid.New(), days)
+		if !errors.Is(err, ErrInvalidTimeRange) {
+			t.Errorf("expected ErrInvalidTimeRange for %d days, got %v", days, err)
+		}
+	}
+}
+
+func TestGetWebhookDailyStats_OtherAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	processor := New(mockStore, createTestTierService(), observability.NewLogger(), NewMockWebhookService(ctrl))
+
+	webhookID := uuid.New()
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).
+		Return(store.Webhook{ID: webhookID, AccountID: uuid.New()}, nil)
+
+	_, err := processor.GetWebhookDailyStats(context.Background(), uuid.New(), webhookID, 30)
+
+	if !errors.Is(err, ErrWebhookNotFound) {
+		t.Errorf("expected ErrWebhookNotFound, got %v", err)
+	}
+}
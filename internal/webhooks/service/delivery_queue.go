@@ -0,0 +1,338 @@
+package service
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// queuedDeliveryMaxWait is how long a delivery may wait in its webhook's queue. Deliveries waiting
+// longer are skipped and left to the retry worker, which picks up deliveries left unattempted for
+// 15 minutes.
+const queuedDeliveryMaxWait = 10 * time.Minute
+
+// queueIdleTimeout is how long a queue worker waits for a delivery before exiting
+const queueIdleTimeout = time.Minute
+
+// DeliveryConfig configures the per-webhook delivery queues
+type DeliveryConfig struct {
+	// Timeout bounds a single delivery request
+	Timeout time.Duration
+	// MaxInFlight is the number of deliveries sent to one webhook at the same time. Webhooks with
+	// ordered delivery are sent one delivery at a time.
+	MaxInFlight int
+	// QueueSize is the number of deliveries waiting for one webhook. Deliveries beyond it are
+	// left to the retry worker.
+	QueueSize int
+}
+
+// queuedDelivery is a recorded delivery waiting to be sent
+type queuedDelivery struct {
+	webhook  store.Webhook
+	payload  WebhookPayload
+	body     []byte
+	delivery store.WebhookDelivery
+	queuedAt time.Time
+}
+
+// endpointQueue holds the deliveries waiting for one webhook and the workers sending them
+type endpointQueue struct {
+	deliveries chan queuedDelivery
+	// limit is the number of workers allowed, 1 for ordered delivery
+	limit    int
+	workers  int
+	inFlight int
+	// pending counts deliveries queued or in flight
+	pending int
+}
+
+// DeliveryQueue sends recorded deliveries through one queue per webhook, so a slow endpoint only
+// holds up its own deliveries. Each queue is worked by up to MaxInFlight workers, or by a single
+// worker in queue order for webhooks with ordered delivery, where a delivery is only sent once the
+// earlier deliveries succeeded or were dropped. Workers are started on demand and exit
+// once their queue is idle.
+type DeliveryQueue struct {
+	config  DeliveryConfig
+	logger  *observability.Logger
+	deliver func(ctx context.Context, queued queuedDelivery)
+
+	mu       sync.Mutex
+	queues   map[uuid.UUID]*endpointQueue
+	stopped  bool
+	spilled  uint64
+	expired  uint64
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// DeliveryQueueStats is a snapshot of the delivery queues
+type DeliveryQueueStats struct {
+	Endpoints int
+	Queued    int
+	InFlight  int
+	// Spilled counts deliveries left to the retry worker because their queue was full
+	Spilled uint64
+	// Expired counts deliveries left to the retry worker after waiting too long in their queue
+	Expired uint64
+	// Backlogged lists the queues with waiting deliveries, longest first
+	Backlogged []EndpointQueueStats
+}
+
+// EndpointQueueStats is a snapshot of one webhook's queue
+type EndpointQueueStats struct {
+	WebhookID   uuid.UUID
+	Queued      int
+	InFlight    int
+	MaxInFlight int
+}
+
+func newDeliveryQueue(config DeliveryConfig, logger *observability.Logger, deliver func(ctx context.Context, queued queuedDelivery)) *DeliveryQueue {
+	return &DeliveryQueue{
+		config:   config,
+		logger:   logger,
+		deliver:  deliver,
+		queues:   make(map[uuid.UUID]*endpointQueue),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start reports the queue metrics every minute until the queue is stopped
+func (q *DeliveryQueue) Start(ctx context.Context) {
+	q.logger.Info(ctx, "Starting webhook delivery queue")
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			q.reportMetrics(ctx)
+		case <-q.stopChan:
+			q.logger.Info(ctx, "Stopping webhook delivery queue")
+			return
+		case <-ctx.Done():
+			q.logger.Info(ctx, "Context cancelled, stopping webhook delivery queue")
+			return
+		}
+	}
+}
+
+// Stop stops accepting deliveries and waits for the deliveries in flight. Deliveries still queued
+// stay pending and are sent by the retry worker.
+func (q *DeliveryQueue) Stop() {
+	q.mu.Lock()
+	if q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.stopped = true
+	close(q.stopChan)
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+// enqueue adds a delivery to its webhook's queue, starting a worker if the queue has fewer than
+// allowed. It returns false when the queue is full or stopped.
+func (q *DeliveryQueue) enqueue(queued queuedDelivery) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return false
+	}
+
+	webhookID := queued.webhook.ID
+	endpoint, ok := q.queues[webhookID]
+	if !ok {
+		endpoint = &endpointQueue{deliveries: make(chan queuedDelivery, q.config.QueueSize)}
+		q.queues[webhookID] = endpoint
+	}
+
+	// Ordered delivery may be switched on or off between deliveries; extra workers exit after their
+	// current delivery
+	endpoint.limit = q.config.MaxInFlight
+	if queued.webhook.OrderedDelivery {
+		endpoint.limit = 1
+	}
+
+	select {
+	case endpoint.deliveries <- queued:
+	default:
+		q.spilled++
+		return false
+	}
+	endpoint.pending++
+
+	if endpoint.workers < endpoint.limit {
+		endpoint.workers++
+		q.wg.Add(1)
+		go q.work(webhookID, endpoint)
+	}
+
+	return true
+}
+
+// work sends the deliveries of one webhook's queue until the queue is idle or stopped
+func (q *DeliveryQueue) work(webhookID uuid.UUID, endpoint *endpointQueue) {
+	defer q.wg.Done()
+
+	idle := time.NewTimer(queueIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		// Stop takes priority over waiting deliveries
+		select {
+		case <-q.stopChan:
+			q.exitWorker(webhookID, endpoint)
+			return
+		default:
+		}
+
+		select {
+		case queued := <-endpoint.deliveries:
+			q.send(endpoint, queued)
+
+			q.mu.Lock()
+			endpoint.pending--
+			if endpoint.workers > endpoint.limit {
+				q.removeWorker(webhookID, endpoint)
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+			idle.Reset(queueIdleTimeout)
+		case <-idle.C:
+			// Checked under the lock so a delivery queued meanwhile is not left without a worker
+			q.mu.Lock()
+			if len(endpoint.deliveries) == 0 {
+				q.removeWorker(webhookID, endpoint)
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+			idle.Reset(queueIdleTimeout)
+		case <-q.stopChan:
+			q.exitWorker(webhookID, endpoint)
+			return
+		}
+	}
+}
+
+// send attempts one queued delivery, skipping deliveries that waited too long
+func (q *DeliveryQueue) send(endpoint *endpointQueue, queued queuedDelivery) {
+	ctx := observability.WithFields(context.Background(),
+		observability.Field{Key: "webhook_id", Value: queued.webhook.ID},
+		observability.Field{Key: "account_id", Value: queued.webhook.AccountID},
+		observability.Field{Key: "event_type", Value: queued.payload.Type},
+	)
+
+	if time.Since(queued.queuedAt) > queuedDeliveryMaxWait {
+		q.mu.Lock()
+		q.expired++
+		q.mu.Unlock()
+		q.logger.Warn(observability.WithFields(ctx, observability.Field{Key: "delivery_id", Value: queued.delivery.ID}),
+			"webhook delivery waited too long in queue, leaving it to the retry worker")
+		return
+	}
+
+	q.mu.Lock()
+	endpoint.inFlight++
+	q.mu.Unlock()
+
+	q.deliver(ctx, queued)
+
+	q.mu.Lock()
+	endpoint.inFlight--
+	q.mu.Unlock()
+}
+
+// exitWorker removes a stopped worker from its queue
+func (q *DeliveryQueue) exitWorker(webhookID uuid.UUID, endpoint *endpointQueue) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.removeWorker(webhookID, endpoint)
+}
+
+// removeWorker removes a worker from its queue, and the queue once it has no workers left and
+// nothing to send. The lock must be held.
+func (q *DeliveryQueue) removeWorker(webhookID uuid.UUID, endpoint *endpointQueue) {
+	endpoint.workers--
+	if endpoint.workers == 0 && endpoint.pending == 0 && q.queues[webhookID] == endpoint {
+		delete(q.queues, webhookID)
+	}
+}
+
+// Stats returns a snapshot of the delivery queues
+func (q *DeliveryQueue) Stats() DeliveryQueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := DeliveryQueueStats{
+		Endpoints: len(q.queues),
+		Spilled:   q.spilled,
+		Expired:   q.expired,
+	}
+	for webhookID, endpoint := range q.queues {
+		queued := len(endpoint.deliveries)
+		stats.Queued += queued
+		stats.InFlight += endpoint.inFlight
+		if queued > 0 {
+			stats.Backlogged = append(stats.Backlogged, EndpointQueueStats{
+				WebhookID:   webhookID,
+				Queued:      queued,
+				InFlight:    endpoint.inFlight,
+				MaxInFlight: endpoint.limit,
+			})
+		}
+	}
+
+	sort.Slice(stats.Backlogged, func(i, j int) bool {
+		return stats.Backlogged[i].Queued > stats.Backlogged[j].Queued
+	})
+
+	return stats
+}
+
+// pending returns the number of deliveries queued or in flight
+func (q *DeliveryQueue) pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	pending := 0
+	for _, endpoint := range q.queues {
+		pending += endpoint.pending
+	}
+	return pending
+}
+
+// reportMetrics logs the queue totals, and the queue depth of the ten most backlogged webhooks
+func (q *DeliveryQueue) reportMetrics(ctx context.Context) {
+	stats := q.Stats()
+
+	q.logger.Metrics(ctx,
+		observability.MetricField{Key: "webhook_queue_endpoints", Value: stats.Endpoints},
+		observability.MetricField{Key: "webhook_queue_depth", Value: stats.Queued},
+		observability.MetricField{Key: "webhook_queue_in_flight", Value: stats.InFlight},
+		observability.MetricField{Key: "webhook_queue_spilled_total", Value: stats.Spilled},
+		observability.MetricField{Key: "webhook_queue_expired_total", Value: stats.Expired},
+	)
+
+	for i, endpoint := range stats.Backlogged {
+		if i == 10 {
+			break
+		}
+		q.logger.Metrics(ctx,
+			observability.MetricField{Key: "webhook_id", Value: endpoint.WebhookID},
+			observability.MetricField{Key: "webhook_queue_depth", Value: endpoint.Queued},
+			observability.MetricField{Key: "webhook_queue_in_flight", Value: endpoint.InFlight},
+			observability.MetricField{Key: "webhook_queue_max_in_flight", Value: endpoint.MaxInFlight},
+		)
+	}
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// concurrencyRecorder records the deliveries sent and the highest number sent at the same time
+type concurrencyRecorder struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	sent        []int
+}
+
+func (r *concurrencyRecorder) deliver(delay time.Duration) func(ctx context.Context, queued queuedDelivery) {
+	return func(ctx context.Context, queued queuedDelivery) {
+		r.mu.Lock()
+		r.inFlight++
+		if r.inFlight > r.maxInFlight {
+			r.maxInFlight = r.inFlight
+		}
+		r.mu.Unlock()
+
+		time.Sleep(delay)
+
+		r.mu.Lock()
+		r.inFlight--
+		r.sent = append(r.sent, queued.delivery.AttemptNumber)
+		r.mu.Unlock()
+	}
+}
+
+func waitForQueue(t *testing.T, queue *DeliveryQueue) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for queue.pending() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for queued deliveries")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// testDelivery builds a queued delivery numbered by its attempt number
+func testDelivery(webhook store.Webhook, n int) queuedDelivery {
+	return queuedDelivery{
+		webhook:  webhook,
+		delivery: store.WebhookDelivery{ID: uuid.New(), WebhookID: webhook.ID, AttemptNumber: n},
+		queuedAt: time.Now(),
+	}
+}
+
+func TestDeliveryQueue_OrderedDelivery(t *testing.T) {
+	recorder := &concurrencyRecorder{}
+	queue := newDeliveryQueue(DeliveryConfig{MaxInFlight: 4, QueueSize: 20}, observability.NewLogger(), recorder.deliver(2*time.Millisecond))
+
+	webhook := store.Webhook{ID: uuid.New(), OrderedDelivery: true}
+	for i := 0; i < 10; i++ {
+		if !queue.enqueue(testDelivery(webhook, i)) {
+			t.Fatalf("Expected delivery %d to be queued", i)
+		}
+	}
+	waitForQueue(t, queue)
+
+	if recorder.maxInFlight != 1 {
+		t.Errorf("Expected one delivery in flight at a time, got %d", recorder.maxInFlight)
+	}
+	for i, n := range recorder.sent {
+		if n != i {
+			t.Fatalf("Expected deliveries in queue order, got %v", recorder.sent)
+		}
+	}
+}
+
+func TestDeliveryQueue_BoundsInFlightPerEndpoint(t *testing.T) {
+	recorder := &concurrencyRecorder{}
+	queue := newDeliveryQueue(DeliveryConfig{MaxInFlight: 3, QueueSize: 20}, observability.NewLogger(), recorder.deliver(20*time.Millisecond))
+
+	webhook := store.Webhook{ID: uuid.New()}
+	for i := 0; i < 12; i++ {
+		queue.enqueue(testDelivery(webhook, i))
+	}
+	waitForQueue(t, queue)
+
+	if recorder.maxInFlight != 3 {
+		t.Errorf("Expected 3 deliveries in flight at most, got %d", recorder.maxInFlight)
+	}
+	if len(recorder.sent) != 12 {
+		t.Errorf("Expected 12 deliveries sent, got %d", len(recorder.sent))
+	}
+}
+
+func TestDeliveryQueue_SlowEndpointDoesNotBlockOthers(t *testing.T) {
+	slow := store.Webhook{ID: uuid.New()}
+	fast := store.Webhook{ID: uuid.New()}
+
+	release := make(chan struct{})
+	fastSent := make(chan struct{})
+	queue := newDeliveryQueue(DeliveryConfig{MaxInFlight: 1, QueueSize: 10}, observability.NewLogger(), func(ctx context.Context, queued queuedDelivery) {
+		if queued.webhook.ID == slow.ID {
+			<-release
+			return
+		}
+		close(fastSent)
+	})
+	defer close(release)
+
+	queue.enqueue(testDelivery(slow, 0))
+	queue.enqueue(testDelivery(slow, 1))
+	queue.enqueue(testDelivery(fast, 0))
+
+	select {
+	case <-fastSent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the fast endpoint to be delivered while the slow one is stuck")
+	}
+}
+
+func TestDeliveryQueue_FullQueueIsRejected(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	queue := newDeliveryQueue(DeliveryConfig{MaxInFlight: 1, QueueSize: 1}, observability.NewLogger(), func(ctx context.Context, queued queuedDelivery) {
+		started <- struct{}{}
+		<-release
+	})
+
+	webhook := store.Webhook{ID: uuid.New()}
+	queue.enqueue(testDelivery(webhook, 0))
+	<-started
+
+	if !queue.enqueue(testDelivery(webhook, 1)) {
+		t.Fatal("Expected the second delivery to wait in the queue")
+	}
+	if queue.enqueue(testDelivery(webhook, 2)) {
+		t.Fatal("Expected the third delivery to be rejected by the full queue")
+	}
+
+	stats := queue.Stats()
+	if stats.Queued != 1 || stats.InFlight != 1 || stats.Spilled != 1 {
+		t.Errorf("Expected 1 queued, 1 in flight and 1 spilled, got %+v", stats)
+	}
+	if len(stats.Backlogged) != 1 || stats.Backlogged[0].WebhookID != webhook.ID {
+		t.Errorf("Expected the webhook's queue to be reported as backlogged, got %+v", stats.Backlogged)
+	}
+
+	close(release)
+	<-started
+	waitForQueue(t, queue)
+}
+
+func TestDeliveryQueue_StopRejectsDeliveries(t *testing.T) {
+	queue := newDeliveryQueue(DeliveryConfig{MaxInFlight: 1, QueueSize: 1}, observability.NewLogger(), func(ctx context.Context, queued queuedDelivery) {})
+	queue.Stop()
+
+	if queue.enqueue(testDelivery(store.Webhook{ID: uuid.New()}, 0)) {
+		t.Error("Expected a stopped queue to reject deliveries")
+	}
+}
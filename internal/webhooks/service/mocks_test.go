@@ -43,6 +43,21 @@ func (m *MockWebhookStore) EXPECT() *MockWebhookStoreMockRecorder {
 	return m.recorder
 }
 
+// ClaimWebhookProbe mocks base method.
+func (m *MockWebhookStore) ClaimWebhookProbe(ctx context.Context, webhookID uuid.UUID, nextProbeAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookProbe", ctx, webhookID, nextProbeAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookProbe indicates an expected call of ClaimWebhookProbe.
+func (mr *MockWebhookStoreMockRecorder) ClaimWebhookProbe(ctx, webhookID, nextProbeAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookProbe", reflect.TypeOf((*MockWebhookStore)(nil).ClaimWebhookProbe), ctx, webhookID, nextProbeAt)
+}
+
 // CreateWebhookDelivery mocks base method.
 func (m *MockWebhookStore) CreateWebhookDelivery(ctx context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
@@ -58,6 +73,35 @@ func (mr *MockWebhookStoreMockRecorder) CreateWebhookDelivery(ctx, params any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDelivery", reflect.TypeOf((*MockWebhookStore)(nil).CreateWebhookDelivery), ctx, params)
 }
 
+// DeferWebhookDelivery mocks base method.
+func (m *MockWebhookStore) DeferWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, nextRetryAt *time.Time, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeferWebhookDelivery", ctx, deliveryID, nextRetryAt, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeferWebhookDelivery indicates an expected call of DeferWebhookDelivery.
+func (mr *MockWebhookStoreMockRecorder) DeferWebhookDelivery(ctx, deliveryID, nextRetryAt, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeferWebhookDelivery", reflect.TypeOf((*MockWebhookStore)(nil).DeferWebhookDelivery), ctx, deliveryID, nextRetryAt, reason)
+}
+
+// GetOldestUnfinishedWebhookDelivery mocks base method.
+func (m *MockWebhookStore) GetOldestUnfinishedWebhookDelivery(ctx context.Context, webhookID uuid.UUID, maxAttempt int) (store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldestUnfinishedWebhookDelivery", ctx, webhookID, maxAttempt)
+	ret0, _ := ret[0].(store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestUnfinishedWebhookDelivery indicates an expected call of GetOldestUnfinishedWebhookDelivery.
+func (mr *MockWebhookStoreMockRecorder) GetOldestUnfinishedWebhookDelivery(ctx, webhookID, maxAttempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldestUnfinishedWebhookDelivery", reflect.TypeOf((*MockWebhookStore)(nil).GetOldestUnfinishedWebhookDelivery), ctx, webhookID, maxAttempt)
+}
+
 // GetPendingWebhookDeliveries mocks base method.
 func (m *MockWebhookStore) GetPendingWebhookDeliveries(ctx context.Context, limit, maxAttempt int) ([]store.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +189,21 @@ func (mr *MockWebhookStoreMockRecorder) IncrementWebhookSent(ctx, webhookID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementWebhookSent", reflect.TypeOf((*MockWebhookStore)(nil).IncrementWebhookSent), ctx, webhookID)
 }
 
+// RecordWebhookAttemptFailure mocks base method.
+func (m *MockWebhookStore) RecordWebhookAttemptFailure(ctx context.Context, webhookID uuid.UUID, threshold int, nextProbeAt time.Time) (store.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWebhookAttemptFailure", ctx, webhookID, threshold, nextProbeAt)
+	ret0, _ := ret[0].(store.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWebhookAttemptFailure indicates an expected call of RecordWebhookAttemptFailure.
+func (mr *MockWebhookStoreMockRecorder) RecordWebhookAttemptFailure(ctx, webhookID, threshold, nextProbeAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookAttemptFailure", reflect.TypeOf((*MockWebhookStore)(nil).RecordWebhookAttemptFailure), ctx, webhookID, threshold, nextProbeAt)
+}
+
 // UpdateWebhookDeliveryStatus mocks base method.
 func (m *MockWebhookStore) UpdateWebhookDeliveryStatus(ctx context.Context, deliveryID uuid.UUID, params store.UpdateWebhookDeliveryStatusParams) error {
 	m.ctrl.T.Helper()
@@ -4,16 +4,23 @@ package service
 
 import (
 	"base-server/internal/observability"
+	"base-server/internal/outbound"
 	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
+	"base-server/internal/webhooks/subscription"
 	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,6 +36,31 @@ type WebhookStore interface {
 	IncrementDeliveryAttempt(ctx context.Context, deliveryID uuid.UUID, nextRetryAt *time.Time) error
 	GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (store.Webhook, error)
 	GetPendingWebhookDeliveries(ctx context.Context, limit int, maxAttempt int) ([]store.WebhookDelivery, error)
+	RecordWebhookAttemptFailure(ctx context.Context, webhookID uuid.UUID, threshold int, nextProbeAt time.Time) (store.Webhook, error)
+	ClaimWebhookProbe(ctx context.Context, webhookID uuid.UUID, nextProbeAt time.Time) (bool, error)
+	DeferWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, nextRetryAt *time.Time, reason string) error
+	GetOldestUnfinishedWebhookDelivery(ctx context.Context, webhookID uuid.UUID, maxAttempt int) (store.WebhookDelivery, error)
+}
+
+// MaxDeliveryAttempts is the attempt number after which a delivery is no longer retried automatically
+const MaxDeliveryAttempts = 5
+
+// ErrCircuitOpen is returned for deliveries deferred because their webhook's circuit is open
+var ErrCircuitOpen = errors.New("webhook circuit is open")
+
+// ErrQueueFull is recorded on deliveries left to the retry worker because their webhook's queue is full
+var ErrQueueFull = errors.New("webhook delivery queue is full")
+
+// ErrWaitingForEarlierDelivery is recorded on deliveries to ordered webhooks deferred until the
+// earlier deliveries of the webhook succeeded or were dropped
+var ErrWaitingForEarlierDelivery = errors.New("waiting for an earlier delivery to the ordered webhook")
+
+// CircuitBreakerConfig configures the per-webhook circuit breaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed attempts that opens a webhook's circuit
+	FailureThreshold int
+	// ProbeInterval is the time between two probe attempts while a webhook's circuit is open
+	ProbeInterval time.Duration
 }
 
 // WebhookService handles webhook delivery operations
@@ -36,17 +68,50 @@ type WebhookService struct {
 	store      WebhookStore
 	logger     *observability.Logger
 	httpClient *http.Client
+	breaker    CircuitBreakerConfig
+	policy     outbound.Policy
+	queue      *DeliveryQueue
 }
 
-// New creates a new WebhookService
-func New(store WebhookStore, logger *observability.Logger) *WebhookService {
-	return &WebhookService{
-		store:  store,
-		logger: logger,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+// New creates a new WebhookService. Deliveries are sent with a client enforcing the outbound
+// network policy, and event deliveries go through per-webhook queues.
+func New(store WebhookStore, logger *observability.Logger, breaker CircuitBreakerConfig, policy outbound.Policy, delivery DeliveryConfig) *WebhookService {
+	if breaker.FailureThreshold <= 0 {
+		breaker.FailureThreshold = 10
+	}
+	if breaker.ProbeInterval <= 0 {
+		breaker.ProbeInterval = 5 * time.Minute
+	}
+	if delivery.Timeout <= 0 {
+		delivery.Timeout = 10 * time.Second
+	}
+	if delivery.MaxInFlight <= 0 {
+		delivery.MaxInFlight = 4
+	}
+	if delivery.QueueSize <= 0 {
+		delivery.QueueSize = 100
 	}
+
+	s := &WebhookService{
+		store:      store,
+		logger:     logger,
+		httpClient: outbound.NewClient(policy, delivery.Timeout),
+		breaker:    breaker,
+		policy:     policy,
+	}
+	s.queue = newDeliveryQueue(delivery, logger, s.sendQueuedDelivery)
+
+	return s
+}
+
+// DeliveryQueue returns the queue sending event deliveries, to be started and stopped with the server
+func (s *WebhookService) DeliveryQueue() *DeliveryQueue {
+	return s.queue
+}
+
+// ValidateURL checks that a webhook URL may be delivered to under the outbound network policy
+func (s *WebhookService) ValidateURL(ctx context.Context, rawURL string) error {
+	return s.policy.ValidateURL(ctx, rawURL)
 }
 
 // WebhookPayload represents the standard webhook payload structure
@@ -56,10 +121,32 @@ type WebhookPayload struct {
 	CreatedAt string                 `json:"created_at"`
 	Data      map[string]interface{} `json:"data"`
 	AccountID string                 `json:"account_id"`
+	// SchemaVersion is the payload version the webhook is pinned to
+	SchemaVersion int `json:"schema_version"`
+}
+
+// newPayload builds the payload of an event for a webhook, with the event data in the payload
+// version the webhook is pinned to
+func newPayload(webhook store.Webhook, id string, eventType string, data map[string]interface{}) WebhookPayload {
+	version := webhook.PayloadVersion
+	if version == 0 {
+		version = 1
+	}
+
+	return WebhookPayload{
+		ID:            id,
+		Type:          eventType,
+		CreatedAt:     time.Now().UTC().Format(time.RFC3339),
+		Data:          events.FormatData(eventType, version, data),
+		AccountID:     webhook.AccountID.String(),
+		SchemaVersion: version,
+	}
 }
 
-// DispatchEvent dispatches a webhook event to all subscribed webhooks
-func (s *WebhookService) DispatchEvent(ctx context.Context, accountID uuid.UUID, campaignID *uuid.UUID, eventType string, data map[string]interface{}) error {
+// DispatchEvent records a delivery of a webhook event for each subscribed webhook and queues it.
+// The deliveries are sent by the webhooks' delivery queues, so DispatchEvent does not wait for
+// endpoints. The event ID is sent as the payload ID so receivers can deduplicate retries and replays.
+func (s *WebhookService) DispatchEvent(ctx context.Context, eventID uuid.UUID, accountID uuid.UUID, campaignID *uuid.UUID, eventType string, data map[string]interface{}) error {
 	// Get all webhooks for the account
 	webhooks, err := s.store.GetWebhooksByAccount(ctx, accountID)
 	if err != nil {
@@ -67,7 +154,8 @@ func (s *WebhookService) DispatchEvent(ctx context.Context, accountID uuid.UUID,
 		return fmt.Errorf("failed to get webhooks: %w", err)
 	}
 
-	// Filter webhooks by campaign if specified
+	// Select the webhooks receiving the event. Filters are evaluated here so that events a
+	// webhook filters out never create a delivery.
 	var relevantWebhooks []store.Webhook
 	for _, webhook := range webhooks {
 		// Skip deleted or paused webhooks
@@ -75,80 +163,152 @@ func (s *WebhookService) DispatchEvent(ctx context.Context, accountID uuid.UUID,
 			continue
 		}
 
-		// Check if webhook is for this campaign or account-level
-		if campaignID != nil && webhook.CampaignID != nil && *webhook.CampaignID != *campaignID {
-			continue
-		}
-
-		// Check if webhook subscribes to this event type
-		if !s.subscribesToEvent(webhook.Events, eventType) {
+		// Check campaign scope, event patterns and filters
+		if !subscription.Matches(webhook, eventType, campaignID, data) {
 			continue
 		}
 
 		relevantWebhooks = append(relevantWebhooks, webhook)
 	}
 
-	// Create webhook payload
-	payload := WebhookPayload{
-		ID:        uuid.New().String(),
-		Type:      eventType,
-		CreatedAt: time.Now().UTC().Format(time.RFC3339),
-		Data:      data,
-		AccountID: accountID.String(),
-	}
-
-	// Send to each webhook
+	// Queue a delivery for each webhook, in the payload version it is pinned to
 	for _, webhook := range relevantWebhooks {
-		err := s.sendWebhook(ctx, webhook, payload)
+		payload := newPayload(webhook, eventID.String(), eventType, data)
+		err := s.queueDelivery(ctx, webhook, payload, deliveryOrigin{
+			EventID:     &eventID,
+			TriggeredBy: store.WebhookDeliveryTriggerEvent,
+		})
 		if err != nil {
-			s.logger.Error(ctx, fmt.Sprintf("failed to send webhook to %s", webhook.URL), err)
-			// Continue sending to other webhooks even if one fails
+			s.logger.Error(ctx, fmt.Sprintf("failed to queue webhook to %s", webhook.URL), err)
+			// Continue queueing to other webhooks even if one fails
 		}
 	}
 
 	return nil
 }
 
-// subscribesToEvent checks if a webhook subscribes to a specific event type
-func (s *WebhookService) subscribesToEvent(subscribedEvents []string, eventType string) bool {
-	for _, event := range subscribedEvents {
-		if event == eventType {
-			return true
-		}
+// queueDelivery records a delivery and adds it to its webhook's queue. When the queue is full the
+// delivery is scheduled for the retry worker instead, so a backed up endpoint never blocks the caller.
+func (s *WebhookService) queueDelivery(ctx context.Context, webhook store.Webhook, payload WebhookPayload, origin deliveryOrigin) error {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "webhook_id", Value: webhook.ID},
+		observability.Field{Key: "event_type", Value: payload.Type},
+	)
+
+	body, delivery, err := s.recordDelivery(ctx, webhook, payload, origin)
+	if err != nil {
+		return err
 	}
-	return false
+
+	queued := s.queue.enqueue(queuedDelivery{
+		webhook:  webhook,
+		payload:  payload,
+		body:     body,
+		delivery: delivery,
+		queuedAt: time.Now(),
+	})
+	if queued {
+		return nil
+	}
+
+	s.logger.Warn(observability.WithFields(ctx, observability.Field{Key: "delivery_id", Value: delivery.ID}),
+		"webhook delivery queue is full, leaving delivery to the retry worker")
+
+	now := time.Now()
+	if err := s.store.DeferWebhookDelivery(ctx, delivery.ID, &now, ErrQueueFull.Error()); err != nil {
+		s.logger.Error(ctx, "failed to defer webhook delivery", err)
+	}
+
+	return nil
+}
+
+// sendQueuedDelivery attempts a delivery taken from its webhook's queue
+func (s *WebhookService) sendQueuedDelivery(ctx context.Context, queued queuedDelivery) {
+	if !s.isNextInOrder(ctx, queued.webhook, queued.delivery) {
+		return
+	}
+
+	_, err := s.attemptDelivery(ctx, queued.webhook, queued.payload, queued.body, queued.delivery, store.WebhookDeliveryTriggerEvent)
+	if err != nil && !errors.Is(err, ErrCircuitOpen) {
+		s.logger.Error(ctx, fmt.Sprintf("failed to send webhook to %s", queued.webhook.URL), err)
+	}
+}
+
+// subscribesToEvent checks if a webhook subscribes to a specific event type, exactly or by pattern
+func (s *WebhookService) subscribesToEvent(subscribedEvents []string, eventType string) bool {
+	return subscription.SubscribesTo(subscribedEvents, eventType)
+}
+
+// deliveryOrigin describes where a delivery comes from
+type deliveryOrigin struct {
+	// EventID is the logged event delivered, nil for test events
+	EventID *uuid.UUID
+	// OriginalDeliveryID is the first delivery of the attempt chain, nil for new deliveries
+	OriginalDeliveryID *uuid.UUID
+	TriggeredBy        store.WebhookDeliveryTrigger
 }
 
-// sendWebhook sends a webhook to a specific endpoint
-func (s *WebhookService) sendWebhook(ctx context.Context, webhook store.Webhook, payload WebhookPayload) error {
+// sendWebhook sends a webhook to a specific endpoint.
+// It returns the ID of the delivery record, which is set whenever the record was created, even if
+// the delivery failed.
+func (s *WebhookService) sendWebhook(ctx context.Context, webhook store.Webhook, payload WebhookPayload, origin deliveryOrigin) (uuid.UUID, error) {
 	ctx = observability.WithFields(ctx,
 		observability.Field{Key: "webhook_id", Value: webhook.ID},
 		observability.Field{Key: "event_type", Value: payload.Type},
 	)
 
+	payloadBytes, delivery, err := s.recordDelivery(ctx, webhook, payload, origin)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return s.attemptDelivery(ctx, webhook, payload, payloadBytes, delivery, origin.TriggeredBy)
+}
+
+// recordDelivery serializes a payload and creates the delivery record for it
+func (s *WebhookService) recordDelivery(ctx context.Context, webhook store.Webhook, payload WebhookPayload, origin deliveryOrigin) ([]byte, store.WebhookDelivery, error) {
 	// Serialize payload
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		s.logger.Error(ctx, "failed to marshal payload", err)
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return nil, store.WebhookDelivery{}, fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
 	// Create webhook delivery record
 	delivery, err := s.store.CreateWebhookDelivery(ctx, store.CreateWebhookDeliveryParams{
-		WebhookID:   webhook.ID,
-		EventType:   payload.Type,
-		Payload:     store.JSONB(payload.Data),
-		NextRetryAt: nil, // Will be set on failure
+		WebhookID:          webhook.ID,
+		EventID:            origin.EventID,
+		OriginalDeliveryID: origin.OriginalDeliveryID,
+		TriggeredBy:        origin.TriggeredBy,
+		EventType:          payload.Type,
+		Payload:            store.JSONB(payload.Data),
+		NextRetryAt:        nil, // Will be set on failure
 	})
 	if err != nil {
 		s.logger.Error(ctx, "failed to create webhook delivery", err)
-		return fmt.Errorf("failed to create webhook delivery: %w", err)
+		return nil, store.WebhookDelivery{}, fmt.Errorf("failed to create webhook delivery: %w", err)
 	}
 
+	return payloadBytes, delivery, nil
+}
+
+// attemptDelivery sends a recorded delivery and records its outcome. It returns the ID of the
+// delivery record, even if the delivery failed.
+func (s *WebhookService) attemptDelivery(ctx context.Context, webhook store.Webhook, payload WebhookPayload, payloadBytes []byte, delivery store.WebhookDelivery, triggeredBy store.WebhookDeliveryTrigger) (uuid.UUID, error) {
 	ctx = observability.WithFields(ctx, observability.Field{Key: "delivery_id", Value: delivery.ID})
 
+	// Test deliveries are manual probes and always attempted
+	if triggeredBy != store.WebhookDeliveryTriggerTest {
+		if err := s.checkCircuit(ctx, webhook, delivery); err != nil {
+			return delivery.ID, err
+		}
+	}
+
 	// Attempt delivery
-	success, responseStatus, responseBody, durationMs, err := s.deliverWebhook(ctx, webhook, payloadBytes)
+	success, responseStatus, responseBody, durationMs, err := s.deliverWebhook(ctx, webhook, payload.ID, payloadBytes)
+	if !success {
+		s.recordAttemptFailure(ctx, webhook)
+	}
 
 	if success {
 		// Update delivery status as success
@@ -170,7 +330,7 @@ func (s *WebhookService) sendWebhook(ctx context.Context, webhook store.Webhook,
 		}
 
 		s.logger.Info(ctx, "webhook delivered successfully")
-		return nil
+		return delivery.ID, nil
 	}
 
 	// Handle failure
@@ -214,16 +374,14 @@ func (s *WebhookService) sendWebhook(ctx context.Context, webhook store.Webhook,
 		s.logger.Error(ctx, "webhook delivery failed, no more retries", fmt.Errorf("max retries reached"))
 	}
 
-	return fmt.Errorf("webhook delivery failed: %s", errorMessage)
+	return delivery.ID, fmt.Errorf("webhook delivery failed: %s", errorMessage)
 }
 
-// deliverWebhook performs the actual HTTP request to deliver the webhook
-func (s *WebhookService) deliverWebhook(ctx context.Context, webhook store.Webhook, payloadBytes []byte) (success bool, responseStatus int, responseBody string, durationMs int, err error) {
+// deliverWebhook performs the actual HTTP request to deliver the webhook. The message ID is the
+// payload ID, which stays the same across retries of a delivery.
+func (s *WebhookService) deliverWebhook(ctx context.Context, webhook store.Webhook, messageID string, payloadBytes []byte) (success bool, responseStatus int, responseBody string, durationMs int, err error) {
 	startTime := time.Now()
 
-	// Generate HMAC signature
-	signature := s.generateSignature(webhook.Secret, payloadBytes, startTime.Unix())
-
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "POST", webhook.URL, io.NopCloser(bytes.NewReader(payloadBytes)))
 	if err != nil {
@@ -232,8 +390,8 @@ func (s *WebhookService) deliverWebhook(ctx context.Context, webhook store.Webho
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Webhook-Signature", signature)
 	req.Header.Set("User-Agent", "Waitlist-Platform-Webhook/1.0")
+	s.setSignatureHeaders(req.Header, webhook, messageID, payloadBytes, startTime.Unix())
 
 	// Send request
 	resp, err := s.httpClient.Do(req)
@@ -263,16 +421,74 @@ func (s *WebhookService) deliverWebhook(ctx context.Context, webhook store.Webho
 	return false, responseStatus, responseBody, durationMs, fmt.Errorf("received non-2xx status code: %d", responseStatus)
 }
 
+// setSignatureHeaders signs a delivery in the webhook's signature format
+func (s *WebhookService) setSignatureHeaders(header http.Header, webhook store.Webhook, messageID string, payload []byte, timestamp int64) {
+	if webhook.SignatureFormat == store.WebhookSignatureFormatStandard {
+		header.Set("webhook-id", messageID)
+		header.Set("webhook-timestamp", strconv.FormatInt(timestamp, 10))
+		header.Set("webhook-signature", s.standardSignatureHeader(webhook, messageID, payload, timestamp))
+		return
+	}
+
+	header.Set("X-Webhook-Signature", s.signatureHeader(webhook, payload, timestamp))
+}
+
+// standardSignatureHeader builds the Standard Webhooks signature header: a space-separated list of
+// "v1,<base64 HMAC-SHA256 of <id>.<timestamp>.<payload>>", one per valid secret, the current
+// secret first. The HMAC key is the raw webhook secret, which receivers configure as
+// "whsec_" + base64(secret).
+func (s *WebhookService) standardSignatureHeader(webhook store.Webhook, messageID string, payload []byte, timestamp int64) string {
+	secrets := []string{webhook.Secret}
+	if previous := previousSigningSecret(webhook, time.Unix(timestamp, 0)); previous != "" {
+		secrets = append(secrets, previous)
+	}
+
+	signedContent := fmt.Sprintf("%s.%d.%s", messageID, timestamp, payload)
+
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(signedContent))
+		signatures = append(signatures, "v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	}
+
+	return strings.Join(signatures, " ")
+}
+
 // generateSignature generates an HMAC signature for the webhook payload
 func (s *WebhookService) generateSignature(secret string, payload []byte, timestamp int64) string {
 	// Format: t=<timestamp>,v1=<signature>
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, s.computeSignature(secret, payload, timestamp))
+}
+
+// signatureHeader builds the signature header of a delivery. While a rotated secret is still
+// valid the header carries a v1 signature for each secret, the current secret first, so receivers
+// accept the delivery whichever of the two they have configured.
+func (s *WebhookService) signatureHeader(webhook store.Webhook, payload []byte, timestamp int64) string {
+	header := s.generateSignature(webhook.Secret, payload, timestamp)
+
+	if previous := previousSigningSecret(webhook, time.Unix(timestamp, 0)); previous != "" {
+		header += ",v1=" + s.computeSignature(previous, payload, timestamp)
+	}
+
+	return header
+}
+
+// computeSignature computes the hex-encoded HMAC-SHA256 of "<timestamp>.<payload>"
+func (s *WebhookService) computeSignature(secret string, payload []byte, timestamp int64) string {
 	signedPayload := fmt.Sprintf("%d.%s", timestamp, string(payload))
 
 	mac := hmac.New(sha256.New, []byte(secret))
 	mac.Write([]byte(signedPayload))
-	signature := hex.EncodeToString(mac.Sum(nil))
+	return hex.EncodeToString(mac.Sum(nil))
+}
 
-	return fmt.Sprintf("t=%d,v1=%s", timestamp, signature)
+// previousSigningSecret returns a webhook's previous secret while its rotation overlap lasts
+func previousSigningSecret(webhook store.Webhook, at time.Time) string {
+	if webhook.PreviousSecret == nil || webhook.PreviousSecretExpiresAt == nil || !at.Before(*webhook.PreviousSecretExpiresAt) {
+		return ""
+	}
+	return *webhook.PreviousSecret
 }
 
 // calculateNextRetry calculates the next retry time based on attempt number
@@ -301,7 +517,7 @@ func (s *WebhookService) calculateNextRetry(attemptNumber int) time.Time {
 // RetryFailedDeliveries processes failed webhook deliveries that are ready for retry
 func (s *WebhookService) RetryFailedDeliveries(ctx context.Context, limit int) error {
 	// Get pending deliveries
-	deliveries, err := s.store.GetPendingWebhookDeliveries(ctx, limit, 5)
+	deliveries, err := s.store.GetPendingWebhookDeliveries(ctx, limit, MaxDeliveryAttempts)
 	if err != nil {
 		s.logger.Error(ctx, "failed to get pending deliveries", err)
 		return fmt.Errorf("failed to get pending deliveries: %w", err)
@@ -323,15 +539,19 @@ func (s *WebhookService) RetryFailedDeliveries(ctx context.Context, limit int) e
 			continue
 		}
 
-		// Reconstruct payload
-		payload := WebhookPayload{
-			ID:        delivery.ID.String(),
-			Type:      delivery.EventType,
-			CreatedAt: time.Now().UTC().Format(time.RFC3339),
-			Data:      delivery.Payload,
-			AccountID: webhook.AccountID.String(),
+		// Defer deliveries of webhooks with an open circuit until the next probe
+		if err := s.checkCircuit(ctx, webhook, delivery); err != nil {
+			continue
 		}
 
+		// Deliveries to ordered webhooks wait for earlier deliveries still being retried
+		if !s.isNextInOrder(ctx, webhook, delivery) {
+			continue
+		}
+
+		// Reconstruct payload
+		payload := newPayload(webhook, deliveryPayloadID(delivery).String(), delivery.EventType, delivery.Payload)
+
 		payloadBytes, err := json.Marshal(payload)
 		if err != nil {
 			s.logger.Error(ctx, "failed to marshal payload", err)
@@ -339,7 +559,10 @@ func (s *WebhookService) RetryFailedDeliveries(ctx context.Context, limit int) e
 		}
 
 		// Attempt delivery
-		success, responseStatus, responseBody, durationMs, deliveryErr := s.deliverWebhook(ctx, webhook, payloadBytes)
+		success, responseStatus, responseBody, durationMs, deliveryErr := s.deliverWebhook(ctx, webhook, payload.ID, payloadBytes)
+		if !success {
+			s.recordAttemptFailure(ctx, webhook)
+		}
 
 		if success {
 			// Update delivery status as success
@@ -392,7 +615,9 @@ func (s *WebhookService) RetryFailedDeliveries(ctx context.Context, limit int) e
 			if err != nil {
 				s.logger.Error(ctx, "failed to set next retry time", err)
 			}
-			s.logger.Info(ctx, fmt.Sprintf("webhook delivery %s failed, will retry at %s", delivery.ID, nextRetryAt.Format(time.RFC3339)))
+			if nextRetryAt != nil {
+				s.logger.Info(ctx, fmt.Sprintf("webhook delivery %s failed, will retry at %s", delivery.ID, nextRetryAt.Format(time.RFC3339)))
+			}
 			if delivery.AttemptNumber == webhook.MaxRetries {
 				// Increment webhook failed counter (no more retries)
 				err = s.store.IncrementWebhookFailed(ctx, webhook.ID)
@@ -416,16 +641,131 @@ func (s *WebhookService) TestWebhook(ctx context.Context, webhookID uuid.UUID) e
 	}
 
 	// Create test payload
-	payload := WebhookPayload{
-		ID:        uuid.New().String(),
-		Type:      "webhook.test",
-		CreatedAt: time.Now().UTC().Format(time.RFC3339),
-		Data: map[string]interface{}{
-			"test":    true,
-			"message": "This is a test webhook event",
-		},
-		AccountID: webhook.AccountID.String(),
-	}
-
-	return s.sendWebhook(ctx, webhook, payload)
+	payload := newPayload(webhook, uuid.New().String(), "webhook.test", map[string]interface{}{
+		"test":    true,
+		"message": "This is a test webhook event",
+	})
+
+	_, err = s.sendWebhook(ctx, webhook, payload, deliveryOrigin{TriggeredBy: store.WebhookDeliveryTriggerTest})
+	return err
+}
+
+// RedeliverDelivery sends the payload of an earlier delivery to its webhook again, as a new delivery
+// linked to the first delivery of the attempt chain. The redelivery is attempted immediately and
+// retried like any other delivery. It returns the ID of the new delivery, which is set whenever
+// the delivery was recorded, even if it failed.
+func (s *WebhookService) RedeliverDelivery(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) (uuid.UUID, error) {
+	ctx = observability.WithFields(ctx, observability.Field{Key: "original_delivery_id", Value: delivery.ID})
+
+	payload := newPayload(webhook, deliveryPayloadID(delivery).String(), delivery.EventType, delivery.Payload)
+
+	return s.sendWebhook(ctx, webhook, payload, deliveryOrigin{
+		EventID:            delivery.EventID,
+		OriginalDeliveryID: RootDeliveryID(delivery),
+		TriggeredBy:        store.WebhookDeliveryTriggerRedelivery,
+	})
+}
+
+// RootDeliveryID returns the ID of the first delivery of a delivery's attempt chain
+func RootDeliveryID(delivery store.WebhookDelivery) *uuid.UUID {
+	if delivery.OriginalDeliveryID != nil {
+		return delivery.OriginalDeliveryID
+	}
+	id := delivery.ID
+	return &id
+}
+
+// deliveryPayloadID returns the payload ID of a delivery. Every attempt of a chain carries the
+// same ID, the event ID for logged events, so receivers can deduplicate them.
+func deliveryPayloadID(delivery store.WebhookDelivery) uuid.UUID {
+	if delivery.EventID != nil {
+		return *delivery.EventID
+	}
+	return *RootDeliveryID(delivery)
+}
+
+// checkCircuit lets a delivery through while its webhook's circuit is closed, and lets one probe
+// through per probe interval while it is open. Other deliveries are deferred to the next probe
+// without counting an attempt, or dropped if the webhook does not retry, and ErrCircuitOpen is
+// returned.
+func (s *WebhookService) checkCircuit(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) error {
+	if webhook.CircuitOpenedAt == nil {
+		return nil
+	}
+
+	now := time.Now()
+	nextProbeAt := now.Add(s.breaker.ProbeInterval)
+	if webhook.CircuitNextProbeAt == nil || !now.Before(*webhook.CircuitNextProbeAt) {
+		claimed, err := s.store.ClaimWebhookProbe(ctx, webhook.ID, nextProbeAt)
+		if err != nil {
+			// Fail open: a missed short-circuit costs one request
+			s.logger.Error(ctx, "failed to claim webhook probe", err)
+			return nil
+		}
+		if claimed {
+			s.logger.Info(ctx, "probing webhook with open circuit")
+			return nil
+		}
+	} else {
+		nextProbeAt = *webhook.CircuitNextProbeAt
+	}
+
+	var nextRetryAt *time.Time
+	if webhook.RetryEnabled {
+		nextRetryAt = &nextProbeAt
+	}
+
+	err := s.store.DeferWebhookDelivery(ctx, delivery.ID, nextRetryAt, ErrCircuitOpen.Error())
+	if err != nil {
+		s.logger.Error(ctx, "failed to defer webhook delivery", err)
+	}
+
+	return ErrCircuitOpen
+}
+
+// isNextInOrder reports whether an event delivery may be sent now. Deliveries to webhooks with
+// ordered delivery are held back while an earlier event delivery of the webhook is still queued or
+// waiting for a retry, and are deferred until that delivery's next attempt, so the head of the
+// webhook's deliveries blocks the rest until it succeeds or is dropped.
+func (s *WebhookService) isNextInOrder(ctx context.Context, webhook store.Webhook, delivery store.WebhookDelivery) bool {
+	if !webhook.OrderedDelivery || delivery.TriggeredBy != string(store.WebhookDeliveryTriggerEvent) {
+		return true
+	}
+
+	head, err := s.store.GetOldestUnfinishedWebhookDelivery(ctx, webhook.ID, MaxDeliveryAttempts)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return true
+		}
+		// Fail closed: sending out of order cannot be undone, deferring costs a retry cycle
+		s.logger.Error(ctx, "failed to get oldest unfinished webhook delivery", err)
+		head = store.WebhookDelivery{}
+	}
+	if head.ID == delivery.ID {
+		return true
+	}
+
+	nextRetryAt := time.Now()
+	if head.NextRetryAt != nil && head.NextRetryAt.After(nextRetryAt) {
+		nextRetryAt = *head.NextRetryAt
+	}
+	if err := s.store.DeferWebhookDelivery(ctx, delivery.ID, &nextRetryAt, ErrWaitingForEarlierDelivery.Error()); err != nil {
+		s.logger.Error(ctx, "failed to defer webhook delivery", err)
+	}
+
+	return false
+}
+
+// recordAttemptFailure counts a failed attempt against a webhook, which opens its circuit once the
+// failure threshold is reached
+func (s *WebhookService) recordAttemptFailure(ctx context.Context, webhook store.Webhook) {
+	updated, err := s.store.RecordWebhookAttemptFailure(ctx, webhook.ID, s.breaker.FailureThreshold, time.Now().Add(s.breaker.ProbeInterval))
+	if err != nil {
+		s.logger.Error(ctx, "failed to record webhook attempt failure", err)
+		return
+	}
+
+	if webhook.CircuitOpenedAt == nil && updated.CircuitOpenedAt != nil {
+		s.logger.Warn(ctx, fmt.Sprintf("webhook circuit opened after %d consecutive failures", updated.ConsecutiveFailures))
+	}
 }
@@ -2,24 +2,44 @@ package service
 
 import (
 	"base-server/internal/observability"
+	"base-server/internal/outbound"
 	"base-server/internal/store"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/DMSAVentures/base-server/pkg/webhookverify"
 	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 )
 
+// testPolicy lets deliveries reach the local test servers
+var testPolicy = outbound.Policy{AllowedHosts: []string{"127.0.0.1"}}
+
+// waitForDeliveries waits until the deliveries queued by DispatchEvent have been sent
+func waitForDeliveries(t *testing.T, service *WebhookService) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for service.queue.pending() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for queued deliveries")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
 func TestGenerateSignature(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	secret := "test-secret"
 	payload := []byte(`{"test":"data"}`)
@@ -42,13 +62,107 @@ func TestGenerateSignature(t *testing.T) {
 	}
 }
 
+func TestSignatureHeader_RotationOverlap(t *testing.T) {
+	service := New(nil, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	payload := []byte(`{"test":"data"}`)
+	timestamp := int64(1234567890)
+	previous := "old-secret"
+	expiresAt := time.Unix(timestamp, 0).Add(time.Hour)
+	webhook := store.Webhook{Secret: "new-secret", PreviousSecret: &previous, PreviousSecretExpiresAt: &expiresAt}
+
+	current := service.computeSignature("new-secret", payload, timestamp)
+	old := service.computeSignature("old-secret", payload, timestamp)
+
+	// Both secrets sign deliveries during the overlap, the current one first
+	header := service.signatureHeader(webhook, payload, timestamp)
+	if want := "t=1234567890,v1=" + current + ",v1=" + old; header != want {
+		t.Errorf("expected %s, got %s", want, header)
+	}
+
+	// Only the current secret signs deliveries once the overlap ended
+	header = service.signatureHeader(webhook, payload, expiresAt.Unix())
+	if want := service.generateSignature("new-secret", payload, expiresAt.Unix()); header != want {
+		t.Errorf("expected %s, got %s", want, header)
+	}
+}
+
+func TestSetSignatureHeaders_VerifiesWithHelper(t *testing.T) {
+	service := New(nil, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	payload := []byte(`{"id":"evt_1","type":"user.created"}`)
+	timestamp := time.Now().Unix()
+	previous := "old-secret"
+	expiresAt := time.Now().Add(time.Hour)
+
+	for _, format := range []store.WebhookSignatureFormat{store.WebhookSignatureFormatV1, store.WebhookSignatureFormatStandard} {
+		t.Run(string(format), func(t *testing.T) {
+			webhook := store.Webhook{Secret: "new-secret", SignatureFormat: format, PreviousSecret: &previous, PreviousSecretExpiresAt: &expiresAt}
+
+			header := http.Header{}
+			service.setSignatureHeaders(header, webhook, "evt_1", payload, timestamp)
+
+			if format == store.WebhookSignatureFormatStandard {
+				if header.Get("webhook-id") != "evt_1" || header.Get("X-Webhook-Signature") != "" {
+					t.Errorf("expected Standard Webhooks headers only, got %v", header)
+				}
+			} else if header.Get("webhook-signature") != "" {
+				t.Errorf("expected X-Webhook-Signature only, got %v", header)
+			}
+
+			// Receivers holding either secret accept the delivery during the rotation overlap
+			for _, secret := range []string{"new-secret", "old-secret", "whsec_" + base64.StdEncoding.EncodeToString([]byte("new-secret"))} {
+				verifier, err := webhookverify.New(secret)
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if err := verifier.Verify(header, payload); err != nil {
+					t.Errorf("expected delivery to verify with %q, got %v", secret, err)
+				}
+			}
+		})
+	}
+}
+
+func TestDispatchEvent_FilteredOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	accountID := uuid.New()
+	webhooks := []store.Webhook{
+		{
+			ID:        uuid.New(),
+			AccountID: accountID,
+			URL:       "https://example.com/webhook",
+			Events:    []string{"user.*"},
+			Filters:   store.WebhookFilters{{Field: "user.utm_source", Operator: "eq", Value: "producthunt"}},
+			Status:    "active",
+		},
+	}
+
+	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return(webhooks, nil)
+	// No delivery is created for an event the webhook filters out
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Times(0)
+
+	data := map[string]interface{}{"user": map[string]interface{}{"utm_source": "twitter"}}
+	err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", data)
+	waitForDeliveries(t, service)
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
 func TestCalculateNextRetry(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	tests := []struct {
 		attemptNumber int
@@ -81,7 +195,7 @@ func TestSubscribesToEvent(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	subscribedEvents := []string{"user.created", "user.verified", "referral.created"}
 
@@ -111,6 +225,7 @@ func TestDispatchEvent_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	// Create a test HTTP server to receive webhooks
+	eventID := uuid.New()
 	receivedWebhooks := 0
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		receivedWebhooks++
@@ -120,13 +235,20 @@ func TestDispatchEvent_Success(t *testing.T) {
 		if r.Header.Get("X-Webhook-Signature") == "" {
 			t.Error("Expected X-Webhook-Signature header")
 		}
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		if payload.ID != eventID.String() {
+			t.Errorf("Expected payload ID %s, got %s", eventID, payload.ID)
+		}
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer testServer.Close()
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	accountID := uuid.New()
@@ -146,12 +268,19 @@ func TestDispatchEvent_Success(t *testing.T) {
 
 	// Setup mock expectations
 	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
-	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Return(store.WebhookDelivery{
-		ID:        deliveryID,
-		WebhookID: webhookID,
-		EventType: "user.created",
-		Status:    "pending",
-	}, nil)
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+			if params.EventID == nil || *params.EventID != eventID {
+				t.Errorf("Expected delivery for event %s, got %v", eventID, params.EventID)
+			}
+			return store.WebhookDelivery{
+				ID:        deliveryID,
+				WebhookID: webhookID,
+				EventID:   params.EventID,
+				EventType: "user.created",
+				Status:    "pending",
+			}, nil
+		})
 	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), deliveryID, gomock.Any()).Return(nil)
 	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), webhookID).Return(nil)
 
@@ -161,7 +290,8 @@ func TestDispatchEvent_Success(t *testing.T) {
 		"email":   "test@example.com",
 	}
 
-	err := service.DispatchEvent(ctx, accountID, nil, eventType, data)
+	err := service.DispatchEvent(ctx, eventID, accountID, nil, eventType, data)
+	waitForDeliveries(t, service)
 	if err != nil {
 		t.Errorf("DispatchEvent failed: %v", err)
 	}
@@ -185,7 +315,7 @@ func TestDispatchEvent_WebhookNotSubscribed(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	accountID := uuid.New()
@@ -205,7 +335,8 @@ func TestDispatchEvent_WebhookNotSubscribed(t *testing.T) {
 	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
 
 	// Dispatch an event the webhook is NOT subscribed to
-	err := service.DispatchEvent(ctx, accountID, nil, "user.created", map[string]interface{}{"test": true})
+	err := service.DispatchEvent(ctx, uuid.New(), accountID, nil, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
 	if err != nil {
 		t.Errorf("DispatchEvent failed: %v", err)
 	}
@@ -230,7 +361,7 @@ func TestDispatchEvent_PausedWebhook(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	accountID := uuid.New()
@@ -249,7 +380,8 @@ func TestDispatchEvent_PausedWebhook(t *testing.T) {
 
 	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
 
-	err := service.DispatchEvent(ctx, accountID, nil, "user.created", map[string]interface{}{"test": true})
+	err := service.DispatchEvent(ctx, uuid.New(), accountID, nil, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
 	if err != nil {
 		t.Errorf("DispatchEvent failed: %v", err)
 	}
@@ -274,7 +406,7 @@ func TestDispatchEvent_CampaignSpecific(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	accountID := uuid.New()
@@ -305,7 +437,8 @@ func TestDispatchEvent_CampaignSpecific(t *testing.T) {
 	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), deliveryID, gomock.Any()).Return(nil)
 	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), webhookID).Return(nil)
 
-	err := service.DispatchEvent(ctx, accountID, &campaignID, "user.created", map[string]interface{}{"test": true})
+	err := service.DispatchEvent(ctx, uuid.New(), accountID, &campaignID, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
 	if err != nil {
 		t.Errorf("DispatchEvent failed: %v", err)
 	}
@@ -319,7 +452,8 @@ func TestDispatchEvent_CampaignSpecific(t *testing.T) {
 	otherCampaignID := uuid.New()
 	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
 
-	err = service.DispatchEvent(ctx, accountID, &otherCampaignID, "user.created", map[string]interface{}{"test": true})
+	err = service.DispatchEvent(ctx, uuid.New(), accountID, &otherCampaignID, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
 	if err != nil {
 		t.Errorf("DispatchEvent failed: %v", err)
 	}
@@ -341,7 +475,7 @@ func TestTestWebhook_Success(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	accountID := uuid.New()
@@ -381,7 +515,7 @@ func TestTestWebhook_WebhookNotFound(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	webhookID := uuid.New()
@@ -406,7 +540,7 @@ func TestDispatchEvent_DeliveryFailed(t *testing.T) {
 
 	mockStore := NewMockWebhookStore(ctrl)
 	logger := observability.NewLogger()
-	service := New(mockStore, logger)
+	service := New(mockStore, logger, CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
 
 	ctx := context.Background()
 	accountID := uuid.New()
@@ -434,10 +568,381 @@ func TestDispatchEvent_DeliveryFailed(t *testing.T) {
 	}, nil)
 	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), deliveryID, gomock.Any()).Return(nil)
 	mockStore.EXPECT().IncrementDeliveryAttempt(gomock.Any(), deliveryID, gomock.Any()).Return(nil)
+	mockStore.EXPECT().RecordWebhookAttemptFailure(gomock.Any(), webhookID, 10, gomock.Any()).Return(webhook, nil)
 
-	err := service.DispatchEvent(ctx, accountID, nil, "user.created", map[string]interface{}{"test": true})
+	err := service.DispatchEvent(ctx, uuid.New(), accountID, nil, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
 	// DispatchEvent continues even if individual webhooks fail
 	if err != nil {
 		t.Errorf("DispatchEvent should not fail: %v", err)
 	}
 }
+
+func TestRedeliverDelivery_LinksToOriginal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	eventID := uuid.New()
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		// Every attempt carries the event ID so receivers can deduplicate
+		if payload.ID != eventID.String() {
+			t.Errorf("Expected payload ID %s, got %s", eventID, payload.ID)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	webhookID := uuid.New()
+	originalID := uuid.New()
+	redeliveryID := uuid.New()
+
+	webhook := store.Webhook{
+		ID:        webhookID,
+		AccountID: uuid.New(),
+		URL:       testServer.URL,
+		Secret:    "test-secret",
+		Status:    "active",
+	}
+	original := store.WebhookDelivery{
+		ID:        originalID,
+		WebhookID: webhookID,
+		EventID:   &eventID,
+		EventType: "user.created",
+		Payload:   store.JSONB{"email": "test@example.com"},
+		Status:    "failed",
+	}
+
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+			if params.OriginalDeliveryID == nil || *params.OriginalDeliveryID != originalID {
+				t.Errorf("Expected redelivery linked to %s, got %v", originalID, params.OriginalDeliveryID)
+			}
+			if params.TriggeredBy != store.WebhookDeliveryTriggerRedelivery {
+				t.Errorf("Expected redelivery trigger, got %s", params.TriggeredBy)
+			}
+			return store.WebhookDelivery{ID: redeliveryID, WebhookID: webhookID, AttemptNumber: 1}, nil
+		})
+	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), redeliveryID, gomock.Any()).Return(nil)
+	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), webhookID).Return(nil)
+
+	id, err := service.RedeliverDelivery(context.Background(), webhook, original)
+	if err != nil {
+		t.Fatalf("RedeliverDelivery failed: %v", err)
+	}
+	if id != redeliveryID {
+		t.Errorf("Expected redelivery %s, got %s", redeliveryID, id)
+	}
+}
+
+func TestDispatchEvent_CircuitOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request while the circuit is open")
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	openedAt := time.Now().Add(-time.Hour)
+	nextProbeAt := time.Now().Add(3 * time.Minute)
+
+	webhook := store.Webhook{
+		ID:                 webhookID,
+		AccountID:          accountID,
+		URL:                testServer.URL,
+		Events:             []string{"user.created"},
+		Status:             "active",
+		RetryEnabled:       true,
+		CircuitOpenedAt:    &openedAt,
+		CircuitNextProbeAt: &nextProbeAt,
+	}
+
+	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Return(store.WebhookDelivery{ID: deliveryID, WebhookID: webhookID}, nil)
+	// The delivery waits for the next probe without counting an attempt
+	mockStore.EXPECT().DeferWebhookDelivery(gomock.Any(), deliveryID, &nextProbeAt, ErrCircuitOpen.Error()).Return(nil)
+
+	err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
+	if err != nil {
+		t.Errorf("DispatchEvent should not fail: %v", err)
+	}
+}
+
+func TestDispatchEvent_CircuitProbe(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	received := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{ProbeInterval: time.Minute}, testPolicy, DeliveryConfig{})
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	openedAt := time.Now().Add(-time.Hour)
+	probeDueAt := time.Now().Add(-time.Second)
+
+	webhook := store.Webhook{
+		ID:                 webhookID,
+		AccountID:          accountID,
+		URL:                testServer.URL,
+		Events:             []string{"user.created"},
+		Status:             "active",
+		CircuitOpenedAt:    &openedAt,
+		CircuitNextProbeAt: &probeDueAt,
+	}
+
+	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Return(store.WebhookDelivery{ID: deliveryID, WebhookID: webhookID}, nil)
+	mockStore.EXPECT().ClaimWebhookProbe(gomock.Any(), webhookID, gomock.Any()).Return(true, nil)
+	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), deliveryID, gomock.Any()).Return(nil)
+	// A successful probe closes the circuit
+	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), webhookID).Return(nil)
+
+	err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", map[string]interface{}{"test": true})
+	waitForDeliveries(t, service)
+	if err != nil {
+		t.Errorf("DispatchEvent should not fail: %v", err)
+	}
+	if received != 1 {
+		t.Errorf("Expected 1 probe request, got %d", received)
+	}
+}
+
+func TestDispatchEvent_QueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{MaxInFlight: 1, QueueSize: 1})
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	spilledID := uuid.New()
+
+	webhook := store.Webhook{
+		ID:        webhookID,
+		AccountID: accountID,
+		URL:       testServer.URL,
+		Events:    []string{"user.created"},
+		Status:    "active",
+	}
+
+	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil).Times(3)
+	deliveryIDs := []uuid.UUID{uuid.New(), uuid.New(), spilledID}
+	for _, id := range deliveryIDs {
+		mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Return(store.WebhookDelivery{ID: id, WebhookID: webhookID}, nil)
+	}
+	// The third delivery finds the endpoint busy and its queue full, and is left to the retry worker
+	mockStore.EXPECT().DeferWebhookDelivery(gomock.Any(), spilledID, gomock.Not(gomock.Nil()), ErrQueueFull.Error()).Return(nil)
+	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), webhookID).Return(nil).Times(2)
+
+	data := map[string]interface{}{"test": true}
+	if err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", data); err != nil {
+		t.Fatalf("DispatchEvent failed: %v", err)
+	}
+	<-started
+
+	// DispatchEvent returns without waiting for the busy endpoint
+	for i := 0; i < 2; i++ {
+		if err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", data); err != nil {
+			t.Fatalf("DispatchEvent failed: %v", err)
+		}
+	}
+
+	close(release)
+	waitForDeliveries(t, service)
+}
+
+func TestDispatchEvent_PinnedPayloadVersion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	received := make(chan WebhookPayload, 2)
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("Failed to decode payload: %v", err)
+		}
+		received <- payload
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	accountID := uuid.New()
+	v1Webhook := store.Webhook{ID: uuid.New(), AccountID: accountID, URL: testServer.URL, Events: []string{"user.created"}, Status: "active", PayloadVersion: 1}
+	v2Webhook := store.Webhook{ID: uuid.New(), AccountID: accountID, URL: testServer.URL, Events: []string{"user.created"}, Status: "active", PayloadVersion: 2}
+
+	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{v1Webhook, v2Webhook}, nil)
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params store.CreateWebhookDeliveryParams) (store.WebhookDelivery, error) {
+			return store.WebhookDelivery{ID: uuid.New(), WebhookID: params.WebhookID, EventType: params.EventType}, nil
+		}).Times(2)
+	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	data := map[string]interface{}{
+		"campaign_id": uuid.New().String(),
+		"user": map[string]interface{}{
+			"id":                 uuid.New().String(),
+			"email":              "test@example.com",
+			"verification_token": "secret-token",
+		},
+	}
+	if err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", data); err != nil {
+		t.Fatalf("DispatchEvent failed: %v", err)
+	}
+	waitForDeliveries(t, service)
+	close(received)
+
+	versions := map[int]bool{}
+	for payload := range received {
+		versions[payload.SchemaVersion] = true
+		user := payload.Data["user"].(map[string]interface{})
+		_, hasToken := user["verification_token"]
+		if payload.SchemaVersion == 1 && !hasToken {
+			t.Error("Expected version 1 payload to include verification_token")
+		}
+		if payload.SchemaVersion == 2 && hasToken {
+			t.Error("Expected version 2 payload not to include verification_token")
+		}
+	}
+	if !versions[1] || !versions[2] {
+		t.Errorf("Expected payloads in versions 1 and 2, got %v", versions)
+	}
+
+	// The published data is left as is for other webhooks
+	if _, ok := data["user"].(map[string]interface{})["verification_token"]; !ok {
+		t.Error("Expected event data not to be modified")
+	}
+}
+
+func TestDispatchEvent_OrderedWaitsForEarlierDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivery must not be sent while an earlier delivery is being retried")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	accountID := uuid.New()
+	webhookID := uuid.New()
+	deliveryID := uuid.New()
+	headRetryAt := time.Now().Add(time.Minute)
+
+	webhook := store.Webhook{
+		ID:              webhookID,
+		AccountID:       accountID,
+		URL:             testServer.URL,
+		Events:          []string{"user.created"},
+		Status:          "active",
+		OrderedDelivery: true,
+	}
+
+	mockStore.EXPECT().GetWebhooksByAccount(gomock.Any(), accountID).Return([]store.Webhook{webhook}, nil)
+	mockStore.EXPECT().CreateWebhookDelivery(gomock.Any(), gomock.Any()).Return(store.WebhookDelivery{
+		ID:          deliveryID,
+		WebhookID:   webhookID,
+		TriggeredBy: string(store.WebhookDeliveryTriggerEvent),
+		Status:      "pending",
+	}, nil)
+	mockStore.EXPECT().GetOldestUnfinishedWebhookDelivery(gomock.Any(), webhookID, MaxDeliveryAttempts).Return(store.WebhookDelivery{
+		ID:          uuid.New(),
+		WebhookID:   webhookID,
+		Status:      "failed",
+		NextRetryAt: &headRetryAt,
+	}, nil)
+	// The delivery is deferred until the earlier delivery's retry
+	mockStore.EXPECT().DeferWebhookDelivery(gomock.Any(), deliveryID, &headRetryAt, ErrWaitingForEarlierDelivery.Error()).Return(nil)
+
+	if err := service.DispatchEvent(context.Background(), uuid.New(), accountID, nil, "user.created", map[string]interface{}{"test": true}); err != nil {
+		t.Fatalf("DispatchEvent failed: %v", err)
+	}
+	waitForDeliveries(t, service)
+}
+
+func TestRetryFailedDeliveries_Ordered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var sent []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		sent = append(sent, payload.ID)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	mockStore := NewMockWebhookStore(ctrl)
+	service := New(mockStore, observability.NewLogger(), CircuitBreakerConfig{}, testPolicy, DeliveryConfig{})
+
+	webhookID := uuid.New()
+	webhook := store.Webhook{
+		ID:              webhookID,
+		AccountID:       uuid.New(),
+		URL:             testServer.URL,
+		Events:          []string{"user.created"},
+		Status:          "active",
+		OrderedDelivery: true,
+	}
+
+	headEventID := uuid.New()
+	head := store.WebhookDelivery{ID: uuid.New(), WebhookID: webhookID, EventID: &headEventID, TriggeredBy: "event", EventType: "user.created", Status: "failed", AttemptNumber: 2}
+	laterEventID := uuid.New()
+	later := store.WebhookDelivery{ID: uuid.New(), WebhookID: webhookID, EventID: &laterEventID, TriggeredBy: "event", EventType: "user.created", Status: "failed", AttemptNumber: 1}
+
+	// The later delivery comes due first but waits for the head, which is then sent
+	mockStore.EXPECT().GetPendingWebhookDeliveries(gomock.Any(), 10, MaxDeliveryAttempts).Return([]store.WebhookDelivery{later, head}, nil)
+	mockStore.EXPECT().GetWebhookByID(gomock.Any(), webhookID).Return(webhook, nil).Times(2)
+	mockStore.EXPECT().GetOldestUnfinishedWebhookDelivery(gomock.Any(), webhookID, MaxDeliveryAttempts).Return(head, nil).Times(2)
+	mockStore.EXPECT().DeferWebhookDelivery(gomock.Any(), later.ID, gomock.Not(gomock.Nil()), ErrWaitingForEarlierDelivery.Error()).Return(nil)
+	mockStore.EXPECT().UpdateWebhookDeliveryStatus(gomock.Any(), head.ID, gomock.Any()).Return(nil)
+	mockStore.EXPECT().IncrementWebhookSent(gomock.Any(), webhookID).Return(nil)
+
+	if err := service.RetryFailedDeliveries(context.Background(), 10); err != nil {
+		t.Fatalf("RetryFailedDeliveries failed: %v", err)
+	}
+
+	if len(sent) != 1 || sent[0] != headEventID.String() {
+		t.Errorf("expected only the head delivery to be sent, got %v", sent)
+	}
+}
@@ -0,0 +1,201 @@
+// Package subscription decides which events a webhook receives. Webhooks subscribe to event types
+// or patterns ("user.*", "*"), may be scoped to a campaign, and may narrow their deliveries with
+// filters on the event data.
+package subscription
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
+
+	"github.com/google/uuid"
+)
+
+// Wildcard subscribes to every public event
+const Wildcard = "*"
+
+// Filter operators
+const (
+	OperatorEq     = "eq"
+	OperatorNeq    = "neq"
+	OperatorIn     = "in"
+	OperatorNotIn  = "not_in"
+	OperatorExists = "exists"
+)
+
+// MaxFilters is the largest number of filters a webhook may have
+const MaxFilters = 20
+
+var (
+	ErrInvalidEvent  = errors.New("invalid event type")
+	ErrInvalidFilter = errors.New("invalid filter")
+)
+
+// Matches reports whether a webhook receives an event: the event must be in the webhook's
+// campaign scope, match one of its event patterns, and meet all of its filters
+func Matches(webhook store.Webhook, eventType string, campaignID *uuid.UUID, data map[string]interface{}) bool {
+	// Campaign webhooks receive their campaign's events and account-level events
+	if campaignID != nil && webhook.CampaignID != nil && *webhook.CampaignID != *campaignID {
+		return false
+	}
+
+	return SubscribesTo(webhook.Events, eventType) && MatchesFilters(webhook.Filters, data)
+}
+
+// SubscribesTo reports whether any of the patterns matches a public event type
+func SubscribesTo(patterns []string, eventType string) bool {
+	if !events.IsPublicEvent(eventType) {
+		return false
+	}
+
+	for _, pattern := range patterns {
+		if matchesPattern(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPattern matches an event type against an exact type, a "<group>.*" pattern or "*"
+func matchesPattern(pattern, eventType string) bool {
+	if pattern == Wildcard {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasSuffix(prefix, ".") && strings.HasPrefix(eventType, prefix)
+	}
+	return pattern == eventType
+}
+
+// ValidatePatterns checks that every pattern is a public event type, "*", or "<group>.*" for a
+// group with public events
+func ValidatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if len(ExpandPatterns([]string{pattern})) == 0 {
+			return fmt.Errorf("%w: %s", ErrInvalidEvent, pattern)
+		}
+	}
+	return nil
+}
+
+// ExpandPatterns returns the public event types matched by any of the patterns
+func ExpandPatterns(patterns []string) []string {
+	var types []string
+	for _, eventType := range events.PublicEvents {
+		if SubscribesTo(patterns, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	return types
+}
+
+// MatchesFilters reports whether event data meets every filter
+func MatchesFilters(filters store.WebhookFilters, data map[string]interface{}) bool {
+	for _, filter := range filters {
+		if !matchesFilter(filter, data) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesFilter evaluates one filter. Values are compared by their string form so that a filter
+// on "42" matches the JSON number 42.
+func matchesFilter(filter store.WebhookFilter, data map[string]interface{}) bool {
+	value, found := lookup(data, filter.Field)
+
+	switch filter.Operator {
+	case OperatorExists:
+		return found && value != nil
+	case OperatorEq:
+		return found && equal(value, filter.Value)
+	case OperatorNeq:
+		return !found || !equal(value, filter.Value)
+	case OperatorIn:
+		return found && contains(filter.Value, value)
+	case OperatorNotIn:
+		return !found || !contains(filter.Value, value)
+	default:
+		return false
+	}
+}
+
+// lookup resolves a dot-separated path in event data
+func lookup(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = object[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func equal(a, b interface{}) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func contains(list, value interface{}) bool {
+	values, ok := list.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, v := range values {
+		if equal(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateFilters checks the fields, operators and values of filters
+func ValidateFilters(filters store.WebhookFilters) error {
+	if len(filters) > MaxFilters {
+		return fmt.Errorf("%w: at most %d filters are allowed", ErrInvalidFilter, MaxFilters)
+	}
+
+	for _, filter := range filters {
+		if filter.Field == "" || strings.HasPrefix(filter.Field, ".") || strings.HasSuffix(filter.Field, ".") || strings.Contains(filter.Field, "..") {
+			return fmt.Errorf("%w: field %q must be a dot-separated path", ErrInvalidFilter, filter.Field)
+		}
+
+		switch filter.Operator {
+		case OperatorEq, OperatorNeq:
+			if !isScalar(filter.Value) {
+				return fmt.Errorf("%w: %s on %s needs a string, number or boolean value", ErrInvalidFilter, filter.Operator, filter.Field)
+			}
+		case OperatorIn, OperatorNotIn:
+			values, ok := filter.Value.([]interface{})
+			if !ok || len(values) == 0 {
+				return fmt.Errorf("%w: %s on %s needs a non-empty list of values", ErrInvalidFilter, filter.Operator, filter.Field)
+			}
+			for _, v := range values {
+				if !isScalar(v) {
+					return fmt.Errorf("%w: %s on %s needs string, number or boolean values", ErrInvalidFilter, filter.Operator, filter.Field)
+				}
+			}
+		case OperatorExists:
+		default:
+			return fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, filter.Operator)
+		}
+	}
+
+	return nil
+}
+
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, float64, bool, int:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,152 @@
+package subscription
+
+import (
+	"errors"
+	"testing"
+
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+func TestSubscribesTo(t *testing.T) {
+	tests := []struct {
+		name      string
+		patterns  []string
+		eventType string
+		expected  bool
+	}{
+		{"exact", []string{"user.created"}, "user.created", true},
+		{"exact other", []string{"user.created"}, "user.verified", false},
+		{"group wildcard", []string{"user.*"}, "user.position_changed", true},
+		{"group wildcard other group", []string{"user.*"}, "referral.created", false},
+		{"wildcard", []string{"*"}, "email.bounced", true},
+		{"wildcard skips internal events", []string{"*"}, "blast.started", false},
+		{"group wildcard skips internal events", []string{"blast.*"}, "blast.started", false},
+		{"partial prefix", []string{"user*"}, "user.created", false},
+		{"unknown event", []string{"*"}, "unknown.event", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SubscribesTo(tt.patterns, tt.eventType); got != tt.expected {
+				t.Errorf("SubscribesTo(%v, %s) = %v, want %v", tt.patterns, tt.eventType, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidatePatterns(t *testing.T) {
+	valid := [][]string{{"user.created"}, {"user.*"}, {"*"}, {"referral.*", "email.opened"}}
+	for _, patterns := range valid {
+		if err := ValidatePatterns(patterns); err != nil {
+			t.Errorf("expected %v to be valid, got %v", patterns, err)
+		}
+	}
+
+	invalid := [][]string{{"unknown.event"}, {"blast.*"}, {"blast.started"}, {"user*"}, {"*.created"}, {""}}
+	for _, patterns := range invalid {
+		if err := ValidatePatterns(patterns); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("expected %v to be invalid, got %v", patterns, err)
+		}
+	}
+}
+
+func TestExpandPatterns(t *testing.T) {
+	types := ExpandPatterns([]string{"referral.*", "user.created"})
+
+	expected := map[string]bool{"user.created": true, "referral.created": true, "referral.verified": true, "referral.converted": true}
+	if len(types) != len(expected) {
+		t.Fatalf("expected %d types, got %v", len(expected), types)
+	}
+	for _, eventType := range types {
+		if !expected[eventType] {
+			t.Errorf("unexpected type %s", eventType)
+		}
+	}
+}
+
+func TestMatchesFilters(t *testing.T) {
+	data := map[string]interface{}{
+		"campaign_id": "c1",
+		"user": map[string]interface{}{
+			"utm_source": "producthunt",
+			"position":   float64(42),
+			"verified":   true,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filters  store.WebhookFilters
+		expected bool
+	}{
+		{"no filters", nil, true},
+		{"eq", store.WebhookFilters{{Field: "user.utm_source", Operator: "eq", Value: "producthunt"}}, true},
+		{"eq mismatch", store.WebhookFilters{{Field: "user.utm_source", Operator: "eq", Value: "twitter"}}, false},
+		{"eq number as string", store.WebhookFilters{{Field: "user.position", Operator: "eq", Value: "42"}}, true},
+		{"eq bool", store.WebhookFilters{{Field: "user.verified", Operator: "eq", Value: true}}, true},
+		{"eq missing field", store.WebhookFilters{{Field: "user.referrer", Operator: "eq", Value: "x"}}, false},
+		{"neq", store.WebhookFilters{{Field: "user.utm_source", Operator: "neq", Value: "twitter"}}, true},
+		{"neq missing field", store.WebhookFilters{{Field: "user.referrer", Operator: "neq", Value: "x"}}, true},
+		{"in", store.WebhookFilters{{Field: "campaign_id", Operator: "in", Value: []interface{}{"c1", "c2"}}}, true},
+		{"not_in", store.WebhookFilters{{Field: "campaign_id", Operator: "not_in", Value: []interface{}{"c1"}}}, false},
+		{"exists", store.WebhookFilters{{Field: "user.utm_source", Operator: "exists"}}, true},
+		{"exists missing", store.WebhookFilters{{Field: "user.utm_medium", Operator: "exists"}}, false},
+		{"path through scalar", store.WebhookFilters{{Field: "campaign_id.id", Operator: "exists"}}, false},
+		{"all must match", store.WebhookFilters{
+			{Field: "user.utm_source", Operator: "eq", Value: "producthunt"},
+			{Field: "campaign_id", Operator: "eq", Value: "c2"},
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MatchesFilters(tt.filters, data); got != tt.expected {
+				t.Errorf("MatchesFilters(%v) = %v, want %v", tt.filters, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatches_CampaignScope(t *testing.T) {
+	campaignID := uuid.New()
+	otherCampaignID := uuid.New()
+	webhook := store.Webhook{CampaignID: &campaignID, Events: store.StringArray{"referral.verified"}}
+
+	if !Matches(webhook, "referral.verified", &campaignID, nil) {
+		t.Error("expected the webhook's campaign events to match")
+	}
+	if Matches(webhook, "referral.verified", &otherCampaignID, nil) {
+		t.Error("expected other campaigns' events not to match")
+	}
+	if Matches(webhook, "referral.created", &campaignID, nil) {
+		t.Error("expected unsubscribed events not to match")
+	}
+}
+
+func TestValidateFilters(t *testing.T) {
+	valid := store.WebhookFilters{
+		{Field: "user.utm_source", Operator: "eq", Value: "producthunt"},
+		{Field: "campaign_id", Operator: "in", Value: []interface{}{"c1", "c2"}},
+		{Field: "user.referrer", Operator: "exists"},
+	}
+	if err := ValidateFilters(valid); err != nil {
+		t.Errorf("expected filters to be valid, got %v", err)
+	}
+
+	invalid := []store.WebhookFilter{
+		{Field: "", Operator: "eq", Value: "x"},
+		{Field: "user..utm_source", Operator: "eq", Value: "x"},
+		{Field: "user.utm_source", Operator: "contains", Value: "x"},
+		{Field: "user.utm_source", Operator: "eq"},
+		{Field: "user.utm_source", Operator: "eq", Value: map[string]interface{}{}},
+		{Field: "user.utm_source", Operator: "in", Value: "x"},
+		{Field: "user.utm_source", Operator: "in", Value: []interface{}{}},
+	}
+	for _, filter := range invalid {
+		if err := ValidateFilters(store.WebhookFilters{filter}); !errors.Is(err, ErrInvalidFilter) {
+			t.Errorf("expected %+v to be invalid, got %v", filter, err)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+// disableBatchSize is the number of failing webhooks disabled per tick
+const disableBatchSize = 100
+
+// DisableStore defines the database operations required by DisableScheduler
+type DisableStore interface {
+	GetWebhooksFailingSince(ctx context.Context, failingBefore time.Time, limit int) ([]store.Webhook, error)
+	DisableWebhook(ctx context.Context, webhookID uuid.UUID, reason string) (store.Webhook, error)
+	GetAccountOwnerEmail(ctx context.Context, accountID uuid.UUID) (string, error)
+}
+
+// Mailer sends notification emails
+type Mailer interface {
+	SendEmail(ctx context.Context, to, subject, htmlContent string) error
+}
+
+// DisableScheduler periodically disables webhooks whose every delivery attempt has failed for
+// longer than the failure period. Disabled webhooks move to the failed status, stop receiving
+// deliveries, and the account owner is emailed.
+type DisableScheduler struct {
+	store         DisableStore
+	mailer        Mailer
+	webAppURI     string
+	logger        *observability.Logger
+	checkInterval time.Duration
+	failingFor    time.Duration
+	stopChan      chan struct{}
+}
+
+// NewDisableScheduler creates a new webhook auto-disable scheduler
+func NewDisableScheduler(
+	store DisableStore,
+	mailer Mailer,
+	webAppURI string,
+	logger *observability.Logger,
+	checkInterval time.Duration,
+	failingFor time.Duration,
+) *DisableScheduler {
+	if checkInterval <= 0 {
+		checkInterval = time.Hour
+	}
+	if failingFor <= 0 {
+		failingFor = 72 * time.Hour
+	}
+
+	return &DisableScheduler{
+		store:         store,
+		mailer:        mailer,
+		webAppURI:     webAppURI,
+		logger:        logger,
+		checkInterval: checkInterval,
+		failingFor:    failingFor,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *DisableScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting webhook auto-disable scheduler with %v interval", s.checkInterval))
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.disableFailingWebhooks(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Webhook auto-disable scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Webhook auto-disable scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.disableFailingWebhooks(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *DisableScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// disableFailingWebhooks disables every webhook that has been failing for the failure period
+func (s *DisableScheduler) disableFailingWebhooks(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "disable_failing_webhooks"},
+	)
+
+	webhooks, err := s.store.GetWebhooksFailingSince(ctx, time.Now().Add(-s.failingFor), disableBatchSize)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get failing webhooks", err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		webhookCtx := observability.WithFields(ctx,
+			observability.Field{Key: "account_id", Value: webhook.AccountID},
+			observability.Field{Key: "webhook_id", Value: webhook.ID},
+		)
+		s.disableWebhook(webhookCtx, webhook)
+	}
+}
+
+// disableWebhook disables a failing webhook and emails the account owner
+func (s *DisableScheduler) disableWebhook(ctx context.Context, webhook store.Webhook) {
+	days := int(s.failingFor.Hours() / 24)
+	reason := fmt.Sprintf("Every delivery attempt failed for %d days (%d consecutive failures)", days, webhook.ConsecutiveFailures)
+
+	disabled, err := s.store.DisableWebhook(ctx, webhook.ID, reason)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			// Paused, deleted or disabled since it was listed
+			return
+		}
+		s.logger.Error(ctx, "Failed to disable webhook", err)
+		return
+	}
+
+	s.logger.Warn(ctx, fmt.Sprintf("Disabled webhook failing since %s", webhook.FailingSince.Format(time.RFC3339)))
+
+	to, err := s.store.GetAccountOwnerEmail(ctx, webhook.AccountID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get account owner email for disabled webhook", err)
+		return
+	}
+
+	if err := s.mailer.SendEmail(ctx, to, "Your webhook endpoint has been disabled", s.disabledEmail(disabled, reason)); err != nil {
+		s.logger.Error(ctx, "Failed to email account owner about disabled webhook", err)
+	}
+}
+
+// disabledEmail renders the email sent to an account owner when one of their webhooks is disabled
+func (s *DisableScheduler) disabledEmail(webhook store.Webhook, reason string) string {
+	return fmt.Sprintf(`
+<html>
+	<body>
+		<h1>Webhook endpoint disabled</h1>
+		<p>We disabled your webhook endpoint <strong>%s</strong> because it kept failing.</p>
+		<p>%s.</p>
+		<p>Events are no longer delivered to this endpoint. Once it is fixed, re-enable it from your <a href="%s/webhooks/%s">webhook settings</a>; we will send a test event first to make sure it responds.</p>
+	</body>
+</html>
+`, html.EscapeString(webhook.URL), html.EscapeString(reason), html.EscapeString(s.webAppURI), webhook.ID)
+}
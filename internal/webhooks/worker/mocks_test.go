@@ -0,0 +1,215 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: retention.go
+//
+// Generated by this command:
+//
+//	mockgen -source=retention.go -destination=mocks_test.go -package=worker
+//
+
+// Package worker is a generated GoMock package.
+package worker
+
+import (
+	store "base-server/internal/store"
+	tiers "base-server/internal/tiers"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRetentionStore is a mock of RetentionStore interface.
+type MockRetentionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRetentionStoreMockRecorder is the mock recorder for MockRetentionStore.
+type MockRetentionStoreMockRecorder struct {
+	mock *MockRetentionStore
+}
+
+// NewMockRetentionStore creates a new mock instance.
+func NewMockRetentionStore(ctrl *gomock.Controller) *MockRetentionStore {
+	mock := &MockRetentionStore{ctrl: ctrl}
+	mock.recorder = &MockRetentionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionStore) EXPECT() *MockRetentionStoreMockRecorder {
+	return m.recorder
+}
+
+// AggregatePurgedWebhookDeliveryStats mocks base method.
+func (m *MockRetentionStore) AggregatePurgedWebhookDeliveryStats(ctx context.Context, accountID uuid.UUID, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregatePurgedWebhookDeliveryStats", ctx, accountID, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AggregatePurgedWebhookDeliveryStats indicates an expected call of AggregatePurgedWebhookDeliveryStats.
+func (mr *MockRetentionStoreMockRecorder) AggregatePurgedWebhookDeliveryStats(ctx, accountID, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregatePurgedWebhookDeliveryStats", reflect.TypeOf((*MockRetentionStore)(nil).AggregatePurgedWebhookDeliveryStats), ctx, accountID, before)
+}
+
+// AggregateWebhookDeliveryStats mocks base method.
+func (m *MockRetentionStore) AggregateWebhookDeliveryStats(ctx context.Context, since time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AggregateWebhookDeliveryStats", ctx, since)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AggregateWebhookDeliveryStats indicates an expected call of AggregateWebhookDeliveryStats.
+func (mr *MockRetentionStoreMockRecorder) AggregateWebhookDeliveryStats(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AggregateWebhookDeliveryStats", reflect.TypeOf((*MockRetentionStore)(nil).AggregateWebhookDeliveryStats), ctx, since)
+}
+
+// GetEventsToRedact mocks base method.
+func (m *MockRetentionStore) GetEventsToRedact(ctx context.Context, before time.Time, limit int) ([]store.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventsToRedact", ctx, before, limit)
+	ret0, _ := ret[0].([]store.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventsToRedact indicates an expected call of GetEventsToRedact.
+func (mr *MockRetentionStoreMockRecorder) GetEventsToRedact(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventsToRedact", reflect.TypeOf((*MockRetentionStore)(nil).GetEventsToRedact), ctx, before, limit)
+}
+
+// GetRetentionAccountIDs mocks base method.
+func (m *MockRetentionStore) GetRetentionAccountIDs(ctx context.Context) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRetentionAccountIDs", ctx)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRetentionAccountIDs indicates an expected call of GetRetentionAccountIDs.
+func (mr *MockRetentionStoreMockRecorder) GetRetentionAccountIDs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRetentionAccountIDs", reflect.TypeOf((*MockRetentionStore)(nil).GetRetentionAccountIDs), ctx)
+}
+
+// GetWebhookDeliveriesToRedact mocks base method.
+func (m *MockRetentionStore) GetWebhookDeliveriesToRedact(ctx context.Context, before time.Time, limit int) ([]store.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveriesToRedact", ctx, before, limit)
+	ret0, _ := ret[0].([]store.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveriesToRedact indicates an expected call of GetWebhookDeliveriesToRedact.
+func (mr *MockRetentionStoreMockRecorder) GetWebhookDeliveriesToRedact(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveriesToRedact", reflect.TypeOf((*MockRetentionStore)(nil).GetWebhookDeliveriesToRedact), ctx, before, limit)
+}
+
+// PurgeEvents mocks base method.
+func (m *MockRetentionStore) PurgeEvents(ctx context.Context, accountID uuid.UUID, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeEvents", ctx, accountID, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeEvents indicates an expected call of PurgeEvents.
+func (mr *MockRetentionStoreMockRecorder) PurgeEvents(ctx, accountID, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeEvents", reflect.TypeOf((*MockRetentionStore)(nil).PurgeEvents), ctx, accountID, before, limit)
+}
+
+// PurgeWebhookDeliveries mocks base method.
+func (m *MockRetentionStore) PurgeWebhookDeliveries(ctx context.Context, accountID uuid.UUID, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeWebhookDeliveries", ctx, accountID, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeWebhookDeliveries indicates an expected call of PurgeWebhookDeliveries.
+func (mr *MockRetentionStoreMockRecorder) PurgeWebhookDeliveries(ctx, accountID, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeWebhookDeliveries", reflect.TypeOf((*MockRetentionStore)(nil).PurgeWebhookDeliveries), ctx, accountID, before, limit)
+}
+
+// RedactEvent mocks base method.
+func (m *MockRetentionStore) RedactEvent(ctx context.Context, eventID uuid.UUID, data store.JSONB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedactEvent", ctx, eventID, data)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedactEvent indicates an expected call of RedactEvent.
+func (mr *MockRetentionStoreMockRecorder) RedactEvent(ctx, eventID, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedactEvent", reflect.TypeOf((*MockRetentionStore)(nil).RedactEvent), ctx, eventID, data)
+}
+
+// RedactWebhookDelivery mocks base method.
+func (m *MockRetentionStore) RedactWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, payload store.JSONB) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedactWebhookDelivery", ctx, deliveryID, payload)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedactWebhookDelivery indicates an expected call of RedactWebhookDelivery.
+func (mr *MockRetentionStoreMockRecorder) RedactWebhookDelivery(ctx, deliveryID, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedactWebhookDelivery", reflect.TypeOf((*MockRetentionStore)(nil).RedactWebhookDelivery), ctx, deliveryID, payload)
+}
+
+// MockTierProvider is a mock of TierProvider interface.
+type MockTierProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockTierProviderMockRecorder
+	isgomock struct{}
+}
+
+// MockTierProviderMockRecorder is the mock recorder for MockTierProvider.
+type MockTierProviderMockRecorder struct {
+	mock *MockTierProvider
+}
+
+// NewMockTierProvider creates a new mock instance.
+func NewMockTierProvider(ctrl *gomock.Controller) *MockTierProvider {
+	mock := &MockTierProvider{ctrl: ctrl}
+	mock.recorder = &MockTierProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTierProvider) EXPECT() *MockTierProviderMockRecorder {
+	return m.recorder
+}
+
+// GetTierInfoByAccountID mocks base method.
+func (m *MockTierProvider) GetTierInfoByAccountID(ctx context.Context, accountID uuid.UUID) (tiers.TierInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTierInfoByAccountID", ctx, accountID)
+	ret0, _ := ret[0].(tiers.TierInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTierInfoByAccountID indicates an expected call of GetTierInfoByAccountID.
+func (mr *MockTierProviderMockRecorder) GetTierInfoByAccountID(ctx, accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTierInfoByAccountID", reflect.TypeOf((*MockTierProvider)(nil).GetTierInfoByAccountID), ctx, accountID)
+}
@@ -0,0 +1,371 @@
+package worker
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=retention.go -destination=mocks_test.go -package=worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/tiers"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RetentionLimit is the tier limit holding the days webhook delivery logs are kept
+	RetentionLimit = "webhook_log_retention_days"
+
+	// statsRecomputeDays is the number of days, today included, whose stats are recomputed every
+	// run to pick up retries. Delivery logs are kept at least this long so those days are complete.
+	statsRecomputeDays = 3
+
+	// purgeBatchSize is the number of deliveries deleted per statement
+	purgeBatchSize = 1000
+	// redactBatchSize is the number of deliveries redacted per batch, and maxRedactBatches the
+	// number of batches per run
+	redactBatchSize  = 500
+	maxRedactBatches = 20
+
+	// redactedValue replaces redacted payload fields
+	redactedValue = "[REDACTED]"
+)
+
+// RetentionStore defines the database operations required by RetentionScheduler
+type RetentionStore interface {
+	GetRetentionAccountIDs(ctx context.Context) ([]uuid.UUID, error)
+	AggregateWebhookDeliveryStats(ctx context.Context, since time.Time) error
+	AggregatePurgedWebhookDeliveryStats(ctx context.Context, accountID uuid.UUID, before time.Time) error
+	PurgeWebhookDeliveries(ctx context.Context, accountID uuid.UUID, before time.Time, limit int) (int64, error)
+	GetWebhookDeliveriesToRedact(ctx context.Context, before time.Time, limit int) ([]store.WebhookDelivery, error)
+	RedactWebhookDelivery(ctx context.Context, deliveryID uuid.UUID, payload store.JSONB) error
+	PurgeEvents(ctx context.Context, accountID uuid.UUID, before time.Time, limit int) (int64, error)
+	GetEventsToRedact(ctx context.Context, before time.Time, limit int) ([]store.Event, error)
+	RedactEvent(ctx context.Context, eventID uuid.UUID, data store.JSONB) error
+}
+
+// TierProvider resolves the tier of an account
+type TierProvider interface {
+	GetTierInfoByAccountID(ctx context.Context, accountID uuid.UUID) (tiers.TierInfo, error)
+}
+
+// RetentionConfig configures webhook delivery log retention
+type RetentionConfig struct {
+	// DefaultRetentionDays applies to accounts whose tier has no retention limit
+	DefaultRetentionDays int
+	// RedactAfter is how long delivery payloads are kept unredacted, 0 to never redact
+	RedactAfter time.Duration
+	// RedactFields are the dot-separated payload paths redacted, e.g. user.email
+	RedactFields []string
+}
+
+// RetentionScheduler periodically rolls webhook deliveries up into per-webhook daily stats, purges
+// the deliveries and events older than the retention period of each account's tier, and redacts
+// the configured fields from older delivery payloads and event data. Events carry the same data as
+// the deliveries generated from them, so they follow the same retention.
+type RetentionScheduler struct {
+	store         RetentionStore
+	tiers         TierProvider
+	config        RetentionConfig
+	logger        *observability.Logger
+	checkInterval time.Duration
+	now           func() time.Time
+	stopChan      chan struct{}
+}
+
+// NewRetentionScheduler creates a new webhook delivery log retention scheduler
+func NewRetentionScheduler(
+	store RetentionStore,
+	tiers TierProvider,
+	config RetentionConfig,
+	logger *observability.Logger,
+	checkInterval time.Duration,
+) *RetentionScheduler {
+	if checkInterval <= 0 {
+		checkInterval = time.Hour
+	}
+	if config.DefaultRetentionDays <= 0 {
+		config.DefaultRetentionDays = 30
+	}
+
+	return &RetentionScheduler{
+		store:         store,
+		tiers:         tiers,
+		config:        config,
+		logger:        logger,
+		checkInterval: checkInterval,
+		now:           time.Now,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *RetentionScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting webhook retention scheduler with %v interval", s.checkInterval))
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.run(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Webhook retention scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Webhook retention scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.run(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *RetentionScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// run aggregates the recent daily stats, then purges and redacts delivery logs and events
+func (s *RetentionScheduler) run(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "webhook_delivery_retention"},
+	)
+
+	today := startOfDay(s.now())
+
+	if err := s.store.AggregateWebhookDeliveryStats(ctx, today.AddDate(0, 0, -(statsRecomputeDays-1))); err != nil {
+		s.logger.Error(ctx, "Failed to aggregate webhook delivery stats", err)
+		// Purged days are aggregated as they are purged, so purging can go ahead
+	}
+
+	s.purge(ctx, today)
+	s.redactDeliveries(ctx)
+	s.redactEvents(ctx)
+}
+
+// purge deletes every account's deliveries and events older than its retention period
+func (s *RetentionScheduler) purge(ctx context.Context, today time.Time) {
+	accountIDs, err := s.store.GetRetentionAccountIDs(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get accounts with webhooks", err)
+		return
+	}
+
+	var total, totalEvents int64
+	for _, accountID := range accountIDs {
+		accountCtx := observability.WithFields(ctx, observability.Field{Key: "account_id", Value: accountID})
+
+		days, keepForever, err := s.retentionDays(accountCtx, accountID)
+		if err != nil {
+			s.logger.Error(accountCtx, "Failed to get webhook log retention", err)
+			continue
+		}
+		if keepForever {
+			continue
+		}
+
+		before := today.AddDate(0, 0, -days)
+		purged, err := s.purgeAccount(accountCtx, accountID, before)
+		total += purged
+		if err != nil {
+			s.logger.Error(accountCtx, "Failed to purge webhook deliveries", err)
+		} else if purged > 0 {
+			s.logger.Info(accountCtx, fmt.Sprintf("Purged %d webhook deliveries older than %d days", purged, days))
+		}
+
+		purgedEvents, err := s.purgeAccountEvents(accountCtx, accountID, before)
+		totalEvents += purgedEvents
+		if err != nil {
+			s.logger.Error(accountCtx, "Failed to purge events", err)
+		} else if purgedEvents > 0 {
+			s.logger.Info(accountCtx, fmt.Sprintf("Purged %d events older than %d days", purgedEvents, days))
+		}
+	}
+
+	s.logger.Metrics(ctx,
+		observability.MetricField{Key: "webhook_deliveries_purged", Value: total},
+		observability.MetricField{Key: "events_purged", Value: totalEvents},
+	)
+}
+
+// purgeAccount rolls an account's deliveries created before the cutoff up into the daily stats
+// once, then deletes them in batches
+func (s *RetentionScheduler) purgeAccount(ctx context.Context, accountID uuid.UUID, before time.Time) (int64, error) {
+	if err := s.store.AggregatePurgedWebhookDeliveryStats(ctx, accountID, before); err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for {
+		purged, err := s.store.PurgeWebhookDeliveries(ctx, accountID, before, purgeBatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// purgeAccountEvents deletes an account's events that occurred before the cutoff, in batches
+func (s *RetentionScheduler) purgeAccountEvents(ctx context.Context, accountID uuid.UUID, before time.Time) (int64, error) {
+	var total int64
+	for {
+		purged, err := s.store.PurgeEvents(ctx, accountID, before, purgeBatchSize)
+		total += purged
+		if err != nil {
+			return total, err
+		}
+		if purged < purgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// retentionDays returns the days an account's delivery logs are kept, per its tier's retention
+// limit or the default when the tier has none. keepForever is set when the limit is unlimited.
+func (s *RetentionScheduler) retentionDays(ctx context.Context, accountID uuid.UUID) (days int, keepForever bool, err error) {
+	tierInfo, err := s.tiers.GetTierInfoByAccountID(ctx, accountID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	limit, ok := tierInfo.Limits[RetentionLimit]
+	switch {
+	case !ok:
+		days = s.config.DefaultRetentionDays
+	case limit == nil:
+		return 0, true, nil
+	default:
+		days = *limit
+	}
+
+	// Recent days must stay complete for their stats to be recomputed
+	if days < statsRecomputeDays {
+		days = statsRecomputeDays
+	}
+	return days, false, nil
+}
+
+// redactDeliveries redacts the configured fields from the payloads of deliveries older than the
+// redaction period
+func (s *RetentionScheduler) redactDeliveries(ctx context.Context) {
+	if s.config.RedactAfter <= 0 || len(s.config.RedactFields) == 0 {
+		return
+	}
+
+	before := s.now().Add(-s.config.RedactAfter)
+	redacted := 0
+	for batch := 0; batch < maxRedactBatches; batch++ {
+		deliveries, err := s.store.GetWebhookDeliveriesToRedact(ctx, before, redactBatchSize)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get webhook deliveries to redact", err)
+			break
+		}
+
+		for _, delivery := range deliveries {
+			// Deliveries without any of the fields are marked redacted too, so they are not listed again
+			if err := s.store.RedactWebhookDelivery(ctx, delivery.ID, RedactPayload(delivery.Payload, s.config.RedactFields)); err != nil {
+				s.logger.Error(observability.WithFields(ctx, observability.Field{Key: "delivery_id", Value: delivery.ID}),
+					"Failed to redact webhook delivery", err)
+				return
+			}
+			redacted++
+		}
+
+		if len(deliveries) < redactBatchSize {
+			break
+		}
+	}
+
+	s.logger.Metrics(ctx, observability.MetricField{Key: "webhook_deliveries_redacted", Value: redacted})
+}
+
+// redactEvents redacts the configured fields from the data of events older than the redaction
+// period, so the events API and replays no longer expose them
+func (s *RetentionScheduler) redactEvents(ctx context.Context) {
+	if s.config.RedactAfter <= 0 || len(s.config.RedactFields) == 0 {
+		return
+	}
+
+	before := s.now().Add(-s.config.RedactAfter)
+	redacted := 0
+	for batch := 0; batch < maxRedactBatches; batch++ {
+		events, err := s.store.GetEventsToRedact(ctx, before, redactBatchSize)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get events to redact", err)
+			break
+		}
+
+		for _, event := range events {
+			if err := s.store.RedactEvent(ctx, event.ID, RedactPayload(event.Data, s.config.RedactFields)); err != nil {
+				s.logger.Error(observability.WithFields(ctx, observability.Field{Key: "event_id", Value: event.ID}),
+					"Failed to redact event", err)
+				return
+			}
+			redacted++
+		}
+
+		if len(events) < redactBatchSize {
+			break
+		}
+	}
+
+	s.logger.Metrics(ctx, observability.MetricField{Key: "events_redacted", Value: redacted})
+}
+
+// RedactPayload returns a copy of a delivery payload with the values at the given dot-separated
+// paths replaced. Paths missing from the payload are ignored; objects along a path are copied, so
+// the payload itself is not modified.
+func RedactPayload(payload store.JSONB, fields []string) store.JSONB {
+	if payload == nil {
+		return nil
+	}
+
+	redacted := map[string]interface{}(payload)
+	for _, field := range fields {
+		redacted, _ = redactPath(redacted, strings.Split(field, "."))
+	}
+	return store.JSONB(redacted)
+}
+
+// redactPath returns a copy of data with the value at the path replaced, or data itself and false
+// when the path does not exist
+func redactPath(data map[string]interface{}, path []string) (map[string]interface{}, bool) {
+	value, ok := data[path[0]]
+	if !ok {
+		return data, false
+	}
+
+	var replacement interface{} = redactedValue
+	if len(path) > 1 {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return data, false
+		}
+		redactedNested, changed := redactPath(nested, path[1:])
+		if !changed {
+			return data, false
+		}
+		replacement = redactedNested
+	}
+
+	copied := make(map[string]interface{}, len(data))
+	for key, v := range data {
+		copied[key] = v
+	}
+	copied[path[0]] = replacement
+	return copied, true
+}
+
+// startOfDay returns the start of the UTC day of a time
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,166 @@
+package worker
+
+import (
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/tiers"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func newTestRetentionScheduler(ctrl *gomock.Controller, config RetentionConfig, now time.Time) (*RetentionScheduler, *MockRetentionStore, *MockTierProvider) {
+	mockStore := NewMockRetentionStore(ctrl)
+	mockTiers := NewMockTierProvider(ctrl)
+	scheduler := NewRetentionScheduler(mockStore, mockTiers, config, observability.NewLogger(), time.Hour)
+	scheduler.now = func() time.Time { return now }
+	return scheduler, mockStore, mockTiers
+}
+
+func TestRetentionScheduler_PurgesPerTier(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	scheduler, mockStore, mockTiers := newTestRetentionScheduler(ctrl, RetentionConfig{DefaultRetentionDays: 30}, now)
+
+	freeAccount := uuid.New()
+	noLimitAccount := uuid.New()
+	unlimitedAccount := uuid.New()
+
+	// Stats of the last days are recomputed first
+	mockStore.EXPECT().AggregateWebhookDeliveryStats(gomock.Any(), today.AddDate(0, 0, -2)).Return(nil)
+	mockStore.EXPECT().GetRetentionAccountIDs(gomock.Any()).Return([]uuid.UUID{freeAccount, noLimitAccount, unlimitedAccount}, nil)
+
+	mockTiers.EXPECT().GetTierInfoByAccountID(gomock.Any(), freeAccount).
+		Return(tiers.TierInfo{Limits: map[string]*int{RetentionLimit: intPtr(7)}}, nil)
+	mockTiers.EXPECT().GetTierInfoByAccountID(gomock.Any(), noLimitAccount).
+		Return(tiers.TierInfo{Limits: map[string]*int{}}, nil)
+	mockTiers.EXPECT().GetTierInfoByAccountID(gomock.Any(), unlimitedAccount).
+		Return(tiers.TierInfo{Limits: map[string]*int{RetentionLimit: nil}}, nil)
+
+	// Purged days are aggregated once per account, then a full batch is followed by another one
+	gomock.InOrder(
+		mockStore.EXPECT().AggregatePurgedWebhookDeliveryStats(gomock.Any(), freeAccount, today.AddDate(0, 0, -7)).Return(nil),
+		mockStore.EXPECT().PurgeWebhookDeliveries(gomock.Any(), freeAccount, today.AddDate(0, 0, -7), purgeBatchSize).Return(int64(purgeBatchSize), nil),
+		mockStore.EXPECT().PurgeWebhookDeliveries(gomock.Any(), freeAccount, today.AddDate(0, 0, -7), purgeBatchSize).Return(int64(12), nil),
+	)
+	// Tiers without a retention limit use the default
+	mockStore.EXPECT().AggregatePurgedWebhookDeliveryStats(gomock.Any(), noLimitAccount, today.AddDate(0, 0, -30)).Return(nil)
+	mockStore.EXPECT().PurgeWebhookDeliveries(gomock.Any(), noLimitAccount, today.AddDate(0, 0, -30), purgeBatchSize).Return(int64(0), nil)
+	// Events follow the same retention
+	mockStore.EXPECT().PurgeEvents(gomock.Any(), freeAccount, today.AddDate(0, 0, -7), purgeBatchSize).Return(int64(3), nil)
+	mockStore.EXPECT().PurgeEvents(gomock.Any(), noLimitAccount, today.AddDate(0, 0, -30), purgeBatchSize).Return(int64(0), nil)
+	// Unlimited retention is never purged
+	mockStore.EXPECT().AggregatePurgedWebhookDeliveryStats(gomock.Any(), unlimitedAccount, gomock.Any()).Times(0)
+	mockStore.EXPECT().PurgeWebhookDeliveries(gomock.Any(), unlimitedAccount, gomock.Any(), gomock.Any()).Times(0)
+	mockStore.EXPECT().PurgeEvents(gomock.Any(), unlimitedAccount, gomock.Any(), gomock.Any()).Times(0)
+
+	scheduler.run(context.Background())
+}
+
+func TestRetentionScheduler_KeepsRecomputedDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	scheduler, mockStore, mockTiers := newTestRetentionScheduler(ctrl, RetentionConfig{}, now)
+
+	accountID := uuid.New()
+	mockStore.EXPECT().AggregateWebhookDeliveryStats(gomock.Any(), gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetRetentionAccountIDs(gomock.Any()).Return([]uuid.UUID{accountID}, nil)
+	mockTiers.EXPECT().GetTierInfoByAccountID(gomock.Any(), accountID).
+		Return(tiers.TierInfo{Limits: map[string]*int{RetentionLimit: intPtr(1)}}, nil)
+	mockStore.EXPECT().AggregatePurgedWebhookDeliveryStats(gomock.Any(), accountID, today.AddDate(0, 0, -statsRecomputeDays)).Return(nil)
+	mockStore.EXPECT().PurgeWebhookDeliveries(gomock.Any(), accountID, today.AddDate(0, 0, -statsRecomputeDays), purgeBatchSize).Return(int64(0), nil)
+	mockStore.EXPECT().PurgeEvents(gomock.Any(), accountID, today.AddDate(0, 0, -statsRecomputeDays), purgeBatchSize).Return(int64(0), nil)
+
+	scheduler.run(context.Background())
+}
+
+func TestRetentionScheduler_RedactsOldPayloads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2025, 3, 10, 15, 30, 0, 0, time.UTC)
+	scheduler, mockStore, _ := newTestRetentionScheduler(ctrl, RetentionConfig{
+		RedactAfter:  7 * 24 * time.Hour,
+		RedactFields: []string{"user.email"},
+	}, now)
+
+	deliveryID := uuid.New()
+	mockStore.EXPECT().AggregateWebhookDeliveryStats(gomock.Any(), gomock.Any()).Return(nil)
+	mockStore.EXPECT().GetRetentionAccountIDs(gomock.Any()).Return(nil, nil)
+	mockStore.EXPECT().GetWebhookDeliveriesToRedact(gomock.Any(), now.Add(-7*24*time.Hour), redactBatchSize).
+		Return([]store.WebhookDelivery{{
+			ID:      deliveryID,
+			Payload: store.JSONB{"user": map[string]interface{}{"id": "user", "email": "test@example.com"}},
+		}}, nil)
+	mockStore.EXPECT().RedactWebhookDelivery(gomock.Any(), deliveryID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, payload store.JSONB) error {
+			user := payload["user"].(map[string]interface{})
+			if user["email"] != redactedValue || user["id"] != "user" {
+				t.Errorf("expected only the email to be redacted, got %v", user)
+			}
+			return nil
+		})
+
+	// Event data is redacted the same way
+	eventID := uuid.New()
+	mockStore.EXPECT().GetEventsToRedact(gomock.Any(), now.Add(-7*24*time.Hour), redactBatchSize).
+		Return([]store.Event{{
+			ID:   eventID,
+			Data: store.JSONB{"user": map[string]interface{}{"id": "user", "email": "test@example.com"}},
+		}}, nil)
+	mockStore.EXPECT().RedactEvent(gomock.Any(), eventID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, data store.JSONB) error {
+			user := data["user"].(map[string]interface{})
+			if user["email"] != redactedValue || user["id"] != "user" {
+				t.Errorf("expected only the email to be redacted, got %v", user)
+			}
+			return nil
+		})
+
+	scheduler.run(context.Background())
+}
+
+func TestRedactPayload(t *testing.T) {
+	payload := store.JSONB{
+		"campaign_id": "campaign",
+		"user": map[string]interface{}{
+			"email":      "test@example.com",
+			"first_name": "Test",
+		},
+		"email": map[string]interface{}{"to": "test@example.com"},
+	}
+
+	redacted := RedactPayload(payload, []string{"user.email", "email.to", "user.missing", "campaign_id.nested", "missing.path"})
+
+	user := redacted["user"].(map[string]interface{})
+	if user["email"] != redactedValue || user["first_name"] != "Test" {
+		t.Errorf("unexpected user: %v", user)
+	}
+	if redacted["email"].(map[string]interface{})["to"] != redactedValue {
+		t.Errorf("expected email.to to be redacted, got %v", redacted["email"])
+	}
+	if redacted["campaign_id"] != "campaign" {
+		t.Errorf("expected campaign_id to be kept, got %v", redacted["campaign_id"])
+	}
+	if _, ok := user["missing"]; ok {
+		t.Error("expected missing fields not to be added")
+	}
+
+	// The original payload is not modified
+	if payload["user"].(map[string]interface{})["email"] != "test@example.com" {
+		t.Error("expected the payload not to be modified")
+	}
+}
@@ -0,0 +1,98 @@
+package blast
+
+import (
+	"time"
+
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultABTestPercentage is the share of recipients in the test slice when the blast doesn't set one
+	defaultABTestPercentage = 10
+	// defaultABTestWaitMinutes is how long to wait for opens/clicks before picking a winner
+	defaultABTestWaitMinutes = 240
+	// abTestMaxHold is how long after the waiting window a test without any opens or clicks is
+	// held for engagement events before the blast fails
+	abTestMaxHold = 24 * time.Hour
+)
+
+// abTestSliceSize returns how many recipients get the A/B test variants.
+// Every variant gets at least one recipient and the slice never exceeds the total.
+func abTestSliceSize(totalRecipients int, percentage *int, variantCount int) int {
+	pct := defaultABTestPercentage
+	if percentage != nil {
+		pct = *percentage
+	}
+
+	size := (totalRecipients*pct + 99) / 100
+	if size < variantCount {
+		size = variantCount
+	}
+	if size > totalRecipients {
+		size = totalRecipients
+	}
+	return size
+}
+
+// isAwaitingABTestWinner reports whether a batch belongs to the remainder of an A/B tested
+// blast whose winner has not been picked yet
+func isAwaitingABTestWinner(blast store.EmailBlast, batchNumber int) bool {
+	return blast.ABTestLastBatch != nil &&
+		batchNumber > *blast.ABTestLastBatch &&
+		blast.ABTestWinnerVariantID == nil
+}
+
+// variantForRecipient returns the variant a recipient should receive: its assigned test variant,
+// or the winning variant for the remainder of the blast
+func variantForRecipient(recipient store.BlastRecipient, blast store.EmailBlast, variants map[uuid.UUID]store.EmailBlastVariant) (store.EmailBlastVariant, bool) {
+	if recipient.VariantID != nil {
+		if v, ok := variants[*recipient.VariantID]; ok {
+			return v, true
+		}
+	}
+
+	if blast.ABTestWinnerVariantID != nil {
+		if v, ok := variants[*blast.ABTestWinnerVariantID]; ok {
+			return v, true
+		}
+	}
+
+	return store.EmailBlastVariant{}, false
+}
+
+// pickWinningVariant returns the variant with the best open or click rate. Ties go to the
+// earliest variant. No winner is picked while no variant has any opens or clicks for the metric,
+// since the first variant would win by default.
+func pickWinningVariant(stats []store.BlastVariantStats, metric string) (uuid.UUID, bool) {
+	if len(stats) == 0 {
+		return uuid.Nil, false
+	}
+
+	best := stats[0]
+	bestRate := variantRate(best, metric)
+	for _, s := range stats[1:] {
+		if r := variantRate(s, metric); r > bestRate {
+			best, bestRate = s, r
+		}
+	}
+
+	if bestRate == 0 {
+		return uuid.Nil, false
+	}
+	return best.VariantID, true
+}
+
+// variantRate returns the open or click rate of a variant's test slice
+func variantRate(stats store.BlastVariantStats, metric string) float64 {
+	if stats.Sent == 0 {
+		return 0
+	}
+
+	count := stats.Opened
+	if metric == string(store.BlastABTestMetricClickRate) {
+		count = stats.Clicked
+	}
+	return float64(count) / float64(stats.Sent)
+}
@@ -0,0 +1,16 @@
+package blast
+
+import "base-server/internal/store"
+
+// defaultFallbackTimezone is the timezone of recipients without one when the blast doesn't set a fallback
+const defaultFallbackTimezone = "UTC"
+
+// cohortForBatch returns the timezone cohort a batch of a local time blast belongs to
+func cohortForBatch(cohorts []store.EmailBlastCohort, batchNumber int) (store.EmailBlastCohort, bool) {
+	for _, c := range cohorts {
+		if batchNumber >= c.FirstBatch && batchNumber <= c.LastBatch {
+			return c, true
+		}
+	}
+	return store.EmailBlastCohort{}, false
+}
@@ -0,0 +1,92 @@
+package blast
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// recipientLeaseDuration is how long a worker holds the recipients it claimed without a heartbeat
+	recipientLeaseDuration = 2 * time.Minute
+
+	// maxRecipientAttempts is how often a recipient is claimed before an expired lease marks it failed
+	maxRecipientAttempts = 3
+
+	// recordSendAttempts is how often recording a sent email is tried before the batch is stopped
+	recordSendAttempts = 3
+	// recordSendRetryDelay is the pause between attempts to record a sent email
+	recordSendRetryDelay = 500 * time.Millisecond
+)
+
+// newWorkerID identifies this worker process as the owner of recipient leases
+func newWorkerID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8])
+}
+
+// recipientIdempotencyKey identifies the send to a blast recipient, so the mail provider
+// delivers it only once even when the recipient is claimed again after a crash
+func recipientIdempotencyKey(blastID, recipientID uuid.UUID) string {
+	return fmt.Sprintf("blast-%s-%s", blastID, recipientID)
+}
+
+// heartbeatLeases extends the leases this worker holds on the recipients of a blast until the
+// returned stop function is called
+func (p *BlastEventProcessor) heartbeatLeases(ctx context.Context, blastID uuid.UUID) (stop func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(recipientLeaseDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := p.store.ExtendBlastRecipientLeases(ctx, blastID, p.workerID, recipientLeaseDuration); err != nil && ctx.Err() == nil {
+					p.logger.Error(ctx, "Failed to extend blast recipient leases", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// markRecipientSent records a sent email, retrying failed writes. A recipient left in sending is
+// claimed again once its lease expires, and only the idempotency key then keeps it from being
+// emailed twice.
+func (p *BlastEventProcessor) markRecipientSent(ctx context.Context, recipient store.BlastRecipient, messageID string) error {
+	var err error
+	for attempt := 1; attempt <= recordSendAttempts; attempt++ {
+		if err = p.store.MarkBlastRecipientSent(ctx, recipient.ID, messageID); err == nil {
+			return nil
+		}
+		if attempt == recordSendAttempts {
+			break
+		}
+
+		p.logger.Warn(ctx, fmt.Sprintf("Failed to record sent email (attempt %d of %d), retrying: %v", attempt, recordSendAttempts, err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(recordSendRetryDelay):
+		}
+	}
+	return fmt.Errorf("failed to record sent email: %w", err)
+}
@@ -3,6 +3,7 @@ package blast
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,12 +23,27 @@ type BlastStore interface {
 	GetBlastEmailTemplateByID(ctx context.Context, templateID uuid.UUID) (store.BlastEmailTemplate, error)
 	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
 	UpdateEmailBlastStatus(ctx context.Context, blastID uuid.UUID, status string, errorMessage *string) (store.EmailBlast, error)
+	CompleteEmailBlast(ctx context.Context, blastID uuid.UUID) (bool, error)
 	UpdateEmailBlastTotalRecipients(ctx context.Context, blastID uuid.UUID, totalRecipients int) error
 	UpdateEmailBlastProgressWithSent(ctx context.Context, blastID uuid.UUID, sentCount int, currentBatch int) error
 	CreateBlastRecipientsFromMultipleSegments(ctx context.Context, blastID uuid.UUID, segmentIDs []uuid.UUID, batchSize int) (int, error)
+	CreateBlastRecipientsFromAudience(ctx context.Context, blastID, audienceID uuid.UUID, batchSize int) (int, error)
 	GetBlastRecipientsByBatch(ctx context.Context, blastID uuid.UUID, batchNumber int) ([]store.BlastRecipient, error)
 	UpdateBlastRecipientStatus(ctx context.Context, recipientID uuid.UUID, status string, emailLogID *uuid.UUID, errorMessage *string) error
+	MarkBlastRecipientSent(ctx context.Context, recipientID uuid.UUID, providerMessageID string) error
 	CountBlastRecipientsByStatus(ctx context.Context, blastID uuid.UUID, status string) (int, error)
+	GetEmailBlastVariantsByBlast(ctx context.Context, blastID uuid.UUID) ([]store.EmailBlastVariant, error)
+	AssignBlastTestVariants(ctx context.Context, blastID uuid.UUID, variantIDs []uuid.UUID, testSize, batchSize int) (int, error)
+	StartEmailBlastABTestWait(ctx context.Context, blastID uuid.UUID, endsAt time.Time) error
+	CreateBlastRecipientCohortsFromMultipleSegments(ctx context.Context, blastID uuid.UUID, segmentIDs []uuid.UUID, batchSize int, sendAt, fallbackTimezone string, after time.Time) (int, error)
+	CreateBlastRecipientCohortsFromAudience(ctx context.Context, blastID, audienceID uuid.UUID, batchSize int, sendAt, fallbackTimezone string, after time.Time) (int, error)
+	GetEmailBlastCohorts(ctx context.Context, blastID uuid.UUID) ([]store.EmailBlastCohort, error)
+	TakeSendRateToken(ctx context.Context, bucketKey string, ratePerSecond float64, burst int) (time.Duration, error)
+	ThrottleSendRateBucket(ctx context.Context, bucketKey string, ratePerSecond float64, burst int, retryAfter time.Duration) error
+	DeleteBlastSendRateBucket(ctx context.Context, blastID uuid.UUID) error
+	ClaimBlastRecipients(ctx context.Context, blastID uuid.UUID, batchNumber int, owner string, lease time.Duration) ([]store.BlastRecipient, error)
+	ExtendBlastRecipientLeases(ctx context.Context, blastID uuid.UUID, owner string, lease time.Duration) error
+	ReleaseBlastRecipientLeases(ctx context.Context, blastID uuid.UUID, owner string) error
 }
 
 // BlastEventProcessor implements the EventProcessor interface for blast events.
@@ -36,20 +52,26 @@ type BlastEventProcessor struct {
 	store           BlastStore
 	emailService    *email.EmailService
 	eventDispatcher *events.EventDispatcher
+	sendRates       SendRates
+	workerID        string
 	logger          *observability.Logger
 }
 
 // NewBlastEventProcessor creates a new blast event processor.
+// Sends are limited to sendRates shared by all workers, and to each blast's SendThrottlePerSecond.
 func NewBlastEventProcessor(
 	store BlastStore,
 	emailService *email.EmailService,
 	eventDispatcher *events.EventDispatcher,
+	sendRates SendRates,
 	logger *observability.Logger,
 ) workers.EventProcessor {
 	return &BlastEventProcessor{
 		store:           store,
 		emailService:    emailService,
 		eventDispatcher: eventDispatcher,
+		sendRates:       sendRates,
+		workerID:        newWorkerID(),
 		logger:          logger,
 	}
 }
@@ -85,8 +107,12 @@ func (p *BlastEventProcessor) Process(ctx context.Context, event workers.EventMe
 
 // handleBlastStarted initializes the blast by:
 // 1. Creating blast recipients from all segments (with deduplication)
-// 2. Updating blast status to "sending"
-// 3. Dispatching first batch event
+// 2. Assigning the A/B test slice to variants (if the blast has variants)
+// 3. Updating blast status to "sending"
+// 4. Dispatching first batch event
+//
+// Blasts sent at each recipient's local time split recipients into timezone cohorts instead.
+// The scheduler dispatches the first batch of each cohort once its local send time arrives.
 func (p *BlastEventProcessor) handleBlastStarted(ctx context.Context, event workers.EventMessage) error {
 	// Parse event data
 	blastID, accountID, err := p.parseBlastEventData(event)
@@ -116,8 +142,17 @@ func (p *BlastEventProcessor) handleBlastStarted(ctx context.Context, event work
 		batchSize = 100
 	}
 
-	// Create recipients from all segments with deduplication
-	totalRecipients, err := p.store.CreateBlastRecipientsFromMultipleSegments(ctx, blastID, blast.SegmentIDs, batchSize)
+	if blast.SendAtLocalTime != nil {
+		return p.startLocalTimeBlast(ctx, blast, batchSize)
+	}
+
+	// Create recipients from the audience or all segments with deduplication
+	var totalRecipients int
+	if blast.AudienceID != nil {
+		totalRecipients, err = p.store.CreateBlastRecipientsFromAudience(ctx, blastID, *blast.AudienceID, batchSize)
+	} else {
+		totalRecipients, err = p.store.CreateBlastRecipientsFromMultipleSegments(ctx, blastID, blast.SegmentIDs, batchSize)
+	}
 	if err != nil {
 		return p.failBlast(ctx, blastID, fmt.Errorf("failed to create blast recipients: %w", err))
 	}
@@ -138,6 +173,27 @@ func (p *BlastEventProcessor) handleBlastStarted(ctx context.Context, event work
 		return p.failBlast(ctx, blastID, fmt.Errorf("failed to update total recipients: %w", err))
 	}
 
+	// Split off the A/B test slice so it is sent in the first batches
+	variants, err := p.store.GetEmailBlastVariantsByBlast(ctx, blastID)
+	if err != nil {
+		return p.failBlast(ctx, blastID, fmt.Errorf("failed to get blast variants: %w", err))
+	}
+
+	if len(variants) > 1 {
+		variantIDs := make([]uuid.UUID, len(variants))
+		for i, v := range variants {
+			variantIDs[i] = v.ID
+		}
+
+		testSize := abTestSliceSize(totalRecipients, blast.ABTestPercentage, len(variants))
+		lastTestBatch, err := p.store.AssignBlastTestVariants(ctx, blastID, variantIDs, testSize, batchSize)
+		if err != nil {
+			return p.failBlast(ctx, blastID, fmt.Errorf("failed to assign test variants: %w", err))
+		}
+
+		p.logger.Info(ctx, fmt.Sprintf("A/B test slice of %d recipients assigned to %d variants (batches 1-%d)", testSize, len(variants), lastTestBatch))
+	}
+
 	// Update status to sending
 	_, err = p.store.UpdateEmailBlastStatus(ctx, blastID, string(store.EmailBlastStatusSending), nil)
 	if err != nil {
@@ -154,11 +210,57 @@ func (p *BlastEventProcessor) handleBlastStarted(ctx context.Context, event work
 	return nil
 }
 
+// startLocalTimeBlast creates the recipients of a local time blast in timezone cohorts and
+// moves it to "sending". No batch is dispatched here; cohorts are released by the scheduler.
+func (p *BlastEventProcessor) startLocalTimeBlast(ctx context.Context, blast store.EmailBlast, batchSize int) error {
+	fallbackTimezone := defaultFallbackTimezone
+	if blast.FallbackTimezone != nil {
+		fallbackTimezone = *blast.FallbackTimezone
+	}
+
+	var totalRecipients int
+	var err error
+	if blast.AudienceID != nil {
+		totalRecipients, err = p.store.CreateBlastRecipientCohortsFromAudience(ctx, blast.ID, *blast.AudienceID, batchSize, *blast.SendAtLocalTime, fallbackTimezone, time.Now())
+	} else {
+		totalRecipients, err = p.store.CreateBlastRecipientCohortsFromMultipleSegments(ctx, blast.ID, blast.SegmentIDs, batchSize, *blast.SendAtLocalTime, fallbackTimezone, time.Now())
+	}
+	if err != nil {
+		return p.failBlast(ctx, blast.ID, fmt.Errorf("failed to create blast recipient cohorts: %w", err))
+	}
+
+	if totalRecipients == 0 {
+		_, err = p.store.UpdateEmailBlastStatus(ctx, blast.ID, string(store.EmailBlastStatusCompleted), nil)
+		if err != nil {
+			return fmt.Errorf("failed to update blast status: %w", err)
+		}
+		p.logger.Info(ctx, "Blast completed with no recipients")
+		return nil
+	}
+
+	err = p.store.UpdateEmailBlastTotalRecipients(ctx, blast.ID, totalRecipients)
+	if err != nil {
+		return p.failBlast(ctx, blast.ID, fmt.Errorf("failed to update total recipients: %w", err))
+	}
+
+	_, err = p.store.UpdateEmailBlastStatus(ctx, blast.ID, string(store.EmailBlastStatusSending), nil)
+	if err != nil {
+		return p.failBlast(ctx, blast.ID, fmt.Errorf("failed to update blast status: %w", err))
+	}
+
+	p.logger.Info(ctx, fmt.Sprintf("Blast started with %d recipients, waiting for %s local time in each timezone", totalRecipients, *blast.SendAtLocalTime))
+	return nil
+}
+
 // handleBlastBatch processes a batch of recipients:
-// 1. Get recipients for the batch
+// 1. Claim the unsent recipients of the batch
 // 2. Send emails to each recipient
 // 3. Update recipient statuses
 // 4. Either dispatch next batch or complete blast
+//
+// Claimed recipients are leased to this worker and the lease is extended while the batch is sent.
+// If the worker dies, the scheduler returns the recipients to pending once the lease expires and
+// dispatches the batch again. Recipients that were already sent are never claimed again.
 func (p *BlastEventProcessor) handleBlastBatch(ctx context.Context, event workers.EventMessage) error {
 	blastID, accountID, err := p.parseBlastEventData(event)
 	if err != nil {
@@ -188,54 +290,98 @@ func (p *BlastEventProcessor) handleBlastBatch(ctx context.Context, event worker
 		return nil
 	}
 
+	// Remaining recipients of an A/B test are held back until a winner is picked
+	if isAwaitingABTestWinner(blast, batchNumber) {
+		p.logger.Info(ctx, "Blast is waiting for A/B test winner, skipping batch")
+		return nil
+	}
+
+	// Batches of a local time blast are only sent once their timezone cohort is released.
+	// Each cohort is sent as its own chain of batches that ends at the cohort's last batch.
+	endsCohort := false
+	if blast.SendAtLocalTime != nil {
+		cohorts, err := p.store.GetEmailBlastCohorts(ctx, blastID)
+		if err != nil {
+			return p.failBlast(ctx, blastID, fmt.Errorf("failed to get blast cohorts: %w", err))
+		}
+
+		cohort, ok := cohortForBatch(cohorts, batchNumber)
+		if !ok {
+			return p.checkBlastCompletion(ctx, blastID, accountID)
+		}
+		if cohort.ReleasedAt == nil {
+			p.logger.Info(ctx, fmt.Sprintf("Timezone cohort %s is not released yet, skipping batch", cohort.Timezone))
+			return nil
+		}
+		endsCohort = batchNumber == cohort.LastBatch
+	}
+
 	// Get template
 	template, err := p.store.GetBlastEmailTemplateByID(ctx, blast.BlastTemplateID)
 	if err != nil {
 		return p.failBlast(ctx, blastID, fmt.Errorf("failed to get template: %w", err))
 	}
 
-	// Get recipients for this batch
-	recipients, err := p.store.GetBlastRecipientsByBatch(ctx, blastID, batchNumber)
-	if err != nil {
-		return p.failBlast(ctx, blastID, fmt.Errorf("failed to get batch recipients: %w", err))
+	// Load variants and their templates for A/B tested blasts
+	variants := make(map[uuid.UUID]store.EmailBlastVariant)
+	templates := map[uuid.UUID]store.BlastEmailTemplate{template.ID: template}
+	if blast.ABTestLastBatch != nil {
+		blastVariants, err := p.store.GetEmailBlastVariantsByBlast(ctx, blastID)
+		if err != nil {
+			return p.failBlast(ctx, blastID, fmt.Errorf("failed to get blast variants: %w", err))
+		}
+
+		for _, v := range blastVariants {
+			variants[v.ID] = v
+			if _, ok := templates[v.BlastTemplateID]; ok {
+				continue
+			}
+			variantTemplate, err := p.store.GetBlastEmailTemplateByID(ctx, v.BlastTemplateID)
+			if err != nil {
+				return p.failBlast(ctx, blastID, fmt.Errorf("failed to get variant template: %w", err))
+			}
+			templates[v.BlastTemplateID] = variantTemplate
+		}
 	}
 
-	if len(recipients) == 0 {
-		// No more recipients - check if blast should complete
-		return p.checkBlastCompletion(ctx, blastID, accountID)
+	// Claim the unsent recipients of this batch
+	recipients, err := p.store.ClaimBlastRecipients(ctx, blastID, batchNumber, p.workerID, recipientLeaseDuration)
+	if err != nil {
+		return p.failBlast(ctx, blastID, fmt.Errorf("failed to claim batch recipients: %w", err))
 	}
 
-	// Process each recipient
-	sentCount := 0
-	for _, recipient := range recipients {
-		// Check if blast is still sending (in case it was paused mid-batch)
-		if batchNumber > 1 && sentCount%10 == 0 {
-			blast, err = p.store.GetEmailBlastByID(ctx, blastID)
-			if err != nil || blast.Status != string(store.EmailBlastStatusSending) {
-				p.logger.Info(ctx, "Blast is no longer sending, stopping batch processing")
-				return nil
+	if len(recipients) > 0 {
+		sentCount, stopped, err := p.sendBatch(ctx, blast, batchNumber, recipients, template, variants, templates)
+		if err != nil {
+			// Count the emails sent before the batch stopped
+			if sentCount > 0 {
+				if progressErr := p.store.UpdateEmailBlastProgressWithSent(ctx, blastID, blast.SentCount+sentCount, batchNumber); progressErr != nil {
+					p.logger.Error(ctx, "Failed to update blast progress", progressErr)
+				}
 			}
+			return err
+		}
+		if stopped {
+			p.logger.Info(ctx, "Blast is no longer sending, stopping batch processing")
+			return nil
 		}
 
-		err = p.sendBlastEmail(ctx, recipient, blast, template)
+		// Update blast progress
+		err = p.store.UpdateEmailBlastProgressWithSent(ctx, blastID, blast.SentCount+sentCount, batchNumber)
 		if err != nil {
-			// Log error but continue with other recipients
-			p.logger.Error(ctx, fmt.Sprintf("Failed to send email to %s", recipient.Email), err)
-			errMsg := err.Error()
-			_ = p.store.UpdateBlastRecipientStatus(ctx, recipient.ID, string(store.BlastRecipientStatusFailed), nil, &errMsg)
-		} else {
-			_ = p.store.UpdateBlastRecipientStatus(ctx, recipient.ID, string(store.BlastRecipientStatusSent), nil, nil)
-			sentCount++
+			p.logger.Error(ctx, "Failed to update blast progress", err)
 		}
-	}
 
-	// Update blast progress
-	err = p.store.UpdateEmailBlastProgressWithSent(ctx, blastID, blast.SentCount+sentCount, batchNumber)
-	if err != nil {
-		p.logger.Error(ctx, "Failed to update blast progress", err)
+		p.logger.Info(ctx, fmt.Sprintf("Batch %d completed: sent %d emails", batchNumber, sentCount))
+	} else {
+		// Already sent, or being sent by another worker - carry on with the next batch
+		p.logger.Info(ctx, fmt.Sprintf("Batch %d has no unsent recipients", batchNumber))
 	}
 
-	p.logger.Info(ctx, fmt.Sprintf("Batch %d completed: sent %d emails", batchNumber, sentCount))
+	if endsCohort {
+		// The next cohort is dispatched by the scheduler when its local send time arrives
+		return p.checkBlastCompletion(ctx, blastID, accountID)
+	}
 
 	// Check if there are more batches
 	nextBatchRecipients, err := p.store.GetBlastRecipientsByBatch(ctx, blastID, batchNumber+1)
@@ -243,7 +389,26 @@ func (p *BlastEventProcessor) handleBlastBatch(ctx context.Context, event worker
 		return p.failBlast(ctx, blastID, fmt.Errorf("failed to check next batch: %w", err))
 	}
 
-	if len(nextBatchRecipients) > 0 {
+	if len(nextBatchRecipients) > 0 && isAwaitingABTestWinner(blast, batchNumber+1) {
+		if blast.ABTestEndsAt != nil {
+			// The wait was already started when this batch was first sent
+			return nil
+		}
+
+		// Test slice sent - the scheduler sends the winner to the remaining recipients once the wait is over
+		waitMinutes := defaultABTestWaitMinutes
+		if blast.ABTestWaitMinutes != nil {
+			waitMinutes = *blast.ABTestWaitMinutes
+		}
+		endsAt := time.Now().Add(time.Duration(waitMinutes) * time.Minute)
+
+		err = p.store.StartEmailBlastABTestWait(ctx, blastID, endsAt)
+		if err != nil {
+			return p.failBlast(ctx, blastID, fmt.Errorf("failed to start A/B test wait: %w", err))
+		}
+
+		p.logger.Info(ctx, fmt.Sprintf("A/B test slice sent, picking winner at %v", endsAt))
+	} else if len(nextBatchRecipients) > 0 {
 		// Dispatch next batch
 		err = p.eventDispatcher.DispatchBlastBatchSend(ctx, accountID, blastID, batchNumber+1)
 		if err != nil {
@@ -257,7 +422,77 @@ func (p *BlastEventProcessor) handleBlastBatch(ctx context.Context, event worker
 	return nil
 }
 
-// handleBlastCompleted marks the blast as completed
+// sendBatch sends to the claimed recipients of a batch while heartbeating their leases.
+// If the blast stops sending mid-batch, the unsent recipients are released to pending and stopped is true.
+func (p *BlastEventProcessor) sendBatch(
+	ctx context.Context,
+	blast store.EmailBlast,
+	batchNumber int,
+	recipients []store.BlastRecipient,
+	template store.BlastEmailTemplate,
+	variants map[uuid.UUID]store.EmailBlastVariant,
+	templates map[uuid.UUID]store.BlastEmailTemplate,
+) (sentCount int, stopped bool, err error) {
+	blastID := blast.ID
+
+	stopHeartbeat := p.heartbeatLeases(ctx, blastID)
+	defer stopHeartbeat()
+
+	// Process each recipient
+	for _, recipient := range recipients {
+		// Check if blast is still sending (in case it was paused mid-batch)
+		if batchNumber > 1 && sentCount%10 == 0 {
+			current, err := p.store.GetEmailBlastByID(ctx, blastID)
+			if err != nil || current.Status != string(store.EmailBlastStatusSending) {
+				if err := p.store.ReleaseBlastRecipientLeases(ctx, blastID, p.workerID); err != nil {
+					p.logger.Error(ctx, "Failed to release blast recipient leases", err)
+				}
+				return sentCount, true, nil
+			}
+		}
+
+		subject, recipientTemplate := blast.Subject, template
+		if variant, ok := variantForRecipient(recipient, blast, variants); ok {
+			subject, recipientTemplate = variant.Subject, templates[variant.BlastTemplateID]
+		}
+
+		messageID, err := p.sendBlastEmailThrottled(ctx, blast, recipient, subject, recipientTemplate)
+		if errors.Is(err, errSendRateLimiter) {
+			if ctx.Err() != nil {
+				return sentCount, false, ctx.Err()
+			}
+			return sentCount, false, p.failBlast(ctx, blastID, err)
+		}
+		recipientCtx := observability.WithFields(ctx,
+			observability.Field{Key: "recipient_id", Value: recipient.ID},
+			observability.Field{Key: "recipient_email", Value: recipient.Email},
+		)
+		if err != nil {
+			// Log error but continue with other recipients
+			p.logger.Error(recipientCtx, "Failed to send blast email", err)
+			errMsg := err.Error()
+			if err := p.store.UpdateBlastRecipientStatus(recipientCtx, recipient.ID, string(store.BlastRecipientStatusFailed), nil, &errMsg); err != nil {
+				// The recipient is claimed again once its lease expires
+				p.logger.Error(recipientCtx, "Failed to mark blast recipient as failed", err)
+			}
+			continue
+		}
+
+		// The message ID matches the opens and clicks the A/B test winner is picked by
+		if err := p.markRecipientSent(recipientCtx, recipient, messageID); err != nil {
+			// Stop rather than send on while sends can't be recorded; the recipient is claimed
+			// again once its lease expires and its idempotency key keeps it from a second email
+			p.logger.Error(recipientCtx, "Failed to record sent blast email, stopping batch", err)
+			return sentCount, false, err
+		}
+		sentCount++
+	}
+
+	return sentCount, false, nil
+}
+
+// handleBlastCompleted marks the blast as completed. Blasts are completed before the event is
+// dispatched, so this only completes blasts whose event was dispatched without it.
 func (p *BlastEventProcessor) handleBlastCompleted(ctx context.Context, event workers.EventMessage) error {
 	blastID, _, err := p.parseBlastEventData(event)
 	if err != nil {
@@ -268,40 +503,44 @@ func (p *BlastEventProcessor) handleBlastCompleted(ctx context.Context, event wo
 		observability.Field{Key: "blast_id", Value: blastID},
 	)
 
-	// Update blast status to completed
-	now := time.Now()
-	blast, err := p.store.GetEmailBlastByID(ctx, blastID)
+	completed, err := p.store.CompleteEmailBlast(ctx, blastID)
 	if err != nil {
-		return fmt.Errorf("failed to get blast: %w", err)
+		return fmt.Errorf("failed to update blast status: %w", err)
 	}
-
-	if blast.Status == string(store.EmailBlastStatusSending) {
-		_, err = p.store.UpdateEmailBlastStatus(ctx, blastID, string(store.EmailBlastStatusCompleted), nil)
-		if err != nil {
-			return fmt.Errorf("failed to update blast status: %w", err)
-		}
-		p.logger.Info(ctx, fmt.Sprintf("Blast completed at %v", now))
+	if completed {
+		p.logger.Info(ctx, fmt.Sprintf("Blast completed at %v", time.Now()))
+		p.deleteSendRateBucket(ctx, blastID)
 	}
 
 	return nil
 }
 
-// sendBlastEmail sends a single email for the blast
-func (p *BlastEventProcessor) sendBlastEmail(ctx context.Context, recipient store.BlastRecipient, blast store.EmailBlast, template store.BlastEmailTemplate) error {
-	// Prepare template data
+// sendBlastEmail sends a single email for the blast and returns the provider message ID
+func (p *BlastEventProcessor) sendBlastEmail(ctx context.Context, recipient store.BlastRecipient, subject string, template store.BlastEmailTemplate) (string, error) {
+	// Blast templates are validated against email.BlastTemplateVariables, so only the email
+	// address is filled in
 	data := email.TemplateData{
 		Email: recipient.Email,
-		// TODO: Add more template variables like first_name, position, etc.
-		// This would require fetching the WaitlistUser data
 	}
 
+	body, err := email.TemplateBody(template.HTMLBody, template.BlocksJSON)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := email.TemplateSendOptions(template.TextBody, template.Headers, template.Attachments)
+	if err != nil {
+		return "", err
+	}
+	opts.IdempotencyKey = recipientIdempotencyKey(recipient.BlastID, recipient.ID)
+
 	// Render and send email
-	err := p.emailService.SendCustomTemplateEmail(ctx, recipient.Email, blast.Subject, template.HTMLBody, data)
+	messageID, err := p.emailService.SendCustomTemplateEmail(ctx, recipient.Email, subject, body, data, opts)
 	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
+		return "", fmt.Errorf("failed to send email: %w", err)
 	}
 
-	return nil
+	return messageID, nil
 }
 
 // failBlast marks a blast as failed with an error message
@@ -314,15 +553,40 @@ func (p *BlastEventProcessor) failBlast(ctx context.Context, blastID uuid.UUID,
 	return err
 }
 
-// checkBlastCompletion checks if all recipients have been processed and completes the blast
+// deleteSendRateBucket removes the blast's own send rate bucket after its last send. Failures are
+// logged but do not fail the blast since the bucket is only left unused.
+func (p *BlastEventProcessor) deleteSendRateBucket(ctx context.Context, blastID uuid.UUID) {
+	if err := p.store.DeleteBlastSendRateBucket(ctx, blastID); err != nil {
+		p.logger.Error(ctx, "Failed to delete blast send rate bucket", err)
+	}
+}
+
+// checkBlastCompletion checks if all recipients have been processed and completes the blast.
+// Several workers can find the blast finished at once; only the one that completes it dispatches
+// the completion event.
 func (p *BlastEventProcessor) checkBlastCompletion(ctx context.Context, blastID, accountID uuid.UUID) error {
-	// Check if there are any pending recipients
+	// Check if there are any pending recipients, or recipients still being sent by another worker
 	pendingCount, err := p.store.CountBlastRecipientsByStatus(ctx, blastID, string(store.BlastRecipientStatusPending))
 	if err != nil {
 		return fmt.Errorf("failed to count pending recipients: %w", err)
 	}
 
-	if pendingCount == 0 {
+	sendingCount, err := p.store.CountBlastRecipientsByStatus(ctx, blastID, string(store.BlastRecipientStatusSending))
+	if err != nil {
+		return fmt.Errorf("failed to count sending recipients: %w", err)
+	}
+
+	if pendingCount == 0 && sendingCount == 0 {
+		completed, err := p.store.CompleteEmailBlast(ctx, blastID)
+		if err != nil {
+			return fmt.Errorf("failed to update blast status: %w", err)
+		}
+		if !completed {
+			return nil
+		}
+		p.logger.Info(ctx, fmt.Sprintf("Blast completed at %v", time.Now()))
+		p.deleteSendRateBucket(ctx, blastID)
+
 		// All done - dispatch completion event
 		err = p.eventDispatcher.DispatchBlastCompleted(ctx, accountID, blastID)
 		if err != nil {
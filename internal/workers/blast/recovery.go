@@ -0,0 +1,107 @@
+package blast
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+)
+
+// defaultStallTimeout is how long a blast may go without progress before it is marked failed
+const defaultStallTimeout = 30 * time.Minute
+
+// checkExpiredLeases returns recipients claimed by workers that stopped heartbeating to pending
+// and dispatches their batches again. The idempotency key of each recipient keeps the provider
+// from delivering a message twice if the worker died after sending it.
+func (s *BlastScheduler) checkExpiredLeases(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "check_expired_leases"},
+	)
+
+	batches, err := s.store.RequeueExpiredBlastRecipients(ctx, maxRecipientAttempts)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to requeue expired blast recipients", err)
+		return
+	}
+
+	for _, batch := range batches {
+		batchCtx := observability.WithFields(ctx,
+			observability.Field{Key: "blast_id", Value: batch.BlastID},
+			observability.Field{Key: "account_id", Value: batch.AccountID},
+			observability.Field{Key: "batch_number", Value: batch.BatchNumber},
+		)
+
+		err := s.eventDispatcher.DispatchBlastBatchSend(batchCtx, batch.AccountID, batch.BlastID, batch.BatchNumber)
+		if err != nil {
+			// The blast is marked failed by checkStalledBlasts if it makes no further progress
+			s.logger.Error(batchCtx, "Failed to dispatch batch with expired leases", err)
+			continue
+		}
+
+		s.logger.Info(batchCtx, "Dispatched batch again after its recipient leases expired")
+	}
+}
+
+// checkStalledBlasts marks processing and sending blasts that made no progress for the stall
+// timeout as failed. Sending blasts with no recipients left were only missing their completion,
+// so they are completed instead and the completion event is dispatched unless a worker completed
+// the blast meanwhile.
+func (s *BlastScheduler) checkStalledBlasts(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "check_stalled_blasts"},
+	)
+
+	blasts, err := s.store.GetStalledEmailBlasts(ctx, time.Now().Add(-s.stallTimeout))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get stalled blasts", err)
+		return
+	}
+
+	for _, blast := range blasts {
+		blastCtx := observability.WithFields(ctx,
+			observability.Field{Key: "blast_id", Value: blast.ID},
+			observability.Field{Key: "account_id", Value: blast.AccountID},
+		)
+
+		if blast.Status == string(store.EmailBlastStatusSending) && !blast.Outstanding() {
+			completed, err := s.store.CompleteEmailBlast(blastCtx, blast.ID)
+			if err != nil {
+				s.logger.Error(blastCtx, "Failed to complete finished blast", err)
+				continue
+			}
+			if !completed {
+				continue
+			}
+
+			s.logger.Info(blastCtx, "Completed blast that was missing its completion")
+			if err := s.store.DeleteBlastSendRateBucket(blastCtx, blast.ID); err != nil {
+				s.logger.Error(blastCtx, "Failed to delete blast send rate bucket", err)
+			}
+			if err := s.eventDispatcher.DispatchBlastCompleted(blastCtx, blast.AccountID, blast.ID); err != nil {
+				s.logger.Error(blastCtx, "Failed to dispatch completion for finished blast", err)
+			}
+			continue
+		}
+
+		errMsg := stalledBlastMessage(blast, s.stallTimeout)
+		_, err := s.store.UpdateEmailBlastStatus(blastCtx, blast.ID, string(store.EmailBlastStatusFailed), &errMsg)
+		if err != nil {
+			s.logger.Error(blastCtx, "Failed to mark stalled blast as failed", err)
+			continue
+		}
+
+		s.logger.Info(blastCtx, fmt.Sprintf("Marked stalled blast as failed: %s", errMsg))
+	}
+}
+
+// stalledBlastMessage describes where a stalled blast got stuck
+func stalledBlastMessage(blast store.StalledEmailBlast, stallTimeout time.Duration) string {
+	msg := fmt.Sprintf("blast made no progress for %v while %s (last progress at %s, batch %d, %d recipients pending, %d in flight)",
+		stallTimeout, blast.Status, blast.LastProgressAt.UTC().Format(time.RFC3339), blast.CurrentBatch, blast.Pending, blast.InFlight)
+	if blast.LastError != nil {
+		msg += fmt.Sprintf("; last recipient error: %s", *blast.LastError)
+	}
+	return msg
+}
@@ -2,6 +2,7 @@ package blast
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,14 +17,28 @@ import (
 type SchedulerStore interface {
 	GetScheduledBlasts(ctx context.Context, beforeTime time.Time) ([]store.EmailBlast, error)
 	UpdateEmailBlastStatus(ctx context.Context, blastID uuid.UUID, status string, errorMessage *string) (store.EmailBlast, error)
+	GetBlastsAwaitingABTestWinner(ctx context.Context, beforeTime time.Time) ([]store.EmailBlast, error)
+	GetBlastVariantStats(ctx context.Context, blastID uuid.UUID) ([]store.BlastVariantStats, error)
+	SetEmailBlastABTestWinner(ctx context.Context, blastID, variantID uuid.UUID) (store.EmailBlast, error)
+	GetDueEmailBlastCohorts(ctx context.Context, beforeTime time.Time) ([]store.DueEmailBlastCohort, error)
+	ReleaseEmailBlastCohort(ctx context.Context, cohortID uuid.UUID) error
+	RequeueExpiredBlastRecipients(ctx context.Context, maxAttempts int) ([]store.ExpiredBlastBatch, error)
+	GetStalledEmailBlasts(ctx context.Context, progressBefore time.Time) ([]store.StalledEmailBlast, error)
+	CompleteEmailBlast(ctx context.Context, blastID uuid.UUID) (bool, error)
+	DeleteBlastSendRateBucket(ctx context.Context, blastID uuid.UUID) error
 }
 
-// BlastScheduler periodically checks for scheduled blasts and triggers them
+// BlastScheduler periodically checks for scheduled blasts and triggers them.
+// It also picks the winner of A/B tested blasts once their waiting window ends and releases
+// the timezone cohorts of local time blasts when their local send time arrives.
+// Recipients left behind by crashed workers are sent again, and blasts that made no progress
+// for stallTimeout are marked failed.
 type BlastScheduler struct {
 	store           SchedulerStore
 	eventDispatcher *events.EventDispatcher
 	logger          *observability.Logger
 	checkInterval   time.Duration
+	stallTimeout    time.Duration
 	stopChan        chan struct{}
 }
 
@@ -33,16 +48,21 @@ func NewBlastScheduler(
 	eventDispatcher *events.EventDispatcher,
 	logger *observability.Logger,
 	checkInterval time.Duration,
+	stallTimeout time.Duration,
 ) *BlastScheduler {
 	if checkInterval <= 0 {
 		checkInterval = 30 * time.Second
 	}
+	if stallTimeout <= 0 {
+		stallTimeout = defaultStallTimeout
+	}
 
 	return &BlastScheduler{
 		store:           store,
 		eventDispatcher: eventDispatcher,
 		logger:          logger,
 		checkInterval:   checkInterval,
+		stallTimeout:    stallTimeout,
 		stopChan:        make(chan struct{}),
 	}
 }
@@ -56,6 +76,10 @@ func (s *BlastScheduler) Start(ctx context.Context) {
 
 	// Run immediately on start
 	s.checkScheduledBlasts(ctx)
+	s.checkABTestWinners(ctx)
+	s.checkCohortReleases(ctx)
+	s.checkExpiredLeases(ctx)
+	s.checkStalledBlasts(ctx)
 
 	for {
 		select {
@@ -67,6 +91,10 @@ func (s *BlastScheduler) Start(ctx context.Context) {
 			return
 		case <-ticker.C:
 			s.checkScheduledBlasts(ctx)
+			s.checkABTestWinners(ctx)
+			s.checkCohortReleases(ctx)
+			s.checkExpiredLeases(ctx)
+			s.checkStalledBlasts(ctx)
 		}
 	}
 }
@@ -120,3 +148,113 @@ func (s *BlastScheduler) checkScheduledBlasts(ctx context.Context) {
 		s.logger.Info(blastCtx, "Triggered scheduled blast")
 	}
 }
+
+// checkABTestWinners picks the winning variant of A/B tested blasts whose waiting window has ended
+// and dispatches the first remaining batch with the winner
+func (s *BlastScheduler) checkABTestWinners(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "check_ab_test_winners"},
+	)
+
+	blasts, err := s.store.GetBlastsAwaitingABTestWinner(ctx, time.Now())
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get blasts awaiting A/B test winner", err)
+		return
+	}
+
+	for _, blast := range blasts {
+		blastCtx := observability.WithFields(ctx,
+			observability.Field{Key: "blast_id", Value: blast.ID},
+			observability.Field{Key: "account_id", Value: blast.AccountID},
+		)
+
+		stats, err := s.store.GetBlastVariantStats(blastCtx, blast.ID)
+		if err != nil {
+			s.logger.Error(blastCtx, "Failed to get blast variant stats", err)
+			continue
+		}
+
+		metric := string(store.BlastABTestMetricOpenRate)
+		if blast.ABTestMetric != nil {
+			metric = *blast.ABTestMetric
+		}
+
+		if len(stats) == 0 || blast.ABTestLastBatch == nil {
+			s.logger.Info(blastCtx, "Blast has no variants to pick a winner from")
+			continue
+		}
+
+		winnerID, ok := pickWinningVariant(stats, metric)
+		if !ok {
+			// Hold the test until opens or clicks arrive, and fail it once they are clearly not coming
+			if blast.ABTestEndsAt != nil && time.Since(*blast.ABTestEndsAt) > abTestMaxHold {
+				s.logger.Warn(blastCtx, "No engagement recorded for any A/B test variant, failing blast")
+				errMsg := fmt.Sprintf("no %s data was recorded for any A/B test variant", metric)
+				_, _ = s.store.UpdateEmailBlastStatus(blastCtx, blast.ID, string(store.EmailBlastStatusFailed), &errMsg)
+				continue
+			}
+			s.logger.Info(blastCtx, "Holding A/B test until variant engagement is recorded")
+			continue
+		}
+
+		// Only one scheduler instance can set the winner, so the remainder is dispatched once
+		blast, err = s.store.SetEmailBlastABTestWinner(blastCtx, blast.ID, winnerID)
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				s.logger.Error(blastCtx, "Failed to set A/B test winner", err)
+			}
+			continue
+		}
+
+		err = s.eventDispatcher.DispatchBlastBatchSend(blastCtx, blast.AccountID, blast.ID, *blast.ABTestLastBatch+1)
+		if err != nil {
+			s.logger.Error(blastCtx, "Failed to dispatch batch for A/B test winner", err)
+			errMsg := fmt.Sprintf("failed to dispatch batch for A/B test winner: %v", err)
+			_, _ = s.store.UpdateEmailBlastStatus(blastCtx, blast.ID, string(store.EmailBlastStatusFailed), &errMsg)
+			continue
+		}
+
+		s.logger.Info(blastCtx, fmt.Sprintf("Picked A/B test winner %s by %s", winnerID, metric))
+	}
+}
+
+// checkCohortReleases releases the timezone cohorts of local time blasts whose local send time
+// has arrived and dispatches the first batch of each
+func (s *BlastScheduler) checkCohortReleases(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "check_cohort_releases"},
+	)
+
+	cohorts, err := s.store.GetDueEmailBlastCohorts(ctx, time.Now())
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get due blast cohorts", err)
+		return
+	}
+
+	for _, cohort := range cohorts {
+		cohortCtx := observability.WithFields(ctx,
+			observability.Field{Key: "blast_id", Value: cohort.BlastID},
+			observability.Field{Key: "account_id", Value: cohort.AccountID},
+			observability.Field{Key: "timezone", Value: cohort.Timezone},
+		)
+
+		// Only one scheduler instance can release a cohort, so its first batch is dispatched once
+		err := s.store.ReleaseEmailBlastCohort(cohortCtx, cohort.ID)
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				s.logger.Error(cohortCtx, "Failed to release blast cohort", err)
+			}
+			continue
+		}
+
+		err = s.eventDispatcher.DispatchBlastBatchSend(cohortCtx, cohort.AccountID, cohort.BlastID, cohort.FirstBatch)
+		if err != nil {
+			s.logger.Error(cohortCtx, "Failed to dispatch batch for blast cohort", err)
+			errMsg := fmt.Sprintf("failed to dispatch batch for timezone cohort %s: %v", cohort.Timezone, err)
+			_, _ = s.store.UpdateEmailBlastStatus(cohortCtx, cohort.BlastID, string(store.EmailBlastStatusFailed), &errMsg)
+			continue
+		}
+
+		s.logger.Info(cohortCtx, fmt.Sprintf("Released timezone cohort of %d recipients", cohort.RecipientCount))
+	}
+}
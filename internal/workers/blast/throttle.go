@@ -0,0 +1,112 @@
+package blast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"base-server/internal/clients/mail"
+	"base-server/internal/store"
+)
+
+const (
+	// providerBucketKey is the send rate bucket shared by every blast sent through the email provider
+	providerBucketKey = "provider:resend"
+	// maxRateLimitedRetries is how often a send is retried after the provider rate limited it
+	maxRateLimitedRetries = 5
+	// defaultProviderSendRate and defaultAccountSendRate apply when SendRates leaves a rate unset
+	defaultProviderSendRate = 10
+	defaultAccountSendRate  = 5
+)
+
+// errSendRateLimiter is returned when a send token could not be taken from the shared buckets
+var errSendRateLimiter = errors.New("send rate limiter unavailable")
+
+// SendRates are the shared send rates all blast workers draw from, in emails per second
+type SendRates struct {
+	ProviderPerSecond int
+	AccountPerSecond  int
+}
+
+// sendBucket is a shared token bucket a send draws a token from
+type sendBucket struct {
+	key  string
+	rate int
+}
+
+// sendBuckets returns the buckets a send of the blast draws from, most specific first so shared
+// tokens are not held while waiting on the blast's own throttle
+func (p *BlastEventProcessor) sendBuckets(blast store.EmailBlast) []sendBucket {
+	buckets := make([]sendBucket, 0, 3)
+	if blast.SendThrottlePerSecond != nil && *blast.SendThrottlePerSecond > 0 {
+		buckets = append(buckets, sendBucket{key: store.BlastSendRateBucketKey(blast.ID), rate: *blast.SendThrottlePerSecond})
+	}
+
+	accountRate := p.sendRates.AccountPerSecond
+	if accountRate <= 0 {
+		accountRate = defaultAccountSendRate
+	}
+
+	return append(buckets,
+		sendBucket{key: "account:" + blast.AccountID.String(), rate: accountRate},
+		sendBucket{key: providerBucketKey, rate: p.providerSendRate()},
+	)
+}
+
+// providerSendRate returns the send rate shared by all blasts sent through the provider
+func (p *BlastEventProcessor) providerSendRate() int {
+	if p.sendRates.ProviderPerSecond <= 0 {
+		return defaultProviderSendRate
+	}
+	return p.sendRates.ProviderPerSecond
+}
+
+// waitForSendToken blocks until a token has been taken from every bucket the blast draws from
+func (p *BlastEventProcessor) waitForSendToken(ctx context.Context, blast store.EmailBlast) error {
+	for _, b := range p.sendBuckets(blast) {
+		for {
+			wait, err := p.store.TakeSendRateToken(ctx, b.key, float64(b.rate), b.rate)
+			if err != nil {
+				return fmt.Errorf("%w: %w", errSendRateLimiter, err)
+			}
+			if wait == 0 {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("%w: %w", errSendRateLimiter, ctx.Err())
+			case <-time.After(wait):
+			}
+		}
+	}
+
+	return nil
+}
+
+// sendBlastEmailThrottled sends a blast email within the shared send rates. When the provider
+// rate limits the send, the provider bucket backs off and the send is retried. Returns the provider
+// message ID of the sent email.
+func (p *BlastEventProcessor) sendBlastEmailThrottled(ctx context.Context, blast store.EmailBlast, recipient store.BlastRecipient, subject string, template store.BlastEmailTemplate) (string, error) {
+	for attempt := 0; ; attempt++ {
+		if err := p.waitForSendToken(ctx, blast); err != nil {
+			return "", err
+		}
+
+		messageID, err := p.sendBlastEmail(ctx, recipient, subject, template)
+
+		var rateLimitErr *mail.RateLimitError
+		if !errors.As(err, &rateLimitErr) || attempt >= maxRateLimitedRetries {
+			return messageID, err
+		}
+
+		p.logger.Info(ctx, fmt.Sprintf("Email provider rate limited send to %s, backing off", recipient.Email))
+
+		providerRate := p.providerSendRate()
+		err = p.store.ThrottleSendRateBucket(ctx, providerBucketKey, float64(providerRate), providerRate, rateLimitErr.RetryAfter)
+		if err != nil {
+			p.logger.Error(ctx, "Failed to back off provider send rate", err)
+		}
+	}
+}
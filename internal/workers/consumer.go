@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -32,6 +33,10 @@ type ConsumerConfig struct {
 
 	// DrainTimeout is the maximum time to wait for in-flight events during shutdown.
 	DrainTimeout time.Duration
+
+	// OrderByAccount routes all events of an account to the same worker, so they are processed in
+	// the order of the topic, which is partitioned by account ID.
+	OrderByAccount bool
 }
 
 // DefaultConsumerConfig returns sensible defaults for a consumer.
@@ -61,6 +66,8 @@ type consumer struct {
 
 	// Event channel for worker distribution
 	eventCh chan eventWithMsg
+	// One event channel per worker when events are routed by account
+	workerChs []chan eventWithMsg
 
 	// Lifecycle management
 	cancelFetch context.CancelFunc // cancels the fetch context
@@ -93,6 +100,12 @@ func NewConsumer(
 		eventCh:   make(chan eventWithMsg, config.QueueSize),
 		doneCh:    make(chan struct{}),
 	}
+	if config.OrderByAccount {
+		c.workerChs = make([]chan eventWithMsg, config.NumWorkers)
+		for i := range c.workerChs {
+			c.workerChs[i] = make(chan eventWithMsg, config.QueueSize)
+		}
+	}
 
 	// Create Kafka reader
 	c.reader = kafkago.NewReader(kafkago.ReaderConfig{
@@ -142,8 +155,11 @@ func (c *consumer) Start(ctx context.Context) error {
 	// Fetch loop - runs until Stop() cancels the context
 	c.fetchLoop(ctx)
 
-	// Shutdown: close channel and wait for workers to drain
+	// Shutdown: close channels and wait for workers to drain
 	close(c.eventCh)
+	for _, ch := range c.workerChs {
+		close(ch)
+	}
 
 	// Wait for workers with timeout
 	done := make(chan struct{})
@@ -197,13 +213,32 @@ func (c *consumer) fetchLoop(ctx context.Context) {
 
 		// Send to workers (blocks if queue full)
 		select {
-		case c.eventCh <- eventWithMsg{event: event, msg: msg}:
+		case c.dispatchChannel(event) <- eventWithMsg{event: event, msg: msg}:
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
+// dispatchChannel returns the channel an event is sent to: the shared channel, or the channel of
+// the worker handling the event's account when events are routed by account
+func (c *consumer) dispatchChannel(event EventMessage) chan eventWithMsg {
+	if len(c.workerChs) == 0 {
+		return c.eventCh
+	}
+	h := fnv.New32a()
+	h.Write([]byte(event.AccountID))
+	return c.workerChs[h.Sum32()%uint32(len(c.workerChs))]
+}
+
+// workerChannel returns the channel a worker processes events from
+func (c *consumer) workerChannel(id int) chan eventWithMsg {
+	if len(c.workerChs) == 0 {
+		return c.eventCh
+	}
+	return c.workerChs[id]
+}
+
 // worker processes events from the channel until it's closed.
 func (c *consumer) worker(wg *sync.WaitGroup, id int, ctx context.Context) {
 	defer wg.Done()
@@ -214,7 +249,7 @@ func (c *consumer) worker(wg *sync.WaitGroup, id int, ctx context.Context) {
 
 	c.logger.Info(ctx, fmt.Sprintf("Worker %d started for %s processor", id, c.processor.Name()))
 
-	for e := range c.eventCh {
+	for e := range c.workerChannel(id) {
 		eventCtx := observability.WithFields(ctx,
 			observability.Field{Key: "event_id", Value: e.event.ID},
 			observability.Field{Key: "event_type", Value: e.event.Type},
@@ -400,3 +400,45 @@ func TestDefaultConsumerConfig(t *testing.T) {
 	assert.Equal(t, 100, config.QueueSize)
 	assert.Equal(t, 30*time.Second, config.DrainTimeout)
 }
+
+// TestOrderByAccountProcessesAccountEventsInOrder tests that events routed by account are
+// processed in the order they were dispatched, even with several workers.
+func TestOrderByAccountProcessesAccountEventsInOrder(t *testing.T) {
+	t.Parallel()
+
+	logger := observability.NewLogger()
+	processor := newMockProcessor("test", time.Millisecond)
+
+	numWorkers := 4
+	c := &consumer{
+		processor: processor,
+		logger:    logger,
+		eventCh:   make(chan eventWithMsg, 100),
+		workerChs: make([]chan eventWithMsg, numWorkers),
+	}
+	for i := range c.workerChs {
+		c.workerChs[i] = make(chan eventWithMsg, 100)
+	}
+
+	var wg sync.WaitGroup
+	ctx := context.Background()
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go c.worker(&wg, i, ctx)
+	}
+
+	var expected []string
+	for i := 0; i < 20; i++ {
+		id := string(rune('A' + i))
+		expected = append(expected, id)
+		event := EventMessage{ID: id, AccountID: "account-1"}
+		c.dispatchChannel(event) <- eventWithMsg{event: event}
+	}
+
+	for _, ch := range c.workerChs {
+		close(ch)
+	}
+	wg.Wait()
+
+	require.Equal(t, expected, processor.getProcessedIDs())
+}
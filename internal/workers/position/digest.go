@@ -0,0 +1,248 @@
+package position
+
+//go:generate go run go.uber.org/mock/mockgen@latest -source=digest.go -destination=mocks_test.go -package=position
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"base-server/internal/email"
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/waitlist/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// digestBatchSize is the number of position updates sent per tick
+	digestBatchSize = 100
+	// digestClaimTimeout is how long a claimed position update is held before a failed or
+	// interrupted send is retried
+	digestClaimTimeout = 15 * time.Minute
+)
+
+// DigestStore defines the database operations required by DigestScheduler
+type DigestStore interface {
+	ClaimDuePositionUpdateDigests(ctx context.Context, lastSentBefore, claimedBefore time.Time, minSpots, limit int) ([]store.PositionUpdateDigest, error)
+	MarkPositionUpdateDigestSent(ctx context.Context, userID uuid.UUID, position int) error
+	GetWaitlistUserByID(ctx context.Context, userID uuid.UUID) (store.WaitlistUser, error)
+	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
+	GetEnabledCampaignEmailTemplatesByType(ctx context.Context, campaignID uuid.UUID, templateType string) ([]store.CampaignEmailTemplate, error)
+	CreateEmailLog(ctx context.Context, params store.CreateEmailLogParams) (store.EmailLog, error)
+	UpdateEmailLogStatus(ctx context.Context, logID uuid.UUID, status string) error
+}
+
+// DigestScheduler periodically emails users whose position improved after referrals.
+// Improvements recorded by position recalculation are collected into one position_update email
+// per user per digest interval, sent only once the user moved up at least minSpots.
+type DigestScheduler struct {
+	store          DigestStore
+	emailService   *email.EmailService
+	webAppURI      string
+	logger         *observability.Logger
+	checkInterval  time.Duration
+	digestInterval time.Duration
+	minSpots       int
+	stopChan       chan struct{}
+}
+
+// NewDigestScheduler creates a new position update digest scheduler
+func NewDigestScheduler(
+	store DigestStore,
+	emailService *email.EmailService,
+	webAppURI string,
+	logger *observability.Logger,
+	checkInterval time.Duration,
+	digestInterval time.Duration,
+	minSpots int,
+) *DigestScheduler {
+	if checkInterval <= 0 {
+		checkInterval = time.Minute
+	}
+	if digestInterval <= 0 {
+		digestInterval = 24 * time.Hour
+	}
+	if minSpots < 1 {
+		minSpots = 1
+	}
+
+	return &DigestScheduler{
+		store:          store,
+		emailService:   emailService,
+		webAppURI:      webAppURI,
+		logger:         logger,
+		checkInterval:  checkInterval,
+		digestInterval: digestInterval,
+		minSpots:       minSpots,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *DigestScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting position update digest scheduler with %v interval", s.checkInterval))
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.sendDueDigests(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Position update digest scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Position update digest scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.sendDueDigests(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *DigestScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// sendDueDigests emails every user whose position update digest is due
+func (s *DigestScheduler) sendDueDigests(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "send_position_update_digests"},
+	)
+
+	now := time.Now()
+	digests, err := s.store.ClaimDuePositionUpdateDigests(ctx, now.Add(-s.digestInterval), now.Add(-digestClaimTimeout), s.minSpots, digestBatchSize)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to claim due position update digests", err)
+		return
+	}
+
+	if len(digests) == 0 {
+		return
+	}
+
+	s.logger.Info(ctx, fmt.Sprintf("Found %d position updates ready to send", len(digests)))
+
+	campaigns := make(map[uuid.UUID]store.Campaign)
+	for _, digest := range digests {
+		digestCtx := observability.WithFields(ctx,
+			observability.Field{Key: "campaign_id", Value: digest.CampaignID},
+			observability.Field{Key: "user_id", Value: digest.UserID},
+			observability.Field{Key: "spots_moved", Value: digest.SpotsMoved()},
+		)
+
+		campaign, ok := campaigns[digest.CampaignID]
+		if !ok {
+			campaign, err = s.store.GetCampaignByID(digestCtx, digest.CampaignID)
+			if err != nil {
+				s.logger.Error(digestCtx, "Failed to get campaign for position update", err)
+				continue
+			}
+			campaigns[digest.CampaignID] = campaign
+		}
+
+		// A failed send stays pending and is retried once its claim times out
+		if err := s.sendDigest(digestCtx, campaign, digest); err != nil {
+			s.logger.Error(digestCtx, "Failed to send position update", err)
+			continue
+		}
+
+		if err := s.store.MarkPositionUpdateDigestSent(digestCtx, digest.UserID, digest.Position); err != nil {
+			s.logger.Error(digestCtx, "Failed to mark position update as sent", err)
+		}
+	}
+}
+
+// sendDigest emails a user their new position using the campaign's position_update template,
+// falling back to the default template when the campaign has none enabled. A failed template lookup
+// fails the send rather than emailing the default template in place of the campaign's own.
+func (s *DigestScheduler) sendDigest(ctx context.Context, campaign store.Campaign, digest store.PositionUpdateDigest) error {
+	user, err := s.store.GetWaitlistUserByID(ctx, digest.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get waitlist user: %w", err)
+	}
+
+	firstName := ""
+	if user.FirstName != nil {
+		firstName = *user.FirstName
+	}
+	referralLink := utils.BuildReferralLink(s.webAppURI, campaign.Slug, user.ReferralCode)
+
+	templates, err := s.store.GetEnabledCampaignEmailTemplatesByType(ctx, campaign.ID, store.EmailTemplateTypePositionUpdate)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		return fmt.Errorf("failed to get position update templates: %w", err)
+	}
+	if len(templates) == 0 {
+		return s.emailService.SendWaitlistPositionUpdateEmail(
+			ctx, user.Email, firstName, campaign.Name, referralLink, digest.Position, user.ReferralCount)
+	}
+
+	template := email.SelectTemplateVariant(templates, rand.Float64())
+
+	body, err := email.TemplateBody(template.HTMLBody, template.BlocksJSON)
+	if err != nil {
+		return err
+	}
+
+	opts, err := email.TemplateSendOptions(template.TextBody, template.Headers, template.Attachments)
+	if err != nil {
+		return err
+	}
+	if campaign.EmailSettings != nil && campaign.EmailSettings.ReplyTo != nil {
+		opts.ReplyTo = *campaign.EmailSettings.ReplyTo
+	}
+
+	data := email.TemplateData{
+		FirstName:     firstName,
+		Email:         user.Email,
+		CampaignName:  campaign.Name,
+		Position:      digest.Position,
+		ReferralLink:  referralLink,
+		ReferralCount: user.ReferralCount,
+		SpotsMoved:    digest.SpotsMoved(),
+		SignedUpAt:    user.CreatedAt,
+	}
+
+	messageID, err := s.emailService.SendCustomTemplateEmail(ctx, user.Email, template.Subject, body, data, opts)
+	if err != nil {
+		return err
+	}
+
+	s.recordSend(ctx, campaign.ID, user, template, messageID)
+	return nil
+}
+
+// recordSend stores an email log for a position update sent with a custom template.
+// Failures are logged but do not fail the send since the email has already gone out.
+func (s *DigestScheduler) recordSend(ctx context.Context, campaignID uuid.UUID, user store.WaitlistUser, template store.CampaignEmailTemplate, messageID string) {
+	var providerMessageID *string
+	if messageID != "" {
+		providerMessageID = &messageID
+	}
+
+	emailLog, err := s.store.CreateEmailLog(ctx, store.CreateEmailLogParams{
+		CampaignID:         campaignID,
+		UserID:             &user.ID,
+		CampaignTemplateID: &template.ID,
+		RecipientEmail:     user.Email,
+		Subject:            template.Subject,
+		Type:               template.Type,
+		ProviderMessageID:  providerMessageID,
+		VariantName:        template.VariantName,
+	})
+	if err != nil {
+		s.logger.Error(ctx, "failed to create email log", err)
+		return
+	}
+
+	if err := s.store.UpdateEmailLogStatus(ctx, emailLog.ID, store.EmailLogStatusSent); err != nil {
+		s.logger.Error(ctx, "failed to mark email log as sent", err)
+	}
+}
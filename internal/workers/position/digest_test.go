@@ -0,0 +1,70 @@
+package position
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDigestScheduler_SendDueDigests(t *testing.T) {
+	t.Parallel()
+
+	campaignID := uuid.New()
+	userID := uuid.New()
+	digest := store.PositionUpdateDigest{UserID: userID, CampaignID: campaignID, PreviousPosition: 40, Position: 25}
+
+	setup := func(t *testing.T) (*MockDigestStore, *DigestScheduler) {
+		ctrl := gomock.NewController(t)
+		mockStore := NewMockDigestStore(ctrl)
+		return mockStore, NewDigestScheduler(mockStore, nil, "https://app.example.com", observability.NewLogger(), time.Minute, 24*time.Hour, 5)
+	}
+
+	t.Run("claims updates due for the digest window and not recently claimed", func(t *testing.T) {
+		mockStore, s := setup(t)
+
+		mockStore.EXPECT().
+			ClaimDuePositionUpdateDigests(gomock.Any(), gomock.Any(), gomock.Any(), 5, digestBatchSize).
+			DoAndReturn(func(_ context.Context, lastSentBefore, claimedBefore time.Time, _, _ int) ([]store.PositionUpdateDigest, error) {
+				if got := time.Since(lastSentBefore); got < 24*time.Hour || got > 24*time.Hour+time.Minute {
+					t.Errorf("expected lastSentBefore one digest interval ago, got %v ago", got)
+				}
+				if got := time.Since(claimedBefore); got < digestClaimTimeout || got > digestClaimTimeout+time.Minute {
+					t.Errorf("expected claimedBefore one claim timeout ago, got %v ago", got)
+				}
+				return nil, nil
+			})
+
+		s.sendDueDigests(context.Background())
+	})
+
+	t.Run("does not mark sent when the template lookup fails", func(t *testing.T) {
+		mockStore, s := setup(t)
+		mockStore.EXPECT().ClaimDuePositionUpdateDigests(gomock.Any(), gomock.Any(), gomock.Any(), 5, digestBatchSize).
+			Return([]store.PositionUpdateDigest{digest}, nil)
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{ID: campaignID, Name: "Launch", Slug: "launch"}, nil)
+		mockStore.EXPECT().GetWaitlistUserByID(gomock.Any(), userID).Return(store.WaitlistUser{ID: userID, Email: "user@example.com"}, nil)
+		mockStore.EXPECT().GetEnabledCampaignEmailTemplatesByType(gomock.Any(), campaignID, store.EmailTemplateTypePositionUpdate).
+			Return(nil, errors.New("connection reset"))
+		mockStore.EXPECT().MarkPositionUpdateDigestSent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		// The email service is nil, so falling back to the default template would panic
+		s.sendDueDigests(context.Background())
+	})
+
+	t.Run("skips updates whose campaign cannot be loaded", func(t *testing.T) {
+		mockStore, s := setup(t)
+		mockStore.EXPECT().ClaimDuePositionUpdateDigests(gomock.Any(), gomock.Any(), gomock.Any(), 5, digestBatchSize).
+			Return([]store.PositionUpdateDigest{digest}, nil)
+		mockStore.EXPECT().GetCampaignByID(gomock.Any(), campaignID).Return(store.Campaign{}, store.ErrNotFound)
+		mockStore.EXPECT().MarkPositionUpdateDigestSent(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+		s.sendDueDigests(context.Background())
+	})
+}
@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: digest.go
+//
+// Generated by this command:
+//
+//	mockgen -source=digest.go -destination=mocks_test.go -package=position
+//
+
+// Package position is a generated GoMock package.
+package position
+
+import (
+	store "base-server/internal/store"
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDigestStore is a mock of DigestStore interface.
+type MockDigestStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockDigestStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockDigestStoreMockRecorder is the mock recorder for MockDigestStore.
+type MockDigestStoreMockRecorder struct {
+	mock *MockDigestStore
+}
+
+// NewMockDigestStore creates a new mock instance.
+func NewMockDigestStore(ctrl *gomock.Controller) *MockDigestStore {
+	mock := &MockDigestStore{ctrl: ctrl}
+	mock.recorder = &MockDigestStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDigestStore) EXPECT() *MockDigestStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimDuePositionUpdateDigests mocks base method.
+func (m *MockDigestStore) ClaimDuePositionUpdateDigests(ctx context.Context, lastSentBefore, claimedBefore time.Time, minSpots, limit int) ([]store.PositionUpdateDigest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDuePositionUpdateDigests", ctx, lastSentBefore, claimedBefore, minSpots, limit)
+	ret0, _ := ret[0].([]store.PositionUpdateDigest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDuePositionUpdateDigests indicates an expected call of ClaimDuePositionUpdateDigests.
+func (mr *MockDigestStoreMockRecorder) ClaimDuePositionUpdateDigests(ctx, lastSentBefore, claimedBefore, minSpots, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDuePositionUpdateDigests", reflect.TypeOf((*MockDigestStore)(nil).ClaimDuePositionUpdateDigests), ctx, lastSentBefore, claimedBefore, minSpots, limit)
+}
+
+// CreateEmailLog mocks base method.
+func (m *MockDigestStore) CreateEmailLog(ctx context.Context, params store.CreateEmailLogParams) (store.EmailLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailLog", ctx, params)
+	ret0, _ := ret[0].(store.EmailLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmailLog indicates an expected call of CreateEmailLog.
+func (mr *MockDigestStoreMockRecorder) CreateEmailLog(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailLog", reflect.TypeOf((*MockDigestStore)(nil).CreateEmailLog), ctx, params)
+}
+
+// GetCampaignByID mocks base method.
+func (m *MockDigestStore) GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignByID", ctx, campaignID)
+	ret0, _ := ret[0].(store.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignByID indicates an expected call of GetCampaignByID.
+func (mr *MockDigestStoreMockRecorder) GetCampaignByID(ctx, campaignID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignByID", reflect.TypeOf((*MockDigestStore)(nil).GetCampaignByID), ctx, campaignID)
+}
+
+// GetEnabledCampaignEmailTemplatesByType mocks base method.
+func (m *MockDigestStore) GetEnabledCampaignEmailTemplatesByType(ctx context.Context, campaignID uuid.UUID, templateType string) ([]store.CampaignEmailTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEnabledCampaignEmailTemplatesByType", ctx, campaignID, templateType)
+	ret0, _ := ret[0].([]store.CampaignEmailTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEnabledCampaignEmailTemplatesByType indicates an expected call of GetEnabledCampaignEmailTemplatesByType.
+func (mr *MockDigestStoreMockRecorder) GetEnabledCampaignEmailTemplatesByType(ctx, campaignID, templateType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEnabledCampaignEmailTemplatesByType", reflect.TypeOf((*MockDigestStore)(nil).GetEnabledCampaignEmailTemplatesByType), ctx, campaignID, templateType)
+}
+
+// GetWaitlistUserByID mocks base method.
+func (m *MockDigestStore) GetWaitlistUserByID(ctx context.Context, userID uuid.UUID) (store.WaitlistUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWaitlistUserByID", ctx, userID)
+	ret0, _ := ret[0].(store.WaitlistUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWaitlistUserByID indicates an expected call of GetWaitlistUserByID.
+func (mr *MockDigestStoreMockRecorder) GetWaitlistUserByID(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWaitlistUserByID", reflect.TypeOf((*MockDigestStore)(nil).GetWaitlistUserByID), ctx, userID)
+}
+
+// MarkPositionUpdateDigestSent mocks base method.
+func (m *MockDigestStore) MarkPositionUpdateDigestSent(ctx context.Context, userID uuid.UUID, position int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPositionUpdateDigestSent", ctx, userID, position)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPositionUpdateDigestSent indicates an expected call of MarkPositionUpdateDigestSent.
+func (mr *MockDigestStoreMockRecorder) MarkPositionUpdateDigestSent(ctx, userID, position any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPositionUpdateDigestSent", reflect.TypeOf((*MockDigestStore)(nil).MarkPositionUpdateDigestSent), ctx, userID, position)
+}
+
+// UpdateEmailLogStatus mocks base method.
+func (m *MockDigestStore) UpdateEmailLogStatus(ctx context.Context, logID uuid.UUID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmailLogStatus", ctx, logID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmailLogStatus indicates an expected call of UpdateEmailLogStatus.
+func (mr *MockDigestStoreMockRecorder) UpdateEmailLogStatus(ctx, logID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmailLogStatus", reflect.TypeOf((*MockDigestStore)(nil).UpdateEmailLogStatus), ctx, logID, status)
+}
@@ -0,0 +1,253 @@
+package segment
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// exportsPerTick is the maximum number of exports rendered per tick
+	exportsPerTick = 5
+	// exportStaleTimeout is how long an export may stay processing before it is claimed again
+	exportStaleTimeout = 15 * time.Minute
+	// exportRetention is how long a completed export can be downloaded
+	exportRetention = 7 * 24 * time.Hour
+)
+
+// ExportStore defines the database operations required by ExportWorker
+type ExportStore interface {
+	ClaimSegmentExport(ctx context.Context, staleBefore time.Time) (store.SegmentExport, error)
+	GetSegmentByID(ctx context.Context, segmentID uuid.UUID) (store.Segment, error)
+	GetSegmentUsers(ctx context.Context, segment store.Segment) ([]store.WaitlistUser, error)
+	CompleteSegmentExport(ctx context.Context, exportID uuid.UUID, content []byte, rowCount int, expiresAt time.Time) error
+	FailSegmentExport(ctx context.Context, exportID uuid.UUID, errorMessage string) error
+	DeleteExpiredSegmentExports(ctx context.Context, before time.Time) (int, error)
+}
+
+// ExportWorker renders requested segment exports into CSV or JSON files in the background and
+// deletes them once they expire
+type ExportWorker struct {
+	store         ExportStore
+	logger        *observability.Logger
+	checkInterval time.Duration
+	stopChan      chan struct{}
+}
+
+// NewExportWorker creates a new segment export worker
+func NewExportWorker(store ExportStore, logger *observability.Logger, checkInterval time.Duration) *ExportWorker {
+	if checkInterval <= 0 {
+		checkInterval = 10 * time.Second
+	}
+
+	return &ExportWorker{
+		store:         store,
+		logger:        logger,
+		checkInterval: checkInterval,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start begins the worker loop
+func (w *ExportWorker) Start(ctx context.Context) {
+	w.logger.Info(ctx, fmt.Sprintf("Starting segment export worker with %v interval", w.checkInterval))
+
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	w.processExports(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.logger.Info(ctx, "Segment export worker stopping: context cancelled")
+			return
+		case <-w.stopChan:
+			w.logger.Info(ctx, "Segment export worker stopping: stop signal received")
+			return
+		case <-ticker.C:
+			w.processExports(ctx)
+		}
+	}
+}
+
+// Stop signals the worker to stop
+func (w *ExportWorker) Stop() {
+	close(w.stopChan)
+}
+
+// processExports renders pending exports and deletes expired ones
+func (w *ExportWorker) processExports(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "process_segment_exports"},
+	)
+
+	for i := 0; i < exportsPerTick; i++ {
+		export, err := w.store.ClaimSegmentExport(ctx, time.Now().Add(-exportStaleTimeout))
+		if err != nil {
+			if !errors.Is(err, store.ErrNotFound) {
+				w.logger.Error(ctx, "Failed to claim segment export", err)
+			}
+			break
+		}
+
+		exportCtx := observability.WithFields(ctx,
+			observability.Field{Key: "export_id", Value: export.ID},
+			observability.Field{Key: "segment_id", Value: export.SegmentID},
+			observability.Field{Key: "format", Value: export.Format},
+		)
+		w.processExport(exportCtx, export)
+	}
+
+	deleted, err := w.store.DeleteExpiredSegmentExports(ctx, time.Now())
+	if err != nil {
+		w.logger.Error(ctx, "Failed to delete expired segment exports", err)
+		return
+	}
+	if deleted > 0 {
+		w.logger.Info(ctx, fmt.Sprintf("Deleted %d expired segment exports", deleted))
+	}
+}
+
+// processExport renders one export and stores its file
+func (w *ExportWorker) processExport(ctx context.Context, export store.SegmentExport) {
+	segment, err := w.store.GetSegmentByID(ctx, export.SegmentID)
+	if err != nil {
+		w.fail(ctx, export, "segment not found", err)
+		return
+	}
+
+	users, err := w.store.GetSegmentUsers(ctx, segment)
+	if err != nil {
+		w.fail(ctx, export, "failed to get segment users", err)
+		return
+	}
+
+	content, err := RenderExport(store.SegmentExportFormat(export.Format), users)
+	if err != nil {
+		w.fail(ctx, export, "failed to render export", err)
+		return
+	}
+
+	if err := w.store.CompleteSegmentExport(ctx, export.ID, content, len(users), time.Now().Add(exportRetention)); err != nil {
+		w.logger.Error(ctx, "Failed to complete segment export", err)
+		return
+	}
+
+	w.logger.Info(ctx, fmt.Sprintf("Segment export completed with %d rows", len(users)))
+}
+
+// fail marks an export failed with a message safe to show to the user
+func (w *ExportWorker) fail(ctx context.Context, export store.SegmentExport, message string, err error) {
+	w.logger.Error(ctx, "Segment export failed: "+message, err)
+	if err := w.store.FailSegmentExport(ctx, export.ID, message); err != nil {
+		w.logger.Error(ctx, "Failed to mark segment export failed", err)
+	}
+}
+
+// ExportRow is one user in a segment export file
+type ExportRow struct {
+	ID            string  `json:"id"`
+	Email         string  `json:"email"`
+	FirstName     *string `json:"first_name,omitempty"`
+	LastName      *string `json:"last_name,omitempty"`
+	Status        string  `json:"status"`
+	Position      int     `json:"position"`
+	ReferralCode  string  `json:"referral_code"`
+	ReferralCount int     `json:"referral_count"`
+	Points        int     `json:"points"`
+	EmailVerified bool    `json:"email_verified"`
+	Source        *string `json:"source,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+var exportCSVHeader = []string{
+	"id", "email", "first_name", "last_name", "status", "position", "referral_code",
+	"referral_count", "points", "email_verified", "source", "created_at",
+}
+
+// RenderExport renders segment users as a CSV file with a header row or as a JSON array
+func RenderExport(format store.SegmentExportFormat, users []store.WaitlistUser) ([]byte, error) {
+	rows := make([]ExportRow, len(users))
+	for i, user := range users {
+		rows[i] = ExportRow{
+			ID:            user.ID.String(),
+			Email:         user.Email,
+			FirstName:     user.FirstName,
+			LastName:      user.LastName,
+			Status:        user.Status,
+			Position:      user.Position,
+			ReferralCode:  user.ReferralCode,
+			ReferralCount: user.ReferralCount,
+			Points:        user.Points,
+			EmailVerified: user.EmailVerified,
+			Source:        user.Source,
+			CreatedAt:     user.CreatedAt.UTC().Format(time.RFC3339),
+		}
+	}
+
+	switch format {
+	case store.SegmentExportFormatJSON:
+		return json.Marshal(rows)
+	case store.SegmentExportFormatCSV:
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write(exportCSVHeader); err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			record := []string{
+				row.ID,
+				csvText(row.Email),
+				csvText(stringValue(row.FirstName)),
+				csvText(stringValue(row.LastName)),
+				row.Status,
+				strconv.Itoa(row.Position),
+				row.ReferralCode,
+				strconv.Itoa(row.ReferralCount),
+				strconv.Itoa(row.Points),
+				strconv.FormatBool(row.EmailVerified),
+				csvText(stringValue(row.Source)),
+				row.CreatedAt,
+			}
+			if err := writer.Write(record); err != nil {
+				return nil, err
+			}
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// csvText prefixes user-supplied text starting with a formula character with a single quote, so
+// spreadsheets opening the export show it as text instead of evaluating it
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,104 @@
+package segment
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+func TestRenderExport(t *testing.T) {
+	t.Parallel()
+
+	firstName := "Ada"
+	users := []store.WaitlistUser{
+		{
+			ID:            uuid.New(),
+			Email:         "ada@example.com",
+			FirstName:     &firstName,
+			Status:        "verified",
+			Position:      3,
+			ReferralCount: 7,
+			EmailVerified: true,
+			CreatedAt:     time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		t.Parallel()
+
+		content, err := RenderExport(store.SegmentExportFormatCSV, users)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v", err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("expected a header and one row, got %d records", len(records))
+		}
+		row := records[1]
+		if row[1] != "ada@example.com" || row[2] != "Ada" || row[3] != "" || row[7] != "7" || row[11] != "2026-01-02T03:04:05Z" {
+			t.Errorf("unexpected row: %v", row)
+		}
+	})
+
+	t.Run("csv escapes formulas", func(t *testing.T) {
+		t.Parallel()
+
+		formula := "=HYPERLINK(\"https://example.com\")"
+		lastName := "-1+2"
+		source := "@SUM(A1)"
+		content, err := RenderExport(store.SegmentExportFormatCSV, []store.WaitlistUser{{
+			ID:        uuid.New(),
+			Email:     "+ada@example.com",
+			FirstName: &formula,
+			LastName:  &lastName,
+			Source:    &source,
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v", err)
+		}
+		row := records[1]
+		if row[1] != "'+ada@example.com" || row[2] != "'"+formula || row[3] != "'-1+2" || row[10] != "'@SUM(A1)" {
+			t.Errorf("expected formulas to be escaped, got %v", row)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		content, err := RenderExport(store.SegmentExportFormatJSON, users)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var rows []ExportRow
+		if err := json.Unmarshal(content, &rows); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if len(rows) != 1 || rows[0].Email != "ada@example.com" || rows[0].Position != 3 {
+			t.Errorf("unexpected rows: %+v", rows)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := RenderExport("xml", users); err == nil {
+			t.Error("expected an error for an unsupported format")
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package segment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
+
+	"github.com/google/uuid"
+)
+
+// refreshBatchSize is the number of segments recounted per tick
+const refreshBatchSize = 100
+
+// RefreshStore defines the database operations required by RefreshScheduler
+type RefreshStore interface {
+	GetSegmentsDueForCountRefresh(ctx context.Context, cachedBefore time.Time, limit int) ([]store.Segment, error)
+	CountSegmentUsers(ctx context.Context, segment store.Segment) (int, error)
+	UpdateSegmentCachedCount(ctx context.Context, segmentID uuid.UUID, count int) error
+	RecordSegmentSize(ctx context.Context, segmentID uuid.UUID, count int) error
+	UpdateSegmentThresholdReached(ctx context.Context, segmentID uuid.UUID, reached bool) error
+	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
+}
+
+// RefreshScheduler periodically recounts active segments whose cached count is older than the
+// refresh interval. Every count is recorded in the segment's daily size history, and a
+// segment.threshold_crossed event is dispatched when a segment grows to its size threshold or
+// shrinks below it.
+type RefreshScheduler struct {
+	store           RefreshStore
+	eventDispatcher *events.EventDispatcher
+	logger          *observability.Logger
+	checkInterval   time.Duration
+	refreshInterval time.Duration
+	stopChan        chan struct{}
+}
+
+// NewRefreshScheduler creates a new segment count refresh scheduler
+func NewRefreshScheduler(
+	store RefreshStore,
+	eventDispatcher *events.EventDispatcher,
+	logger *observability.Logger,
+	checkInterval time.Duration,
+	refreshInterval time.Duration,
+) *RefreshScheduler {
+	if checkInterval <= 0 {
+		checkInterval = time.Minute
+	}
+	if refreshInterval <= 0 {
+		refreshInterval = time.Hour
+	}
+
+	return &RefreshScheduler{
+		store:           store,
+		eventDispatcher: eventDispatcher,
+		logger:          logger,
+		checkInterval:   checkInterval,
+		refreshInterval: refreshInterval,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *RefreshScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting segment count refresh scheduler with %v interval", s.checkInterval))
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.refreshDueSegments(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Segment count refresh scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Segment count refresh scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.refreshDueSegments(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *RefreshScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// refreshDueSegments recounts every segment whose cached count is due for a refresh
+func (s *RefreshScheduler) refreshDueSegments(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "refresh_segment_counts"},
+	)
+
+	segments, err := s.store.GetSegmentsDueForCountRefresh(ctx, time.Now().Add(-s.refreshInterval), refreshBatchSize)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get segments due for count refresh", err)
+		return
+	}
+
+	if len(segments) == 0 {
+		return
+	}
+
+	s.logger.Info(ctx, fmt.Sprintf("Refreshing counts of %d segments", len(segments)))
+
+	campaigns := make(map[uuid.UUID]store.Campaign)
+	for _, segment := range segments {
+		segmentCtx := observability.WithFields(ctx,
+			observability.Field{Key: "campaign_id", Value: segment.CampaignID},
+			observability.Field{Key: "segment_id", Value: segment.ID},
+		)
+		s.refreshSegment(segmentCtx, segment, campaigns)
+	}
+}
+
+// refreshSegment recounts one segment, records its size and checks its size threshold.
+// Campaigns are cached across the batch for event dispatching.
+func (s *RefreshScheduler) refreshSegment(ctx context.Context, segment store.Segment, campaigns map[uuid.UUID]store.Campaign) {
+	count, err := s.store.CountSegmentUsers(ctx, segment)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to count segment users", err)
+		return
+	}
+
+	if err := s.store.UpdateSegmentCachedCount(ctx, segment.ID, count); err != nil {
+		s.logger.Error(ctx, "Failed to update segment cached count", err)
+		return
+	}
+
+	if err := s.store.RecordSegmentSize(ctx, segment.ID, count); err != nil {
+		s.logger.Error(ctx, "Failed to record segment size", err)
+	}
+
+	if segment.SizeThreshold == nil {
+		return
+	}
+
+	threshold := *segment.SizeThreshold
+	reached := count >= threshold
+	if reached == segment.SizeThresholdReached {
+		return
+	}
+
+	if err := s.store.UpdateSegmentThresholdReached(ctx, segment.ID, reached); err != nil {
+		// Leave the state unchanged so the crossing is dispatched on the next refresh
+		s.logger.Error(ctx, "Failed to update segment threshold state", err)
+		return
+	}
+
+	campaign, ok := campaigns[segment.CampaignID]
+	if !ok {
+		campaign, err = s.store.GetCampaignByID(ctx, segment.CampaignID)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get campaign for segment threshold event", err)
+			return
+		}
+		campaigns[segment.CampaignID] = campaign
+	}
+
+	direction := "down"
+	if reached {
+		direction = "up"
+	}
+
+	s.logger.Info(ctx, fmt.Sprintf("Segment crossed size threshold %d (%s, %d -> %d users)", threshold, direction, segment.CachedUserCount, count))
+
+	s.eventDispatcher.DispatchSegmentThresholdCrossed(ctx, campaign.AccountID, segment.CampaignID, events.SegmentPayload{
+		ID:   segment.ID.String(),
+		Name: segment.Name,
+		Type: segment.Type,
+	}, threshold, segment.CachedUserCount, count, direction)
+}
@@ -0,0 +1,194 @@
+package segment
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/webhooks/events"
+
+	"github.com/google/uuid"
+)
+
+// syncBatchSize is the number of segments synced per tick
+const syncBatchSize = 20
+
+// SyncStore defines the database operations required by SyncScheduler
+type SyncStore interface {
+	GetSegmentsDueForSync(ctx context.Context, syncedBefore time.Time, limit int) ([]store.Segment, error)
+	GetSegmentUsers(ctx context.Context, segment store.Segment) ([]store.WaitlistUser, error)
+	SyncSegmentMembers(ctx context.Context, segmentID uuid.UUID, users []store.WaitlistUser, publish store.SegmentSyncPublisher) ([]store.SegmentSyncMember, []store.SegmentSyncMember, error)
+	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
+}
+
+// SyncScheduler keeps segments with sync enabled mirrored in downstream tools. Every syncInterval
+// it diffs a segment's current users against the membership it last pushed and publishes a
+// segment.member_added or segment.member_removed event per change, which webhooks and
+// integrations such as Zapier deliver. The first sync after enabling publishes every member.
+// Changes are published before the new membership is committed, so a change whose publish fails
+// is published again by the next sync rather than lost, and may be delivered more than once.
+type SyncScheduler struct {
+	store           SyncStore
+	eventDispatcher *events.EventDispatcher
+	logger          *observability.Logger
+	checkInterval   time.Duration
+	syncInterval    time.Duration
+	stopChan        chan struct{}
+}
+
+// NewSyncScheduler creates a new segment sync scheduler
+func NewSyncScheduler(
+	store SyncStore,
+	eventDispatcher *events.EventDispatcher,
+	logger *observability.Logger,
+	checkInterval time.Duration,
+	syncInterval time.Duration,
+) *SyncScheduler {
+	if checkInterval <= 0 {
+		checkInterval = time.Minute
+	}
+	if syncInterval <= 0 {
+		syncInterval = 5 * time.Minute
+	}
+
+	return &SyncScheduler{
+		store:           store,
+		eventDispatcher: eventDispatcher,
+		logger:          logger,
+		checkInterval:   checkInterval,
+		syncInterval:    syncInterval,
+		stopChan:        make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *SyncScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting segment sync scheduler with %v interval", s.checkInterval))
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.syncDueSegments(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Segment sync scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Segment sync scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.syncDueSegments(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *SyncScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// syncDueSegments syncs every segment whose last sync is older than the sync interval
+func (s *SyncScheduler) syncDueSegments(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "sync_segments"},
+	)
+
+	segments, err := s.store.GetSegmentsDueForSync(ctx, time.Now().Add(-s.syncInterval), syncBatchSize)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get segments due for sync", err)
+		return
+	}
+
+	if len(segments) == 0 {
+		return
+	}
+
+	campaigns := make(map[uuid.UUID]store.Campaign)
+	for _, segment := range segments {
+		segmentCtx := observability.WithFields(ctx,
+			observability.Field{Key: "campaign_id", Value: segment.CampaignID},
+			observability.Field{Key: "segment_id", Value: segment.ID},
+		)
+		s.syncSegment(segmentCtx, segment, campaigns)
+	}
+}
+
+// syncSegment publishes the membership changes of one segment since its previous sync.
+// Campaigns are cached across the batch for event dispatching.
+func (s *SyncScheduler) syncSegment(ctx context.Context, segment store.Segment, campaigns map[uuid.UUID]store.Campaign) {
+	campaign, ok := campaigns[segment.CampaignID]
+	if !ok {
+		var err error
+		campaign, err = s.store.GetCampaignByID(ctx, segment.CampaignID)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get campaign for segment sync", err)
+			return
+		}
+		campaigns[segment.CampaignID] = campaign
+	}
+
+	users, err := s.store.GetSegmentUsers(ctx, segment)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get segment users for sync", err)
+		return
+	}
+
+	segmentData := events.SegmentPayload{
+		ID:   segment.ID.String(),
+		Name: segment.Name,
+		Type: segment.Type,
+	}
+
+	usersByID := make(map[uuid.UUID]store.WaitlistUser, len(users))
+	for _, user := range users {
+		usersByID[user.ID] = user
+	}
+
+	// Changes are published before the membership is committed; a failed publish leaves the
+	// previous membership in place so the next sync publishes the changes again
+	publish := func(added, removed []store.SegmentSyncMember) error {
+		s.logger.Info(ctx, fmt.Sprintf("Publishing segment sync: %d members added, %d removed", len(added), len(removed)))
+
+		for _, member := range added {
+			userData := events.UserPayload{
+				ID:    member.UserID.String(),
+				Email: member.Email,
+			}
+			if user, ok := usersByID[member.UserID]; ok {
+				userData.FirstName = user.FirstName
+				userData.LastName = user.LastName
+				userData.Status = user.Status
+				userData.Position = &user.Position
+				userData.ReferralCount = &user.ReferralCount
+				userData.UTMSource = user.UTMSource
+				userData.UTMMedium = user.UTMMedium
+				userData.UTMCampaign = user.UTMCampaign
+				userData.UTMTerm = user.UTMTerm
+				userData.UTMContent = user.UTMContent
+			}
+			if err := s.eventDispatcher.DispatchSegmentMemberAdded(ctx, campaign.AccountID, segment.CampaignID, segmentData, userData); err != nil {
+				return err
+			}
+		}
+
+		for _, member := range removed {
+			err := s.eventDispatcher.DispatchSegmentMemberRemoved(ctx, campaign.AccountID, segment.CampaignID, segmentData, events.UserPayload{
+				ID:    member.UserID.String(),
+				Email: member.Email,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, _, err := s.store.SyncSegmentMembers(ctx, segment.ID, users, publish); err != nil {
+		s.logger.Error(ctx, "Failed to sync segment members", err)
+	}
+}
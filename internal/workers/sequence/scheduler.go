@@ -0,0 +1,316 @@
+package sequence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"base-server/internal/clients/mail"
+	"base-server/internal/email"
+	"base-server/internal/observability"
+	"base-server/internal/store"
+	"base-server/internal/waitlist/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// claimBatchSize is the number of due enrollments processed per tick
+	claimBatchSize = 100
+	// claimLease is how long a claimed enrollment is hidden from other schedulers
+	claimLease = 10 * time.Minute
+)
+
+// SchedulerStore defines the database operations required by SequenceScheduler
+type SchedulerStore interface {
+	ExitInactiveUserEmailSequenceEnrollments(ctx context.Context) (int, error)
+	ClaimDueEmailSequenceEnrollments(ctx context.Context, limit int, lease time.Duration) ([]store.DueEmailSequenceEnrollment, error)
+	GetEmailSequenceByID(ctx context.Context, sequenceID uuid.UUID) (store.EmailSequence, error)
+	GetWaitlistUserByID(ctx context.Context, userID uuid.UUID) (store.WaitlistUser, error)
+	GetCampaignByID(ctx context.Context, campaignID uuid.UUID) (store.Campaign, error)
+	GetCampaignEmailTemplateByID(ctx context.Context, templateID uuid.UUID) (store.CampaignEmailTemplate, error)
+	UserMatchesCriteria(ctx context.Context, campaignID, userID uuid.UUID, criteria store.SegmentFilterCriteria) (bool, error)
+	CreateEmailLog(ctx context.Context, params store.CreateEmailLogParams) (store.EmailLog, error)
+	UpdateEmailLogStatus(ctx context.Context, logID uuid.UUID, status string) error
+	RecordEmailSequenceStep(ctx context.Context, params store.RecordEmailSequenceStepParams) error
+	CompleteEmailSequenceEnrollment(ctx context.Context, enrollmentID uuid.UUID) error
+	ExitEmailSequenceEnrollment(ctx context.Context, enrollmentID uuid.UUID, reason string) error
+}
+
+// SequenceScheduler periodically sends the due steps of drip email sequences.
+// Users who converted or left the waitlist exit their sequences before any step is sent.
+type SequenceScheduler struct {
+	store         SchedulerStore
+	emailService  *email.EmailService
+	webAppURI     string
+	logger        *observability.Logger
+	checkInterval time.Duration
+	stopChan      chan struct{}
+}
+
+// NewSequenceScheduler creates a new sequence scheduler
+func NewSequenceScheduler(
+	store SchedulerStore,
+	emailService *email.EmailService,
+	webAppURI string,
+	logger *observability.Logger,
+	checkInterval time.Duration,
+) *SequenceScheduler {
+	if checkInterval <= 0 {
+		checkInterval = 30 * time.Second
+	}
+
+	return &SequenceScheduler{
+		store:         store,
+		emailService:  emailService,
+		webAppURI:     webAppURI,
+		logger:        logger,
+		checkInterval: checkInterval,
+		stopChan:      make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *SequenceScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting sequence scheduler with %v interval", s.checkInterval))
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.checkDueSteps(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Sequence scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Sequence scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.checkDueSteps(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *SequenceScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// checkDueSteps exits inactive users and sends the steps that are due
+func (s *SequenceScheduler) checkDueSteps(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "check_sequence_steps"},
+	)
+
+	exited, err := s.store.ExitInactiveUserEmailSequenceEnrollments(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to exit inactive users from email sequences", err)
+	} else if exited > 0 {
+		s.logger.Info(ctx, fmt.Sprintf("Exited %d email sequence enrollments of inactive users", exited))
+	}
+
+	enrollments, err := s.store.ClaimDueEmailSequenceEnrollments(ctx, claimBatchSize, claimLease)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to claim due email sequence enrollments", err)
+		return
+	}
+
+	if len(enrollments) == 0 {
+		return
+	}
+
+	s.logger.Info(ctx, fmt.Sprintf("Found %d email sequence steps ready to send", len(enrollments)))
+
+	for _, enrollment := range enrollments {
+		enrollmentCtx := observability.WithFields(ctx,
+			observability.Field{Key: "enrollment_id", Value: enrollment.ID},
+			observability.Field{Key: "sequence_id", Value: enrollment.SequenceID},
+			observability.Field{Key: "user_id", Value: enrollment.UserID},
+		)
+
+		// Failed enrollments are retried once their claim lease expires
+		if err := s.processEnrollment(enrollmentCtx, enrollment); err != nil {
+			s.logger.Error(enrollmentCtx, "Failed to process email sequence step", err)
+		}
+	}
+}
+
+// processEnrollment sends, or skips, the enrollment's current step and schedules the next one
+func (s *SequenceScheduler) processEnrollment(ctx context.Context, enrollment store.DueEmailSequenceEnrollment) error {
+	sequence, err := s.store.GetEmailSequenceByID(ctx, enrollment.SequenceID)
+	if err != nil {
+		return fmt.Errorf("failed to get email sequence: %w", err)
+	}
+
+	step, ok := findStep(sequence.Steps, enrollment.CurrentStep)
+	if !ok {
+		// Steps were removed after the user reached them
+		return s.store.CompleteEmailSequenceEnrollment(ctx, enrollment.ID)
+	}
+
+	user, err := s.store.GetWaitlistUserByID(ctx, enrollment.UserID)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			return s.store.ExitEmailSequenceEnrollment(ctx, enrollment.ID, "user_removed")
+		}
+		return fmt.Errorf("failed to get waitlist user: %w", err)
+	}
+
+	switch user.Status {
+	case store.WaitlistUserStatusConverted:
+		return s.store.ExitEmailSequenceEnrollment(ctx, enrollment.ID, "converted")
+	case store.WaitlistUserStatusRemoved, store.WaitlistUserStatusBlocked:
+		return s.store.ExitEmailSequenceEnrollment(ctx, enrollment.ID, "user_removed")
+	}
+
+	params := store.RecordEmailSequenceStepParams{
+		EnrollmentID: enrollment.ID,
+		StepID:       step.ID,
+		Status:       string(store.EmailSequenceSendStatusSent),
+	}
+	params.NextStep, params.NextSendAt = nextStep(sequence.Steps, step, time.Now())
+
+	matches, err := s.matchesCondition(ctx, enrollment.CampaignID, user.ID, step)
+	if err != nil {
+		return err
+	}
+
+	if !matches {
+		params.Status = string(store.EmailSequenceSendStatusSkipped)
+		return s.store.RecordEmailSequenceStep(ctx, params)
+	}
+
+	emailLogID, err := s.sendStep(ctx, enrollment, step, user)
+	if err != nil {
+		if errors.Is(err, mail.ErrRateLimited) {
+			return err
+		}
+		errMsg := err.Error()
+		params.Status = string(store.EmailSequenceSendStatusFailed)
+		params.ErrorMessage = &errMsg
+		s.logger.Error(ctx, "Failed to send email sequence step", err)
+	}
+	params.EmailLogID = emailLogID
+
+	return s.store.RecordEmailSequenceStep(ctx, params)
+}
+
+// matchesCondition reports whether the user matches the step's segment criteria.
+// Steps without a condition are always sent.
+func (s *SequenceScheduler) matchesCondition(ctx context.Context, campaignID, userID uuid.UUID, step store.EmailSequenceStep) (bool, error) {
+	if step.Condition == nil || len(*step.Condition) == 0 {
+		return true, nil
+	}
+
+	criteria, err := store.ParseFilterCriteria(*step.Condition)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse step condition: %w", err)
+	}
+
+	matches, err := s.store.UserMatchesCriteria(ctx, campaignID, userID, criteria)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate step condition: %w", err)
+	}
+
+	return matches, nil
+}
+
+// sendStep renders and sends the step's template to the user and logs the send
+func (s *SequenceScheduler) sendStep(ctx context.Context, enrollment store.DueEmailSequenceEnrollment, step store.EmailSequenceStep, user store.WaitlistUser) (*uuid.UUID, error) {
+	campaign, err := s.store.GetCampaignByID(ctx, enrollment.CampaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get campaign: %w", err)
+	}
+
+	template, err := s.store.GetCampaignEmailTemplateByID(ctx, step.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	body, err := email.TemplateBody(template.HTMLBody, template.BlocksJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := email.TemplateSendOptions(template.TextBody, template.Headers, template.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.EmailSettings != nil && campaign.EmailSettings.ReplyTo != nil {
+		opts.ReplyTo = *campaign.EmailSettings.ReplyTo
+	}
+	opts.IdempotencyKey = fmt.Sprintf("sequence-%s-%s", enrollment.ID, step.ID)
+
+	firstName := ""
+	if user.FirstName != nil {
+		firstName = *user.FirstName
+	}
+
+	data := email.TemplateData{
+		FirstName:     firstName,
+		Email:         user.Email,
+		CampaignName:  campaign.Name,
+		Position:      user.Position,
+		ReferralLink:  utils.BuildReferralLink(s.webAppURI, campaign.Slug, user.ReferralCode),
+		ReferralCount: user.ReferralCount,
+		SpotsMoved:    max(0, user.OriginalPosition-user.Position),
+		SignedUpAt:    user.CreatedAt,
+	}
+
+	messageID, err := s.emailService.SendCustomTemplateEmail(ctx, user.Email, template.Subject, body, data, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send email: %w", err)
+	}
+
+	var providerMessageID *string
+	if messageID != "" {
+		providerMessageID = &messageID
+	}
+
+	emailLog, err := s.store.CreateEmailLog(ctx, store.CreateEmailLogParams{
+		CampaignID:         campaign.ID,
+		UserID:             &user.ID,
+		CampaignTemplateID: &template.ID,
+		RecipientEmail:     user.Email,
+		Subject:            template.Subject,
+		Type:               template.Type,
+		ProviderMessageID:  providerMessageID,
+	})
+	if err != nil {
+		// The email went out; record the step without a log rather than sending it again
+		s.logger.Error(ctx, "failed to create email log", err)
+		return nil, nil
+	}
+
+	if err := s.store.UpdateEmailLogStatus(ctx, emailLog.ID, store.EmailLogStatusSent); err != nil {
+		s.logger.Error(ctx, "failed to mark email log as sent", err)
+	}
+
+	return &emailLog.ID, nil
+}
+
+// findStep returns the step of the sequence at the given position
+func findStep(steps []store.EmailSequenceStep, position int) (store.EmailSequenceStep, bool) {
+	for _, step := range steps {
+		if step.Position == position {
+			return step, true
+		}
+	}
+	return store.EmailSequenceStep{}, false
+}
+
+// nextStep returns the position and send time of the step after current.
+// The send time is nil when current is the last step.
+func nextStep(steps []store.EmailSequenceStep, current store.EmailSequenceStep, now time.Time) (int, *time.Time) {
+	next, ok := findStep(steps, current.Position+1)
+	if !ok {
+		return current.Position + 1, nil
+	}
+
+	sendAt := now.Add(time.Duration(next.DelayMinutes) * time.Minute)
+	return next.Position, &sendAt
+}
@@ -0,0 +1,116 @@
+package variant
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"base-server/internal/email"
+	"base-server/internal/observability"
+	"base-server/internal/store"
+
+	"github.com/google/uuid"
+)
+
+// PromotionStore defines the database operations required by PromotionScheduler
+type PromotionStore interface {
+	GetCampaignEmailVariantTests(ctx context.Context) ([]store.CampaignEmailVariantTest, error)
+	GetCampaignEmailTemplateVariantStats(ctx context.Context, campaignID uuid.UUID, templateType string) ([]store.CampaignEmailTemplateVariantStats, error)
+	PromoteCampaignEmailTemplateVariant(ctx context.Context, campaignID uuid.UUID, templateType string, winnerID uuid.UUID) error
+}
+
+// PromotionScheduler periodically checks campaign email template variant tests with automatic
+// promotion, and makes the best performing variant the only enabled template once every variant
+// reached the campaign's send threshold and opens, clicks or verifications were recorded
+type PromotionScheduler struct {
+	store    PromotionStore
+	logger   *observability.Logger
+	interval time.Duration
+	stopChan chan struct{}
+}
+
+// NewPromotionScheduler creates a new variant promotion scheduler
+func NewPromotionScheduler(store PromotionStore, logger *observability.Logger, interval time.Duration) *PromotionScheduler {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+
+	return &PromotionScheduler{
+		store:    store,
+		logger:   logger,
+		interval: interval,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins the scheduler loop
+func (s *PromotionScheduler) Start(ctx context.Context) {
+	s.logger.Info(ctx, fmt.Sprintf("Starting email variant promotion scheduler with %v interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	// Run immediately on start
+	s.promoteWinners(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info(ctx, "Email variant promotion scheduler stopping: context cancelled")
+			return
+		case <-s.stopChan:
+			s.logger.Info(ctx, "Email variant promotion scheduler stopping: stop signal received")
+			return
+		case <-ticker.C:
+			s.promoteWinners(ctx)
+		}
+	}
+}
+
+// Stop signals the scheduler to stop
+func (s *PromotionScheduler) Stop() {
+	close(s.stopChan)
+}
+
+// promoteWinners promotes the winner of every running variant test that has one
+func (s *PromotionScheduler) promoteWinners(ctx context.Context) {
+	ctx = observability.WithFields(ctx,
+		observability.Field{Key: "operation", Value: "promote_email_variants"},
+	)
+
+	tests, err := s.store.GetCampaignEmailVariantTests(ctx)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get campaign email variant tests", err)
+		return
+	}
+
+	for _, test := range tests {
+		testCtx := observability.WithFields(ctx,
+			observability.Field{Key: "campaign_id", Value: test.CampaignID},
+			observability.Field{Key: "template_type", Value: test.TemplateType},
+		)
+		s.promoteWinner(testCtx, test)
+	}
+}
+
+// promoteWinner promotes the best variant of a test once it has a winner
+func (s *PromotionScheduler) promoteWinner(ctx context.Context, test store.CampaignEmailVariantTest) {
+	stats, err := s.store.GetCampaignEmailTemplateVariantStats(ctx, test.CampaignID, test.TemplateType)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get variant stats for auto-promotion", err)
+		return
+	}
+
+	metric := email.PrimaryVariantMetric(test.TemplateType)
+	winner, ok := email.PickVariantWinner(email.EvaluateVariants(stats, metric), metric, test.AutoPromoteAfter)
+	if !ok {
+		return
+	}
+
+	if err := s.store.PromoteCampaignEmailTemplateVariant(ctx, test.CampaignID, test.TemplateType, winner.TemplateID); err != nil {
+		s.logger.Error(ctx, "Failed to promote winning variant", err)
+		return
+	}
+
+	s.logger.Info(ctx, fmt.Sprintf("Promoted %s template %s as winning variant", test.TemplateType, winner.TemplateID))
+}
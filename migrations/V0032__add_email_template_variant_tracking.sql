@@ -0,0 +1,14 @@
+-- Track which A/B variant of a campaign email template was sent
+-- and allow campaigns to auto-promote the winning variant
+
+-- Variant name is denormalized onto the log so analytics survive template renames/deletes
+ALTER TABLE email_logs ADD COLUMN variant_name VARCHAR(100);
+
+CREATE INDEX idx_email_logs_campaign_template ON email_logs(campaign_template_id) WHERE campaign_template_id IS NOT NULL;
+
+-- Number of sends each variant must reach before the winner is promoted automatically.
+-- NULL disables auto-promotion.
+ALTER TABLE campaign_email_settings ADD COLUMN variant_auto_promote_after INTEGER;
+
+COMMENT ON COLUMN email_logs.variant_name IS 'A/B variant name of the campaign template used for this send';
+COMMENT ON COLUMN campaign_email_settings.variant_auto_promote_after IS 'Sends per variant required before the best variant is promoted (NULL = disabled)';
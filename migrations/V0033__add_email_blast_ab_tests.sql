@@ -0,0 +1,42 @@
+-- A/B testing for email blasts
+-- A blast can define 2-4 subject/template variants. Each variant is sent to part of a
+-- test slice of the recipients; after a waiting window the best variant is sent to the rest.
+
+-- Email Blast Variants Table
+CREATE TABLE email_blast_variants (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    blast_id UUID NOT NULL REFERENCES email_blasts(id) ON DELETE CASCADE,
+    blast_template_id UUID NOT NULL REFERENCES blast_email_templates(id) ON DELETE RESTRICT,
+
+    name VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(blast_id, name)
+);
+
+CREATE INDEX idx_email_blast_variants_blast ON email_blast_variants(blast_id);
+
+-- A/B test configuration and progress on the blast
+ALTER TABLE email_blasts ADD COLUMN ab_test_percentage INTEGER CHECK (ab_test_percentage BETWEEN 1 AND 50);
+ALTER TABLE email_blasts ADD COLUMN ab_test_wait_minutes INTEGER CHECK (ab_test_wait_minutes > 0);
+ALTER TABLE email_blasts ADD COLUMN ab_test_metric VARCHAR(20) CHECK (ab_test_metric IN ('open_rate', 'click_rate'));
+ALTER TABLE email_blasts ADD COLUMN ab_test_last_batch INTEGER;
+ALTER TABLE email_blasts ADD COLUMN ab_test_ends_at TIMESTAMPTZ;
+ALTER TABLE email_blasts ADD COLUMN ab_test_winner_variant_id UUID REFERENCES email_blast_variants(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_email_blasts_ab_test_pending ON email_blasts(ab_test_ends_at)
+    WHERE ab_test_ends_at IS NOT NULL AND ab_test_winner_variant_id IS NULL AND deleted_at IS NULL;
+
+-- Variant each test recipient received (NULL for non-test recipients)
+ALTER TABLE blast_recipients ADD COLUMN variant_id UUID REFERENCES email_blast_variants(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_blast_recipients_variant ON blast_recipients(variant_id) WHERE variant_id IS NOT NULL;
+
+COMMENT ON COLUMN email_blasts.ab_test_percentage IS 'Percentage of recipients in the A/B test slice';
+COMMENT ON COLUMN email_blasts.ab_test_wait_minutes IS 'Minutes to wait after the test slice is sent before picking a winner';
+COMMENT ON COLUMN email_blasts.ab_test_metric IS 'Metric used to pick the winning variant (open_rate or click_rate)';
+COMMENT ON COLUMN email_blasts.ab_test_last_batch IS 'Last batch number belonging to the test slice';
+COMMENT ON COLUMN email_blasts.ab_test_ends_at IS 'When the waiting window ends and the winner is sent to the remaining recipients';
+COMMENT ON COLUMN email_blasts.ab_test_winner_variant_id IS 'Variant sent to the remaining recipients';
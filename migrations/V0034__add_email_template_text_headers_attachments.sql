@@ -0,0 +1,19 @@
+-- Multipart plain-text bodies, custom headers and attachments for campaign and blast email templates
+-- text_body is a template for the text/plain part; when NULL the text part is generated from the HTML.
+-- headers is a JSON object of extra header names to values.
+-- attachments is a JSON array of {"filename": "...", "content": "<base64>"} objects.
+
+ALTER TABLE campaign_email_templates ADD COLUMN text_body TEXT;
+ALTER TABLE campaign_email_templates ADD COLUMN headers JSONB;
+ALTER TABLE campaign_email_templates ADD COLUMN attachments JSONB;
+
+ALTER TABLE blast_email_templates ADD COLUMN text_body TEXT;
+ALTER TABLE blast_email_templates ADD COLUMN headers JSONB;
+ALTER TABLE blast_email_templates ADD COLUMN attachments JSONB;
+
+COMMENT ON COLUMN campaign_email_templates.text_body IS 'Plain-text body template; generated from html_body when NULL';
+COMMENT ON COLUMN campaign_email_templates.headers IS 'Custom email headers as a JSON object';
+COMMENT ON COLUMN campaign_email_templates.attachments IS 'Small attachments as a JSON array of filename and base64 content';
+COMMENT ON COLUMN blast_email_templates.text_body IS 'Plain-text body template; generated from html_body when NULL';
+COMMENT ON COLUMN blast_email_templates.headers IS 'Custom email headers as a JSON object';
+COMMENT ON COLUMN blast_email_templates.attachments IS 'Small attachments as a JSON array of filename and base64 content';
@@ -0,0 +1,38 @@
+-- Recipient-timezone-aware delivery for email blasts
+-- A blast can be sent at a local time of day (e.g. 09:00) in each recipient's timezone.
+-- Recipients are split into one cohort per timezone and each cohort is released when
+-- its local time arrives. Recipients without a timezone use the blast's fallback timezone.
+
+ALTER TABLE email_blasts ADD COLUMN send_at_local_time VARCHAR(5) CHECK (send_at_local_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$');
+ALTER TABLE email_blasts ADD COLUMN fallback_timezone VARCHAR(64);
+
+-- Resolved timezone of each recipient (NULL for blasts without local time delivery)
+ALTER TABLE blast_recipients ADD COLUMN timezone VARCHAR(64);
+
+-- Email Blast Cohorts Table
+CREATE TABLE email_blast_cohorts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    blast_id UUID NOT NULL REFERENCES email_blasts(id) ON DELETE CASCADE,
+
+    timezone VARCHAR(64) NOT NULL,
+    release_at TIMESTAMPTZ NOT NULL,
+
+    first_batch INTEGER NOT NULL,
+    last_batch INTEGER NOT NULL,
+    recipient_count INTEGER NOT NULL DEFAULT 0,
+
+    released_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(blast_id, timezone),
+    CHECK (last_batch >= first_batch)
+);
+
+CREATE INDEX idx_email_blast_cohorts_blast ON email_blast_cohorts(blast_id);
+CREATE INDEX idx_email_blast_cohorts_pending ON email_blast_cohorts(release_at) WHERE released_at IS NULL;
+
+COMMENT ON COLUMN email_blasts.send_at_local_time IS 'Local time of day (HH:MM) to send at in each recipient''s timezone';
+COMMENT ON COLUMN email_blasts.fallback_timezone IS 'Timezone used for recipients without a known timezone';
+COMMENT ON COLUMN email_blast_cohorts.release_at IS 'When the local send time arrives in the cohort''s timezone';
+COMMENT ON COLUMN email_blast_cohorts.first_batch IS 'First batch number of the cohort; batches of a cohort are contiguous';
+COMMENT ON COLUMN email_blast_cohorts.released_at IS 'When the scheduler released the cohort for sending';
@@ -0,0 +1,20 @@
+-- Shared token buckets for email sending
+-- Blast workers draw a token from the bucket of the blast, its account and the email provider
+-- before every send, so the rate holds across workers and concurrent blasts.
+
+CREATE TABLE send_rate_buckets (
+    bucket_key VARCHAR(100) PRIMARY KEY,
+
+    tokens DOUBLE PRECISION NOT NULL,
+
+    -- Share of the configured rate currently allowed; halved on every 429 and recovered over time
+    rate_factor DOUBLE PRECISION NOT NULL DEFAULT 1 CHECK (rate_factor > 0 AND rate_factor <= 1),
+    consecutive_throttles INTEGER NOT NULL DEFAULT 0,
+    backoff_until TIMESTAMPTZ,
+
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN send_rate_buckets.bucket_key IS 'Bucket identifier, e.g. provider:resend, account:<id> or blast:<id>';
+COMMENT ON COLUMN send_rate_buckets.tokens IS 'Tokens available as of updated_at';
+COMMENT ON COLUMN send_rate_buckets.backoff_until IS 'No tokens are handed out before this time after the provider rate limited a send';
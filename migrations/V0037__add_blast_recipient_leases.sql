@@ -0,0 +1,14 @@
+-- Lease-based claiming of blast recipients
+-- A worker claims the recipients of a batch for a limited time and extends the lease while it
+-- sends. Recipients whose lease expires are returned to pending by the recovery sweeper.
+
+ALTER TABLE blast_recipients ADD COLUMN lease_owner VARCHAR(100);
+ALTER TABLE blast_recipients ADD COLUMN lease_expires_at TIMESTAMPTZ;
+ALTER TABLE blast_recipients ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_blast_recipients_lease ON blast_recipients(lease_expires_at)
+    WHERE status IN ('queued', 'sending');
+
+COMMENT ON COLUMN blast_recipients.lease_owner IS 'Worker currently sending to the recipient';
+COMMENT ON COLUMN blast_recipients.lease_expires_at IS 'When the claim lapses and the recipient may be claimed again';
+COMMENT ON COLUMN blast_recipients.attempts IS 'Number of times the recipient has been claimed for sending';
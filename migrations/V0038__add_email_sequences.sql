@@ -0,0 +1,79 @@
+-- Drip email sequences
+-- A sequence sends a series of campaign email templates to each waitlist user after signup.
+-- Every step waits for its delay after the previous step (or after enrollment for the first
+-- step) and is only sent when the user matches the step's condition. Users leave the sequence
+-- when they convert.
+
+CREATE TABLE email_sequences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'active', 'paused')),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_email_sequences_campaign ON email_sequences(campaign_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE email_sequence_steps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sequence_id UUID NOT NULL REFERENCES email_sequences(id) ON DELETE CASCADE,
+    template_id UUID NOT NULL REFERENCES campaign_email_templates(id) ON DELETE RESTRICT,
+
+    position INTEGER NOT NULL CHECK (position > 0),
+    delay_minutes INTEGER NOT NULL DEFAULT 0 CHECK (delay_minutes >= 0),
+
+    -- Segment filter criteria the user must match for the step to be sent; NULL always sends
+    condition JSONB,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(sequence_id, position)
+);
+
+CREATE TABLE email_sequence_enrollments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sequence_id UUID NOT NULL REFERENCES email_sequences(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES waitlist_users(id) ON DELETE CASCADE,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'completed', 'exited')),
+    current_step INTEGER NOT NULL DEFAULT 1,
+    next_send_at TIMESTAMPTZ,
+    exit_reason VARCHAR(50),
+
+    enrolled_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMPTZ,
+    exited_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(sequence_id, user_id)
+);
+
+CREATE INDEX idx_email_sequence_enrollments_due ON email_sequence_enrollments(next_send_at)
+    WHERE status = 'active';
+CREATE INDEX idx_email_sequence_enrollments_user ON email_sequence_enrollments(user_id);
+
+CREATE TABLE email_sequence_sends (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    enrollment_id UUID NOT NULL REFERENCES email_sequence_enrollments(id) ON DELETE CASCADE,
+    step_id UUID NOT NULL REFERENCES email_sequence_steps(id) ON DELETE CASCADE,
+    email_log_id UUID REFERENCES email_logs(id) ON DELETE SET NULL,
+
+    status VARCHAR(20) NOT NULL CHECK (status IN ('sent', 'skipped', 'failed')),
+    error_message TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(enrollment_id, step_id)
+);
+
+CREATE INDEX idx_email_sequence_sends_step ON email_sequence_sends(step_id);
+
+COMMENT ON COLUMN email_sequence_steps.delay_minutes IS 'Wait after the previous step, or after enrollment for the first step';
+COMMENT ON COLUMN email_sequence_enrollments.current_step IS 'Position of the next step to send';
+COMMENT ON COLUMN email_sequence_enrollments.exit_reason IS 'Why the user left the sequence early, e.g. converted';
+COMMENT ON COLUMN email_sequence_sends.status IS 'sent, skipped when the user did not match the step condition, or failed';
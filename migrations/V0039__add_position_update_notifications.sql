@@ -0,0 +1,22 @@
+-- Position update notifications
+-- Position recalculation records users who moved up. Improvements are collected into a digest
+-- so a user gets at most one position update email per digest window, covering every spot
+-- gained since the last email.
+
+CREATE TABLE waitlist_position_notifications (
+    user_id UUID PRIMARY KEY REFERENCES waitlist_users(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+
+    baseline_position INTEGER NOT NULL,
+    pending_since TIMESTAMPTZ,
+    last_sent_at TIMESTAMPTZ,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_waitlist_position_notifications_pending ON waitlist_position_notifications(pending_since)
+    WHERE pending_since IS NOT NULL;
+
+COMMENT ON COLUMN waitlist_position_notifications.baseline_position IS 'Position when the user was last notified, or before their first improvement';
+COMMENT ON COLUMN waitlist_position_notifications.pending_since IS 'First improvement not yet covered by an email; NULL when there is nothing to report';
@@ -0,0 +1,33 @@
+-- Static segments
+-- A static segment is a fixed list of users instead of a filter query. Members are added and
+-- removed by user ID, email CSV upload, or by snapshotting a dynamic segment, and every change
+-- is kept in the membership history.
+
+ALTER TABLE segments ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'dynamic'
+    CHECK (type IN ('dynamic', 'static'));
+
+CREATE TABLE segment_members (
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES waitlist_users(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (segment_id, user_id)
+);
+
+CREATE INDEX idx_segment_members_user ON segment_members(user_id);
+
+CREATE TABLE segment_membership_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES waitlist_users(id) ON DELETE CASCADE,
+
+    action VARCHAR(20) NOT NULL CHECK (action IN ('added', 'removed')),
+    source VARCHAR(20) NOT NULL CHECK (source IN ('manual', 'csv', 'snapshot')),
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_segment_membership_events_segment ON segment_membership_events(segment_id, created_at DESC);
+
+COMMENT ON COLUMN segments.type IS 'dynamic segments match filter_criteria, static segments list their members in segment_members';
+COMMENT ON COLUMN segment_membership_events.source IS 'How the change was made: manual by user ID, csv upload, or snapshot of a dynamic segment';
@@ -0,0 +1,36 @@
+-- Account-level audiences
+-- An audience combines segments from any of the account's campaigns into one recipient list,
+-- deduplicated by email. Segments are combined by union (in any segment) or intersect (in every
+-- segment), then suppression rules remove users before an email blast is sent.
+
+CREATE TABLE audiences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+
+    combine_mode VARCHAR(20) NOT NULL DEFAULT 'union' CHECK (combine_mode IN ('union', 'intersect')),
+    segment_ids UUID[] NOT NULL,
+
+    -- Suppression rules
+    exclude_segment_ids UUID[] NOT NULL DEFAULT '{}',
+    suppress_bounced BOOLEAN NOT NULL DEFAULT TRUE,
+    require_marketing_consent BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_audiences_account ON audiences(account_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_audiences_segment_ids ON audiences USING GIN(segment_ids);
+
+ALTER TABLE email_blasts ADD COLUMN audience_id UUID REFERENCES audiences(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_email_blasts_audience ON email_blasts(audience_id) WHERE audience_id IS NOT NULL;
+
+COMMENT ON COLUMN audiences.combine_mode IS 'union includes users in any segment, intersect only emails present in every segment';
+COMMENT ON COLUMN audiences.exclude_segment_ids IS 'Users whose email is in any of these segments are suppressed';
+COMMENT ON COLUMN audiences.suppress_bounced IS 'Suppress emails that bounced in a previous email of the account';
+COMMENT ON COLUMN email_blasts.audience_id IS 'When set the blast is sent to the audience instead of segment_ids';
@@ -0,0 +1,22 @@
+-- Segment size history
+-- Segment counts are refreshed on a schedule and the latest count of each day is kept as a
+-- time series for growth charts. A segment can be given a size threshold; crossing it in either
+-- direction fires a segment.threshold_crossed webhook event.
+
+ALTER TABLE segments ADD COLUMN size_threshold INT CHECK (size_threshold > 0);
+ALTER TABLE segments ADD COLUMN size_threshold_reached BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE segment_size_history (
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    user_count INT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (segment_id, date)
+);
+
+CREATE INDEX idx_segments_cached_at ON segments(cached_at NULLS FIRST) WHERE status = 'active' AND deleted_at IS NULL;
+
+COMMENT ON COLUMN segments.size_threshold IS 'User count that fires segment.threshold_crossed when the segment grows to it or shrinks below it';
+COMMENT ON COLUMN segments.size_threshold_reached IS 'Whether the last scheduled refresh found the segment at or above its size threshold';
+COMMENT ON COLUMN segment_size_history.user_count IS 'Latest segment count recorded on that day (UTC)';
@@ -0,0 +1,47 @@
+-- Segment exports and sync
+-- Exports render a segment's members as CSV or JSON in the background and keep the file for
+-- download until it expires. Segments with sync enabled are diffed against the membership last
+-- pushed downstream, and every add and remove is published as a segment.member_added or
+-- segment.member_removed event for webhooks and integrations.
+
+CREATE TABLE segment_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'json')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'completed', 'failed')),
+
+    row_count INT,
+    content BYTEA,
+    error_message TEXT,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_segment_exports_segment ON segment_exports(segment_id, created_at DESC);
+CREATE INDEX idx_segment_exports_pending ON segment_exports(created_at) WHERE status IN ('pending', 'processing');
+CREATE INDEX idx_segment_exports_expires ON segment_exports(expires_at) WHERE expires_at IS NOT NULL;
+
+ALTER TABLE segments ADD COLUMN sync_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE segments ADD COLUMN synced_at TIMESTAMPTZ;
+
+CREATE TABLE segment_sync_members (
+    segment_id UUID NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    synced_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (segment_id, user_id)
+);
+
+CREATE INDEX idx_segments_sync ON segments(synced_at NULLS FIRST) WHERE sync_enabled AND status = 'active' AND deleted_at IS NULL;
+
+COMMENT ON COLUMN segment_exports.content IS 'Rendered export file, kept until expires_at';
+COMMENT ON COLUMN segments.sync_enabled IS 'Publish segment.member_added and segment.member_removed events as membership changes';
+COMMENT ON COLUMN segment_sync_members.user_id IS 'No foreign key so removals of deleted users can still be published';
@@ -0,0 +1,49 @@
+-- Persistent event log
+-- Every public domain event is appended to the events table with the ID it was published to
+-- Kafka with and a monotonically increasing sequence number. Events are never updated, and are
+-- listed through the events API and replayed to webhooks from here.
+
+CREATE TABLE events (
+    id UUID PRIMARY KEY,
+    sequence BIGINT GENERATED ALWAYS AS IDENTITY UNIQUE,
+
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    campaign_id UUID,
+
+    type VARCHAR(100) NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+
+    occurred_at TIMESTAMPTZ NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_events_account_sequence ON events(account_id, sequence DESC);
+CREATE INDEX idx_events_account_type ON events(account_id, type, sequence DESC);
+CREATE INDEX idx_events_campaign ON events(campaign_id, sequence DESC) WHERE campaign_id IS NOT NULL;
+CREATE INDEX idx_events_account_occurred ON events(account_id, occurred_at);
+
+COMMENT ON COLUMN events.id IS 'Event ID as published to Kafka and sent as the webhook payload ID';
+COMMENT ON COLUMN events.sequence IS 'Monotonically increasing position in the log, used as the pagination cursor';
+COMMENT ON COLUMN events.campaign_id IS 'Campaign the event belongs to, NULL for account-level events';
+COMMENT ON COLUMN events.occurred_at IS 'When the event was published';
+
+-- Events are append-only
+CREATE OR REPLACE FUNCTION prevent_event_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'events are append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER events_append_only
+    BEFORE UPDATE ON events
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_event_update();
+
+-- Deliveries reference the event they carry so retries and replays keep the event ID
+ALTER TABLE webhook_deliveries ADD COLUMN event_id UUID;
+
+CREATE INDEX idx_webhook_deliveries_event ON webhook_deliveries(event_id) WHERE event_id IS NOT NULL;
+CREATE INDEX idx_webhook_deliveries_pending ON webhook_deliveries(next_retry_at) WHERE status = 'pending';
+
+COMMENT ON COLUMN webhook_deliveries.event_id IS 'Event delivered, NULL for test deliveries';
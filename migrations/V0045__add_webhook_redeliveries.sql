@@ -0,0 +1,13 @@
+-- Webhook redeliveries
+-- A redelivery is a new delivery of an earlier delivery's payload. Every delivery in an attempt
+-- chain references the chain's first delivery, and records what triggered it.
+
+ALTER TABLE webhook_deliveries ADD COLUMN original_delivery_id UUID REFERENCES webhook_deliveries(id) ON DELETE CASCADE;
+ALTER TABLE webhook_deliveries ADD COLUMN triggered_by VARCHAR(20) NOT NULL DEFAULT 'event'
+    CHECK (triggered_by IN ('event', 'test', 'replay', 'redelivery', 'bulk_retry'));
+
+CREATE INDEX idx_webhook_deliveries_original ON webhook_deliveries(original_delivery_id, created_at)
+    WHERE original_delivery_id IS NOT NULL;
+
+COMMENT ON COLUMN webhook_deliveries.original_delivery_id IS 'First delivery of the attempt chain, NULL for the first delivery itself';
+COMMENT ON COLUMN webhook_deliveries.triggered_by IS 'What created the delivery: event, test, replay, redelivery or bulk_retry';
@@ -0,0 +1,21 @@
+-- Webhook circuit breaker and auto-disable
+-- Consecutive failed attempts are counted per endpoint. Past a threshold the endpoint's circuit
+-- opens: deliveries are deferred without a request, and one probe attempt is let through per
+-- probe interval until one succeeds. Endpoints failing for several days are disabled into the
+-- failed status until they are re-enabled through the API.
+
+ALTER TABLE webhooks ADD COLUMN consecutive_failures INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE webhooks ADD COLUMN failing_since TIMESTAMPTZ;
+ALTER TABLE webhooks ADD COLUMN circuit_opened_at TIMESTAMPTZ;
+ALTER TABLE webhooks ADD COLUMN circuit_next_probe_at TIMESTAMPTZ;
+ALTER TABLE webhooks ADD COLUMN disabled_at TIMESTAMPTZ;
+ALTER TABLE webhooks ADD COLUMN disabled_reason TEXT;
+
+CREATE INDEX idx_webhooks_failing_since ON webhooks(failing_since)
+    WHERE status = 'active' AND failing_since IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN webhooks.consecutive_failures IS 'Failed attempts since the last successful delivery';
+COMMENT ON COLUMN webhooks.failing_since IS 'First failed attempt since the last successful delivery';
+COMMENT ON COLUMN webhooks.circuit_opened_at IS 'When the circuit opened, NULL while closed';
+COMMENT ON COLUMN webhooks.circuit_next_probe_at IS 'Earliest time of the next probe attempt while the circuit is open';
+COMMENT ON COLUMN webhooks.disabled_at IS 'When the webhook was automatically disabled';
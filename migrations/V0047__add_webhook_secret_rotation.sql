@@ -0,0 +1,11 @@
+-- Webhook signing secret rotation
+-- When a secret is rotated the previous secret stays valid until previous_secret_expires_at, and
+-- deliveries are signed with both secrets during that overlap.
+
+ALTER TABLE webhooks ADD COLUMN previous_secret VARCHAR(255);
+ALTER TABLE webhooks ADD COLUMN previous_secret_expires_at TIMESTAMPTZ;
+ALTER TABLE webhooks ADD COLUMN secret_rotated_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN webhooks.previous_secret IS 'Secret replaced by the last rotation, still used to sign deliveries until it expires';
+COMMENT ON COLUMN webhooks.previous_secret_expires_at IS 'End of the rotation overlap, after which only the current secret is used';
+COMMENT ON COLUMN webhooks.secret_rotated_at IS 'When the secret was last rotated, NULL if never';
@@ -0,0 +1,9 @@
+-- Per-webhook signature format
+-- 'v1' signs deliveries with the X-Webhook-Signature header (t=<timestamp>,v1=<hex HMAC>).
+-- 'standard_webhooks' follows the Standard Webhooks specification: webhook-id, webhook-timestamp
+-- and webhook-signature (v1,<base64 HMAC>) headers.
+
+ALTER TABLE webhooks ADD COLUMN signature_format VARCHAR(30) NOT NULL DEFAULT 'v1'
+    CHECK (signature_format IN ('v1', 'standard_webhooks'));
+
+COMMENT ON COLUMN webhooks.signature_format IS 'How deliveries are signed: v1 (X-Webhook-Signature) or standard_webhooks';
@@ -0,0 +1,7 @@
+-- Webhook payload filters
+-- Webhooks may subscribe to event patterns (user.*, *) and narrow their deliveries with filters on
+-- the event data. Events that do not match every filter are skipped before a delivery is created.
+
+ALTER TABLE webhooks ADD COLUMN filters JSONB;
+
+COMMENT ON COLUMN webhooks.filters IS 'Conditions on the event data every delivered event must meet: [{"field","operator","value"}], NULL for none';
@@ -0,0 +1,11 @@
+-- Webhook ordered delivery
+-- Deliveries are queued per webhook and sent by a bounded pool of workers. Webhooks that need
+-- events in order are sent one delivery at a time, in the order their events were received.
+
+ALTER TABLE webhooks ADD COLUMN ordered_delivery BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN webhooks.ordered_delivery IS 'Send queued deliveries one at a time in event order instead of concurrently; retries are not ordered';
+
+-- Deliveries are recorded when queued and stay pending without a retry time until attempted.
+-- Queued deliveries lost on a restart are picked up by the retry worker after a grace period.
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_queued ON webhook_deliveries(created_at) WHERE status = 'pending' AND next_retry_at IS NULL;
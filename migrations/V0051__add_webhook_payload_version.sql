@@ -0,0 +1,8 @@
+-- Webhook payload versions
+-- Event payloads are versioned so they can evolve without breaking receivers. Each webhook is
+-- pinned to a version and receives the payload fields of that version. Existing webhooks are pinned
+-- to version 1, the payloads they receive today; new webhooks default to the latest version.
+
+ALTER TABLE webhooks ADD COLUMN payload_version INTEGER NOT NULL DEFAULT 1 CHECK (payload_version >= 1);
+
+COMMENT ON COLUMN webhooks.payload_version IS 'Event payload version delivered to the webhook, see GET /webhooks/event-types';
@@ -0,0 +1,55 @@
+-- Webhook delivery log retention
+-- Delivery rows hold payloads, headers and response bodies, which include PII. Rows are purged
+-- after the retention period of the account's tier, once their day has been rolled up into the
+-- daily stats below, and chosen payload fields are redacted earlier.
+
+ALTER TABLE webhook_deliveries ADD COLUMN redacted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_webhook_deliveries_unredacted ON webhook_deliveries(created_at) WHERE redacted_at IS NULL;
+
+COMMENT ON COLUMN webhook_deliveries.redacted_at IS 'When the configured payload fields were redacted, NULL until then';
+
+-- Per-webhook daily delivery stats, kept after the detailed rows are purged
+CREATE TABLE webhook_delivery_daily_stats (
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+
+    deliveries INTEGER NOT NULL DEFAULT 0,
+    succeeded INTEGER NOT NULL DEFAULT 0,
+    failed INTEGER NOT NULL DEFAULT 0,
+    pending INTEGER NOT NULL DEFAULT 0,
+
+    avg_duration_ms INTEGER,
+    max_duration_ms INTEGER,
+
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (webhook_id, day)
+);
+
+COMMENT ON TABLE webhook_delivery_daily_stats IS 'Deliveries per webhook and UTC day, by status at aggregation time';
+
+-- Retention period per tier, as a resource limit
+INSERT INTO features (name, description) VALUES
+    ('webhook_log_retention_days', 'Days webhook delivery logs are kept');
+
+INSERT INTO limits (feature_id, limit_name, limit_value)
+SELECT id, 'webhook_log_retention_days_free', 7 FROM features WHERE name = 'webhook_log_retention_days';
+
+INSERT INTO limits (feature_id, limit_name, limit_value)
+SELECT id, 'webhook_log_retention_days_pro', 30 FROM features WHERE name = 'webhook_log_retention_days';
+
+INSERT INTO limits (feature_id, limit_name, limit_value)
+SELECT id, 'webhook_log_retention_days_team', 90 FROM features WHERE name = 'webhook_log_retention_days';
+
+INSERT INTO plan_feature_limits (plan_id, feature_id, limit_id, enabled)
+SELECT p.id, f.id, l.id, true
+FROM prices p
+CROSS JOIN features f
+JOIN limits l ON l.feature_id = f.id
+WHERE f.name = 'webhook_log_retention_days'
+AND l.limit_name = CASE
+    WHEN p.description = 'free' THEN 'webhook_log_retention_days_free'
+    WHEN p.description IN ('lc_pro_monthly', 'lc_pro_annual') THEN 'webhook_log_retention_days_pro'
+    WHEN p.description IN ('lc_team_monthly', 'lc_team_annual') THEN 'webhook_log_retention_days_team'
+END;
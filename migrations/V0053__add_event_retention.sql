@@ -0,0 +1,30 @@
+-- Event log retention
+-- Events hold the same PII as the webhook deliveries generated from them. They are purged after
+-- the webhook log retention period of the account's tier and the configured payload fields are
+-- redacted with the deliveries, so listing or replaying old events does not expose them.
+
+ALTER TABLE events ADD COLUMN redacted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_events_unredacted ON events(occurred_at) WHERE redacted_at IS NULL;
+
+COMMENT ON COLUMN events.redacted_at IS 'When the configured data fields were redacted, NULL until then';
+
+-- Events stay append-only, except for redacting their data once
+CREATE OR REPLACE FUNCTION prevent_event_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.redacted_at IS NULL
+       AND NEW.redacted_at IS NOT NULL
+       AND NEW.id = OLD.id
+       AND NEW.sequence = OLD.sequence
+       AND NEW.account_id = OLD.account_id
+       AND NEW.campaign_id IS NOT DISTINCT FROM OLD.campaign_id
+       AND NEW.type = OLD.type
+       AND NEW.occurred_at = OLD.occurred_at
+       AND NEW.recorded_at = OLD.recorded_at THEN
+        RETURN NEW;
+    END IF;
+
+    RAISE EXCEPTION 'events are append-only';
+END;
+$$ LANGUAGE plpgsql;
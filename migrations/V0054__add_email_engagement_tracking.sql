@@ -0,0 +1,10 @@
+-- Email engagement tracking
+-- Resend reports opens, clicks and bounces by the ID it returned for the sent message. Email logs
+-- already store it; blast recipients now do too, so A/B test winners are picked from real opens
+-- and clicks.
+
+ALTER TABLE blast_recipients ADD COLUMN provider_message_id VARCHAR(255);
+
+CREATE INDEX idx_blast_recipients_provider ON blast_recipients(provider_message_id) WHERE provider_message_id IS NOT NULL;
+
+COMMENT ON COLUMN blast_recipients.provider_message_id IS 'Email provider message ID of the sent email, used to match engagement events';
@@ -0,0 +1,8 @@
+-- Ordered retries
+-- A delivery to a webhook with ordered delivery is only sent once every earlier event delivery of
+-- the webhook succeeded or was dropped, including deliveries waiting for a retry.
+
+CREATE INDEX idx_webhook_deliveries_unfinished ON webhook_deliveries(webhook_id, created_at)
+    WHERE triggered_by = 'event' AND status IN ('pending', 'failed');
+
+COMMENT ON COLUMN webhooks.ordered_delivery IS 'Send event deliveries one at a time in event order instead of concurrently; a delivery waits while an earlier one is queued or retried';
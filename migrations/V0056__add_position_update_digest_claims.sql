@@ -0,0 +1,9 @@
+-- Position update digest claims
+-- Claiming a digest no longer counts as sending it. A claim holds the user for a short lease, and
+-- last_sent_at only moves once the email went out, so a failed send is retried once its claim
+-- expires instead of waiting for the next digest window.
+
+ALTER TABLE waitlist_position_notifications ADD COLUMN claimed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN waitlist_position_notifications.claimed_at IS 'When the scheduler claimed the pending update for sending; NULL once sent. An expired claim is picked up again';
+COMMENT ON COLUMN waitlist_position_notifications.last_sent_at IS 'When the last position update email was sent';
@@ -0,0 +1,3 @@
+module github.com/DMSAVentures/base-server/pkg/webhookverify
+
+go 1.21
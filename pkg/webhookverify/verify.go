@@ -0,0 +1,189 @@
+// Package webhookverify verifies the signatures of webhook deliveries. It supports both signature
+// formats a webhook can be configured with:
+//
+//   - v1: X-Webhook-Signature: t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//   - standard_webhooks: the Standard Webhooks headers webhook-id, webhook-timestamp and
+//     webhook-signature: v1,<base64 HMAC-SHA256 of "<id>.<timestamp>.<body>">
+//
+// While a secret is being rotated a delivery carries one signature per valid secret, and it is
+// accepted when any of them matches. The package only depends on the standard library.
+package webhookverify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names
+const (
+	HeaderV1Signature       = "X-Webhook-Signature"
+	HeaderWebhookID         = "webhook-id"
+	HeaderWebhookTimestamp  = "webhook-timestamp"
+	HeaderWebhookSignature  = "webhook-signature"
+	standardSecretPrefix    = "whsec_"
+	standardSignatureScheme = "v1"
+)
+
+// DefaultTolerance is how far a delivery's timestamp may be from the current time
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	ErrMissingSignature  = errors.New("webhookverify: missing signature headers")
+	ErrInvalidSignature  = errors.New("webhookverify: malformed signature header")
+	ErrInvalidSecret     = errors.New("webhookverify: invalid secret")
+	ErrTimestampTooOld   = errors.New("webhookverify: timestamp outside of tolerance")
+	ErrSignatureMismatch = errors.New("webhookverify: no matching signature")
+)
+
+// Verifier verifies deliveries signed with one webhook secret
+type Verifier struct {
+	key       []byte
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// New creates a Verifier for a webhook secret, given either as returned for v1 webhooks or in
+// the "whsec_<base64>" form returned for Standard Webhooks webhooks. Both forms of the same
+// secret verify both signature formats.
+func New(secret string) (*Verifier, error) {
+	key := []byte(secret)
+	if strings.HasPrefix(secret, standardSecretPrefix) {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, standardSecretPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSecret, err)
+		}
+		key = decoded
+	}
+	if len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+
+	return &Verifier{
+		key:       key,
+		tolerance: DefaultTolerance,
+		now:       time.Now,
+	}, nil
+}
+
+// WithTolerance returns a copy of the Verifier accepting timestamps up to tolerance away from the
+// current time. A tolerance of 0 disables the timestamp check, which leaves replays undetected.
+func (v *Verifier) WithTolerance(tolerance time.Duration) *Verifier {
+	copied := *v
+	copied.tolerance = tolerance
+	return &copied
+}
+
+// Verify checks the signature of a delivery given its headers and raw body. The signature format
+// is detected from the headers.
+func (v *Verifier) Verify(header http.Header, body []byte) error {
+	if signature := header.Get(HeaderWebhookSignature); signature != "" {
+		return v.VerifyStandard(header.Get(HeaderWebhookID), header.Get(HeaderWebhookTimestamp), signature, body)
+	}
+	if signature := header.Get(HeaderV1Signature); signature != "" {
+		return v.VerifyV1(signature, body)
+	}
+	return ErrMissingSignature
+}
+
+// VerifyV1 checks an X-Webhook-Signature header value against the raw body
+func (v *Verifier) VerifyV1(signatureHeader string, body []byte) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(signatureHeader, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidSignature
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				return ErrInvalidSignature
+			}
+			signatures = append(signatures, signature)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if err := v.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	return v.match(timestamp+"."+string(body), signatures)
+}
+
+// VerifyStandard checks Standard Webhooks headers against the raw body
+func (v *Verifier) VerifyStandard(id, timestamp, signatureHeader string, body []byte) error {
+	if id == "" || timestamp == "" || signatureHeader == "" {
+		return ErrMissingSignature
+	}
+
+	var signatures [][]byte
+	for _, entry := range strings.Fields(signatureHeader) {
+		scheme, value, ok := strings.Cut(entry, ",")
+		if !ok {
+			return ErrInvalidSignature
+		}
+		// Signatures of other schemes are skipped so new schemes can be added alongside v1
+		if scheme != standardSignatureScheme {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		signatures = append(signatures, signature)
+	}
+	if len(signatures) == 0 {
+		return ErrSignatureMismatch
+	}
+
+	if err := v.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+
+	return v.match(id+"."+timestamp+"."+string(body), signatures)
+}
+
+// checkTimestamp rejects timestamps further than the tolerance from the current time
+func (v *Verifier) checkTimestamp(timestamp string) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if v.tolerance <= 0 {
+		return nil
+	}
+
+	skew := v.now().Sub(time.Unix(seconds, 0))
+	if skew > v.tolerance || skew < -v.tolerance {
+		return ErrTimestampTooOld
+	}
+	return nil
+}
+
+// match reports whether any of the signatures is the HMAC of the signed content
+func (v *Verifier) match(signedContent string, signatures [][]byte) error {
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write([]byte(signedContent))
+	expected := mac.Sum(nil)
+
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
@@ -0,0 +1,163 @@
+package webhookverify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const testSecret = "c2VjcmV0LWZvci10ZXN0aW5nLXdlYmhvb2stc2lnbmluZw=="
+
+var testBody = []byte(`{"id":"evt_1","type":"user.created","data":{}}`)
+
+func sign(secret, content string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(content))
+	return mac.Sum(nil)
+}
+
+func fixedVerifier(t *testing.T, secret string, now time.Time) *Verifier {
+	t.Helper()
+	v, err := New(secret)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	v.now = func() time.Time { return now }
+	return v
+}
+
+func TestVerifyV1(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp := now.Unix()
+	valid := hex.EncodeToString(sign(testSecret, fmt.Sprintf("%d.%s", timestamp, testBody)))
+	other := hex.EncodeToString(sign("another-secret", fmt.Sprintf("%d.%s", timestamp, testBody)))
+
+	tests := []struct {
+		name    string
+		header  string
+		body    []byte
+		wantErr error
+	}{
+		{name: "valid", header: fmt.Sprintf("t=%d,v1=%s", timestamp, valid), body: testBody},
+		{name: "valid during rotation", header: fmt.Sprintf("t=%d,v1=%s,v1=%s", timestamp, other, valid), body: testBody},
+		{name: "wrong secret", header: fmt.Sprintf("t=%d,v1=%s", timestamp, other), body: testBody, wantErr: ErrSignatureMismatch},
+		{name: "tampered body", header: fmt.Sprintf("t=%d,v1=%s", timestamp, valid), body: []byte(`{}`), wantErr: ErrSignatureMismatch},
+		{name: "expired timestamp", header: fmt.Sprintf("t=%d,v1=%s", timestamp-600, valid), body: testBody, wantErr: ErrTimestampTooOld},
+		{name: "missing timestamp", header: "v1=" + valid, body: testBody, wantErr: ErrInvalidSignature},
+		{name: "malformed", header: "garbage", body: testBody, wantErr: ErrInvalidSignature},
+	}
+
+	v := fixedVerifier(t, testSecret, now)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.VerifyV1(tt.header, tt.body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyStandard(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp := fmt.Sprint(now.Unix())
+	id := "evt_1"
+	content := id + "." + timestamp + "." + string(testBody)
+	valid := "v1," + base64.StdEncoding.EncodeToString(sign(testSecret, content))
+	other := "v1," + base64.StdEncoding.EncodeToString(sign("another-secret", content))
+
+	tests := []struct {
+		name      string
+		id        string
+		timestamp string
+		signature string
+		wantErr   error
+	}{
+		{name: "valid", id: id, timestamp: timestamp, signature: valid},
+		{name: "valid during rotation", id: id, timestamp: timestamp, signature: other + " " + valid},
+		{name: "unknown scheme skipped", id: id, timestamp: timestamp, signature: "v1a,abc " + valid},
+		{name: "wrong secret", id: id, timestamp: timestamp, signature: other, wantErr: ErrSignatureMismatch},
+		{name: "different id", id: "evt_2", timestamp: timestamp, signature: valid, wantErr: ErrSignatureMismatch},
+		{name: "expired timestamp", id: id, timestamp: fmt.Sprint(now.Unix() - 600), signature: valid, wantErr: ErrTimestampTooOld},
+		{name: "missing id", timestamp: timestamp, signature: valid, wantErr: ErrMissingSignature},
+	}
+
+	v := fixedVerifier(t, testSecret, now)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.VerifyStandard(tt.id, tt.timestamp, tt.signature, testBody)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVerifyStandard_SpecExample(t *testing.T) {
+	// Example from the Standard Webhooks specification
+	v := fixedVerifier(t, "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", time.Unix(1614265330, 0))
+
+	err := v.VerifyStandard(
+		"msg_p5jXN8AQM9LWM0D4loKWxJek",
+		"1614265330",
+		"v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE=",
+		[]byte(`{"test": 2432232314}`),
+	)
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestVerify_DetectsFormat(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp := fmt.Sprint(now.Unix())
+
+	// The whsec_ form of a secret verifies both formats
+	v := fixedVerifier(t, "whsec_"+base64.StdEncoding.EncodeToString([]byte(testSecret)), now)
+
+	v1Header := http.Header{}
+	v1Header.Set(HeaderV1Signature, "t="+timestamp+",v1="+hex.EncodeToString(sign(testSecret, timestamp+"."+string(testBody))))
+	if err := v.Verify(v1Header, testBody); err != nil {
+		t.Errorf("expected v1 delivery to verify, got %v", err)
+	}
+
+	standardHeader := http.Header{}
+	standardHeader.Set(HeaderWebhookID, "evt_1")
+	standardHeader.Set(HeaderWebhookTimestamp, timestamp)
+	standardHeader.Set(HeaderWebhookSignature, "v1,"+base64.StdEncoding.EncodeToString(sign(testSecret, "evt_1."+timestamp+"."+string(testBody))))
+	if err := v.Verify(standardHeader, testBody); err != nil {
+		t.Errorf("expected Standard Webhooks delivery to verify, got %v", err)
+	}
+
+	if err := v.Verify(http.Header{}, testBody); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("expected ErrMissingSignature, got %v", err)
+	}
+}
+
+func TestNew_InvalidSecret(t *testing.T) {
+	for _, secret := range []string{"", "whsec_", "whsec_not base64!"} {
+		if _, err := New(secret); !errors.Is(err, ErrInvalidSecret) {
+			t.Errorf("expected ErrInvalidSecret for %q, got %v", secret, err)
+		}
+	}
+}
+
+func TestWithTolerance(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp := now.Unix() - 3600
+	header := fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(sign(testSecret, fmt.Sprintf("%d.%s", timestamp, testBody))))
+
+	v := fixedVerifier(t, testSecret, now)
+	if err := v.VerifyV1(header, testBody); !errors.Is(err, ErrTimestampTooOld) {
+		t.Errorf("expected ErrTimestampTooOld, got %v", err)
+	}
+	if err := v.WithTolerance(2*time.Hour).VerifyV1(header, testBody); err != nil {
+		t.Errorf("expected no error with a larger tolerance, got %v", err)
+	}
+}